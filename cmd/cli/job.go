@@ -42,6 +42,16 @@ func buildJobCmd() *cobra.Command {
 	job.InitViewFlags(jobViewCmd)
 	jobCmd.AddCommand(jobViewCmd)
 
+	jobExplainCmd := &cobra.Command{
+		Use:   "explain",
+		Short: "show the last scheduling failures of a job",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.ExplainJob())
+		},
+	}
+	job.InitExplainFlags(jobExplainCmd)
+	jobCmd.AddCommand(jobExplainCmd)
+
 	jobSuspendCmd := &cobra.Command{
 		Use:   "suspend",
 		Short: "abort a job",
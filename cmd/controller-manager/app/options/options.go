@@ -33,8 +33,19 @@ const (
 	defaultSchedulerName       = "volcano"
 	defaultHealthzAddress      = ":11251"
 	defaultLockObjectNamespace = "volcano-system"
+	defaultMaxSpareNodes       = 5
 )
 
+// defaultInterruptionTaints are the taints set on nodes going to be interrupted by the node termination handlers
+// of AWS, GCP and Karpenter.
+var defaultInterruptionTaints = []string{
+	"aws-node-termination-handler/spot-itn",
+	"aws-node-termination-handler/scheduled-maintenance",
+	"cloud.google.com/impending-node-termination",
+	"karpenter.sh/disruption",
+	"karpenter.sh/disrupted",
+}
+
 // ServerOption is the main context object for the controllers.
 type ServerOption struct {
 	KubeClientOptions    kube.ClientOptions
@@ -63,6 +74,22 @@ type ServerOption struct {
 	// WorkerThreadsForPG is the number of threads syncing podgroup operations
 	// The larger the number, the faster the podgroup processing, but requires more CPU load.
 	WorkerThreadsForPG uint32
+	// SpareNodeSelector is the label selector of nodes kept as hot spares for pending gangs,
+	// MinSpareNodes and MaxSpareNodes bound the number of spare nodes.
+	SpareNodeSelector string
+	MinSpareNodes     int
+	MaxSpareNodes     int
+	// EnableNodeInterruptionHandling enables notifying and requeueing the jobs on nodes going to be interrupted
+	EnableNodeInterruptionHandling bool
+	// InterruptionTaints and InterruptionConditions are the keys of taints and the types of conditions set on
+	// nodes going to be interrupted, e.g. by node termination handlers of spot instances.
+	InterruptionTaints     []string
+	InterruptionConditions []string
+	// EnableQueueCapabilityTuning enables tuning the capability of queues within the bounds set by operator
+	// based on their utilization history
+	EnableQueueCapabilityTuning bool
+	// StartConditionAllowedHosts are the hosts which may be requested to check the URLs of the start conditions of jobs
+	StartConditionAllowedHosts []string
 }
 
 type DecryptFunc func(c *ServerOption) error
@@ -94,6 +121,20 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&s.EnableHealthz, "enable-healthz", false, "Enable the health check; it is false by default")
 	fs.BoolVar(&s.InheritOwnerAnnotations, "inherit-owner-annotations", true, "Enable inherit owner annotations for pods when create podgroup; it is enabled by default")
 	fs.Uint32Var(&s.WorkerThreadsForPG, "worker-threads-for-podgroup", 1, "The number of threads syncing podgroup operations. The larger the number, the faster the podgroup processing, but requires more CPU load.")
+	fs.StringVar(&s.SpareNodeSelector, "spare-node-selector", "", "The label selector of nodes kept cordoned but ready as hot spares for pending gangs; spare nodes are disabled if it is empty")
+	fs.IntVar(&s.MinSpareNodes, "min-spare-nodes", 0, "The min number of spare nodes")
+	fs.IntVar(&s.MaxSpareNodes, "max-spare-nodes", defaultMaxSpareNodes, "The max number of spare nodes")
+	fs.BoolVar(&s.EnableNodeInterruptionHandling, "enable-node-interruption-handling", false, "Enable notifying the pods of Jobs "+
+		"on nodes going to be interrupted, and requeueing them by the volcano.sh/interruption-policy annotation of Jobs; it is false by default")
+	fs.StringSliceVar(&s.InterruptionTaints, "interruption-taints", defaultInterruptionTaints, "The keys of taints set on nodes going to be interrupted; "+
+		"Pods of Jobs on such nodes are notified, or requeued by the volcano.sh/interruption-policy annotation of Jobs")
+	fs.StringSliceVar(&s.InterruptionConditions, "interruption-conditions", nil, "The types of conditions set on nodes going to be interrupted, "+
+		"e.g. by node-problem-detector; the interruption controller is also disabled if both interruption taints and conditions are empty")
+	fs.BoolVar(&s.EnableQueueCapabilityTuning, "enable-queue-capability-tuning", false, "Enable tuning the capability of queues "+
+		"within the bounds of their volcano.sh/min-capability and volcano.sh/max-capability annotations by utilization history; it is false by default")
+	fs.StringSliceVar(&s.StartConditionAllowedHosts, "start-condition-allowed-hosts", nil, "The hosts which may be requested to check "+
+		"the volcano.sh/wait-for-urls annotation of Jobs, \"*.domain\" allows all subdomains of domain; the URLs of other hosts are never met, "+
+		"and none is allowed by default")
 }
 
 // CheckOptionOrDie checks the LockObjectNamespace.
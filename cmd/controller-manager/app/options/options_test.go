@@ -35,6 +35,7 @@ func TestAddFlags(t *testing.T) {
 		"--kube-api-burst=200",
 		"--scheduler-name=volcano",
 		"--scheduler-name=volcano2",
+		"--start-condition-allowed-hosts=feature-store.default.svc,*.example.com",
 	}
 	fs.Parse(args)
 
@@ -46,15 +47,18 @@ func TestAddFlags(t *testing.T) {
 			QPS:        defaultQPS,
 			Burst:      200,
 		},
-		PrintVersion:            false,
-		WorkerThreads:           defaultWorkers,
-		SchedulerNames:          []string{"volcano", "volcano2"},
-		MaxRequeueNum:           defaultMaxRequeueNum,
-		HealthzBindAddress:      ":11251",
-		InheritOwnerAnnotations: true,
-		EnableLeaderElection:    true,
-		LockObjectNamespace:     defaultLockObjectNamespace,
-		WorkerThreadsForPG:      1,
+		PrintVersion:               false,
+		WorkerThreads:              defaultWorkers,
+		SchedulerNames:             []string{"volcano", "volcano2"},
+		MaxRequeueNum:              defaultMaxRequeueNum,
+		HealthzBindAddress:         ":11251",
+		InheritOwnerAnnotations:    true,
+		EnableLeaderElection:       true,
+		LockObjectNamespace:        defaultLockObjectNamespace,
+		WorkerThreadsForPG:         1,
+		MaxSpareNodes:              defaultMaxSpareNodes,
+		InterruptionTaints:         defaultInterruptionTaints,
+		StartConditionAllowedHosts: []string{"feature-store.default.svc", "*.example.com"},
 	}
 
 	if !reflect.DeepEqual(expected, s) {
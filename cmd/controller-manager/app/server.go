@@ -128,6 +128,14 @@ func startControllers(config *rest.Config, opt *options.ServerOption) func(ctx c
 	controllerOpt.SharedInformerFactory = informers.NewSharedInformerFactory(controllerOpt.KubeClient, 0)
 	controllerOpt.InheritOwnerAnnotations = opt.InheritOwnerAnnotations
 	controllerOpt.WorkerThreadsForPG = opt.WorkerThreadsForPG
+	controllerOpt.SpareNodeSelector = opt.SpareNodeSelector
+	controllerOpt.MinSpareNodes = opt.MinSpareNodes
+	controllerOpt.MaxSpareNodes = opt.MaxSpareNodes
+	controllerOpt.EnableNodeInterruptionHandling = opt.EnableNodeInterruptionHandling
+	controllerOpt.InterruptionTaints = opt.InterruptionTaints
+	controllerOpt.InterruptionConditions = opt.InterruptionConditions
+	controllerOpt.EnableQueueCapabilityTuning = opt.EnableQueueCapabilityTuning
+	controllerOpt.StartConditionAllowedHosts = opt.StartConditionAllowedHosts
 
 	return func(ctx context.Context) {
 		framework.ForeachController(func(c framework.Controller) {
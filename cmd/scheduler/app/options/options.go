@@ -31,12 +31,18 @@ const (
 	defaultSchedulerPeriod = time.Second
 	defaultQueue           = "default"
 	defaultListenAddress   = ":8080"
+	defaultDebugAddress    = ":8443"
 	defaultHealthzAddress  = ":11251"
 	defaultPluginsDir      = ""
 
 	defaultQPS   = 2000.0
 	defaultBurst = 2000
 
+	defaultPodGroupStatusQPS   = 0.0
+	defaultPodGroupStatusBurst = 200
+
+	defaultTracingSamplingRatePerMillion = 1000000
+
 	// Default parameters to control the number of feasible nodes to find and score
 	defaultMinPercentageOfNodesToFind = 5
 	defaultMinNodesToFind             = 100
@@ -63,7 +69,9 @@ type ServerOption struct {
 	EnablePriorityClass  bool
 	EnableCSIStorage     bool
 	// vc-scheduler will load (not activate) custom plugins which are in this directory
-	PluginsDir    string
+	PluginsDir string
+	// vc-scheduler will register remote plugins served on unix sockets, given by name=socket
+	RemotePlugins []string
 	EnableHealthz bool
 	// HealthzBindAddress is the IP address and port for the health check server to serve on
 	// defaulting to :11251
@@ -75,6 +83,35 @@ type ServerOption struct {
 
 	NodeSelector      []string
 	EnableCacheDumper bool
+	// EnableInvariantCheck verifies the accounting invariants of each session at session close, for debugging
+	EnableInvariantCheck bool
+	// EnableProfilingLabels tags the goroutines running actions and plugins with pprof labels
+	EnableProfilingLabels bool
+	// EnablePluginLatencyMetrics observes the latency of each call of the filter and score functions of plugins
+	EnablePluginLatencyMetrics bool
+	// SlowSessionProfileThreshold is the duration of session above which the CPU profile of session is kept
+	SlowSessionProfileThreshold time.Duration
+	// MaxCompletedTasksPerJob is the number of succeeded and of failed tasks of each job kept in the cache
+	MaxCompletedTasksPerJob int
+	// EnableConfigDryRun compares the reloaded scheduler configuration with the active one in dry-run sessions
+	EnableConfigDryRun bool
+	// EnableIncrementalSnapshot reuses the clones of nodes not changed since the previous session in the snapshot
+	EnableIncrementalSnapshot bool
+	// EnableDebugQueueAuthorization serves the debug endpoints to the users the apiserver proxies, scoped to their queues
+	EnableDebugQueueAuthorization bool
+	// DebugListenAddress is the https address the debug endpoints are served at to the apiserver
+	DebugListenAddress string
+	// RequestHeaderClientCAFile and RequestHeaderAllowedNames verify the client certificate of the apiserver
+	// proxying the users to the debug endpoints
+	RequestHeaderClientCAFile string
+	RequestHeaderAllowedNames []string
+	// PodGroupStatusQPS and PodGroupStatusBurst limit the rate the status of podgroups is applied in the background
+	PodGroupStatusQPS   float32
+	PodGroupStatusBurst int
+	// TracingEndpoint is the collector the spans of sessions are exported to, tracing is disabled if empty
+	TracingEndpoint string
+	// TracingSamplingRatePerMillion is the number of sessions traced per million
+	TracingSamplingRatePerMillion int32
 }
 
 type DecryptFunc func(c *ServerOption) error
@@ -122,12 +159,53 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.Int32Var(&s.PercentageOfNodesToFind, "percentage-nodes-to-find", defaultPercentageOfNodesToFind, "The percentage of nodes to find and score, if <=0 will be calcuated based on the cluster size")
 
 	fs.StringVar(&s.PluginsDir, "plugins-dir", defaultPluginsDir, "vc-scheduler will load custom plugins which are in this directory")
+	fs.StringSliceVar(&s.RemotePlugins, "remote-plugins", nil, "vc-scheduler will register remote plugins served by gRPC on unix sockets, like: --remote-plugins=magic=/var/run/volcano/magic.sock")
 	fs.BoolVar(&s.EnableCSIStorage, "csi-storage", false,
 		"Enable tracking of available storage capacity that CSI drivers provide; it is false by default")
 	fs.BoolVar(&s.EnableHealthz, "enable-healthz", false, "Enable the health check; it is false by default")
 	fs.BoolVar(&s.EnableMetrics, "enable-metrics", false, "Enable the metrics function; it is false by default")
 	fs.StringSliceVar(&s.NodeSelector, "node-selector", nil, "volcano only work with the labeled node, like: --node-selector=volcano.sh/role:train --node-selector=volcano.sh/role:serving")
 	fs.BoolVar(&s.EnableCacheDumper, "cache-dumper", true, "Enable the cache dumper, it's true by default")
+	fs.BoolVar(&s.EnableInvariantCheck, "invariant-check", false, "Enable verifying invariants of scheduling accounting at "+
+		"session close, violations are logged and counted in metrics; it is false by default")
+	fs.BoolVar(&s.EnableProfilingLabels, "profiling-labels", false, "Tag the goroutines running actions and plugins with "+
+		"pprof labels, so that CPU profiles attribute time to actions and plugins; it is false by default")
+	fs.BoolVar(&s.EnablePluginLatencyMetrics, "plugin-latency-metrics", false, "Observe the latency of each call of the "+
+		"filter and score functions of plugins, e.g. predicate and nodeOrder, in metrics "+
+		"plugin_extension_point_latency_microseconds; it is false by default")
+	fs.DurationVar(&s.SlowSessionProfileThreshold, "slow-session-profile-threshold", 0, "Profile the CPU of each "+
+		"session and keep the profiles of the sessions taking longer than the threshold, served at "+
+		"the debug endpoints with --debug-queue-authorization; 0 disables it, which is the default")
+	fs.IntVar(&s.MaxCompletedTasksPerJob, "max-completed-tasks-per-job", 0, "The number of succeeded and of failed "+
+		"tasks of each job kept in the scheduler cache, the others are only counted to cap memory; 0 keeps all of them, "+
+		"which is the default")
+	fs.BoolVar(&s.EnableConfigDryRun, "config-dry-run", false, "Run the reloaded scheduler configuration and the "+
+		"active one in dry-run sessions in background, and log and export the decisions differing between them; "+
+		"it is false by default")
+	fs.BoolVar(&s.EnableIncrementalSnapshot, "incremental-snapshot", false, "Reuse the nodes of the snapshot of the "+
+		"previous session changed neither in the scheduler cache nor by the session, instead of cloning all nodes for "+
+		"each session; only nodes are reused, jobs and queues are still cloned for each session; it is false by default")
+	fs.BoolVar(&s.EnableDebugQueueAuthorization, "debug-queue-authorization", false, "Serve the session profiles and "+
+		"podgroup diagnostics, which are not served otherwise, at --debug-listen-address to the users proxied by the "+
+		"apiserver, the podgroup diagnostics of a queue only to the users allowed to get the queue, and the session "+
+		"profiles only to the users allowed to list queues; it is false by default")
+	fs.StringVar(&s.DebugListenAddress, "debug-listen-address", defaultDebugAddress, "The address to serve the debug "+
+		"endpoints over https at, with --tls-cert-file and --tls-private-key-file, with --debug-queue-authorization")
+	fs.StringVar(&s.RequestHeaderClientCAFile, "requestheader-client-ca-file", "", "The CA the client certificate of "+
+		"the apiserver proxying the users to the debug endpoints is verified by, the --requestheader-client-ca-file "+
+		"of the apiserver; it is required with --debug-queue-authorization")
+	fs.StringSliceVar(&s.RequestHeaderAllowedNames, "requestheader-allowed-names", nil, "The common names of the "+
+		"client certificates of the apiserver proxying the users to the debug endpoints; any name is allowed if empty")
+	fs.Float32Var(&s.PodGroupStatusQPS, "podgroup-status-qps", defaultPodGroupStatusQPS, "The QPS of applying the "+
+		"status of podgroups by server-side apply in the background, the writes of a podgroup are coalesced; "+
+		"0, the default, updates the podgroups one by one in the session")
+	fs.IntVar(&s.PodGroupStatusBurst, "podgroup-status-burst", defaultPodGroupStatusBurst, "The burst of applying "+
+		"the status of podgroups in the background")
+	fs.StringVar(&s.TracingEndpoint, "tracing-endpoint", "", "The URL of the collector the spans of sessions "+
+		"are exported to in the Jaeger protocol over HTTP, e.g. http://otel-collector:14268/api/traces of the jaeger "+
+		"receiver of an OpenTelemetry collector; sessions are not traced if empty, which is the default")
+	fs.Int32Var(&s.TracingSamplingRatePerMillion, "tracing-sampling-rate-per-million", defaultTracingSamplingRatePerMillion,
+		"The number of sessions traced per million with --tracing-endpoint; all sessions are traced by default")
 }
 
 // CheckOptionOrDie check lock-object-namespace when LeaderElection is enabled.
@@ -135,6 +213,13 @@ func (s *ServerOption) CheckOptionOrDie() error {
 	if s.EnableLeaderElection && s.LockObjectNamespace == "" {
 		return fmt.Errorf("lock-object-namespace must not be nil when LeaderElection is enabled")
 	}
+	if s.EnableDebugQueueAuthorization && (s.CertFile == "" || s.KeyFile == "" || s.RequestHeaderClientCAFile == "") {
+		return fmt.Errorf("tls-cert-file, tls-private-key-file and requestheader-client-ca-file must be set when " +
+			"debug-queue-authorization is enabled")
+	}
+	if s.TracingSamplingRatePerMillion < 0 || s.TracingSamplingRatePerMillion > 1000000 {
+		return fmt.Errorf("tracing-sampling-rate-per-million must be between 0 and 1000000")
+	}
 
 	return nil
 }
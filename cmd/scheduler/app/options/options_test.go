@@ -40,23 +40,27 @@ func TestAddFlags(t *testing.T) {
 
 	// This is a snapshot of expected options parsed by args.
 	expected := &ServerOption{
-		SchedulerNames: []string{defaultSchedulerName},
-		SchedulePeriod: 5 * time.Minute,
-		DefaultQueue:   defaultQueue,
-		ListenAddress:  defaultListenAddress,
+		SchedulerNames:     []string{defaultSchedulerName},
+		SchedulePeriod:     5 * time.Minute,
+		DefaultQueue:       defaultQueue,
+		ListenAddress:      defaultListenAddress,
+		DebugListenAddress: defaultDebugAddress,
 		KubeClientOptions: kube.ClientOptions{
 			Master:     "",
 			KubeConfig: "",
 			QPS:        defaultQPS,
 			Burst:      defaultBurst,
 		},
-		PluginsDir:                 defaultPluginsDir,
-		HealthzBindAddress:         ":11251",
-		MinNodesToFind:             defaultMinNodesToFind,
-		MinPercentageOfNodesToFind: defaultMinPercentageOfNodesToFind,
-		PercentageOfNodesToFind:    defaultPercentageOfNodesToFind,
-		EnableLeaderElection:       true,
-		LockObjectNamespace:        defaultLockObjectNamespace,
+		PluginsDir:                    defaultPluginsDir,
+		HealthzBindAddress:            ":11251",
+		MinNodesToFind:                defaultMinNodesToFind,
+		MinPercentageOfNodesToFind:    defaultMinPercentageOfNodesToFind,
+		PercentageOfNodesToFind:       defaultPercentageOfNodesToFind,
+		EnableLeaderElection:          true,
+		LockObjectNamespace:           defaultLockObjectNamespace,
+		PodGroupStatusQPS:             defaultPodGroupStatusQPS,
+		PodGroupStatusBurst:           defaultPodGroupStatusBurst,
+		TracingSamplingRatePerMillion: defaultTracingSamplingRatePerMillion,
 	}
 
 	if !reflect.DeepEqual(expected, s) {
@@ -18,18 +18,24 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/trace/jaeger"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
 
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	"volcano.sh/volcano/pkg/kube"
 	"volcano.sh/volcano/pkg/scheduler"
 	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/remote"
 	"volcano.sh/volcano/pkg/signals"
 	commonutil "volcano.sh/volcano/pkg/util"
 	"volcano.sh/volcano/pkg/version"
@@ -74,6 +80,20 @@ func Run(opt *options.ServerOption) error {
 		}
 	}
 
+	if err := remote.RegisterPlugins(opt.RemotePlugins); err != nil {
+		klog.Errorf("Fail to register remote plugins: %v", err)
+		return err
+	}
+
+	if opt.TracingEndpoint != "" {
+		shutdown, err := registerTracer(opt)
+		if err != nil {
+			klog.Errorf("Fail to register tracer: %v", err)
+			return err
+		}
+		defer shutdown()
+	}
+
 	sched, err := scheduler.NewScheduler(config,
 		opt.SchedulerNames,
 		opt.SchedulerConf,
@@ -87,7 +107,21 @@ func Run(opt *options.ServerOption) error {
 	if opt.EnableMetrics {
 		go func() {
 			http.Handle("/metrics", promhttp.Handler())
-			klog.Fatalf("Prometheus Http Server failed %s", http.ListenAndServe(opt.ListenAddress, nil))
+			klog.Fatalf("Prometheus Http Server failed %s", http.ListenAndServe(opt.ListenAddress, scheduler.BoundCPUProfiles(http.DefaultServeMux)))
+		}()
+	}
+
+	// The session profiles and podgroup diagnostics name the jobs and nodes of
+	// all queues, they are only served to the users proxied by the apiserver.
+	if opt.EnableDebugQueueAuthorization {
+		go func() {
+			server := &http.Server{
+				Addr:    opt.DebugListenAddress,
+				Handler: sched.DebugHandler(),
+				// The client certificate of the apiserver is verified with the users it proxies.
+				TLSConfig: &tls.Config{ClientAuth: tls.RequestClientCert},
+			}
+			klog.Fatalf("Debug Https Server failed %s", server.ListenAndServeTLS(opt.CertFile, opt.KeyFile))
 		}()
 	}
 
@@ -152,3 +186,28 @@ func Run(opt *options.ServerOption) error {
 	})
 	return fmt.Errorf("lost lease")
 }
+
+// registerTracer registers the tracer exporting the spans of sessions to the collector at --tracing-endpoint,
+// and returns the function flushing the spans not exported yet. The spans are exported over HTTP in the Jaeger
+// protocol, which OpenTelemetry collectors also receive, as the OTLP exporter needs a newer grpc than vc-scheduler.
+func registerTracer(opt *options.ServerOption) (func(), error) {
+	exporter, err := jaeger.NewRawExporter(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(opt.TracingEndpoint)))
+	if err != nil {
+		return nil, err
+	}
+	sampler := sdktrace.TraceIDRatioBased(float64(opt.TracingSamplingRatePerMillion) / 1000000)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(commonutil.GenerateComponentName(opt.SchedulerNames)))),
+	)
+	framework.RegisterTracer(framework.NewOpenTelemetryTracer(provider))
+
+	return func() {
+		framework.RegisterTracer(nil)
+		if err := provider.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Failed to flush the spans of sessions: %v", err)
+		}
+	}, nil
+}
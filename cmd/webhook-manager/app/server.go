@@ -25,12 +25,14 @@ import (
 	"syscall"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/apis/pkg/apis/scheduling/scheme"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/cmd/webhook-manager/app/options"
 	"volcano.sh/volcano/pkg/kube"
 	commonutil "volcano.sh/volcano/pkg/util"
@@ -74,6 +76,10 @@ func Run(config *options.Config) error {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
 	recorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: commonutil.GenerateComponentName(config.SchedulerNames)})
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	priorityClassLister := informerFactory.Scheduling().V1().PriorityClasses().Lister()
+	vcInformerFactory := vcinformer.NewSharedInformerFactory(vClient, 0)
+	podGroupLister := vcInformerFactory.Scheduling().V1beta1().PodGroups().Lister()
 	if err := router.ForEachAdmission(config, func(service *router.AdmissionService) error {
 		if service.Config != nil {
 			service.Config.VolcanoClient = vClient
@@ -81,6 +87,8 @@ func Run(config *options.Config) error {
 			service.Config.SchedulerNames = config.SchedulerNames
 			service.Config.Recorder = recorder
 			service.Config.ConfigData = admissionConf
+			service.Config.PriorityClassLister = priorityClassLister
+			service.Config.PodGroupLister = podGroupLister
 		}
 
 		klog.V(3).Infof("Registered '%s' as webhook.", service.Path)
@@ -97,6 +105,21 @@ func Run(config *options.Config) error {
 
 	klog.V(3).Infof("Successfully added caCert for all webhooks")
 
+	informerStopCh := make(chan struct{})
+	defer close(informerStopCh)
+	informerFactory.Start(informerStopCh)
+	for informerType, synced := range informerFactory.WaitForCacheSync(informerStopCh) {
+		if !synced {
+			return fmt.Errorf("failed to sync informer of %v", informerType)
+		}
+	}
+	vcInformerFactory.Start(informerStopCh)
+	for informerType, synced := range vcInformerFactory.WaitForCacheSync(informerStopCh) {
+		if !synced {
+			return fmt.Errorf("failed to sync informer of %v", informerType)
+		}
+	}
+
 	webhookServeError := make(chan struct{})
 	stopChannel := make(chan os.Signal, 1)
 	signal.Notify(stopChannel, syscall.SIGTERM, syscall.SIGINT)
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+type explainFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+}
+
+// podGroupDiagnosis is the last scheduling failure of a podgroup served by the scheduler.
+type podGroupDiagnosis struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Time      time.Time       `json:"time"`
+	Message   string          `json:"message,omitempty"`
+	Plugins   []pluginFailure `json:"plugins"`
+}
+
+// pluginFailure is the last predicate failure of a scheduler plugin for the tasks of a podgroup.
+type pluginFailure struct {
+	Plugin string `json:"plugin"`
+	Nodes  int    `json:"nodes"`
+	Task   string `json:"task"`
+	Node   string `json:"node"`
+	Reason string `json:"reason"`
+}
+
+// podGroupDiagnosticsPath is the path the apiserver proxies the diagnostics of the scheduler at.
+const podGroupDiagnosticsPath = "/apis/debug.scheduling.volcano.sh/v1alpha1/podgroupdiagnostics"
+
+var explainJobFlags = &explainFlags{}
+
+// InitExplainFlags init the explain command flags.
+func InitExplainFlags(cmd *cobra.Command) {
+	initFlags(cmd, &explainJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&explainJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&explainJobFlags.JobName, "name", "N", "", "the name of job")
+}
+
+// ExplainJob shows the last scheduling failures of the job kept by the scheduler, queried through the
+// apiserver, which authenticates the user and proxies the request to the scheduler by an APIService.
+func ExplainJob() error {
+	config, err := util.BuildConfig(explainJobFlags.Master, explainJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if explainJobFlags.JobName == "" {
+		err := fmt.Errorf("job name (specified by --name or -N) is mandatory to explain a particular job")
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err := jobClient.BatchV1alpha1().Jobs(explainJobFlags.Namespace).Get(context.TODO(), explainJobFlags.JobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	podGroup := job.Name + "-" + string(job.UID)
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	data, err := kubeClient.CoreV1().RESTClient().Get().
+		AbsPath(podGroupDiagnosticsPath).
+		Param("namespace", job.Namespace).
+		Param("name", podGroup).
+		DoRaw(context.TODO())
+	if err != nil {
+		return fmt.Errorf("failed to get the diagnostics of podgroup %s/%s from scheduler, which serves them with "+
+			"--debug-queue-authorization: %v", job.Namespace, podGroup, err)
+	}
+	var diagnoses []podGroupDiagnosis
+	if err := json.Unmarshal(data, &diagnoses); err != nil {
+		return fmt.Errorf("failed to decode the diagnostics of podgroup %s/%s: %v", job.Namespace, podGroup, err)
+	}
+	printDiagnoses(job.Namespace, job.Name, diagnoses, os.Stdout)
+	return nil
+}
+
+// printDiagnoses prints the last scheduling failures of the podgroups of the job into writer.
+func printDiagnoses(namespace, name string, diagnoses []podGroupDiagnosis, writer io.Writer) {
+	if len(diagnoses) == 0 {
+		WriteLine(writer, Level0, "No scheduling failure of job %s/%s is kept by the scheduler\n", namespace, name)
+		return
+	}
+	for _, diagnosis := range diagnoses {
+		WriteLine(writer, Level0, "PodGroup:\t%s/%s\n", diagnosis.Namespace, diagnosis.Name)
+		WriteLine(writer, Level0, "Time:    \t%s\n", diagnosis.Time.Format(time.RFC3339))
+		if len(diagnosis.Message) != 0 {
+			WriteLine(writer, Level0, "Message: \t%s\n", diagnosis.Message)
+		}
+		WriteLine(writer, Level0, "Plugins:\n")
+		for _, failure := range diagnosis.Plugins {
+			WriteLine(writer, Level1, "%s:\n", failure.Plugin)
+			WriteLine(writer, Level2, "Failed Nodes:\t%d\n", failure.Nodes)
+			WriteLine(writer, Level2, "Last Task:   \t%s\n", failure.Task)
+			WriteLine(writer, Level2, "Last Node:   \t%s\n", failure.Node)
+			WriteLine(writer, Level2, "Last Reason: \t%s\n", failure.Reason)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestExplainJob(t *testing.T) {
+	job := v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test", UID: "uid"}}
+	diagnoses := []podGroupDiagnosis{{
+		Namespace: "test",
+		Name:      "job1-uid",
+		Plugins:   []pluginFailure{{Plugin: "predicates", Nodes: 3, Task: "test/job1-worker-0", Node: "n1", Reason: "node(s) had untolerated taint"}},
+	}}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == podGroupDiagnosticsPath {
+			if r.URL.Query().Get("namespace") != "test" || r.URL.Query().Get("name") != "job1-uid" {
+				t.Errorf("unexpected query of diagnostics %s", r.URL.RawQuery)
+			}
+			val, _ := json.Marshal(diagnoses)
+			w.Write(val)
+			return
+		}
+		val, _ := json.Marshal(job)
+		w.Write(val)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	explainJobFlags.Master = server.URL
+	explainJobFlags.Namespace = "test"
+	explainJobFlags.JobName = "job1"
+	if err := ExplainJob(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	printDiagnoses("test", "job1", diagnoses, &buf)
+	if !strings.Contains(buf.String(), "node(s) had untolerated taint") {
+		t.Errorf("expected the reason of plugin failure printed, got %q", buf.String())
+	}
+	buf.Reset()
+	printDiagnoses("test", "job1", nil, &buf)
+	if !strings.Contains(buf.String(), "No scheduling failure") {
+		t.Errorf("expected no failure printed, got %q", buf.String())
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 type createFlags struct {
 	commonFlags
+	specFlags
 
 	Name   string
 	Weight int32
@@ -46,6 +47,7 @@ func InitCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Int32VarP(&createQueueFlags.Weight, "weight", "w", 1, "the weight of the queue")
 
 	cmd.Flags().StringVarP(&createQueueFlags.State, "state", "S", "Open", "the state of queue")
+	initSpecFlags(cmd, &createQueueFlags.specFlags)
 }
 
 // CreateQueue create queue.
@@ -68,6 +70,19 @@ func CreateQueue() error {
 	}
 
 	queueClient := versioned.NewForConfigOrDie(config)
+	var queues []schedulingv1beta1.Queue
+	if createQueueFlags.Parent != "" {
+		if queues, err = listQueues(queueClient); err != nil {
+			return err
+		}
+	}
+	if err := createQueueFlags.apply(queue, queues); err != nil {
+		return err
+	}
+	if err := validateQueue(queue, queues); err != nil {
+		return err
+	}
+
 	if _, err := queueClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{}); err != nil {
 		return err
 	}
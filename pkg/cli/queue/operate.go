@@ -23,9 +23,8 @@ import (
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"k8s.io/apimachinery/pkg/types"
-
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 )
 
@@ -40,6 +39,8 @@ const (
 
 type operateFlags struct {
 	commonFlags
+	// specFlags are the hierarchy and resources of queue updated by ActionUpdate
+	specFlags
 
 	// Name is name of queue
 	Name string
@@ -59,6 +60,7 @@ func InitOperateFlags(cmd *cobra.Command) {
 	cmd.Flags().Int32VarP(&operateQueueFlags.Weight, "weight", "w", 0, "the weight of the queue")
 	cmd.Flags().StringVarP(&operateQueueFlags.Action, "action", "a", "",
 		"operate action to queue, valid actions are open, close, update")
+	initSpecFlags(cmd, &operateQueueFlags.specFlags)
 }
 
 // OperateQueue operates queue
@@ -80,17 +82,12 @@ func OperateQueue() error {
 	case ActionClose:
 		action = v1alpha1.CloseQueueAction
 	case ActionUpdate:
-		if operateQueueFlags.Weight == 0 {
+		if operateQueueFlags.Weight == 0 && !operateQueueFlags.specified() {
 			return fmt.Errorf("when %s queue %s, weight must be specified, "+
 				"the value must be greater than 0", ActionUpdate, operateQueueFlags.Name)
 		}
 
-		queueClient := versioned.NewForConfigOrDie(config)
-		patchBytes := []byte(fmt.Sprintf(`{"spec":{"weight":%d}}`, operateQueueFlags.Weight))
-		_, err := queueClient.SchedulingV1beta1().Queues().Patch(context.TODO(),
-			operateQueueFlags.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
-
-		return err
+		return updateQueue(versioned.NewForConfigOrDie(config))
 	case "":
 		return fmt.Errorf("action can not be null")
 	default:
@@ -100,3 +97,30 @@ func OperateQueue() error {
 
 	return createQueueCommand(config, action)
 }
+
+// updateQueue updates the weight, hierarchy and resources of queue given by the flags.
+func updateQueue(queueClient versioned.Interface) error {
+	queue, err := queueClient.SchedulingV1beta1().Queues().Get(context.TODO(), operateQueueFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if operateQueueFlags.Weight != 0 {
+		queue.Spec.Weight = operateQueueFlags.Weight
+	}
+	var queues []v1beta1.Queue
+	if operateQueueFlags.Parent != "" || queue.Annotations[v1beta1.KubeHierarchyAnnotationKey] != "" {
+		if queues, err = listQueues(queueClient); err != nil {
+			return err
+		}
+	}
+	if err := operateQueueFlags.apply(queue, queues); err != nil {
+		return err
+	}
+	if err := validateQueue(queue, queues); err != nil {
+		return err
+	}
+
+	_, err = queueClient.SchedulingV1beta1().Queues().Update(context.TODO(), queue, metav1.UpdateOptions{})
+	return err
+}
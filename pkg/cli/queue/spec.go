@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+// hierarchyRoot is the root of the hierarchy of queues
+const hierarchyRoot = "root"
+
+// specFlags are the flags of the hierarchy and resources of a queue, shared by creating and updating.
+type specFlags struct {
+	// Parent is the hierarchy path of the parent of the queue, e.g. root/sci
+	Parent string
+	// ParentWeights are the slash separated weights of the nodes along Parent, e.g. 1/2
+	ParentWeights string
+	// Guarantee and Capability are the resources of the queue, e.g. cpu=4,memory=8Gi
+	Guarantee  string
+	Capability string
+}
+
+func initSpecFlags(cmd *cobra.Command, sf *specFlags) {
+	cmd.Flags().StringVarP(&sf.Parent, "parent", "", "",
+		"the hierarchy path of the parent of the queue, e.g. root/sci, the queue is not hierarchical if it is empty")
+	cmd.Flags().StringVarP(&sf.ParentWeights, "parent-weights", "", "",
+		"the slash separated weights of the nodes along the parent, e.g. 1/2, "+
+			"the ones of the queues with the same parent are used if it is empty")
+	cmd.Flags().StringVarP(&sf.Guarantee, "guarantee", "", "",
+		"the resources guaranteed to the queue, e.g. cpu=4,memory=8Gi")
+	cmd.Flags().StringVarP(&sf.Capability, "capability", "", "",
+		"the upper limit of resources of the queue, e.g. cpu=8,memory=16Gi")
+}
+
+// specified returns whether any of the flags is set.
+func (sf *specFlags) specified() bool {
+	return sf.Parent != "" || sf.ParentWeights != "" || sf.Guarantee != "" || sf.Capability != ""
+}
+
+// apply sets the hierarchy and resources of the flags to queue. The weights of the parent are taken
+// from the queues with the same parent in queues if they are not given.
+func (sf *specFlags) apply(queue *v1beta1.Queue, queues []v1beta1.Queue) error {
+	guarantee, err := util.PopulateResourceListV1(sf.Guarantee)
+	if err != nil {
+		return fmt.Errorf("invalid guarantee %q: %v", sf.Guarantee, err)
+	}
+	if guarantee != nil {
+		queue.Spec.Guarantee.Resource = guarantee
+	}
+
+	capability, err := util.PopulateResourceListV1(sf.Capability)
+	if err != nil {
+		return fmt.Errorf("invalid capability %q: %v", sf.Capability, err)
+	}
+	if capability != nil {
+		queue.Spec.Capability = capability
+	}
+
+	if sf.Parent == "" {
+		if sf.ParentWeights != "" {
+			return fmt.Errorf("parent weights can not be specified without parent")
+		}
+		return nil
+	}
+
+	parent := strings.Trim(sf.Parent, "/")
+	if parent != hierarchyRoot && !strings.HasPrefix(parent, hierarchyRoot+"/") {
+		parent = hierarchyRoot + "/" + parent
+	}
+	parentWeights := sf.ParentWeights
+	if parentWeights == "" {
+		parentWeights = siblingWeights(parent, queue.Name, queues)
+	}
+
+	if queue.Annotations == nil {
+		queue.Annotations = map[string]string{}
+	}
+	queue.Annotations[v1beta1.KubeHierarchyAnnotationKey] = parent + "/" + queue.Name
+	queue.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey] = parentWeights + "/" + strconv.Itoa(int(queue.Spec.Weight))
+	return nil
+}
+
+// siblingWeights returns the weights of the nodes along parent of another queue with the same parent,
+// or 1 for each node if there is no such queue.
+func siblingWeights(parent, name string, queues []v1beta1.Queue) string {
+	paths := strings.Split(parent, "/")
+	for _, q := range queues {
+		if q.Name == name || !strings.HasPrefix(q.Annotations[v1beta1.KubeHierarchyAnnotationKey], parent+"/") {
+			continue
+		}
+		weights := strings.Split(q.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey], "/")
+		if len(weights) > len(paths) {
+			return strings.Join(weights[:len(paths)], "/")
+		}
+	}
+
+	weights := make([]string, len(paths))
+	for i := range weights {
+		weights[i] = "1"
+	}
+	return strings.Join(weights, "/")
+}
+
+// validateQueue validates queue the same as the admission webhook of queues, so that mistakes
+// are reported before the queue is sent. queues are the queues in the cluster.
+func validateQueue(queue *v1beta1.Queue, queues []v1beta1.Queue) error {
+	switch queue.Status.State {
+	case "", v1beta1.QueueStateOpen, v1beta1.QueueStateClosed:
+	default:
+		return fmt.Errorf("queue state must be in %v",
+			[]v1beta1.QueueState{v1beta1.QueueStateOpen, v1beta1.QueueStateClosed})
+	}
+
+	if queue.Spec.Weight <= 0 {
+		return fmt.Errorf("queue weight must be a positive integer")
+	}
+
+	hierarchy := queue.Annotations[v1beta1.KubeHierarchyAnnotationKey]
+	hierarchicalWeights := queue.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey]
+	if hierarchy == "" && hierarchicalWeights == "" {
+		return nil
+	}
+
+	paths := strings.Split(hierarchy, "/")
+	weights := strings.Split(hierarchicalWeights, "/")
+	if len(paths) != len(weights) {
+		return fmt.Errorf("hierarchy %s must have the same length with weights %s", hierarchy, hierarchicalWeights)
+	}
+	for _, weight := range weights {
+		weightFloat, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return fmt.Errorf("%s in the weights %s is invalid number: %v", weight, hierarchicalWeights, err)
+		}
+		if weightFloat <= 0 {
+			return fmt.Errorf("%s in the weights %s must be larger than 0", weight, hierarchicalWeights)
+		}
+	}
+
+	// The node is not allowed to be in the sub path of a node.
+	// For example, a queue with "root/sci" conflicts with a queue with "root/sci/dev"
+	for _, q := range queues {
+		hierarchyInTree := q.Annotations[v1beta1.KubeHierarchyAnnotationKey]
+		if hierarchyInTree != "" && q.Name != queue.Name && strings.HasPrefix(hierarchyInTree, hierarchy) {
+			return fmt.Errorf("%s is not allowed to be in the sub path of %s of queue %s",
+				hierarchy, hierarchyInTree, q.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func hierarchicalQueue(name, hierarchy, weights string) v1beta1.Queue {
+	return v1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				v1beta1.KubeHierarchyAnnotationKey:       hierarchy,
+				v1beta1.KubeHierarchyWeightAnnotationKey: weights,
+			},
+		},
+		Spec: v1beta1.QueueSpec{Weight: 1},
+	}
+}
+
+func TestApplySpecFlags(t *testing.T) {
+	queues := []v1beta1.Queue{hierarchicalQueue("prod", "root/eng/prod", "1/4/2")}
+
+	testCases := []struct {
+		name              string
+		flags             specFlags
+		expectedHierarchy string
+		expectedWeights   string
+		expectedErr       bool
+	}{
+		{
+			name:              "weights of parent taken from sibling",
+			flags:             specFlags{Parent: "root/eng"},
+			expectedHierarchy: "root/eng/dev",
+			expectedWeights:   "1/4/3",
+		},
+		{
+			name:              "root added to parent",
+			flags:             specFlags{Parent: "eng", ParentWeights: "1/8"},
+			expectedHierarchy: "root/eng/dev",
+			expectedWeights:   "1/8/3",
+		},
+		{
+			name:              "weights of new parent default to 1",
+			flags:             specFlags{Parent: "root/sci"},
+			expectedHierarchy: "root/sci/dev",
+			expectedWeights:   "1/1/3",
+		},
+		{
+			name:        "parent weights without parent",
+			flags:       specFlags{ParentWeights: "1/2"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid guarantee",
+			flags:       specFlags{Guarantee: "cpu"},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		queue := &v1beta1.Queue{ObjectMeta: metav1.ObjectMeta{Name: "dev"}, Spec: v1beta1.QueueSpec{Weight: 3}}
+		err := testCase.flags.apply(queue, queues)
+		if (err != nil) != testCase.expectedErr {
+			t.Errorf("Case '%s' failed, expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if hierarchy := queue.Annotations[v1beta1.KubeHierarchyAnnotationKey]; hierarchy != testCase.expectedHierarchy {
+			t.Errorf("Case '%s' failed, expected hierarchy %s, got %s", testCase.name, testCase.expectedHierarchy, hierarchy)
+		}
+		if weights := queue.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey]; weights != testCase.expectedWeights {
+			t.Errorf("Case '%s' failed, expected weights %s, got %s", testCase.name, testCase.expectedWeights, weights)
+		}
+	}
+
+	queue := &v1beta1.Queue{ObjectMeta: metav1.ObjectMeta{Name: "dev"}, Spec: v1beta1.QueueSpec{Weight: 1}}
+	flags := specFlags{Guarantee: "cpu=2", Capability: "cpu=4,memory=8Gi"}
+	if err := flags.apply(queue, nil); err != nil {
+		t.Fatalf("failed to apply resources: %v", err)
+	}
+	if !queue.Spec.Guarantee.Resource.Cpu().Equal(resource.MustParse("2")) {
+		t.Errorf("expected guarantee cpu 2, got %v", queue.Spec.Guarantee.Resource)
+	}
+	if !queue.Spec.Capability.Memory().Equal(resource.MustParse("8Gi")) || queue.Spec.Capability[v1.ResourceCPU] != resource.MustParse("4") {
+		t.Errorf("expected capability cpu 4 and memory 8Gi, got %v", queue.Spec.Capability)
+	}
+}
+
+func TestValidateQueue(t *testing.T) {
+	queues := []v1beta1.Queue{hierarchicalQueue("dev", "root/eng/dev", "1/4/2")}
+
+	testCases := []struct {
+		name        string
+		queue       v1beta1.Queue
+		expectedErr bool
+	}{
+		{name: "valid queue", queue: v1beta1.Queue{Spec: v1beta1.QueueSpec{Weight: 1}}},
+		{name: "valid hierarchical queue", queue: hierarchicalQueue("prod", "root/eng/prod", "1/4/1")},
+		{name: "same queue in sub path", queue: hierarchicalQueue("dev", "root/eng", "1/4")},
+		{name: "zero weight", queue: v1beta1.Queue{}, expectedErr: true},
+		{
+			name:        "invalid state",
+			queue:       v1beta1.Queue{Spec: v1beta1.QueueSpec{Weight: 1}, Status: v1beta1.QueueStatus{State: "Running"}},
+			expectedErr: true,
+		},
+		{name: "length mismatch", queue: hierarchicalQueue("prod", "root/eng/prod", "1/4"), expectedErr: true},
+		{name: "invalid weight", queue: hierarchicalQueue("prod", "root/eng/prod", "1/a/1"), expectedErr: true},
+		{name: "negative weight", queue: hierarchicalQueue("prod", "root/eng/prod", "1/-1/1"), expectedErr: true},
+		{name: "other queue in sub path", queue: hierarchicalQueue("eng", "root/eng", "1/4"), expectedErr: true},
+	}
+
+	for _, testCase := range testCases {
+		err := validateQueue(&testCase.queue, queues)
+		if (err != nil) != testCase.expectedErr {
+			t.Errorf("Case '%s' failed, expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+	}
+}
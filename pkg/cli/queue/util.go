@@ -30,6 +30,7 @@ import (
 
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 )
 
@@ -44,6 +45,15 @@ func buildConfig(master, kubeconfig string) (*rest.Config, error) {
 	return clientcmd.BuildConfigFromFlags(master, kubeconfig)
 }
 
+// listQueues returns all the queues in the cluster.
+func listQueues(queueClient versioned.Interface) ([]schedulingv1beta1.Queue, error) {
+	queueList, err := queueClient.SchedulingV1beta1().Queues().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %v", err)
+	}
+	return queueList.Items, nil
+}
+
 func createQueueCommand(config *rest.Config, action busv1alpha1.Action) error {
 	queueClient := versioned.NewForConfigOrDie(config)
 	queue, err := queueClient.SchedulingV1beta1().Queues().Get(context.TODO(), operateQueueFlags.Name, metav1.GetOptions{})
@@ -34,6 +34,10 @@ type Request struct {
 	ExitCode   int32
 	Action     v1alpha1.Action
 	JobVersion int32
+	// FailedNode is the node whose failure caused the event, if any.
+	FailedNode string
+	// CrashedNode is the node where the Pod failed, if the failure is not caused by the node.
+	CrashedNode string
 }
 
 // String function returns the request in string format.
@@ -30,6 +30,7 @@ import (
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 type jobCache struct {
@@ -286,6 +287,9 @@ func (jc *jobCache) TaskCompleted(jobKey, taskName string) bool {
 			break
 		}
 	}
+	if completions, found := jobhelpers.GetTaskCompletions(jobInfo.Job)[taskName]; found {
+		taskReplicas = completions
+	}
 	if taskReplicas <= 0 {
 		return false
 	}
@@ -34,6 +34,25 @@ type ControllerOption struct {
 
 	InheritOwnerAnnotations bool
 	WorkerThreadsForPG      uint32
+
+	// SpareNodeSelector selects the nodes of pool kept as hot spares, spare nodes are disabled if it is empty
+	SpareNodeSelector string
+	MinSpareNodes     int
+	MaxSpareNodes     int
+
+	// EnableNodeInterruptionHandling enables the interruption controller
+	EnableNodeInterruptionHandling bool
+	// InterruptionTaints and InterruptionConditions are the keys of taints and the types of conditions set on
+	// nodes going to be interrupted, the interruption controller is disabled if both are empty
+	InterruptionTaints     []string
+	InterruptionConditions []string
+
+	// EnableQueueCapabilityTuning enables tuning the capability of queues within the bounds of their annotations
+	EnableQueueCapabilityTuning bool
+
+	// StartConditionAllowedHosts are the hosts which the job controller may request to check the URLs of
+	// the start conditions of jobs, the URLs of other hosts are never met
+	StartConditionAllowedHosts []string
 }
 
 // Controller is the interface of all controllers.
@@ -0,0 +1,357 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interruption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	bus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+	vcscheme "volcano.sh/apis/pkg/client/clientset/versioned/scheme"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+const (
+	// InterruptionPolicyKey is the key of annotation on Job which defines how Job reacts when its nodes are
+	// going to be interrupted.
+	InterruptionPolicyKey = "volcano.sh/interruption-policy"
+	// NotifyOnInterruption only notifies the Pods on the interrupted nodes, e.g. to checkpoint, it is the default.
+	NotifyOnInterruption = "Notify"
+	// RequeueOnInterruption notifies the Pods on the interrupted nodes, and then moves them off the nodes:
+	// the Pods are deleted to be created again if Job keeps its minAvailable Pods, otherwise Job is restarted.
+	RequeueOnInterruption = "Requeue"
+	// InterruptionGracePeriodKey is the key of annotation on Job which defines the seconds between the notice
+	// and the requeue of Pods, e.g. for them to checkpoint; it is 0 by default.
+	InterruptionGracePeriodKey = "volcano.sh/interruption-grace-period-seconds"
+	// InterruptionNoticeKey is the key of annotation on Pod with the time its node was found to be interrupted,
+	// Pods may watch it by a downwardAPI volume.
+	InterruptionNoticeKey = "volcano.sh/interruption-notice"
+
+	// NodeInterruptedReason is added in an event of Job when its nodes are going to be interrupted.
+	NodeInterruptedReason = "NodeInterrupted"
+
+	syncPeriod = 5 * time.Second
+)
+
+func init() {
+	framework.RegisterController(&interruptioncontroller{})
+}
+
+// interruptioncontroller watches the interruption notices of nodes, i.e. the taints or conditions set by node
+// termination handlers of spot or preemptible instances, and notifies or requeues the Pods of Jobs on the nodes
+// before the nodes disappear.
+type interruptioncontroller struct {
+	kubeClient kubernetes.Interface
+	vcClient   vcclientset.Interface
+
+	informerFactory   informers.SharedInformerFactory
+	vcInformerFactory vcinformer.SharedInformerFactory
+
+	// A store of nodes
+	nodeLister corelisters.NodeLister
+	// A store of pods
+	podLister corelisters.PodLister
+	// A store of jobs
+	jobLister batchlister.JobLister
+
+	recorder record.EventRecorder
+
+	// taints and conditions are the keys of taints and the types of conditions of interrupted nodes,
+	// the controller is disabled if both are empty
+	taints     map[string]bool
+	conditions map[v1.NodeConditionType]bool
+}
+
+func (ic *interruptioncontroller) Name() string {
+	return "interruption-controller"
+}
+
+// Initialize creates the interruption controller, it is disabled unless node interruption handling is enabled and
+// interruption taints or conditions are given.
+func (ic *interruptioncontroller) Initialize(opt *framework.ControllerOption) error {
+	if !opt.EnableNodeInterruptionHandling {
+		return nil
+	}
+	ic.taints = map[string]bool{}
+	for _, taint := range opt.InterruptionTaints {
+		ic.taints[taint] = true
+	}
+	ic.conditions = map[v1.NodeConditionType]bool{}
+	for _, condition := range opt.InterruptionConditions {
+		ic.conditions[v1.NodeConditionType(condition)] = true
+	}
+	if len(ic.taints) == 0 && len(ic.conditions) == 0 {
+		return nil
+	}
+
+	ic.kubeClient = opt.KubeClient
+	ic.vcClient = opt.VolcanoClient
+
+	ic.informerFactory = opt.SharedInformerFactory
+	ic.nodeLister = opt.SharedInformerFactory.Core().V1().Nodes().Lister()
+	ic.podLister = opt.SharedInformerFactory.Core().V1().Pods().Lister()
+
+	factory := vcinformer.NewSharedInformerFactory(ic.vcClient, 0)
+	ic.vcInformerFactory = factory
+	ic.jobLister = factory.Batch().V1alpha1().Jobs().Lister()
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: ic.kubeClient.CoreV1().Events("")})
+	ic.recorder = eventBroadcaster.NewRecorder(vcscheme.Scheme, v1.EventSource{Component: "vc-controller-manager"})
+
+	return nil
+}
+
+// Run starts syncing interrupted nodes periodically.
+func (ic *interruptioncontroller) Run(stopCh <-chan struct{}) {
+	if ic.nodeLister == nil {
+		klog.V(3).Infof("Interruption controller is disabled as node interruption handling is not enabled, or no interruption taints or conditions are given")
+		return
+	}
+
+	ic.informerFactory.Start(stopCh)
+	ic.vcInformerFactory.Start(stopCh)
+
+	for informerType, ok := range ic.informerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+	for informerType, ok := range ic.vcInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+
+	go wait.Until(func() {
+		if err := ic.sync(time.Now()); err != nil {
+			klog.Errorf("Failed to sync interrupted nodes: %v", err)
+		}
+	}, syncPeriod, stopCh)
+
+	klog.Infof("InterruptionController is running ...... ")
+}
+
+// isInterrupted checks whether node has an interruption taint or condition.
+func (ic *interruptioncontroller) isInterrupted(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if ic.taints[taint.Key] {
+			return true
+		}
+	}
+	for _, cond := range node.Status.Conditions {
+		if ic.conditions[cond.Type] && cond.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func isActive(pod *v1.Pod) bool {
+	return pod.DeletionTimestamp == nil && (pod.Status.Phase == v1.PodPending || pod.Status.Phase == v1.PodRunning)
+}
+
+// sync notifies the Pods of Jobs on interrupted nodes, and requeues them once their grace period is over if
+// required by the interruption policy of Jobs.
+func (ic *interruptioncontroller) sync(now time.Time) error {
+	nodes, err := ic.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	interrupted := map[string]bool{}
+	for _, node := range nodes {
+		if ic.isInterrupted(node) {
+			interrupted[node.Name] = true
+		}
+	}
+	if len(interrupted) == 0 {
+		return nil
+	}
+
+	pods, err := ic.podLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	jobPods := map[string][]*v1.Pod{}
+	affected := map[string]bool{}
+	for _, pod := range pods {
+		jobName, found := pod.Annotations[batch.JobNameKey]
+		if !found || !isActive(pod) {
+			continue
+		}
+		key := pod.Namespace + "/" + jobName
+		jobPods[key] = append(jobPods[key], pod)
+		if interrupted[pod.Spec.NodeName] {
+			affected[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(affected))
+	for key := range affected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := ic.syncJob(jobPods[key], interrupted, now); err != nil {
+			klog.Errorf("Failed to handle interruption of Job <%s>: %v", key, err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to handle interruption of %d jobs", len(errs))
+	}
+	return nil
+}
+
+// gracePeriod returns the grace period of Job between the notice and the requeue of its Pods.
+func gracePeriod(job *batch.Job) time.Duration {
+	value, found := job.Annotations[InterruptionGracePeriodKey]
+	if !found {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		klog.Warningf("Invalid %s=%s of Job <%s/%s>, ignore it.",
+			InterruptionGracePeriodKey, value, job.Namespace, job.Name)
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// syncJob notifies the active Pods of Job on interrupted nodes, and requeues them if required.
+func (ic *interruptioncontroller) syncJob(pods []*v1.Pod, interrupted map[string]bool, now time.Time) error {
+	job, err := ic.jobLister.Jobs(pods[0].Namespace).Get(pods[0].Annotations[batch.JobNameKey])
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if job.Status.State.Phase != batch.Running && job.Status.State.Phase != batch.Pending {
+		return nil
+	}
+
+	var victims []*v1.Pod
+	var notified []string
+	healthy := int32(0)
+	requeueAt := now
+	for _, pod := range pods {
+		if !interrupted[pod.Spec.NodeName] {
+			healthy++
+			continue
+		}
+		victims = append(victims, pod)
+
+		noticedAt, err := time.Parse(time.RFC3339, pod.Annotations[InterruptionNoticeKey])
+		if err != nil {
+			if err := ic.notify(pod, now); err != nil {
+				return err
+			}
+			notified = append(notified, pod.Name)
+			noticedAt = now
+		}
+		if at := noticedAt.Add(gracePeriod(job)); at.After(requeueAt) {
+			requeueAt = at
+		}
+	}
+	if len(notified) != 0 {
+		ic.recorder.Eventf(job, v1.EventTypeWarning, NodeInterruptedReason,
+			"Nodes of Pods %v are going to be interrupted", notified)
+	}
+
+	if job.Annotations[InterruptionPolicyKey] != RequeueOnInterruption || now.Before(requeueAt) {
+		return nil
+	}
+	if healthy < job.Spec.MinAvailable {
+		return ic.restartJob(job, len(victims))
+	}
+	for _, pod := range victims {
+		err := ic.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.V(3).Infof("Deleted Pod <%s/%s> on interrupted node %s", pod.Namespace, pod.Name, pod.Spec.NodeName)
+	}
+	ic.recorder.Eventf(job, v1.EventTypeNormal, NodeInterruptedReason,
+		"Deleted %d Pods on interrupted nodes, %d Pods kept for minAvailable %d", len(victims), healthy, job.Spec.MinAvailable)
+	return nil
+}
+
+// notify records the time the node of Pod was found to be interrupted in the annotation of Pod.
+func (ic *interruptioncontroller) notify(pod *v1.Pod, now time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{InterruptionNoticeKey: now.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = ic.kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// restartJob restarts Job by a RestartJob command, the command is created once for each version of Job.
+func (ic *interruptioncontroller) restartJob(job *batch.Job, victims int) error {
+	ctrlRef := metav1.NewControllerRef(job, helpers.JobKind)
+	cmd := &bus.Command{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-interrupted-v%d", job.Name, job.Status.Version),
+			Namespace:       job.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*ctrlRef},
+		},
+		TargetObject: ctrlRef,
+		Action:       string(bus.RestartJobAction),
+	}
+	if _, err := ic.vcClient.BusV1alpha1().Commands(job.Namespace).Create(context.TODO(), cmd, metav1.CreateOptions{}); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	ic.recorder.Eventf(job, v1.EventTypeNormal, NodeInterruptedReason,
+		"Restarting Job as %d Pods are on interrupted nodes and fewer than minAvailable %d Pods are left",
+		victims, job.Spec.MinAvailable)
+	return nil
+}
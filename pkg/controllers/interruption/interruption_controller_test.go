@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+const spotTaint = "aws-node-termination-handler/spot-itn"
+
+func newNode(name string, interrupted bool) *v1.Node {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if interrupted {
+		node.Spec.Taints = []v1.Taint{{Key: spotTaint, Effect: v1.TaintEffectNoSchedule}}
+	}
+	return node
+}
+
+func newJob(name string, minAvailable int32, annotations map[string]string) *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Annotations: annotations},
+		Spec:       batch.JobSpec{MinAvailable: minAvailable},
+		Status:     batch.JobStatus{State: batch.JobState{Phase: batch.Running}, Version: 1},
+	}
+}
+
+func newPod(job string, index int, node string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        fmt.Sprintf("%s-worker-%d", job, index),
+			Annotations: map[string]string{batch.JobNameKey: job},
+		},
+		Spec:   v1.PodSpec{NodeName: node},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func newController(t *testing.T, nodes []*v1.Node, pods []*v1.Pod, jobs []*batch.Job) *interruptioncontroller {
+	kubeClient := kubeclient.NewSimpleClientset()
+	vcClient := vcclient.NewSimpleClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	vcFactory := vcinformer.NewSharedInformerFactory(vcClient, 0)
+
+	for _, node := range nodes {
+		factory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+	}
+	for _, pod := range pods {
+		if _, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+	}
+	for _, job := range jobs {
+		vcFactory.Batch().V1alpha1().Jobs().Informer().GetIndexer().Add(job)
+	}
+
+	return &interruptioncontroller{
+		kubeClient: kubeClient,
+		vcClient:   vcClient,
+		nodeLister: factory.Core().V1().Nodes().Lister(),
+		podLister:  factory.Core().V1().Pods().Lister(),
+		jobLister:  vcFactory.Batch().V1alpha1().Jobs().Lister(),
+		recorder:   record.NewFakeRecorder(10),
+		taints:     map[string]bool{spotTaint: true},
+		conditions: map[v1.NodeConditionType]bool{"TerminateScheduled": true},
+	}
+}
+
+func TestInitializeOptIn(t *testing.T) {
+	kubeClient := kubeclient.NewSimpleClientset()
+	opt := &framework.ControllerOption{
+		KubeClient:            kubeClient,
+		VolcanoClient:         vcclient.NewSimpleClientset(),
+		SharedInformerFactory: informers.NewSharedInformerFactory(kubeClient, 0),
+		InterruptionTaints:    []string{spotTaint},
+	}
+
+	ic := &interruptioncontroller{}
+	if err := ic.Initialize(opt); err != nil {
+		t.Fatal(err)
+	}
+	if ic.nodeLister != nil {
+		t.Errorf("expected interruption controller disabled by default")
+	}
+
+	opt.EnableNodeInterruptionHandling = true
+	ic = &interruptioncontroller{}
+	if err := ic.Initialize(opt); err != nil {
+		t.Fatal(err)
+	}
+	if ic.nodeLister == nil {
+		t.Errorf("expected interruption controller enabled with interruption taints")
+	}
+}
+
+func TestIsInterrupted(t *testing.T) {
+	ic := newController(t, nil, nil, nil)
+	conditioned := newNode("n3", false)
+	conditioned.Status.Conditions = []v1.NodeCondition{{Type: "TerminateScheduled", Status: v1.ConditionTrue}}
+	cleared := newNode("n4", false)
+	cleared.Status.Conditions = []v1.NodeCondition{{Type: "TerminateScheduled", Status: v1.ConditionFalse}}
+
+	for node, expected := range map[*v1.Node]bool{
+		newNode("n1", true): true, newNode("n2", false): false, conditioned: true, cleared: false,
+	} {
+		if got := ic.isInterrupted(node); got != expected {
+			t.Errorf("node %s: expected interrupted %v, got %v", node.Name, expected, got)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	now := time.Now()
+	noticed := now.Add(-time.Minute).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name        string
+		job         *batch.Job
+		notice      string
+		deleted     bool
+		restarted   bool
+		annotated   bool
+		podsOnSpot  int
+		podsOnFixed int
+	}{
+		{
+			name:        "notify by default",
+			job:         newJob("j1", 2, nil),
+			podsOnSpot:  1,
+			podsOnFixed: 2,
+			annotated:   true,
+		},
+		{
+			name:        "delete pods if min available kept",
+			job:         newJob("j1", 2, map[string]string{InterruptionPolicyKey: RequeueOnInterruption}),
+			podsOnSpot:  1,
+			podsOnFixed: 2,
+			annotated:   true,
+			deleted:     true,
+		},
+		{
+			name:        "restart job if min available broken",
+			job:         newJob("j1", 3, map[string]string{InterruptionPolicyKey: RequeueOnInterruption}),
+			podsOnSpot:  1,
+			podsOnFixed: 2,
+			annotated:   true,
+			restarted:   true,
+		},
+		{
+			name: "wait for grace period",
+			job: newJob("j1", 3, map[string]string{InterruptionPolicyKey: RequeueOnInterruption,
+				InterruptionGracePeriodKey: "120"}),
+			notice:      noticed,
+			podsOnSpot:  1,
+			podsOnFixed: 2,
+		},
+		{
+			name: "requeue after grace period",
+			job: newJob("j1", 3, map[string]string{InterruptionPolicyKey: RequeueOnInterruption,
+				InterruptionGracePeriodKey: "30"}),
+			notice:      noticed,
+			podsOnSpot:  1,
+			podsOnFixed: 2,
+			restarted:   true,
+		},
+	}
+
+	for _, test := range tests {
+		var pods []*v1.Pod
+		for i := 0; i < test.podsOnSpot+test.podsOnFixed; i++ {
+			node := "fixed"
+			if i < test.podsOnSpot {
+				node = "spot"
+			}
+			pod := newPod(test.job.Name, i, node)
+			if node == "spot" && test.notice != "" {
+				pod.Annotations[InterruptionNoticeKey] = test.notice
+			}
+			pods = append(pods, pod)
+		}
+		ic := newController(t, []*v1.Node{newNode("spot", true), newNode("fixed", false)}, pods, []*batch.Job{test.job})
+
+		if err := ic.sync(now); err != nil {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+
+		pod, err := ic.kubeClient.CoreV1().Pods("ns").Get(context.TODO(), pods[0].Name, metav1.GetOptions{})
+		if deleted := err != nil; deleted != test.deleted {
+			t.Errorf("%s: expected pod on spot node deleted %v, got %v", test.name, test.deleted, deleted)
+		}
+		if err == nil {
+			if _, annotated := pod.Annotations[InterruptionNoticeKey]; annotated != (test.annotated || test.notice != "") {
+				t.Errorf("%s: unexpected annotations %v", test.name, pod.Annotations)
+			}
+		}
+		if _, err := ic.kubeClient.CoreV1().Pods("ns").Get(context.TODO(), pods[len(pods)-1].Name, metav1.GetOptions{}); err != nil {
+			t.Errorf("%s: expected pod on fixed node kept, got %v", test.name, err)
+		}
+
+		cmds, err := ic.vcClient.BusV1alpha1().Commands("ns").List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if restarted := len(cmds.Items) == 1 && cmds.Items[0].Action == "RestartJob"; restarted != test.restarted {
+			t.Errorf("%s: expected job restarted %v, got commands %v", test.name, test.restarted, cmds.Items)
+		}
+	}
+}
@@ -31,3 +31,46 @@ const (
 	// is successfully deleted.
 	SuccessfulDeletePodReason = "SuccessfulDelete"
 )
+
+// Annotation and its values which define how Job reacts when its Pods fail because of node failure.
+const (
+	// FailureDomainRestartPolicyKey is the key of annotation on Job which defines
+	// the action taken when Pods of Job fail because of node failure.
+	FailureDomainRestartPolicyKey = "volcano.sh/failure-domain-restart-policy"
+	// RestartJobOnNodeFailure restarts the whole Job.
+	RestartJobOnNodeFailure = "RestartJob"
+	// RestartFailedTasksOnNodeFailure restarts only the failed Pods of Job.
+	RestartFailedTasksOnNodeFailure = "RestartFailedTasks"
+	// RestartFailedTasksExcludingNodeOnNodeFailure restarts only the failed Pods of Job,
+	// and excludes the failed node from scheduling of Job.
+	RestartFailedTasksExcludingNodeOnNodeFailure = "RestartFailedTasksExcludingNode"
+)
+
+// Annotations on Job which define the conditions to meet before Job starts.
+const (
+	// WaitForObjectsKey is the key of annotation on Job which lists the objects, in the namespace of Job,
+	// to exist before Job starts, e.g. "configmap/input,secret/token".
+	WaitForObjectsKey = "volcano.sh/wait-for-objects"
+	// WaitForURLsKey is the key of annotation on Job which lists the URLs to return 200 before Job starts,
+	// e.g. "http://data-loader.default/healthz". Only the hosts allowed by the controller manager are requested.
+	WaitForURLsKey = "volcano.sh/wait-for-urls"
+	// StartConditionsNotMetReason is added in an event when Job waits for its start conditions.
+	StartConditionsNotMetReason = "StartConditionsNotMet"
+)
+
+// Annotations which define and keep the per-Job blacklist of the nodes where Pods of Job fail repeatedly.
+const (
+	// NodeBlacklistThresholdKey is the key of annotation on Job which defines how many times Pods of Job
+	// may fail on a node, not because of node failure, before the node is excluded from scheduling of Job.
+	NodeBlacklistThresholdKey = "volcano.sh/node-blacklist-threshold"
+	// NodeFailureCountsKey is the key of annotation on PodGroup which keeps how many times Pods of Job
+	// failed on each node, e.g. "node1=2,node2=1".
+	NodeFailureCountsKey = "volcano.sh/node-failure-counts"
+)
+
+// Annotations which keep the placement of the Pods of Job restarted by the RestartTask policy.
+const (
+	// PreviousNodesKey is the key of annotation on PodGroup which keeps the nodes the Pods of the restarted
+	// tasks ran on, e.g. "job-worker-0=node1,job-worker-1=node2".
+	PreviousNodesKey = "volcano.sh/previous-nodes"
+)
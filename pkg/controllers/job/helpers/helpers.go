@@ -35,6 +35,19 @@ const (
 	PodNameFmt = "%s-%s-%d"
 	// persistentVolumeClaimFmt represents persistent volume claim name format
 	persistentVolumeClaimFmt = "%s-pvc-%s"
+	// TaskCompletionsKey is the key of annotation on Job which defines the number of successful
+	// completions of its tasks, e.g. "map=100,reduce=10". The replicas of such a task are the
+	// number of its pods running at the same time.
+	TaskCompletionsKey = "volcano.sh/task-completions"
+	// MinSuccessPolicyKey is the key of annotation on Job which defines what to do with the pending and
+	// running pods of the job once `minSuccess` pods succeeded, one of MinSuccessPolicyKill and MinSuccessPolicyWait.
+	MinSuccessPolicyKey = "volcano.sh/min-success-policy"
+	// MinSuccessPolicyKill completes the job by killing its pending and running pods, the default.
+	MinSuccessPolicyKill = "Kill"
+	// MinSuccessPolicyWait completes the job once its pending and running pods finish.
+	MinSuccessPolicyWait = "Wait"
+	// defaultMaxRetry is the max retry of task if not set.
+	defaultMaxRetry = 3
 )
 
 // GetPodIndexUnderTask returns task Index.
@@ -143,3 +156,47 @@ func GetPodsNameUnderTask(taskName string, job *batch.Job) []string {
 	}
 	return res
 }
+
+// GetMinSuccessPolicy returns the policy defined by the volcano.sh/min-success-policy annotation of job,
+// MinSuccessPolicyKill if it is not set.
+func GetMinSuccessPolicy(job *batch.Job) string {
+	if policy, found := job.Annotations[MinSuccessPolicyKey]; found && policy != "" {
+		return policy
+	}
+	return MinSuccessPolicyKill
+}
+
+// GetTaskCompletions returns the completions of tasks defined by the volcano.sh/task-completions annotation of job.
+func GetTaskCompletions(job *batch.Job) map[string]int32 {
+	value, found := job.Annotations[TaskCompletionsKey]
+	if !found {
+		return nil
+	}
+
+	completions := map[string]int32{}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		count, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil || count <= 0 {
+			continue
+		}
+		completions[parts[0]] = int32(count)
+	}
+	return completions
+}
+
+// IsCompletionsExhausted returns whether the task with completions failed too many times to replace
+// its failed pods, according to its maxRetry.
+func IsCompletionsExhausted(ts batch.TaskSpec, failed int32) bool {
+	maxRetry := ts.MaxRetry
+	if maxRetry == -1 {
+		return false
+	}
+	if maxRetry == 0 {
+		maxRetry = defaultMaxRetry
+	}
+	return failed >= maxRetry
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package helpers
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -322,3 +323,35 @@ func contains(s []string, e string) bool {
 	}
 	return false
 }
+
+func TestGetTaskCompletions(t *testing.T) {
+	testcases := []struct {
+		Name        string
+		Annotations map[string]string
+		Expected    map[string]int32
+	}{
+		{
+			Name:     "no completions",
+			Expected: nil,
+		},
+		{
+			Name:        "completions of tasks",
+			Annotations: map[string]string{TaskCompletionsKey: "map=100, reduce=10"},
+			Expected:    map[string]int32{"map": 100, "reduce": 10},
+		},
+		{
+			Name:        "invalid completions ignored",
+			Annotations: map[string]string{TaskCompletionsKey: "map=0,reduce=x,merge,sort=5"},
+			Expected:    map[string]int32{"sort": 5},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Annotations: testcase.Annotations}}
+			if completions := GetTaskCompletions(job); !reflect.DeepEqual(completions, testcase.Expected) {
+				t.Errorf("expected completions %v, got %v", testcase.Expected, completions)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -63,14 +64,16 @@ type jobcontroller struct {
 	kubeClient kubernetes.Interface
 	vcClient   vcclientset.Interface
 
-	jobInformer   batchinformer.JobInformer
-	podInformer   coreinformers.PodInformer
-	pvcInformer   coreinformers.PersistentVolumeClaimInformer
-	pgInformer    schedulinginformers.PodGroupInformer
-	svcInformer   coreinformers.ServiceInformer
-	cmdInformer   businformer.CommandInformer
-	pcInformer    kubeschedulinginformers.PriorityClassInformer
-	queueInformer schedulinginformers.QueueInformer
+	jobInformer    batchinformer.JobInformer
+	podInformer    coreinformers.PodInformer
+	pvcInformer    coreinformers.PersistentVolumeClaimInformer
+	pgInformer     schedulinginformers.PodGroupInformer
+	svcInformer    coreinformers.ServiceInformer
+	cmInformer     coreinformers.ConfigMapInformer
+	secretInformer coreinformers.SecretInformer
+	cmdInformer    businformer.CommandInformer
+	pcInformer     kubeschedulinginformers.PriorityClassInformer
+	queueInformer  schedulinginformers.QueueInformer
 
 	informerFactory   informers.SharedInformerFactory
 	vcInformerFactory vcinformer.SharedInformerFactory
@@ -100,6 +103,12 @@ type jobcontroller struct {
 	pcLister kubeschedulinglisters.PriorityClassLister
 	pcSynced func() bool
 
+	cmLister corelisters.ConfigMapLister
+	cmSynced func() bool
+
+	secretLister corelisters.SecretLister
+	secretSynced func() bool
+
 	queueLister schedulinglisters.QueueLister
 	queueSynced func() bool
 
@@ -113,6 +122,15 @@ type jobcontroller struct {
 	errTasks      workqueue.RateLimitingInterface
 	workers       uint32
 	maxRequeueNum int
+
+	// startConditionsBackoff is the delay between the checks of the start conditions of the Jobs waiting for them.
+	startConditionsBackoff workqueue.RateLimiter
+	// urlChecker checks the URLs of the start conditions.
+	urlChecker *urlChecker
+
+	// restartedPods keeps the UIDs of the Pods killed to restart their task, whose
+	// deletion must not trigger the policy of the task again, with the time they were killed.
+	restartedPods sync.Map
 }
 
 func (cc *jobcontroller) Name() string {
@@ -137,6 +155,8 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.commandQueue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
 	cc.cache = jobcache.New()
 	cc.errTasks = newRateLimitingQueue()
+	cc.startConditionsBackoff = workqueue.NewItemExponentialFailureRateLimiter(startConditionsMinRetryPeriod, startConditionsMaxRetryPeriod)
+	cc.urlChecker = newURLChecker(opt.StartConditionAllowedHosts)
 	cc.recorder = recorder
 	cc.workers = workers
 	cc.maxRequeueNum = opt.MaxRequeueNum
@@ -203,6 +223,14 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.svcLister = cc.svcInformer.Lister()
 	cc.svcSynced = cc.svcInformer.Informer().HasSynced
 
+	cc.cmInformer = sharedInformers.Core().V1().ConfigMaps()
+	cc.cmLister = cc.cmInformer.Lister()
+	cc.cmSynced = cc.cmInformer.Informer().HasSynced
+
+	cc.secretInformer = sharedInformers.Core().V1().Secrets()
+	cc.secretLister = cc.secretInformer.Lister()
+	cc.secretSynced = cc.secretInformer.Informer().HasSynced
+
 	cc.pgInformer = factory.Scheduling().V1beta1().PodGroups()
 	cc.pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: cc.updatePodGroup,
@@ -221,6 +249,7 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	// Register actions
 	state.SyncJob = cc.syncJob
 	state.KillJob = cc.killJob
+	state.RestartFailedTasks = cc.restartFailedTasks
 
 	return nil
 }
@@ -342,21 +371,34 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 			"Start to execute action %s ", action))
 	}
 
-	if err := st.Execute(action); err != nil {
-		if cc.maxRequeueNum == -1 || queue.NumRequeues(req) < cc.maxRequeueNum {
-			klog.V(2).Infof("Failed to handle Job <%s/%s>: %v",
+	if action == state.RestartFailedTasksAction {
+		if err := cc.excludeFailedNode(jobInfo.Job, &req); err != nil {
+			klog.V(2).Infof("Failed to exclude failed node of Job <%s/%s>: %v",
 				jobInfo.Job.Namespace, jobInfo.Job.Name, err)
-			// If any error, requeue it.
-			queue.AddRateLimited(req)
+			cc.handleActionError(queue, req, jobInfo, st, action, err)
 			return true
 		}
-		cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, batchv1alpha1.ExecuteAction, fmt.Sprintf(
-			"Job failed on action %s for retry limit reached", action))
-		klog.Warningf("Terminating Job <%s/%s> and releasing resources", jobInfo.Job.Namespace, jobInfo.Job.Name)
-		if err = st.Execute(busv1alpha1.TerminateJobAction); err != nil {
-			klog.Errorf("Failed to terminate Job<%s/%s>: %v", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+	}
+
+	if action == busv1alpha1.RestartTaskAction {
+		if err := cc.restartTask(jobInfo, &req); err != nil {
+			klog.V(2).Infof("Failed to restart task %s of Job <%s/%s>: %v",
+				req.TaskName, jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+			cc.handleActionError(queue, req, jobInfo, st, action, err)
+			return true
 		}
-		klog.Warningf("Dropping job<%s/%s> out of the queue: %v because max retries has reached", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+	}
+
+	if err := st.Execute(action); err != nil {
+		cc.handleActionError(queue, req, jobInfo, st, action, err)
+		return true
+	}
+
+	// The crash is recorded once the action handling it succeeded, so that the retries of the action do not
+	// count it again.
+	if err := cc.recordCrashedNode(jobInfo, &req); err != nil {
+		cc.retryRecordCrashedNode(queue, req, jobInfo, err)
+		return true
 	}
 
 	// If no error, forget it.
@@ -364,3 +406,47 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 
 	return true
 }
+
+// handleActionError requeues req whose action failed, until the max requeue number is reached, then Job is
+// terminated.
+func (cc *jobcontroller) handleActionError(queue workqueue.RateLimitingInterface, req apis.Request, jobInfo *apis.JobInfo,
+	st state.State, action busv1alpha1.Action, err error) {
+	if cc.maxRequeueNum == -1 || queue.NumRequeues(req) < cc.maxRequeueNum {
+		klog.V(2).Infof("Failed to handle Job <%s/%s>: %v",
+			jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+		// If any error, requeue it.
+		queue.AddRateLimited(req)
+		return
+	}
+	cc.recordJobEvent(jobInfo.Job.Namespace, jobInfo.Job.Name, batchv1alpha1.ExecuteAction, fmt.Sprintf(
+		"Job failed on action %s for retry limit reached", action))
+	klog.Warningf("Terminating Job <%s/%s> and releasing resources", jobInfo.Job.Namespace, jobInfo.Job.Name)
+	if err = st.Execute(busv1alpha1.TerminateJobAction); err != nil {
+		klog.Errorf("Failed to terminate Job<%s/%s>: %v", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+	}
+	klog.Warningf("Dropping job<%s/%s> out of the queue: %v because max retries has reached", jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+	queue.Forget(req)
+}
+
+// retryRecordCrashedNode requeues the record of the crashed node of req which failed, as a sync of Job so that
+// the action of req is not executed again, until the max requeue number is reached.
+func (cc *jobcontroller) retryRecordCrashedNode(queue workqueue.RateLimitingInterface, req apis.Request, jobInfo *apis.JobInfo, err error) {
+	crashReq := apis.Request{
+		Namespace:   req.Namespace,
+		JobName:     req.JobName,
+		Action:      busv1alpha1.SyncJobAction,
+		CrashedNode: req.CrashedNode,
+	}
+	if crashReq != req {
+		queue.Forget(req)
+	}
+	if cc.maxRequeueNum == -1 || queue.NumRequeues(crashReq) < cc.maxRequeueNum {
+		klog.V(2).Infof("Failed to record crashed node %s of Job <%s/%s>: %v",
+			req.CrashedNode, jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+		queue.AddRateLimited(crashReq)
+		return
+	}
+	klog.Warningf("Dropping crashed node %s of Job <%s/%s> because max retries has reached: %v",
+		req.CrashedNode, jobInfo.Job.Namespace, jobInfo.Job.Name, err)
+	queue.Forget(crashReq)
+}
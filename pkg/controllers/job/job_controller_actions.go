@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -39,6 +41,7 @@ import (
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
 	"volcano.sh/volcano/pkg/controllers/util"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 var calMutex sync.Mutex
@@ -257,6 +260,10 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 
 	// Skip job initiation if job is already initiated
 	if !isInitiated(job) {
+		// Neither PodGroup nor Pods of Job are created until its start conditions are met
+		if cc.waitForStartConditions(job) {
+			return nil
+		}
 		if job, err = cc.initiateJob(job); err != nil {
 			return err
 		}
@@ -278,8 +285,10 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 	}
 
 	var syncTask bool
+	var previousNodes map[string]string
 	pgName := job.Name + "-" + string(job.UID)
 	if pg, _ := cc.pgLister.PodGroups(job.Namespace).Get(pgName); pg != nil {
+		previousNodes = parsePreviousNodes(pg.Annotations[PreviousNodesKey])
 		if pg.Status.Phase != "" && pg.Status.Phase != scheduling.PodGroupPending {
 			syncTask = true
 		}
@@ -332,6 +341,7 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 
 	waitCreationGroup := sync.WaitGroup{}
 
+	taskCompletions := jobhelpers.GetTaskCompletions(job)
 	for _, ts := range job.Spec.Tasks {
 		ts.Template.Name = ts.Name
 		tc := ts.Template.DeepCopy()
@@ -342,11 +352,19 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			pods = map[string]*v1.Pod{}
 		}
 
+		podNum := int(ts.Replicas)
+		if completions, found := taskCompletions[name]; found {
+			podNum = completionPodNum(ts, completions, pods)
+		}
+
 		var podToCreateEachTask []*v1.Pod
-		for i := 0; i < int(ts.Replicas); i++ {
+		for i := 0; i < podNum; i++ {
 			podName := fmt.Sprintf(jobhelpers.PodNameFmt, job.Name, name, i)
 			if pod, found := pods[podName]; !found {
 				newPod := createJobPod(job, tc, ts.TopologyPolicy, i, jobForwarding)
+				if node, found := previousNodes[podName]; found {
+					newPod.Annotations[schedulingapi.PreviousNodeAnnotation] = node
+				}
 				if err := cc.pluginOnPodCreate(job, newPod); err != nil {
 					return err
 				}
@@ -705,6 +723,39 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 		}
 	}
 
+	// The PodGroup in the lister is shared, update a copy of it.
+	pg = pg.DeepCopy()
+	if !cc.syncPodGroupSpec(pg, job) {
+		return nil
+	}
+
+	// MinMember, MinResources and MinTaskMember are updated together by one update, so the scheduler never
+	// sees the gang of the job partly scaled. If the PodGroup in the lister is stale, they are recalculated
+	// on the latest one instead of waiting for the next sync of the job. The update carries the resourceVersion
+	// of the PodGroup it is calculated on, so it never overwrites a status written by the scheduler since: it
+	// conflicts and is recalculated on the latest PodGroup.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := cc.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+		latest, getErr := cc.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Get(context.TODO(), pg.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+		pg = latest
+		cc.syncPodGroupSpec(pg, job)
+		return err
+	})
+	if err != nil {
+		klog.V(3).Infof("Failed to update PodGroup for Job <%s/%s>: %v",
+			job.Namespace, job.Name, err)
+	}
+	return err
+}
+
+// syncPodGroupSpec updates the spec and the annotations of pg synced from job, and returns whether pg is changed.
+func (cc *jobcontroller) syncPodGroupSpec(pg *scheduling.PodGroup, job *batch.Job) bool {
 	pgShouldUpdate := false
 	if pg.Spec.PriorityClassName != job.Spec.PriorityClassName {
 		pg.Spec.PriorityClassName = job.Spec.PriorityClassName
@@ -718,12 +769,28 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 		pgShouldUpdate = true
 	}
 
+	jobPaused, jobPausedFound := job.Annotations[schedulingapi.SchedulingPausedAnnotation]
+	pgPaused, pgPausedFound := pg.Annotations[schedulingapi.SchedulingPausedAnnotation]
+	if jobPausedFound != pgPausedFound || jobPaused != pgPaused {
+		if jobPausedFound {
+			if pg.Annotations == nil {
+				pg.Annotations = make(map[string]string)
+			}
+			pg.Annotations[schedulingapi.SchedulingPausedAnnotation] = jobPaused
+		} else {
+			delete(pg.Annotations, schedulingapi.SchedulingPausedAnnotation)
+		}
+		pgShouldUpdate = true
+	}
+
 	if pg.Spec.MinTaskMember == nil {
 		pgShouldUpdate = true
 		pg.Spec.MinTaskMember = make(map[string]int32)
 	}
 
+	tasks := make(map[string]bool, len(job.Spec.Tasks))
 	for _, task := range job.Spec.Tasks {
+		tasks[task.Name] = true
 		cnt := task.Replicas
 		if task.MinAvailable != nil {
 			cnt = *task.MinAvailable
@@ -742,16 +809,15 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 		}
 	}
 
-	if !pgShouldUpdate {
-		return nil
+	// The tasks removed from job must not be waited for by the gang of job.
+	for task := range pg.Spec.MinTaskMember {
+		if !tasks[task] {
+			pgShouldUpdate = true
+			delete(pg.Spec.MinTaskMember, task)
+		}
 	}
 
-	_, err = cc.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
-	if err != nil {
-		klog.V(3).Infof("Failed to update PodGroup for Job <%s/%s>: %v",
-			job.Namespace, job.Name, err)
-	}
-	return err
+	return pgShouldUpdate
 }
 
 func (cc *jobcontroller) deleteJobPod(jobName string, pod *v1.Pod) error {
@@ -830,6 +896,46 @@ func (cc *jobcontroller) initJobStatus(job *batch.Job) (*batch.Job, error) {
 	return newJob, nil
 }
 
+// completionPodNum returns the number of pods of task with completions, so that as many pods as its replicas run
+// at the same time until it reaches completions. Pods of such a task are never scaled down: a pod succeeded or
+// failed is replaced by a new one with the next index, until the task fails more than its maxRetry.
+func completionPodNum(ts batch.TaskSpec, completions int32, pods map[string]*v1.Pod) int {
+	var succeeded, failed, active int32
+	maxIndex := -1
+	for _, pod := range pods {
+		if index, err := strconv.Atoi(jobhelpers.GetPodIndexUnderTask(pod)); err == nil && index > maxIndex {
+			maxIndex = index
+		}
+		switch pod.Status.Phase {
+		case v1.PodSucceeded:
+			succeeded++
+		case v1.PodFailed:
+			failed++
+		default:
+			active++
+		}
+	}
+
+	// Pods missing under maxIndex, e.g. deleted to restart, are created again in any case.
+	podNum := maxIndex + 1
+	missing := int32(podNum - len(pods))
+	if missing < 0 {
+		missing = 0
+	}
+
+	if jobhelpers.IsCompletionsExhausted(ts, failed) {
+		return podNum
+	}
+	target := completions - succeeded
+	if target > ts.Replicas {
+		target = ts.Replicas
+	}
+	if more := target - active - missing; more > 0 {
+		podNum += int(more)
+	}
+	return podNum
+}
+
 func classifyAndAddUpPodBaseOnPhase(pod *v1.Pod, pending, running, succeeded, failed, unknown *int32) {
 	switch pod.Status.Phase {
 	case v1.PodPending:
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/agiledragon/gomonkey/v2"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"reflect"
 	"testing"
@@ -613,6 +614,73 @@ func TestUpdatePodGroupIfJobUpdateFunc(t *testing.T) {
 
 }
 
+func TestUpdatePodGroupOnJobScale(t *testing.T) {
+	namespace := "test"
+	pg := &schedulingapi.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "job1-e7f18111-1cec-11ea-b688-fa163ec79500",
+		},
+		Spec: schedulingapi.PodGroupSpec{
+			MinMember:     3,
+			MinTaskMember: map[string]int32{"ps": 1, "worker": 2},
+			MinResources:  &v1.ResourceList{},
+		},
+	}
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            "job1",
+			ResourceVersion: "100",
+			UID:             "e7f18111-1cec-11ea-b688-fa163ec79500",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 4,
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "worker",
+					Replicas: 4,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{{
+								Name: "worker",
+								Resources: v1.ResourceRequirements{
+									Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	fakeController := newFakeController()
+	fakeController.pgInformer.Informer().GetIndexer().Add(pg)
+	fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{})
+
+	if err := fakeController.createOrUpdatePodGroup(job); err != nil {
+		t.Fatalf("Expected PodGroup to be updated, but got: %v", err)
+	}
+
+	updated, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pg.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get PodGroup: %v", err)
+	}
+	if updated.Spec.MinMember != 4 {
+		t.Errorf("Expected MinMember to be 4, but got: %d", updated.Spec.MinMember)
+	}
+	if !reflect.DeepEqual(updated.Spec.MinTaskMember, map[string]int32{"worker": 4}) {
+		t.Errorf("Expected MinTaskMember of removed task to be dropped, but got: %v", updated.Spec.MinTaskMember)
+	}
+	if cpu := (*updated.Spec.MinResources)[v1.ResourceCPU]; cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("Expected MinResources to be recalculated to 4 cpu, but got: %v", *updated.Spec.MinResources)
+	}
+	if pg.Spec.MinMember != 3 {
+		t.Errorf("Expected PodGroup in lister not to be mutated, but got MinMember %d", pg.Spec.MinMember)
+	}
+}
+
 func TestDeleteJobPod(t *testing.T) {
 	namespace := "test"
 
@@ -664,3 +732,188 @@ func TestDeleteJobPod(t *testing.T) {
 		})
 	}
 }
+
+func TestCompletionPodNum(t *testing.T) {
+	namespace := "test"
+
+	buildPods := func(phases ...v1.PodPhase) map[string]*v1.Pod {
+		pods := map[string]*v1.Pod{}
+		for i, phase := range phases {
+			if phase == "" {
+				continue
+			}
+			name := fmt.Sprintf("job1-task1-%d", i)
+			pods[name] = buildPod(namespace, name, phase, nil)
+		}
+		return pods
+	}
+
+	testcases := []struct {
+		Name        string
+		Replicas    int32
+		MaxRetry    int32
+		Completions int32
+		Pods        map[string]*v1.Pod
+		Expected    int
+	}{
+		{
+			Name:        "no pods created",
+			Replicas:    3,
+			Completions: 10,
+			Pods:        buildPods(),
+			Expected:    3,
+		},
+		{
+			Name:        "completions less than replicas",
+			Replicas:    3,
+			Completions: 2,
+			Pods:        buildPods(),
+			Expected:    2,
+		},
+		{
+			Name:        "succeeded pod replaced",
+			Replicas:    3,
+			Completions: 10,
+			Pods:        buildPods(v1.PodSucceeded, v1.PodRunning, v1.PodRunning),
+			Expected:    4,
+		},
+		{
+			Name:        "pods missing created again instead of new ones",
+			Replicas:    3,
+			Completions: 10,
+			Pods:        buildPods(v1.PodSucceeded, "", v1.PodRunning, v1.PodRunning),
+			Expected:    4,
+		},
+		{
+			Name:        "no more pods needed to reach completions",
+			Replicas:    3,
+			Completions: 4,
+			Pods:        buildPods(v1.PodSucceeded, v1.PodSucceeded, v1.PodSucceeded, v1.PodRunning, v1.PodRunning),
+			Expected:    5,
+		},
+		{
+			Name:        "failed pods not replaced when max retry reached",
+			Replicas:    2,
+			MaxRetry:    2,
+			Completions: 10,
+			Pods:        buildPods(v1.PodFailed, v1.PodFailed, v1.PodRunning),
+			Expected:    3,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			ts := v1alpha1.TaskSpec{Name: "task1", Replicas: testcase.Replicas, MaxRetry: testcase.MaxRetry}
+			if podNum := completionPodNum(ts, testcase.Completions, testcase.Pods); podNum != testcase.Expected {
+				t.Errorf("expected %d pods, got %d", testcase.Expected, podNum)
+			}
+		})
+	}
+}
+
+func TestRestartFailedTasksCountsRetries(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name          string
+		RetryCount    int32
+		ExpectRetries int32
+		ExpectPhase   v1alpha1.JobPhase
+	}{
+		{
+			Name:          "restarting failed tasks counts a retry",
+			RetryCount:    0,
+			ExpectRetries: 1,
+			ExpectPhase:   v1alpha1.Running,
+		},
+		{
+			Name:          "job reaching max retry fails",
+			RetryCount:    3,
+			ExpectRetries: 3,
+			ExpectPhase:   v1alpha1.Failed,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+			patches := gomonkey.ApplyMethod(reflect.TypeOf(fakeController), "GetQueueInfo", func(_ *jobcontroller, _ string) (*schedulingapi.Queue, error) {
+				return &schedulingapi.Queue{}, nil
+			})
+			defer patches.Reset()
+
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "job1",
+					Namespace:       namespace,
+					ResourceVersion: "100",
+					UID:             "e7f18111-1cec-11ea-b688-fa163ec79500",
+				},
+				Spec: v1alpha1.JobSpec{
+					MaxRetry: 3,
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task1",
+							Replicas: 2,
+							Template: v1.PodTemplateSpec{
+								Spec: v1.PodSpec{Containers: []v1.Container{{Name: "Containers"}}},
+							},
+						},
+					},
+				},
+				Status: v1alpha1.JobStatus{
+					State:      v1alpha1.JobState{Phase: v1alpha1.Running},
+					RetryCount: testcase.RetryCount,
+				},
+			}
+			podGroup := &schedulingapi.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1-e7f18111-1cec-11ea-b688-fa163ec79500",
+					Namespace: namespace,
+				},
+				Spec: schedulingapi.PodGroupSpec{
+					MinResources:  &v1.ResourceList{},
+					MinTaskMember: map[string]int32{},
+				},
+				Status: schedulingapi.PodGroupStatus{Phase: schedulingapi.PodGroupRunning},
+			}
+			pods := map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "job1-task1-0", v1.PodRunning, nil),
+				"job1-task1-1": buildPod(namespace, "job1-task1-1", v1.PodFailed, nil),
+			}
+			jobInfo := &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "job1",
+				Job:       job,
+				Pods:      map[string]map[string]*v1.Pod{"task1": pods},
+			}
+
+			fakeController.pgInformer.Informer().GetIndexer().Add(podGroup)
+			fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), podGroup, metav1.CreateOptions{})
+			for _, pod := range pods {
+				if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Error while creating pod: %v", err)
+				}
+			}
+			if _, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("Error while creating job: %v", err)
+			}
+			if err := fakeController.cache.Add(job); err != nil {
+				t.Fatalf("Error while adding job in cache: %v", err)
+			}
+
+			if err := fakeController.restartFailedTasks(jobInfo, nil); err != nil {
+				t.Fatalf("Expected no error while restarting failed tasks, but got error: %v", err)
+			}
+
+			updated, err := fakeController.vcClient.BatchV1alpha1().Jobs(namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Error while getting job: %v", err)
+			}
+			if updated.Status.RetryCount != testcase.ExpectRetries || updated.Status.State.Phase != testcase.ExpectPhase {
+				t.Errorf("Expected retry count %d and phase %s, got %d and %s", testcase.ExpectRetries, testcase.ExpectPhase,
+					updated.Status.RetryCount, updated.Status.State.Phase)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/job/state"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// nodeFailureReasons are the reasons set to status of Pods which are killed because their node failed.
+var nodeFailureReasons = map[string]struct{}{
+	"NodeLost":     {},
+	"NodeShutdown": {},
+	"Shutdown":     {},
+	"Terminated":   {},
+}
+
+// isNodeFailure checks whether the Pod failed or was evicted because of the failure of its node.
+func isNodeFailure(pod *v1.Pod) bool {
+	if len(pod.Spec.NodeName) == 0 {
+		return false
+	}
+	if _, found := nodeFailureReasons[pod.Status.Reason]; found {
+		return true
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.AlphaNoCompatGuaranteeDisruptionTarget && cond.Status == v1.ConditionTrue &&
+			cond.Reason == "DeletionByTaintManager" {
+			return true
+		}
+	}
+	return false
+}
+
+// failedNodeOf returns the node of the Pod if the Pod failed because of node failure.
+func failedNodeOf(pod *v1.Pod) string {
+	if isNodeFailure(pod) {
+		return pod.Spec.NodeName
+	}
+	return ""
+}
+
+// failureDomainAction returns the action defined by the failure-domain restart policy of Job.
+func failureDomainAction(job *batch.Job) (v1alpha1.Action, bool) {
+	policy, found := job.Annotations[FailureDomainRestartPolicyKey]
+	if !found {
+		return "", false
+	}
+
+	switch policy {
+	case RestartJobOnNodeFailure:
+		return v1alpha1.RestartJobAction, true
+	case RestartFailedTasksOnNodeFailure, RestartFailedTasksExcludingNodeOnNodeFailure:
+		return state.RestartFailedTasksAction, true
+	default:
+		klog.Warningf("Invalid %s=%s of Job <%s/%s>, ignore it.",
+			FailureDomainRestartPolicyKey, policy, job.Namespace, job.Name)
+		return "", false
+	}
+}
+
+// excludeFailedNode excludes the failed node from scheduling of Job if
+// required by its failure-domain restart policy.
+func (cc *jobcontroller) excludeFailedNode(job *batch.Job, req *apis.Request) error {
+	if len(req.FailedNode) == 0 || job.Annotations[FailureDomainRestartPolicyKey] != RestartFailedTasksExcludingNodeOnNodeFailure {
+		return nil
+	}
+
+	pgName := job.Name + "-" + string(job.UID)
+	pg, err := cc.pgLister.PodGroups(job.Namespace).Get(pgName)
+	if err != nil {
+		return err
+	}
+
+	excludedNodes := schedulingapi.GetExcludedNodes(pg.Annotations)
+	if _, found := excludedNodes[req.FailedNode]; found {
+		return nil
+	}
+
+	updated := pg.DeepCopy()
+	addExcludedNode(updated, req.FailedNode)
+
+	if err := cc.patchPodGroupAnnotations(pg, map[string]*string{
+		schedulingapi.ExcludedNodesAnnotation: annotationValue(updated.Annotations, schedulingapi.ExcludedNodesAnnotation),
+	}); err != nil {
+		klog.Errorf("Failed to exclude node %s from PodGroup %s/%s: %v",
+			req.FailedNode, job.Namespace, pgName, err)
+		return err
+	}
+	cc.recorder.Event(job, v1.EventTypeWarning, string(batch.ExecuteAction),
+		fmt.Sprintf("Node %s is excluded from scheduling of Job because of node failure", req.FailedNode))
+	return nil
+}
+
+// patchPodGroupAnnotations patches only the given annotations of pg, a nil value removes the annotation.
+// PodGroup has no status subresource, so updating the whole PodGroup from the lister would overwrite the
+// status written by the scheduler since; the resourceVersion of pg still fails the patch if the annotations
+// changed in the meantime.
+func (cc *jobcontroller) patchPodGroupAnnotations(pg *scheduling.PodGroup, annotations map[string]*string) error {
+	metadata := map[string]interface{}{"annotations": annotations}
+	if len(pg.ResourceVersion) != 0 {
+		metadata["resourceVersion"] = pg.ResourceVersion
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+
+	_, err = cc.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Patch(context.TODO(), pg.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// annotationValue returns the value of annotation key to patch, nil if it is not set.
+func annotationValue(annotations map[string]string, key string) *string {
+	value, found := annotations[key]
+	if !found {
+		return nil
+	}
+	return &value
+}
+
+// addExcludedNode adds node to the scheduling.volcano.sh/excluded-nodes annotation of PodGroup.
+func addExcludedNode(pg *scheduling.PodGroup, node string) {
+	if pg.Annotations == nil {
+		pg.Annotations = map[string]string{}
+	}
+	nodes := pg.Annotations[schedulingapi.ExcludedNodesAnnotation]
+	if len(nodes) == 0 {
+		nodes = node
+	} else {
+		nodes = strings.Join([]string{nodes, node}, ",")
+	}
+	pg.Annotations[schedulingapi.ExcludedNodesAnnotation] = nodes
+}
+
+// restartFailedTasks kills the failed Pods of Job, and then syncs Job to create the missing Pods again.
+// Each restart counts as a retry of Job, and Job fails once it reaches maxRetry like a restarted Job.
+func (cc *jobcontroller) restartFailedTasks(jobInfo *apis.JobInfo, updateStatus state.UpdateStatusFn) error {
+	job := jobInfo.Job
+	klog.V(3).Infof("Restarting failed tasks of Job <%s/%s>, current version %d", job.Namespace, job.Name, job.Status.Version)
+
+	var failed []*v1.Pod
+	for _, pods := range jobInfo.Pods {
+		for _, pod := range pods {
+			if pod.DeletionTimestamp == nil && pod.Status.Phase == v1.PodFailed {
+				failed = append(failed, pod)
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return cc.syncJob(jobInfo, updateStatus)
+	}
+
+	if job.Status.RetryCount >= job.Spec.MaxRetry {
+		klog.V(3).Infof("Job <%s/%s> reached the maximum number of retries %d", job.Namespace, job.Name, job.Spec.MaxRetry)
+		return cc.killJob(jobInfo, state.PodRetainPhaseSoft, func(status *batch.JobStatus) bool {
+			status.State.Phase = batch.Failed
+			return true
+		})
+	}
+
+	var errs []error
+	for _, pod := range failed {
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			errs = append(errs, err)
+			cc.resyncTask(pod)
+		}
+	}
+
+	if len(errs) != 0 {
+		klog.Errorf("failed to restart failed pods for job %s/%s, with err %+v", job.Namespace, job.Name, errs)
+		cc.recorder.Event(job, v1.EventTypeWarning, FailedDeletePodReason,
+			fmt.Sprintf("Error deleting pods: %+v", errs))
+		return fmt.Errorf("failed to restart %d failed pods", len(errs))
+	}
+
+	return cc.syncJob(jobInfo, func(status *batch.JobStatus) bool {
+		status.RetryCount++
+		return updateStatus != nil && updateStatus(status)
+	})
+}
@@ -35,6 +35,7 @@ import (
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobcache "volcano.sh/volcano/pkg/controllers/cache"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 func (cc *jobcontroller) addCommand(obj interface{}) {
@@ -97,7 +98,9 @@ func (cc *jobcontroller) updateJob(oldObj, newObj interface{}) {
 
 	// NOTE: Since we only reconcile job based on Spec, we will ignore other attributes
 	// For Job status, it's used internally and always been updated via our controller.
-	if reflect.DeepEqual(newJob.Spec, oldJob.Spec) && newJob.Status.State.Phase == oldJob.Status.State.Phase {
+	// The scheduling paused annotation is the exception as it has to be synced to PodGroup.
+	if reflect.DeepEqual(newJob.Spec, oldJob.Spec) && newJob.Status.State.Phase == oldJob.Status.State.Phase &&
+		newJob.Annotations[schedulingapi.SchedulingPausedAnnotation] == oldJob.Annotations[schedulingapi.SchedulingPausedAnnotation] {
 		klog.V(6).Infof("Job update event is ignored since no update in 'Spec'.")
 		return
 	}
@@ -132,6 +135,7 @@ func (cc *jobcontroller) deleteJob(obj interface{}) {
 		klog.Errorf("Failed to delete job <%s/%s>: %v in cache",
 			job.Namespace, job.Name, err)
 	}
+	cc.startConditionsBackoff.Forget(jobcache.JobKey(job))
 }
 
 func (cc *jobcontroller) addPod(obj interface{}) {
@@ -250,11 +254,16 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 
 	event := bus.OutOfSyncEvent
 	var exitCode int32
+	var failedNode, crashedNode string
 
 	switch newPod.Status.Phase {
 	case v1.PodFailed:
 		if oldPod.Status.Phase != v1.PodFailed {
 			event = bus.PodFailedEvent
+			failedNode = failedNodeOf(newPod)
+			if len(failedNode) == 0 {
+				crashedNode = newPod.Spec.NodeName
+			}
 			// TODO: currently only one container pod is supported by volcano
 			// Once multi containers pod is supported, update accordingly.
 			if len(newPod.Status.ContainerStatuses) > 0 && newPod.Status.ContainerStatuses[0].State.Terminated != nil {
@@ -270,6 +279,7 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 		if cc.cache.TaskFailed(jobcache.JobKeyByName(newPod.Namespace, jobName), taskName) {
 			event = bus.TaskFailedEvent
 		}
+		crashedNode = restartedNodeOf(oldPod, newPod)
 	}
 
 	req := apis.Request{
@@ -277,9 +287,11 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 		JobName:   jobName,
 		TaskName:  taskName,
 
-		Event:      event,
-		ExitCode:   exitCode,
-		JobVersion: int32(dVersion),
+		Event:       event,
+		ExitCode:    exitCode,
+		JobVersion:  int32(dVersion),
+		FailedNode:  failedNode,
+		CrashedNode: crashedNode,
 	}
 
 	key := jobhelpers.GetJobKeyByReq(&req)
@@ -303,6 +315,9 @@ func (cc *jobcontroller) deletePod(obj interface{}) {
 		}
 	}
 
+	// The Pod is forgotten before it is filtered out, so that it is never kept.
+	_, restarted := cc.restartedPods.LoadAndDelete(pod.UID)
+
 	// Filter out pods that are not created from volcano job
 	if !isControlledBy(pod, helpers.JobKind) {
 		return
@@ -336,13 +351,19 @@ func (cc *jobcontroller) deletePod(obj interface{}) {
 		return
 	}
 
+	event := bus.PodEvictedEvent
+	if restarted {
+		event = bus.OutOfSyncEvent
+	}
+
 	req := apis.Request{
 		Namespace: pod.Namespace,
 		JobName:   jobName,
 		TaskName:  taskName,
 
-		Event:      bus.PodEvictedEvent,
+		Event:      event,
 		JobVersion: int32(dVersion),
+		FailedNode: failedNodeOf(pod),
 	}
 
 	if err := cc.cache.DeletePod(pod); err != nil {
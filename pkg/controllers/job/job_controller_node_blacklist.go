@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// nodeBlacklistThreshold returns the node blacklist threshold of Job, and whether the blacklist is enabled.
+func nodeBlacklistThreshold(job *batch.Job) (int, bool) {
+	value, found := job.Annotations[NodeBlacklistThresholdKey]
+	if !found {
+		return 0, false
+	}
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold <= 0 {
+		klog.Warningf("Invalid %s=%s of Job <%s/%s>, ignore it.",
+			NodeBlacklistThresholdKey, value, job.Namespace, job.Name)
+		return 0, false
+	}
+	return threshold, true
+}
+
+// parseNodeFailureCounts parses the value of the volcano.sh/node-failure-counts annotation.
+func parseNodeFailureCounts(value string) map[string]int {
+	counts := map[string]int{}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			continue
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count <= 0 {
+			continue
+		}
+		counts[parts[0]] = count
+	}
+	return counts
+}
+
+// formatNodeFailureCounts formats the value of the volcano.sh/node-failure-counts annotation, ordered by node.
+func formatNodeFailureCounts(counts map[string]int) string {
+	nodes := make([]string, 0, len(counts))
+	for node := range counts {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	items := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		items = append(items, fmt.Sprintf("%s=%d", node, counts[node]))
+	}
+	return strings.Join(items, ",")
+}
+
+// restartedNodeOf returns the node of the Pod if a container of the Pod restarted after failing since oldPod,
+// e.g. the crash loop of a Pod with restartPolicy Always or OnFailure, whose phase never turns Failed.
+func restartedNodeOf(oldPod, newPod *v1.Pod) string {
+	if len(newPod.Spec.NodeName) == 0 {
+		return ""
+	}
+	restartCounts := map[string]int32{}
+	for _, status := range containerStatusesOf(oldPod) {
+		restartCounts[status.Name] = status.RestartCount
+	}
+	for _, status := range containerStatusesOf(newPod) {
+		if status.RestartCount <= restartCounts[status.Name] {
+			continue
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return newPod.Spec.NodeName
+		}
+	}
+	return ""
+}
+
+// containerStatusesOf returns the statuses of the init containers and containers of the Pod.
+func containerStatusesOf(pod *v1.Pod) []v1.ContainerStatus {
+	statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// restartedContainers returns whether a container of the Pod restarted.
+func restartedContainers(pod *v1.Pod) bool {
+	for _, status := range containerStatusesOf(pod) {
+		if status.RestartCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// recordCrashedNode counts the failure of the Pod of Job on the crashed node in PodGroup of Job if the node
+// blacklist of Job is enabled, and excludes the node from scheduling of Job once the threshold is reached.
+func (cc *jobcontroller) recordCrashedNode(jobInfo *apis.JobInfo, req *apis.Request) error {
+	job := jobInfo.Job
+	if len(req.CrashedNode) == 0 {
+		return nil
+	}
+	threshold, enabled := nodeBlacklistThreshold(job)
+	if !enabled {
+		return nil
+	}
+
+	pgName := job.Name + "-" + string(job.UID)
+	pg, err := cc.pgLister.PodGroups(job.Namespace).Get(pgName)
+	if err != nil {
+		return err
+	}
+
+	excludedNodes := schedulingapi.GetExcludedNodes(pg.Annotations)
+	if _, found := excludedNodes[req.CrashedNode]; found {
+		return cc.deleteCrashLoopingPods(jobInfo, req.CrashedNode)
+	}
+
+	updated := pg.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	counts := parseNodeFailureCounts(updated.Annotations[NodeFailureCountsKey])
+	counts[req.CrashedNode]++
+	blacklisted := counts[req.CrashedNode] >= threshold
+	if blacklisted {
+		delete(counts, req.CrashedNode)
+		addExcludedNode(updated, req.CrashedNode)
+	}
+	if len(counts) == 0 {
+		delete(updated.Annotations, NodeFailureCountsKey)
+	} else {
+		updated.Annotations[NodeFailureCountsKey] = formatNodeFailureCounts(counts)
+	}
+
+	if err := cc.patchPodGroupAnnotations(pg, map[string]*string{
+		NodeFailureCountsKey:                  annotationValue(updated.Annotations, NodeFailureCountsKey),
+		schedulingapi.ExcludedNodesAnnotation: annotationValue(updated.Annotations, schedulingapi.ExcludedNodesAnnotation),
+	}); err != nil {
+		klog.Errorf("Failed to record failure on node %s in PodGroup %s/%s: %v",
+			req.CrashedNode, job.Namespace, pgName, err)
+		return err
+	}
+	if blacklisted {
+		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.ExecuteAction),
+			fmt.Sprintf("Node %s is excluded from scheduling of Job because Pods of Job failed on it %d times",
+				req.CrashedNode, threshold))
+		return cc.deleteCrashLoopingPods(jobInfo, req.CrashedNode)
+	}
+	return nil
+}
+
+// deleteCrashLoopingPods deletes the running Pods of Job on the excluded node whose containers restarted, so that
+// the Pods crash looping with restartPolicy Always or OnFailure are created again and scheduled elsewhere. As the
+// Pods killed to restart their task, their deletion is not handled as an eviction.
+func (cc *jobcontroller) deleteCrashLoopingPods(jobInfo *apis.JobInfo, node string) error {
+	now := time.Now()
+	var errs []error
+	for _, pods := range jobInfo.Pods {
+		for _, pod := range pods {
+			if pod.Spec.NodeName != node || pod.DeletionTimestamp != nil ||
+				pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed || !restartedContainers(pod) {
+				continue
+			}
+			cc.restartedPods.Store(pod.UID, now)
+			if err := cc.deleteJobPod(jobInfo.Job.Name, pod); err != nil {
+				cc.restartedPods.Delete(pod.UID)
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to delete %d crash looping pods on node %s: %v", len(errs), node, errs)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestRecordCrashedNode(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name                string
+		JobAnnotations      map[string]string
+		PodGroupAnnotations map[string]string
+		CrashedNode         string
+		ExpectedCounts      string
+		ExpectedExcluded    string
+	}{
+		{
+			Name:           "blacklist disabled",
+			CrashedNode:    "node1",
+			ExpectedCounts: "",
+		},
+		{
+			Name:           "first failure on node",
+			JobAnnotations: map[string]string{NodeBlacklistThresholdKey: "2"},
+			PodGroupAnnotations: map[string]string{
+				NodeFailureCountsKey: "node2=1",
+			},
+			CrashedNode:    "node1",
+			ExpectedCounts: "node1=1,node2=1",
+		},
+		{
+			Name:           "threshold reached",
+			JobAnnotations: map[string]string{NodeBlacklistThresholdKey: "2"},
+			PodGroupAnnotations: map[string]string{
+				NodeFailureCountsKey:                  "node1=1,node2=1",
+				schedulingapi.ExcludedNodesAnnotation: "node3",
+			},
+			CrashedNode:      "node1",
+			ExpectedCounts:   "node2=1",
+			ExpectedExcluded: "node3,node1",
+		},
+		{
+			Name:           "invalid threshold",
+			JobAnnotations: map[string]string{NodeBlacklistThresholdKey: "0"},
+			CrashedNode:    "node1",
+			ExpectedCounts: "",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					UID:         "e7f18111-1cec-11ea-b688-fa163ec79500",
+					Annotations: testcase.JobAnnotations,
+				},
+			}
+			pg := &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1-e7f18111-1cec-11ea-b688-fa163ec79500",
+					Namespace:   namespace,
+					Annotations: testcase.PodGroupAnnotations,
+				},
+			}
+			// The status written by the scheduler is not in the lister yet.
+			written := pg.DeepCopy()
+			written.Status.Phase = scheduling.PodGroupRunning
+			if _, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), written, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+			fakeController.pgInformer.Informer().GetIndexer().Add(pg)
+
+			req := &apis.Request{Namespace: namespace, JobName: job.Name, CrashedNode: testcase.CrashedNode}
+			if err := fakeController.recordCrashedNode(&apis.JobInfo{Job: job}, req); err != nil {
+				t.Fatalf("failed to record crashed node: %v", err)
+			}
+
+			updated, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pg.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get podgroup: %v", err)
+			}
+			if counts := updated.Annotations[NodeFailureCountsKey]; counts != testcase.ExpectedCounts {
+				t.Errorf("expected node failure counts %q, got %q", testcase.ExpectedCounts, counts)
+			}
+			if excluded := updated.Annotations[schedulingapi.ExcludedNodesAnnotation]; len(testcase.ExpectedExcluded) != 0 && excluded != testcase.ExpectedExcluded {
+				t.Errorf("expected excluded nodes %q, got %q", testcase.ExpectedExcluded, excluded)
+			}
+			if updated.Status.Phase != scheduling.PodGroupRunning {
+				t.Errorf("expected status of podgroup kept, got phase %q", updated.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestRetryRecordCrashedNode(t *testing.T) {
+	fakeController := newFakeController()
+	fakeController.maxRequeueNum = 2
+	queue := workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(0, 0))
+	defer queue.ShutDown()
+	jobInfo := &apis.JobInfo{Job: &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test"}}}
+
+	req := apis.Request{Namespace: "test", JobName: "job1", Event: busv1alpha1.PodFailedEvent, CrashedNode: "node1"}
+	for i := 0; i < fakeController.maxRequeueNum; i++ {
+		fakeController.retryRecordCrashedNode(queue, req, jobInfo, fmt.Errorf("conflict"))
+		item, _ := queue.Get()
+		queue.Done(item)
+		req = item.(apis.Request)
+		if req.Action != busv1alpha1.SyncJobAction || req.CrashedNode != "node1" {
+			t.Fatalf("expected crashed node retried by sync of job, got %v", req)
+		}
+	}
+	// The max requeue number is reached.
+	fakeController.retryRecordCrashedNode(queue, req, jobInfo, fmt.Errorf("conflict"))
+	if length := queue.Len(); length != 0 {
+		t.Errorf("expected crashed node dropped after max retries, got %d requests", length)
+	}
+}
+
+func TestRestartedNodeOf(t *testing.T) {
+	containerStatus := func(restartCount, exitCode int32) v1.ContainerStatus {
+		status := v1.ContainerStatus{Name: "worker", RestartCount: restartCount}
+		if restartCount > 0 {
+			status.LastTerminationState.Terminated = &v1.ContainerStateTerminated{ExitCode: exitCode}
+		}
+		return status
+	}
+	podWith := func(nodeName string, status v1.ContainerStatus) *v1.Pod {
+		return &v1.Pod{
+			Spec:   v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{status}},
+		}
+	}
+
+	testcases := []struct {
+		Name     string
+		OldPod   *v1.Pod
+		NewPod   *v1.Pod
+		Expected string
+	}{
+		{
+			Name:     "container crashed and restarted",
+			OldPod:   podWith("node1", containerStatus(1, 1)),
+			NewPod:   podWith("node1", containerStatus(2, 1)),
+			Expected: "node1",
+		},
+		{
+			Name:   "container exited successfully and restarted",
+			OldPod: podWith("node1", containerStatus(0, 0)),
+			NewPod: podWith("node1", containerStatus(1, 0)),
+		},
+		{
+			Name:   "restart already seen",
+			OldPod: podWith("node1", containerStatus(2, 1)),
+			NewPod: podWith("node1", containerStatus(2, 1)),
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if node := restartedNodeOf(testcase.OldPod, testcase.NewPod); node != testcase.Expected {
+				t.Errorf("expected crashed node %q, got %q", testcase.Expected, node)
+			}
+		})
+	}
+}
+
+func TestRecordCrashedNodeDeletesCrashLoopingPods(t *testing.T) {
+	namespace := "test"
+	fakeController := newFakeController()
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1",
+			Namespace:   namespace,
+			UID:         "e7f18111-1cec-11ea-b688-fa163ec79500",
+			Annotations: map[string]string{NodeBlacklistThresholdKey: "2"},
+		},
+	}
+	pg := &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1-e7f18111-1cec-11ea-b688-fa163ec79500",
+			Namespace:   namespace,
+			Annotations: map[string]string{NodeFailureCountsKey: "node1=1"},
+		},
+	}
+	if _, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create podgroup: %v", err)
+	}
+	fakeController.pgInformer.Informer().GetIndexer().Add(pg)
+
+	newPod := func(name, nodeName string, restartCount int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "worker", RestartCount: restartCount}},
+			},
+		}
+	}
+	pods := map[string]*v1.Pod{
+		"job1-worker-0": newPod("job1-worker-0", "node1", 3),
+		"job1-worker-1": newPod("job1-worker-1", "node1", 0),
+		"job1-worker-2": newPod("job1-worker-2", "node2", 3),
+	}
+	for _, pod := range pods {
+		if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+
+	jobInfo := &apis.JobInfo{Job: job, Pods: map[string]map[string]*v1.Pod{"worker": pods}}
+	req := &apis.Request{Namespace: namespace, JobName: job.Name, CrashedNode: "node1"}
+	if err := fakeController.recordCrashedNode(jobInfo, req); err != nil {
+		t.Fatalf("failed to record crashed node: %v", err)
+	}
+
+	for name, deleted := range map[string]bool{"job1-worker-0": true, "job1-worker-1": false, "job1-worker-2": false} {
+		_, err := fakeController.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if deleted != apierrors.IsNotFound(err) {
+			t.Errorf("expected pod %s deleted %v, got error %v", name, deleted, err)
+		}
+	}
+	if _, found := fakeController.restartedPods.Load(types.UID("job1-worker-0")); !found {
+		t.Errorf("expected deletion of crash looping pod not handled as an eviction")
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+const (
+	// maxPreviousNodes is the max number of Pods whose nodes are kept in the volcano.sh/previous-nodes annotation.
+	maxPreviousNodes = 1000
+	// restartedPodTTL is how long a Pod killed to restart its task is remembered, if its deletion is not seen.
+	restartedPodTTL = 10 * time.Minute
+)
+
+// parsePreviousNodes parses the value of the volcano.sh/previous-nodes annotation.
+func parsePreviousNodes(value string) map[string]string {
+	nodes := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			continue
+		}
+		nodes[parts[0]] = parts[1]
+	}
+	return nodes
+}
+
+// formatPreviousNodes formats the value of the volcano.sh/previous-nodes annotation, ordered by Pod.
+func formatPreviousNodes(nodes map[string]string) string {
+	pods := make([]string, 0, len(nodes))
+	for pod := range nodes {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	items := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		items = append(items, fmt.Sprintf("%s=%s", pod, nodes[pod]))
+	}
+	return strings.Join(items, ",")
+}
+
+// capPreviousNodes drops Pods from previousNodes until at most maxPreviousNodes are kept, the Pods not restarted now,
+// i.e. not in nodes, first.
+func capPreviousNodes(previousNodes, nodes map[string]string) {
+	if len(previousNodes) <= maxPreviousNodes {
+		return
+	}
+	pods := make([]string, 0, len(previousNodes))
+	for pod := range previousNodes {
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		_, restartedI := nodes[pods[i]]
+		_, restartedJ := nodes[pods[j]]
+		if restartedI != restartedJ {
+			return restartedJ
+		}
+		return pods[i] < pods[j]
+	})
+	for _, pod := range pods[:len(pods)-maxPreviousNodes] {
+		delete(previousNodes, pod)
+	}
+}
+
+// restartTask records the nodes of the Pods of the task of the request in PodGroup of Job and kills the Pods, so
+// that they are created again by syncing Job and prefer the nodes they ran on, where the local data and images are.
+func (cc *jobcontroller) restartTask(jobInfo *apis.JobInfo, req *apis.Request) error {
+	job := jobInfo.Job
+	if len(req.TaskName) == 0 || job.Status.State.Phase != batch.Running {
+		return nil
+	}
+	klog.V(3).Infof("Restarting task %s of Job <%s/%s>, current version %d", req.TaskName, job.Namespace, job.Name, job.Status.Version)
+
+	var pods []*v1.Pod
+	previousNodes := map[string]string{}
+	for _, pod := range jobInfo.Pods[req.TaskName] {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+		if len(pod.Spec.NodeName) != 0 {
+			previousNodes[pod.Name] = pod.Spec.NodeName
+		}
+	}
+
+	if err := cc.recordPreviousNodes(jobInfo, previousNodes); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cc.forgetRestartedPods(now)
+	var errs []error
+	for _, pod := range pods {
+		cc.restartedPods.Store(pod.UID, now)
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			cc.restartedPods.Delete(pod.UID)
+			errs = append(errs, err)
+			cc.resyncTask(pod)
+		}
+	}
+
+	if len(errs) != 0 {
+		klog.Errorf("failed to restart pods of task %s for job %s/%s, with err %+v", req.TaskName, job.Namespace, job.Name, errs)
+		cc.recorder.Event(job, v1.EventTypeWarning, FailedDeletePodReason,
+			fmt.Sprintf("Error deleting pods: %+v", errs))
+		return fmt.Errorf("failed to restart %d pods of task %s", len(errs), req.TaskName)
+	}
+	return nil
+}
+
+// forgetRestartedPods forgets the Pods killed to restart their task before restartedPodTTL, whose deletion is never
+// seen, e.g. because they were already gone.
+func (cc *jobcontroller) forgetRestartedPods(now time.Time) {
+	cc.restartedPods.Range(func(uid, restarted interface{}) bool {
+		if now.Sub(restarted.(time.Time)) > restartedPodTTL {
+			cc.restartedPods.Delete(uid)
+		}
+		return true
+	})
+}
+
+// recordPreviousNodes merges the nodes of Pods into the volcano.sh/previous-nodes annotation of PodGroup of Job.
+// The nodes of the Pods no longer in Job are dropped, and at most maxPreviousNodes Pods are kept.
+func (cc *jobcontroller) recordPreviousNodes(jobInfo *apis.JobInfo, nodes map[string]string) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	job := jobInfo.Job
+
+	pgName := job.Name + "-" + string(job.UID)
+	pg, err := cc.pgLister.PodGroups(job.Namespace).Get(pgName)
+	if err != nil {
+		return err
+	}
+
+	jobPods := map[string]struct{}{}
+	for _, pods := range jobInfo.Pods {
+		for name := range pods {
+			jobPods[name] = struct{}{}
+		}
+	}
+	previousNodes := parsePreviousNodes(pg.Annotations[PreviousNodesKey])
+	changed := false
+	for pod := range previousNodes {
+		if _, found := jobPods[pod]; !found {
+			delete(previousNodes, pod)
+			changed = true
+		}
+	}
+	for pod, node := range nodes {
+		if previousNodes[pod] != node {
+			previousNodes[pod] = node
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	capPreviousNodes(previousNodes, nodes)
+
+	value := formatPreviousNodes(previousNodes)
+	if err := cc.patchPodGroupAnnotations(pg, map[string]*string{PreviousNodesKey: &value}); err != nil {
+		klog.Errorf("Failed to record previous nodes in PodGroup %s/%s: %v",
+			job.Namespace, pgName, err)
+		return err
+	}
+	return nil
+}
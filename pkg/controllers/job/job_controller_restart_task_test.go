@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestRestartTask(t *testing.T) {
+	namespace := "test"
+	fakeController := newFakeController()
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+			UID:       "e7f18111-1cec-11ea-b688-fa163ec79500",
+		},
+		Status: batch.JobStatus{State: batch.JobState{Phase: batch.Running}},
+	}
+	pg := &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1-e7f18111-1cec-11ea-b688-fa163ec79500",
+			Namespace:   namespace,
+			Annotations: map[string]string{PreviousNodesKey: "job1-ps-0=node9,job1-worker-5=node5"},
+		},
+	}
+	if _, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create podgroup: %v", err)
+	}
+	fakeController.pgInformer.Informer().GetIndexer().Add(pg)
+
+	jobInfo := &apis.JobInfo{Namespace: namespace, Name: job.Name, Job: job, Pods: map[string]map[string]*v1.Pod{}}
+	for _, p := range []struct{ task, name, node string }{
+		{"worker", "job1-worker-0", "node1"},
+		{"worker", "job1-worker-1", "node2"},
+		{"ps", "job1-ps-0", "node3"},
+	} {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: namespace, UID: types.UID(p.name)},
+			Spec:       v1.PodSpec{NodeName: p.node},
+		}
+		if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		if jobInfo.Pods[p.task] == nil {
+			jobInfo.Pods[p.task] = map[string]*v1.Pod{}
+		}
+		jobInfo.Pods[p.task][p.name] = pod
+	}
+
+	req := &apis.Request{Namespace: namespace, JobName: job.Name, TaskName: "worker"}
+	if err := fakeController.restartTask(jobInfo, req); err != nil {
+		t.Fatalf("failed to restart task: %v", err)
+	}
+
+	updated, err := fakeController.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pg.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get podgroup: %v", err)
+	}
+	expected := "job1-ps-0=node9,job1-worker-0=node1,job1-worker-1=node2"
+	if nodes := updated.Annotations[PreviousNodesKey]; nodes != expected {
+		t.Errorf("expected previous nodes %q, got %q", expected, nodes)
+	}
+
+	for _, name := range []string{"job1-worker-0", "job1-worker-1"} {
+		if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+			t.Errorf("expected pod %s of restarted task killed, got %v", name, err)
+		}
+		if _, found := fakeController.restartedPods.Load(types.UID(name)); !found {
+			t.Errorf("expected pod %s recorded as restarted", name)
+		}
+	}
+	if _, err := fakeController.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), "job1-ps-0", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected pod of other task kept, got %v", err)
+	}
+}
+
+func TestCapPreviousNodes(t *testing.T) {
+	previousNodes := map[string]string{}
+	nodes := map[string]string{}
+	for i := 0; i < maxPreviousNodes+10; i++ {
+		previousNodes[fmt.Sprintf("job1-ps-%04d", i)] = "node1"
+	}
+	for i := 0; i < 5; i++ {
+		pod := fmt.Sprintf("job1-worker-%d", i)
+		previousNodes[pod] = "node2"
+		nodes[pod] = "node2"
+	}
+
+	capPreviousNodes(previousNodes, nodes)
+	if len(previousNodes) != maxPreviousNodes {
+		t.Errorf("expected %d previous nodes kept, got %d", maxPreviousNodes, len(previousNodes))
+	}
+	for pod := range nodes {
+		if _, found := previousNodes[pod]; !found {
+			t.Errorf("expected node of pod %s restarted now kept", pod)
+		}
+	}
+}
+
+func TestForgetRestartedPods(t *testing.T) {
+	fakeController := newFakeController()
+	now := time.Now()
+	fakeController.restartedPods.Store(types.UID("stale"), now.Add(-restartedPodTTL-time.Second))
+	fakeController.restartedPods.Store(types.UID("recent"), now.Add(-time.Second))
+
+	fakeController.forgetRestartedPods(now)
+	if _, found := fakeController.restartedPods.Load(types.UID("stale")); found {
+		t.Errorf("expected stale restarted pod forgotten")
+	}
+	if _, found := fakeController.restartedPods.Load(types.UID("recent")); !found {
+		t.Errorf("expected recent restarted pod kept")
+	}
+}
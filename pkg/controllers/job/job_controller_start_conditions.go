@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+)
+
+const (
+	// startConditionsMinRetryPeriod is the first period to check the start conditions of Job again when they are
+	// not met, it doubles at each check up to startConditionsMaxRetryPeriod.
+	startConditionsMinRetryPeriod = 5 * time.Second
+	// startConditionsMaxRetryPeriod is the longest period to check the start conditions of Job again.
+	startConditionsMaxRetryPeriod = 5 * time.Minute
+	// startConditionsURLTimeout is the timeout of the request to a URL of the start conditions.
+	startConditionsURLTimeout = 5 * time.Second
+	// startConditionsMaxRedirects is the most redirects followed by the request to a URL of the start conditions.
+	startConditionsMaxRedirects = 3
+)
+
+// urlChecker checks that the URLs of the start conditions return 200. Only the hosts allowed by the operator
+// are requested, and a URL failing is not requested again before its backoff expires, whichever Job waits for it.
+type urlChecker struct {
+	allowedHosts []string
+	client       *http.Client
+
+	mutex sync.Mutex
+	// backoff is the delay before a failing URL is requested again.
+	backoff workqueue.RateLimiter
+	// failures keeps the last error of the failing URLs, and when they may be requested again.
+	failures map[string]urlFailure
+}
+
+type urlFailure struct {
+	err       error
+	nextCheck time.Time
+}
+
+func newURLChecker(allowedHosts []string) *urlChecker {
+	c := &urlChecker{
+		allowedHosts: allowedHosts,
+		backoff:      workqueue.NewItemExponentialFailureRateLimiter(startConditionsMinRetryPeriod, startConditionsMaxRetryPeriod),
+		failures:     map[string]urlFailure{},
+	}
+	c.client = &http.Client{
+		Timeout: startConditionsURLTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= startConditionsMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", startConditionsMaxRedirects)
+			}
+			return c.checkAllowed(req.URL)
+		},
+	}
+	return c
+}
+
+// hostAllowed returns true if host matches one of the allowed hosts, either exactly or, for the
+// allowed hosts in the form of "*.domain", as a subdomain of domain.
+func (c *urlChecker) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range c.allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix := strings.TrimPrefix(allowed, "*"); suffix != allowed {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAllowed checks that u is requested over http or https to an allowed host.
+func (c *urlChecker) checkAllowed(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if !c.hostAllowed(u.Hostname()) {
+		return fmt.Errorf("host %q is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+// check checks that rawURL returns 200.
+func (c *urlChecker) check(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q", rawURL)
+	}
+	if err := c.checkAllowed(u); err != nil {
+		return fmt.Errorf("URL %s: %v", rawURL, err)
+	}
+
+	c.mutex.Lock()
+	failure, found := c.failures[rawURL]
+	c.mutex.Unlock()
+	if found && time.Now().Before(failure.nextCheck) {
+		return failure.err
+	}
+
+	err = c.get(rawURL)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err == nil {
+		delete(c.failures, rawURL)
+		c.backoff.Forget(rawURL)
+		return nil
+	}
+	c.failures[rawURL] = urlFailure{err: err, nextCheck: time.Now().Add(c.backoff.When(rawURL))}
+	return err
+}
+
+func (c *urlChecker) get(rawURL string) error {
+	resp, err := c.client.Get(rawURL)
+	if err != nil {
+		return fmt.Errorf("URL %s is not ready: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+	// Drains a bit of the body only, to reuse the connection with no risk of reading a large response.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("URL %s returned %d", rawURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// splitList splits the comma separated value of annotation, and drops the empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getObject gets the object of kind from the listers.
+func (cc *jobcontroller) getObject(kind, namespace, name string) (bool, error) {
+	var err error
+	switch kind {
+	case "configmap", "cm":
+		_, err = cc.cmLister.ConfigMaps(namespace).Get(name)
+	case "secret":
+		_, err = cc.secretLister.Secrets(namespace).Get(name)
+	case "service":
+		_, err = cc.svcLister.Services(namespace).Get(name)
+	case "persistentvolumeclaim", "pvc":
+		_, err = cc.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+	default:
+		return false, fmt.Errorf("unsupported kind %q", kind)
+	}
+	return true, err
+}
+
+// checkObjectExists checks that the object, in the form of "kind/name", exists in namespace.
+func (cc *jobcontroller) checkObjectExists(namespace, object string) error {
+	parts := strings.SplitN(object, "/", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return fmt.Errorf("invalid object %q, expected kind/name", object)
+	}
+	supported, err := cc.getObject(strings.ToLower(parts[0]), namespace, parts[1])
+	if !supported {
+		return fmt.Errorf("unsupported kind %q of object %q", parts[0], object)
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%s does not exist", object)
+		}
+		return fmt.Errorf("failed to get %s: %v", object, err)
+	}
+	return nil
+}
+
+// unmetStartConditions returns the start conditions of Job which are not met yet.
+func (cc *jobcontroller) unmetStartConditions(job *batch.Job) []string {
+	var unmet []string
+	for _, object := range splitList(job.Annotations[WaitForObjectsKey]) {
+		if err := cc.checkObjectExists(job.Namespace, object); err != nil {
+			unmet = append(unmet, err.Error())
+		}
+	}
+	if len(unmet) != 0 {
+		// No URL is requested while Job waits for its objects anyway.
+		return unmet
+	}
+	for _, rawURL := range splitList(job.Annotations[WaitForURLsKey]) {
+		if err := cc.urlChecker.check(rawURL); err != nil {
+			unmet = append(unmet, err.Error())
+		}
+	}
+	return unmet
+}
+
+// waitForStartConditions checks the start conditions of Job, and returns true if Job has to wait for them.
+// Job waiting is synced again after a backoff, instead of being requeued as a failure, so it may wait as
+// long as needed without reaching the max requeue number.
+func (cc *jobcontroller) waitForStartConditions(job *batch.Job) bool {
+	key := jobcache.JobKey(job)
+	unmet := cc.unmetStartConditions(job)
+	if len(unmet) == 0 {
+		cc.startConditionsBackoff.Forget(key)
+		return false
+	}
+
+	message := fmt.Sprintf("Waiting for start conditions: %s", strings.Join(unmet, "; "))
+	klog.V(3).Infof("Job <%s/%s>: %s", job.Namespace, job.Name, message)
+	cc.recorder.Event(job, v1.EventTypeNormal, StartConditionsNotMetReason, message)
+
+	req := apis.Request{
+		Namespace: job.Namespace,
+		JobName:   job.Name,
+		Event:     v1alpha1.OutOfSyncEvent,
+	}
+	cc.getWorkerQueue(jobcache.JobKeyByReq(&req)).AddAfter(req, cc.startConditionsBackoff.When(key))
+	return true
+}
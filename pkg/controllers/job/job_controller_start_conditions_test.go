@@ -0,0 +1,228 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestUnmetStartConditions(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name        string
+		Annotations map[string]string
+		UnmetNum    int
+	}{
+		{
+			Name:     "no start conditions",
+			UnmetNum: 0,
+		},
+		{
+			Name: "all start conditions met",
+			Annotations: map[string]string{
+				WaitForObjectsKey: "configmap/input, cm/input",
+			},
+			UnmetNum: 0,
+		},
+		{
+			Name: "missing object",
+			Annotations: map[string]string{
+				WaitForObjectsKey: "configmap/input,secret/token",
+			},
+			UnmetNum: 1,
+		},
+		{
+			Name: "invalid objects",
+			Annotations: map[string]string{
+				WaitForObjectsKey: "input,deployment/loader",
+			},
+			UnmetNum: 2,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+			cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "input", Namespace: namespace}}
+			fakeController.cmInformer.Informer().GetIndexer().Add(cm)
+
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					Annotations: testcase.Annotations,
+				},
+			}
+			unmet := fakeController.unmetStartConditions(job)
+			if len(unmet) != testcase.UnmetNum {
+				t.Errorf("expected %d unmet start conditions, got %v", testcase.UnmetNum, unmet)
+			}
+		})
+	}
+}
+
+func TestWaitForStartConditionsBackoff(t *testing.T) {
+	namespace := "test"
+	fakeController := newFakeController()
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1",
+			Namespace:   namespace,
+			Annotations: map[string]string{WaitForObjectsKey: "configmap/input"},
+		},
+	}
+	key := namespace + "/" + job.Name
+
+	for i := 0; i < 2; i++ {
+		if !fakeController.waitForStartConditions(job) {
+			t.Fatalf("expected job to wait for configmap/input")
+		}
+	}
+	if requeues := fakeController.startConditionsBackoff.NumRequeues(key); requeues != 2 {
+		t.Errorf("expected 2 checks backed off, got %d", requeues)
+	}
+
+	cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "input", Namespace: namespace}}
+	fakeController.cmInformer.Informer().GetIndexer().Add(cm)
+	if fakeController.waitForStartConditions(job) {
+		t.Fatalf("expected job not to wait once configmap/input exists")
+	}
+	if requeues := fakeController.startConditionsBackoff.NumRequeues(key); requeues != 0 {
+		t.Errorf("expected backoff reset once start conditions are met, got %d", requeues)
+	}
+}
+
+func TestURLStartConditions(t *testing.T) {
+	namespace := "test"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ready":
+			w.WriteHeader(http.StatusOK)
+		case "/redirect":
+			// localhost is not allowed, only 127.0.0.1 is.
+			http.Redirect(w, r, strings.Replace("http://"+r.Host, "127.0.0.1", "localhost", 1)+"/ready", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		Name        string
+		Annotations map[string]string
+		UnmetNum    int
+	}{
+		{
+			Name:        "ready URL",
+			Annotations: map[string]string{WaitForURLsKey: server.URL + "/ready"},
+			UnmetNum:    0,
+		},
+		{
+			Name:        "URL not ready",
+			Annotations: map[string]string{WaitForURLsKey: server.URL + "/ready," + server.URL + "/loading"},
+			UnmetNum:    1,
+		},
+		{
+			Name:        "host not allowed",
+			Annotations: map[string]string{WaitForURLsKey: strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/ready"},
+			UnmetNum:    1,
+		},
+		{
+			Name:        "redirect to host not allowed",
+			Annotations: map[string]string{WaitForURLsKey: server.URL + "/redirect"},
+			UnmetNum:    1,
+		},
+		{
+			Name:        "unsupported scheme",
+			Annotations: map[string]string{WaitForURLsKey: "file:///etc/passwd"},
+			UnmetNum:    1,
+		},
+		{
+			Name: "URLs not requested while objects are missing",
+			Annotations: map[string]string{
+				WaitForObjectsKey: "configmap/input",
+				WaitForURLsKey:    server.URL + "/loading",
+			},
+			UnmetNum: 1,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			fakeController := newFakeController()
+			fakeController.urlChecker = newURLChecker([]string{"127.0.0.1"})
+
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					Annotations: testcase.Annotations,
+				},
+			}
+			unmet := fakeController.unmetStartConditions(job)
+			if len(unmet) != testcase.UnmetNum {
+				t.Errorf("expected %d unmet start conditions, got %v", testcase.UnmetNum, unmet)
+			}
+		})
+	}
+}
+
+func TestURLCheckerBackoff(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	checker := newURLChecker([]string{"127.0.0.1"})
+	for i := 0; i < 3; i++ {
+		if err := checker.check(server.URL); err == nil {
+			t.Fatalf("expected URL returning 503 not to be met")
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected failing URL requested once before its backoff expires, got %d requests", got)
+	}
+}
+
+func TestURLCheckerHostAllowed(t *testing.T) {
+	checker := newURLChecker([]string{"feature-store.default.svc", "*.example.com"})
+	for host, allowed := range map[string]bool{
+		"feature-store.default.svc": true,
+		"FEATURE-STORE.default.svc": true,
+		"data.example.com":          true,
+		"example.com":               false,
+		"badexample.com":            false,
+		"metadata.google.internal":  false,
+	} {
+		if got := checker.hostAllowed(host); got != allowed {
+			t.Errorf("host %s: expected allowed %v, got %v", host, allowed, got)
+		}
+	}
+}
@@ -30,6 +30,7 @@ import (
 	schedulingv2 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 // MakePodName append podname,jobname,taskName and index and returns the string.
@@ -58,6 +59,12 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 		pod.Spec.SchedulerName = job.Spec.SchedulerName
 	}
 
+	// If no priority class in Pod, inherit it from Job, so kubelet eviction and other schedulers
+	// see the same priority as Volcano uses to order the job.
+	if len(pod.Spec.PriorityClassName) == 0 {
+		pod.Spec.PriorityClassName = job.Spec.PriorityClassName
+	}
+
 	volumeMap := make(map[string]string)
 	for _, volume := range job.Spec.Volumes {
 		vcName := volume.VolumeClaimName
@@ -119,6 +126,12 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 			pod.Annotations[schedulingv2.RevocableZone] = value
 		}
 
+		for _, key := range []string{schedulingapi.NodeArchAnnotation, schedulingapi.NodeFeaturesAnnotation} {
+			if value, found := job.Annotations[key]; found {
+				pod.Annotations[key] = value
+			}
+		}
+
 		if value, found := job.Annotations[schedulingv2.JDBMinAvailable]; found {
 			pod.Annotations[schedulingv2.JDBMinAvailable] = value
 		} else if value, found := job.Annotations[schedulingv2.JDBMaxUnavailable]; found {
@@ -149,9 +162,44 @@ func createJobPod(job *batch.Job, template *v1.PodTemplateSpec, topologyPolicy b
 		pod.Labels[batch.JobForwardingKey] = "true"
 	}
 
+	addNodeFeatureAffinity(pod)
+
 	return pod
 }
 
+// addNodeFeatureAffinity requires the architecture and node features of the annotations of Pod in
+// the node affinity of Pod, so they are also respected by other schedulers.
+func addNodeFeatureAffinity(pod *v1.Pod) {
+	features, err := schedulingapi.ParseNodeFeatures(pod.Annotations)
+	if err != nil {
+		klog.Warningf("Failed to parse node features of Pod <%s/%s>: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	if len(features) == 0 {
+		return
+	}
+	requirements := schedulingapi.NodeFeatureRequirements(features)
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	nodeAffinity := pod.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{}
+	}
+	selector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []v1.NodeSelectorTerm{{}}
+	}
+	// Terms are ORed, so the requirements are added to every term.
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirements...)
+	}
+}
+
 func applyPolicies(job *batch.Job, req *apis.Request) v1alpha1.Action {
 	if len(req.Action) != 0 {
 		return req.Action
@@ -167,6 +215,13 @@ func applyPolicies(job *batch.Job, req *apis.Request) v1alpha1.Action {
 		return v1alpha1.SyncJobAction
 	}
 
+	// Failure-domain restart policy takes precedence over lifecycle policies for node failures
+	if len(req.FailedNode) != 0 {
+		if action, found := failureDomainAction(job); found {
+			return action
+		}
+	}
+
 	// Overwrite Job level policies
 	if len(req.TaskName) != 0 {
 		// Parse task level policies
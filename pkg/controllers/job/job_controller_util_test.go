@@ -17,6 +17,7 @@ limitations under the License.
 package job
 
 import (
+	"reflect"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -25,6 +26,8 @@ import (
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/job/state"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 func TestMakePodName(t *testing.T) {
@@ -248,6 +251,98 @@ func TestCreateJobPod(t *testing.T) {
 	}
 }
 
+func TestCreateJobPodPriorityClass(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test"},
+		Spec:       v1alpha1.JobSpec{PriorityClassName: "high"},
+	}
+
+	testcases := []struct {
+		Name      string
+		Template  *v1.PodTemplateSpec
+		ReturnVal string
+	}{
+		{
+			Name:      "inherit priority class of job",
+			Template:  &v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Name: "task1"}},
+			ReturnVal: "high",
+		},
+		{
+			Name: "keep priority class of task",
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: "task1"},
+				Spec:       v1.PodSpec{PriorityClassName: "low"},
+			},
+			ReturnVal: "low",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			pod := createJobPod(job, testcase.Template, "", 0, false)
+			if pod.Spec.PriorityClassName != testcase.ReturnVal {
+				t.Errorf("Expected priority class %s, but got %s", testcase.ReturnVal, pod.Spec.PriorityClassName)
+			}
+		})
+	}
+}
+
+func TestCreateJobPodNodeFeatures(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test", Annotations: map[string]string{
+			schedulingapi.NodeArchAnnotation:     "arm64",
+			schedulingapi.NodeFeaturesAnnotation: "cpu-cpuid.AVX512F",
+		}},
+	}
+	zone := v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}
+	arch := v1.NodeSelectorRequirement{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{"arm64"}}
+	avx := v1.NodeSelectorRequirement{Key: "feature.node.kubernetes.io/cpu-cpuid.AVX512F", Operator: v1.NodeSelectorOpExists}
+
+	testcases := []struct {
+		Name     string
+		Template *v1.PodTemplateSpec
+		Terms    []v1.NodeSelectorTerm
+	}{
+		{
+			Name:     "add node affinity",
+			Template: &v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Name: "task1"}},
+			Terms:    []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{arch, avx}}},
+		},
+		{
+			Name: "add requirements to every term",
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: "task1"},
+				Spec: v1.PodSpec{Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{MatchExpressions: []v1.NodeSelectorRequirement{zone}},
+						{MatchFields: []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"n1"}}}},
+					}},
+				}}},
+			},
+			Terms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{zone, arch, avx}},
+				{
+					MatchExpressions: []v1.NodeSelectorRequirement{arch, avx},
+					MatchFields:      []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"n1"}}},
+				},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			pod := createJobPod(job, testcase.Template, "", 0, false)
+			if pod.Annotations[schedulingapi.NodeArchAnnotation] != "arm64" {
+				t.Errorf("Expected annotations of job copied to pod, but got %v", pod.Annotations)
+			}
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			if !reflect.DeepEqual(terms, testcase.Terms) {
+				t.Errorf("Expected node selector terms %v, but got %v", testcase.Terms, terms)
+			}
+		})
+	}
+}
+
 func TestApplyPolicies(t *testing.T) {
 	namespace := "test"
 	errorCode0 := int32(0)
@@ -564,6 +659,66 @@ func TestApplyPolicies(t *testing.T) {
 			Request:   &apis.Request{},
 			ReturnVal: busv1alpha1.SyncJobAction,
 		},
+		{
+			Name: "Test Apply policies with failure-domain restart policy on node failure",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					Annotations: map[string]string{FailureDomainRestartPolicyKey: RestartFailedTasksExcludingNodeOnNodeFailure},
+				},
+				Spec: v1alpha1.JobSpec{
+					Policies: []v1alpha1.LifecyclePolicy{
+						{
+							Action: busv1alpha1.RestartJobAction,
+							Event:  busv1alpha1.PodFailedEvent,
+						},
+					},
+				},
+			},
+			Request: &apis.Request{
+				Event:      busv1alpha1.PodFailedEvent,
+				FailedNode: "node1",
+			},
+			ReturnVal: state.RestartFailedTasksAction,
+		},
+		{
+			Name: "Test Apply policies with failure-domain restart policy without node failure",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					Annotations: map[string]string{FailureDomainRestartPolicyKey: RestartFailedTasksOnNodeFailure},
+				},
+				Spec: v1alpha1.JobSpec{
+					Policies: []v1alpha1.LifecyclePolicy{
+						{
+							Action: busv1alpha1.RestartJobAction,
+							Event:  busv1alpha1.PodFailedEvent,
+						},
+					},
+				},
+			},
+			Request: &apis.Request{
+				Event: busv1alpha1.PodFailedEvent,
+			},
+			ReturnVal: busv1alpha1.RestartJobAction,
+		},
+		{
+			Name: "Test Apply policies with invalid failure-domain restart policy",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					Annotations: map[string]string{FailureDomainRestartPolicyKey: "invalid"},
+				},
+			},
+			Request: &apis.Request{
+				Event:      busv1alpha1.PodEvictedEvent,
+				FailedNode: "node1",
+			},
+			ReturnVal: busv1alpha1.SyncJobAction,
+		},
 	}
 
 	for i, testcase := range testcases {
@@ -682,3 +837,60 @@ func TestTasksPriority_Swap(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNodeFailure(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Pod      *v1.Pod
+		Expected bool
+	}{
+		{
+			Name: "pod not bound to node",
+			Pod: &v1.Pod{
+				Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "NodeLost"},
+			},
+			Expected: false,
+		},
+		{
+			Name: "pod failed because of node lost",
+			Pod: &v1.Pod{
+				Spec:   v1.PodSpec{NodeName: "node1"},
+				Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "NodeLost"},
+			},
+			Expected: true,
+		},
+		{
+			Name: "pod evicted by taint manager",
+			Pod: &v1.Pod{
+				Spec: v1.PodSpec{NodeName: "node1"},
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					Conditions: []v1.PodCondition{
+						{
+							Type:   v1.AlphaNoCompatGuaranteeDisruptionTarget,
+							Status: v1.ConditionTrue,
+							Reason: "DeletionByTaintManager",
+						},
+					},
+				},
+			},
+			Expected: true,
+		},
+		{
+			Name: "pod failed because of application error",
+			Pod: &v1.Pod{
+				Spec:   v1.PodSpec{NodeName: "node1"},
+				Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Error"},
+			},
+			Expected: false,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if got := isNodeFailure(testcase.Pod); got != testcase.Expected {
+				t.Errorf("Expected %v but got %v", testcase.Expected, got)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	schedulingapi "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
@@ -1385,6 +1386,126 @@ func TestRunningState_Execute(t *testing.T) {
 	}
 }
 
+func TestRunningState_MinSuccess(t *testing.T) {
+	namespace := "test"
+	var minSuccess int32 = 2
+
+	testcases := []struct {
+		Name          string
+		Annotations   map[string]string
+		Pods          map[string]*v1.Pod
+		Status        v1alpha1.JobStatus
+		ExpectedPhase v1alpha1.JobPhase
+	}{
+		{
+			Name: "kill running pods once minSuccess pods succeeded",
+			Pods: map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "pod1", v1.PodSucceeded, nil),
+				"job1-task1-1": buildPod(namespace, "pod2", v1.PodSucceeded, nil),
+				"job1-task1-2": buildPod(namespace, "pod3", v1.PodRunning, nil),
+			},
+			Status:        v1alpha1.JobStatus{Succeeded: 2, Running: 1},
+			ExpectedPhase: v1alpha1.Completing,
+		},
+		{
+			Name:        "wait for running pods once minSuccess pods succeeded",
+			Annotations: map[string]string{jobhelpers.MinSuccessPolicyKey: jobhelpers.MinSuccessPolicyWait},
+			Pods: map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "pod1", v1.PodSucceeded, nil),
+				"job1-task1-1": buildPod(namespace, "pod2", v1.PodSucceeded, nil),
+				"job1-task1-2": buildPod(namespace, "pod3", v1.PodRunning, nil),
+			},
+			Status:        v1alpha1.JobStatus{Succeeded: 2, Running: 1},
+			ExpectedPhase: v1alpha1.Running,
+		},
+		{
+			Name:        "complete with failed pods once minSuccess pods succeeded",
+			Annotations: map[string]string{jobhelpers.MinSuccessPolicyKey: jobhelpers.MinSuccessPolicyWait},
+			Pods: map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "pod1", v1.PodSucceeded, nil),
+				"job1-task1-1": buildPod(namespace, "pod2", v1.PodSucceeded, nil),
+				"job1-task1-2": buildPod(namespace, "pod3", v1.PodFailed, nil),
+			},
+			Status:        v1alpha1.JobStatus{Succeeded: 2, Failed: 1},
+			ExpectedPhase: v1alpha1.Completed,
+		},
+		{
+			Name: "fail when pods finished without minSuccess pods succeeded",
+			Pods: map[string]*v1.Pod{
+				"job1-task1-0": buildPod(namespace, "pod1", v1.PodSucceeded, nil),
+				"job1-task1-1": buildPod(namespace, "pod2", v1.PodFailed, nil),
+				"job1-task1-2": buildPod(namespace, "pod3", v1.PodFailed, nil),
+			},
+			Status:        v1alpha1.JobStatus{Succeeded: 1, Failed: 2},
+			ExpectedPhase: v1alpha1.Failed,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "job1",
+					Namespace:       namespace,
+					ResourceVersion: "100",
+					Annotations:     testcase.Annotations,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					MinSuccess:   &minSuccess,
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task1",
+							Replicas: 3,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "task1",
+								},
+							},
+						},
+					},
+				},
+				Status: testcase.Status,
+			}
+			job.Status.State.Phase = v1alpha1.Running
+			jobInfo := &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "job1",
+				Job:       job,
+				Pods:      map[string]map[string]*v1.Pod{"task1": testcase.Pods},
+			}
+			testState := state.NewState(jobInfo)
+
+			fakecontroller := newFakeController()
+			state.KillJob = fakecontroller.killJob
+
+			patches := gomonkey.ApplyMethod(reflect.TypeOf(fakecontroller), "GetQueueInfo", func(_ *jobcontroller, _ string) (*schedulingapi.Queue, error) {
+				return &schedulingapi.Queue{}, nil
+			})
+			defer patches.Reset()
+
+			if _, err := fakecontroller.vcClient.BatchV1alpha1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Error("Error while creating Job")
+			}
+			if err := fakecontroller.cache.Add(job); err != nil {
+				t.Error("Error while adding Job in cache")
+			}
+
+			if err := testState.Execute(busv1alpha1.SyncJobAction); err != nil {
+				t.Errorf("Expected Error not to occur but got: %s", err)
+			}
+
+			updated, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", namespace, job.Name))
+			if err != nil {
+				t.Error("Error while retrieving value from Cache")
+			}
+			if updated.Job.Status.State.Phase != testcase.ExpectedPhase {
+				t.Errorf("Expected Job phase to %s, but got %s", testcase.ExpectedPhase, updated.Job.Status.State.Phase)
+			}
+		})
+	}
+}
+
 func TestTerminatingState_Execute(t *testing.T) {
 	namespace := "test"
 
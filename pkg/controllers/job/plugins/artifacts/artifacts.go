@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifacts
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+// sidecarScript runs the command of the artifacts container in sidecar mode, and terminates it once the other
+// containers of the pod exited, so that the pod completes. The pod shares its process namespace, in which the
+// first process of each container has no parent but pause, the process 1.
+const sidecarScript = `/bin/sh -c "$` + EnvArtifactsCommand + `" &
+child=$!
+trap 'kill -TERM $child 2>/dev/null' TERM
+containers() {
+  count=0
+  for dir in %[1]s/[0-9]*; do
+    pid=${dir##*/}
+    if [ "$pid" = 1 ] || [ "$pid" = $$ ] || [ ! -r "$dir/status" ]; then
+      continue
+    fi
+    while read -r key value rest; do
+      if [ "$key" = "PPid:" ]; then
+        [ "$value" = 0 ] && count=$((count + 1))
+        break
+      fi
+    done < "$dir/status"
+  done
+  echo $count
+}
+while kill -0 $child 2>/dev/null; do
+  if [ "$(containers)" = 0 ]; then
+    kill -TERM $child 2>/dev/null
+    wait $child
+    exit 0
+  fi
+  sleep %[2]d
+done
+wait $child
+`
+
+var (
+	// procDir is the proc filesystem the sidecar script looks for the other containers in.
+	procDir = "/proc"
+	// sidecarPollSeconds is the period, in seconds, the sidecar script checks the other containers.
+	sidecarPollSeconds = 5
+)
+
+type artifactsPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments []string
+
+	Clientset pluginsinterface.PluginClientset
+
+	// flag parse args
+	image       string
+	command     string
+	mode        string
+	mountPath   string
+	volumeClaim string
+	tasks       string
+	port        int
+}
+
+// New creates artifacts plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	artifactsPlugin := artifactsPlugin{
+		pluginArguments: arguments,
+		Clientset:       client,
+		mode:            CompanionMode,
+		mountPath:       DefaultMountPath,
+	}
+
+	artifactsPlugin.addFlags()
+
+	return &artifactsPlugin
+}
+
+func (ap *artifactsPlugin) Name() string {
+	return "artifacts"
+}
+
+func (ap *artifactsPlugin) addFlags() {
+	flagSet := flag.NewFlagSet(ap.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&ap.image, "image", ap.image, "image of the artifacts container, e.g. tensorboard")
+	flagSet.StringVar(&ap.command, "command", ap.command, "command of the artifacts container run by /bin/sh -c, "+
+		"it is required by sidecar mode; the entrypoint of the image is run if it is empty in companion mode")
+	flagSet.StringVar(&ap.mode, "mode", ap.mode, "sidecar to add the artifacts container to the pods of the job, "+
+		"or companion to create one artifacts pod for the job")
+	flagSet.StringVar(&ap.mountPath, "mount-path", ap.mountPath, "path the artifacts volume is mounted to")
+	flagSet.StringVar(&ap.volumeClaim, "volume-claim", ap.volumeClaim, "PersistentVolumeClaim of the artifacts volume, "+
+		"it is required by companion mode; an emptyDir of the pod is used in sidecar mode if it is empty")
+	flagSet.StringVar(&ap.tasks, "tasks", ap.tasks, "comma separated tasks whose pods the artifacts container "+
+		"is added to in sidecar mode, all tasks if it is empty")
+	flagSet.IntVar(&ap.port, "port", ap.port, "port exposed by the artifacts container, e.g. 6006 of tensorboard")
+
+	if err := flagSet.Parse(ap.pluginArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", ap.Name(), err)
+	}
+}
+
+func (ap *artifactsPlugin) validate() error {
+	if len(ap.image) == 0 {
+		return fmt.Errorf("plugin %s requires --image", ap.Name())
+	}
+	switch ap.mode {
+	case SidecarMode:
+		if len(ap.command) == 0 {
+			return fmt.Errorf("plugin %s requires --command in %s mode", ap.Name(), SidecarMode)
+		}
+	case CompanionMode:
+		if len(ap.volumeClaim) == 0 {
+			return fmt.Errorf("plugin %s requires --volume-claim in %s mode", ap.Name(), CompanionMode)
+		}
+	default:
+		return fmt.Errorf("plugin %s got invalid mode %s", ap.Name(), ap.mode)
+	}
+	return nil
+}
+
+func (ap *artifactsPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	if err := ap.validate(); err != nil {
+		return err
+	}
+
+	ap.addVolume(&pod.Spec)
+	vm := v1.VolumeMount{Name: VolumeName, MountPath: ap.mountPath}
+	for i, c := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
+	}
+	for i, c := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].VolumeMounts = append(c.VolumeMounts, vm)
+	}
+
+	if ap.mode == SidecarMode && ap.injectsInto(pod.Annotations[batch.TaskSpecKey]) {
+		pod.Spec.Containers = append(pod.Spec.Containers, ap.sidecarContainer(job))
+		shareProcessNamespace := true
+		pod.Spec.ShareProcessNamespace = &shareProcessNamespace
+	}
+
+	return nil
+}
+
+func (ap *artifactsPlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+ap.Name()] == ap.Name() {
+		return nil
+	}
+
+	if err := ap.validate(); err != nil {
+		return err
+	}
+	if ap.mode == CompanionMode {
+		if err := ap.createCompanionIfNotExist(job); err != nil {
+			return err
+		}
+	}
+
+	job.Status.ControlledResources["plugin-"+ap.Name()] = ap.Name()
+
+	return nil
+}
+
+// OnJobDelete deletes the companion pod when the job is finished, it is kept when the job is restarted
+// so that the artifacts of all the runs are served.
+func (ap *artifactsPlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+ap.Name()] != ap.Name() {
+		return nil
+	}
+	if !isJobFinished(job) {
+		return nil
+	}
+
+	if ap.mode == CompanionMode {
+		if err := ap.Clientset.KubeClients.CoreV1().Pods(job.Namespace).Delete(context.TODO(), ap.companionName(job), metav1.DeleteOptions{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to delete artifacts pod of Job %v/%v: %v", job.Namespace, job.Name, err)
+				return err
+			}
+		}
+	}
+	delete(job.Status.ControlledResources, "plugin-"+ap.Name())
+
+	return nil
+}
+
+// OnJobUpdate recreates the companion pod if it is gone, e.g. evicted.
+func (ap *artifactsPlugin) OnJobUpdate(job *batch.Job) error {
+	if ap.mode != CompanionMode || job.Status.ControlledResources["plugin-"+ap.Name()] != ap.Name() {
+		return nil
+	}
+	if err := ap.validate(); err != nil {
+		return err
+	}
+	return ap.createCompanionIfNotExist(job)
+}
+
+func (ap *artifactsPlugin) injectsInto(taskName string) bool {
+	if len(ap.tasks) == 0 {
+		return true
+	}
+	for _, task := range strings.Split(ap.tasks, ",") {
+		if strings.TrimSpace(task) == taskName {
+			return true
+		}
+	}
+	return false
+}
+
+func (ap *artifactsPlugin) addVolume(spec *v1.PodSpec) {
+	volume := v1.Volume{Name: VolumeName}
+	if len(ap.volumeClaim) != 0 {
+		volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{ClaimName: ap.volumeClaim}
+	} else {
+		volume.EmptyDir = &v1.EmptyDirVolumeSource{}
+	}
+	spec.Volumes = append(spec.Volumes, volume)
+}
+
+func (ap *artifactsPlugin) container(job *batch.Job) v1.Container {
+	container := v1.Container{
+		Name:  ContainerName,
+		Image: ap.image,
+		Env: []v1.EnvVar{
+			{Name: EnvArtifactsDir, Value: ap.mountPath},
+			{Name: EnvJobName, Value: job.Name},
+			{Name: EnvJobNamespace, Value: job.Namespace},
+		},
+		VolumeMounts: []v1.VolumeMount{{Name: VolumeName, MountPath: ap.mountPath}},
+	}
+	if len(ap.command) != 0 {
+		container.Command = []string{"/bin/sh", "-c", ap.command}
+	}
+	if ap.port > 0 {
+		container.Ports = []v1.ContainerPort{{Name: ContainerName, ContainerPort: int32(ap.port)}}
+	}
+	return container
+}
+
+// sidecarContainer returns the artifacts container exiting with the other containers of the pod.
+func (ap *artifactsPlugin) sidecarContainer(job *batch.Job) v1.Container {
+	container := ap.container(job)
+	container.Env = append(container.Env, v1.EnvVar{Name: EnvArtifactsCommand, Value: ap.command})
+	container.Command = []string{"/bin/sh", "-c", fmt.Sprintf(sidecarScript, procDir, sidecarPollSeconds)}
+	return container
+}
+
+func (ap *artifactsPlugin) createCompanionIfNotExist(job *batch.Job) error {
+	name := ap.companionName(job)
+	// The pod is read from the cache, the create of a pod missing from a stale cache only returns AlreadyExists.
+	if _, err := ap.Clientset.PodLister.Pods(job.Namespace).Get(name); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		klog.V(3).Infof("Failed to get artifacts pod for Job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return err
+	}
+
+	// The pod has no annotations of job pods, so it is not managed as a pod of the job, but deleted with the job.
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: job.Namespace,
+			Name:      name,
+			Labels:    map[string]string{JobLabelKey: job.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, helpers.JobKind),
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers:    []v1.Container{ap.container(job)},
+			RestartPolicy: v1.RestartPolicyAlways,
+		},
+	}
+	ap.addVolume(&pod.Spec)
+
+	if _, err := ap.Clientset.KubeClients.CoreV1().Pods(job.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.V(3).Infof("Failed to create artifacts pod for Job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return err
+	}
+	return nil
+}
+
+func (ap *artifactsPlugin) companionName(job *batch.Job) string {
+	return fmt.Sprintf("%s-%s", job.Name, ap.Name())
+}
+
+func isJobFinished(job *batch.Job) bool {
+	switch job.Status.State.Phase {
+	case batch.Completing, batch.Completed, batch.Terminating, batch.Terminated,
+		batch.Failed, batch.Aborting, batch.Aborted:
+		return true
+	}
+	return false
+}
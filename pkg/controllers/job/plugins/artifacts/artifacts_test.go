@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifacts
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func newTestJob() *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns1", UID: "job1-uid"},
+		Status:     batch.JobStatus{ControlledResources: map[string]string{}},
+	}
+}
+
+func newTestPod(task string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job1-" + task + "-0",
+			Annotations: map[string]string{batch.TaskSpecKey: task},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+	}
+}
+
+func TestOnPodCreateSidecar(t *testing.T) {
+	params := []string{"--mode=sidecar", "--image=uploader", "--command=upload.sh", "--tasks=worker", "--mount-path=/logs"}
+	plugin := New(pluginsinterface.PluginClientset{}, params)
+	job := newTestJob()
+
+	worker := newTestPod("worker")
+	if err := plugin.OnPodCreate(worker, job); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	if len(worker.Spec.Containers) != 2 {
+		t.Fatalf("expected artifacts container in pod of task worker, got %d containers", len(worker.Spec.Containers))
+	}
+	sidecar := worker.Spec.Containers[1]
+	if sidecar.Image != "uploader" || sidecar.Env[len(sidecar.Env)-1].Value != "upload.sh" {
+		t.Errorf("unexpected artifacts container %v", sidecar)
+	}
+	if share := worker.Spec.ShareProcessNamespace; share == nil || !*share {
+		t.Errorf("expected pod of task worker to share its process namespace")
+	}
+	if worker.Spec.Volumes[0].EmptyDir == nil {
+		t.Errorf("expected emptyDir artifacts volume, got %v", worker.Spec.Volumes[0])
+	}
+	if got := worker.Spec.Containers[0].VolumeMounts[0].MountPath; got != "/logs" {
+		t.Errorf("expected artifacts volume mounted to /logs, got %s", got)
+	}
+
+	ps := newTestPod("ps")
+	if err := plugin.OnPodCreate(ps, job); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	if len(ps.Spec.Containers) != 1 {
+		t.Errorf("expected no artifacts container in pod of task ps, got %d containers", len(ps.Spec.Containers))
+	}
+}
+
+func TestCompanionLifecycle(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	params := []string{"--image=tensorboard", "--volume-claim=logs", "--port=6006"}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	plugin := New(pluginsinterface.PluginClientset{KubeClients: kubeClient, PodLister: corelisters.NewPodLister(podIndexer)}, params)
+	job := newTestJob()
+
+	// syncPods syncs the pods of the client to the lister, as the informer would.
+	syncPods := func() {
+		pods, err := kubeClient.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("failed to list pods: %v", err)
+		}
+		var objs []interface{}
+		for i := range pods.Items {
+			objs = append(objs, &pods.Items[i])
+		}
+		podIndexer.Replace(objs, "")
+	}
+
+	companionExists := func() bool {
+		_, err := kubeClient.CoreV1().Pods(job.Namespace).Get(context.TODO(), "job1-artifacts", metav1.GetOptions{})
+		return err == nil
+	}
+
+	if err := plugin.OnJobAdd(job); err != nil {
+		t.Fatalf("failed to add job: %v", err)
+	}
+	pod, err := kubeClient.CoreV1().Pods(job.Namespace).Get(context.TODO(), "job1-artifacts", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get companion pod: %v", err)
+	}
+	if claim := pod.Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != "logs" {
+		t.Errorf("expected companion pod to mount claim logs, got %v", pod.Spec.Volumes[0])
+	}
+	if _, found := pod.Annotations[batch.JobNameKey]; found {
+		t.Errorf("expected companion pod not to be managed as a pod of the job")
+	}
+
+	// The companion pod in the cache is not requested again
+	syncPods()
+	kubeClient.ClearActions()
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	if actions := kubeClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no request for the companion pod in the cache, got %v", actions)
+	}
+
+	// The companion pod is recreated if it is gone
+	if err := kubeClient.CoreV1().Pods(job.Namespace).Delete(context.TODO(), "job1-artifacts", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete companion pod: %v", err)
+	}
+	syncPods()
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	if !companionExists() {
+		t.Errorf("expected companion pod to be recreated")
+	}
+
+	// The companion pod is kept when the job is restarted, and deleted when it is finished
+	job.Status.State.Phase = batch.Restarting
+	if err := plugin.OnJobDelete(job); err != nil {
+		t.Fatalf("failed to delete job: %v", err)
+	}
+	if !companionExists() {
+		t.Errorf("expected companion pod to be kept when the job is restarted")
+	}
+	job.Status.State.Phase = batch.Completing
+	if err := plugin.OnJobDelete(job); err != nil {
+		t.Fatalf("failed to delete job: %v", err)
+	}
+	if companionExists() {
+		t.Errorf("expected companion pod to be deleted when the job is finished")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []string
+		wantErr bool
+	}{
+		{name: "no image", params: []string{"--mode=sidecar"}, wantErr: true},
+		{name: "companion without claim", params: []string{"--image=tensorboard"}, wantErr: true},
+		{name: "invalid mode", params: []string{"--image=tensorboard", "--mode=daemon"}, wantErr: true},
+		{name: "sidecar without command", params: []string{"--image=uploader", "--mode=sidecar"}, wantErr: true},
+		{name: "sidecar", params: []string{"--image=uploader", "--mode=sidecar", "--command=upload.sh"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin := New(pluginsinterface.PluginClientset{}, test.params).(*artifactsPlugin)
+			if err := plugin.validate(); (err != nil) != test.wantErr {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSidecarExitsWithPod(t *testing.T) {
+	// The proc filesystem of the pod, with pause and the process of the job container.
+	proc := t.TempDir()
+	for pid, status := range map[string]string{"1": "Name:\tpause\nPPid:\t0\n", "7": "Name:\tpython\nPPid:\t0\n"} {
+		if err := os.MkdirAll(filepath.Join(proc, pid), 0755); err != nil {
+			t.Fatalf("failed to create proc of %s: %v", pid, err)
+		}
+		if err := os.WriteFile(filepath.Join(proc, pid, "status"), []byte(status), 0644); err != nil {
+			t.Fatalf("failed to write status of %s: %v", pid, err)
+		}
+	}
+	oldProcDir, oldPollSeconds := procDir, sidecarPollSeconds
+	procDir, sidecarPollSeconds = proc, 1
+	defer func() { procDir, sidecarPollSeconds = oldProcDir, oldPollSeconds }()
+
+	artifacts := t.TempDir()
+	params := []string{"--mode=sidecar", "--image=uploader", "--mount-path=" + artifacts,
+		"--command=trap 'touch $VC_ARTIFACTS_DIR/uploaded; exit 0' TERM; while true; do sleep 1; done"}
+	plugin := New(pluginsinterface.PluginClientset{}, params)
+	pod := newTestPod("worker")
+	if err := plugin.OnPodCreate(pod, newTestJob()); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+
+	sidecar := pod.Spec.Containers[1]
+	cmd := exec.Command(sidecar.Command[0], sidecar.Command[1:]...)
+	for _, env := range sidecar.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start artifacts container: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		t.Fatalf("expected artifacts container to run with the job container, exited: %v", err)
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	// The job container exits, the artifacts container follows so that the pod, and the job, completes.
+	if err := os.RemoveAll(filepath.Join(proc, "7")); err != nil {
+		t.Fatalf("failed to remove proc of job container: %v", err)
+	}
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Errorf("expected artifacts container to succeed, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		t.Fatalf("expected artifacts container to exit with the job container")
+	}
+	if _, err := os.Stat(filepath.Join(artifacts, "uploaded")); err != nil {
+		t.Errorf("expected artifacts command terminated gracefully: %v", err)
+	}
+}
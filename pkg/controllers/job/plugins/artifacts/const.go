@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifacts
+
+const (
+	// SidecarMode injects the artifacts container into the pods of the job
+	SidecarMode = "sidecar"
+	// CompanionMode creates one artifacts pod for the job
+	CompanionMode = "companion"
+
+	// ContainerName is the name of the artifacts container
+	ContainerName = "artifacts"
+	// VolumeName is the name of the volume shared by the job containers and the artifacts container
+	VolumeName = "volcano-artifacts"
+	// DefaultMountPath is the default path the artifacts volume is mounted to
+	DefaultMountPath = "/artifacts"
+
+	// JobLabelKey is the label of the companion pod with the job name, the pod is not managed as a pod of the job
+	JobLabelKey = "volcano.sh/artifacts-job"
+
+	// EnvArtifactsDir is the env of the path of the artifacts volume
+	EnvArtifactsDir = "VC_ARTIFACTS_DIR"
+	// EnvJobName is the env of the job name in the artifacts container
+	EnvJobName = "VC_JOB_NAME"
+	// EnvJobNamespace is the env of the job namespace in the artifacts container
+	EnvJobNamespace = "VC_JOB_NAMESPACE"
+	// EnvArtifactsCommand is the env of the command run by the artifacts container in sidecar mode
+	EnvArtifactsCommand = "VC_ARTIFACTS_COMMAND"
+)
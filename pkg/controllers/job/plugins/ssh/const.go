@@ -34,4 +34,7 @@ const (
 
 	// SSHRelativePath ssh rel path
 	SSHRelativePath = ".ssh"
+
+	// SSHKeyVersionAnnotation is the annotation of the secret recording the job version its keys are generated for
+	SSHKeyVersionAnnotation = "volcano.sh/ssh-key-version"
 )
@@ -17,15 +17,20 @@ limitations under the License.
 package ssh
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"flag"
 	"fmt"
+	"path"
+	"strconv"
 
 	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -42,6 +47,12 @@ type sshPlugin struct {
 
 	// flag parse args
 	sshKeyFilePath string
+	// sshKeyFilePathSet is whether the ssh-key-file-path is given, otherwise the keys are mounted
+	// under the HOME of each container if it is set
+	sshKeyFilePathSet bool
+
+	// rotateKeys is whether to generate new keys when the job is restarted
+	rotateKeys bool
 
 	// private key string
 	sshPrivateKey string
@@ -111,19 +122,46 @@ func (sp *sshPlugin) OnJobDelete(job *batch.Job) error {
 	return nil
 }
 
-// TODO: currently a container using a Secret as a subPath volume mount will not receive Secret updates.
-// we may not update the job secret due to the above reason now.
-// related issue: https://github.com/volcano-sh/volcano/issues/1420
+// A container using a Secret as a subPath volume mount does not receive Secret updates, see
+// https://github.com/volcano-sh/volcano/issues/1420, so the keys are only rotated when the job is restarted,
+// i.e. when its version is bumped and all its pods are recreated.
 func (sp *sshPlugin) OnJobUpdate(job *batch.Job) error {
-	//data, err := generateRsaKey(job)
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//if err := helpers.CreateOrUpdateSecret(job, sp.client.KubeClients, data, sp.secretName(job)); err != nil {
-	//	return fmt.Errorf("update secret for job <%s/%s> with ssh plugin failed for %v",
-	//		job.Namespace, job.Name, err)
-	//}
+	if !sp.rotateKeys || len(sp.sshPrivateKey) > 0 {
+		return nil
+	}
+
+	secretName := sp.secretName(job)
+	secret, err := sp.client.SecretLister.Secrets(job.Namespace).Get(secretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Keys of secrets without the annotation are generated when the job is added, i.e. for version 0
+	version := strconv.Itoa(int(job.Status.Version))
+	if recorded, found := secret.Annotations[SSHKeyVersionAnnotation]; recorded == version || !found && job.Status.Version == 0 {
+		return nil
+	}
+
+	data, err := generateRsaKey(job)
+	if err != nil {
+		return err
+	}
+	secret = secret.DeepCopy()
+	secret.Data = data
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[SSHKeyVersionAnnotation] = version
+	// The update of a secret read from a stale cache, e.g. just after its keys were rotated, conflicts and
+	// is retried once the cache is synced, so the keys are not rotated twice for a version.
+	if _, err := sp.client.KubeClients.CoreV1().Secrets(job.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("rotate keys of secret for job <%s/%s> with ssh plugin failed for %v",
+			job.Namespace, job.Name, err)
+	}
+	klog.V(3).Infof("Rotated ssh keys of job <%s/%s> for version %s", job.Namespace, job.Name, version)
 
 	return nil
 }
@@ -159,31 +197,71 @@ func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *batch.Job) {
 		DefaultMode: &mode,
 	}
 
-	if sp.sshKeyFilePath != SSHAbsolutePath {
-		var noRootMode int32 = 0644
-		sshVolume.Secret.DefaultMode = &noRootMode
-	}
-
-	pod.Spec.Volumes = append(pod.Spec.Volumes, sshVolume)
-
+	nonRoot := false
 	for i, c := range pod.Spec.Containers {
 		vm := v1.VolumeMount{
-			MountPath: sp.sshKeyFilePath,
+			MountPath: sp.sshKeyPath(&c),
 			SubPath:   SSHRelativePath,
 			Name:      secretName,
 		}
+		nonRoot = nonRoot || vm.MountPath != SSHAbsolutePath || runsAsNonRoot(pod, &c)
 
 		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
 	}
 	for i, c := range pod.Spec.InitContainers {
 		vm := v1.VolumeMount{
-			MountPath: sp.sshKeyFilePath,
+			MountPath: sp.sshKeyPath(&c),
 			SubPath:   SSHRelativePath,
 			Name:      secretName,
 		}
+		nonRoot = nonRoot || vm.MountPath != SSHAbsolutePath || runsAsNonRoot(pod, &c)
 
 		pod.Spec.InitContainers[i].VolumeMounts = append(c.VolumeMounts, vm)
 	}
+
+	// The keys are owned by root, so they must be readable by others for users other than root. ssh does not
+	// check the mode of keys owned by other users.
+	if nonRoot {
+		var noRootMode int32 = 0644
+		sshVolume.Secret.DefaultMode = &noRootMode
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, sshVolume)
+}
+
+// sshKeyPath returns the path the keys are mounted to in the container: the ssh-key-file-path if it is given,
+// or the .ssh directory under the HOME of the container if it is set.
+func (sp *sshPlugin) sshKeyPath(c *v1.Container) string {
+	if sp.sshKeyFilePathSet {
+		return sp.sshKeyFilePath
+	}
+	for _, env := range c.Env {
+		if env.Name == "HOME" && len(env.Value) != 0 {
+			return path.Join(env.Value, SSHRelativePath)
+		}
+	}
+	return sp.sshKeyFilePath
+}
+
+// runsAsNonRoot returns whether the container runs as a user other than root.
+func runsAsNonRoot(pod *v1.Pod, c *v1.Container) bool {
+	var uid *int64
+	var nonRoot *bool
+	if psc := pod.Spec.SecurityContext; psc != nil {
+		uid, nonRoot = psc.RunAsUser, psc.RunAsNonRoot
+	}
+	if csc := c.SecurityContext; csc != nil {
+		if csc.RunAsUser != nil {
+			uid = csc.RunAsUser
+		}
+		if csc.RunAsNonRoot != nil {
+			nonRoot = csc.RunAsNonRoot
+		}
+	}
+	if uid != nil {
+		return *uid != 0
+	}
+	return nonRoot != nil && *nonRoot
 }
 
 func generateRsaKey(job *batch.Job) (map[string][]byte, error) {
@@ -239,10 +317,17 @@ func (sp *sshPlugin) addFlags() {
 		"ssh private and public keys, it is `/root/.ssh` by default.")
 	flagSet.StringVar(&sp.sshPrivateKey, "ssh-private-key", sp.sshPrivateKey, "The input string of the private key")
 	flagSet.StringVar(&sp.sshPublicKey, "ssh-public-key", sp.sshPublicKey, "The input string of the public key")
+	flagSet.BoolVar(&sp.rotateKeys, "rotate-keys", sp.rotateKeys, "Generate new keys when the job is restarted, "+
+		"it is false by default and ignored if the keys are given.")
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
 	}
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == "ssh-key-file-path" {
+			sp.sshKeyFilePathSet = true
+		}
+	})
 }
 
 func generateSSHConfig(job *batch.Job) string {
@@ -17,8 +17,17 @@ limitations under the License.
 package ssh
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
@@ -71,3 +80,127 @@ func TestSSHPlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestMountRsaKey(t *testing.T) {
+	var uid int64 = 1000
+	var fsGroup int64 = 2000
+
+	tests := []struct {
+		name       string
+		params     []string
+		podSC      *v1.PodSecurityContext
+		container  v1.Container
+		mountPath  string
+		secretMode int32
+	}{
+		{
+			name:       "root",
+			container:  v1.Container{Name: "c"},
+			mountPath:  SSHAbsolutePath,
+			secretMode: 0600,
+		},
+		{
+			name:       "ssh-key-file-path is given",
+			params:     []string{"--ssh-key-file-path=/a/b"},
+			container:  v1.Container{Name: "c", Env: []v1.EnvVar{{Name: "HOME", Value: "/home/user"}}},
+			mountPath:  "/a/b",
+			secretMode: 0644,
+		},
+		{
+			name:       "home of container",
+			container:  v1.Container{Name: "c", Env: []v1.EnvVar{{Name: "HOME", Value: "/home/user"}}},
+			mountPath:  "/home/user/.ssh",
+			secretMode: 0644,
+		},
+		{
+			name:       "non-root user of container",
+			container:  v1.Container{Name: "c", SecurityContext: &v1.SecurityContext{RunAsUser: &uid}},
+			mountPath:  SSHAbsolutePath,
+			secretMode: 0644,
+		},
+		{
+			name:       "non-root user with fsGroup",
+			podSC:      &v1.PodSecurityContext{RunAsUser: &uid, FSGroup: &fsGroup},
+			container:  v1.Container{Name: "c", Env: []v1.EnvVar{{Name: "HOME", Value: "/home/user"}}},
+			mountPath:  "/home/user/.ssh",
+			secretMode: 0644,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin := New(pluginsinterface.PluginClientset{}, test.params).(*sshPlugin)
+			job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns1"}}
+			pod := &v1.Pod{Spec: v1.PodSpec{SecurityContext: test.podSC, Containers: []v1.Container{test.container}}}
+
+			plugin.mountRsaKey(pod, job)
+
+			if got := pod.Spec.Containers[0].VolumeMounts[0].MountPath; got != test.mountPath {
+				t.Errorf("expected mount path %s, got %s", test.mountPath, got)
+			}
+			if got := *pod.Spec.Volumes[0].Secret.DefaultMode; got != test.secretMode {
+				t.Errorf("expected secret mode %o, got %o", test.secretMode, got)
+			}
+		})
+	}
+}
+
+func TestOnJobUpdateRotatesKeys(t *testing.T) {
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns1", UID: "job1-uid"},
+		Status:     batch.JobStatus{ControlledResources: map[string]string{}},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clientset := pluginsinterface.PluginClientset{KubeClients: kubeClient, SecretLister: corelisters.NewSecretLister(secretIndexer)}
+	plugin := New(clientset, []string{"--rotate-keys=true"}).(*sshPlugin)
+
+	// getSecret gets the secret from the client, and syncs it to the lister as the informer would.
+	getSecret := func() *v1.Secret {
+		secret, err := kubeClient.CoreV1().Secrets(job.Namespace).Get(context.TODO(), plugin.secretName(job), metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get secret: %v", err)
+		}
+		secretIndexer.Add(secret)
+		return secret
+	}
+
+	if err := plugin.OnJobAdd(job); err != nil {
+		t.Fatalf("failed to add job: %v", err)
+	}
+	added := getSecret()
+
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	if !reflect.DeepEqual(getSecret().Data, added.Data) {
+		t.Errorf("expected keys not to be rotated before the job is restarted")
+	}
+
+	job.Status.Version = 1
+	noRotation := New(clientset, nil).(*sshPlugin)
+	if err := noRotation.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	if !reflect.DeepEqual(getSecret().Data, added.Data) {
+		t.Errorf("expected keys not to be rotated by default")
+	}
+
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	rotated := getSecret()
+	if reflect.DeepEqual(rotated.Data[SSHPrivateKey], added.Data[SSHPrivateKey]) {
+		t.Errorf("expected keys to be rotated after the job is restarted")
+	}
+	if got := rotated.Annotations[SSHKeyVersionAnnotation]; got != "1" {
+		t.Errorf("expected key version 1, got %s", got)
+	}
+
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	if !reflect.DeepEqual(getSecret().Data, rotated.Data) {
+		t.Errorf("expected keys to be rotated once per restart")
+	}
+}
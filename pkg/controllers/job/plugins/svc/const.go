@@ -26,4 +26,11 @@ const (
 
 	// ConfigMapMountPath mount path
 	ConfigMapMountPath = "/etc/volcano"
+
+	// ConfigMapTaskNetworkHostsFmt key in config map for the addresses of the pods of a task on a secondary network
+	ConfigMapTaskNetworkHostsFmt = "%s.%s.hosts"
+	// NetworkStatusAnnotation is the annotation in which Multus reports the network attachments of the pod
+	NetworkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+	// DeprecatedNetworkStatusAnnotation is the annotation in which older Multus reports the network attachments
+	DeprecatedNetworkStatusAnnotation = "k8s.v1.cni.cncf.io/networks-status"
 )
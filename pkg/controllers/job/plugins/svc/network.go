@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// invalidKeyChars are the characters not allowed in config map keys.
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// networkStatus is a network attachment of the pod reported by Multus.
+type networkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+func (sp *servicePlugin) listJobPods(job *batch.Job) ([]*v1.Pod, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		batch.JobNameKey:      job.Name,
+		batch.JobNamespaceKey: job.Namespace,
+	})
+	podList, err := sp.Clientset.PodLister.Pods(job.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("Failed to list pods of Job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return nil, err
+	}
+
+	pods := make([]*v1.Pod, 0, len(podList))
+	for _, pod := range podList {
+		if metav1.IsControlledBy(pod, job) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func podNetworkStatus(pod *v1.Pod) []networkStatus {
+	value, found := pod.Annotations[NetworkStatusAnnotation]
+	if !found {
+		value, found = pod.Annotations[DeprecatedNetworkStatusAnnotation]
+	}
+	if !found {
+		return nil
+	}
+
+	var status []networkStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		klog.Warningf("Failed to parse network status of pod <%s/%s>: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	return status
+}
+
+// networkKey returns the name of the network in config map keys, i.e. the name of the NetworkAttachmentDefinition
+// without its namespace.
+func networkKey(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return invalidKeyChars.ReplaceAllString(name, "-")
+}
+
+// GenerateNetworkHosts generates hosts files per task and secondary network, each line of which maps an address of
+// a pod on the network to the domain name of the pod. The default network is left to DNS.
+func GenerateNetworkHosts(job *batch.Job, pods []*v1.Pod) map[string]string {
+	sorted := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		sorted = append(sorted, pod)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].Annotations[batch.TaskSpecKey], sorted[j].Annotations[batch.TaskSpecKey]
+		if ti != tj {
+			return ti < tj
+		}
+		ii, _ := strconv.Atoi(jobhelpers.GetPodIndexUnderTask(sorted[i]))
+		ij, _ := strconv.Atoi(jobhelpers.GetPodIndexUnderTask(sorted[j]))
+		return ii < ij
+	})
+
+	lines := map[string][]string{}
+	for _, pod := range sorted {
+		domain := pod.Spec.Hostname
+		if len(pod.Spec.Subdomain) != 0 {
+			domain = domain + "." + pod.Spec.Subdomain
+		}
+		formateENVKey := strings.Replace(pod.Annotations[batch.TaskSpecKey], "-", "_", -1)
+		for _, status := range podNetworkStatus(pod) {
+			if status.Default || len(status.Name) == 0 {
+				continue
+			}
+			key := fmt.Sprintf(ConfigMapTaskNetworkHostsFmt, formateENVKey, networkKey(status.Name))
+			for _, ip := range status.IPs {
+				lines[key] = append(lines[key], ip+" "+domain)
+			}
+		}
+	}
+
+	hostFile := make(map[string]string, len(lines))
+	for key, l := range lines {
+		hostFile[key] = strings.Join(l, "\n")
+	}
+	return hostFile
+}
@@ -44,6 +44,8 @@ type servicePlugin struct {
 	// flag parse args
 	publishNotReadyAddresses bool
 	disableNetworkPolicy     bool
+	ipFamilyPolicy           string
+	publishNetworkAddresses  bool
 }
 
 // New creates service plugin.
@@ -65,10 +67,21 @@ func (sp *servicePlugin) addFlags() {
 		"set publishNotReadyAddresses of svc to true")
 	flagSet.BoolVar(&sp.disableNetworkPolicy, "disable-network-policy", sp.disableNetworkPolicy,
 		"set disableNetworkPolicy of svc to true")
+	flagSet.StringVar(&sp.ipFamilyPolicy, "ip-family-policy", sp.ipFamilyPolicy,
+		"set ipFamilyPolicy of svc, e.g. PreferDualStack to publish both IPv4 and IPv6 records of pods")
+	flagSet.BoolVar(&sp.publishNetworkAddresses, "publish-network-addresses", sp.publishNetworkAddresses,
+		"publish the addresses of pods on secondary networks attached by Multus into the hosts config map")
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
 	}
+
+	switch v1.IPFamilyPolicy(sp.ipFamilyPolicy) {
+	case "", v1.IPFamilyPolicySingleStack, v1.IPFamilyPolicyPreferDualStack, v1.IPFamilyPolicyRequireDualStack:
+	default:
+		klog.Errorf("plugin %s got invalid ip-family-policy %s, use the default of cluster", sp.Name(), sp.ipFamilyPolicy)
+		sp.ipFamilyPolicy = ""
+	}
 }
 
 func (sp *servicePlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
@@ -181,6 +194,16 @@ func (sp *servicePlugin) OnJobDelete(job *batch.Job) error {
 func (sp *servicePlugin) OnJobUpdate(job *batch.Job) error {
 	hostFile := GenerateHosts(job)
 
+	if sp.publishNetworkAddresses {
+		pods, err := sp.listJobPods(job)
+		if err != nil {
+			return err
+		}
+		for key, hosts := range GenerateNetworkHosts(job, pods) {
+			hostFile[key] = hosts
+		}
+	}
+
 	// updates ConfigMap of hosts for Pods to mount.
 	return helpers.CreateOrUpdateConfigMap(job, sp.Clientset.KubeClients, hostFile, sp.cmName(job))
 }
@@ -236,6 +259,10 @@ func (sp *servicePlugin) createServiceIfNotExist(job *batch.Job) error {
 				PublishNotReadyAddresses: sp.publishNotReadyAddresses,
 			},
 		}
+		if len(sp.ipFamilyPolicy) != 0 {
+			policy := v1.IPFamilyPolicy(sp.ipFamilyPolicy)
+			svc.Spec.IPFamilyPolicy = &policy
+		}
 
 		if _, e := sp.Clientset.KubeClients.CoreV1().Services(job.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{}); e != nil {
 			klog.V(3).Infof("Failed to create Service for Job <%s/%s>: %v", job.Namespace, job.Name, e)
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func newTestJob() *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns1", UID: "job1-uid"},
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{
+				{Name: "worker", Replicas: 2},
+			},
+		},
+		Status: batch.JobStatus{ControlledResources: map[string]string{}},
+	}
+}
+
+func newTestPod(job *batch.Job, name string, networkStatus string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: job.Namespace,
+			Labels: map[string]string{
+				batch.JobNameKey:      job.Name,
+				batch.JobNamespaceKey: job.Namespace,
+			},
+			Annotations: map[string]string{
+				batch.TaskSpecKey: "worker",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, helpers.JobKind),
+			},
+		},
+		Spec: v1.PodSpec{Hostname: name, Subdomain: job.Name},
+	}
+	if len(networkStatus) != 0 {
+		pod.Annotations[NetworkStatusAnnotation] = networkStatus
+	}
+	return pod
+}
+
+func TestServiceIPFamilyPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		want   *v1.IPFamilyPolicy
+	}{
+		{
+			name: "default of cluster",
+		},
+		{
+			name:   "prefer dual stack",
+			params: []string{"--ip-family-policy=PreferDualStack"},
+			want:   func() *v1.IPFamilyPolicy { p := v1.IPFamilyPolicyPreferDualStack; return &p }(),
+		},
+		{
+			name:   "invalid policy",
+			params: []string{"--ip-family-policy=DualStack"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			plugin := New(pluginsinterface.PluginClientset{KubeClients: kubeClient}, test.params).(*servicePlugin)
+			job := newTestJob()
+			if err := plugin.createServiceIfNotExist(job); err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+
+			svc, err := kubeClient.CoreV1().Services(job.Namespace).Get(context.TODO(), job.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get service: %v", err)
+			}
+			if !reflect.DeepEqual(svc.Spec.IPFamilyPolicy, test.want) {
+				t.Errorf("expected ipFamilyPolicy %v, got %v", test.want, svc.Spec.IPFamilyPolicy)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkHosts(t *testing.T) {
+	job := newTestJob()
+	status0 := `[{"name":"kindnet","ips":["10.244.0.5"],"default":true},` +
+		`{"name":"ns1/rdma-net","interface":"net1","ips":["192.168.1.10","fd00::10"]}]`
+	status1 := `[{"name":"kindnet","ips":["10.244.0.6"],"default":true},` +
+		`{"name":"ns1/rdma-net","interface":"net1","ips":["192.168.1.11"]}]`
+
+	finished := newTestPod(job, "job1-worker-2", `[{"name":"ns1/rdma-net","ips":["192.168.1.12"]}]`)
+	finished.Status.Phase = v1.PodSucceeded
+
+	pods := []*v1.Pod{
+		newTestPod(job, "job1-worker-1", status1),
+		newTestPod(job, "job1-worker-0", status0),
+		newTestPod(job, "job1-worker-3", ""),
+		finished,
+	}
+
+	got := GenerateNetworkHosts(job, pods)
+	want := map[string]string{
+		"worker.rdma-net.hosts": "192.168.1.10 job1-worker-0.job1\nfd00::10 job1-worker-0.job1\n192.168.1.11 job1-worker-1.job1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hosts %v, got %v", want, got)
+	}
+}
+
+func TestOnJobUpdatePublishNetworkAddresses(t *testing.T) {
+	job := newTestJob()
+	other := newTestJob()
+	other.UID = "other-uid"
+	kubeClient := fake.NewSimpleClientset()
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range []*v1.Pod{
+		newTestPod(job, "job1-worker-0", `[{"name":"ns1/rdma-net","ips":["192.168.1.10"]}]`),
+		// a pod of a former job of the same name is ignored
+		newTestPod(other, "job1-worker-1", `[{"name":"ns1/rdma-net","ips":["192.168.1.99"]}]`),
+	} {
+		podIndexer.Add(pod)
+	}
+	clientset := pluginsinterface.PluginClientset{KubeClients: kubeClient, PodLister: corelisters.NewPodLister(podIndexer)}
+	plugin := New(clientset, []string{"--publish-network-addresses"}).(*servicePlugin)
+
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("failed to update job: %v", err)
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(job.Namespace).Get(context.TODO(), plugin.cmName(job), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get config map: %v", err)
+	}
+	if got, want := cm.Data["worker.rdma-net.hosts"], "192.168.1.10 job1-worker-0.job1"; got != want {
+		t.Errorf("expected network hosts %q, got %q", want, got)
+	}
+	if got, want := cm.Data["worker.host"], "job1-worker-0.job1\njob1-worker-1.job1"; got != want {
+		t.Errorf("expected hosts %q, got %q", want, got)
+	}
+}
@@ -45,11 +45,17 @@ var PodRetainPhaseSoft = PhaseMap{
 	v1.PodFailed:    {},
 }
 
+// RestartFailedTasksAction restarts only the failed Pods of Job, which is
+// taken when Pods of Job fail because of node failure.
+const RestartFailedTasksAction v1alpha1.Action = "RestartFailedTasks"
+
 var (
 	// SyncJob will create or delete Pods according to Job's spec.
 	SyncJob ActionFn
 	// KillJob kill all Pods of Job with phase not in podRetainPhase.
 	KillJob KillActionFn
+	// RestartFailedTasks kill the failed Pods of Job and create them again.
+	RestartFailedTasks ActionFn
 )
 
 // State interface.
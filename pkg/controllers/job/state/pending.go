@@ -35,6 +35,9 @@ func (ps *pendingState) Execute(action v1alpha1.Action) error {
 			return true
 		})
 
+	case RestartFailedTasksAction:
+		return RestartFailedTasks(ps.job, nil)
+
 	case v1alpha1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
 			status.State.Phase = vcbatch.Aborting
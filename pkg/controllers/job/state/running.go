@@ -22,6 +22,7 @@ import (
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 type runningState struct {
@@ -36,6 +37,8 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 			status.RetryCount++
 			return true
 		})
+	case RestartFailedTasksAction:
+		return RestartFailedTasks(ps.job, nil)
 	case v1alpha1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vcbatch.JobStatus) bool {
 			status.State.Phase = vcbatch.Aborting
@@ -61,12 +64,33 @@ func (ps *runningState) Execute(action v1alpha1.Action) error {
 
 			minSuccess := ps.job.Job.Spec.MinSuccess
 			if minSuccess != nil && status.Succeeded >= *minSuccess {
+				// The other "alive" pods are killed in Completing phase, unless the
+				// policy is to wait for them to finish.
+				if status.Pending != 0 || status.Running != 0 || status.Terminating != 0 {
+					if jobhelpers.GetMinSuccessPolicy(ps.job.Job) == jobhelpers.MinSuccessPolicyWait {
+						return false
+					}
+					status.State.Phase = vcbatch.Completing
+					return true
+				}
 				status.State.Phase = vcbatch.Completed
 				return true
 			}
 
 			totalTaskMinAvailable := TotalTaskMinAvailable(ps.job.Job)
-			if status.Succeeded+status.Failed == jobReplicas {
+			finished := status.Succeeded+status.Failed == jobReplicas
+			completions := jobhelpers.GetTaskCompletions(ps.job.Job)
+			if len(completions) != 0 {
+				finished = TasksFinished(ps.job.Job, status, completions)
+			}
+			if finished {
+				for _, task := range ps.job.Job.Spec.Tasks {
+					if count, found := completions[task.Name]; found && status.TaskStatusCount[task.Name].Phase[v1.PodSucceeded] < count {
+						status.State.Phase = vcbatch.Failed
+						return true
+					}
+				}
+
 				if ps.job.Job.Spec.MinAvailable >= totalTaskMinAvailable {
 					for _, task := range ps.job.Job.Spec.Tasks {
 						if task.MinAvailable == nil {
@@ -17,7 +17,10 @@ limitations under the License.
 package state
 
 import (
+	v1 "k8s.io/api/core/v1"
+
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 // TotalTasks returns number of tasks in a given volcano job.
@@ -45,3 +48,28 @@ func TotalTaskMinAvailable(job *vcbatch.Job) int32 {
 
 	return rep
 }
+
+// TasksFinished returns whether all tasks of job are finished, with the completions of tasks taken into account:
+// a task with completions is finished when it reaches completions, or when it failed too many times and none of
+// its pods is active, while the other tasks are finished when all of their pods succeeded or failed.
+func TasksFinished(job *vcbatch.Job, status *vcbatch.JobStatus, completions map[string]int32) bool {
+	for _, task := range job.Spec.Tasks {
+		phases := status.TaskStatusCount[task.Name].Phase
+		succeeded, failed := phases[v1.PodSucceeded], phases[v1.PodFailed]
+		count, found := completions[task.Name]
+		if !found {
+			if succeeded+failed != task.Replicas {
+				return false
+			}
+			continue
+		}
+		if succeeded >= count {
+			continue
+		}
+		active := phases[v1.PodPending] + phases[v1.PodRunning] + phases[v1.PodUnknown]
+		if active != 0 || !jobhelpers.IsCompletionsExhausted(task, failed) {
+			return false
+		}
+	}
+	return true
+}
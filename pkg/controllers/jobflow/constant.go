@@ -23,4 +23,12 @@ const (
 	JobFlow = "JobFlow"
 	// CreatedByJobTemplate the vcjob annotation of created by jobTemplate
 	CreatedByJobTemplate = "volcano.sh/createdByJobTemplate"
+	// InheritDependencyContext the jobFlow annotation which makes a job inherit the queue and
+	// priority class of the jobs it depends on if its template does not set them
+	InheritDependencyContext = "volcano.sh/inherit-dependency-context"
+	// DependencyCompletedPriorityClass the jobFlow annotation of the priority class set to a job
+	// with dependencies, once all its dependencies completed
+	DependencyCompletedPriorityClass = "volcano.sh/dependency-completed-priority-class"
+	// InheritedFrom the vcjob annotation of the job which its queue and priority class are inherited from
+	InheritedFrom = "volcano.sh/inherited-from"
 )
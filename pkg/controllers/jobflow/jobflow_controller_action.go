@@ -130,6 +130,9 @@ func (jf *jobflowcontroller) createJob(jobFlow *v1alpha1flow.JobFlow, flow v1alp
 	if err := jf.loadJobTemplateAndSetJob(jobFlow, flow.Name, getJobName(jobFlow.Name, flow.Name), job); err != nil {
 		return err
 	}
+	if err := jf.inheritDependencyContext(jobFlow, flow, job); err != nil {
+		return err
+	}
 	if _, err := jf.vcClient.BatchV1alpha1().Jobs(jobFlow.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
 		if errors.IsAlreadyExists(err) {
 			return nil
@@ -284,6 +287,50 @@ func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobF
 	return controllerutil.SetControllerReference(jobFlow, job, scheme.Scheme)
 }
 
+// inheritDependencyContext sets the queue and priority class of the job from the jobs it depends on
+// as required by the annotations of jobFlow. The job is created once all its dependencies completed,
+// so the priority class for completed dependencies is always applied to a job with dependencies.
+func (jf *jobflowcontroller) inheritDependencyContext(jobFlow *v1alpha1flow.JobFlow, flow v1alpha1flow.Flow, job *v1alpha1.Job) error {
+	if flow.DependsOn == nil || len(flow.DependsOn.Targets) == 0 {
+		return nil
+	}
+
+	if jobFlow.Annotations[InheritDependencyContext] == "true" {
+		var inheritedFrom []string
+		for _, targetName := range flow.DependsOn.Targets {
+			target, err := jf.jobLister.Jobs(jobFlow.Namespace).Get(getJobName(jobFlow.Name, targetName))
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+
+			inherited := false
+			if len(job.Spec.Queue) == 0 && len(target.Spec.Queue) != 0 {
+				job.Spec.Queue = target.Spec.Queue
+				inherited = true
+			}
+			if len(job.Spec.PriorityClassName) == 0 && len(target.Spec.PriorityClassName) != 0 {
+				job.Spec.PriorityClassName = target.Spec.PriorityClassName
+				inherited = true
+			}
+			if inherited {
+				inheritedFrom = append(inheritedFrom, target.Name)
+			}
+		}
+		if len(inheritedFrom) != 0 {
+			job.Annotations[InheritedFrom] = strings.Join(inheritedFrom, ",")
+		}
+	}
+
+	if priorityClass := jobFlow.Annotations[DependencyCompletedPriorityClass]; len(priorityClass) != 0 {
+		job.Spec.PriorityClassName = priorityClass
+	}
+
+	return nil
+}
+
 func (jf *jobflowcontroller) deleteAllJobsCreatedByJobFlow(jobFlow *v1alpha1flow.JobFlow) error {
 	selector := labels.NewSelector()
 	jobList, err := jf.jobLister.Jobs(jobFlow.Namespace).List(selector)
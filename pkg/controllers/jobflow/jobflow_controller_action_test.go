@@ -557,6 +557,93 @@ func TestDeployJobFunc(t *testing.T) {
 	}
 }
 
+func TestInheritDependencyContextFunc(t *testing.T) {
+	upstreamJobs := []*v1alpha1.Job{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "jobflow-a", Namespace: "default"},
+			Spec:       v1alpha1.JobSpec{Queue: "q1", PriorityClassName: "high"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "jobflow-b", Namespace: "default"},
+			Spec:       v1alpha1.JobSpec{Queue: "q2"},
+		},
+	}
+	flow := jobflowv1alpha1.Flow{
+		Name:      "c",
+		DependsOn: &jobflowv1alpha1.DependsOn{Targets: []string{"a", "b"}},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		flow        jobflowv1alpha1.Flow
+		spec        v1alpha1.JobSpec
+		want        v1alpha1.JobSpec
+		inherited   string
+	}{
+		{
+			name: "no inheritance by default",
+			flow: flow,
+			want: v1alpha1.JobSpec{},
+		},
+		{
+			name:        "inherit queue and priority class of first dependency",
+			annotations: map[string]string{InheritDependencyContext: "true"},
+			flow:        flow,
+			want:        v1alpha1.JobSpec{Queue: "q1", PriorityClassName: "high"},
+			inherited:   "jobflow-a",
+		},
+		{
+			name:        "keep queue set by template",
+			annotations: map[string]string{InheritDependencyContext: "true"},
+			flow:        flow,
+			spec:        v1alpha1.JobSpec{Queue: "q3"},
+			want:        v1alpha1.JobSpec{Queue: "q3", PriorityClassName: "high"},
+			inherited:   "jobflow-a",
+		},
+		{
+			name:        "boost once dependencies completed",
+			annotations: map[string]string{InheritDependencyContext: "true", DependencyCompletedPriorityClass: "urgent"},
+			flow:        flow,
+			want:        v1alpha1.JobSpec{Queue: "q1", PriorityClassName: "urgent"},
+			inherited:   "jobflow-a",
+		},
+		{
+			name:        "no boost without dependencies",
+			annotations: map[string]string{DependencyCompletedPriorityClass: "urgent"},
+			flow:        jobflowv1alpha1.Flow{Name: "c"},
+			want:        v1alpha1.JobSpec{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeController := newFakeController()
+			for _, job := range upstreamJobs {
+				if err := fakeController.jobInformer.Informer().GetIndexer().Add(job); err != nil {
+					t.Error("Error While add vcjob")
+				}
+			}
+			jobFlow := &jobflowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{Name: "jobflow", Namespace: "default", Annotations: tt.annotations},
+			}
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "jobflow-c", Namespace: "default", Annotations: map[string]string{}},
+				Spec:       tt.spec,
+			}
+
+			if err := fakeController.inheritDependencyContext(jobFlow, tt.flow, job); err != nil {
+				t.Errorf("Expected inheritDependencyContext() return nil, but got %v", err)
+			}
+			if !reflect.DeepEqual(job.Spec, tt.want) {
+				t.Errorf("Expected job spec %v, but got %v", tt.want, job.Spec)
+			}
+			if job.Annotations[InheritedFrom] != tt.inherited {
+				t.Errorf("Expected inherited from %q, but got %q", tt.inherited, job.Annotations[InheritedFrom])
+			}
+		})
+	}
+}
+
 func TestDeleteAllJobsCreateByJobFlowFunc(t *testing.T) {
 	type args struct {
 		jobFlow *jobflowv1alpha1.JobFlow
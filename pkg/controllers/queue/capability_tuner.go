@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+const (
+	// MinCapabilityAnnotation is the key of annotation on queue with the lower bound of capability tuned by
+	// the controller, e.g. cpu=8,memory=32Gi
+	MinCapabilityAnnotation = "volcano.sh/min-capability"
+	// MaxCapabilityAnnotation is the key of annotation on queue with the upper bound of capability tuned by
+	// the controller, only the resources in it are tuned, and queues without it are never tuned
+	MaxCapabilityAnnotation = "volcano.sh/max-capability"
+
+	// CapabilityTunedReason is the reason of event published on each adjustment of capability
+	CapabilityTunedReason = "CapabilityTuned"
+
+	tuneInterval = time.Minute
+	tuneWindow   = time.Hour
+	// a resource is busy if its allocated reaches busyUtilization of capability in every sample of the window,
+	// and idle if it stays below idleUtilization
+	busyUtilization = 0.95
+	idleUtilization = 0.3
+	// tuneStep is the ratio of capability grown or shrunk in one adjustment
+	tuneStep = 0.2
+)
+
+// parseResourceList parses resource list of format cpu=8,memory=32Gi.
+func parseResourceList(value string) (v1.ResourceList, error) {
+	result := v1.ResourceList{}
+	for _, statement := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(statement), "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid resource %q, expected <resource>=<value>", statement)
+		}
+		quantity, err := resource.ParseQuantity(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of resource %s: %v", parts[0], err)
+		}
+		result[v1.ResourceName(parts[0])] = quantity
+	}
+	return result, nil
+}
+
+// capabilityBounds returns the bounds of capability of queue set by operator, tuned is false if queue is not tuned.
+func capabilityBounds(queue *schedulingv1beta1.Queue) (lower, upper v1.ResourceList, tuned bool, err error) {
+	maxValue, found := queue.Annotations[MaxCapabilityAnnotation]
+	if !found {
+		return nil, nil, false, nil
+	}
+	if upper, err = parseResourceList(maxValue); err != nil {
+		return nil, nil, false, fmt.Errorf("invalid %s=%s: %v", MaxCapabilityAnnotation, maxValue, err)
+	}
+	lower = v1.ResourceList{}
+	if minValue, found := queue.Annotations[MinCapabilityAnnotation]; found {
+		if lower, err = parseResourceList(minValue); err != nil {
+			return nil, nil, false, fmt.Errorf("invalid %s=%s: %v", MinCapabilityAnnotation, minValue, err)
+		}
+	}
+	return lower, upper, true, nil
+}
+
+type utilizationSample struct {
+	at          time.Time
+	utilization map[v1.ResourceName]float64
+}
+
+// utilizationHistory remembers the utilization of capability of a queue since its last adjustment.
+type utilizationHistory struct {
+	since   time.Time
+	samples []utilizationSample
+}
+
+// capabilityTuner grows the capability of queues constantly at capability, and shrinks the one of chronically
+// idle queues, within the bounds set by operator.
+type capabilityTuner struct {
+	window  time.Duration
+	history map[string]*utilizationHistory
+}
+
+func newCapabilityTuner(window time.Duration) *capabilityTuner {
+	return &capabilityTuner{window: window, history: map[string]*utilizationHistory{}}
+}
+
+// observe records the utilization of the tuned resources of queue, and returns the history within the window.
+func (t *capabilityTuner) observe(queue *schedulingv1beta1.Queue, upper v1.ResourceList, now time.Time) *utilizationHistory {
+	history, found := t.history[queue.Name]
+	if !found {
+		history = &utilizationHistory{since: now}
+		t.history[queue.Name] = history
+	}
+
+	sample := utilizationSample{at: now, utilization: map[v1.ResourceName]float64{}}
+	for name := range upper {
+		capability, found := queue.Spec.Capability[name]
+		if !found || capability.IsZero() {
+			continue
+		}
+		allocated := queue.Status.Allocated[name]
+		sample.utilization[name] = float64(allocated.MilliValue()) / float64(capability.MilliValue())
+	}
+	history.samples = append(history.samples, sample)
+
+	expired := 0
+	for expired < len(history.samples) && history.samples[expired].at.Before(now.Add(-t.window)) {
+		expired++
+	}
+	history.samples = history.samples[expired:]
+	return history
+}
+
+// tune returns the capability of queue adjusted by its utilization history, and the description of the changes,
+// the capability is not changed if changes is empty.
+func (t *capabilityTuner) tune(queue *schedulingv1beta1.Queue, lower, upper v1.ResourceList, now time.Time) (v1.ResourceList, []string) {
+	history := t.observe(queue, upper, now)
+	if now.Sub(history.since) < t.window {
+		return queue.Spec.Capability, nil
+	}
+
+	capability := queue.Spec.Capability.DeepCopy()
+	var changes []string
+	for name, max := range upper {
+		current, found := capability[name]
+		if !found || current.IsZero() {
+			continue
+		}
+
+		busy, idle := true, true
+		for _, sample := range history.samples {
+			utilization, found := sample.utilization[name]
+			if !found {
+				busy, idle = false, false
+				break
+			}
+			busy = busy && utilization >= busyUtilization
+			idle = idle && utilization < idleUtilization
+		}
+
+		var target int64
+		switch {
+		case busy:
+			target = int64(float64(current.MilliValue()) * (1 + tuneStep))
+			if target > max.MilliValue() {
+				target = max.MilliValue()
+			}
+		case idle:
+			target = int64(float64(current.MilliValue()) * (1 - tuneStep))
+			if min, found := lower[name]; found && target < min.MilliValue() {
+				target = min.MilliValue()
+			}
+			// never shrink below what is allocated in queue
+			if allocated := queue.Status.Allocated[name]; target < allocated.MilliValue() {
+				target = allocated.MilliValue()
+			}
+		default:
+			continue
+		}
+		if target == current.MilliValue() {
+			continue
+		}
+
+		adjusted := resource.NewMilliQuantity(target, current.Format)
+		capability[name] = *adjusted
+		changes = append(changes, fmt.Sprintf("%s %s -> %s", name, current.String(), adjusted.String()))
+	}
+
+	if len(changes) == 0 {
+		return queue.Spec.Capability, nil
+	}
+	sort.Strings(changes)
+	return capability, changes
+}
+
+// reset forgets the history of queue after its capability is adjusted, a fresh window of utilization
+// is needed to judge the adjusted capability.
+func (t *capabilityTuner) reset(name string) {
+	delete(t.history, name)
+}
+
+// prune forgets the history of queues not tuned any more.
+func (t *capabilityTuner) prune(tuned map[string]bool) {
+	for name := range t.history {
+		if !tuned[name] {
+			delete(t.history, name)
+		}
+	}
+}
+
+// tuneCapability adjusts the capability of the tuned queues by their utilization history,
+// and publishes each adjustment as an event of the queue.
+func (c *queuecontroller) tuneCapability() {
+	c.tuneCapabilityAt(time.Now())
+}
+
+func (c *queuecontroller) tuneCapabilityAt(now time.Time) {
+	queues, err := c.queueLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list queues for tuning capability: %v", err)
+		return
+	}
+
+	tuned := map[string]bool{}
+	for _, queue := range queues {
+		if queue.Status.State != schedulingv1beta1.QueueStateOpen {
+			continue
+		}
+		lower, upper, found, err := capabilityBounds(queue)
+		if err != nil {
+			klog.Warningf("Queue <%s> is not tuned: %v", queue.Name, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+		tuned[queue.Name] = true
+
+		capability, changes := c.tuner.tune(queue, lower, upper, now)
+		if len(changes) == 0 {
+			continue
+		}
+
+		newQueue := queue.DeepCopy()
+		newQueue.Spec.Capability = capability
+		if _, err := c.vcClient.SchedulingV1beta1().Queues().Update(context.TODO(), newQueue, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Failed to tune capability of queue <%s>: %v", queue.Name, err)
+			continue
+		}
+		c.tuner.reset(queue.Name)
+		message := fmt.Sprintf("Tuned capability by utilization history: %s", strings.Join(changes, ", "))
+		klog.V(3).Infof("Queue <%s>: %s", queue.Name, message)
+		c.recorder.Event(newQueue, v1.EventTypeNormal, CapabilityTunedReason, message)
+	}
+	c.tuner.prune(tuned)
+}
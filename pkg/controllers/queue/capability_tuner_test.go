@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func buildTunedQueue(name, capability, allocated string, annotations map[string]string) *schedulingv1beta1.Queue {
+	return &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec: schedulingv1beta1.QueueSpec{
+			Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse(capability)},
+		},
+		Status: schedulingv1beta1.QueueStatus{
+			State:     schedulingv1beta1.QueueStateOpen,
+			Allocated: v1.ResourceList{v1.ResourceCPU: resource.MustParse(allocated)},
+		},
+	}
+}
+
+func TestCapabilityBounds(t *testing.T) {
+	queue := buildTunedQueue("q1", "10", "0", map[string]string{
+		MinCapabilityAnnotation: "cpu=4",
+		MaxCapabilityAnnotation: "cpu=20,memory=64Gi",
+	})
+	lower, upper, tuned, err := capabilityBounds(queue)
+	if err != nil || !tuned {
+		t.Fatalf("expected queue tuned, got %v, %v", tuned, err)
+	}
+	if lower.Cpu().Value() != 4 || upper.Cpu().Value() != 20 || upper.Memory().Value() != 64*1024*1024*1024 {
+		t.Errorf("unexpected bounds %v, %v", lower, upper)
+	}
+
+	if _, _, tuned, _ := capabilityBounds(buildTunedQueue("q2", "10", "0", nil)); tuned {
+		t.Errorf("expected queue without bounds not tuned")
+	}
+	if _, _, _, err := capabilityBounds(buildTunedQueue("q3", "10", "0",
+		map[string]string{MaxCapabilityAnnotation: "cpu"})); err == nil {
+		t.Errorf("expected error for invalid bounds")
+	}
+}
+
+func TestCapabilityTunerTune(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	lower := v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}
+	upper := v1.ResourceList{v1.ResourceCPU: resource.MustParse("11")}
+
+	tests := []struct {
+		name      string
+		allocated []string
+		expected  string
+	}{
+		{
+			name:      "constantly at capability grows within max",
+			allocated: []string{"10", "10", "9.6"},
+			expected:  "11",
+		},
+		{
+			name:      "chronically idle shrinks within min",
+			allocated: []string{"1", "2", "0"},
+			expected:  "8",
+		},
+		{
+			name:      "busy not constantly is kept",
+			allocated: []string{"10", "5", "10"},
+			expected:  "10",
+		},
+	}
+
+	for _, test := range tests {
+		tuner := newCapabilityTuner(time.Hour)
+		var capability v1.ResourceList
+		var changes []string
+		for i, allocated := range test.allocated {
+			queue := buildTunedQueue("q1", "10", allocated, nil)
+			capability, changes = tuner.tune(queue, lower, upper, now.Add(time.Duration(i)*30*time.Minute))
+			if i < len(test.allocated)-1 && len(changes) != 0 {
+				t.Errorf("case %s: expected no changes before the window is full, got %v", test.name, changes)
+			}
+		}
+		if quantity := capability[v1.ResourceCPU]; quantity.Cmp(resource.MustParse(test.expected)) != 0 {
+			t.Errorf("case %s: expected cpu capability %s, got %s, changes %v", test.name, test.expected, quantity.String(), changes)
+		}
+	}
+}
+
+func TestTuneCapability(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newFakeController()
+	c.tuner = newCapabilityTuner(time.Hour)
+
+	queue := buildTunedQueue("q1", "10", "10", map[string]string{MaxCapabilityAnnotation: "cpu=20"})
+	if _, err := c.vcClient.SchedulingV1beta1().Queues().Create(context.TODO(), queue, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	c.queueInformer.Informer().GetIndexer().Add(queue)
+
+	c.tuneCapabilityAt(now)
+	c.tuneCapabilityAt(now.Add(time.Hour))
+
+	tuned, err := c.vcClient.SchedulingV1beta1().Queues().Get(context.TODO(), "q1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get queue: %v", err)
+	}
+	if quantity := tuned.Spec.Capability[v1.ResourceCPU]; quantity.Cmp(resource.MustParse("12")) != 0 {
+		t.Errorf("expected cpu capability grown to 12, got %s", quantity.String())
+	}
+	if _, found := c.tuner.history["q1"]; found {
+		t.Errorf("expected history of queue q1 reset after tuning")
+	}
+}
@@ -90,6 +90,9 @@ type queuecontroller struct {
 
 	recorder      record.EventRecorder
 	maxRequeueNum int
+
+	// tuner tunes the capability of queues by their utilization history, nil if capability tuning is disabled.
+	tuner *capabilityTuner
 }
 
 func (c *queuecontroller) Name() string {
@@ -124,6 +127,9 @@ func (c *queuecontroller) Initialize(opt *framework.ControllerOption) error {
 	if c.maxRequeueNum < 0 {
 		c.maxRequeueNum = -1
 	}
+	if opt.EnableQueueCapabilityTuning {
+		c.tuner = newCapabilityTuner(tuneWindow)
+	}
 
 	queueInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.addQueue,
@@ -186,6 +192,9 @@ func (c *queuecontroller) Run(stopCh <-chan struct{}) {
 
 	go wait.Until(c.worker, 0, stopCh)
 	go wait.Until(c.commandWorker, 0, stopCh)
+	if c.tuner != nil {
+		go wait.Until(c.tuneCapability, tuneInterval, stopCh)
+	}
 
 	<-stopCh
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparenode
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+// gangNodes returns how many nodes of allocatable the gang needs at least, it is 0 if pg is not a gang.
+func gangNodes(pg *scheduling.PodGroup, allocatable v1.ResourceList) int {
+	if pg.Spec.MinMember <= 1 || pg.Spec.MinResources == nil {
+		return 0
+	}
+
+	nodes := 0.0
+	for name, quantity := range *pg.Spec.MinResources {
+		capacity, found := allocatable[name]
+		if !found || capacity.IsZero() {
+			continue
+		}
+		nodes = math.Max(nodes, float64(quantity.MilliValue())/float64(capacity.MilliValue()))
+	}
+	return int(math.Ceil(nodes))
+}
+
+// averageAllocatable returns the average allocatable resource of nodes.
+func averageAllocatable(nodes []*v1.Node) v1.ResourceList {
+	total := map[v1.ResourceName]int64{}
+	for _, node := range nodes {
+		for name, quantity := range node.Status.Allocatable {
+			total[name] += quantity.MilliValue()
+		}
+	}
+
+	average := v1.ResourceList{}
+	for name, milliValue := range total {
+		quantity := average[name]
+		quantity.SetMilli(milliValue / int64(len(nodes)))
+		average[name] = quantity
+	}
+	return average
+}
+
+type demandSample struct {
+	nodes int
+	seen  time.Time
+}
+
+// demandHistory remembers the node demand of gangs seen pending within the window.
+type demandHistory struct {
+	window  time.Duration
+	samples map[types.UID]demandSample
+}
+
+func newDemandHistory(window time.Duration) *demandHistory {
+	return &demandHistory{window: window, samples: map[types.UID]demandSample{}}
+}
+
+// observe records the node demand of the pending gang, a gang is counted once by its latest demand.
+func (h *demandHistory) observe(uid types.UID, nodes int, now time.Time) {
+	h.samples[uid] = demandSample{nodes: nodes, seen: now}
+}
+
+// percentile returns the node demand at percentile p of the gangs seen within the window.
+func (h *demandHistory) percentile(p float64, now time.Time) int {
+	var demands []int
+	for uid, sample := range h.samples {
+		if now.Sub(sample.seen) > h.window {
+			delete(h.samples, uid)
+			continue
+		}
+		demands = append(demands, sample.nodes)
+	}
+	if len(demands) == 0 {
+		return 0
+	}
+
+	sort.Ints(demands)
+	index := int(math.Ceil(p*float64(len(demands)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return demands[index]
+}
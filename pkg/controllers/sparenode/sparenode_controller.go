@@ -0,0 +1,294 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparenode
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	schedulinglister "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+const (
+	// SpareNodeAnnotation is the key of annotation on node which is cordoned by the controller as a hot spare,
+	// nodes cordoned by others are never touched.
+	SpareNodeAnnotation = "volcano.sh/spare-node"
+
+	syncPeriod = 30 * time.Second
+	// demandWindow is how long the node demand of a pending gang is remembered.
+	demandWindow = time.Hour
+	// demandPercentile is the percentile of node demand of recent gangs the spare nodes are sized to.
+	demandPercentile = 0.9
+)
+
+func init() {
+	framework.RegisterController(&sparenodecontroller{})
+}
+
+// sparenodecontroller keeps empty nodes of a pool cordoned but ready as hot spares, so large gangs do not
+// wait for a pool of nodes to be drained. The number of spares follows the node demand of recent pending
+// gangs; spares are uncordoned as soon as gangs admitted by the scheduler wait for nodes.
+type sparenodecontroller struct {
+	kubeClient kubernetes.Interface
+	vcClient   vcclientset.Interface
+
+	informerFactory   informers.SharedInformerFactory
+	vcInformerFactory vcinformer.SharedInformerFactory
+
+	// A store of nodes
+	nodeLister corelisters.NodeLister
+	// A store of pods
+	podLister corelisters.PodLister
+	// A store of podgroups
+	pgLister schedulinglister.PodGroupLister
+
+	// selector selects the nodes of pool, the controller is disabled if it is nil
+	selector  labels.Selector
+	minSpares int
+	maxSpares int
+
+	demand *demandHistory
+}
+
+func (sc *sparenodecontroller) Name() string {
+	return "sparenode-controller"
+}
+
+// Initialize creates the spare node controller, it is disabled if no spare node selector is given.
+func (sc *sparenodecontroller) Initialize(opt *framework.ControllerOption) error {
+	if len(opt.SpareNodeSelector) == 0 {
+		return nil
+	}
+
+	selector, err := labels.Parse(opt.SpareNodeSelector)
+	if err != nil {
+		return err
+	}
+	sc.selector = selector
+	sc.minSpares = opt.MinSpareNodes
+	sc.maxSpares = opt.MaxSpareNodes
+	if sc.maxSpares < sc.minSpares {
+		sc.maxSpares = sc.minSpares
+	}
+	sc.demand = newDemandHistory(demandWindow)
+
+	sc.kubeClient = opt.KubeClient
+	sc.vcClient = opt.VolcanoClient
+
+	sc.informerFactory = opt.SharedInformerFactory
+	sc.nodeLister = opt.SharedInformerFactory.Core().V1().Nodes().Lister()
+	sc.podLister = opt.SharedInformerFactory.Core().V1().Pods().Lister()
+
+	factory := informerfactory.NewSharedInformerFactory(sc.vcClient, 0)
+	sc.vcInformerFactory = factory
+	sc.pgLister = factory.Scheduling().V1beta1().PodGroups().Lister()
+
+	return nil
+}
+
+// Run starts syncing spare nodes periodically.
+func (sc *sparenodecontroller) Run(stopCh <-chan struct{}) {
+	if sc.selector == nil {
+		klog.V(3).Infof("Spare node controller is disabled as no spare node selector is given")
+		return
+	}
+
+	sc.informerFactory.Start(stopCh)
+	sc.vcInformerFactory.Start(stopCh)
+
+	for informerType, ok := range sc.informerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+	for informerType, ok := range sc.vcInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+
+	go wait.Until(func() {
+		if err := sc.sync(time.Now()); err != nil {
+			klog.Errorf("Failed to sync spare nodes: %v", err)
+		}
+	}, syncPeriod, stopCh)
+
+	klog.Infof("SpareNodeController is running ...... ")
+}
+
+// sync releases spare nodes to gangs waiting for nodes, and keeps the number of spare nodes as
+// the node demand of recent gangs, within the min and max spare nodes.
+func (sc *sparenodecontroller) sync(now time.Time) error {
+	nodes, err := sc.nodeLister.List(sc.selector)
+	if err != nil || len(nodes) == 0 {
+		return err
+	}
+	pods, err := sc.podLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	pgs, err := sc.pgLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	allocatable := averageAllocatable(nodes)
+	waiting := 0
+	for _, pg := range pgs {
+		demand := gangNodes(pg, allocatable)
+		if demand == 0 {
+			continue
+		}
+		switch pg.Status.Phase {
+		case scheduling.PodGroupPending:
+			sc.demand.observe(pg.UID, demand, now)
+		case scheduling.PodGroupInqueue:
+			sc.demand.observe(pg.UID, demand, now)
+			waiting += demand
+		}
+	}
+	desired := sc.demand.percentile(demandPercentile, now)
+	if desired < sc.minSpares {
+		desired = sc.minSpares
+	}
+	if desired > sc.maxSpares {
+		desired = sc.maxSpares
+	}
+
+	spares, stale, candidates := classifyNodes(nodes, pods)
+	for _, node := range stale {
+		if err := sc.setSpare(node, false); err != nil {
+			return err
+		}
+	}
+
+	release := waiting
+	if len(spares)-desired > release {
+		release = len(spares) - desired
+	}
+	if release > len(spares) {
+		release = len(spares)
+	}
+	klog.V(4).Infof("Spare nodes: %d, desired %d, gangs waiting for %d nodes, %d candidates",
+		len(spares), desired, waiting, len(candidates))
+
+	for _, node := range spares[:release] {
+		if err := sc.setSpare(node, false); err != nil {
+			return err
+		}
+		klog.V(3).Infof("Released spare node %s", node.Name)
+	}
+
+	// Do not take nodes away while gangs are waiting for nodes.
+	if waiting > 0 {
+		return nil
+	}
+	for i := 0; i < desired-len(spares) && i < len(candidates); i++ {
+		if err := sc.setSpare(candidates[i], true); err != nil {
+			return err
+		}
+		klog.V(3).Infof("Cordoned node %s as spare", candidates[i].Name)
+	}
+	return nil
+}
+
+// classifyNodes returns the spare nodes cordoned by the controller, the stale spare nodes uncordoned by others,
+// and the ready and empty nodes which can be cordoned as spares, all sorted by name.
+func classifyNodes(nodes []*v1.Node, pods []*v1.Pod) ([]*v1.Node, []*v1.Node, []*v1.Node) {
+	busy := map[string]bool{}
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) == 0 || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil && controllerRef.Kind == "DaemonSet" {
+			continue
+		}
+		busy[pod.Spec.NodeName] = true
+	}
+
+	var spares, stale, candidates []*v1.Node
+	for _, node := range nodes {
+		if _, found := node.Annotations[SpareNodeAnnotation]; found {
+			if node.Spec.Unschedulable {
+				spares = append(spares, node)
+			} else {
+				stale = append(stale, node)
+			}
+			continue
+		}
+		if !node.Spec.Unschedulable && !busy[node.Name] && isNodeReady(node) {
+			candidates = append(candidates, node)
+		}
+	}
+
+	for _, list := range [][]*v1.Node{spares, stale, candidates} {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+	}
+	return spares, stale, candidates
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// setSpare cordons node and marks it as spare, or uncordons it and drops the mark.
+func (sc *sparenodecontroller) setSpare(node *v1.Node, spare bool) error {
+	var annotation interface{}
+	if spare {
+		annotation = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{SpareNodeAnnotation: annotation},
+		},
+		"spec": map[string]interface{}{
+			"unschedulable": spare,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = sc.kubeClient.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparenode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+)
+
+func newNode(name string, spare bool) *v1.Node {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": "gpu"}},
+		Spec:       v1.NodeSpec{Unschedulable: spare},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+	if spare {
+		node.Annotations = map[string]string{SpareNodeAnnotation: "true"}
+	}
+	return node
+}
+
+func newGang(name string, phase scheduling.PodGroupPhase, cpu string) *scheduling.PodGroup {
+	return &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID("uid-" + name)},
+		Spec: scheduling.PodGroupSpec{
+			MinMember:    4,
+			MinResources: &v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+		},
+		Status: scheduling.PodGroupStatus{Phase: phase},
+	}
+}
+
+func newController(t *testing.T, nodes []*v1.Node, pods []*v1.Pod, pgs []*scheduling.PodGroup) *sparenodecontroller {
+	kubeClient := kubeclient.NewSimpleClientset()
+	vcClient := vcclient.NewSimpleClientset()
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	vcFactory := informerfactory.NewSharedInformerFactory(vcClient, 0)
+
+	for _, node := range nodes {
+		if _, err := kubeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		factory.Core().V1().Nodes().Informer().GetIndexer().Add(node)
+	}
+	for _, pod := range pods {
+		factory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+	}
+	for _, pg := range pgs {
+		vcFactory.Scheduling().V1beta1().PodGroups().Informer().GetIndexer().Add(pg)
+	}
+
+	return &sparenodecontroller{
+		kubeClient: kubeClient,
+		vcClient:   vcClient,
+		nodeLister: factory.Core().V1().Nodes().Lister(),
+		podLister:  factory.Core().V1().Pods().Lister(),
+		pgLister:   vcFactory.Scheduling().V1beta1().PodGroups().Lister(),
+		selector:   labels.SelectorFromSet(labels.Set{"pool": "gpu"}),
+		maxSpares:  5,
+		demand:     newDemandHistory(demandWindow),
+	}
+}
+
+func spareNodes(t *testing.T, sc *sparenodecontroller) []string {
+	nodes, err := sc.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spares []string
+	for _, node := range nodes.Items {
+		_, found := node.Annotations[SpareNodeAnnotation]
+		if found != node.Spec.Unschedulable {
+			t.Errorf("node %s is spare %v but unschedulable %v", node.Name, found, node.Spec.Unschedulable)
+		}
+		if found {
+			spares = append(spares, node.Name)
+		}
+	}
+	return spares
+}
+
+func TestSync(t *testing.T) {
+	busyPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p1"},
+		Spec:       v1.PodSpec{NodeName: "n1"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []*v1.Node
+		pods     []*v1.Pod
+		pgs      []*scheduling.PodGroup
+		expected []string
+	}{
+		{
+			name:     "cordon empty nodes for pending gang",
+			nodes:    []*v1.Node{newNode("n1", false), newNode("n2", false), newNode("n3", false), newNode("n4", false)},
+			pods:     []*v1.Pod{busyPod},
+			pgs:      []*scheduling.PodGroup{newGang("pg1", scheduling.PodGroupPending, "6")},
+			expected: []string{"n2", "n3"},
+		},
+		{
+			name:     "release spare nodes to gang waiting for nodes",
+			nodes:    []*v1.Node{newNode("n1", true), newNode("n2", true), newNode("n3", true), newNode("n4", false)},
+			pgs:      []*scheduling.PodGroup{newGang("pg1", scheduling.PodGroupInqueue, "6")},
+			expected: []string{"n3"},
+		},
+		{
+			name:  "release spare nodes without demand",
+			nodes: []*v1.Node{newNode("n1", true), newNode("n2", false)},
+			pgs:   []*scheduling.PodGroup{newGang("pg1", scheduling.PodGroupRunning, "6")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc := newController(t, test.nodes, test.pods, test.pgs)
+			if err := sc.sync(time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			spares := spareNodes(t, sc)
+			if len(spares) != len(test.expected) {
+				t.Fatalf("expected spare nodes %v, got %v", test.expected, spares)
+			}
+			for i := range spares {
+				if spares[i] != test.expected[i] {
+					t.Errorf("expected spare nodes %v, got %v", test.expected, spares)
+				}
+			}
+		})
+	}
+}
+
+func TestDemandHistory(t *testing.T) {
+	now := time.Now()
+	h := newDemandHistory(time.Hour)
+	for i, nodes := range []int{1, 2, 2, 3, 8} {
+		h.observe(types.UID(fmt.Sprintf("pg%d", i)), nodes, now)
+	}
+	h.observe("old", 20, now.Add(-2*time.Hour))
+
+	if demand := h.percentile(0.5, now); demand != 2 {
+		t.Errorf("expected demand 2 at p50, got %d", demand)
+	}
+	if demand := h.percentile(0.9, now); demand != 8 {
+		t.Errorf("expected demand 8 at p90, got %d", demand)
+	}
+	if _, found := h.samples["old"]; found {
+		t.Errorf("expected sample out of window to be pruned")
+	}
+}
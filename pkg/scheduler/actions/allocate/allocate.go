@@ -72,6 +72,12 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 			job.PodGroup.Status.Phase = scheduling.PodGroupInqueue
 		}
 
+		if ssn.JobPaused(job) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip allocate, reason: scheduling is paused.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
 		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
 			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip allocate, reason: %v, message %v", job.Namespace, job.Name, job.Queue, vr.Reason, vr.Message)
 			continue
@@ -99,7 +105,10 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 	allNodes := ssn.NodeList
 	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
 		// Check for Resource Predicate
-		if ok, resources := task.InitResreq.LessEqualWithResourcesName(node.FutureIdle(), api.Zero); !ok {
+		futureIdle := node.FutureIdle()
+		ok, resources := task.InitResreq.LessEqualWithResourcesName(futureIdle, api.Zero)
+		futureIdle.Release()
+		if !ok {
 			return nil, api.NewFitError(task, node, api.WrapInsufficientResourceReason(resources))
 		}
 		var statusSets util.StatusSets
@@ -172,24 +181,34 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 		for !tasks.Empty() {
 			task := tasks.Pop().(*api.TaskInfo)
 
-			if !ssn.Allocatable(queue, task) {
-				klog.V(3).Infof("Queue <%s> is overused when considering task <%s>, ignore it.", queue.Name, task.Name)
-				continue
-			}
+			predicateNodes, fitErrors, ignored := predicateResourceShapes(job, task, func() ([]*api.NodeInfo, *api.FitErrors, bool) {
+				if !ssn.Allocatable(queue, task) {
+					klog.V(3).Infof("Queue <%s> is overused when considering task <%s>, ignore it.", queue.Name, task.Name)
+					return nil, nil, true
+				}
 
-			klog.V(3).Infof("There are <%d> nodes for Job <%v/%v>", len(ssn.Nodes), job.Namespace, job.Name)
+				if !ssn.AllowedByClusterReserve(task) {
+					klog.V(3).Infof("Cluster reserve is kept from task <%s/%s>, ignore it.", task.Namespace, task.Name)
+					return nil, nil, true
+				}
+
+				klog.V(3).Infof("There are <%d> nodes for Job <%v/%v>", len(ssn.Nodes), job.Namespace, job.Name)
 
-			if err := ssn.PrePredicateFn(task); err != nil {
-				klog.V(3).Infof("PrePredicate for task %s/%s failed for: %v", task.Namespace, task.Name, err)
-				fitErrors := api.NewFitErrors()
-				for _, ni := range allNodes {
-					fitErrors.SetNodeError(ni.Name, err)
+				if err := ssn.PrePredicateFn(task); err != nil {
+					klog.V(3).Infof("PrePredicate for task %s/%s failed for: %v", task.Namespace, task.Name, err)
+					fitErrors := api.NewFitErrors()
+					for _, ni := range allNodes {
+						fitErrors.SetNodeError(ni.Name, err)
+					}
+					return nil, fitErrors, false
 				}
-				job.NodesFitErrors[task.UID] = fitErrors
-				break
-			}
 
-			predicateNodes, fitErrors := ph.PredicateNodes(task, allNodes, predicateFn, true)
+				predicateNodes, fitErrors := ph.PredicateNodes(task, allNodes, predicateFn, true)
+				return predicateNodes, fitErrors, false
+			})
+			if ignored {
+				continue
+			}
 			if len(predicateNodes) == 0 {
 				job.NodesFitErrors[task.UID] = fitErrors
 				break
@@ -288,4 +307,36 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 	}
 }
 
+// predicateResourceShapes predicates nodes for the task in its resource shapes in preference order, and keeps
+// the first shape that some nodes fit. If no node fits any shape, the task is left in its preferred shape
+// and the result of the preferred shape is returned.
+func predicateResourceShapes(job *api.JobInfo, task *api.TaskInfo,
+	predicate func() ([]*api.NodeInfo, *api.FitErrors, bool)) ([]*api.NodeInfo, *api.FitErrors, bool) {
+	if len(task.ResourceShapes) == 0 {
+		return predicate()
+	}
+
+	preferredErrors, preferredIgnored := api.NewFitErrors(), false
+	for shape := range task.ResourceShapes {
+		if err := job.SetTaskResourceShape(task, shape); err != nil {
+			klog.Errorf("Failed to set resource shape %d of task <%s/%s>: %v", shape, task.Namespace, task.Name, err)
+			break
+		}
+		nodes, fitErrors, ignored := predicate()
+		if len(nodes) != 0 {
+			klog.V(3).Infof("Task <%s/%s> is allocated in resource shape %d: %v",
+				task.Namespace, task.Name, shape, task.InitResreq)
+			return nodes, fitErrors, false
+		}
+		if shape == 0 {
+			preferredErrors, preferredIgnored = fitErrors, ignored
+		}
+	}
+
+	if err := job.SetTaskResourceShape(task, 0); err != nil {
+		klog.Errorf("Failed to reset resource shape of task <%s/%s>: %v", task.Namespace, task.Name, err)
+	}
+	return nil, preferredErrors, preferredIgnored
+}
+
 func (alloc *Action) UnInitialize() {}
@@ -115,6 +115,43 @@ func TestAllocate(t *testing.T) {
 				"c1/p2": "n1",
 			},
 		},
+		{
+			name: "paused queue should not be allocated",
+			podGroups: []*schedulingv1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: schedulingv1.PodGroupSpec{
+						Queue: "c1",
+					},
+					Status: schedulingv1.PodGroupStatus{
+						Phase: schedulingv1.PodGroupInqueue,
+					},
+				},
+			},
+			pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string)),
+			},
+			nodes: []*v1.Node{
+				util.BuildNode("n1", util.BuildResourceList("2", "4Gi"), make(map[string]string)),
+			},
+			queues: []*schedulingv1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "c1",
+						Annotations: map[string]string{
+							api.SchedulingPausedAnnotation: "true",
+						},
+					},
+					Spec: schedulingv1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: map[string]string{},
+		},
 		{
 			name: "two Jobs on one node",
 			podGroups: []*schedulingv1.PodGroup{
@@ -492,3 +529,101 @@ func TestAllocateWithDynamicPVC(t *testing.T) {
 		})
 	}
 }
+
+func TestAllocateWithResourceShapes(t *testing.T) {
+	shapes := map[string]int{}
+	var tmp *cache.SchedulerCache
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(tmp), "AddBindTask", func(scCache *cache.SchedulerCache, task *api.TaskInfo) error {
+		scCache.Binder.Bind(nil, []*api.TaskInfo{task})
+		shapes[task.Name] = task.ResourceShape
+		return nil
+	})
+	defer patches.Reset()
+
+	patchUpdateQueueStatus := gomonkey.ApplyMethod(reflect.TypeOf(tmp), "UpdateQueueStatus", func(scCache *cache.SchedulerCache, queue *api.QueueInfo) error {
+		return nil
+	})
+	defer patchUpdateQueueStatus.Reset()
+
+	framework.RegisterPluginBuilder("proportion", proportion.New)
+	defer framework.CleanupPluginBuilders()
+
+	options.ServerOpts = &options.ServerOption{
+		MinNodesToFind:             100,
+		MinPercentageOfNodesToFind: 5,
+		PercentageOfNodesToFind:    100,
+	}
+
+	a100Node := util.BuildResourceList("4", "8Gi")
+	a100Node["nvidia.com/a100"] = resource.MustParse("1")
+	v100Node := util.BuildResourceList("4", "8Gi")
+	v100Node["nvidia.com/v100"] = resource.MustParse("2")
+
+	binder := &util.FakeBinder{
+		Binds:   map[string]string{},
+		Channel: make(chan string),
+	}
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Binder:        binder,
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(util.BuildNode("n1", a100Node, make(map[string]string)))
+	schedulerCache.AddNode(util.BuildNode("n2", v100Node, make(map[string]string)))
+	for _, name := range []string{"p1", "p2"} {
+		pod := util.BuildPod("c1", name, "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string))
+		pod.Annotations[api.ResourceShapesAnnotation] = `[{"nvidia.com/a100":"1"},{"nvidia.com/v100":"2"}]`
+		schedulerCache.AddPod(pod)
+	}
+	schedulerCache.AddPodGroupV1beta1(&schedulingv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "c1"},
+		Spec:       schedulingv1.PodGroupSpec{Queue: "c1"},
+		Status:     schedulingv1.PodGroupStatus{Phase: schedulingv1.PodGroupInqueue},
+	})
+	schedulerCache.AddQueueV1beta1(&schedulingv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+		Spec:       schedulingv1.QueueSpec{Weight: 1},
+	})
+
+	trueValue := true
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:               "proportion",
+					EnabledQueueOrder:  &trueValue,
+					EnabledReclaimable: &trueValue,
+				},
+			},
+		},
+	}, nil)
+	defer framework.CloseSession(ssn)
+
+	New().Execute(ssn)
+
+	if len(binder.Binds) != 2 {
+		t.Fatalf("expected both tasks bound, got %v", binder.Binds)
+	}
+	for name, shape := range shapes {
+		expected := map[int]string{0: "n1", 1: "n2"}[shape]
+		if node := binder.Binds["c1/"+name]; node != expected {
+			t.Errorf("expected task %s in resource shape %d bound to %s, got %s", name, shape, expected, node)
+		}
+	}
+	if shapes["p1"] == shapes["p2"] {
+		t.Errorf("expected tasks allocated in different resource shapes, got %v", shapes)
+	}
+
+	job := ssn.Jobs["c1/pg1"]
+	expectedRequest := api.NewResource(util.BuildResourceList("2", "2G"))
+	expectedRequest.AddScalar("nvidia.com/a100", 1000)
+	expectedRequest.AddScalar("nvidia.com/v100", 2000)
+	if !job.TotalRequest.Equal(expectedRequest, api.Zero) {
+		t.Errorf("expected total request of job %v, got %v", expectedRequest, job.TotalRequest)
+	}
+}
@@ -50,6 +50,12 @@ func (backfill *Action) Execute(ssn *framework.Session) {
 			continue
 		}
 
+		if ssn.JobPaused(job) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip backfill, reason: scheduling is paused.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
 		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
 			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip backfill, reason: %v, message %v", job.Namespace, job.Name, job.Queue, vr.Reason, vr.Message)
 			continue
@@ -19,6 +19,7 @@ package enqueue
 import (
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
@@ -29,10 +30,13 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
-type Action struct{}
+type Action struct {
+	// gangs tracks the in-flight gangs across sessions
+	gangs *gangTracker
+}
 
 func New() *Action {
-	return &Action{}
+	return &Action{gangs: newGangTracker()}
 }
 
 func (enqueue *Action) Name() string {
@@ -67,6 +71,18 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 			queues.Push(queue)
 		}
 
+		if ssn.JobPaused(job) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip enqueue, reason: scheduling is paused.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
+		if ssn.JobDeferred(job, time.Now()) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip enqueue, reason: out of schedule window.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
 		if job.IsPending() {
 			if _, found := jobsMap[job.Queue]; !found {
 				jobsMap[job.Queue] = util.NewPriorityQueue(ssn.JobOrderFn)
@@ -76,6 +92,14 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 		}
 	}
 
+	arguments := framework.GetArgOfActionFromConf(ssn.Configurations, enqueue.Name())
+	if newUsageGate(arguments).closed(ssn.Nodes) {
+		return
+	}
+	now := time.Now()
+	gangLimit := enqueue.gangs.newGangLimit(arguments, ssn.Jobs, ssn.OtherProfileJobs(), now)
+	quotaGate := newQuotaGate(arguments, ssn)
+
 	klog.V(3).Infof("Try to enqueue PodGroup to %d Queues", len(jobsMap))
 
 	for {
@@ -92,10 +116,36 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 		}
 		job := jobs.Pop().(*api.JobInfo)
 
+		if isGang(job) && gangLimit.full() {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip enqueue, reason: too many in-flight gangs.",
+				job.Namespace, job.Name, job.Queue)
+			queues.Push(queue)
+			continue
+		}
+
+		if admitted, reason := quotaGate.admits(job); !admitted {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip enqueue, reason: %s.",
+				job.Namespace, job.Name, job.Queue, reason)
+			ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), reason)
+			queues.Push(queue)
+			continue
+		}
+
 		if job.PodGroup.Spec.MinResources == nil || ssn.JobEnqueueable(job) {
 			ssn.JobEnqueued(job)
 			job.PodGroup.Status.Phase = scheduling.PodGroupInqueue
 			ssn.Jobs[job.UID] = job
+			// The time the job is enqueued is kept by the podgroup, so e.g. the gang scheduling timeout
+			// survives restarts of the scheduler.
+			ssn.UpdatePodGroupCondition(job, &scheduling.PodGroupCondition{
+				Type:               api.PodGroupInqueueType,
+				Status:             v1.ConditionTrue,
+				TransitionID:       string(ssn.UID),
+				LastTransitionTime: metav1.NewTime(now),
+				Reason:             api.EnqueuedReason,
+			})
+			enqueue.gangs.admit(gangLimit, job, now)
+			quotaGate.admit(job)
 		}
 
 		// Added Queue back until no job in Queue.
@@ -104,3 +154,11 @@ func (enqueue *Action) Execute(ssn *framework.Session) {
 }
 
 func (enqueue *Action) UnInitialize() {}
+
+// SnapshotState saves when the in-flight gangs were admitted, and returns the function restoring it.
+func (enqueue *Action) SnapshotState() func() {
+	admitted := copyAdmitted(enqueue.gangs.admitted)
+	return func() {
+		enqueue.gangs.admitted = copyAdmitted(admitted)
+	}
+}
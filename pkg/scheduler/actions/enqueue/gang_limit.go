@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// MaxInflightGangs is the argument key of the max number of gangs admitted into Inqueue but not yet
+	// running, additional gangs are kept pending until earlier gangs are bound or time out. 0 means no limit.
+	MaxInflightGangs = "inflightGangs.max"
+	// InflightGangTimeout is the argument key of the duration after which an admitted gang no longer
+	// counts towards the limit, e.g. 10m. 0 means gangs never time out.
+	InflightGangTimeout = "inflightGangs.timeout"
+
+	defaultInflightGangTimeout = 10 * time.Minute
+)
+
+// gangTracker remembers when gangs were admitted across sessions.
+type gangTracker struct {
+	admitted map[api.JobID]time.Time
+}
+
+func newGangTracker() *gangTracker {
+	return &gangTracker{admitted: map[api.JobID]time.Time{}}
+}
+
+func copyAdmitted(admitted map[api.JobID]time.Time) map[api.JobID]time.Time {
+	copied := make(map[api.JobID]time.Time, len(admitted))
+	for job, at := range admitted {
+		copied[job] = at
+	}
+	return copied
+}
+
+// gangLimit bounds the number of in-flight gangs in one session.
+type gangLimit struct {
+	max     int
+	timeout time.Duration
+	// inflight is the number of in-flight gangs, including the ones admitted in this session
+	inflight int
+}
+
+// isGang checks whether the job needs more than one task to run.
+func isGang(job *api.JobInfo) bool {
+	return job.MinAvailable > 1
+}
+
+// isInflight checks whether the gang is admitted but not yet running.
+func isInflight(job *api.JobInfo) bool {
+	return isGang(job) && job.PodGroup != nil && job.PodGroup.Status.Phase == scheduling.PodGroupInqueue
+}
+
+// newGangLimit reads the limit from the arguments of enqueue action and counts the in-flight gangs of jobs and of
+// otherJobs, the jobs of other scheduling profiles, as they share the limit; it returns nil if no limit is configured.
+func (t *gangTracker) newGangLimit(arguments framework.Arguments, jobs, otherJobs map[api.JobID]*api.JobInfo,
+	now time.Time) *gangLimit {
+	limit := &gangLimit{timeout: defaultInflightGangTimeout}
+	arguments.GetInt(&limit.max, MaxInflightGangs)
+	var timeout string
+	arguments.GetString(&timeout, InflightGangTimeout)
+	if len(timeout) != 0 {
+		if d, err := time.ParseDuration(timeout); err == nil && d >= 0 {
+			limit.timeout = d
+		} else {
+			klog.Warningf("Invalid %s %s, use default %v", InflightGangTimeout, timeout, defaultInflightGangTimeout)
+		}
+	}
+	if limit.max <= 0 {
+		t.admitted = map[api.JobID]time.Time{}
+		return nil
+	}
+
+	// The gangs deleted, or no longer in-flight, are forgotten whether they timed out or not.
+	for uid := range t.admitted {
+		job, found := jobs[uid]
+		if !found {
+			job, found = otherJobs[uid]
+		}
+		if !found || !isInflight(job) {
+			delete(t.admitted, uid)
+		}
+	}
+	for _, all := range []map[api.JobID]*api.JobInfo{jobs, otherJobs} {
+		for uid, job := range all {
+			if !isInflight(job) {
+				continue
+			}
+			if _, found := t.admitted[uid]; !found {
+				t.admitted[uid] = now
+			}
+			if !limit.expired(t.admitted[uid], now) {
+				limit.inflight++
+			}
+		}
+	}
+	return limit
+}
+
+func (l *gangLimit) expired(admitted, now time.Time) bool {
+	return l.timeout > 0 && now.Sub(admitted) >= l.timeout
+}
+
+// full checks whether no more gangs can be admitted.
+func (l *gangLimit) full() bool {
+	return l != nil && l.inflight >= l.max
+}
+
+// admit records the gang admitted into Inqueue.
+func (t *gangTracker) admit(l *gangLimit, job *api.JobInfo, now time.Time) {
+	if l == nil || !isGang(job) {
+		return
+	}
+	t.admitted[job.UID] = now
+	l.inflight++
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"testing"
+	"time"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestGangLimit(t *testing.T) {
+	newJob := func(uid api.JobID, minAvailable int32, phase scheduling.PodGroupPhase) *api.JobInfo {
+		return &api.JobInfo{
+			UID:          uid,
+			MinAvailable: minAvailable,
+			PodGroup:     &api.PodGroup{PodGroup: scheduling.PodGroup{Status: scheduling.PodGroupStatus{Phase: phase}}},
+		}
+	}
+	jobs := map[api.JobID]*api.JobInfo{
+		"inqueue":  newJob("inqueue", 2, scheduling.PodGroupInqueue),
+		"running":  newJob("running", 2, scheduling.PodGroupRunning),
+		"single":   newJob("single", 1, scheduling.PodGroupInqueue),
+		"pending":  newJob("pending", 3, scheduling.PodGroupPending),
+		"inqueue2": newJob("inqueue2", 2, scheduling.PodGroupInqueue),
+	}
+	arguments := framework.Arguments{MaxInflightGangs: 3, InflightGangTimeout: "10m"}
+	start := time.Now()
+
+	tracker := newGangTracker()
+	if limit := tracker.newGangLimit(framework.Arguments{}, jobs, nil, start); limit != nil {
+		t.Fatalf("expected no limit without %s", MaxInflightGangs)
+	}
+
+	limit := tracker.newGangLimit(arguments, jobs, nil, start)
+	if limit.inflight != 2 || limit.full() {
+		t.Fatalf("expected 2 in-flight gangs and not full, got %d", limit.inflight)
+	}
+	tracker.admit(limit, jobs["single"], start)
+	tracker.admit(limit, jobs["pending"], start)
+	if !limit.full() {
+		t.Errorf("expected limit full after admitting a gang, got %d in-flight gangs", limit.inflight)
+	}
+
+	// The gang admitted later has not timed out yet.
+	jobs["pending"].PodGroup.Status.Phase = scheduling.PodGroupInqueue
+	limit = tracker.newGangLimit(arguments, jobs, nil, start.Add(5*time.Minute))
+	if limit.inflight != 3 || !limit.full() {
+		t.Errorf("expected 3 in-flight gangs, got %d", limit.inflight)
+	}
+
+	// The gang which is running is no longer tracked.
+	jobs["inqueue"].PodGroup.Status.Phase = scheduling.PodGroupRunning
+	limit = tracker.newGangLimit(arguments, jobs, nil, start.Add(5*time.Minute))
+	if limit.inflight != 2 {
+		t.Errorf("expected 2 in-flight gangs, got %d", limit.inflight)
+	}
+	if _, found := tracker.admitted["inqueue"]; found {
+		t.Errorf("expected running gang to be untracked")
+	}
+
+	// Gangs time out.
+	limit = tracker.newGangLimit(arguments, jobs, nil, start.Add(10*time.Minute))
+	if limit.inflight != 0 {
+		t.Errorf("expected all gangs to time out, got %d in-flight gangs", limit.inflight)
+	}
+
+	// The gangs deleted are forgotten even if they never time out.
+	arguments[InflightGangTimeout] = "0"
+	tracker.newGangLimit(arguments, jobs, nil, start.Add(10*time.Minute))
+	delete(jobs, "inqueue2")
+	if tracker.newGangLimit(arguments, jobs, nil, start.Add(11*time.Minute)); len(tracker.admitted) != 1 {
+		t.Errorf("expected only the gang of job pending tracked, got %v", tracker.admitted)
+	}
+
+	// The in-flight gangs of other scheduling profiles count towards the limit.
+	others := map[api.JobID]*api.JobInfo{
+		"other":  newJob("other", 2, scheduling.PodGroupInqueue),
+		"other2": newJob("other2", 2, scheduling.PodGroupInqueue),
+	}
+	if limit := tracker.newGangLimit(arguments, jobs, others, start.Add(12*time.Minute)); limit.inflight != 3 || !limit.full() {
+		t.Errorf("expected 3 in-flight gangs with the ones of other profiles, got %d", limit.inflight)
+	}
+	delete(others, "other")
+	if tracker.newGangLimit(arguments, jobs, others, start.Add(13*time.Minute)); len(tracker.admitted) != 2 {
+		t.Errorf("expected the gang of the deleted job of other profile forgotten, got %v", tracker.admitted)
+	}
+}
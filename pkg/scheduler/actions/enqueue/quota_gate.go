@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	quotacore "k8s.io/kubernetes/pkg/quota/v1/evaluator/core"
+	"k8s.io/utils/clock"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// ResourceQuotaCheck is the argument key which enables checking the remaining ResourceQuotas of the namespace
+// before a PodGroup is admitted into Inqueue.
+const ResourceQuotaCheck = "resourceQuota.check"
+
+// quotaLimitsPrefix is the prefix of the resources of ResourceQuota limiting the limits of pods.
+const quotaLimitsPrefix = "limits."
+
+// quotaStandardResources are the resources whose requests are also limited by ResourceQuota without
+// the `requests.` prefix.
+var quotaStandardResources = map[v1.ResourceName]bool{
+	v1.ResourceCPU:              true,
+	v1.ResourceMemory:           true,
+	v1.ResourceEphemeralStorage: true,
+}
+
+// quotaGate keeps PodGroups out of Inqueue if the pods of their minMember would exceed the remaining
+// ResourceQuotas of their namespace, so gangs are not partly created and then rejected by the quota
+// admission controller.
+type quotaGate struct {
+	namespaces map[api.NamespaceName]*api.NamespaceInfo
+	// pending is the quota usage of the pods not created yet of the PodGroups in Inqueue by namespace,
+	// including the ones admitted in this session
+	pending map[string]v1.ResourceList
+}
+
+// newQuotaGate reads the quota gate from the arguments of enqueue action, it returns nil if the check is
+// not enabled.
+func newQuotaGate(arguments framework.Arguments, ssn *framework.Session) *quotaGate {
+	enabled := false
+	arguments.GetBool(&enabled, ResourceQuotaCheck)
+	if !enabled {
+		return nil
+	}
+
+	gate := &quotaGate{namespaces: ssn.NamespaceInfo, pending: map[string]v1.ResourceList{}}
+	for _, job := range ssn.Jobs {
+		if job.PodGroup != nil && job.PodGroup.Status.Phase == scheduling.PodGroupInqueue {
+			gate.pending[job.Namespace] = quotav1.Add(gate.pending[job.Namespace], quotaUsage(job))
+		}
+	}
+	return gate
+}
+
+// quotaUsage returns the usage of ResourceQuota by the pods of minMember of job not created yet: the requests of
+// minResources are counted to both `requests.<resource>` and `<resource>` for standard resources, and to
+// `requests.<resource>` for others; minMember is counted to `pods` and `count/pods`. The limits of the pods are
+// unknown before they are created, but never less than their requests, so the requests of standard resources are
+// also counted to `limits.<resource>`. The pods of job created are already counted in the used of ResourceQuotas,
+// so their usage is taken off.
+func quotaUsage(job *api.JobInfo) v1.ResourceList {
+	usage := v1.ResourceList{}
+	if job.PodGroup == nil {
+		return usage
+	}
+	if minResources := job.PodGroup.Spec.MinResources; minResources != nil {
+		for name, quantity := range *minResources {
+			name = v1.ResourceName(strings.TrimPrefix(string(name), v1.DefaultResourceRequestsPrefix))
+			if quotaStandardResources[name] {
+				usage[name] = quantity.DeepCopy()
+				usage[v1.ResourceName(quotaLimitsPrefix+string(name))] = quantity.DeepCopy()
+			}
+			usage[v1.ResourceName(v1.DefaultResourceRequestsPrefix+string(name))] = quantity.DeepCopy()
+		}
+	}
+	pods := *resource.NewQuantity(int64(job.PodGroup.Spec.MinMember), resource.DecimalSI)
+	usage[v1.ResourcePods] = pods
+	usage["count/pods"] = pods.DeepCopy()
+
+	created := v1.ResourceList{}
+	for _, task := range job.Tasks {
+		if task.Pod == nil {
+			continue
+		}
+		podUsage, _ := quotacore.PodUsageFunc(task.Pod, clock.RealClock{})
+		created = quotav1.Add(created, podUsage)
+	}
+	return quotav1.Mask(quotav1.SubtractWithNonNegativeResult(usage, created), quotav1.ResourceNames(usage))
+}
+
+// admits checks whether the quota usage of job fits into the remaining of every ResourceQuota of its
+// namespace, quotas with scopes are not checked. The reason is returned if not.
+func (g *quotaGate) admits(job *api.JobInfo) (bool, string) {
+	if g == nil {
+		return true, ""
+	}
+	namespace, found := g.namespaces[api.NamespaceName(job.Namespace)]
+	if !found {
+		return true, ""
+	}
+
+	usage := quotaUsage(job)
+	names := make([]string, 0, len(namespace.QuotaStatus))
+	for name := range namespace.QuotaStatus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if namespace.QuotaScoped[name] {
+			continue
+		}
+		status := namespace.QuotaStatus[name]
+		hard := quotav1.ResourceNames(status.Hard)
+		requested := quotav1.Mask(usage, hard)
+		if len(requested) == 0 {
+			continue
+		}
+		used := quotav1.Add(status.Used, g.pending[job.Namespace])
+		newUsage := quotav1.Mask(quotav1.Add(used, requested), quotav1.ResourceNames(requested))
+		if allowed, exceeded := quotav1.LessThanOrEqual(newUsage, status.Hard); !allowed {
+			return false, fmt.Sprintf("resource quota %s insufficient, requested: %v, used: %v, limited: %v",
+				name, quotav1.Mask(requested, exceeded), quotav1.Mask(used, exceeded), quotav1.Mask(status.Hard, exceeded))
+		}
+	}
+	return true, ""
+}
+
+// admit counts the quota usage of job admitted into Inqueue.
+func (g *quotaGate) admit(job *api.JobInfo) {
+	if g == nil {
+		return
+	}
+	g.pending[job.Namespace] = quotav1.Add(g.pending[job.Namespace], quotaUsage(job))
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestQuotaGate(t *testing.T) {
+	newJob := func(uid api.JobID, minMember int32, cpu string, phase scheduling.PodGroupPhase) *api.JobInfo {
+		return &api.JobInfo{
+			UID:          uid,
+			Namespace:    "ns",
+			MinAvailable: minMember,
+			PodGroup: &api.PodGroup{PodGroup: scheduling.PodGroup{
+				Spec: scheduling.PodGroupSpec{
+					MinMember:    minMember,
+					MinResources: &v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+				},
+				Status: scheduling.PodGroupStatus{Phase: phase},
+			}},
+		}
+	}
+	namespace := api.NewNamespaceCollection("ns")
+	namespace.Update(&v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute"},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{"requests.cpu": resource.MustParse("10"), v1.ResourcePods: resource.MustParse("8")},
+			Used: v1.ResourceList{"requests.cpu": resource.MustParse("2"), v1.ResourcePods: resource.MustParse("2")},
+		},
+	})
+	namespace.Update(&v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "best-effort"},
+		Spec:       v1.ResourceQuotaSpec{Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeBestEffort}},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("0")},
+		},
+	})
+	// one of the pods of the partial gang is created and counted in the used of the quota.
+	partial := newJob("partial", 2, "2", scheduling.PodGroupInqueue)
+	partial.Tasks = map[api.TaskID]*api.TaskInfo{"p0": api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p0", UID: "p0"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		}}}},
+		Status: v1.PodStatus{Phase: v1.PodPending},
+	})}
+	ssn := &framework.Session{
+		Jobs: map[api.JobID]*api.JobInfo{
+			"inqueue": newJob("inqueue", 2, "4", scheduling.PodGroupInqueue),
+			"partial": partial,
+		},
+		NamespaceInfo: map[api.NamespaceName]*api.NamespaceInfo{"ns": namespace.Snapshot()},
+	}
+
+	if gate := newQuotaGate(framework.Arguments{}, ssn); gate != nil {
+		t.Fatalf("expected no gate without %s", ResourceQuotaCheck)
+	}
+	gate := newQuotaGate(framework.Arguments{ResourceQuotaCheck: true}, ssn)
+
+	// 2 used, 4 of the gang in Inqueue and 1 of the pod of the partial gang not created leave 3 cpu and 3 pods.
+	if admitted, reason := gate.admits(newJob("fit", 2, "3", scheduling.PodGroupPending)); !admitted {
+		t.Errorf("expected gang fitting into the quota admitted, got %s", reason)
+	}
+	admitted, reason := gate.admits(newJob("cpu", 2, "4", scheduling.PodGroupPending))
+	if admitted || !strings.Contains(reason, "requests.cpu") {
+		t.Errorf("expected gang exceeding the cpu quota rejected, got %v %s", admitted, reason)
+	}
+	if admitted, _ := gate.admits(newJob("pods", 4, "1", scheduling.PodGroupPending)); admitted {
+		t.Errorf("expected gang exceeding the pods quota rejected")
+	}
+
+	// the limits of the pods not created yet are at least the requests of minResources.
+	limits := api.NewNamespaceCollection("limits")
+	limits.Update(&v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits"},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{"limits.cpu": resource.MustParse("4")},
+			Used: v1.ResourceList{"limits.cpu": resource.MustParse("2")},
+		},
+	})
+	gate.namespaces["limits"] = limits.Snapshot()
+	limited := newJob("limited", 2, "3", scheduling.PodGroupPending)
+	limited.Namespace = "limits"
+	admitted, reason = gate.admits(limited)
+	if admitted || !strings.Contains(reason, "limits.cpu") {
+		t.Errorf("expected gang exceeding the limits cpu quota rejected, got %v %s", admitted, reason)
+	}
+
+	gate.admit(newJob("fit", 2, "3", scheduling.PodGroupPending))
+	if admitted, _ := gate.admits(newJob("more", 1, "1", scheduling.PodGroupPending)); admitted {
+		t.Errorf("expected gang exceeding the quota left by admitted gangs rejected")
+	}
+	if admitted, _ := gate.admits(&api.JobInfo{Namespace: "other", PodGroup: &api.PodGroup{}}); !admitted {
+		t.Errorf("expected gang in namespace without quota admitted")
+	}
+}
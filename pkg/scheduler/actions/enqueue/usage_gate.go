@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// CPUUsageWatermark is the argument key of the cluster-wide average cpu usage, in percentage,
+	// above which no more PodGroups are admitted into Inqueue.
+	CPUUsageWatermark = "usageWatermark.cpu"
+	// MEMUsageWatermark is the argument key of the cluster-wide average memory usage, in percentage,
+	// above which no more PodGroups are admitted into Inqueue.
+	MEMUsageWatermark = "usageWatermark.memory"
+	// UsagePeriod is the argument key of the period of average usage reported by the metrics source.
+	UsagePeriod = "usageWatermark.period"
+
+	defaultUsagePeriod = "5m"
+)
+
+// usageGate stops admitting PodGroups when the cluster-wide average usage is above the watermarks.
+type usageGate struct {
+	cpuWatermark float64
+	memWatermark float64
+	period       string
+}
+
+// newUsageGate reads the usage gate from the arguments of enqueue action, it returns nil if no
+// watermark is configured.
+func newUsageGate(arguments framework.Arguments) *usageGate {
+	gate := &usageGate{period: defaultUsagePeriod}
+	arguments.GetFloat64(&gate.cpuWatermark, CPUUsageWatermark)
+	arguments.GetFloat64(&gate.memWatermark, MEMUsageWatermark)
+	arguments.GetString(&gate.period, UsagePeriod)
+	if gate.cpuWatermark <= 0 && gate.memWatermark <= 0 {
+		return nil
+	}
+	return gate
+}
+
+// closed checks whether the cluster-wide average usage of nodes is above any watermark.
+// Usage of nodes is weighted by their allocatable resource; nodes not reporting usage are ignored,
+// so the gate is open if the metrics source is unavailable.
+func (g *usageGate) closed(nodes map[string]*api.NodeInfo) bool {
+	if g == nil {
+		return false
+	}
+
+	var cpuUsed, cpuTotal, memUsed, memTotal float64
+	for _, node := range nodes {
+		if node.ResourceUsage == nil || node.Allocatable == nil {
+			continue
+		}
+		if usage, found := node.ResourceUsage.CPUUsageAvg[g.period]; found {
+			cpuUsed += usage * node.Allocatable.MilliCPU
+			cpuTotal += node.Allocatable.MilliCPU
+		}
+		if usage, found := node.ResourceUsage.MEMUsageAvg[g.period]; found {
+			memUsed += usage * node.Allocatable.Memory
+			memTotal += node.Allocatable.Memory
+		}
+	}
+
+	if g.cpuWatermark > 0 && cpuTotal > 0 && cpuUsed/cpuTotal > g.cpuWatermark {
+		klog.V(3).Infof("Cluster cpu usage %f exceeds the watermark %f, stop enqueue.", cpuUsed/cpuTotal, g.cpuWatermark)
+		return true
+	}
+	if g.memWatermark > 0 && memTotal > 0 && memUsed/memTotal > g.memWatermark {
+		klog.V(3).Infof("Cluster memory usage %f exceeds the watermark %f, stop enqueue.", memUsed/memTotal, g.memWatermark)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enqueue
+
+import (
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestUsageGate(t *testing.T) {
+	newNode := func(cpu, cpuUsage, memUsage float64) *api.NodeInfo {
+		usage := &api.NodeUsage{
+			CPUUsageAvg: map[string]float64{"5m": cpuUsage},
+			MEMUsageAvg: map[string]float64{"5m": memUsage},
+		}
+		return &api.NodeInfo{
+			Allocatable:   &api.Resource{MilliCPU: cpu, Memory: 1000},
+			ResourceUsage: usage,
+		}
+	}
+	nodes := map[string]*api.NodeInfo{
+		"n1": newNode(1000, 90, 50),
+		"n2": newNode(3000, 70, 70),
+	}
+
+	tests := []struct {
+		name      string
+		arguments framework.Arguments
+		nodes     map[string]*api.NodeInfo
+		expected  bool
+	}{
+		{
+			name:      "no watermark",
+			arguments: framework.Arguments{},
+			nodes:     nodes,
+			expected:  false,
+		},
+		{
+			name:      "cpu usage weighted by allocatable below watermark",
+			arguments: framework.Arguments{CPUUsageWatermark: 80},
+			nodes:     nodes,
+			expected:  false,
+		},
+		{
+			name:      "cpu usage above watermark",
+			arguments: framework.Arguments{CPUUsageWatermark: 70},
+			nodes:     nodes,
+			expected:  true,
+		},
+		{
+			name:      "memory usage above watermark",
+			arguments: framework.Arguments{CPUUsageWatermark: 80, MEMUsageWatermark: 55.5},
+			nodes:     nodes,
+			expected:  true,
+		},
+		{
+			name:      "no usage reported for period",
+			arguments: framework.Arguments{CPUUsageWatermark: 10, UsagePeriod: "10m"},
+			nodes:     nodes,
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if closed := newUsageGate(test.arguments).closed(test.nodes); closed != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, closed)
+			}
+		})
+	}
+}
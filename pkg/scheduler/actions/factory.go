@@ -21,6 +21,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/actions/backfill"
 	"volcano.sh/volcano/pkg/scheduler/actions/enqueue"
 	"volcano.sh/volcano/pkg/scheduler/actions/preempt"
+	"volcano.sh/volcano/pkg/scheduler/actions/rebalance"
 	"volcano.sh/volcano/pkg/scheduler/actions/reclaim"
 	"volcano.sh/volcano/pkg/scheduler/actions/shuffle"
 	"volcano.sh/volcano/pkg/scheduler/framework"
@@ -33,4 +34,5 @@ func init() {
 	framework.RegisterAction(preempt.New())
 	framework.RegisterAction(enqueue.New())
 	framework.RegisterAction(shuffle.New())
+	framework.RegisterAction(rebalance.New())
 }
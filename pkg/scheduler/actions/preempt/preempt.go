@@ -54,6 +54,12 @@ func (pmpt *Action) Execute(ssn *framework.Session) {
 			continue
 		}
 
+		if ssn.JobPaused(job) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip preemption, reason: scheduling is paused.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
 		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
 			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip preemption, reason: %v, message %v", job.Namespace, job.Name, job.Queue, vr.Reason, vr.Message)
 			continue
@@ -76,6 +82,10 @@ func (pmpt *Action) Execute(ssn *framework.Session) {
 			underRequest = append(underRequest, job)
 			preemptorTasks[job.UID] = util.NewPriorityQueue(ssn.TaskOrderFn)
 			for _, task := range job.TaskStatusIndex[api.Pending] {
+				if task.PreemptNever {
+					skipPreemptNever(job, task)
+					continue
+				}
 				preemptorTasks[job.UID].Push(task)
 			}
 		}
@@ -153,6 +163,9 @@ func (pmpt *Action) Execute(ssn *framework.Session) {
 			// Fix: preemptor numbers lose when in same job
 			preemptorTasks[job.UID] = util.NewPriorityQueue(ssn.TaskOrderFn)
 			for _, task := range job.TaskStatusIndex[api.Pending] {
+				if task.PreemptNever {
+					continue
+				}
 				preemptorTasks[job.UID].Push(task)
 			}
 			for {
@@ -238,6 +251,11 @@ func preempt(
 	currentQueue := ssn.Queues[job.Queue]
 
 	for _, node := range selectedNodes {
+		if !ssn.InPreemptionDomain(job, node) {
+			klog.V(4).Infof("Node <%s> is out of the preemption domain of Job <%s/%s>, skip it.",
+				node.Name, job.Namespace, job.Name)
+			continue
+		}
 		klog.V(3).Infof("Considering Task <%s/%s> on Node <%s>.",
 			preemptor.Namespace, preemptor.Name, node.Name)
 
@@ -249,7 +267,16 @@ func preempt(
 				preemptees = append(preemptees, task.Clone())
 			}
 		}
-		victims := ssn.Preemptable(preemptor, preemptees)
+		candidates := ssn.Preemptable(preemptor, preemptees)
+		// The candidates of the node are checked before the victims are vetoed, so that the plugins vetoing victims
+		// are only called on the nodes the preemptor may fit.
+		if err := util.ValidateVictims(preemptor, node, candidates); err != nil {
+			klog.V(3).Infof("No validated victims on Node <%s>: %v", node.Name, err)
+			continue
+		}
+		victims := ssn.VetoSelectedVictims(preemptor, candidates, func(candidates []*api.TaskInfo) []*api.TaskInfo {
+			return selectVictims(ssn, preemptor, node, currentQueue, candidates)
+		})
 		metrics.UpdatePreemptionVictimsCount(len(victims))
 
 		if err := util.ValidateVictims(preemptor, node, victims); err != nil {
@@ -257,27 +284,15 @@ func preempt(
 			continue
 		}
 
-		victimsQueue := util.NewPriorityQueue(func(l, r interface{}) bool {
-			lv := l.(*api.TaskInfo)
-			rv := r.(*api.TaskInfo)
-			if lv.Job != rv.Job {
-				return !ssn.JobOrderFn(ssn.Jobs[lv.Job], ssn.Jobs[rv.Job])
-			}
-			return !ssn.TaskOrderFn(l, r)
-		})
-		for _, victim := range victims {
-			victimsQueue.Push(victim)
-		}
-		// Preempt victims for tasks, pick lowest priority task first.
+		// Preempt victims for tasks, lowest priority task first.
 		preempted := api.EmptyResource()
 
-		for !victimsQueue.Empty() {
+		for _, preemptee := range victims {
 			// If reclaimed enough resources, break loop to avoid Sub panic.
 			// If preemptor's queue is overused, it means preemptor can not be allcated. So no need care about the node idle resourace
 			if !ssn.Overused(currentQueue) && preemptor.InitResreq.LessEqual(node.FutureIdle(), api.Zero) {
 				break
 			}
-			preemptee := victimsQueue.Pop().(*api.TaskInfo)
 			klog.V(3).Infof("Try to preempt Task <%s/%s> for Task <%s/%s>",
 				preemptee.Namespace, preemptee.Name, preemptor.Namespace, preemptor.Name)
 			if err := stmt.Evict(preemptee, "preempt"); err != nil {
@@ -309,6 +324,49 @@ func preempt(
 	return assigned, nil
 }
 
+// selectVictims returns the victims to evict on node for preemptor from candidates, lowest priority task first,
+// until preemptor fits the future idle of node, or all of them if the queue of preemptor is overused.
+func selectVictims(ssn *framework.Session, preemptor *api.TaskInfo, node *api.NodeInfo, queue *api.QueueInfo,
+	candidates []*api.TaskInfo) []*api.TaskInfo {
+	victimsQueue := util.NewPriorityQueue(func(l, r interface{}) bool {
+		lv := l.(*api.TaskInfo)
+		rv := r.(*api.TaskInfo)
+		if lv.Job != rv.Job {
+			return !ssn.JobOrderFn(ssn.Jobs[lv.Job], ssn.Jobs[rv.Job])
+		}
+		return !ssn.TaskOrderFn(l, r)
+	})
+	for _, candidate := range candidates {
+		victimsQueue.Push(candidate)
+	}
+
+	var victims []*api.TaskInfo
+	futureIdle := node.FutureIdle()
+	for !victimsQueue.Empty() {
+		if !ssn.Overused(queue) && preemptor.InitResreq.LessEqual(futureIdle, api.Zero) {
+			break
+		}
+		victim := victimsQueue.Pop().(*api.TaskInfo)
+		victims = append(victims, victim)
+		futureIdle.Add(victim.Resreq)
+	}
+	return victims
+}
+
+// skipPreemptNever records in the fit errors of task that preemption is not attempted
+// as the PreemptionPolicy of its PriorityClass is Never.
+func skipPreemptNever(job *api.JobInfo, task *api.TaskInfo) {
+	klog.V(4).Infof("Task <%s/%s> skip preemption, reason: PreemptionPolicy is Never.",
+		task.Namespace, task.Name)
+
+	fitErrors := job.NodesFitErrors[task.UID]
+	if fitErrors == nil {
+		fitErrors = api.NewFitErrors()
+		job.NodesFitErrors[task.UID] = fitErrors
+	}
+	fitErrors.SetPreemptionError(api.PreemptionPolicyNeverMsg)
+}
+
 func victimTasks(ssn *framework.Session) {
 	stmt := framework.NewStatement(ssn)
 	tasks := make([]*api.TaskInfo, 0)
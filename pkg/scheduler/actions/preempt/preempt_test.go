@@ -282,6 +282,71 @@ func TestPreempt(t *testing.T) {
 			},
 			expected: 2,
 		},
+		{
+			name: "do not preempt for task whose preemption policy is never",
+			podGroups: []*schedulingv1beta1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: "c1",
+					},
+					Spec: schedulingv1beta1.PodGroupSpec{
+						MinMember: 1,
+						MinTaskMember: map[string]int32{
+							"": 3,
+						},
+						Queue:             "q1",
+						PriorityClassName: "low-priority",
+					},
+					Status: schedulingv1beta1.PodGroupStatus{
+						Phase: schedulingv1beta1.PodGroupInqueue,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: "c1",
+					},
+					Spec: schedulingv1beta1.PodGroupSpec{
+						MinMember: 1,
+						MinTaskMember: map[string]int32{
+							"": 1,
+						},
+						Queue:             "q1",
+						PriorityClassName: "high-priority",
+					},
+					Status: schedulingv1beta1.PodGroupStatus{
+						Phase: schedulingv1beta1.PodGroupInqueue,
+					},
+				},
+			},
+			// Big task would preempt 2 of 3 running tasks, but its PreemptionPolicy is Never.
+			pods: []*v1.Pod{
+				util.BuildPod("c1", "preemptee1", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", map[string]string{schedulingv1beta1.PodPreemptable: "true"}, make(map[string]string)),
+				util.BuildPod("c1", "preemptee2", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", map[string]string{schedulingv1beta1.PodPreemptable: "true"}, make(map[string]string)),
+				util.BuildPod("c1", "preemptee3", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", map[string]string{schedulingv1beta1.PodPreemptable: "true"}, make(map[string]string)),
+				func() *v1.Pod {
+					pod := util.BuildPod("c1", "preemptor1", "", v1.PodPending, util.BuildResourceList("5", "5G"), "pg2", make(map[string]string), make(map[string]string))
+					preemptNever := v1.PreemptNever
+					pod.Spec.PreemptionPolicy = &preemptNever
+					return pod
+				}(),
+			},
+			nodes: []*v1.Node{
+				util.BuildNode("n1", util.BuildResourceList("6", "6G"), make(map[string]string)),
+			},
+			queues: []*schedulingv1beta1.Queue{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "q1",
+					},
+					Spec: schedulingv1beta1.QueueSpec{
+						Weight: 1,
+					},
+				},
+			},
+			expected: 0,
+		},
 	}
 
 	preempt := New()
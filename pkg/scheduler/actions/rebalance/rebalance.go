@@ -0,0 +1,309 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rebalance
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+const (
+	// Rebalance indicates the action name
+	Rebalance = "rebalance"
+
+	// HighCPUWatermark and HighMEMWatermark are the argument keys of the cpu and memory usages of nodes, in
+	// percentage, above which tasks are evicted from them.
+	HighCPUWatermark = "highWatermark.cpu"
+	HighMEMWatermark = "highWatermark.memory"
+	// LowCPUWatermark and LowMEMWatermark are the argument keys of the cpu and memory usages of nodes, in
+	// percentage, below which nodes take the tasks evicted.
+	LowCPUWatermark = "lowWatermark.cpu"
+	LowMEMWatermark = "lowWatermark.memory"
+	// UsagePeriod is the argument key of the period of average usage reported by the metrics source.
+	UsagePeriod = "period"
+	// MaxEvictionsPerNode is the argument key of the max number of tasks evicted from a node in a session.
+	MaxEvictionsPerNode = "maxEvictionsPerNode"
+	// Cooldown is the argument key of the duration a node is not rebalanced again after tasks are evicted from it,
+	// for its usage to catch up with the evictions.
+	Cooldown = "cooldown"
+
+	defaultHighWatermark       = 80
+	defaultLowWatermark        = 50
+	defaultUsagePeriod         = "5m"
+	defaultMaxEvictionsPerNode = 1
+	defaultCooldown            = 5 * time.Minute
+)
+
+/*
+   actions: "enqueue, allocate, backfill, rebalance"
+   configurations:
+   - name: rebalance
+     arguments:
+       highWatermark.cpu: 80
+       highWatermark.memory: 80
+       lowWatermark.cpu: 50
+       lowWatermark.memory: 50
+       period: 5m
+       maxEvictionsPerNode: 1
+       cooldown: 5m
+*/
+
+// Action defines the action
+type Action struct {
+	// rebalanced is the last time tasks were evicted from nodes, kept across sessions
+	rebalanced map[string]time.Time
+}
+
+// New returns the action instance
+func New() *Action {
+	return &Action{rebalanced: map[string]time.Time{}}
+}
+
+// SnapshotState saves when nodes were rebalanced, and returns the function restoring it.
+func (rebalance *Action) SnapshotState() func() {
+	rebalanced := copyRebalanced(rebalance.rebalanced)
+	return func() {
+		rebalance.rebalanced = copyRebalanced(rebalanced)
+	}
+}
+
+func copyRebalanced(rebalanced map[string]time.Time) map[string]time.Time {
+	copied := make(map[string]time.Time, len(rebalanced))
+	for node, last := range rebalanced {
+		copied[node] = last
+	}
+	return copied
+}
+
+// Name returns the action name
+func (rebalance *Action) Name() string {
+	return Rebalance
+}
+
+// Initialize inits the action
+func (rebalance *Action) Initialize() {}
+
+// config is the configuration of the action in a session.
+type config struct {
+	highCPU, highMem    float64
+	lowCPU, lowMem      float64
+	period              string
+	maxEvictionsPerNode int
+	cooldown            time.Duration
+}
+
+func newConfig(arguments framework.Arguments) config {
+	c := config{
+		highCPU:             defaultHighWatermark,
+		highMem:             defaultHighWatermark,
+		lowCPU:              defaultLowWatermark,
+		lowMem:              defaultLowWatermark,
+		period:              defaultUsagePeriod,
+		maxEvictionsPerNode: defaultMaxEvictionsPerNode,
+		cooldown:            defaultCooldown,
+	}
+	arguments.GetFloat64(&c.highCPU, HighCPUWatermark)
+	arguments.GetFloat64(&c.highMem, HighMEMWatermark)
+	arguments.GetFloat64(&c.lowCPU, LowCPUWatermark)
+	arguments.GetFloat64(&c.lowMem, LowMEMWatermark)
+	arguments.GetString(&c.period, UsagePeriod)
+	arguments.GetInt(&c.maxEvictionsPerNode, MaxEvictionsPerNode)
+
+	var cooldown string
+	arguments.GetString(&cooldown, Cooldown)
+	if cooldown != "" {
+		if duration, err := time.ParseDuration(cooldown); err == nil && duration >= 0 {
+			c.cooldown = duration
+		} else {
+			klog.Warningf("Invalid %s %q of action %s, use default %v", Cooldown, cooldown, Rebalance, defaultCooldown)
+		}
+	}
+	return c
+}
+
+// usageOf returns the cpu and memory usages of the period of the node, and whether both are reported and fresh.
+func (c config) usageOf(node *api.NodeInfo) (float64, float64, bool) {
+	usage := node.ResourceUsage
+	if usage == nil || usage.Stale {
+		return 0, 0, false
+	}
+	cpu, cpuFound := usage.CPUUsageAvg[c.period]
+	mem, memFound := usage.MEMUsageAvg[c.period]
+	return cpu, mem, cpuFound && memFound
+}
+
+// Execute evicts best-effort and revocable tasks from the nodes whose real usages are above the high watermarks,
+// so that they are rescheduled onto the nodes below the low watermarks.
+func (rebalance *Action) Execute(ssn *framework.Session) {
+	klog.V(5).Infoln("Enter Rebalance ...")
+	defer klog.V(5).Infoln("Leaving Rebalance ...")
+
+	c := newConfig(framework.GetArgOfActionFromConf(ssn.Configurations, rebalance.Name()))
+	now := time.Now()
+	// The nodes deleted are not rebalanced again.
+	for name := range rebalance.rebalanced {
+		if _, found := ssn.Nodes[name]; !found {
+			delete(rebalance.rebalanced, name)
+		}
+	}
+
+	var hotNodes, coldNodes []*api.NodeInfo
+	for _, node := range ssn.Nodes {
+		cpu, mem, found := c.usageOf(node)
+		if !found {
+			continue
+		}
+		switch {
+		case cpu > c.highCPU || mem > c.highMem:
+			if last, found := rebalance.rebalanced[node.Name]; found && now.Sub(last) < c.cooldown {
+				klog.V(4).Infof("Node <%s> is over the high watermarks but rebalanced at %v, skip it", node.Name, last)
+				continue
+			}
+			hotNodes = append(hotNodes, node)
+		case cpu < c.lowCPU && mem < c.lowMem:
+			coldNodes = append(coldNodes, node)
+		}
+	}
+	if len(hotNodes) == 0 || len(coldNodes) == 0 {
+		klog.V(4).Infof("%d nodes are over the high watermarks and %d nodes are below the low watermarks, skip rebalance",
+			len(hotNodes), len(coldNodes))
+		return
+	}
+
+	// idle is the idle resource of the cold nodes left for the tasks evicted.
+	idle := map[string]*api.Resource{}
+	for _, node := range coldNodes {
+		idle[node.Name] = node.FutureIdle()
+	}
+	// ready is the number of ready tasks of jobs left after the evictions, which must not drop below minAvailable.
+	ready := map[api.JobID]int32{}
+	tasks := candidates(ssn)
+
+	for _, node := range hotNodes {
+		cpu, mem, _ := c.usageOf(node)
+		victims := util.NewPriorityQueue(func(l, r interface{}) bool {
+			return !ssn.TaskOrderFn(l, r)
+		})
+		for _, task := range ssn.Evictable(tasks[node.Name]) {
+			victims.Push(task)
+		}
+
+		evicted := 0
+		for !victims.Empty() && evicted < c.maxEvictionsPerNode && (cpu > c.highCPU || mem > c.highMem) {
+			victim := victims.Pop().(*api.TaskInfo)
+			job, found := ssn.Jobs[victim.Job]
+			if !found {
+				continue
+			}
+			if _, found := ready[job.UID]; !found {
+				ready[job.UID] = job.ReadyTaskNum()
+			}
+			if ready[job.UID] <= job.MinAvailable {
+				klog.V(4).Infof("Can not evict task <%s/%s> because job %s ready num(%d) <= MinAvailable(%d) for gang-scheduling",
+					victim.Namespace, victim.Name, job.Name, ready[job.UID], job.MinAvailable)
+				continue
+			}
+			target := targetNode(ssn, victim, coldNodes, idle)
+			if target == nil {
+				klog.V(4).Infof("No node below the low watermarks fits task <%s/%s>, skip it", victim.Namespace, victim.Name)
+				continue
+			}
+			// The victim is only vetoed once it is to be evicted.
+			if len(ssn.VetoVictims(nil, []*api.TaskInfo{victim})) == 0 {
+				continue
+			}
+
+			klog.V(3).Infof("Evict task <%s/%s> from node <%s> over the high watermarks for node <%s>",
+				victim.Namespace, victim.Name, node.Name, target.Name)
+			if err := ssn.Evict(victim, Rebalance); err != nil {
+				klog.Errorf("Failed to evict Task <%s/%s>: %v", victim.Namespace, victim.Name, err)
+				continue
+			}
+			idle[target.Name].Sub(victim.InitResreq)
+			ready[job.UID]--
+			evicted++
+			rebalance.rebalanced[node.Name] = now
+			cpuDrop, memDrop := usageOfTask(victim, node)
+			cpu, mem = cpu-cpuDrop, mem-memDrop
+		}
+	}
+}
+
+// candidates returns the running tasks of jobs which may be evicted for rebalance by node, i.e. best-effort tasks
+// and tasks of revocable zones.
+func candidates(ssn *framework.Session) map[string][]*api.TaskInfo {
+	tasks := map[string][]*api.TaskInfo{}
+	for _, job := range ssn.Jobs {
+		for _, task := range job.TaskStatusIndex[api.Running] {
+			if task.BestEffort || task.RevocableZone != "" {
+				tasks[task.NodeName] = append(tasks[task.NodeName], task)
+			}
+		}
+	}
+	return tasks
+}
+
+// targetNode returns a node below the low watermarks the task fits, nil if none.
+func targetNode(ssn *framework.Session, task *api.TaskInfo, nodes []*api.NodeInfo, idle map[string]*api.Resource) *api.NodeInfo {
+	for _, node := range nodes {
+		if node.Name == task.NodeName || !task.InitResreq.LessEqual(idle[node.Name], api.Zero) {
+			continue
+		}
+		var statusSets util.StatusSets
+		statusSets, err := ssn.PredicateFn(task, node)
+		if err != nil {
+			klog.V(5).Infof("Task <%s/%s> does not fit node <%s>: %v", task.Namespace, task.Name, node.Name, err)
+			continue
+		}
+		if statusSets.ContainsUnschedulable() || statusSets.ContainsUnschedulableAndUnresolvable() ||
+			statusSets.ContainsErrorSkipOrWait() {
+			klog.V(5).Infof("Task <%s/%s> does not fit node <%s>, status is not success", task.Namespace, task.Name, node.Name)
+			continue
+		}
+		return node
+	}
+	return nil
+}
+
+// usageOfTask returns the cpu and memory usages of the task in percentage of the node, by its real usage if the
+// node reports it, otherwise by its request.
+func usageOfTask(task *api.TaskInfo, node *api.NodeInfo) (float64, float64) {
+	milliCPU, memory := task.Resreq.MilliCPU, task.Resreq.Memory
+	if usage, found := node.ResourceUsage.PodUsages[task.Namespace+"/"+task.Name]; found {
+		milliCPU, memory = usage.MilliCPU, usage.Memory
+	}
+	total := node.Capacity
+	if total == nil || total.MilliCPU == 0 || total.Memory == 0 {
+		total = node.Allocatable
+	}
+	var cpu, mem float64
+	if total != nil && total.MilliCPU > 0 {
+		cpu = milliCPU / total.MilliCPU * 100
+	}
+	if total != nil && total.Memory > 0 {
+		mem = memory / total.Memory * 100
+	}
+	return cpu, mem
+}
+
+// UnInitialize releases resource which is not useful.
+func (rebalance *Action) UnInitialize() {}
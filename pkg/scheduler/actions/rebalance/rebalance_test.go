@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rebalance
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/priority"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestRebalance(t *testing.T) {
+	now := time.Now()
+	usage := func(cpu, mem float64) *api.NodeUsage {
+		return &api.NodeUsage{CPUUsageAvg: map[string]float64{"5m": cpu}, MEMUsageAvg: map[string]float64{"5m": mem}, SampleTime: now}
+	}
+	nodes := []*v1.Node{
+		util.BuildNode("hot", util.BuildResourceList("8", "16Gi"), nil),
+		util.BuildNode("warm", util.BuildResourceList("8", "16Gi"), nil),
+		util.BuildNode("cold", util.BuildResourceList("8", "16Gi"), nil),
+	}
+	bestEffort := v1.ResourceList{}
+	// Tasks of lower priority are evicted first by the task order of the priority plugin.
+	low, high := int32(1), int32(10)
+
+	tests := []struct {
+		name       string
+		arguments  framework.Arguments
+		podGroups  []*schedulingv1.PodGroup
+		pods       []*v1.Pod
+		usages     map[string]*api.NodeUsage
+		rebalanced map[string]time.Time
+		// rejectCold rejects the cold node by the statuses of predicate without error
+		rejectCold bool
+		evicted    []string
+	}{
+		{
+			name:      "best-effort task is evicted from the hot node",
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods: []*v1.Pod{
+				util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil),
+				util.BuildPod("ns", "prod", "hot", v1.PodRunning, util.BuildResourceList("1", "1Gi"), "pg1", nil, nil),
+			},
+			usages:  map[string]*api.NodeUsage{"hot": usage(90, 40), "warm": usage(60, 60), "cold": usage(20, 20)},
+			evicted: []string{"ns/be1"},
+		},
+		{
+			name:      "tasks are evicted up to max evictions per node",
+			arguments: framework.Arguments{MaxEvictionsPerNode: 3},
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods: []*v1.Pod{
+				util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil),
+				util.BuildPod("ns", "be2", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil),
+				util.BuildPod("ns", "be3", "warm", v1.PodRunning, bestEffort, "pg1", nil, nil),
+			},
+			usages:  map[string]*api.NodeUsage{"hot": usage(40, 90), "warm": usage(60, 60), "cold": usage(20, 20)},
+			evicted: []string{"ns/be1", "ns/be2"},
+		},
+		{
+			name:      "gang is kept above min available",
+			arguments: framework.Arguments{MaxEvictionsPerNode: 3},
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupRunning)},
+			pods: []*v1.Pod{
+				util.BuildPodWithPriority("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil, &low),
+				util.BuildPodWithPriority("ns", "be2", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil, &high),
+			},
+			usages:  map[string]*api.NodeUsage{"hot": usage(90, 90), "warm": usage(60, 60), "cold": usage(20, 20)},
+			evicted: []string{"ns/be1"},
+		},
+		{
+			name:       "target node rejected by the statuses of predicate",
+			podGroups:  []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods:       []*v1.Pod{util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil)},
+			usages:     map[string]*api.NodeUsage{"hot": usage(90, 40), "warm": usage(60, 60), "cold": usage(20, 20)},
+			rejectCold: true,
+			evicted:    []string{},
+		},
+		{
+			name:      "no node is below the low watermarks",
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods:      []*v1.Pod{util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil)},
+			usages:    map[string]*api.NodeUsage{"hot": usage(90, 40), "warm": usage(60, 60), "cold": usage(20, 60)},
+			evicted:   []string{},
+		},
+		{
+			name:      "stale node is not rebalanced",
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods:      []*v1.Pod{util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil)},
+			usages:    map[string]*api.NodeUsage{"hot": api.StaleNodeUsage(now), "warm": usage(60, 60), "cold": usage(20, 20)},
+			evicted:   []string{},
+		},
+		{
+			name:       "node in cooldown is not rebalanced",
+			podGroups:  []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods:       []*v1.Pod{util.BuildPod("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil)},
+			usages:     map[string]*api.NodeUsage{"hot": usage(90, 40), "warm": usage(60, 60), "cold": usage(20, 20)},
+			rebalanced: map[string]time.Time{"hot": now.Add(-time.Minute)},
+			evicted:    []string{},
+		},
+		{
+			// The real usage of the first task evicted brings the node below the high watermarks.
+			name:      "tasks are evicted until the usage is below the high watermarks",
+			arguments: framework.Arguments{MaxEvictionsPerNode: 3},
+			podGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 0, schedulingv1.PodGroupRunning)},
+			pods: []*v1.Pod{
+				util.BuildPodWithPriority("ns", "be1", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil, &low),
+				util.BuildPodWithPriority("ns", "be2", "hot", v1.PodRunning, bestEffort, "pg1", nil, nil, &high),
+			},
+			usages: map[string]*api.NodeUsage{"hot": {CPUUsageAvg: map[string]float64{"5m": 90}, MEMUsageAvg: map[string]float64{"5m": 40},
+				SampleTime: now, PodUsages: map[string]*api.PodUsage{"ns/be1": {MilliCPU: 2000}, "ns/be2": {MilliCPU: 2000}}},
+				"warm": usage(60, 60), "cold": usage(20, 20)},
+			evicted: []string{"ns/be1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugins := map[string]framework.PluginBuilder{priority.PluginName: priority.New}
+			if test.rejectCold {
+				plugins[rejectColdPluginName] = func(framework.Arguments) framework.Plugin { return &rejectColdPlugin{} }
+			}
+			c := uthelper.TestCommonStruct{
+				Name:           test.name,
+				Plugins:        plugins,
+				Configurations: []conf.Configuration{{Name: Rebalance, Arguments: test.arguments}},
+				PodGroups:      test.podGroups,
+				Pods:           test.pods,
+				Nodes:          nodes,
+				Queues:         []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+				NodeUsages:     test.usages,
+				ExpectEvicted:  test.evicted,
+			}
+			defer c.Close()
+			action := New()
+			for node, last := range test.rebalanced {
+				action.rebalanced[node] = last
+			}
+			// The nodes deleted are pruned.
+			action.rebalanced["deleted"] = now
+			c.Run(action)
+			if err := c.CheckAll(); err != nil {
+				t.Error(err)
+			}
+			if _, found := action.rebalanced["deleted"]; found {
+				t.Errorf("expected the deleted node pruned from the nodes rebalanced")
+			}
+		})
+	}
+}
+
+const rejectColdPluginName = "reject-cold"
+
+// rejectColdPlugin rejects the node cold by the statuses of predicate, without error.
+type rejectColdPlugin struct{}
+
+func (rp *rejectColdPlugin) Name() string {
+	return rejectColdPluginName
+}
+
+func (rp *rejectColdPlugin) OnSessionOpen(ssn *framework.Session) {
+	ssn.AddPredicateFn(rp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if node.Name == "cold" {
+			return []*api.Status{{Code: api.UnschedulableAndUnresolvable, Reason: "cold"}}, nil
+		}
+		return nil, nil
+	})
+}
+
+func (rp *rejectColdPlugin) OnSessionClose(ssn *framework.Session) {}
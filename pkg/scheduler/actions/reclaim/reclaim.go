@@ -54,6 +54,12 @@ func (ra *Action) Execute(ssn *framework.Session) {
 			continue
 		}
 
+		if ssn.JobPaused(job) {
+			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip reclaim, reason: scheduling is paused.",
+				job.Namespace, job.Name, job.Queue)
+			continue
+		}
+
 		if vr := ssn.JobValid(job); vr != nil && !vr.Pass {
 			klog.V(4).Infof("Job <%s/%s> Queue <%s> skip reclaim, reason: %v, message %v", job.Namespace, job.Name, job.Queue, vr.Reason, vr.Message)
 			continue
@@ -96,6 +102,11 @@ func (ra *Action) Execute(ssn *framework.Session) {
 			continue
 		}
 
+		if queue.Fill {
+			klog.V(3).Infof("Queue <%s> is a fill queue which never reclaims, ignore it.", queue.Name)
+			continue
+		}
+
 		// Found "high" priority job
 		jobs, found := preemptorsMap[queue.UID]
 		if !found || jobs.Empty() {
@@ -137,6 +148,11 @@ func (ra *Action) Execute(ssn *framework.Session) {
 					task.Namespace, task.Name, n.Name)
 				continue
 			}
+			if !ssn.InPreemptionDomain(job, n) {
+				klog.V(4).Infof("Node <%s> is out of the preemption domain of Job <%s/%s>, skip it.",
+					n.Name, job.Namespace, job.Name)
+				continue
+			}
 			klog.V(3).Infof("Considering Task <%s/%s> on Node <%s>.",
 				task.Namespace, task.Name, n.Name)
 
@@ -167,8 +183,16 @@ func (ra *Action) Execute(ssn *framework.Session) {
 				continue
 			}
 
-			victims := ssn.Reclaimable(task, reclaimees)
-
+			candidates := ssn.Reclaimable(task, reclaimees)
+			// The candidates of the node are checked before the victims are vetoed, so that the plugins vetoing
+			// victims are only called on the nodes the task may fit.
+			if err := util.ValidateVictims(task, n, candidates); err != nil {
+				klog.V(3).Infof("No validated victims on Node <%s>: %v", n.Name, err)
+				continue
+			}
+			victims := ssn.VetoSelectedVictims(task, candidates, func(candidates []*api.TaskInfo) []*api.TaskInfo {
+				return selectVictims(task, candidates)
+			})
 			if err := util.ValidateVictims(task, n, victims); err != nil {
 				klog.V(3).Infof("No validated victims on Node <%s>: %v", n.Name, err)
 				continue
@@ -216,5 +240,17 @@ func (ra *Action) Execute(ssn *framework.Session) {
 	}
 }
 
+// selectVictims returns the first candidates whose resource covers the request of task.
+func selectVictims(task *api.TaskInfo, candidates []*api.TaskInfo) []*api.TaskInfo {
+	reclaimed := api.EmptyResource()
+	for i, candidate := range candidates {
+		reclaimed.Add(candidate.Resreq)
+		if task.InitResreq.LessEqual(reclaimed, api.Zero) {
+			return candidates[:i+1]
+		}
+	}
+	return candidates
+}
+
 func (ra *Action) UnInitialize() {
 }
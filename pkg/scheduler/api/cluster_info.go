@@ -27,8 +27,10 @@ type ClusterInfo struct {
 	Queues         map[QueueID]*QueueInfo
 	NamespaceInfo  map[NamespaceName]*NamespaceInfo
 	RevocableNodes map[string]*NodeInfo
-	NodeList       []string
-	CSINodesStatus map[string]*CSINodeStatusInfo
+	// QuarantinedNodes are the nodes quarantined for bind failures, they are not in Nodes and not scheduled
+	QuarantinedNodes map[string]*NodeInfo
+	NodeList         []string
+	CSINodesStatus   map[string]*CSINodeStatusInfo
 }
 
 func (ci ClusterInfo) String() string {
@@ -17,13 +17,10 @@ limitations under the License.
 package gpushare
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
@@ -130,21 +127,11 @@ func (gs *GPUDevices) HasDeviceRequest(pod *v1.Pod) bool {
 	return false
 }
 
-func (gs *GPUDevices) Release(kubeClient kubernetes.Interface, pod *v1.Pod) error {
-	ids := GetGPUIndex(pod)
-	patch := RemoveGPUIndexPatch()
-	_, err := kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.JSONPatchType, []byte(patch), metav1.PatchOptions{})
-	if err != nil {
-		return errors.Errorf("patch pod %s failed with patch %s: %v", pod.Name, patch, err)
+func (gs *GPUDevices) Release(pod *v1.Pod) error {
+	// The GPU index annotation is not on the pod until it is bound, so lookup the devices by pod UID
+	for _, dev := range gs.Device {
+		delete(dev.PodMap, string(pod.UID))
 	}
-
-	for _, id := range ids {
-		if dev, ok := gs.Device[id]; ok {
-			delete(dev.PodMap, string(pod.UID))
-		}
-	}
-
-	klog.V(4).Infof("predicates with gpu sharing, update pod %s/%s deallocate from node [%s]", pod.Namespace, pod.Name, gs.Name)
 	return nil
 }
 
@@ -172,51 +159,48 @@ func (gs *GPUDevices) GetStatus() string {
 	return ""
 }
 
-func (gs *GPUDevices) Allocate(kubeClient kubernetes.Interface, pod *v1.Pod) error {
+func (gs *GPUDevices) Allocate(pod *v1.Pod) (map[string]string, error) {
 	klog.V(4).Infoln("DeviceSharing:Into AllocateToPod", pod.Name)
+	var annotations map[string]string
 	if getGPUMemoryOfPod(pod) > 0 {
-		if NodeLockEnable {
-			nodelock.UseClient(kubeClient)
-			err := nodelock.LockNode(gs.Name, "gpu")
-			if err != nil {
-				return errors.Errorf("node %s locked for lockname gpushare %s", gs.Name, err.Error())
-			}
-		}
 		ids := predicateGPUbyMemory(pod, gs)
 		if len(ids) == 0 {
-			return errors.Errorf("the node %s can't place the pod %s in ns %s", pod.Spec.NodeName, pod.Name, pod.Namespace)
+			return nil, errors.Errorf("the node %s can't place the pod %s in ns %s", pod.Spec.NodeName, pod.Name, pod.Namespace)
 		}
 		id := ids[0]
-		patch := AddGPUIndexPatch([]int{id})
-		pod, err := kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.JSONPatchType, []byte(patch), metav1.PatchOptions{})
-		if err != nil {
-			return errors.Errorf("patch pod %s failed with patch %s: %v", pod.Name, patch, err)
-		}
 		dev, ok := gs.Device[id]
 		if !ok {
-			return errors.Errorf("failed to get GPU %d from node %s", id, gs.Name)
+			return nil, errors.Errorf("failed to get GPU %d from node %s", id, gs.Name)
 		}
 		dev.PodMap[string(pod.UID)] = pod
+		annotations = GPUIndexAnnotations([]int{id})
 		klog.V(4).Infof("predicates with gpu sharing, update pod %s/%s allocate to node [%s]", pod.Namespace, pod.Name, gs.Name)
 	}
 	if getGPUNumberOfPod(pod) > 0 {
 		ids := predicateGPUbyNumber(pod, gs)
 		if len(ids) == 0 {
-			return errors.Errorf("the node %s can't place the pod %s in ns %s", pod.Spec.NodeName, pod.Name, pod.Namespace)
-		}
-		patch := AddGPUIndexPatch(ids)
-		pod, err := kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.JSONPatchType, []byte(patch), metav1.PatchOptions{})
-		if err != nil {
-			return errors.Errorf("patch pod %s failed with patch %s: %v", pod.Name, patch, err)
+			return nil, errors.Errorf("the node %s can't place the pod %s in ns %s", pod.Spec.NodeName, pod.Name, pod.Namespace)
 		}
 		for _, id := range ids {
 			dev, ok := gs.Device[id]
 			if !ok {
-				return errors.Errorf("failed to get GPU %d from node %s", id, gs.Name)
+				return nil, errors.Errorf("failed to get GPU %d from node %s", id, gs.Name)
 			}
 			dev.PodMap[string(pod.UID)] = pod
 		}
+		annotations = GPUIndexAnnotations(ids)
 		klog.V(4).Infof("predicates with gpu number, update pod %s/%s allocate to node [%s]", pod.Namespace, pod.Name, gs.Name)
 	}
+	return annotations, nil
+}
+
+func (gs *GPUDevices) LockNode(kubeClient kubernetes.Interface, pod *v1.Pod) error {
+	if !NodeLockEnable || getGPUMemoryOfPod(pod) == 0 {
+		return nil
+	}
+	nodelock.UseClient(kubeClient)
+	if err := nodelock.LockNode(gs.Name, "gpu"); err != nil {
+		return errors.Errorf("node %s locked for lockname gpushare %s", gs.Name, err.Error())
+	}
 	return nil
 }
@@ -173,26 +173,12 @@ func predicateGPUbyNumber(pod *v1.Pod, gs *GPUDevices) []int {
 	return allocatableGPUs[:gpuRequest]
 }
 
-func escapeJSONPointer(p string) string {
-	// Escaping reference name using https://tools.ietf.org/html/rfc6901
-	p = strings.Replace(p, "~", "~0", -1)
-	p = strings.Replace(p, "/", "~1", -1)
-	return p
-}
-
-// AddGPUIndexPatch returns the patch adding GPU index
-func AddGPUIndexPatch(ids []int) string {
-	idsstring := strings.Trim(strings.Replace(fmt.Sprint(ids), " ", ",", -1), "[]")
-	return fmt.Sprintf(`[{"op": "add", "path": "/metadata/annotations/%s", "value":"%d"},`+
-		`{"op": "add", "path": "/metadata/annotations/%s", "value": "%s"}]`,
-		escapeJSONPointer(PredicateTime), time.Now().UnixNano(),
-		escapeJSONPointer(GPUIndex), idsstring)
-}
-
-// RemoveGPUIndexPatch returns the patch removing GPU index
-func RemoveGPUIndexPatch() string {
-	return fmt.Sprintf(`[{"op": "remove", "path": "/metadata/annotations/%s"},`+
-		`{"op": "remove", "path": "/metadata/annotations/%s"}]`, escapeJSONPointer(PredicateTime), escapeJSONPointer(GPUIndex))
+// GPUIndexAnnotations returns the annotations recording the GPU index allocated to the pod
+func GPUIndexAnnotations(ids []int) map[string]string {
+	return map[string]string{
+		PredicateTime: strconv.FormatInt(time.Now().UnixNano(), 10),
+		GPUIndex:      strings.Trim(strings.Replace(fmt.Sprint(ids), " ", ",", -1), "[]"),
+	}
 }
 
 // getUsedGPUMemory calculates the used memory of the device.
@@ -174,7 +174,7 @@ func (gs *GPUDevices) HasDeviceRequest(pod *v1.Pod) bool {
 	return false
 }
 
-func (gs *GPUDevices) Release(kubeClient kubernetes.Interface, pod *v1.Pod) error {
+func (gs *GPUDevices) Release(pod *v1.Pod) error {
 	// Nothing needs to be done here
 	return nil
 }
@@ -196,35 +196,36 @@ func (gs *GPUDevices) GetStatus() string {
 	return ""
 }
 
-func (gs *GPUDevices) Allocate(kubeClient kubernetes.Interface, pod *v1.Pod) error {
+func (gs *GPUDevices) Allocate(pod *v1.Pod) (map[string]string, error) {
 	klog.V(3).Infoln("VGPU DeviceSharing:Into AllocateToPod", pod.Name)
-	if VGPUEnable {
-		fit, device, err := checkNodeGPUSharingPredicate(pod, gs, false)
-		if err != nil || !fit {
-			klog.Errorln("DeviceSharing err=", err.Error())
-			return err
-		}
-		if NodeLockEnable {
-			nodelock.UseClient(kubeClient)
-			err = nodelock.LockNode(gs.Name, DeviceName)
-			if err != nil {
-				return errors.Errorf("node %s locked for lockname gpushare %s", gs.Name, err.Error())
-			}
-		}
+	if !VGPUEnable {
+		return nil, nil
+	}
+	fit, device, err := checkNodeGPUSharingPredicate(pod, gs, false)
+	if err != nil || !fit {
+		klog.Errorln("DeviceSharing err=", err.Error())
+		return nil, err
+	}
 
-		annotations := make(map[string]string)
-		annotations[AssignedNodeAnnotations] = gs.Name
-		annotations[AssignedTimeAnnotations] = strconv.FormatInt(time.Now().Unix(), 10)
-		annotations[AssignedIDsAnnotations] = encodePodDevices(device)
-		annotations[AssignedIDsToAllocateAnnotations] = annotations[AssignedIDsAnnotations]
-
-		annotations[DeviceBindPhase] = "allocating"
-		annotations[BindTimeAnnotations] = strconv.FormatInt(time.Now().Unix(), 10)
-		err = patchPodAnnotations(pod, annotations)
-		if err != nil {
-			return err
-		}
-		klog.V(3).Infoln("DeviceSharing:Allocate Success")
+	annotations := make(map[string]string)
+	annotations[AssignedNodeAnnotations] = gs.Name
+	annotations[AssignedTimeAnnotations] = strconv.FormatInt(time.Now().Unix(), 10)
+	annotations[AssignedIDsAnnotations] = encodePodDevices(device)
+	annotations[AssignedIDsToAllocateAnnotations] = annotations[AssignedIDsAnnotations]
+
+	annotations[DeviceBindPhase] = "allocating"
+	annotations[BindTimeAnnotations] = strconv.FormatInt(time.Now().Unix(), 10)
+	klog.V(3).Infoln("DeviceSharing:Allocate Success")
+	return annotations, nil
+}
+
+func (gs *GPUDevices) LockNode(kubeClient kubernetes.Interface, pod *v1.Pod) error {
+	if !VGPUEnable || !NodeLockEnable {
+		return nil
+	}
+	nodelock.UseClient(kubeClient)
+	if err := nodelock.LockNode(gs.Name, DeviceName); err != nil {
+		return errors.Errorf("node %s locked for lockname gpushare %s", gs.Name, err.Error())
 	}
 	return nil
 }
@@ -0,0 +1,28 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import "sync/atomic"
+
+// lastGeneration is the last generation given to nodes, jobs and queues.
+var lastGeneration uint64
+
+// nextGeneration returns a generation no node, job or queue has had before, so that the same generation of two
+// of them, e.g. an object and its clone, means the same state.
+func nextGeneration() uint64 {
+	return atomic.AddUint64(&lastGeneration, 1)
+}
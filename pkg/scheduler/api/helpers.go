@@ -17,10 +17,15 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	clientcache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 )
 
 // PodKey returns the string key of a pod.
@@ -100,5 +105,235 @@ func MergeErrors(errs ...error) error {
 
 // JobTerminated checks whether job was terminated.
 func JobTerminated(job *JobInfo) bool {
-	return job.PodGroup == nil && len(job.Tasks) == 0
+	return job.PodGroup == nil && len(job.Tasks) == 0 && job.CompactedTaskNum() == 0
+}
+
+// IsSchedulingPaused checks whether the scheduling.volcano.sh/paused annotation is set to true.
+func IsSchedulingPaused(annotations map[string]string) bool {
+	value, found := annotations[SchedulingPausedAnnotation]
+	if !found {
+		return false
+	}
+
+	paused, err := strconv.ParseBool(value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", SchedulingPausedAnnotation, value)
+		return false
+	}
+	return paused
+}
+
+// IsFillQueue checks whether the queue is a best-effort fill queue by scheduling.volcano.sh/queue-type annotation.
+func IsFillQueue(annotations map[string]string) bool {
+	return annotations[QueueTypeAnnotation] == QueueTypeFill
+}
+
+// GetVictimGracePeriod returns the value of scheduling.volcano.sh/victim-grace-period-seconds annotation,
+// nil means not set.
+func GetVictimGracePeriod(annotations map[string]string) *int64 {
+	value, found := annotations[VictimGracePeriodAnnotation]
+	if !found {
+		return nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		klog.Warningf("invalid %s=%s", VictimGracePeriodAnnotation, value)
+		return nil
+	}
+	return &seconds
+}
+
+// IsNoEvict checks whether the pod is invisible to preempt and reclaim by the no-evict label of scheduler configuration.
+func IsNoEvict(pod *v1.Pod, label string) bool {
+	return len(label) != 0 && pod != nil && pod.Labels[label] == "true"
+}
+
+// GetMaxPerNode returns the value of scheduling.volcano.sh/max-per-node annotation, 0 means no limit.
+func GetMaxPerNode(annotations map[string]string) int {
+	value, found := annotations[MaxPerNodeAnnotation]
+	if !found {
+		return 0
+	}
+
+	maxPerNode, err := strconv.Atoi(value)
+	if err != nil || maxPerNode < 0 {
+		klog.Warningf("invalid %s=%s", MaxPerNodeAnnotation, value)
+		return 0
+	}
+	return maxPerNode
+}
+
+// GetBindOrder returns the value of scheduling.volcano.sh/bind-order annotation and whether it is set.
+func GetBindOrder(annotations map[string]string) (int, bool) {
+	value, found := annotations[BindOrderAnnotation]
+	if !found {
+		return 0, false
+	}
+
+	order, err := strconv.Atoi(value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", BindOrderAnnotation, value)
+		return 0, false
+	}
+	return order, true
+}
+
+// IsBindRankDescending checks whether tasks are bound towards rank 0 by scheduling.volcano.sh/bind-rank-order annotation.
+func IsBindRankDescending(annotations map[string]string) bool {
+	value, found := annotations[BindRankOrderAnnotation]
+	if !found {
+		return false
+	}
+
+	switch value {
+	case BindRankOrderAscending:
+		return false
+	case BindRankOrderDescending:
+		return true
+	default:
+		klog.Warningf("invalid %s=%s", BindRankOrderAnnotation, value)
+		return false
+	}
+}
+
+// GetResourceShapes returns the resource shapes of scheduling.volcano.sh/resource-shapes annotation,
+// invalid annotations are ignored.
+func GetResourceShapes(annotations map[string]string) []*Resource {
+	value, found := annotations[ResourceShapesAnnotation]
+	if !found {
+		return nil
+	}
+
+	var resourceLists []v1.ResourceList
+	if err := json.Unmarshal([]byte(value), &resourceLists); err != nil {
+		klog.Warningf("invalid %s=%s: %v", ResourceShapesAnnotation, value, err)
+		return nil
+	}
+	shapes := make([]*Resource, 0, len(resourceLists))
+	for _, resourceList := range resourceLists {
+		shapes = append(shapes, NewResource(resourceList))
+	}
+	return shapes
+}
+
+// GetResourceShape returns the value of scheduling.volcano.sh/resource-shape annotation, 0 if it is not
+// the index of one of the shapes.
+func GetResourceShape(annotations map[string]string, shapes int) int {
+	value, found := annotations[ResourceShapeAnnotation]
+	if !found {
+		return 0
+	}
+
+	shape, err := strconv.Atoi(value)
+	if err != nil || shape < 0 || shape >= shapes {
+		klog.Warningf("invalid %s=%s", ResourceShapeAnnotation, value)
+		return 0
+	}
+	return shape
+}
+
+// GetNodeFeatures returns the node features required by the scheduling.volcano.sh/node-arch and
+// scheduling.volcano.sh/node-features annotations, invalid annotations are ignored.
+func GetNodeFeatures(annotations map[string]string) []NodeFeature {
+	features, err := ParseNodeFeatures(annotations)
+	if err != nil {
+		klog.Warningf("invalid node features: %v", err)
+		return nil
+	}
+	return features
+}
+
+// GetMinRuntime returns the value of scheduling.volcano.sh/min-runtime annotation, 0 means no protection.
+func GetMinRuntime(annotations map[string]string) time.Duration {
+	value, found := annotations[MinRuntimeAnnotation]
+	if !found {
+		return 0
+	}
+
+	minRuntime, err := time.ParseDuration(value)
+	if err != nil || minRuntime < 0 {
+		klog.Warningf("invalid %s=%s", MinRuntimeAnnotation, value)
+		return 0
+	}
+	return minRuntime
+}
+
+// GetGangSchedulingTimeout returns the value of scheduling.volcano.sh/gang-scheduling-timeout annotation, 0 means
+// the gang waits forever.
+func GetGangSchedulingTimeout(annotations map[string]string) time.Duration {
+	value, found := annotations[GangSchedulingTimeoutAnnotation]
+	if !found {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		klog.Warningf("invalid %s=%s", GangSchedulingTimeoutAnnotation, value)
+		return 0
+	}
+	return timeout
+}
+
+// GetGangFallbackMinMember returns the value of scheduling.volcano.sh/gang-fallback-min-member annotation, 0 means
+// no fallback.
+func GetGangFallbackMinMember(annotations map[string]string) int32 {
+	value, found := annotations[GangFallbackMinMemberAnnotation]
+	if !found {
+		return 0
+	}
+
+	minMember, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || minMember < 0 {
+		klog.Warningf("invalid %s=%s", GangFallbackMinMemberAnnotation, value)
+		return 0
+	}
+	return int32(minMember)
+}
+
+// IsNodeStabilitySensitive checks whether the scheduling.volcano.sh/node-stability-sensitive annotation is set to true,
+// it follows whether the job is a gang job of more than one member if the annotation is not set.
+func IsNodeStabilitySensitive(annotations map[string]string, minMember int32) bool {
+	value, found := annotations[NodeStabilitySensitiveAnnotation]
+	if !found {
+		return minMember > 1
+	}
+
+	sensitive, err := strconv.ParseBool(value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", NodeStabilitySensitiveAnnotation, value)
+		return minMember > 1
+	}
+	return sensitive
+}
+
+// IsExclusiveNode checks whether the scheduling.volcano.sh/exclusive-node annotation is set to true.
+func IsExclusiveNode(annotations map[string]string) bool {
+	value, found := annotations[ExclusiveNodeAnnotation]
+	if !found {
+		return false
+	}
+
+	exclusive, err := strconv.ParseBool(value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", ExclusiveNodeAnnotation, value)
+		return false
+	}
+	return exclusive
+}
+
+// GetExcludedNodes returns the nodes listed in the scheduling.volcano.sh/excluded-nodes annotation.
+func GetExcludedNodes(annotations map[string]string) map[string]struct{} {
+	value, found := annotations[ExcludedNodesAnnotation]
+	if !found || len(value) == 0 {
+		return nil
+	}
+
+	nodes := map[string]struct{}{}
+	for _, node := range strings.Split(value, ",") {
+		if node = strings.TrimSpace(node); len(node) != 0 {
+			nodes[node] = struct{}{}
+		}
+	}
+	return nodes
 }
@@ -63,6 +63,53 @@ func (db *DisruptionBudget) Clone() *DisruptionBudget {
 // when job waits longer than waiting time, it should enqueue at once, and cluster should reserve resources for it
 const JobWaitingTime = "sla-waiting-time"
 
+const (
+	// PodGroupResourcesSufficientType is the type of podgroup condition which reports whether the cluster
+	// has sufficient resources for the podgroup, so external controllers can provision or borrow resources.
+	PodGroupResourcesSufficientType scheduling.PodGroupConditionType = "ResourcesSufficient"
+	// ScaleNeededReason is the reason of ResourcesSufficient condition if resources are insufficient,
+	// the message of the condition is the missing resources.
+	ScaleNeededReason = "ScaleNeeded"
+
+	// PodGroupPreemptionProtectedType is the type of podgroup condition which reports whether the tasks of
+	// the podgroup are protected from preemption and reclaim by its min runtime, the message of the condition
+	// tells until when.
+	PodGroupPreemptionProtectedType scheduling.PodGroupConditionType = "PreemptionProtected"
+	// MinRuntimeReason is the reason of PreemptionProtected condition while the min runtime is not elapsed.
+	MinRuntimeReason = "MinRuntime"
+	// MinRuntimeElapsedReason is the reason of PreemptionProtected condition once the min runtime is elapsed.
+	MinRuntimeElapsedReason = "MinRuntimeElapsed"
+
+	// PodGroupEvictedType is the type of podgroup condition which reports the evictions of the tasks of the podgroup
+	// by the scheduler, its last transition time is when they are evicted last, or when the podgroup is protected
+	// from starvation.
+	PodGroupEvictedType scheduling.PodGroupConditionType = "Evicted"
+	// EvictedReason is the reason of Evicted condition while the job is not protected from starvation.
+	EvictedReason = "Evicted"
+	// PodGroupEvictionsAnnotation counts how many times the tasks of the podgroup are evicted by the scheduler since
+	// the podgroup is last protected from starvation.
+	PodGroupEvictionsAnnotation = "scheduling.volcano.sh/evictions"
+	// StarvationProtectedReason is the reason of Evicted condition while the job is evicted too often and is
+	// protected from preemption and reclaim, the message of the condition tells until when.
+	StarvationProtectedReason = "StarvationProtected"
+
+	// PodGroupInqueueType is the type of podgroup condition which reports when the podgroup is enqueued by the
+	// scheduler, its last transition time is when the podgroup is enqueued last.
+	PodGroupInqueueType scheduling.PodGroupConditionType = "Inqueue"
+	// EnqueuedReason is the reason of Inqueue condition.
+	EnqueuedReason = "Enqueued"
+)
+
+// inqueueTransitionTime returns when pg is enqueued last by its Inqueue condition, zero if it has none.
+func inqueueTransitionTime(pg *PodGroup) time.Time {
+	for _, condition := range pg.Status.Conditions {
+		if condition.Type == PodGroupInqueueType {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
 // TaskID is UID type for Task
 type TaskID types.UID
 
@@ -126,10 +173,27 @@ type TaskInfo struct {
 	// empty value means workload can not use revocable node
 	// * value means workload can use all the revocable node for during node active revocable time.
 	RevocableZone string
+	// ExclusiveNode means the task requests whole node by scheduling.volcano.sh/exclusive-node annotation
+	ExclusiveNode bool
+	// MaxPerNode is the max number of tasks of the same task role of the job on one node, 0 means no limit
+	MaxPerNode int
+	// NodeFeatures are the architectures and node features required by the scheduling.volcano.sh/node-arch
+	// and scheduling.volcano.sh/node-features annotations
+	NodeFeatures []NodeFeature
+	// ResourceShapes are the alternative resources requested by the task besides the pod requests in preference
+	// order by the scheduling.volcano.sh/resource-shapes annotation, ResourceShape is the index of the one requested
+	ResourceShapes []*Resource
+	ResourceShape  int
+	// PreemptNever means the PriorityClass of pod has PreemptionPolicy Never, so the task never preempts others
+	PreemptNever bool
+	// Class is the class of pod, e.g. a DaemonSet or static pod, in the accounting of nodes
+	Class PodClass
 
 	NumaInfo   *TopologyInfo
 	PodVolumes *volumescheduling.PodVolumes
-	Pod        *v1.Pod
+	// DeviceAnnotations records the devices allocated to the task, they are written to the pod before binding
+	DeviceAnnotations map[string]string
+	Pod               *v1.Pod
 }
 
 func getJobID(pod *v1.Pod) JobID {
@@ -175,6 +239,11 @@ func NewTaskInfo(pod *v1.Pod) *TaskInfo {
 		Preemptable:   preemptable,
 		BestEffort:    bestEffort,
 		RevocableZone: revocableZone,
+		ExclusiveNode: IsExclusiveNode(pod.Annotations),
+		MaxPerNode:    GetMaxPerNode(pod.Annotations),
+		NodeFeatures:  GetNodeFeatures(pod.Annotations),
+		PreemptNever:  pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever,
+		Class:         GetPodClass(pod),
 		NumaInfo:      topologyInfo,
 		TransactionContext: TransactionContext{
 			NodeName: pod.Spec.NodeName,
@@ -182,6 +251,11 @@ func NewTaskInfo(pod *v1.Pod) *TaskInfo {
 		},
 	}
 
+	if shapes := GetResourceShapes(pod.Annotations); len(shapes) != 0 {
+		ti.ResourceShapes = shapes
+		ti.setResourceShape(GetResourceShape(pod.Annotations, len(shapes)))
+	}
+
 	if pod.Spec.Priority != nil {
 		ti.Priority = *pod.Spec.Priority
 	}
@@ -230,6 +304,22 @@ func (ti *TaskInfo) SetPodResourceDecision() error {
 	return nil
 }
 
+// setResourceShape requests the resource shape of the index besides the pod requests.
+func (ti *TaskInfo) setResourceShape(shape int) {
+	ti.ResourceShape = shape
+	ti.InitResreq = GetPodResourceRequest(ti.Pod).Add(ti.ResourceShapes[shape])
+	ti.Resreq = ti.InitResreq.Clone()
+	ti.BestEffort = ti.InitResreq.IsEmpty()
+}
+
+// SetPodResourceShape records the resource shape allocated to the task in the pod annotations.
+func (ti *TaskInfo) SetPodResourceShape() {
+	if len(ti.ResourceShapes) == 0 {
+		return
+	}
+	metav1.SetMetaDataAnnotation(&ti.Pod.ObjectMeta, ResourceShapeAnnotation, strconv.Itoa(ti.ResourceShape))
+}
+
 func (ti *TaskInfo) UnsetPodResourceDecision() {
 	delete(ti.Pod.Annotations, topologyDecisionAnnotation)
 }
@@ -250,12 +340,21 @@ func (ti *TaskInfo) Clone() *TaskInfo {
 		Preemptable:   ti.Preemptable,
 		BestEffort:    ti.BestEffort,
 		RevocableZone: ti.RevocableZone,
+		ExclusiveNode: ti.ExclusiveNode,
+		MaxPerNode:    ti.MaxPerNode,
+		NodeFeatures:  ti.NodeFeatures,
+		PreemptNever:  ti.PreemptNever,
+		Class:         ti.Class,
 		NumaInfo:      ti.NumaInfo.Clone(),
+
+		ResourceShapes: ti.ResourceShapes,
+		ResourceShape:  ti.ResourceShape,
 		TransactionContext: TransactionContext{
 			NodeName: ti.NodeName,
 			Status:   ti.Status,
 		},
-		LastTransaction: ti.LastTransaction.Clone(),
+		LastTransaction:   ti.LastTransaction.Clone(),
+		DeviceAnnotations: ti.DeviceAnnotations,
 	}
 }
 
@@ -311,6 +410,9 @@ type JobInfo struct {
 	Tasks                 tasksMap
 	TaskMinAvailable      map[TaskID]int32
 	TaskMinAvailableTotal int32
+	// CompactedTasks are the numbers of completed tasks dropped from Tasks to cap memory, by status and task,
+	// their requests are still in TotalRequest.
+	CompactedTasks map[TaskStatus]map[TaskID]int32
 
 	Allocated    *Resource
 	TotalRequest *Resource
@@ -328,12 +430,52 @@ type JobInfo struct {
 	// * value means workload can use all the revocable node for during node active revocable time.
 	RevocableZone string
 	Budget        *DisruptionBudget
+
+	// Paused means the scheduling of job is frozen by scheduling.volcano.sh/paused annotation
+	Paused bool
+	// ExcludedNodes are the nodes listed by scheduling.volcano.sh/excluded-nodes annotation,
+	// tasks of the job must not be scheduled to them
+	ExcludedNodes map[string]struct{}
+	// ExclusiveNode means tasks of the job request whole nodes by scheduling.volcano.sh/exclusive-node annotation
+	ExclusiveNode bool
+	// SchedulingProfile is the scheduling profile of scheduling.volcano.sh/scheduling-profile annotation
+	SchedulingProfile string
+	// NodeStabilitySensitive means tasks of the job prefer nodes without recent Ready flaps or restarts
+	NodeStabilitySensitive bool
+	// NotBefore is the time of scheduling.volcano.sh/not-before annotation, before which the job is not enqueued
+	NotBefore *time.Time
+	// ScheduleWindow is the window of scheduling.volcano.sh/schedule-window annotation, out of which the job is not enqueued
+	ScheduleWindow *ScheduleWindow
+	// GangSchedulingTimeout is the duration of scheduling.volcano.sh/gang-scheduling-timeout annotation, after which
+	// the job is sent back to pending or starts with GangFallbackMinMember tasks if it is not ready
+	GangSchedulingTimeout time.Duration
+	// GangFallbackMinMember is the value of scheduling.volcano.sh/gang-fallback-min-member annotation
+	GangFallbackMinMember int32
+	// InqueueTime is the time the job is seen enqueued by the scheduler, it is reset when the job is pending again
+	InqueueTime time.Time
+	// ReadyVetoedBy is the plugin which vetoed the readiness of the job when it was last checked, it is kept by
+	// the scheduler cache across sessions so the veto is only reported when it changes
+	ReadyVetoedBy string
+
+	// generation is renewed on each change of job by its methods or Touch, to tell whether a clone is outdated.
+	generation uint64
+}
+
+// Generation returns the generation of job, which is renewed on each change of job and kept by Clone.
+func (ji *JobInfo) Generation() uint64 {
+	return ji.generation
+}
+
+// Touch renews the generation of job, it must be called after changing the fields of job directly.
+func (ji *JobInfo) Touch() {
+	ji.generation = nextGeneration()
 }
 
 // NewJobInfo creates a new jobInfo for set of tasks
 func NewJobInfo(uid JobID, tasks ...*TaskInfo) *JobInfo {
 	job := &JobInfo{
 		UID:              uid,
+		generation:       nextGeneration(),
 		MinAvailable:     0,
 		NodesFitErrors:   make(map[TaskID]*FitErrors),
 		Allocated:        EmptyResource(),
@@ -352,11 +494,13 @@ func NewJobInfo(uid JobID, tasks ...*TaskInfo) *JobInfo {
 
 // UnsetPodGroup removes podGroup details from a job
 func (ji *JobInfo) UnsetPodGroup() {
+	ji.generation = nextGeneration()
 	ji.PodGroup = nil
 }
 
 // SetPodGroup sets podGroup details to a job
 func (ji *JobInfo) SetPodGroup(pg *PodGroup) {
+	ji.generation = nextGeneration()
 	ji.Name = pg.Name
 	ji.Namespace = pg.Namespace
 	ji.MinAvailable = pg.Spec.MinMember
@@ -382,7 +526,25 @@ func (ji *JobInfo) SetPodGroup(pg *PodGroup) {
 	ji.Preemptable = ji.extractPreemptable(pg)
 	ji.RevocableZone = ji.extractRevocableZone(pg)
 	ji.Budget = ji.extractBudget(pg)
+	ji.Paused = IsSchedulingPaused(pg.Annotations)
+	ji.ExcludedNodes = GetExcludedNodes(pg.Annotations)
+	ji.ExclusiveNode = IsExclusiveNode(pg.Annotations)
+	ji.NodeStabilitySensitive = IsNodeStabilitySensitive(pg.Annotations, pg.Spec.MinMember)
+	ji.SchedulingProfile = pg.Annotations[SchedulingProfileAnnotation]
+	ji.NotBefore = GetNotBefore(pg.Annotations)
+	ji.ScheduleWindow = GetScheduleWindow(pg.Annotations)
+	ji.GangSchedulingTimeout = GetGangSchedulingTimeout(pg.Annotations)
+	ji.GangFallbackMinMember = GetGangFallbackMinMember(pg.Annotations)
+	if pg.Status.Phase == "" || pg.Status.Phase == scheduling.PodGroupPending {
+		ji.InqueueTime = time.Time{}
+	} else if enqueued := inqueueTransitionTime(pg); !enqueued.IsZero() {
+		ji.InqueueTime = enqueued
+	} else if ji.InqueueTime.IsZero() {
+		ji.InqueueTime = time.Now()
+	}
 
+	// Rebuild TaskMinAvailable, so the tasks removed from a scaled job are not waited for by its gang.
+	ji.TaskMinAvailable = make(map[TaskID]int32, len(pg.Spec.MinTaskMember))
 	taskMinAvailableTotal := int32(0)
 	for task, member := range pg.Spec.MinTaskMember {
 		ji.TaskMinAvailable[TaskID(task)] = member
@@ -484,8 +646,23 @@ func (ji *JobInfo) GetMinResources() *Resource {
 	return NewResource(*ji.PodGroup.Spec.MinResources)
 }
 
+// GetMissingResources returns the resources the job still misses to meet the min resources of podgroup.
+// If the podgroup does not set min resources, it is the resources requested by tasks not allocated yet.
+func (ji *JobInfo) GetMissingResources() *Resource {
+	if ji.PodGroup == nil || ji.PodGroup.Spec.MinResources == nil {
+		missing := EmptyResource()
+		for _, task := range ji.TaskStatusIndex[Pending] {
+			missing.Add(task.Resreq)
+		}
+		return missing
+	}
+
+	missing, _ := ji.GetMinResources().Diff(ji.Allocated, Zero)
+	return missing
+}
+
 func (ji *JobInfo) GetElasticResources() *Resource {
-	if ji.Allocated.LessEqualPartly(ji.GetMinResources(), Zero) {
+	if ji.Allocated.Compare(ji.GetMinResources(), NewDimensionSet(Zero, Zero)).AnyLessEqual() {
 		return EmptyResource()
 	}
 	return ji.Allocated.Clone().Sub(ji.GetMinResources())
@@ -500,6 +677,7 @@ func (ji *JobInfo) addTaskIndex(ti *TaskInfo) {
 
 // AddTaskInfo is used to add a task to a job
 func (ji *JobInfo) AddTaskInfo(ti *TaskInfo) {
+	ji.generation = nextGeneration()
 	ji.Tasks[ti.UID] = ti
 	ji.addTaskIndex(ti)
 	ji.TotalRequest.Add(ti.Resreq)
@@ -539,8 +717,23 @@ func (ji *JobInfo) deleteTaskIndex(ti *TaskInfo) {
 	}
 }
 
+// SetTaskResourceShape changes the resource shape requested by the task of the job, the resources of the job
+// are updated as well.
+func (ji *JobInfo) SetTaskResourceShape(task *TaskInfo, shape int) error {
+	if shape < 0 || shape >= len(task.ResourceShapes) {
+		return fmt.Errorf("task <%v/%v> has no resource shape %d", task.Namespace, task.Name, shape)
+	}
+	if err := ji.DeleteTaskInfo(task); err != nil {
+		return err
+	}
+	task.setResourceShape(shape)
+	ji.AddTaskInfo(task)
+	return nil
+}
+
 // DeleteTaskInfo is used to delete a task from a job
 func (ji *JobInfo) DeleteTaskInfo(ti *TaskInfo) error {
+	ji.generation = nextGeneration()
 	if task, found := ji.Tasks[ti.UID]; found {
 		ji.TotalRequest.Sub(task.Resreq)
 		if AllocatedStatus(task.Status) {
@@ -551,10 +744,66 @@ func (ji *JobInfo) DeleteTaskInfo(ti *TaskInfo) error {
 		return nil
 	}
 
+	if counts := ji.CompactedTasks[ti.Status]; counts[getTaskID(ti.Pod)] > 0 {
+		ji.TotalRequest.Sub(ti.Resreq)
+		counts[getTaskID(ti.Pod)]--
+		return nil
+	}
+
 	return fmt.Errorf("failed to find task <%v/%v> in job <%v/%v>",
 		ti.Namespace, ti.Name, ji.Namespace, ji.Name)
 }
 
+// CompactCompletedTasks drops the succeeded and failed tasks of job above retained of each status from Tasks,
+// so that jobs with many completed tasks do not bloat memory. The tasks dropped are only counted in CompactedTasks.
+func (ji *JobInfo) CompactCompletedTasks(retained int) {
+	ji.generation = nextGeneration()
+	for _, status := range []TaskStatus{Succeeded, Failed} {
+		tasks := ji.TaskStatusIndex[status]
+		for uid, task := range tasks {
+			if len(tasks) <= retained {
+				break
+			}
+			if ji.CompactedTasks == nil {
+				ji.CompactedTasks = map[TaskStatus]map[TaskID]int32{}
+			}
+			if _, found := ji.CompactedTasks[status]; !found {
+				ji.CompactedTasks[status] = map[TaskID]int32{}
+			}
+			ji.CompactedTasks[status][getTaskID(task.Pod)]++
+			delete(ji.Tasks, uid)
+			ji.deleteTaskIndex(task)
+		}
+	}
+}
+
+// TaskNum returns the number of tasks of job in status, including the compacted ones.
+func (ji *JobInfo) TaskNum(status TaskStatus) int32 {
+	num := int32(len(ji.TaskStatusIndex[status]))
+	for _, count := range ji.CompactedTasks[status] {
+		num += count
+	}
+	return num
+}
+
+// CompactedTaskNum returns the number of compacted tasks of job.
+func (ji *JobInfo) CompactedTaskNum() int32 {
+	var num int32
+	for _, counts := range ji.CompactedTasks {
+		for _, count := range counts {
+			num += count
+		}
+	}
+	return num
+}
+
+// addCompactedSucceeded adds the numbers of compacted succeeded tasks of job to occupied, by task.
+func (ji *JobInfo) addCompactedSucceeded(occupied map[TaskID]int32) {
+	for taskID, count := range ji.CompactedTasks[Succeeded] {
+		occupied[taskID] += count
+	}
+}
+
 // Clone is used to clone a jobInfo object
 func (ji *JobInfo) Clone() *JobInfo {
 	info := &JobInfo{
@@ -573,13 +822,24 @@ func (ji *JobInfo) Clone() *JobInfo {
 
 		PodGroup: ji.PodGroup.Clone(),
 
-		TaskStatusIndex:       map[TaskStatus]tasksMap{},
-		TaskMinAvailable:      make(map[TaskID]int32, len(ji.TaskMinAvailable)),
-		TaskMinAvailableTotal: ji.TaskMinAvailableTotal,
-		Tasks:                 tasksMap{},
-		Preemptable:           ji.Preemptable,
-		RevocableZone:         ji.RevocableZone,
-		Budget:                ji.Budget.Clone(),
+		TaskStatusIndex:        map[TaskStatus]tasksMap{},
+		TaskMinAvailable:       make(map[TaskID]int32, len(ji.TaskMinAvailable)),
+		TaskMinAvailableTotal:  ji.TaskMinAvailableTotal,
+		Tasks:                  tasksMap{},
+		Preemptable:            ji.Preemptable,
+		RevocableZone:          ji.RevocableZone,
+		Budget:                 ji.Budget.Clone(),
+		Paused:                 ji.Paused,
+		ExcludedNodes:          ji.ExcludedNodes,
+		ExclusiveNode:          ji.ExclusiveNode,
+		SchedulingProfile:      ji.SchedulingProfile,
+		NodeStabilitySensitive: ji.NodeStabilitySensitive,
+		NotBefore:              ji.NotBefore,
+		ScheduleWindow:         ji.ScheduleWindow,
+		GangSchedulingTimeout:  ji.GangSchedulingTimeout,
+		GangFallbackMinMember:  ji.GangFallbackMinMember,
+		InqueueTime:            ji.InqueueTime,
+		ReadyVetoedBy:          ji.ReadyVetoedBy,
 	}
 
 	ji.CreationTimestamp.DeepCopyInto(&info.CreationTimestamp)
@@ -590,6 +850,17 @@ func (ji *JobInfo) Clone() *JobInfo {
 	for _, task := range ji.Tasks {
 		info.AddTaskInfo(task.Clone())
 	}
+	if len(ji.CompactedTasks) != 0 {
+		info.CompactedTasks = make(map[TaskStatus]map[TaskID]int32, len(ji.CompactedTasks))
+		for status, counts := range ji.CompactedTasks {
+			info.CompactedTasks[status] = make(map[TaskID]int32, len(counts))
+			for taskID, count := range counts {
+				info.CompactedTasks[status][taskID] = count
+			}
+		}
+		info.TotalRequest = ji.TotalRequest.Clone()
+	}
+	info.generation = ji.generation
 
 	return info
 }
@@ -682,7 +953,7 @@ func (ji *JobInfo) ReadyTaskNum() int32 {
 	occupied += len(ji.TaskStatusIndex[Binding])
 	occupied += len(ji.TaskStatusIndex[Running])
 	occupied += len(ji.TaskStatusIndex[Allocated])
-	occupied += len(ji.TaskStatusIndex[Succeeded])
+	occupied += int(ji.TaskNum(Succeeded))
 
 	if tasks, found := ji.TaskStatusIndex[Pending]; found {
 		for _, task := range tasks {
@@ -700,14 +971,45 @@ func (ji *JobInfo) WaitingTaskNum() int32 {
 	return int32(len(ji.TaskStatusIndex[Pipelined]))
 }
 
+// CheckTaskMinAvailable returns whether the minAvailable of each task is checked for the gang of job. It is skipped if
+// job minAvailable is less than sumof(task minAvailable), e.g. task minAvailable defaults to its replicas while only job
+// minAvailable is given.
+func (ji *JobInfo) CheckTaskMinAvailable() bool {
+	return ji.MinAvailable >= ji.TaskMinAvailableTotal
+}
+
+// ReadyTaskNumOfTasks returns the number of tasks that are ready or that is best-effort for each task of job.
+func (ji *JobInfo) ReadyTaskNumOfTasks() map[TaskID]int32 {
+	occupiedMap := map[TaskID]int32{}
+	ji.addCompactedSucceeded(occupiedMap)
+	for status, tasks := range ji.TaskStatusIndex {
+		if AllocatedStatus(status) ||
+			status == Succeeded {
+			for _, task := range tasks {
+				occupiedMap[getTaskID(task.Pod)]++
+			}
+			continue
+		}
+
+		if status == Pending {
+			for _, task := range tasks {
+				if task.InitResreq.IsEmpty() {
+					occupiedMap[getTaskID(task.Pod)]++
+				}
+			}
+		}
+	}
+	return occupiedMap
+}
+
 // CheckTaskValid returns whether each task of job is valid.
 func (ji *JobInfo) CheckTaskValid() bool {
-	// if job minAvailable is less than sumof(task minAvailable), skip this check
-	if ji.MinAvailable < ji.TaskMinAvailableTotal {
+	if !ji.CheckTaskMinAvailable() {
 		return true
 	}
 
 	actual := map[TaskID]int32{}
+	ji.addCompactedSucceeded(actual)
 	for status, tasks := range ji.TaskStatusIndex {
 		if AllocatedStatus(status) ||
 			status == Succeeded ||
@@ -734,27 +1036,10 @@ func (ji *JobInfo) CheckTaskValid() bool {
 
 // CheckTaskReady return whether each task of job is ready.
 func (ji *JobInfo) CheckTaskReady() bool {
-	if ji.MinAvailable < ji.TaskMinAvailableTotal {
+	if !ji.CheckTaskMinAvailable() {
 		return true
 	}
-	occupiedMap := map[TaskID]int32{}
-	for status, tasks := range ji.TaskStatusIndex {
-		if AllocatedStatus(status) ||
-			status == Succeeded {
-			for _, task := range tasks {
-				occupiedMap[getTaskID(task.Pod)]++
-			}
-			continue
-		}
-
-		if status == Pending {
-			for _, task := range tasks {
-				if task.InitResreq.IsEmpty() {
-					occupiedMap[getTaskID(task.Pod)]++
-				}
-			}
-		}
-	}
+	occupiedMap := ji.ReadyTaskNumOfTasks()
 	for taskID, minNum := range ji.TaskMinAvailable {
 		if occupiedMap[taskID] < minNum {
 			klog.V(4).Infof("Job %s/%s Task %s occupied %v less than task min avaliable", ji.Namespace, ji.Name, taskID, occupiedMap[taskID])
@@ -766,10 +1051,11 @@ func (ji *JobInfo) CheckTaskReady() bool {
 
 // CheckTaskPipelined return whether each task of job is pipelined.
 func (ji *JobInfo) CheckTaskPipelined() bool {
-	if ji.MinAvailable < ji.TaskMinAvailableTotal {
+	if !ji.CheckTaskMinAvailable() {
 		return true
 	}
 	occupiedMap := map[TaskID]int32{}
+	ji.addCompactedSucceeded(occupiedMap)
 	for status, tasks := range ji.TaskStatusIndex {
 		if AllocatedStatus(status) ||
 			status == Succeeded ||
@@ -799,10 +1085,11 @@ func (ji *JobInfo) CheckTaskPipelined() bool {
 
 // CheckTaskStarving return whether job has at least one task which is starving.
 func (ji *JobInfo) CheckTaskStarving() bool {
-	if ji.MinAvailable < ji.TaskMinAvailableTotal {
-		return true
+	if !ji.CheckTaskMinAvailable() {
+		return false
 	}
 	occupiedMap := map[TaskID]int32{}
+	ji.addCompactedSucceeded(occupiedMap)
 	for status, tasks := range ji.TaskStatusIndex {
 		if AllocatedStatus(status) ||
 			status == Succeeded ||
@@ -841,6 +1128,9 @@ func (ji *JobInfo) ValidTaskNum() int32 {
 			occupied += len(tasks)
 		}
 	}
+	for _, count := range ji.CompactedTasks[Succeeded] {
+		occupied += int(count)
+	}
 
 	return int32(occupied)
 }
@@ -17,18 +17,25 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/scheduling"
 	schedulingv2 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
 func jobInfoEqual(l, r *JobInfo) bool {
+	// generation tells the changes of job, it is not part of the state compared.
+	lg, rg := l.generation, r.generation
+	l.generation, r.generation = 0, 0
+	defer func() { l.generation, r.generation = lg, rg }()
 	return reflect.DeepEqual(l, r)
 }
 
@@ -288,3 +295,354 @@ func TestTaskSchedulingReason(t *testing.T) {
 		}
 	}
 }
+
+func TestJobInfoPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:     "no annotation",
+			expected: false,
+		},
+		{
+			name:        "paused",
+			annotations: map[string]string{SchedulingPausedAnnotation: "true"},
+			expected:    true,
+		},
+		{
+			name:        "not paused",
+			annotations: map[string]string{SchedulingPausedAnnotation: "false"},
+			expected:    false,
+		},
+		{
+			name:        "invalid value",
+			annotations: map[string]string{SchedulingPausedAnnotation: "yes-please"},
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		job := NewJobInfo("uid")
+		job.SetPodGroup(&PodGroup{
+			PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg",
+					Namespace:   "ns",
+					Annotations: test.annotations,
+				},
+			},
+		})
+
+		if job.Paused != test.expected {
+			t.Errorf("case %s: expected paused %v, got %v", test.name, test.expected, job.Paused)
+		}
+		if job.Clone().Paused != test.expected {
+			t.Errorf("case %s: expected cloned paused %v, got %v", test.name, test.expected, job.Clone().Paused)
+		}
+	}
+}
+
+func TestJobInfoExcludedNodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]struct{}
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "empty annotation",
+			annotations: map[string]string{ExcludedNodesAnnotation: ""},
+		},
+		{
+			name:        "excluded nodes",
+			annotations: map[string]string{ExcludedNodesAnnotation: "node1, node2,,"},
+			expected:    map[string]struct{}{"node1": {}, "node2": {}},
+		},
+	}
+
+	for _, test := range tests {
+		job := NewJobInfo("uid")
+		job.SetPodGroup(&PodGroup{
+			PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg",
+					Namespace:   "ns",
+					Annotations: test.annotations,
+				},
+			},
+		})
+
+		if !reflect.DeepEqual(job.ExcludedNodes, test.expected) {
+			t.Errorf("case %s: expected excluded nodes %v, got %v", test.name, test.expected, job.ExcludedNodes)
+		}
+		if clone := job.Clone(); !reflect.DeepEqual(clone.ExcludedNodes, test.expected) {
+			t.Errorf("case %s: expected cloned excluded nodes %v, got %v", test.name, test.expected, clone.ExcludedNodes)
+		}
+	}
+}
+
+func TestJobInfoMissingResources(t *testing.T) {
+	allocatedPod := buildPod("ns", "p1", "n1", v1.PodRunning, buildResourceList("2", "2G"), nil, nil)
+	pendingPod := buildPod("ns", "p2", "", v1.PodPending, buildResourceList("1", "1G"), nil, nil)
+	minResources := buildResourceList("4", "2G")
+
+	tests := []struct {
+		name         string
+		minResources *v1.ResourceList
+		expected     *Resource
+	}{
+		{
+			name:     "pending tasks without min resources",
+			expected: buildResource("1", "1G"),
+		},
+		{
+			name:         "min resources not met",
+			minResources: &minResources,
+			expected:     buildResource("2", "0"),
+		},
+	}
+
+	for _, test := range tests {
+		job := NewJobInfo("uid", NewTaskInfo(allocatedPod), NewTaskInfo(pendingPod))
+		job.SetPodGroup(&PodGroup{
+			PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "ns"},
+				Spec:       scheduling.PodGroupSpec{MinResources: test.minResources},
+			},
+		})
+
+		if missing := job.GetMissingResources(); !missing.Equal(test.expected, Zero) {
+			t.Errorf("case %s: expected missing resources %v, got %v", test.name, test.expected, missing)
+		}
+	}
+}
+
+func TestCompactCompletedTasks(t *testing.T) {
+	var tasks []*TaskInfo
+	for i := 0; i < 4; i++ {
+		tasks = append(tasks, NewTaskInfo(buildPod("ns", fmt.Sprintf("succeeded-%d", i), "n1", v1.PodSucceeded, buildResourceList("1", "1G"), nil, nil)))
+	}
+	tasks = append(tasks, NewTaskInfo(buildPod("ns", "failed-0", "n1", v1.PodFailed, buildResourceList("1", "1G"), nil, nil)))
+	tasks = append(tasks, NewTaskInfo(buildPod("ns", "running-0", "n1", v1.PodRunning, buildResourceList("1", "1G"), nil, nil)))
+	job := NewJobInfo("uid", tasks...)
+	job.SetPodGroup(&PodGroup{
+		PodGroup: scheduling.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "ns"},
+		},
+	})
+
+	job.CompactCompletedTasks(1)
+	if len(job.Tasks) != 3 {
+		t.Errorf("expected 3 tasks kept, got %d", len(job.Tasks))
+	}
+	if num := job.TaskNum(Succeeded); num != 4 {
+		t.Errorf("expected 4 succeeded tasks, got %d", num)
+	}
+	if num := job.TaskNum(Failed); num != 1 {
+		t.Errorf("expected 1 failed task, got %d", num)
+	}
+	if num := job.CompactedTaskNum(); num != 3 {
+		t.Errorf("expected 3 compacted tasks, got %d", num)
+	}
+	if num := job.ReadyTaskNum(); num != 5 {
+		t.Errorf("expected 5 ready tasks, got %d", num)
+	}
+	if !job.TotalRequest.Equal(buildResource("6", "6G"), Zero) {
+		t.Errorf("expected total request of all tasks kept, got %v", job.TotalRequest)
+	}
+
+	clone := job.Clone()
+	if num := clone.TaskNum(Succeeded); num != 4 {
+		t.Errorf("expected 4 succeeded tasks in clone, got %d", num)
+	}
+	if !clone.TotalRequest.Equal(job.TotalRequest, Zero) {
+		t.Errorf("expected total request %v in clone, got %v", job.TotalRequest, clone.TotalRequest)
+	}
+
+	// Deleting all succeeded tasks, compacted or not, leaves none of them.
+	for _, task := range tasks[:4] {
+		if err := job.DeleteTaskInfo(NewTaskInfo(task.Pod)); err != nil {
+			t.Errorf("failed to delete task %s: %v", task.Name, err)
+		}
+	}
+	if num := job.TaskNum(Succeeded); num != 0 {
+		t.Errorf("expected no succeeded tasks, got %d", num)
+	}
+	if err := job.DeleteTaskInfo(NewTaskInfo(tasks[0].Pod)); err == nil {
+		t.Errorf("expected error deleting task not in job")
+	}
+	if !job.TotalRequest.Equal(buildResource("2", "2G"), Zero) {
+		t.Errorf("expected total request of the remaining tasks, got %v", job.TotalRequest)
+	}
+}
+
+func TestSetPodGroupOnScale(t *testing.T) {
+	job := NewJobInfo("uid")
+	job.SetPodGroup(&PodGroup{
+		PodGroup: scheduling.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "ns"},
+			Spec:       scheduling.PodGroupSpec{MinMember: 3, MinTaskMember: map[string]int32{"ps": 1, "worker": 2}},
+		},
+	})
+	job.SetPodGroup(&PodGroup{
+		PodGroup: scheduling.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "ns"},
+			Spec:       scheduling.PodGroupSpec{MinMember: 4, MinTaskMember: map[string]int32{"worker": 4}},
+		},
+	})
+
+	if job.MinAvailable != 4 {
+		t.Errorf("expected min available 4, got %d", job.MinAvailable)
+	}
+	if !reflect.DeepEqual(job.TaskMinAvailable, map[TaskID]int32{"worker": 4}) {
+		t.Errorf("expected min available of removed task dropped, got %v", job.TaskMinAvailable)
+	}
+	if job.TaskMinAvailableTotal != 4 {
+		t.Errorf("expected total task min available 4, got %d", job.TaskMinAvailableTotal)
+	}
+}
+
+func TestSetPodGroupGangSchedulingTimeout(t *testing.T) {
+	pgOf := func(phase scheduling.PodGroupPhase) *PodGroup {
+		return &PodGroup{
+			PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pg",
+					Namespace: "ns",
+					Annotations: map[string]string{
+						GangSchedulingTimeoutAnnotation: "10m",
+						GangFallbackMinMemberAnnotation: "invalid",
+					},
+				},
+				Spec:   scheduling.PodGroupSpec{MinMember: 3},
+				Status: scheduling.PodGroupStatus{Phase: phase},
+			},
+		}
+	}
+
+	job := NewJobInfo("uid")
+	job.SetPodGroup(pgOf(scheduling.PodGroupPending))
+	if !job.InqueueTime.IsZero() {
+		t.Errorf("expected no inqueue time of pending job, got %v", job.InqueueTime)
+	}
+	if job.GangSchedulingTimeout != 10*time.Minute || job.GangFallbackMinMember != 0 {
+		t.Errorf("expected timeout 10m without fallback, got %v and %d", job.GangSchedulingTimeout, job.GangFallbackMinMember)
+	}
+
+	job.SetPodGroup(pgOf(scheduling.PodGroupInqueue))
+	inqueueTime := job.InqueueTime
+	if inqueueTime.IsZero() {
+		t.Errorf("expected inqueue time of enqueued job")
+	}
+	job.SetPodGroup(pgOf(scheduling.PodGroupRunning))
+	if !job.InqueueTime.Equal(inqueueTime) || !job.Clone().InqueueTime.Equal(inqueueTime) {
+		t.Errorf("expected inqueue time %v kept, got %v", inqueueTime, job.InqueueTime)
+	}
+
+	job.SetPodGroup(pgOf(scheduling.PodGroupPending))
+	if !job.InqueueTime.IsZero() {
+		t.Errorf("expected inqueue time reset when job is pending again, got %v", job.InqueueTime)
+	}
+
+	// The time the podgroup is enqueued is taken from its condition, e.g. after the scheduler restarts.
+	enqueued := pgOf(scheduling.PodGroupInqueue)
+	enqueuedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	enqueued.Status.Conditions = []scheduling.PodGroupCondition{
+		{Type: PodGroupInqueueType, Status: v1.ConditionTrue, LastTransitionTime: enqueuedAt},
+	}
+	job = NewJobInfo("uid")
+	job.SetPodGroup(enqueued)
+	if !job.InqueueTime.Equal(enqueuedAt.Time) {
+		t.Errorf("expected inqueue time %v of condition, got %v", enqueuedAt, job.InqueueTime)
+	}
+}
+
+func TestCheckTaskMinAvailable(t *testing.T) {
+	var tasks []*TaskInfo
+	for i := 0; i < 4; i++ {
+		pod := buildPod("ns", fmt.Sprintf("worker-%d", i), "n1", v1.PodRunning, buildResourceList("1", "1G"), nil, nil)
+		pod.Annotations = map[string]string{batch.TaskSpecKey: "worker"}
+		tasks = append(tasks, NewTaskInfo(pod))
+	}
+	pod := buildPod("ns", "ps-0", "", v1.PodPending, buildResourceList("1", "1G"), nil, nil)
+	pod.Annotations = map[string]string{batch.TaskSpecKey: "ps"}
+	tasks = append(tasks, NewTaskInfo(pod))
+	job := NewJobInfo("uid", tasks...)
+	setMinAvailable := func(minMember int32) {
+		job.SetPodGroup(&PodGroup{
+			PodGroup: scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "ns"},
+				Spec:       scheduling.PodGroupSpec{MinMember: minMember, MinTaskMember: map[string]int32{"ps": 1, "worker": 3}},
+			},
+		})
+	}
+
+	// At least 1 ps and 3 workers: 4 running workers are not ready without the ps.
+	setMinAvailable(4)
+	if !reflect.DeepEqual(job.ReadyTaskNumOfTasks(), map[TaskID]int32{"worker": 4}) {
+		t.Errorf("expected 4 ready workers, got %v", job.ReadyTaskNumOfTasks())
+	}
+	if !job.Ready() {
+		t.Errorf("expected job ready by its minAvailable")
+	}
+	if !job.CheckTaskMinAvailable() || job.CheckTaskReady() || job.CheckTaskPipelined() {
+		t.Errorf("expected job not ready without the ps")
+	}
+	if !job.CheckTaskValid() {
+		t.Errorf("expected job valid with the pending ps")
+	}
+	if !job.CheckTaskStarving() {
+		t.Errorf("expected job starving without the ps")
+	}
+
+	// The minAvailable of tasks is not checked if job minAvailable is less than their sum.
+	setMinAvailable(3)
+	if job.CheckTaskMinAvailable() || !job.CheckTaskReady() || job.CheckTaskStarving() {
+		t.Errorf("expected minAvailable of tasks not checked")
+	}
+}
+
+func TestSetTaskResourceShape(t *testing.T) {
+	pod := buildPod("ns", "p1", "", v1.PodPending, buildResourceList("1", "1G"), nil, nil)
+	pod.Annotations = map[string]string{
+		ResourceShapesAnnotation: `[{"nvidia.com/a100":"1"},{"nvidia.com/v100":"2"}]`,
+	}
+	task := NewTaskInfo(pod)
+	if len(task.ResourceShapes) != 2 || task.ResourceShape != 0 {
+		t.Fatalf("expected task in the first of 2 resource shapes, got %d of %d", task.ResourceShape, len(task.ResourceShapes))
+	}
+	if quant := task.Resreq.Get("nvidia.com/a100"); quant != 1000 {
+		t.Errorf("expected 1 nvidia.com/a100 requested, got %v", task.Resreq)
+	}
+
+	job := NewJobInfo("uid", task)
+	if err := job.SetTaskResourceShape(task, 1); err != nil {
+		t.Fatalf("failed to set resource shape: %v", err)
+	}
+	if task.Resreq.Get("nvidia.com/a100") != 0 || task.Resreq.Get("nvidia.com/v100") != 2000 {
+		t.Errorf("expected 2 nvidia.com/v100 requested, got %v", task.Resreq)
+	}
+	if !job.TotalRequest.Equal(task.Resreq, Zero) {
+		t.Errorf("expected total request %v of job, got %v", task.Resreq, job.TotalRequest)
+	}
+	if err := job.SetTaskResourceShape(task, 2); err == nil {
+		t.Errorf("expected error setting resource shape out of range")
+	}
+
+	task.SetPodResourceShape()
+	if shape := pod.Annotations[ResourceShapeAnnotation]; shape != "1" {
+		t.Errorf("expected resource shape 1 recorded in pod annotations, got %q", shape)
+	}
+	if rebuilt := NewTaskInfo(pod); rebuilt.ResourceShape != 1 || !rebuilt.Resreq.Equal(task.Resreq, Zero) {
+		t.Errorf("expected task rebuilt from pod in resource shape 1, got %d: %v", rebuilt.ResourceShape, rebuilt.Resreq)
+	}
+
+	pod.Annotations[ResourceShapesAnnotation] = "invalid"
+	if shapes := NewTaskInfo(pod).ResourceShapes; shapes != nil {
+		t.Errorf("expected invalid resource shapes ignored, got %v", shapes)
+	}
+}
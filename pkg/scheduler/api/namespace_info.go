@@ -29,12 +29,15 @@ type NamespaceInfo struct {
 	Name NamespaceName
 	// QuotaStatus stores the ResourceQuotaStatus of all ResourceQuotas in this namespace
 	QuotaStatus map[string]v1.ResourceQuotaStatus
+	// QuotaScoped stores whether the ResourceQuotas in this namespace only limit pods matching their scopes
+	QuotaScoped map[string]bool
 }
 
 // NamespaceCollection will record all details about namespace
 type NamespaceCollection struct {
 	Name        string
 	QuotaStatus map[string]v1.ResourceQuotaStatus
+	QuotaScoped map[string]bool
 }
 
 // NewNamespaceCollection creates new NamespaceCollection object to record all information about a namespace
@@ -42,6 +45,7 @@ func NewNamespaceCollection(name string) *NamespaceCollection {
 	n := &NamespaceCollection{
 		Name:        name,
 		QuotaStatus: make(map[string]v1.ResourceQuotaStatus),
+		QuotaScoped: make(map[string]bool),
 	}
 	return n
 }
@@ -49,11 +53,13 @@ func NewNamespaceCollection(name string) *NamespaceCollection {
 // Update modify the registered information according quota object
 func (n *NamespaceCollection) Update(quota *v1.ResourceQuota) {
 	n.QuotaStatus[quota.Name] = quota.Status
+	n.QuotaScoped[quota.Name] = len(quota.Spec.Scopes) != 0 || quota.Spec.ScopeSelector != nil
 }
 
 // Delete remove the registered information according quota object
 func (n *NamespaceCollection) Delete(quota *v1.ResourceQuota) {
 	delete(n.QuotaStatus, quota.Name)
+	delete(n.QuotaScoped, quota.Name)
 }
 
 // Snapshot will clone a NamespaceInfo without Heap according NamespaceCollection
@@ -61,5 +67,6 @@ func (n *NamespaceCollection) Snapshot() *NamespaceInfo {
 	return &NamespaceInfo{
 		Name:        NamespaceName(n.Name),
 		QuotaStatus: n.QuotaStatus,
+		QuotaScoped: n.QuotaScoped,
 	}
 }
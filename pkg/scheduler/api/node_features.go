@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+)
+
+// NodeFeature is a requirement of the job on the labels of nodes, parsed from an item of the
+// scheduling.volcano.sh/node-arch or scheduling.volcano.sh/node-features annotation.
+type NodeFeature struct {
+	// Item is the item of annotation the requirement is parsed from, e.g. avx512f
+	Item string
+	// Arch means the requirement is on the architecture of nodes
+	Arch        bool
+	Requirement v1.NodeSelectorRequirement
+
+	// selector is built from Requirement when the feature is parsed, so it is not built again for every node
+	selector *nodeaffinity.NodeSelector
+}
+
+// newNodeSelector builds the selector of nodes matching the requirement.
+func newNodeSelector(requirement v1.NodeSelectorRequirement) (*nodeaffinity.NodeSelector, error) {
+	return nodeaffinity.NewNodeSelector(&v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+		MatchExpressions: []v1.NodeSelectorRequirement{requirement},
+	}}})
+}
+
+// Match returns whether the labels of node match the feature.
+func (f *NodeFeature) Match(node *v1.Node) bool {
+	selector := f.selector
+	if selector == nil {
+		var err error
+		if selector, err = newNodeSelector(f.Requirement); err != nil {
+			return false
+		}
+	}
+	return selector.Match(node)
+}
+
+// featureOperators are the operators of feature items, longer ones first.
+var featureOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// featureKey returns the label of feature, features without prefix are labels of Node Feature Discovery.
+func featureKey(key string) string {
+	if strings.Contains(key, "/") {
+		return key
+	}
+	return NodeFeatureLabelPrefix + key
+}
+
+// parseFeature parses a feature item, which is one of:
+//   - `key` or `!key`, the label must or must not exist, e.g. `cpu-cpuid.AVX512F`;
+//   - `key=value` or `key!=value`, e.g. `nvidia.com/cuda.driver.major=535`;
+//   - `key>n`, `key>=n`, `key<n` or `key<=n` for integer n, e.g. `nvidia.com/cuda.driver.major>=525`.
+func parseFeature(item string) (v1.NodeSelectorRequirement, error) {
+	key, operator, value := item, "", ""
+	for _, op := range featureOperators {
+		if index := strings.Index(item, op); index > 0 {
+			key, operator, value = strings.TrimSpace(item[:index]), op, strings.TrimSpace(item[index+len(op):])
+			break
+		}
+	}
+
+	requirement := v1.NodeSelectorRequirement{}
+	switch operator {
+	case "":
+		requirement.Operator = v1.NodeSelectorOpExists
+		if strings.HasPrefix(key, "!") {
+			key = strings.TrimSpace(key[1:])
+			requirement.Operator = v1.NodeSelectorOpDoesNotExist
+		}
+	case "=", "!=":
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			return requirement, fmt.Errorf("invalid value of node feature %q: %s", item, strings.Join(errs, "; "))
+		}
+		requirement.Operator = v1.NodeSelectorOpIn
+		if operator == "!=" {
+			requirement.Operator = v1.NodeSelectorOpNotIn
+		}
+		requirement.Values = []string{value}
+	default:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return requirement, fmt.Errorf("value of node feature %q must be an integer", item)
+		}
+		switch operator {
+		case ">=":
+			n--
+		case "<=":
+			n++
+		}
+		requirement.Operator = v1.NodeSelectorOpGt
+		if strings.HasPrefix(operator, "<") {
+			requirement.Operator = v1.NodeSelectorOpLt
+		}
+		requirement.Values = []string{strconv.FormatInt(n, 10)}
+	}
+
+	requirement.Key = featureKey(key)
+	if errs := validation.IsQualifiedName(requirement.Key); len(errs) != 0 {
+		return requirement, fmt.Errorf("invalid key of node feature %q: %s", item, strings.Join(errs, "; "))
+	}
+	return requirement, nil
+}
+
+// ParseNodeFeatures parses the scheduling.volcano.sh/node-arch and scheduling.volcano.sh/node-features
+// annotations into the requirements on the labels of nodes.
+func ParseNodeFeatures(annotations map[string]string) ([]NodeFeature, error) {
+	var features []NodeFeature
+	if value := strings.TrimSpace(annotations[NodeArchAnnotation]); len(value) != 0 {
+		var archs []string
+		for _, arch := range strings.Split(value, ",") {
+			if arch = strings.TrimSpace(arch); len(arch) != 0 {
+				archs = append(archs, arch)
+			}
+		}
+		features = append(features, NodeFeature{
+			Item: value,
+			Arch: true,
+			Requirement: v1.NodeSelectorRequirement{
+				Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: archs,
+			},
+		})
+	}
+
+	for _, item := range strings.Split(annotations[NodeFeaturesAnnotation], ",") {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		requirement, err := parseFeature(item)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, NodeFeature{Item: item, Requirement: requirement})
+	}
+
+	for i := range features {
+		selector, err := newNodeSelector(features[i].Requirement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node feature %q: %v", features[i].Item, err)
+		}
+		features[i].selector = selector
+	}
+	return features, nil
+}
+
+// NodeFeatureRequirements returns the requirements of features on the labels of nodes.
+func NodeFeatureRequirements(features []NodeFeature) []v1.NodeSelectorRequirement {
+	requirements := make([]v1.NodeSelectorRequirement, 0, len(features))
+	for _, feature := range features {
+		requirements = append(requirements, feature.Requirement)
+	}
+	return requirements
+}
+
+// UnmatchedNodeFeature returns the first feature the labels of node do not match.
+func UnmatchedNodeFeature(node *v1.Node, features []NodeFeature) (*NodeFeature, bool) {
+	for i := range features {
+		if !features[i].Match(node) {
+			return &features[i], true
+		}
+	}
+	return nil, false
+}
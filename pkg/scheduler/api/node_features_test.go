@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseNodeFeatures(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		requirements []v1.NodeSelectorRequirement
+		err          bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "arch and features",
+			annotations: map[string]string{
+				NodeArchAnnotation:     "amd64, arm64",
+				NodeFeaturesAnnotation: "cpu-cpuid.AVX512F, !kernel-version.rt, nvidia.com/cuda.driver.major>=525, nvidia.com/gpu.family=ampere",
+			},
+			requirements: []v1.NodeSelectorRequirement{
+				{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{"amd64", "arm64"}},
+				{Key: "feature.node.kubernetes.io/cpu-cpuid.AVX512F", Operator: v1.NodeSelectorOpExists},
+				{Key: "feature.node.kubernetes.io/kernel-version.rt", Operator: v1.NodeSelectorOpDoesNotExist},
+				{Key: "nvidia.com/cuda.driver.major", Operator: v1.NodeSelectorOpGt, Values: []string{"524"}},
+				{Key: "nvidia.com/gpu.family", Operator: v1.NodeSelectorOpIn, Values: []string{"ampere"}},
+			},
+		},
+		{
+			name:        "not equal and less than",
+			annotations: map[string]string{NodeFeaturesAnnotation: "nvidia.com/gpu.family!=kepler,nvidia.com/cuda.driver.major<=550"},
+			requirements: []v1.NodeSelectorRequirement{
+				{Key: "nvidia.com/gpu.family", Operator: v1.NodeSelectorOpNotIn, Values: []string{"kepler"}},
+				{Key: "nvidia.com/cuda.driver.major", Operator: v1.NodeSelectorOpLt, Values: []string{"551"}},
+			},
+		},
+		{
+			name:        "not integer",
+			annotations: map[string]string{NodeFeaturesAnnotation: "nvidia.com/cuda.driver.major>latest"},
+			err:         true,
+		},
+		{
+			name:        "invalid key",
+			annotations: map[string]string{NodeFeaturesAnnotation: "bad key"},
+			err:         true,
+		},
+		{
+			name:        "no arch",
+			annotations: map[string]string{NodeArchAnnotation: ","},
+			err:         true,
+		},
+	}
+
+	for _, test := range tests {
+		features, err := ParseNodeFeatures(test.annotations)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.err, err)
+			continue
+		}
+		if requirements := NodeFeatureRequirements(features); !test.err && len(test.requirements) != 0 &&
+			!reflect.DeepEqual(requirements, test.requirements) {
+			t.Errorf("%s: expected requirements %v, got %v", test.name, test.requirements, requirements)
+		}
+		for _, feature := range features {
+			if feature.selector == nil {
+				t.Errorf("%s: expected selector of node feature %q built when parsed", test.name, feature.Item)
+			}
+		}
+	}
+}
+
+func TestUnmatchedNodeFeature(t *testing.T) {
+	features, err := ParseNodeFeatures(map[string]string{
+		NodeArchAnnotation:     "amd64",
+		NodeFeaturesAnnotation: "cpu-cpuid.AVX512F,nvidia.com/cuda.driver.major>=525",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		item   string
+	}{
+		{
+			name: "matched",
+			labels: map[string]string{v1.LabelArchStable: "amd64", "feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true",
+				"nvidia.com/cuda.driver.major": "535"},
+		},
+		{
+			name:   "arch",
+			labels: map[string]string{v1.LabelArchStable: "arm64", "feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true"},
+			item:   "amd64",
+		},
+		{
+			name: "old driver",
+			labels: map[string]string{v1.LabelArchStable: "amd64", "feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true",
+				"nvidia.com/cuda.driver.major": "470"},
+			item: "nvidia.com/cuda.driver.major>=525",
+		},
+	}
+	for _, test := range tests {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name, Labels: test.labels}}
+		feature, unmatched := UnmatchedNodeFeature(node, features)
+		if unmatched != (test.item != "") || (unmatched && feature.Item != test.item) {
+			t.Errorf("%s: expected unmatched feature %q, got %v", test.name, test.item, feature)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package api
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -60,6 +61,8 @@ type NodeInfo struct {
 	// The used resource on that node, including running and terminating
 	// pods
 	Used *Resource
+	// DaemonUsed is the part of Used requested by DaemonSet and static pods
+	DaemonUsed *Resource
 
 	Allocatable   *Resource
 	Capacity      *Resource
@@ -87,6 +90,22 @@ type NodeInfo struct {
 	// checking an image's existence and advanced usage (e.g., image locality scheduling policy) based on the image
 	// state information.
 	ImageStates map[string]*k8sframework.ImageStateSummary
+
+	// Stability holds the recent Ready flaps and restarts of node, it is recorded by scheduler cache on node updates.
+	Stability *NodeStability
+
+	// generation is renewed on each change of node by its methods or Touch, to tell whether a clone is outdated.
+	generation uint64
+}
+
+// Generation returns the generation of node, which is renewed on each change of node and kept by Clone.
+func (ni *NodeInfo) Generation() uint64 {
+	return ni.generation
+}
+
+// Touch renews the generation of node, it must be called after changing the fields of node directly.
+func (ni *NodeInfo) Touch() {
+	ni.generation = nextGeneration()
 }
 
 // FutureIdle returns resources that will be idle in the future:
@@ -111,12 +130,62 @@ type NodeState struct {
 type NodeUsage struct {
 	CPUUsageAvg map[string]float64
 	MEMUsageAvg map[string]float64
+	// CPUUsageMax and MEMUsageMax are the max usages over the periods.
+	CPUUsageMax map[string]float64
+	MEMUsageMax map[string]float64
+	// CPUUsage and MEMUsage are the latest usages.
+	CPUUsage float64
+	MEMUsage float64
+	// GPUUsageAvg, GPUMEMUsageAvg, GPUUsageMax and GPUMEMUsageMax are the average and the max GPU utilization and
+	// GPU memory usage over the periods, they are only set for the periods reported by the node.
+	GPUUsageAvg    map[string]float64
+	GPUMEMUsageAvg map[string]float64
+	GPUUsageMax    map[string]float64
+	GPUMEMUsageMax map[string]float64
+	// GPUUsage and GPUMEMUsage are the latest GPU utilization and GPU memory usage, set if GPUReported.
+	GPUUsage    float64
+	GPUMEMUsage float64
+	GPUReported bool
+	// SampleTime is when the usage was collected, it is zero if the usage was never collected.
+	SampleTime time.Time
+	// Stale is set if the usage failed to be collected for longer than the staleness TTL of metrics,
+	// or was never collected, the usages are cleared then.
+	Stale bool
+	// PodUsages are the latest usages of the pods on the node keyed by namespace/name, set if the metrics
+	// source reports the usages of pods.
+	PodUsages map[string]*PodUsage
+}
+
+// PodUsage is the real usage of a pod.
+type PodUsage struct {
+	// MilliCPU is the cpu usage in millicores, Memory is the memory usage in bytes.
+	MilliCPU float64
+	Memory   float64
+}
+
+// StaleNodeUsage returns the empty usage marked stale of the usage collected at sampleTime.
+func StaleNodeUsage(sampleTime time.Time) *NodeUsage {
+	return &NodeUsage{SampleTime: sampleTime, Stale: true}
 }
 
 func (nu *NodeUsage) DeepCopy() *NodeUsage {
 	newUsage := &NodeUsage{
 		CPUUsageAvg: make(map[string]float64),
 		MEMUsageAvg: make(map[string]float64),
+		CPUUsageMax: make(map[string]float64),
+		MEMUsageMax: make(map[string]float64),
+		CPUUsage:    nu.CPUUsage,
+		MEMUsage:    nu.MEMUsage,
+		SampleTime:  nu.SampleTime,
+		Stale:       nu.Stale,
+
+		GPUUsageAvg:    make(map[string]float64),
+		GPUMEMUsageAvg: make(map[string]float64),
+		GPUUsageMax:    make(map[string]float64),
+		GPUMEMUsageMax: make(map[string]float64),
+		GPUUsage:       nu.GPUUsage,
+		GPUMEMUsage:    nu.GPUMEMUsage,
+		GPUReported:    nu.GPUReported,
 	}
 	for k, v := range nu.CPUUsageAvg {
 		newUsage.CPUUsageAvg[k] = v
@@ -124,6 +193,31 @@ func (nu *NodeUsage) DeepCopy() *NodeUsage {
 	for k, v := range nu.MEMUsageAvg {
 		newUsage.MEMUsageAvg[k] = v
 	}
+	for k, v := range nu.CPUUsageMax {
+		newUsage.CPUUsageMax[k] = v
+	}
+	for k, v := range nu.MEMUsageMax {
+		newUsage.MEMUsageMax[k] = v
+	}
+	for k, v := range nu.GPUUsageAvg {
+		newUsage.GPUUsageAvg[k] = v
+	}
+	for k, v := range nu.GPUMEMUsageAvg {
+		newUsage.GPUMEMUsageAvg[k] = v
+	}
+	for k, v := range nu.GPUUsageMax {
+		newUsage.GPUUsageMax[k] = v
+	}
+	for k, v := range nu.GPUMEMUsageMax {
+		newUsage.GPUMEMUsageMax[k] = v
+	}
+	if nu.PodUsages != nil {
+		newUsage.PodUsages = make(map[string]*PodUsage, len(nu.PodUsages))
+		for k, v := range nu.PodUsages {
+			podUsage := *v
+			newUsage.PodUsages[k] = &podUsage
+		}
+	}
 	return newUsage
 }
 
@@ -135,6 +229,8 @@ func NewNodeInfo(node *v1.Node) *NodeInfo {
 		Idle:      EmptyResource(),
 		Used:      EmptyResource(),
 
+		DaemonUsed: EmptyResource(),
+
 		Allocatable:   EmptyResource(),
 		Capacity:      EmptyResource(),
 		ResourceUsage: &NodeUsage{},
@@ -165,9 +261,13 @@ func NewNodeInfo(node *v1.Node) *NodeInfo {
 // RefreshNumaSchedulerInfoByCrd used to update scheduler numa information based the CRD numatopo
 func (ni *NodeInfo) RefreshNumaSchedulerInfoByCrd() {
 	if ni.NumaInfo == nil {
-		ni.NumaSchedulerInfo = nil
+		if ni.NumaSchedulerInfo != nil {
+			ni.NumaSchedulerInfo = nil
+			ni.generation = nextGeneration()
+		}
 		return
 	}
+	ni.generation = nextGeneration()
 
 	tmp := ni.NumaInfo.DeepCopy()
 	if ni.NumaChgFlag == NumaInfoMoreFlag {
@@ -215,6 +315,8 @@ func (ni *NodeInfo) Clone() *NodeInfo {
 
 	res.Others = ni.CloneOthers()
 	res.ImageStates = ni.CloneImageSummary()
+	res.Stability = ni.Stability.Clone()
+	res.generation = ni.generation
 	return res
 }
 
@@ -318,6 +420,7 @@ func (ni *NodeInfo) setNodeState(node *v1.Node) {
 
 // SetNode sets kubernetes node object to nodeInfo object
 func (ni *NodeInfo) SetNode(node *v1.Node) {
+	ni.generation = nextGeneration()
 	ni.setNodeState(node)
 	if !ni.Ready() {
 		klog.Warningf("Failed to set node info for %s, phase: %s, reason: %s",
@@ -364,24 +467,41 @@ func (ni *NodeInfo) setNode(node *v1.Node) {
 	ni.Pipelined = EmptyResource()
 	ni.Idle = NewResource(node.Status.Allocatable).Add(ni.OversubscriptionResource)
 	ni.Used = EmptyResource()
+	ni.DaemonUsed = EmptyResource()
 
 	for _, ti := range ni.Tasks {
 		switch ti.Status {
 		case Releasing:
 			ni.allocateIdleResource(ti)
 			ni.Releasing.Add(ti.Resreq)
-			ni.Used.Add(ti.Resreq)
+			ni.addUsed(ti)
 			ni.addResource(ti.Pod)
 		case Pipelined:
 			ni.Pipelined.Add(ti.Resreq)
 		default:
 			ni.allocateIdleResource(ti)
-			ni.Used.Add(ti.Resreq)
+			ni.addUsed(ti)
 			ni.addResource(ti.Pod)
 		}
 	}
 }
 
+// addUsed adds the request of task to the used resource of node.
+func (ni *NodeInfo) addUsed(ti *TaskInfo) {
+	ni.Used.Add(ti.Resreq)
+	if ti.Class != PodClassWorkload {
+		ni.DaemonUsed.Add(ti.Resreq)
+	}
+}
+
+// subUsed subtracts the request of task from the used resource of node.
+func (ni *NodeInfo) subUsed(ti *TaskInfo) {
+	ni.Used.Sub(ti.Resreq)
+	if ti.Class != PodClassWorkload {
+		ni.DaemonUsed.Sub(ti.Resreq)
+	}
+}
+
 func (ni *NodeInfo) allocateIdleResource(ti *TaskInfo) {
 	ok, resources := ti.Resreq.LessEqualWithResourcesName(ni.Idle, Zero)
 	if ok {
@@ -418,7 +538,7 @@ func (ni *NodeInfo) AddTask(task *TaskInfo) error {
 		case Releasing:
 			ni.allocateIdleResource(ti)
 			ni.Releasing.Add(ti.Resreq)
-			ni.Used.Add(ti.Resreq)
+			ni.addUsed(ti)
 			ni.addResource(ti.Pod)
 		case Pipelined:
 			ni.Pipelined.Add(ti.Resreq)
@@ -428,14 +548,15 @@ func (ni *NodeInfo) AddTask(task *TaskInfo) error {
 				return fmt.Errorf("node %s resources %v are not enough to put task <%s/%s>, idle: %s, req: %s", ni.Name, resNames, ti.Namespace, ti.Name, ni.Idle.String(), ti.Resreq.String())
 			}
 			ni.allocateIdleResource(ti)
-			ni.Used.Add(ti.Resreq)
+			ni.addUsed(ti)
 			ni.addResource(ti.Pod)
 		default:
 			ni.allocateIdleResource(ti)
-			ni.Used.Add(ti.Resreq)
+			ni.addUsed(ti)
 			ni.addResource(ti.Pod)
 		}
 	}
+	ni.generation = nextGeneration()
 
 	if ni.NumaInfo != nil {
 		ni.NumaInfo.AddTask(ti)
@@ -449,6 +570,14 @@ func (ni *NodeInfo) AddTask(task *TaskInfo) error {
 	return nil
 }
 
+// SetExclusiveNode sets whether the copy of task on node requests the whole node.
+func (ni *NodeInfo) SetExclusiveNode(task *TaskInfo, exclusive bool) {
+	if ti, found := ni.Tasks[PodKey(task.Pod)]; found && ti.ExclusiveNode != exclusive {
+		ti.ExclusiveNode = exclusive
+		ni.generation = nextGeneration()
+	}
+}
+
 // RemoveTask used to remove a task from nodeInfo object.
 //
 // If error occurs both task and node are guaranteed to be in the original state.
@@ -461,19 +590,20 @@ func (ni *NodeInfo) RemoveTask(ti *TaskInfo) error {
 			ti.Namespace, ti.Name, ni.Name)
 		return nil
 	}
+	ni.generation = nextGeneration()
 
 	if ni.Node != nil {
 		switch task.Status {
 		case Releasing:
 			ni.Releasing.Sub(task.Resreq)
 			ni.Idle.Add(task.Resreq)
-			ni.Used.Sub(task.Resreq)
+			ni.subUsed(task)
 			ni.subResource(ti.Pod)
 		case Pipelined:
 			ni.Pipelined.Sub(task.Resreq)
 		default:
 			ni.Idle.Add(task.Resreq)
-			ni.Used.Sub(task.Resreq)
+			ni.subUsed(task)
 			ni.subResource(ti.Pod)
 		}
 	}
@@ -29,10 +29,15 @@ import (
 )
 
 func nodeInfoEqual(l, r *NodeInfo) bool {
+	// generation counts the changes of node, it is not part of the state compared.
+	lg, rg := l.generation, r.generation
+	l.generation, r.generation = 0, 0
+	defer func() { l.generation, r.generation = lg, rg }()
 	return reflect.DeepEqual(l, r)
 }
 
 func TestNodeInfo_AddPod(t *testing.T) {
+	controller := true
 	// case1
 	case01Node := buildNode("n1", buildResourceList("8000m", "10G"))
 	case01Pod1 := buildPod("c1", "p1", "n1", v1.PodRunning, buildResourceList("1000m", "1G"), []metav1.OwnerReference{}, make(map[string]string))
@@ -40,6 +45,11 @@ func TestNodeInfo_AddPod(t *testing.T) {
 	// case2
 	case02Node := buildNode("n2", buildResourceList("2000m", "1G"))
 	case02Pod1 := buildPod("c2", "p1", "n2", v1.PodUnknown, buildResourceList("1000m", "2G"), []metav1.OwnerReference{}, make(map[string]string))
+	// case3
+	case03Node := buildNode("n3", buildResourceList("8000m", "10G"))
+	case03Pod1 := buildPod("c3", "p1", "n3", v1.PodRunning, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds1", Controller: &controller}}, make(map[string]string))
+	case03Pod2 := buildPod("c3", "p2", "n3", v1.PodRunning, buildResourceList("2000m", "2G"), []metav1.OwnerReference{}, make(map[string]string))
 
 	tests := []struct {
 		name            string
@@ -57,6 +67,7 @@ func TestNodeInfo_AddPod(t *testing.T) {
 				Node:                     case01Node,
 				Idle:                     buildResource("5000m", "7G"),
 				Used:                     buildResource("3000m", "3G"),
+				DaemonUsed:               EmptyResource(),
 				Releasing:                EmptyResource(),
 				Pipelined:                EmptyResource(),
 				OversubscriptionResource: EmptyResource(),
@@ -84,6 +95,7 @@ func TestNodeInfo_AddPod(t *testing.T) {
 				Node:                     case02Node,
 				Idle:                     buildResource("1000m", "-1G"),
 				Used:                     buildResource("1000m", "2G"),
+				DaemonUsed:               EmptyResource(),
 				Releasing:                EmptyResource(),
 				Pipelined:                EmptyResource(),
 				OversubscriptionResource: EmptyResource(),
@@ -102,6 +114,34 @@ func TestNodeInfo_AddPod(t *testing.T) {
 			},
 			expectedFailure: false,
 		},
+		{
+			name: "add 1 running daemonset pod and 1 running non-owner pod",
+			node: case03Node,
+			pods: []*v1.Pod{case03Pod1, case03Pod2},
+			expected: &NodeInfo{
+				Name:                     "n3",
+				Node:                     case03Node,
+				Idle:                     buildResource("5000m", "7G"),
+				Used:                     buildResource("3000m", "3G"),
+				DaemonUsed:               buildResource("1000m", "1G"),
+				Releasing:                EmptyResource(),
+				Pipelined:                EmptyResource(),
+				OversubscriptionResource: EmptyResource(),
+				Allocatable:              buildResource("8000m", "10G"),
+				Capacity:                 buildResource("8000m", "10G"),
+				ResourceUsage:            &NodeUsage{},
+				State:                    NodeState{Phase: Ready},
+				Tasks: map[TaskID]*TaskInfo{
+					"c3/p1": NewTaskInfo(case03Pod1),
+					"c3/p2": NewTaskInfo(case03Pod2),
+				},
+				Others: map[string]interface{}{
+					GPUSharingDevice: gpushare.NewGPUDevices("n3", case03Node),
+					vgpu.DeviceName:  vgpu.NewGPUDevices("n3", case03Node),
+				},
+				ImageStates: make(map[string]*k8sframework.ImageStateSummary),
+			},
+		},
 	}
 
 	for i, test := range tests {
@@ -149,6 +189,7 @@ func TestNodeInfo_RemovePod(t *testing.T) {
 				Node:                     case01Node,
 				Idle:                     buildResource("4000m", "6G"),
 				Used:                     buildResource("4000m", "4G"),
+				DaemonUsed:               EmptyResource(),
 				OversubscriptionResource: EmptyResource(),
 				Releasing:                EmptyResource(),
 				Pipelined:                EmptyResource(),
@@ -215,6 +256,7 @@ func TestNodeInfo_SetNode(t *testing.T) {
 				Node:                     case01Node2,
 				Idle:                     buildResource("-1", "-1G"),
 				Used:                     buildResource("9", "9G"),
+				DaemonUsed:               EmptyResource(),
 				OversubscriptionResource: EmptyResource(),
 				Releasing:                EmptyResource(),
 				Pipelined:                EmptyResource(),
@@ -238,6 +280,7 @@ func TestNodeInfo_SetNode(t *testing.T) {
 				Node:                     case01Node1,
 				Idle:                     buildResource("1", "1G"),
 				Used:                     buildResource("9", "9G"),
+				DaemonUsed:               EmptyResource(),
 				OversubscriptionResource: EmptyResource(),
 				Releasing:                EmptyResource(),
 				Pipelined:                EmptyResource(),
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// NodeStabilityWindow is how long the unstable events of node are remembered.
+const NodeStabilityWindow = time.Hour
+
+// NodeStability records the recent unstable events of node observed by scheduler cache.
+type NodeStability struct {
+	// ReadyFlaps are the times when the Ready condition of node changes
+	ReadyFlaps []time.Time
+	// Restarts are the times when the boot ID of node changes, an upgrade of kubelet alone is not a restart of node
+	Restarts []time.Time
+}
+
+// Record records the unstable events between oldNode and newNode at now, and forgets the events out of window.
+func (ns *NodeStability) Record(oldNode, newNode *v1.Node, now time.Time) {
+	if oldNode != nil && newNode != nil {
+		if nodeReadyStatus(oldNode) != nodeReadyStatus(newNode) {
+			ns.ReadyFlaps = append(ns.ReadyFlaps, now)
+		}
+		if oldNode.Status.NodeInfo.BootID != newNode.Status.NodeInfo.BootID {
+			ns.Restarts = append(ns.Restarts, now)
+		}
+	}
+
+	since := now.Add(-NodeStabilityWindow)
+	ns.ReadyFlaps = eventsSince(ns.ReadyFlaps, since)
+	ns.Restarts = eventsSince(ns.Restarts, since)
+}
+
+// RecordStability records the unstable events of node between oldNode and newNode at now.
+func (ni *NodeInfo) RecordStability(oldNode, newNode *v1.Node, now time.Time) {
+	ni.generation = nextGeneration()
+	if ni.Stability == nil {
+		ni.Stability = &NodeStability{}
+	}
+	ni.Stability.Record(oldNode, newNode, now)
+}
+
+// Events returns the number of Ready flaps and restarts of node since the given time.
+func (ns *NodeStability) Events(since time.Time) int {
+	if ns == nil {
+		return 0
+	}
+	return len(eventsSince(ns.ReadyFlaps, since)) + len(eventsSince(ns.Restarts, since))
+}
+
+// Clone clones the NodeStability.
+func (ns *NodeStability) Clone() *NodeStability {
+	if ns == nil {
+		return nil
+	}
+	return &NodeStability{
+		ReadyFlaps: append([]time.Time(nil), ns.ReadyFlaps...),
+		Restarts:   append([]time.Time(nil), ns.Restarts...),
+	}
+}
+
+func nodeReadyStatus(node *v1.Node) v1.ConditionStatus {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			return cond.Status
+		}
+	}
+	return v1.ConditionUnknown
+}
+
+// eventsSince returns the events not before since, events are in time order.
+func eventsSince(events []time.Time, since time.Time) []time.Time {
+	for i, event := range events {
+		if !event.Before(since) {
+			return events[i:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNodeStabilityRecord(t *testing.T) {
+	newNode := func(ready v1.ConditionStatus, bootID string, kubeletVersion ...string) *v1.Node {
+		version := "v1.25.0"
+		if len(kubeletVersion) > 0 {
+			version = kubeletVersion[0]
+		}
+		return &v1.Node{
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+				NodeInfo:   v1.NodeSystemInfo{BootID: bootID, KubeletVersion: version},
+			},
+		}
+	}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ns := &NodeStability{}
+	ns.Record(newNode(v1.ConditionTrue, "b1"), newNode(v1.ConditionTrue, "b1"), start)
+	if events := ns.Events(start.Add(-NodeStabilityWindow)); events != 0 {
+		t.Errorf("expected no events for unchanged node, got %d", events)
+	}
+	ns.Record(newNode(v1.ConditionTrue, "b1"), newNode(v1.ConditionTrue, "b1", "v1.26.0"), start)
+	if events := ns.Events(start.Add(-NodeStabilityWindow)); events != 0 {
+		t.Errorf("expected no restart for kubelet upgrade without reboot, got %d events", events)
+	}
+
+	ns.Record(newNode(v1.ConditionTrue, "b1"), newNode(v1.ConditionFalse, "b1"), start)
+	ns.Record(newNode(v1.ConditionFalse, "b1"), newNode(v1.ConditionTrue, "b2"), start.Add(30*time.Minute))
+	now := start.Add(45 * time.Minute)
+	if events := ns.Events(now.Add(-NodeStabilityWindow)); events != 3 {
+		t.Errorf("expected 2 flaps and 1 restart, got %d events", events)
+	}
+
+	later := start.Add(NodeStabilityWindow + time.Minute)
+	ns.Record(newNode(v1.ConditionTrue, "b2"), newNode(v1.ConditionTrue, "b2"), later)
+	if len(ns.ReadyFlaps) != 1 || len(ns.Restarts) != 1 {
+		t.Errorf("expected events out of window forgotten, got flaps %v, restarts %v", ns.ReadyFlaps, ns.Restarts)
+	}
+}
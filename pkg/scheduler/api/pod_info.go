@@ -21,6 +21,8 @@ import (
 	"strconv"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/features"
@@ -140,6 +142,48 @@ func GetPodTopologyInfo(pod *v1.Pod) *TopologyInfo {
 	return &info
 }
 
+// GetPodEmptyDirSizeLimit returns the total size limit of emptyDir volumes of Pod on the disk of node.
+func GetPodEmptyDirSizeLimit(pod *v1.Pod) resource.Quantity {
+	var total resource.Quantity
+	for _, volume := range pod.Spec.Volumes {
+		emptyDir := volume.EmptyDir
+		if emptyDir == nil || emptyDir.Medium == v1.StorageMediumMemory || emptyDir.SizeLimit == nil {
+			continue
+		}
+		total.Add(*emptyDir.SizeLimit)
+	}
+	return total
+}
+
+// PodClass classifies the pods on nodes in the accounting of nodes, so that plugins may count the pods running on
+// every node apart from the workloads.
+type PodClass string
+
+const (
+	// PodClassWorkload is the class of the pods of workloads, e.g. the tasks of jobs and deployments.
+	PodClassWorkload PodClass = ""
+	// PodClassDaemonSet is the class of the pods of DaemonSets.
+	PodClassDaemonSet PodClass = "DaemonSet"
+	// PodClassStatic is the class of static pods, i.e. their mirror pods on the API server.
+	PodClassStatic PodClass = "Static"
+)
+
+// GetPodClass returns the class of pod by its mirror annotation and controller.
+func GetPodClass(pod *v1.Pod) PodClass {
+	if _, found := pod.Annotations[v1.MirrorPodAnnotationKey]; found {
+		return PodClassStatic
+	}
+	if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil {
+		switch controllerRef.Kind {
+		case "DaemonSet":
+			return PodClassDaemonSet
+		case "Node":
+			return PodClassStatic
+		}
+	}
+	return PodClassWorkload
+}
+
 // GetPodResourceWithoutInitContainers returns Pod's resource request, it does not contain
 // init containers' resource request.
 func GetPodResourceWithoutInitContainers(pod *v1.Pod) *Resource {
@@ -148,6 +192,11 @@ func GetPodResourceWithoutInitContainers(pod *v1.Pod) *Resource {
 		result.Add(NewResource(container.Resources.Requests))
 	}
 
+	// emptyDir volumes on the disk of node consume ephemeral storage besides the containers
+	if sizeLimit := GetPodEmptyDirSizeLimit(pod); !sizeLimit.IsZero() {
+		result.AddScalar(v1.ResourceEphemeralStorage, float64(sizeLimit.MilliValue()))
+	}
+
 	// if PodOverhead feature is supported, add overhead for running a pod
 	if pod.Spec.Overhead != nil && utilfeature.DefaultFeatureGate.Enabled(features.PodOverhead) {
 		result.Add(NewResource(pod.Spec.Overhead))
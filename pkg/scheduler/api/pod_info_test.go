@@ -98,6 +98,7 @@ func TestGetPodResourceRequest(t *testing.T) {
 }
 
 func TestGetPodResourceWithoutInitContainers(t *testing.T) {
+	diskSizeLimit := resource.MustParse("2Gi")
 	tests := []struct {
 		name             string
 		pod              *v1.Pod
@@ -179,6 +180,34 @@ func TestGetPodResourceWithoutInitContainers(t *testing.T) {
 			},
 			expectedResource: NewResource(buildResourceList("3500m", "3G")),
 		},
+		{
+			name: "get resource for pod with emptyDir volumes",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{
+									v1.ResourceCPU:              resource.MustParse("1000m"),
+									v1.ResourceMemory:           resource.MustParse("1G"),
+									v1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+					Volumes: []v1.Volume{
+						{Name: "scratch", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{SizeLimit: &diskSizeLimit}}},
+						{Name: "shm", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory, SizeLimit: &diskSizeLimit}}},
+						{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+					},
+				},
+			},
+			expectedResource: NewResource(v1.ResourceList{
+				v1.ResourceCPU:              resource.MustParse("1000m"),
+				v1.ResourceMemory:           resource.MustParse("1G"),
+				v1.ResourceEphemeralStorage: resource.MustParse("3Gi"),
+			}),
+		},
 	}
 
 	for i, test := range tests {
@@ -243,3 +272,62 @@ func TestGetGPUIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPodClass(t *testing.T) {
+	controller := true
+	testCases := []struct {
+		name string
+		pod  *v1.Pod
+		want PodClass
+	}{
+		{
+			name: "pod without controller",
+			pod:  &v1.Pod{},
+			want: PodClassWorkload,
+		},
+		{
+			name: "pod of replicaset",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs1", Controller: &controller}},
+				},
+			},
+			want: PodClassWorkload,
+		},
+		{
+			name: "pod of daemonset",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds1", Controller: &controller}},
+				},
+			},
+			want: PodClassDaemonSet,
+		},
+		{
+			name: "mirror pod",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{v1.MirrorPodAnnotationKey: "hash"},
+				},
+			},
+			want: PodClassStatic,
+		},
+		{
+			name: "pod controlled by node",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Node", Name: "n1", Controller: &controller}},
+				},
+			},
+			want: PodClassStatic,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := GetPodClass(tc.pod); got != tc.want {
+				t.Errorf("expected class %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -17,7 +17,13 @@ limitations under the License.
 package api
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/scheduling"
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
@@ -40,8 +46,35 @@ type QueueInfo struct {
 	// Hierarchy is a list of node name along the
 	// path from the root to the node itself.
 	Hierarchy string
+	// Parent is the name of the parent queue by scheduling.volcano.sh/parent-queue annotation, empty for the
+	// queues at the top of the hierarchy.
+	Parent string
+	// Paused means the scheduling of jobs in queue is frozen by
+	// scheduling.volcano.sh/paused annotation.
+	Paused bool
+	// Fill means the queue is a best-effort fill queue by scheduling.volcano.sh/queue-type annotation,
+	// it never accrues deserved share and its jobs only run on otherwise-idle resource.
+	Fill bool
+	// VictimGracePeriodSeconds overrides the termination grace period of tasks in queue evicted by preempt
+	// and reclaim, see scheduling.volcano.sh/victim-grace-period-seconds annotation.
+	VictimGracePeriodSeconds *int64
+	// NotBefore and ScheduleWindow are the defaults of jobs in queue, see scheduling.volcano.sh/not-before
+	// and scheduling.volcano.sh/schedule-window annotations.
+	NotBefore      *time.Time
+	ScheduleWindow *ScheduleWindow
+	// GangSchedulingTimeout is the default gang scheduling timeout of jobs in queue, see
+	// scheduling.volcano.sh/gang-scheduling-timeout annotation.
+	GangSchedulingTimeout time.Duration
 
 	Queue *scheduling.Queue
+
+	// generation is given on creation of queue and kept by Clone, queues are not changed but replaced in cache.
+	generation uint64
+}
+
+// Generation returns the generation of queue, which is kept by Clone.
+func (q *QueueInfo) Generation() uint64 {
+	return q.generation
 }
 
 // NewQueueInfo creates new queueInfo object
@@ -53,8 +86,20 @@ func NewQueueInfo(queue *scheduling.Queue) *QueueInfo {
 		Weight:    queue.Spec.Weight,
 		Hierarchy: queue.Annotations[v1beta1.KubeHierarchyAnnotationKey],
 		Weights:   queue.Annotations[v1beta1.KubeHierarchyWeightAnnotationKey],
+		Parent:    queue.Annotations[ParentQueueAnnotation],
+		Paused:    IsSchedulingPaused(queue.Annotations),
+		Fill:      IsFillQueue(queue.Annotations),
+
+		VictimGracePeriodSeconds: GetVictimGracePeriod(queue.Annotations),
+
+		NotBefore:      GetNotBefore(queue.Annotations),
+		ScheduleWindow: GetScheduleWindow(queue.Annotations),
+
+		GangSchedulingTimeout: GetGangSchedulingTimeout(queue.Annotations),
 
 		Queue: queue,
+
+		generation: nextGeneration(),
 	}
 }
 
@@ -66,7 +111,20 @@ func (q *QueueInfo) Clone() *QueueInfo {
 		Weight:    q.Weight,
 		Hierarchy: q.Hierarchy,
 		Weights:   q.Weights,
-		Queue:     q.Queue,
+		Parent:    q.Parent,
+		Paused:    q.Paused,
+		Fill:      q.Fill,
+
+		VictimGracePeriodSeconds: q.VictimGracePeriodSeconds,
+
+		NotBefore:      q.NotBefore,
+		ScheduleWindow: q.ScheduleWindow,
+
+		GangSchedulingTimeout: q.GangSchedulingTimeout,
+
+		Queue: q.Queue,
+
+		generation: q.generation,
 	}
 }
 
@@ -86,3 +144,48 @@ func (q *QueueInfo) Reclaimable() bool {
 
 	return *q.Queue.Spec.Reclaimable
 }
+
+// ResolveQueueHierarchy drops the parents of queues which are not found or would close a cycle, so those queues are
+// at the top of the hierarchy, and sets the hierarchy and weights of the queues with parents and without the
+// hierarchy annotation, or with children, by the names and weights of their ancestors, e.g. root/a/b and 1/2/3.
+func ResolveQueueHierarchy(queues map[QueueID]*QueueInfo) {
+	names := make([]string, 0, len(queues))
+	for id := range queues {
+		names = append(names, string(id))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		visited := map[string]bool{}
+		for queue := queues[QueueID(name)]; queue.Parent != ""; queue = queues[QueueID(queue.Parent)] {
+			visited[queue.Name] = true
+			if _, found := queues[QueueID(queue.Parent)]; !found || visited[queue.Parent] {
+				klog.Warningf("Parent queue <%s> of queue <%s> is not found or is its descendant, ignore it",
+					queue.Parent, queue.Name)
+				queue.Parent = ""
+				break
+			}
+		}
+	}
+
+	parents := map[string]bool{}
+	for _, queue := range queues {
+		parents[queue.Parent] = true
+	}
+	for _, name := range names {
+		queue := queues[QueueID(name)]
+		if (queue.Parent == "" && !parents[queue.Name]) || queue.Hierarchy != "" {
+			continue
+		}
+		paths, weights := []string{}, []string{}
+		for ancestor := queue; ancestor != nil; ancestor = queues[QueueID(ancestor.Parent)] {
+			paths = append([]string{ancestor.Name}, paths...)
+			weights = append([]string{strconv.Itoa(int(ancestor.Weight))}, weights...)
+			if ancestor.Parent == "" {
+				break
+			}
+		}
+		queue.Hierarchy = strings.Join(append([]string{"root"}, paths...), "/")
+		queue.Weights = strings.Join(append([]string{"1"}, weights...), "/")
+	}
+}
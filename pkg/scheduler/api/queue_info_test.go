@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func TestResolveQueueHierarchy(t *testing.T) {
+	buildQueue := func(name, parent string, weight int32, annotations map[string]string) *QueueInfo {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if parent != "" {
+			annotations[ParentQueueAnnotation] = parent
+		}
+		return NewQueueInfo(&scheduling.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Spec:       scheduling.QueueSpec{Weight: weight},
+		})
+	}
+	queues := map[QueueID]*QueueInfo{}
+	for _, queue := range []*QueueInfo{
+		buildQueue("a", "", 2, nil),
+		buildQueue("a1", "a", 3, nil),
+		buildQueue("a11", "a1", 1, nil),
+		buildQueue("flat", "", 1, nil),
+		buildQueue("orphan", "missing", 1, nil),
+		buildQueue("x", "y", 1, nil),
+		buildQueue("y", "x", 1, nil),
+		buildQueue("annotated", "a", 1, map[string]string{
+			v1beta1.KubeHierarchyAnnotationKey:       "root/sci",
+			v1beta1.KubeHierarchyWeightAnnotationKey: "1/5",
+		}),
+	} {
+		queues[queue.UID] = queue
+	}
+
+	ResolveQueueHierarchy(queues)
+
+	expected := map[string]struct {
+		parent    string
+		hierarchy string
+		weights   string
+	}{
+		"a":      {hierarchy: "root/a", weights: "1/2"},
+		"a1":     {parent: "a", hierarchy: "root/a/a1", weights: "1/2/3"},
+		"a11":    {parent: "a1", hierarchy: "root/a/a1/a11", weights: "1/2/3/1"},
+		"flat":   {},
+		"orphan": {},
+		// The parent of y closes the cycle walking from x.
+		"x":         {parent: "y", hierarchy: "root/y/x", weights: "1/1/1"},
+		"y":         {hierarchy: "root/y", weights: "1/1"},
+		"annotated": {parent: "a", hierarchy: "root/sci", weights: "1/5"},
+	}
+	for name, e := range expected {
+		queue := queues[QueueID(name)]
+		if queue.Parent != e.parent || queue.Hierarchy != e.hierarchy || queue.Weights != e.weights {
+			t.Errorf("queue %s: expected parent %q, hierarchy %q and weights %q, got %q, %q and %q",
+				name, e.parent, e.hierarchy, e.weights, queue.Parent, queue.Hierarchy, queue.Weights)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"math"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Verdict is the result of comparing a single resource dimension.
+type Verdict int
+
+const (
+	// VerdictLess means the left value is less than the right value.
+	VerdictLess Verdict = -1
+	// VerdictEqual means both values are equal within the minimal resource tolerance.
+	VerdictEqual Verdict = 0
+	// VerdictGreater means the left value is greater than the right value.
+	VerdictGreater Verdict = 1
+)
+
+// String returns the readable form of the verdict.
+func (v Verdict) String() string {
+	switch v {
+	case VerdictLess:
+		return "Less"
+	case VerdictEqual:
+		return "Equal"
+	case VerdictGreater:
+		return "Greater"
+	}
+	return "Unknown"
+}
+
+// DimensionSet describes which dimensions take part in a comparison and how a
+// dimension that is not defined by one of the operands is treated.
+//
+// CPU and memory are always defined, so the missing-dimension treatment only
+// applies to scalar resources.
+type DimensionSet struct {
+	// Names restricts the comparison to the given dimensions. If empty, CPU,
+	// memory and the union of the scalar resources of both operands are compared.
+	Names []v1.ResourceName
+	// LeftMissing is the value of a dimension not defined by the left operand.
+	LeftMissing DimensionDefaultValue
+	// RightMissing is the value of a dimension not defined by the right operand.
+	RightMissing DimensionDefaultValue
+}
+
+// NewDimensionSet returns a DimensionSet with the given missing-dimension treatment
+// for the left and right operand, restricted to names if any are given.
+func NewDimensionSet(leftMissing, rightMissing DimensionDefaultValue, names ...v1.ResourceName) DimensionSet {
+	return DimensionSet{
+		Names:        names,
+		LeftMissing:  leftMissing,
+		RightMissing: rightMissing,
+	}
+}
+
+// ComparisonResult holds the per-dimension verdicts of a comparison.
+type ComparisonResult map[v1.ResourceName]Verdict
+
+// Compare compares r with rr dimension by dimension according to ds.
+func (r *Resource) Compare(rr *Resource, ds DimensionSet) ComparisonResult {
+	names := ds.Names
+	if len(names) == 0 {
+		names = compareDimensions(r, rr)
+	}
+
+	result := make(ComparisonResult, len(names))
+	for _, name := range names {
+		l, lok := r.dimension(name)
+		rv, rok := rr.dimension(name)
+		result[name] = compareDimension(l, lok, ds.LeftMissing, rv, rok, ds.RightMissing)
+	}
+	return result
+}
+
+// AllLess returns true if r is less than rr in every compared dimension.
+func (cr ComparisonResult) AllLess() bool {
+	return cr.all(func(v Verdict) bool { return v == VerdictLess })
+}
+
+// AllLessEqual returns true if r is less than or equal with rr in every compared dimension.
+func (cr ComparisonResult) AllLessEqual() bool {
+	return cr.all(func(v Verdict) bool { return v != VerdictGreater })
+}
+
+// AllEqual returns true if r is equal with rr in every compared dimension.
+func (cr ComparisonResult) AllEqual() bool {
+	return cr.all(func(v Verdict) bool { return v == VerdictEqual })
+}
+
+// AnyLess returns true if r is less than rr in at least one compared dimension.
+func (cr ComparisonResult) AnyLess() bool {
+	return !cr.all(func(v Verdict) bool { return v != VerdictLess })
+}
+
+// AnyLessEqual returns true if r is less than or equal with rr in at least one compared dimension.
+func (cr ComparisonResult) AnyLessEqual() bool {
+	return !cr.all(func(v Verdict) bool { return v == VerdictGreater })
+}
+
+// Greater returns the sorted names of the dimensions in which r is greater than rr,
+// e.g. the dimensions that are insufficient when rr is a capacity.
+func (cr ComparisonResult) Greater() []v1.ResourceName {
+	var names []v1.ResourceName
+	for name, v := range cr {
+		if v == VerdictGreater {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func (cr ComparisonResult) all(fn func(Verdict) bool) bool {
+	for _, v := range cr {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// dimension returns the value of the given dimension and whether it is defined.
+func (r *Resource) dimension(name v1.ResourceName) (float64, bool) {
+	switch name {
+	case v1.ResourceCPU:
+		return r.MilliCPU, true
+	case v1.ResourceMemory:
+		return r.Memory, true
+	default:
+		if r.ScalarResources == nil {
+			return 0, false
+		}
+		v, ok := r.ScalarResources[name]
+		return v, ok
+	}
+}
+
+// compareDimensions returns CPU, memory and the union of the scalar resources of l and r.
+func compareDimensions(l, r *Resource) []v1.ResourceName {
+	names := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+	seen := map[v1.ResourceName]struct{}{}
+	for _, res := range []*Resource{l, r} {
+		for name := range res.ScalarResources {
+			if _, found := seen[name]; found {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func compareDimension(l float64, lok bool, lMissing DimensionDefaultValue,
+	r float64, rok bool, rMissing DimensionDefaultValue) Verdict {
+	lInf := !lok && lMissing == Infinity
+	rInf := !rok && rMissing == Infinity
+	switch {
+	case lInf && rInf:
+		return VerdictEqual
+	case lInf:
+		return VerdictGreater
+	case rInf:
+		return VerdictLess
+	}
+
+	if math.Abs(l-r) < minResource {
+		return VerdictEqual
+	}
+	if l < r {
+		return VerdictLess
+	}
+	return VerdictGreater
+}
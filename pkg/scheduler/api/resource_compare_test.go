@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestCompare(t *testing.T) {
+	const gpu = v1.ResourceName("nvidia.com/gpu")
+	const pods = v1.ResourceName("pods")
+
+	tests := []struct {
+		name     string
+		left     *Resource
+		right    *Resource
+		ds       DimensionSet
+		expected ComparisonResult
+	}{
+		{
+			name:  "empty resources are equal",
+			left:  &Resource{},
+			right: &Resource{},
+			ds:    NewDimensionSet(Zero, Zero),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictEqual,
+			},
+		},
+		{
+			name:  "differences below the minimal resource are equal",
+			left:  &Resource{MilliCPU: 1000.05, Memory: 10},
+			right: &Resource{MilliCPU: 1000, Memory: 20},
+			ds:    NewDimensionSet(Zero, Zero),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictLess,
+			},
+		},
+		{
+			name:  "scalar missing on the left treated as zero",
+			left:  &Resource{MilliCPU: 1000},
+			right: &Resource{MilliCPU: 1000, ScalarResources: map[v1.ResourceName]float64{gpu: 1000}},
+			ds:    NewDimensionSet(Zero, Zero),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictEqual,
+				gpu:               VerdictLess,
+			},
+		},
+		{
+			name:  "scalar missing on the right treated as zero",
+			left:  &Resource{ScalarResources: map[v1.ResourceName]float64{gpu: 1000}},
+			right: &Resource{},
+			ds:    NewDimensionSet(Zero, Zero),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictEqual,
+				gpu:               VerdictGreater,
+			},
+		},
+		{
+			name:  "scalar missing on the right treated as infinity",
+			left:  &Resource{ScalarResources: map[v1.ResourceName]float64{gpu: 1000}},
+			right: &Resource{ScalarResources: map[v1.ResourceName]float64{pods: 10}},
+			ds:    NewDimensionSet(Zero, Infinity),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictEqual,
+				gpu:               VerdictLess,
+				pods:              VerdictLess,
+			},
+		},
+		{
+			name:  "scalar missing on the left treated as infinity",
+			left:  &Resource{},
+			right: &Resource{ScalarResources: map[v1.ResourceName]float64{gpu: 1000}},
+			ds:    NewDimensionSet(Infinity, Zero),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictEqual,
+				v1.ResourceMemory: VerdictEqual,
+				gpu:               VerdictGreater,
+			},
+		},
+		{
+			name:  "scalar missing on both sides treated as infinity",
+			left:  &Resource{},
+			right: &Resource{},
+			ds:    NewDimensionSet(Infinity, Infinity, gpu),
+			expected: ComparisonResult{
+				gpu: VerdictEqual,
+			},
+		},
+		{
+			name:  "cpu and memory are always defined",
+			left:  &Resource{},
+			right: &Resource{MilliCPU: 1000},
+			ds:    NewDimensionSet(Infinity, Infinity),
+			expected: ComparisonResult{
+				v1.ResourceCPU:    VerdictLess,
+				v1.ResourceMemory: VerdictEqual,
+			},
+		},
+		{
+			name:  "only named dimensions are compared",
+			left:  &Resource{MilliCPU: 2000, Memory: 100, ScalarResources: map[v1.ResourceName]float64{gpu: 1000}},
+			right: &Resource{MilliCPU: 1000, Memory: 200},
+			ds:    NewDimensionSet(Zero, Zero, v1.ResourceMemory),
+			expected: ComparisonResult{
+				v1.ResourceMemory: VerdictLess,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.left.Compare(test.right, test.ds)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestComparisonResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       ComparisonResult
+		allLess      bool
+		allLessEqual bool
+		allEqual     bool
+		anyLess      bool
+		anyLessEqual bool
+		greater      []v1.ResourceName
+	}{
+		{
+			name:         "no dimensions",
+			result:       ComparisonResult{},
+			allLess:      true,
+			allLessEqual: true,
+			allEqual:     true,
+		},
+		{
+			name:         "all less",
+			result:       ComparisonResult{v1.ResourceCPU: VerdictLess, v1.ResourceMemory: VerdictLess},
+			allLess:      true,
+			allLessEqual: true,
+			anyLess:      true,
+			anyLessEqual: true,
+		},
+		{
+			name:         "less and equal",
+			result:       ComparisonResult{v1.ResourceCPU: VerdictLess, v1.ResourceMemory: VerdictEqual},
+			allLessEqual: true,
+			anyLess:      true,
+			anyLessEqual: true,
+		},
+		{
+			name:         "all equal",
+			result:       ComparisonResult{v1.ResourceCPU: VerdictEqual, v1.ResourceMemory: VerdictEqual},
+			allLessEqual: true,
+			allEqual:     true,
+			anyLessEqual: true,
+		},
+		{
+			name:         "equal and greater",
+			result:       ComparisonResult{v1.ResourceCPU: VerdictEqual, v1.ResourceMemory: VerdictGreater},
+			anyLessEqual: true,
+			greater:      []v1.ResourceName{v1.ResourceMemory},
+		},
+		{
+			name:    "all greater",
+			result:  ComparisonResult{v1.ResourceMemory: VerdictGreater, v1.ResourceCPU: VerdictGreater},
+			greater: []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.result.AllLess(); got != test.allLess {
+				t.Errorf("AllLess: expected %v, got %v", test.allLess, got)
+			}
+			if got := test.result.AllLessEqual(); got != test.allLessEqual {
+				t.Errorf("AllLessEqual: expected %v, got %v", test.allLessEqual, got)
+			}
+			if got := test.result.AllEqual(); got != test.allEqual {
+				t.Errorf("AllEqual: expected %v, got %v", test.allEqual, got)
+			}
+			if got := test.result.AnyLess(); got != test.anyLess {
+				t.Errorf("AnyLess: expected %v, got %v", test.anyLess, got)
+			}
+			if got := test.result.AnyLessEqual(); got != test.anyLessEqual {
+				t.Errorf("AnyLessEqual: expected %v, got %v", test.anyLessEqual, got)
+			}
+			if got := test.result.Greater(); !reflect.DeepEqual(got, test.greater) {
+				t.Errorf("Greater: expected %v, got %v", test.greater, got)
+			}
+		})
+	}
+}
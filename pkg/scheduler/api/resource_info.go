@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -129,6 +130,14 @@ func ResQuantity2Float64(resName v1.ResourceName, quantity resource.Quantity) fl
 	return resQuantity
 }
 
+// scalarResourcesPool holds the scalar resource maps given back by Release, so that
+// short-lived resources, e.g. the future idle of nodes in predicates, do not allocate a map per call.
+var scalarResourcesPool = sync.Pool{
+	New: func() interface{} {
+		return make(map[v1.ResourceName]float64)
+	},
+}
+
 // Clone is used to clone a resource type, which is a deep copy function.
 func (r *Resource) Clone() *Resource {
 	clone := &Resource{
@@ -138,7 +147,7 @@ func (r *Resource) Clone() *Resource {
 	}
 
 	if r.ScalarResources != nil {
-		clone.ScalarResources = make(map[v1.ResourceName]float64)
+		clone.ScalarResources = scalarResourcesPool.Get().(map[v1.ResourceName]float64)
 		for k, v := range r.ScalarResources {
 			clone.ScalarResources[k] = v
 		}
@@ -147,6 +156,44 @@ func (r *Resource) Clone() *Resource {
 	return clone
 }
 
+// CopyFrom overwrites r with the values of rr in place, reusing the scalar resource map of r if any.
+func (r *Resource) CopyFrom(rr *Resource) *Resource {
+	r.MilliCPU = rr.MilliCPU
+	r.Memory = rr.Memory
+	r.MaxTaskNum = rr.MaxTaskNum
+
+	if rr.ScalarResources == nil {
+		r.Release()
+		return r
+	}
+	if r.ScalarResources == nil {
+		r.ScalarResources = scalarResourcesPool.Get().(map[v1.ResourceName]float64)
+	}
+	for k := range r.ScalarResources {
+		if _, found := rr.ScalarResources[k]; !found {
+			delete(r.ScalarResources, k)
+		}
+	}
+	for k, v := range rr.ScalarResources {
+		r.ScalarResources[k] = v
+	}
+
+	return r
+}
+
+// Release gives the scalar resource map of r back to the pool. It must only be called
+// on resources which are not referenced anymore, e.g. a temporary clone.
+func (r *Resource) Release() {
+	if r.ScalarResources == nil {
+		return
+	}
+	for k := range r.ScalarResources {
+		delete(r.ScalarResources, k)
+	}
+	scalarResourcesPool.Put(r.ScalarResources)
+	r.ScalarResources = nil
+}
+
 // String returns resource details in string format
 func (r *Resource) String() string {
 	str := fmt.Sprintf("cpu %0.2f, memory %0.2f", r.MilliCPU, r.Memory)
@@ -231,10 +278,13 @@ func (r *Resource) Add(rr *Resource) *Resource {
 	r.MilliCPU += rr.MilliCPU
 	r.Memory += rr.Memory
 
+	if len(rr.ScalarResources) == 0 {
+		return r
+	}
+	if r.ScalarResources == nil {
+		r.ScalarResources = scalarResourcesPool.Get().(map[v1.ResourceName]float64)
+	}
 	for rName, rQuant := range rr.ScalarResources {
-		if r.ScalarResources == nil {
-			r.ScalarResources = map[v1.ResourceName]float64{}
-		}
 		r.ScalarResources[rName] += rQuant
 	}
 
@@ -534,6 +584,10 @@ func (r *Resource) Equal(rr *Resource, defaultValue DimensionDefaultValue) bool
 // Diff calculate the difference between two resource object
 // Note: if `defaultValue` equals `Infinity`, the difference between two values will be `Infinity`, marked as -1
 func (r *Resource) Diff(rr *Resource, defaultValue DimensionDefaultValue) (*Resource, *Resource) {
+	if len(r.ScalarResources) == 0 && len(rr.ScalarResources) == 0 {
+		return r.diffWithoutScalars(rr)
+	}
+
 	leftRes := r.Clone()
 	rightRes := rr.Clone()
 	increasedVal := EmptyResource()
@@ -574,6 +628,27 @@ func (r *Resource) Diff(rr *Resource, defaultValue DimensionDefaultValue) (*Reso
 	return increasedVal, decreasedVal
 }
 
+// diffWithoutScalars is the fast path of Diff for resources without scalar resources, e.g. the
+// requests of most pods, which does not clone both resources to fill the default values of scalar resources.
+func (r *Resource) diffWithoutScalars(rr *Resource) (*Resource, *Resource) {
+	increasedVal := &Resource{ScalarResources: make(map[v1.ResourceName]float64)}
+	decreasedVal := &Resource{ScalarResources: make(map[v1.ResourceName]float64)}
+
+	if r.MilliCPU > rr.MilliCPU {
+		increasedVal.MilliCPU = r.MilliCPU - rr.MilliCPU
+	} else {
+		decreasedVal.MilliCPU = rr.MilliCPU - r.MilliCPU
+	}
+
+	if r.Memory > rr.Memory {
+		increasedVal.Memory = r.Memory - rr.Memory
+	} else {
+		decreasedVal.Memory = rr.Memory - r.Memory
+	}
+
+	return increasedVal, decreasedVal
+}
+
 // AddScalar adds a resource by a scalar value of this resource.
 func (r *Resource) AddScalar(name v1.ResourceName, quantity float64) {
 	r.SetScalar(name, r.ScalarResources[name]+quantity)
@@ -1396,3 +1396,165 @@ func TestResource_LessEqualResource(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceCopyFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  *Resource
+		src  *Resource
+	}{
+		{
+			name: "copy without scalar resources",
+			dst:  &Resource{MilliCPU: 1000, ScalarResources: map[v1.ResourceName]float64{"scalar.test/scalar1": 1000}},
+			src:  &Resource{MilliCPU: 2000, Memory: 1000, MaxTaskNum: 10},
+		},
+		{
+			name: "copy into resource without scalar resources",
+			dst:  &Resource{MilliCPU: 1000},
+			src:  &Resource{MilliCPU: 2000, ScalarResources: map[v1.ResourceName]float64{"scalar.test/scalar1": 1000}},
+		},
+		{
+			name: "stale scalar resources are removed",
+			dst:  &Resource{ScalarResources: map[v1.ResourceName]float64{"scalar.test/scalar1": 1000, "hugepages-test": 2000}},
+			src:  &Resource{ScalarResources: map[v1.ResourceName]float64{"hugepages-test": 3000}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.dst.CopyFrom(test.src)
+			if !reflect.DeepEqual(test.dst, test.src) {
+				t.Errorf("expected %v, got %v", test.src, test.dst)
+			}
+		})
+	}
+}
+
+func TestResourceRelease(t *testing.T) {
+	r := &Resource{MilliCPU: 1000, ScalarResources: map[v1.ResourceName]float64{"scalar.test/scalar1": 1000}}
+	clone := r.Clone()
+	clone.Release()
+	if clone.ScalarResources != nil {
+		t.Errorf("expected released resource has no scalar resources, got %v", clone.ScalarResources)
+	}
+
+	// A map taken from the pool must not carry values of released resources.
+	clone = (&Resource{ScalarResources: map[v1.ResourceName]float64{}}).Clone()
+	if len(clone.ScalarResources) != 0 {
+		t.Errorf("expected empty scalar resources, got %v", clone.ScalarResources)
+	}
+	if r.ScalarResources["scalar.test/scalar1"] != 1000 {
+		t.Errorf("expected original resource is not changed, got %v", r)
+	}
+}
+
+func buildBenchmarkNodeResources(num int) []*Resource {
+	resources := make([]*Resource, 0, num)
+	for i := 0; i < num; i++ {
+		r := &Resource{MilliCPU: 32000, Memory: 64 * 1024 * 1024 * 1024}
+		if i%2 == 0 {
+			r.ScalarResources = map[v1.ResourceName]float64{"nvidia.com/gpu": 8000, "pods": 110000}
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// buildBenchmarkNodes returns num nodes, half of them with GPUs, some resources of which are releasing or pipelined.
+func buildBenchmarkNodes(num int) []*NodeInfo {
+	nodes := make([]*NodeInfo, 0, num)
+	for i, idle := range buildBenchmarkNodeResources(num) {
+		node := &NodeInfo{Idle: idle, Releasing: EmptyResource(), Pipelined: EmptyResource()}
+		if i%4 == 0 {
+			node.Releasing = &Resource{MilliCPU: 2000, Memory: 1024 * 1024 * 1024}
+			node.Pipelined = &Resource{MilliCPU: 1000, Memory: 1024 * 1024 * 1024}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// BenchmarkNodeFutureIdle predicates the resource of a task on 10k nodes as the allocate action did before
+// the scalar resource maps were pooled.
+func BenchmarkNodeFutureIdle(b *testing.B) {
+	nodes := buildBenchmarkNodes(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			request.LessEqualWithResourcesName(node.FutureIdle(), Zero)
+		}
+	}
+}
+
+// BenchmarkNodeFutureIdleRelease predicates the resource of a task on 10k nodes as the allocate action does,
+// the future idle resources are released to the pool after the predicate.
+func BenchmarkNodeFutureIdleRelease(b *testing.B) {
+	nodes := buildBenchmarkNodes(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			futureIdle := node.FutureIdle()
+			request.LessEqualWithResourcesName(futureIdle, Zero)
+			futureIdle.Release()
+		}
+	}
+}
+
+func BenchmarkResourceClone(b *testing.B) {
+	nodes := buildBenchmarkNodeResources(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			idle := node.Clone()
+			request.LessEqual(idle, Zero)
+		}
+	}
+}
+
+func BenchmarkResourceCloneRelease(b *testing.B) {
+	nodes := buildBenchmarkNodeResources(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			idle := node.Clone()
+			request.LessEqual(idle, Zero)
+			idle.Release()
+		}
+	}
+}
+
+func BenchmarkResourceCopyFrom(b *testing.B) {
+	nodes := buildBenchmarkNodeResources(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	idle := EmptyResource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			idle.CopyFrom(node)
+			request.LessEqual(idle, Zero)
+		}
+	}
+}
+
+// BenchmarkResourceDiff diffs the resources of 10k nodes with the request of a task, half of the nodes
+// have no scalar resources and take the fast path of Diff.
+func BenchmarkResourceDiff(b *testing.B) {
+	nodes := buildBenchmarkNodeResources(10000)
+	request := &Resource{MilliCPU: 1000, Memory: 1024}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, node := range nodes {
+			node.Diff(request, Zero)
+		}
+	}
+}
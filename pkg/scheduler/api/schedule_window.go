@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ScheduleWindow is a daily time window in UTC, it wraps around midnight if End is before Start.
+type ScheduleWindow struct {
+	// Start is the offset of the start of window from midnight.
+	Start time.Duration
+	// End is the offset of the end of window from midnight.
+	End time.Duration
+}
+
+// ParseScheduleWindow parses the window in format of `HH:MM-HH:MM`, e.g. `22:00-06:00`.
+func ParseScheduleWindow(value string) (*ScheduleWindow, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("schedule window %q should be in format of HH:MM-HH:MM", value)
+	}
+
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q of schedule window: %v", part, err)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return &ScheduleWindow{Start: offsets[0], End: offsets[1]}, nil
+}
+
+// Contains checks whether t is inside the window, the window is open all day if Start equals End.
+func (w *ScheduleWindow) Contains(t time.Time) bool {
+	if w == nil || w.Start == w.End {
+		return true
+	}
+
+	t = t.UTC()
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// GetNotBefore returns the time of scheduling.volcano.sh/not-before annotation in RFC3339 format.
+func GetNotBefore(annotations map[string]string) *time.Time {
+	value, found := annotations[NotBeforeAnnotation]
+	if !found {
+		return nil
+	}
+
+	notBefore, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s", NotBeforeAnnotation, value)
+		return nil
+	}
+	return &notBefore
+}
+
+// GetScheduleWindow returns the window of scheduling.volcano.sh/schedule-window annotation.
+func GetScheduleWindow(annotations map[string]string) *ScheduleWindow {
+	value, found := annotations[ScheduleWindowAnnotation]
+	if !found {
+		return nil
+	}
+
+	window, err := ParseScheduleWindow(value)
+	if err != nil {
+		klog.Warningf("invalid %s=%s: %v", ScheduleWindowAnnotation, value, err)
+		return nil
+	}
+	return window
+}
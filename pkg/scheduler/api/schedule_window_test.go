@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		window  string
+		invalid bool
+		inside  []time.Time
+		outside []time.Time
+	}{
+		{
+			window:  "09:00-17:30",
+			inside:  []time.Time{at(9, 0), at(17, 29)},
+			outside: []time.Time{at(8, 59), at(17, 30), at(23, 0)},
+		},
+		{
+			window:  "22:00-06:00",
+			inside:  []time.Time{at(22, 0), at(0, 0), at(5, 59)},
+			outside: []time.Time{at(6, 0), at(12, 0), at(21, 59)},
+		},
+		{
+			window: "00:00-00:00",
+			inside: []time.Time{at(0, 0), at(12, 0)},
+		},
+		{
+			window:  "22:00",
+			invalid: true,
+		},
+		{
+			window:  "25:00-06:00",
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		window, err := ParseScheduleWindow(test.window)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("window %s: expected error, got %v", test.window, window)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("window %s: unexpected error: %v", test.window, err)
+		}
+		for _, inside := range test.inside {
+			if !window.Contains(inside) {
+				t.Errorf("window %s: expected %v inside", test.window, inside)
+			}
+		}
+		for _, outside := range test.outside {
+			if window.Contains(outside) {
+				t.Errorf("window %s: expected %v outside", test.window, outside)
+			}
+		}
+	}
+}
+
+func TestGetNotBefore(t *testing.T) {
+	if notBefore := GetNotBefore(map[string]string{NotBeforeAnnotation: "tonight"}); notBefore != nil {
+		t.Errorf("expected invalid not-before ignored, got %v", notBefore)
+	}
+
+	expected := time.Date(2023, 1, 1, 22, 0, 0, 0, time.UTC)
+	notBefore := GetNotBefore(map[string]string{NotBeforeAnnotation: "2023-01-01T22:00:00Z"})
+	if notBefore == nil || !notBefore.Equal(expected) {
+		t.Errorf("expected not-before %v, got %v", expected, notBefore)
+	}
+}
@@ -58,10 +58,15 @@ type Devices interface {
 	// that the pod can get scheduled with preemption.
 	// The accompanying status message should explain why the pod is unschedulable.
 	FilterNode(pod *v1.Pod) (int, string, error)
-	//Allocate action in predicate
-	Allocate(kubeClient kubernetes.Interface, pod *v1.Pod) error
+	//Allocate action in predicate, it returns the annotations recording the devices allocated to the 'pod',
+	//which are written to the pod before it is bound
+	Allocate(pod *v1.Pod) (map[string]string, error)
 	//Release action in predicate
-	Release(kubeClient kubernetes.Interface, pod *v1.Pod) error
+	Release(pod *v1.Pod) error
+
+	//following function used in binding
+	//LockNode locks the node for the device plugin before the device annotations are written to the 'pod'
+	LockNode(kubeClient kubernetes.Interface, pod *v1.Pod) error
 
 	//IgnredDevices notify vc-scheduler to ignore devices in return list
 	GetIgnoredDevices() []string
@@ -197,8 +197,20 @@ type TargetJobFn func([]*JobInfo) *JobInfo
 // ReservedNodesFn is the func declaration used to select the reserved nodes
 type ReservedNodesFn func()
 
+// EvictOptions are the options to evict a victim
+type EvictOptions struct {
+	// UseEvictionAPI evicts the victim by the Eviction API, which respects PodDisruptionBudgets,
+	// instead of deleting it directly.
+	UseEvictionAPI bool
+	// GracePeriodSeconds overrides the termination grace period of the victim if not nil.
+	GracePeriodSeconds *int64
+}
+
 // VictimTasksFn is the func declaration used to select victim tasks
 type VictimTasksFn func([]*TaskInfo) []*TaskInfo
 
+// VictimVetoFn is the func declaration used to veto victims selected for evictor, it returns the vetoed victims
+type VictimVetoFn func(evictor *TaskInfo, victims []*TaskInfo) []*TaskInfo
+
 // AllocatableFn is the func declaration used to check whether the task can be allocated
 type AllocatableFn func(*QueueInfo, *TaskInfo) bool
@@ -9,11 +9,33 @@ import (
 const (
 	// NodePodNumberExceeded means pods in node exceed the allocatable pod number
 	NodePodNumberExceeded = "node(s) pod number exceeded"
+	// NodeVolcanoPodNumberExceeded means pods scheduled by volcano in node exceed the cap of node
+	NodeVolcanoPodNumberExceeded = "node(s) volcano pod number exceeded"
 	// NodeResourceFitFailed means node could not fit the request of pod
 	NodeResourceFitFailed = "node(s) resource fit failed"
+	// NodeEphemeralStorageInsufficient means node could not fit the ephemeral storage request of pod
+	NodeEphemeralStorageInsufficient = "node(s) ephemeral storage insufficient"
+	// NodeTaskRoleNumberExceeded means tasks of the same task role of the job in node exceed the max per node
+	NodeTaskRoleNumberExceeded = "node(s) max tasks per node of task role exceeded"
+	// NodeExcludedByJob means node is excluded by the job, e.g. it failed the job before
+	NodeExcludedByJob = "node(s) excluded by job"
+	// NodeNotEmptyForExclusiveTask means node holds tasks of other jobs and can not be used by exclusive task
+	NodeNotEmptyForExclusiveTask = "node(s) not empty for exclusive task"
+	// NodeHeldExclusively means node is held exclusively by task of other job
+	NodeHeldExclusively = "node(s) held exclusively by other job"
+	// NodeDatasetNotCached means node is not a cache node of the datasets of task which must be cache-local
+	NodeDatasetNotCached = "node(s) not caching datasets of task"
+	// NodeArchMismatch means the architecture of node is not one of the architectures required by the job
+	NodeArchMismatch = "node(s) didn't match job architecture"
+	// NodeFeaturesMismatch means node does not have the node features required by the job
+	NodeFeaturesMismatch = "node(s) didn't match job node features"
+	// NodeReservedByAdvanceReservation means the capacity of node is fenced by an advance reservation of other queue
+	NodeReservedByAdvanceReservation = "node(s) reserved by advance reservation of other queue"
 
 	// AllNodeUnavailableMsg is the default error message
 	AllNodeUnavailableMsg = "all nodes are unavailable"
+	// PreemptionPolicyNeverMsg means preemption is not attempted for pod whose PreemptionPolicy is Never
+	PreemptionPolicyNeverMsg = "not eligible due to preemptionPolicy=Never"
 )
 
 // These are reasons for a pod's transition to a condition.
@@ -30,8 +52,9 @@ const (
 
 // FitErrors is set of FitError on many nodes
 type FitErrors struct {
-	nodes map[string]*FitError
-	err   string
+	nodes      map[string]*FitError
+	err        string
+	preemptErr string
 }
 
 // NewFitErrors returns an FitErrors
@@ -63,8 +86,20 @@ func (f *FitErrors) SetNodeError(nodeName string, err error) {
 	f.nodes[nodeName] = fe
 }
 
+// SetPreemptionError set the reason why preemption does not help in FitErrors
+func (f *FitErrors) SetPreemptionError(err string) {
+	f.preemptErr = err
+}
+
 // Error returns the final error message
 func (f *FitErrors) Error() string {
+	if f.preemptErr != "" {
+		return f.error() + " preemption: " + f.preemptErr + "."
+	}
+	return f.error()
+}
+
+func (f *FitErrors) error() string {
 	if f.err == "" {
 		f.err = fmt.Sprintf("0/%v", len(f.nodes)) + " nodes are unavailable"
 	}
@@ -72,12 +107,7 @@ func (f *FitErrors) Error() string {
 		return f.err
 	}
 
-	reasons := make(map[string]int)
-	for _, node := range f.nodes {
-		for _, reason := range node.Reasons {
-			reasons[reason]++
-		}
-	}
+	reasons := f.Reasons()
 
 	sortReasonsHistogram := func() []string {
 		reasonStrings := []string{}
@@ -91,6 +121,17 @@ func (f *FitErrors) Error() string {
 	return reasonMsg
 }
 
+// Reasons returns the number of nodes which could not fit for each reason
+func (f *FitErrors) Reasons() map[string]int {
+	reasons := make(map[string]int)
+	for _, node := range f.nodes {
+		for _, reason := range node.Reasons {
+			reasons[reason]++
+		}
+	}
+	return reasons
+}
+
 // FitError describe the reason why task could not fit that node
 type FitError struct {
 	taskNamespace string
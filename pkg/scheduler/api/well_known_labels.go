@@ -37,9 +37,99 @@ const (
 	OversubscriptionCPU = "volcano.sh/oversubscription-cpu"
 	// OversubscriptionMemory is the key of memory oversubscription
 	OversubscriptionMemory = "volcano.sh/oversubscription-memory"
+	// MaxVolcanoPodsAnnotation is the key of annotation on node which caps the number of pods scheduled by volcano on it
+	MaxVolcanoPodsAnnotation = "volcano.sh/max-volcano-pods"
 	// OfflineJobEvicting node will not schedule pod due to offline job evicting
 	OfflineJobEvicting = "volcano.sh/offline-job-evicting"
 
+	// SchedulingProfileAnnotation is the key of annotation on podgroup which selects the named scheduling profile
+	// of the job, the job is scheduled by the default profile if it is empty or unknown
+	SchedulingProfileAnnotation = "scheduling.volcano.sh/scheduling-profile"
+
+	// SchedulingPausedAnnotation is the key of annotation on queue/job/podgroup which freezes scheduling of it
+	SchedulingPausedAnnotation = "scheduling.volcano.sh/paused"
+
+	// QueueTypeAnnotation is the key of annotation on queue which tells the type of queue, see QueueTypeFill
+	QueueTypeAnnotation = "scheduling.volcano.sh/queue-type"
+	// QueueTypeFill is the type of best-effort queue which never accrues deserved share, its jobs only run on
+	// otherwise-idle resource and are reclaimed first
+	QueueTypeFill = "fill"
+
+	// VictimGracePeriodAnnotation is the key of annotation on queue with the termination grace period in seconds
+	// of the tasks in queue evicted by preempt and reclaim, it overrides the one of scheduler configuration
+	VictimGracePeriodAnnotation = "scheduling.volcano.sh/victim-grace-period-seconds"
+
+	// ExcludedNodesAnnotation is the key of annotation on podgroup which lists the comma separated
+	// nodes that tasks of the job must not be scheduled to, e.g. nodes failed the job before
+	ExcludedNodesAnnotation = "scheduling.volcano.sh/excluded-nodes"
+
+	// PreviousNodeAnnotation is the key of annotation on pod with the node the task ran on before it was restarted
+	// by the RestartTask policy, it is written by the job controller and the task prefers the node if it still fits
+	PreviousNodeAnnotation = "scheduling.volcano.sh/previous-node"
+
+	// ExclusiveNodeAnnotation is the key of annotation on pod/podgroup which requests whole nodes for the task/job,
+	// tasks are only placed on nodes without tasks of other jobs, and the nodes are held until the tasks complete
+	ExclusiveNodeAnnotation = "scheduling.volcano.sh/exclusive-node"
+
+	// MaxPerNodeAnnotation is the key of annotation on pod which caps the number of tasks of the same task role
+	// of the job on one node, it is usually set in the template of the task
+	MaxPerNodeAnnotation = "scheduling.volcano.sh/max-per-node"
+
+	// BindOrderAnnotation is the key of annotation on pod with the integer order the tasks of its task role are
+	// bound in among the tasks of the job dispatched together, lower orders first, it is usually set in the template
+	// of the task, e.g. to bind the master of an elastic training job after its workers
+	BindOrderAnnotation = "scheduling.volcano.sh/bind-order"
+	// BindRankOrderAnnotation is the key of annotation on pod with the order the tasks of its task role are bound in
+	// by their index, BindRankOrderAscending or BindRankOrderDescending
+	BindRankOrderAnnotation = "scheduling.volcano.sh/bind-rank-order"
+	// BindRankOrderAscending binds the tasks of a task role from rank 0, the default
+	BindRankOrderAscending = "ascending"
+	// BindRankOrderDescending binds the tasks of a task role towards rank 0, i.e. rank 0 last
+	BindRankOrderDescending = "descending"
+
+	// ResourceShapesAnnotation is the key of annotation on pod with the JSON list of alternative resources the task
+	// requests besides the pod requests in preference order, e.g. [{"nvidia.com/a100":"1"},{"nvidia.com/v100":"2"}]
+	ResourceShapesAnnotation = "scheduling.volcano.sh/resource-shapes"
+	// ResourceShapeAnnotation is the key of annotation on pod with the index of the resource shape allocated
+	// to the task, it is written when the task is bound for the runtime to consume
+	ResourceShapeAnnotation = "scheduling.volcano.sh/resource-shape"
+
+	// NodeArchAnnotation is the key of annotation on job/pod with the comma separated architectures of nodes
+	// the tasks of the job must run on, e.g. amd64,arm64
+	NodeArchAnnotation = "scheduling.volcano.sh/node-arch"
+	// NodeFeaturesAnnotation is the key of annotation on job/pod with the comma separated node features
+	// the tasks of the job require, see ParseNodeFeatures
+	NodeFeaturesAnnotation = "scheduling.volcano.sh/node-features"
+	// NodeFeatureLabelPrefix is the prefix of the labels of node features discovered by Node Feature Discovery,
+	// node features without prefix are the features of Node Feature Discovery
+	NodeFeatureLabelPrefix = "feature.node.kubernetes.io/"
+
+	// MinRuntimeAnnotation is the key of annotation on job/podgroup with the duration, e.g. 30m, the tasks of
+	// the job are protected from preemption and reclaim after the job starts running
+	MinRuntimeAnnotation = "scheduling.volcano.sh/min-runtime"
+
+	// NotBeforeAnnotation is the key of annotation on queue/podgroup with the RFC3339 time before which
+	// the job is not enqueued, the annotation of podgroup overrides the one of its queue
+	NotBeforeAnnotation = "scheduling.volcano.sh/not-before"
+	// ScheduleWindowAnnotation is the key of annotation on queue/podgroup with the daily window in UTC,
+	// e.g. 22:00-06:00, out of which the job is not enqueued, the annotation of podgroup overrides the one of its queue
+	ScheduleWindowAnnotation = "scheduling.volcano.sh/schedule-window"
+
+	// GangSchedulingTimeoutAnnotation is the key of annotation on queue/podgroup with the duration, e.g. 30m, a gang
+	// waits to be fully placed after it is enqueued, the annotation of podgroup overrides the one of its queue
+	GangSchedulingTimeoutAnnotation = "scheduling.volcano.sh/gang-scheduling-timeout"
+	// GangFallbackMinMemberAnnotation is the key of annotation on podgroup with the number of tasks the job starts
+	// with once its gang scheduling times out, the job is sent back to pending if it is not set
+	GangFallbackMinMemberAnnotation = "scheduling.volcano.sh/gang-fallback-min-member"
+
+	// ParentQueueAnnotation is the key of annotation on queue with the name of its parent queue, the queue shares the
+	// deserved resources of its parent with its siblings by weight
+	ParentQueueAnnotation = "scheduling.volcano.sh/parent-queue"
+
+	// NodeStabilitySensitiveAnnotation is the key of annotation on podgroup which tells whether tasks of the job
+	// prefer stable nodes, it defaults to true for gang jobs whose minMember is greater than 1
+	NodeStabilitySensitiveAnnotation = "scheduling.volcano.sh/node-stability-sensitive"
+
 	// topologyDecisionAnnotation is the key of topology decision about pod request resource
 	topologyDecisionAnnotation = "volcano.sh/topology-decision"
 )
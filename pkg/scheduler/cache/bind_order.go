@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// hasBindOrder checks whether the task is bound in order with the other tasks of its job.
+func hasBindOrder(task *schedulingapi.TaskInfo) bool {
+	if task.Pod == nil {
+		return false
+	}
+	_, orderFound := task.Pod.Annotations[schedulingapi.BindOrderAnnotation]
+	_, rankFound := task.Pod.Annotations[schedulingapi.BindRankOrderAnnotation]
+	return orderFound || rankFound
+}
+
+// podIndex returns the index of the pod in its task role by the suffix of its name, -1 if it has none.
+func podIndex(task *schedulingapi.TaskInfo) int {
+	parts := strings.Split(task.Name, "-")
+	if len(parts) < 3 {
+		return -1
+	}
+	index, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// sortByBindOrder sorts the tasks of a job by the bind order of their task roles, then by their index within the
+// task role, ascending unless the task role binds towards rank 0.
+func sortByBindOrder(tasks []*schedulingapi.TaskInfo) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		l, r := tasks[i], tasks[j]
+		lOrder, _ := schedulingapi.GetBindOrder(l.Pod.Annotations)
+		rOrder, _ := schedulingapi.GetBindOrder(r.Pod.Annotations)
+		if lOrder != rOrder {
+			return lOrder < rOrder
+		}
+		if lRole, rRole := l.GetTaskSpecKey(), r.GetTaskSpecKey(); lRole != rRole {
+			return lRole < rRole
+		}
+		lIndex, rIndex := podIndex(l), podIndex(r)
+		if lIndex == rIndex {
+			return false
+		}
+		if schedulingapi.IsBindRankDescending(l.Pod.Annotations) {
+			return lIndex > rIndex
+		}
+		return lIndex < rIndex
+	})
+}
+
+// orderedBindTasks holds the tasks of jobs bound in order until no more task of the job is dispatched in a round of
+// processing bind tasks, so that the tasks of a job dispatched together by a session are bound in order in one batch.
+type orderedBindTasks struct {
+	jobs map[schedulingapi.JobID][]*schedulingapi.TaskInfo
+}
+
+func newOrderedBindTasks() *orderedBindTasks {
+	return &orderedBindTasks{jobs: map[schedulingapi.JobID][]*schedulingapi.TaskInfo{}}
+}
+
+// add holds the task until its job is flushed.
+func (obt *orderedBindTasks) add(task *schedulingapi.TaskInfo) {
+	obt.jobs[task.Job] = append(obt.jobs[task.Job], task)
+}
+
+// flush returns the tasks of each job without task received in the round in bind order, and releases them.
+func (obt *orderedBindTasks) flush(received map[schedulingapi.JobID]bool) [][]*schedulingapi.TaskInfo {
+	var batches [][]*schedulingapi.TaskInfo
+	for job, tasks := range obt.jobs {
+		if received[job] {
+			continue
+		}
+		sortByBindOrder(tasks)
+		batches = append(batches, tasks)
+		delete(obt.jobs, job)
+	}
+	return batches
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// orderRecordingBinder records the names of the tasks in the order they are bound.
+type orderRecordingBinder struct {
+	sync.Mutex
+	bound []string
+}
+
+func (orb *orderRecordingBinder) Bind(kubeClient kubernetes.Interface, tasks []*api.TaskInfo) ([]*api.TaskInfo, error) {
+	orb.Lock()
+	defer orb.Unlock()
+	for _, task := range tasks {
+		orb.bound = append(orb.bound, task.Name)
+	}
+	return nil, nil
+}
+
+func (orb *orderRecordingBinder) boundTasks() []string {
+	orb.Lock()
+	defer orb.Unlock()
+	return append([]string(nil), orb.bound...)
+}
+
+func buildOrderedTask(job, role string, index int, annotations map[string]string) *api.TaskInfo {
+	pod := buildPod("ns", fmt.Sprintf("%s-%s-%d", job, role, index), "", v1.PodPending,
+		buildResourceList("1", "1G"), nil, nil)
+	pod.Annotations = map[string]string{batch.TaskSpecKey: role}
+	for k, v := range annotations {
+		pod.Annotations[k] = v
+	}
+	task := api.NewTaskInfo(pod)
+	task.Job = api.JobID("ns/" + job)
+	task.NodeName = "n1"
+	return task
+}
+
+func TestSortByBindOrder(t *testing.T) {
+	master := map[string]string{api.BindOrderAnnotation: "1"}
+	descending := map[string]string{api.BindRankOrderAnnotation: api.BindRankOrderDescending}
+	tests := []struct {
+		name     string
+		tasks    []*api.TaskInfo
+		expected []string
+	}{
+		{
+			name: "master bound after workers",
+			tasks: []*api.TaskInfo{
+				buildOrderedTask("j1", "master", 0, master),
+				buildOrderedTask("j1", "worker", 1, nil),
+				buildOrderedTask("j1", "worker", 0, nil),
+			},
+			expected: []string{"j1-worker-0", "j1-worker-1", "j1-master-0"},
+		},
+		{
+			name: "rank 0 bound last",
+			tasks: []*api.TaskInfo{
+				buildOrderedTask("j1", "worker", 0, descending),
+				buildOrderedTask("j1", "worker", 2, descending),
+				buildOrderedTask("j1", "worker", 1, descending),
+			},
+			expected: []string{"j1-worker-2", "j1-worker-1", "j1-worker-0"},
+		},
+		{
+			name: "task roles of the same order are grouped",
+			tasks: []*api.TaskInfo{
+				buildOrderedTask("j1", "ps", 1, nil),
+				buildOrderedTask("j1", "worker", 0, nil),
+				buildOrderedTask("j1", "ps", 0, nil),
+				buildOrderedTask("j1", "chief", 0, map[string]string{api.BindOrderAnnotation: "-1"}),
+			},
+			expected: []string{"j1-chief-0", "j1-ps-0", "j1-ps-1", "j1-worker-0"},
+		},
+	}
+
+	for _, test := range tests {
+		sortByBindOrder(test.tasks)
+		var names []string
+		for _, task := range test.tasks {
+			names = append(names, task.Name)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s: expected tasks bound in order %v, got %v", test.name, test.expected, names)
+		}
+	}
+}
+
+func TestProcessBindTaskInOrder(t *testing.T) {
+	binder := &orderRecordingBinder{}
+	sc := &SchedulerCache{
+		Binder:          binder,
+		VolumeBinder:    &util.FakeVolumeBinder{},
+		Recorder:        record.NewFakeRecorder(100),
+		BindFlowChannel: make(chan *api.TaskInfo, 10),
+		batchNum:        1,
+		bindQuarantine:  newNodeQuarantine(),
+	}
+	master := map[string]string{api.BindOrderAnnotation: "1"}
+	worker := map[string]string{api.BindOrderAnnotation: "0"}
+
+	sc.BindFlowChannel <- buildOrderedTask("j1", "master", 0, master)
+	sc.BindFlowChannel <- buildOrderedTask("j1", "worker", 0, worker)
+	sc.processBindTask()
+	// The job is held while its tasks are being dispatched.
+	sc.BindFlowChannel <- buildOrderedTask("j1", "worker", 1, worker)
+	sc.processBindTask()
+	if bound := binder.boundTasks(); len(bound) != 0 {
+		t.Fatalf("expected tasks held until the job is dispatched, got %v bound", bound)
+	}
+	sc.processBindTask()
+
+	expected := []string{"j1-worker-0", "j1-worker-1", "j1-master-0"}
+	err := wait.Poll(10*time.Millisecond, time.Second, func() (bool, error) {
+		return reflect.DeepEqual(binder.boundTasks(), expected), nil
+	})
+	if err != nil {
+		t.Errorf("expected tasks bound in order %v, got %v", expected, binder.boundTasks())
+	}
+}
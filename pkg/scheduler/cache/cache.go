@@ -18,6 +18,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -26,10 +27,12 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -62,16 +65,11 @@ import (
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 	volumescheduling "volcano.sh/volcano/pkg/scheduler/capabilities/volumebinding"
+	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
-	"volcano.sh/volcano/pkg/scheduler/metrics/source"
 	commonutil "volcano.sh/volcano/pkg/util"
 )
 
-const (
-	// default interval for sync data from metrics server, the value is 5s
-	defaultMetricsInternal = 5000000000
-)
-
 func init() {
 	schemeBuilder := runtime.SchemeBuilder{
 		v1.AddToScheme,
@@ -97,6 +95,10 @@ type SchedulerCache struct {
 	schedulerNames     []string
 	nodeSelectorLabels map[string]string
 	metricsConf        map[string]string
+	evictionConf       conf.EvictionConfiguration
+	// maxCompletedTasks is the number of succeeded and of failed tasks kept in each job, the others are
+	// only counted; 0 keeps all of them.
+	maxCompletedTasks int
 
 	podInformer                infov1.PodInformer
 	nodeInformer               infov1.NodeInformer
@@ -140,6 +142,30 @@ type SchedulerCache struct {
 	BindFlowChannel chan *schedulingapi.TaskInfo
 	bindCache       []*schedulingapi.TaskInfo
 	batchNum        int
+	// orderedBindTasks holds the tasks of jobs bound in order by the scheduling.volcano.sh/bind-order and
+	// scheduling.volcano.sh/bind-rank-order annotations, it is only accessed by processBindTask
+	orderedBindTasks *orderedBindTasks
+
+	// preBinders are the pre-binders registered by plugins, called after the volume and
+	// device pre-binders before tasks are bound
+	preBinders     []PreBinder
+	preBinderMutex sync.Mutex
+
+	// bindQuarantine keeps the nodes with repeated bind failures out of the snapshot
+	bindQuarantine *nodeQuarantine
+
+	// incrementalSnapshot reuses the clones of nodes of the previous snapshot not changed since, nodeSnapshots
+	// holds these clones by node name
+	incrementalSnapshot bool
+	nodeSnapshots       map[string]*nodeSnapshot
+
+	// podGroupStatusWriter writes the status of podgroups in the background, nil if not enabled.
+	podGroupStatusWriter *podGroupStatusWriter
+
+	// hungVictims are the victims reported terminating past the pipeline timeout, until they are deleted
+	hungVictims map[schedulingapi.TaskID]struct{}
+	// pluginStates are the states plugins keep across sessions by the name of plugins
+	pluginStates map[string]interface{}
 
 	// A map from image name to its imageState.
 	imageStates map[string]*imageState
@@ -159,6 +185,7 @@ type DefaultBinder struct {
 // Bind will send bind request to api server
 func (db *DefaultBinder) Bind(kubeClient kubernetes.Interface, tasks []*schedulingapi.TaskInfo) ([]*schedulingapi.TaskInfo, error) {
 	var errTasks []*schedulingapi.TaskInfo
+	bindErrors := BindErrors{}
 	for _, task := range tasks {
 		p := task.Pod
 		if err := kubeClient.CoreV1().Pods(p.Namespace).Bind(context.TODO(),
@@ -172,11 +199,12 @@ func (db *DefaultBinder) Bind(kubeClient kubernetes.Interface, tasks []*scheduli
 			metav1.CreateOptions{}); err != nil {
 			klog.Errorf("Failed to bind pod <%v/%v> to node %s : %#v", p.Namespace, p.Name, task.NodeName, err)
 			errTasks = append(errTasks, task)
+			bindErrors[task.UID] = err
 		}
 	}
 
 	if len(errTasks) > 0 {
-		return errTasks, fmt.Errorf("failed to bind pods")
+		return errTasks, bindErrors
 	}
 
 	return nil, nil
@@ -191,15 +219,11 @@ type defaultEvictor struct {
 	recorder   record.EventRecorder
 }
 
-// Evict will send delete pod request to api server
-func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
+// Evict will send delete pod request, or eviction request if opts.UseEvictionAPI, to api server
+func (de *defaultEvictor) Evict(p *v1.Pod, reason string, opts schedulingapi.EvictOptions) error {
 	klog.V(3).Infof("Evicting pod %v/%v, because of %v", p.Namespace, p.Name, reason)
 
 	evictMsg := fmt.Sprintf("Pod is evicted, because of %v", reason)
-	annotations := map[string]string{}
-	// record that we are evicting the pod
-	de.recorder.AnnotatedEventf(p, annotations, v1.EventTypeWarning, "Evict", evictMsg)
-
 	pod := p.DeepCopy()
 	condition := &v1.PodCondition{
 		Type:    v1.PodReady,
@@ -212,15 +236,32 @@ func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
 		klog.V(1).Infof("%+v", pod.Status.Conditions)
 		return nil
 	}
-	if _, err := de.kubeclient.CoreV1().Pods(p.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{}); err != nil {
-		klog.Errorf("Failed to update pod <%v/%v> status: %v", pod.Namespace, pod.Name, err)
-		return err
-	}
-	if err := de.kubeclient.CoreV1().Pods(p.Namespace).Delete(context.TODO(), p.Name, metav1.DeleteOptions{}); err != nil {
+
+	deleteOptions := metav1.DeleteOptions{GracePeriodSeconds: opts.GracePeriodSeconds}
+	if opts.UseEvictionAPI {
+		eviction := &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace},
+			DeleteOptions: &deleteOptions,
+		}
+		// The eviction is rejected with TooManyRequests if it violates a PodDisruptionBudget,
+		// the task is resynchronized and may be evicted again in later sessions.
+		if err := de.kubeclient.PolicyV1().Evictions(p.Namespace).Evict(context.TODO(), eviction); err != nil {
+			klog.Errorf("Failed to evict pod <%v/%v> by eviction API: %#v", p.Namespace, p.Name, err)
+			return err
+		}
+	} else if err := de.kubeclient.CoreV1().Pods(p.Namespace).Delete(context.TODO(), p.Name, deleteOptions); err != nil {
 		klog.Errorf("Failed to evict pod <%v/%v>: %#v", p.Namespace, p.Name, err)
 		return err
 	}
 
+	// The pod is only reported evicted once the eviction is accepted, so a pod protected by a PodDisruptionBudget
+	// is not reported not ready while it keeps running.
+	annotations := map[string]string{}
+	de.recorder.AnnotatedEventf(p, annotations, v1.EventTypeWarning, "Evict", evictMsg)
+	if _, err := de.kubeclient.CoreV1().Pods(p.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to update pod <%v/%v> status: %v", pod.Namespace, pod.Name, err)
+	}
+
 	return nil
 }
 
@@ -228,6 +269,8 @@ func (de *defaultEvictor) Evict(p *v1.Pod, reason string) error {
 type defaultStatusUpdater struct {
 	kubeclient *kubernetes.Clientset
 	vcclient   *vcclient.Clientset
+	// podGroups writes the status of podgroups in the background, nil if podgroups are updated one by one.
+	podGroups *podGroupStatusWriter
 }
 
 // following the same logic as podutil.UpdatePodCondition
@@ -271,6 +314,11 @@ func (su *defaultStatusUpdater) UpdatePodGroup(pg *schedulingapi.PodGroup) (*sch
 		klog.Errorf("Error while converting PodGroup to v1alpha1.PodGroup with error: %v", err)
 		return nil, err
 	}
+	if su.podGroups != nil {
+		// The status is applied in the background, the podgroup in cache is updated by the informer then.
+		su.podGroups.enqueue(podgroup)
+		return pg, nil
+	}
 
 	updated, err := su.vcclient.SchedulingV1beta1().PodGroups(podgroup.Namespace).Update(context.TODO(), podgroup, metav1.UpdateOptions{})
 	if err != nil {
@@ -441,9 +489,14 @@ func newSchedulerCache(config *rest.Config, schedulerNames []string, defaultQueu
 		NamespaceCollection: make(map[string]*schedulingapi.NamespaceCollection),
 		CSINodesStatus:      make(map[string]*schedulingapi.CSINodeStatusInfo),
 		imageStates:         make(map[string]*imageState),
+		bindQuarantine:      newNodeQuarantine(),
 
 		NodeList: []string{},
 	}
+	if options.ServerOpts != nil {
+		sc.maxCompletedTasks = options.ServerOpts.MaxCompletedTasksPerJob
+		sc.incrementalSnapshot = options.ServerOpts.EnableIncrementalSnapshot
+	}
 	if len(nodeSelectors) > 0 {
 		for _, nodeSelectorLabel := range nodeSelectors {
 			nodeSelectorLabelLen := len(nodeSelectorLabel)
@@ -482,10 +535,16 @@ func newSchedulerCache(config *rest.Config, schedulerNames []string, defaultQueu
 		recorder:   sc.Recorder,
 	}
 
-	sc.StatusUpdater = &defaultStatusUpdater{
+	statusUpdater := &defaultStatusUpdater{
 		kubeclient: sc.kubeClient,
 		vcclient:   sc.vcClient,
 	}
+	if options.ServerOpts != nil && options.ServerOpts.PodGroupStatusQPS > 0 {
+		sc.podGroupStatusWriter = newPodGroupStatusWriter(sc.vcClient, options.ServerOpts.PodGroupStatusQPS,
+			options.ServerOpts.PodGroupStatusBurst)
+		statusUpdater.podGroups = sc.podGroupStatusWriter
+	}
+	sc.StatusUpdater = statusUpdater
 
 	sc.PodGroupBinder = &podgroupBinder{
 		kubeclient: sc.kubeClient,
@@ -699,13 +758,10 @@ func (sc *SchedulerCache) Run(stopCh <-chan struct{}) {
 	go wait.Until(sc.processBindTask, time.Millisecond*20, stopCh)
 
 	// Get metrics data
-	address := sc.metricsConf["address"]
-	if len(address) > 0 {
-		interval, err := time.ParseDuration(sc.metricsConf["interval"])
-		if err != nil || interval <= 0 {
-			interval = time.Duration(defaultMetricsInternal)
-		}
-		go wait.Until(sc.GetMetricsData, interval, stopCh)
+	go sc.runMetricsRefresh(stopCh)
+
+	if sc.podGroupStatusWriter != nil {
+		go sc.podGroupStatusWriter.run(stopCh)
 	}
 }
 
@@ -745,6 +801,11 @@ func (sc *SchedulerCache) Evict(taskInfo *schedulingapi.TaskInfo, reason string)
 		return err
 	}
 
+	if schedulingapi.IsNoEvict(task.Pod, sc.evictionConf.NoEvictLabel) {
+		return fmt.Errorf("failed to evict Task %v, it has no-evict label %s",
+			task.UID, sc.evictionConf.NoEvictLabel)
+	}
+
 	node, found := sc.Nodes[task.NodeName]
 	if !found {
 		return fmt.Errorf("failed to bind Task %v to host %v, host does not exist",
@@ -770,9 +831,10 @@ func (sc *SchedulerCache) Evict(taskInfo *schedulingapi.TaskInfo, reason string)
 	}
 
 	p := task.Pod
+	opts := sc.evictOptions(job)
 
 	go func() {
-		err := sc.Evictor.Evict(p, reason)
+		err := sc.Evictor.Evict(p, reason, opts)
 		if err != nil {
 			sc.resyncTask(task)
 		}
@@ -796,17 +858,45 @@ func (sc *SchedulerCache) Bind(tasks []*schedulingapi.TaskInfo) error {
 		for _, task := range tasks {
 			sc.Recorder.Eventf(task.Pod, v1.EventTypeNormal, "Scheduled", "Successfully assigned %v/%v to %v",
 				task.Namespace, task.Name, task.NodeName)
+			sc.bindQuarantine.bindSucceeded(task.NodeName)
 		}
 	} else {
+		var bindErrors BindErrors
+		errors.As(err, &bindErrors)
+		failed := make(map[schedulingapi.TaskID]bool, len(errTasks))
+		chain := sc.preBinderChain()
 		for _, task := range errTasks {
 			klog.V(2).Infof("resyncTask task %s", task.Name)
-			sc.VolumeBinder.RevertVolumes(task, task.PodVolumes)
+			failed[task.UID] = true
+			sc.preBindRollBack(chain, task)
 			sc.resyncTask(task)
+			if nodeAttributable(bindErrors[task.UID]) {
+				sc.bindFailed(task.NodeName)
+			}
+		}
+		for _, task := range tasks {
+			if !failed[task.UID] {
+				sc.bindQuarantine.bindSucceeded(task.NodeName)
+			}
 		}
 	}
 	return nil
 }
 
+// bindFailed records a bind failure on the node, and emits an event on the node if it is quarantined.
+func (sc *SchedulerCache) bindFailed(nodeName string) {
+	duration, quarantined := sc.bindQuarantine.bindFailed(nodeName, time.Now())
+	if !quarantined {
+		return
+	}
+
+	klog.Warningf("Node <%s> is quarantined for %v after repeated bind failures", nodeName, duration)
+	// Events of nodes are recorded with their name as UID, the same as kubelet.
+	ref := &v1.ObjectReference{Kind: "Node", Name: nodeName, UID: types.UID(nodeName)}
+	sc.Recorder.Eventf(ref, v1.EventTypeWarning, "BindFailureQuarantine",
+		"Node is not used for scheduling for %v after repeated bind failures", duration)
+}
+
 // BindPodGroup binds job to silo cluster
 func (sc *SchedulerCache) BindPodGroup(job *schedulingapi.JobInfo, cluster string) error {
 	if _, err := sc.PodGroupBinder.Bind(job, cluster); err != nil {
@@ -976,11 +1066,20 @@ func (sc *SchedulerCache) AddBindTask(taskInfo *schedulingapi.TaskInfo) error {
 			task.UID, taskInfo.NodeName)
 	}
 
+	originalShape := task.ResourceShape
+	if taskInfo.ResourceShape != originalShape {
+		if err := job.SetTaskResourceShape(task, taskInfo.ResourceShape); err != nil {
+			return err
+		}
+	}
+
 	originalStatus := task.Status
 	if err := job.UpdateTaskStatus(task, schedulingapi.Binding); err != nil {
+		sc.revertTaskResourceShape(job, task, originalShape)
 		return err
 	}
 
+	taskInfo.SetPodResourceShape()
 	err = taskInfo.SetPodResourceDecision()
 	if err != nil {
 		return fmt.Errorf("set task %v/%v resource decision failed, err %v", task.Namespace, task.Name, err)
@@ -997,6 +1096,7 @@ func (sc *SchedulerCache) AddBindTask(taskInfo *schedulingapi.TaskInfo) error {
 				task.Namespace, task.Name, task.Status, originalStatus, node.Name, err)
 			sc.resyncTask(task)
 		}
+		sc.revertTaskResourceShape(job, task, originalShape)
 		return err
 	}
 
@@ -1005,7 +1105,23 @@ func (sc *SchedulerCache) AddBindTask(taskInfo *schedulingapi.TaskInfo) error {
 	return nil
 }
 
+// revertTaskResourceShape reverts the resource shape of the task after failing to add it for binding.
+func (sc *SchedulerCache) revertTaskResourceShape(job *schedulingapi.JobInfo, task *schedulingapi.TaskInfo, shape int) {
+	if task.ResourceShape == shape {
+		return
+	}
+	if err := job.SetTaskResourceShape(task, shape); err != nil {
+		klog.Errorf("Task <%s/%s> will be resynchronized after failing to revert resource shape to %d: %v",
+			task.Namespace, task.Name, shape, err)
+		sc.resyncTask(task)
+	}
+}
+
 func (sc *SchedulerCache) processBindTask() {
+	if sc.orderedBindTasks == nil {
+		sc.orderedBindTasks = newOrderedBindTasks()
+	}
+	received := map[schedulingapi.JobID]bool{}
 	for {
 		select {
 		case taskInfo, ok := <-sc.BindFlowChannel:
@@ -1013,9 +1129,14 @@ func (sc *SchedulerCache) processBindTask() {
 				return
 			}
 
-			sc.bindCache = append(sc.bindCache, taskInfo)
-			if len(sc.bindCache) == sc.batchNum {
-				sc.BindTask()
+			if hasBindOrder(taskInfo) {
+				sc.orderedBindTasks.add(taskInfo)
+				received[taskInfo.Job] = true
+			} else {
+				sc.bindCache = append(sc.bindCache, taskInfo)
+				if len(sc.bindCache) == sc.batchNum {
+					sc.BindTask()
+				}
 			}
 		default:
 		}
@@ -1025,6 +1146,12 @@ func (sc *SchedulerCache) processBindTask() {
 		}
 	}
 
+	// The tasks of a job bound in order are bound in one batch, as the tasks of a batch are bound one by one.
+	for _, tasks := range sc.orderedBindTasks.flush(received) {
+		klog.V(5).Infof("bind %d tasks of job %s in order", len(tasks), tasks[0].Job)
+		sc.bindTasks(tasks)
+	}
+
 	if len(sc.bindCache) == 0 {
 		return
 	}
@@ -1035,16 +1162,22 @@ func (sc *SchedulerCache) BindTask() {
 	klog.V(5).Infof("batch bind task count %d", len(sc.bindCache))
 	var tmpBindCache []*schedulingapi.TaskInfo = make([]*schedulingapi.TaskInfo, len(sc.bindCache))
 	copy(tmpBindCache, sc.bindCache)
+	sc.bindTasks(tmpBindCache)
+	sc.bindCache = sc.bindCache[0:0]
+}
+
+// bindTasks pre-binds and binds the tasks in order in a new goroutine.
+func (sc *SchedulerCache) bindTasks(tasks []*schedulingapi.TaskInfo) {
 	go func(tasks []*schedulingapi.TaskInfo) {
 		successfulTasks := make([]*schedulingapi.TaskInfo, 0)
+		chain := sc.preBinderChain()
 		for _, task := range tasks {
-			if err := sc.VolumeBinder.BindVolumes(task, task.PodVolumes); err != nil {
-				klog.Errorf("task %s/%s bind Volumes failed: %#v", task.Namespace, task.Name, err)
-				sc.VolumeBinder.RevertVolumes(task, task.PodVolumes)
+			if err := sc.preBind(chain, task); err != nil {
+				klog.Errorf("task %s/%s pre-bind failed: %v", task.Namespace, task.Name, err)
 				sc.resyncTask(task)
 			} else {
 				successfulTasks = append(successfulTasks, task)
-				klog.V(5).Infof("task %s/%s bind Volumes done", task.Namespace, task.Name)
+				klog.V(5).Infof("task %s/%s pre-bind done", task.Namespace, task.Name)
 			}
 		}
 
@@ -1058,23 +1191,30 @@ func (sc *SchedulerCache) BindTask() {
 		for _, task := range successfulTasks {
 			metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
 		}
-	}(tmpBindCache)
-	sc.bindCache = sc.bindCache[0:0]
+	}(tasks)
 }
 
 // Snapshot returns the complete snapshot of the cluster from cache
 func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
+	return sc.snapshot(sc.incrementalSnapshot)
+}
+
+// snapshot returns the snapshot of the cache, reusing the clones of nodes of the previous snapshot with
+// incremental snapshot. The nodes of such a snapshot are only valid until the next one. Jobs and queues are
+// cloned for every snapshot either way.
+func (sc *SchedulerCache) snapshot(incremental bool) *schedulingapi.ClusterInfo {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
 
 	snapshot := &schedulingapi.ClusterInfo{
-		Nodes:          make(map[string]*schedulingapi.NodeInfo),
-		Jobs:           make(map[schedulingapi.JobID]*schedulingapi.JobInfo),
-		Queues:         make(map[schedulingapi.QueueID]*schedulingapi.QueueInfo),
-		NamespaceInfo:  make(map[schedulingapi.NamespaceName]*schedulingapi.NamespaceInfo),
-		RevocableNodes: make(map[string]*schedulingapi.NodeInfo),
-		NodeList:       make([]string, len(sc.NodeList)),
-		CSINodesStatus: make(map[string]*schedulingapi.CSINodeStatusInfo),
+		Nodes:            make(map[string]*schedulingapi.NodeInfo),
+		Jobs:             make(map[schedulingapi.JobID]*schedulingapi.JobInfo),
+		Queues:           make(map[schedulingapi.QueueID]*schedulingapi.QueueInfo),
+		NamespaceInfo:    make(map[schedulingapi.NamespaceName]*schedulingapi.NamespaceInfo),
+		RevocableNodes:   make(map[string]*schedulingapi.NodeInfo),
+		QuarantinedNodes: make(map[string]*schedulingapi.NodeInfo),
+		NodeList:         make([]string, len(sc.NodeList)),
+		CSINodesStatus:   make(map[string]*schedulingapi.CSINodeStatusInfo),
 	}
 
 	copy(snapshot.NodeList, sc.NodeList)
@@ -1086,21 +1226,38 @@ func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
 		snapshot.CSINodesStatus[value.CSINodeName] = value.Clone()
 	}
 
+	now := time.Now()
+	_, ttl, _ := metricsSettings(sc.metricsConf)
+	var reusedNodes int
 	for _, value := range sc.Nodes {
 		if !value.Ready() {
 			continue
 		}
+		if sc.bindQuarantine.quarantined(value.Name, now) {
+			klog.V(3).Infof("Node <%s> is quarantined for bind failures, skip it.", value.Name)
+			snapshot.QuarantinedNodes[value.Name] = value.Clone()
+			continue
+		}
 
-		snapshot.Nodes[value.Name] = value.Clone()
+		info, reused := sc.snapshotNode(value, incremental, ttl, now)
+		if reused {
+			reusedNodes++
+		}
+		snapshot.Nodes[value.Name] = info
 
 		if value.RevocableZone != "" {
 			snapshot.RevocableNodes[value.Name] = snapshot.Nodes[value.Name]
 		}
 	}
+	if incremental {
+		sc.pruneNodeSnapshots()
+		klog.V(3).Infof("Reused <%d> of <%d> Nodes of the previous snapshot.", reusedNodes, len(snapshot.Nodes))
+	}
 
 	for _, value := range sc.Queues {
 		snapshot.Queues[value.UID] = value.Clone()
 	}
+	schedulingapi.ResolveQueueHierarchy(snapshot.Queues)
 
 	var cloneJobLock sync.Mutex
 	var wg sync.WaitGroup
@@ -1108,11 +1265,15 @@ func (sc *SchedulerCache) Snapshot() *schedulingapi.ClusterInfo {
 	cloneJob := func(value *schedulingapi.JobInfo) {
 		defer wg.Done()
 		if value.PodGroup != nil {
-			value.Priority = sc.defaultPriority
+			priority := sc.defaultPriority
 
 			priName := value.PodGroup.Spec.PriorityClassName
 			if priorityClass, found := sc.PriorityClasses[priName]; found {
-				value.Priority = priorityClass.Value
+				priority = priorityClass.Value
+			}
+			if value.Priority != priority {
+				value.Priority = priority
+				value.Touch()
 			}
 
 			klog.V(4).Infof("The priority of job <%s/%s> is <%s/%d>",
@@ -1211,7 +1372,7 @@ func (sc *SchedulerCache) RecordJobStatusEvent(job *schedulingapi.JobInfo) {
 	if pgUnschedulable {
 		msg := fmt.Sprintf("%v/%v tasks in gang unschedulable: %v",
 			len(job.TaskStatusIndex[schedulingapi.Pending]),
-			len(job.Tasks),
+			int32(len(job.Tasks))+job.CompactedTaskNum(),
 			job.FitError())
 		sc.recordPodGroupEvent(job.PodGroup, v1.EventTypeWarning, string(scheduling.PodGroupUnschedulableType), msg)
 	} else {
@@ -1245,13 +1406,51 @@ func (sc *SchedulerCache) UpdateJobStatus(job *schedulingapi.JobInfo, updatePG b
 			return nil, err
 		}
 		job.PodGroup = pg
+
+		// The status is written in the background, set it to the podgroup in cache at once, so the next
+		// sessions do not see e.g. the job just enqueued pending and enqueue it again.
+		if sc.podGroupStatusWriter != nil {
+			sc.setPodGroupStatus(job.UID, pg)
+		}
 	}
+	sc.setJobReadyVetoedBy(job)
 
 	sc.RecordJobStatusEvent(job)
 
 	return job, nil
 }
 
+// setJobReadyVetoedBy keeps the plugin which vetoed the readiness of job in session for the next sessions.
+func (sc *SchedulerCache) setJobReadyVetoedBy(job *schedulingapi.JobInfo) {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	if cached, found := sc.Jobs[job.UID]; found && cached.ReadyVetoedBy != job.ReadyVetoedBy {
+		cached.ReadyVetoedBy = job.ReadyVetoedBy
+		cached.Touch()
+	}
+}
+
+// setPodGroupStatus sets the status of pg to the podgroup of job in cache.
+func (sc *SchedulerCache) setPodGroupStatus(jobID schedulingapi.JobID, pg *schedulingapi.PodGroup) {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	cached, found := sc.Jobs[jobID]
+	if !found || cached.PodGroup == nil {
+		return
+	}
+	podGroup := cached.PodGroup.Clone()
+	podGroup.Status = *pg.Status.DeepCopy()
+	for key, value := range schedulerAnnotations(pg.Annotations) {
+		if podGroup.Annotations == nil {
+			podGroup.Annotations = map[string]string{}
+		}
+		podGroup.Annotations[key] = value
+	}
+	cached.SetPodGroup(podGroup)
+}
+
 // UpdateQueueStatus update the status of queue.
 func (sc *SchedulerCache) UpdateQueueStatus(queue *schedulingapi.QueueInfo) error {
 	var newQueue = &vcv1beta1.Queue{}
@@ -1282,54 +1481,84 @@ func (sc *SchedulerCache) recordPodGroupEvent(podGroup *schedulingapi.PodGroup,
 }
 
 func (sc *SchedulerCache) SetMetricsConf(conf map[string]string) {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
 	sc.metricsConf = conf
 }
 
-func (sc *SchedulerCache) GetMetricsData() {
-	client, err := source.NewMetricsClient(sc.metricsConf)
-	if err != nil {
-		klog.Errorf("Error creating client: %v\n", err)
-		return
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
-	defer cancel()
-	nodeUsageMap := make(map[string]*schedulingapi.NodeUsage)
+// SetEvictionConf set the configuration of evicting victims
+func (sc *SchedulerCache) SetEvictionConf(evictionConf conf.EvictionConfiguration) {
 	sc.Mutex.Lock()
-	for k := range sc.Nodes {
-		nodeUsageMap[k] = &schedulingapi.NodeUsage{
-			CPUUsageAvg: make(map[string]float64),
-			MEMUsageAvg: make(map[string]float64),
-		}
-	}
-	sc.Mutex.Unlock()
+	defer sc.Mutex.Unlock()
+	sc.evictionConf = evictionConf
+}
 
-	supportedPeriods := []string{"5m"}
-	for node := range nodeUsageMap {
-		for _, period := range supportedPeriods {
-			nodeMetrics, err := client.NodeMetricsAvg(ctx, node, period)
-			if err != nil {
-				klog.Errorf("Error getting node metrics: %v\n", err)
-				continue
-			}
-			klog.V(4).Infof("node: %v, CpuUsageAvg: %v, MemUsageAvg: %v, period:%v", node, nodeMetrics.CPU, nodeMetrics.Memory, period)
-			nodeUsageMap[node].CPUUsageAvg[period] = nodeMetrics.CPU
-			nodeUsageMap[node].MEMUsageAvg[period] = nodeMetrics.Memory
-		}
+// NoEvictLabel returns the key of label which makes pods invisible to preempt and reclaim
+func (sc *SchedulerCache) NoEvictLabel() string {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+	return sc.evictionConf.NoEvictLabel
+}
+
+// PreemptionDomainLabel returns the key of node label dividing nodes into preemption domains
+func (sc *SchedulerCache) PreemptionDomainLabel() string {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+	return sc.evictionConf.DomainLabel
+}
+
+// PipelineTimeout returns how long victims may keep terminating after their grace period before their
+// resources are no longer regarded as releasing, 0 if they are releasing until they exit
+func (sc *SchedulerCache) PipelineTimeout() time.Duration {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+	// The timeout is validated when the configuration is loaded.
+	timeout, _ := time.ParseDuration(sc.evictionConf.PipelineTimeout)
+	return timeout
+}
+
+// MarkHungVictim marks the victim terminating past the pipeline timeout, it returns false if the victim
+// is marked already, so that it is reported once.
+func (sc *SchedulerCache) MarkHungVictim(task *schedulingapi.TaskInfo) bool {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+	if _, found := sc.hungVictims[task.UID]; found {
+		return false
+	}
+	if sc.hungVictims == nil {
+		sc.hungVictims = map[schedulingapi.TaskID]struct{}{}
 	}
-	sc.setMetricsData(nodeUsageMap)
+	sc.hungVictims[task.UID] = struct{}{}
+	return true
 }
 
-func (sc *SchedulerCache) setMetricsData(usageInfo map[string]*schedulingapi.NodeUsage) {
+// PluginState returns the state the plugin of name keeps across the sessions of the scheduler, it is
+// created by newState at first.
+func (sc *SchedulerCache) PluginState(name string, newState func() interface{}) interface{} {
 	sc.Mutex.Lock()
 	defer sc.Mutex.Unlock()
+	if state, found := sc.pluginStates[name]; found {
+		return state
+	}
+	if sc.pluginStates == nil {
+		sc.pluginStates = map[string]interface{}{}
+	}
+	state := newState()
+	sc.pluginStates[name] = state
+	return state
+}
 
-	for k := range usageInfo {
-		nodeInfo, ok := sc.Nodes[k]
-		if ok {
-			klog.V(3).Infof("node: %s, ResourceUsage: %+v => %+v", k, *nodeInfo.ResourceUsage, *usageInfo[k])
-			nodeInfo.ResourceUsage = usageInfo[k]
-		}
+// evictOptions returns the options to evict the tasks of job, the grace period of the queue of job
+// overrides the one of scheduler configuration.
+func (sc *SchedulerCache) evictOptions(job *schedulingapi.JobInfo) schedulingapi.EvictOptions {
+	opts := schedulingapi.EvictOptions{
+		UseEvictionAPI:     sc.evictionConf.Mode == conf.EvictionModeEviction,
+		GracePeriodSeconds: sc.evictionConf.GracePeriodSeconds,
+	}
+	if queue, found := sc.Queues[job.Queue]; found && queue.VictimGracePeriodSeconds != nil {
+		opts.GracePeriodSeconds = queue.VictimGracePeriodSeconds
 	}
+	return opts
 }
 
 // createImageStateSummary returns a summarizing snapshot of the given image's state.
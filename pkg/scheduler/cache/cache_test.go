@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
@@ -314,3 +315,66 @@ func TestNodeOperation(t *testing.T) {
 		}
 	}
 }
+
+func TestSchedulerCache_EvictOptions(t *testing.T) {
+	configured, overridden := int64(30), int64(0)
+	cache := &SchedulerCache{
+		Queues: map[api.QueueID]*api.QueueInfo{
+			"default": {UID: "default"},
+			"urgent":  {UID: "urgent", VictimGracePeriodSeconds: &overridden},
+		},
+		evictionConf: conf.EvictionConfiguration{Mode: conf.EvictionModeEviction, GracePeriodSeconds: &configured},
+	}
+
+	opts := cache.evictOptions(&api.JobInfo{Queue: "default"})
+	if !opts.UseEvictionAPI || opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 30 {
+		t.Errorf("expected configured options for queue default, got %+v", opts)
+	}
+	opts = cache.evictOptions(&api.JobInfo{Queue: "urgent"})
+	if !opts.UseEvictionAPI || opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 0 {
+		t.Errorf("expected grace period of queue urgent, got %+v", opts)
+	}
+}
+
+func TestSchedulerCache_Evict_NoEvictLabel(t *testing.T) {
+	owner := buildOwnerReference("j1")
+	evictor := &util.FakeEvictor{Channel: make(chan string, 1)}
+	cache := &SchedulerCache{
+		Jobs:         make(map[api.JobID]*api.JobInfo),
+		Nodes:        make(map[string]*api.NodeInfo),
+		Evictor:      evictor,
+		evictionConf: conf.EvictionConfiguration{NoEvictLabel: "volcano.sh/no-evict"},
+	}
+	cache.AddNode(buildNode("n1", buildResourceList("2000m", "10G")))
+
+	pod := buildPod("c1", "p1", "n1", v1.PodRunning, buildResourceList("1000m", "1G"),
+		[]metav1.OwnerReference{owner}, map[string]string{"volcano.sh/no-evict": "true"})
+	task := api.NewTaskInfo(pod)
+	task.Job = "j1"
+	if err := cache.addTask(task); err != nil {
+		t.Fatalf("failed to add task %v", err)
+	}
+
+	if err := cache.Evict(task, "preempt"); err == nil {
+		t.Errorf("expected error evicting task with no-evict label")
+	}
+	if task.Status != api.Running || len(evictor.Evicts()) != 0 {
+		t.Errorf("expected task with no-evict label kept running, got %v, evicts %v", task.Status, evictor.Evicts())
+	}
+}
+
+func TestMetricsPeriods(t *testing.T) {
+	tests := []struct {
+		conf     map[string]string
+		expected []string
+	}{
+		{conf: nil, expected: []string{"5m"}},
+		{conf: map[string]string{"periods": " , "}, expected: []string{"5m"}},
+		{conf: map[string]string{"periods": "5m, 1h,1d"}, expected: []string{"5m", "1h", "1d"}},
+	}
+	for _, test := range tests {
+		if periods := metricsPeriods(test.conf); !reflect.DeepEqual(periods, test.expected) {
+			t.Errorf("periods of %v: expected %v, got %v", test.conf, test.expected, periods)
+		}
+	}
+}
@@ -35,7 +35,13 @@ type Dumper struct {
 }
 
 func (d *Dumper) dumpAll() {
-	snapshot := d.Cache.Snapshot()
+	var snapshot *api.ClusterInfo
+	if sc, ok := d.Cache.(*SchedulerCache); ok {
+		// The nodes reused by incremental snapshot may be in use by the running session.
+		snapshot = sc.snapshot(false)
+	} else {
+		snapshot = d.Cache.Snapshot()
+	}
 	klog.Info("Dump of nodes info in scheduler cache")
 	for _, nodeInfo := range snapshot.Nodes {
 		klog.Info(d.printNodeInfo(nodeInfo))
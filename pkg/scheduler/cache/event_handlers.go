@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
@@ -67,6 +68,13 @@ func (sc *SchedulerCache) getOrCreateJob(pi *schedulingapi.TaskInfo) *scheduling
 }
 
 func (sc *SchedulerCache) addTask(pi *schedulingapi.TaskInfo) error {
+	job := sc.getOrCreateJob(pi)
+	// The task on node requests the whole node if its job does, so that the node is seen held
+	// even if the job is not scheduled in the session.
+	if job != nil && job.ExclusiveNode {
+		pi.ExclusiveNode = true
+	}
+
 	if len(pi.NodeName) != 0 {
 		if _, found := sc.Nodes[pi.NodeName]; !found {
 			sc.Nodes[pi.NodeName] = schedulingapi.NewNodeInfo(nil)
@@ -83,9 +91,11 @@ func (sc *SchedulerCache) addTask(pi *schedulingapi.TaskInfo) error {
 		}
 	}
 
-	job := sc.getOrCreateJob(pi)
 	if job != nil {
 		job.AddTaskInfo(pi)
+		if sc.maxCompletedTasks > 0 && isTerminated(pi.Status) {
+			job.CompactCompletedTasks(sc.maxCompletedTasks)
+		}
 	}
 
 	return nil
@@ -200,6 +210,7 @@ func (sc *SchedulerCache) deletePod(pod *v1.Pod) error {
 	if err := sc.deleteTask(task); err != nil {
 		klog.Warningf("Failed to delete task: %v", err)
 	}
+	delete(sc.hungVictims, pi.UID)
 
 	// If job was terminated, delete it.
 	if job, found := sc.Jobs[pi.Job]; found && schedulingapi.JobTerminated(job) {
@@ -350,6 +361,7 @@ func (sc *SchedulerCache) addNode(node *v1.Node) error {
 // Assumes that lock is already acquired.
 func (sc *SchedulerCache) updateNode(oldNode, newNode *v1.Node) error {
 	if sc.Nodes[newNode.Name] != nil {
+		sc.Nodes[newNode.Name].RecordStability(oldNode, newNode, time.Now())
 		sc.Nodes[newNode.Name].SetNode(newNode)
 		sc.removeNodeImageStates(newNode)
 		sc.addNodeImageStates(newNode, sc.Nodes[newNode.Name])
@@ -374,6 +386,7 @@ func (sc *SchedulerCache) deleteNode(node *v1.Node) error {
 		}
 	}
 	sc.removeNodeImageStates(node)
+	sc.bindQuarantine.forget(node.Name)
 	delete(sc.Nodes, node.Name)
 
 	return nil
@@ -525,6 +538,7 @@ func (sc *SchedulerCache) setPodGroup(ss *schedulingapi.PodGroup) error {
 	}
 
 	sc.Jobs[job].SetPodGroup(ss)
+	sc.setExclusiveNode(sc.Jobs[job])
 
 	// TODO(k82cn): set default queue in admission.
 	if len(ss.Spec.Queue) == 0 {
@@ -536,6 +550,22 @@ func (sc *SchedulerCache) setPodGroup(ss *schedulingapi.PodGroup) error {
 	return nil
 }
 
+// setExclusiveNode sets whether the tasks of job and their copies on nodes request whole nodes,
+// after the exclusive-node annotation of the podgroup of job changes.
+// Assumes that lock is already acquired.
+func (sc *SchedulerCache) setExclusiveNode(job *schedulingapi.JobInfo) {
+	for _, task := range job.Tasks {
+		exclusive := job.ExclusiveNode || task.Pod != nil && schedulingapi.IsExclusiveNode(task.Pod.Annotations)
+		if task.ExclusiveNode == exclusive {
+			continue
+		}
+		task.ExclusiveNode = exclusive
+		if node, found := sc.Nodes[task.NodeName]; found {
+			node.SetExclusiveNode(task, exclusive)
+		}
+	}
+}
+
 // Assumes that lock is already acquired.
 func (sc *SchedulerCache) updatePodGroup(newPodGroup *schedulingapi.PodGroup) error {
 	return sc.setPodGroup(newPodGroup)
@@ -599,6 +629,21 @@ func (sc *SchedulerCache) UpdatePodGroupV1beta1(oldObj, newObj interface{}) {
 		return
 	}
 
+	// The status waiting to be written is newer than the one of the event, e.g. of an annotation updated by
+	// the controllers, keep it so the job is not seen e.g. pending again.
+	if sc.podGroupStatusWriter != nil {
+		if status, annotations, found := sc.podGroupStatusWriter.pendingStatus(newSS.Namespace, newSS.Name); found {
+			newSS = newSS.DeepCopy()
+			newSS.Status = status
+			for key, value := range annotations {
+				if newSS.Annotations == nil {
+					newSS.Annotations = map[string]string{}
+				}
+				newSS.Annotations[key] = value
+			}
+		}
+	}
+
 	podgroup := scheduling.PodGroup{}
 	if err := scheme.Scheme.Convert(newSS, &podgroup, nil); err != nil {
 		klog.Errorf("Failed to convert podgroup from %T to %T", newSS, podgroup)
@@ -955,6 +1000,7 @@ func (sc *SchedulerCache) addNumaInfo(info *nodeinfov1alpha1.Numatopology) error
 
 		sc.Nodes[info.Name].NumaInfo = newLocalInfo
 	}
+	sc.Nodes[info.Name].Touch()
 
 	for resName, NumaResInfo := range sc.Nodes[info.Name].NumaInfo.NumaResMap {
 		klog.V(3).Infof("resource %s Allocatable %v on node[%s] into cache", resName, NumaResInfo, info.Name)
@@ -970,6 +1016,7 @@ func (sc *SchedulerCache) deleteNumaInfo(info *nodeinfov1alpha1.Numatopology) {
 	if sc.Nodes[info.Name] != nil {
 		sc.Nodes[info.Name].NumaInfo = nil
 		sc.Nodes[info.Name].NumaChgFlag = schedulingapi.NumaInfoResetFlag
+		sc.Nodes[info.Name].Touch()
 		klog.V(3).Infof("delete numainfo in cahce for node<%s>", info.Name)
 	}
 }
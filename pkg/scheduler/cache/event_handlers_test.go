@@ -229,6 +229,56 @@ func TestSchedulerCache_AddPodGroupV1beta1(t *testing.T) {
 	}
 }
 
+func TestSchedulerCache_ExclusiveNodeOfPodGroup(t *testing.T) {
+	namespace := "test"
+	owner := buildOwnerReference("j1")
+	cache := &SchedulerCache{
+		Jobs:  make(map[api.JobID]*api.JobInfo),
+		Nodes: make(map[string]*api.NodeInfo),
+	}
+	cache.AddNode(buildNode("n1", buildResourceList("2000m", "10G")))
+	cache.AddNode(buildNode("n2", buildResourceList("2000m", "10G")))
+
+	newPod := func(name, nodeName string) *v1.Pod {
+		pod := buildPod(namespace, name, nodeName, v1.PodRunning, buildResourceList("1000m", "1G"), []metav1.OwnerReference{owner}, make(map[string]string))
+		pod.Annotations = map[string]string{"scheduling.k8s.io/group-name": "j1"}
+		return pod
+	}
+	newPodGroup := func(resourceVersion, exclusive string) *schedulingv1.PodGroup {
+		return &schedulingv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "j1",
+				Namespace:       namespace,
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{api.ExclusiveNodeAnnotation: exclusive},
+			},
+		}
+	}
+	exclusiveOnNode := func(nodeName, podName string) bool {
+		task, found := cache.Nodes[nodeName].Tasks[api.TaskID(fmt.Sprintf("%s/%s", namespace, podName))]
+		return found && task.ExclusiveNode
+	}
+
+	cache.AddPod(newPod("p1", "n1"))
+	cache.AddPodGroupV1beta1(newPodGroup("1", "true"))
+	if !exclusiveOnNode("n1", "p1") {
+		t.Errorf("expected task added before exclusive podgroup to be exclusive on node")
+	}
+	cache.AddPod(newPod("p2", "n2"))
+	if !exclusiveOnNode("n2", "p2") {
+		t.Errorf("expected task added after exclusive podgroup to be exclusive on node")
+	}
+
+	generation := cache.Nodes["n1"].Generation()
+	cache.UpdatePodGroupV1beta1(newPodGroup("1", "true"), newPodGroup("2", "false"))
+	if exclusiveOnNode("n1", "p1") || exclusiveOnNode("n2", "p2") {
+		t.Errorf("expected tasks not exclusive on nodes once podgroup is not exclusive")
+	}
+	if cache.Nodes["n1"].Generation() == generation {
+		t.Errorf("expected node generation to change with exclusivity of its task")
+	}
+}
+
 func TestSchedulerCache_UpdatePodGroupV1beta1(t *testing.T) {
 	namespace := "test"
 	owner := buildOwnerReference("j1")
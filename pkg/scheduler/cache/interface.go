@@ -17,6 +17,8 @@ limitations under the License.
 package cache
 
 import (
+	"time"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -25,6 +27,7 @@ import (
 
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/capabilities/volumebinding"
+	"volcano.sh/volcano/pkg/scheduler/conf"
 )
 
 // Cache collects pods/nodes/queues information
@@ -71,6 +74,9 @@ type Cache interface {
 	// RevertVolumes clean cache generated by AllocateVolumes
 	RevertVolumes(task *api.TaskInfo, podVolumes *volumebinding.PodVolumes)
 
+	// RegisterPreBinder adds the pre-binder to the hooks called before tasks are bound
+	RegisterPreBinder(preBinder PreBinder)
+
 	// Client returns the kubernetes clientSet, which can be used by plugins
 	Client() kubernetes.Interface
 
@@ -85,10 +91,37 @@ type Cache interface {
 	// SetMetricsConf set the metrics server related configuration
 	SetMetricsConf(conf map[string]string)
 
+	// SetEvictionConf set the configuration of evicting victims
+	SetEvictionConf(evictionConf conf.EvictionConfiguration)
+
+	// NoEvictLabel returns the key of label which makes pods invisible to preempt and reclaim
+	NoEvictLabel() string
+
+	// PreemptionDomainLabel returns the key of node label dividing nodes into preemption domains
+	PreemptionDomainLabel() string
+
+	// PipelineTimeout returns how long victims may keep terminating after their grace period before their
+	// resources are no longer regarded as releasing, 0 if they are releasing until they exit
+	PipelineTimeout() time.Duration
+
+	// MarkHungVictim marks the victim terminating past the pipeline timeout, it returns false if the victim
+	// is marked already, so that it is reported once
+	MarkHungVictim(task *api.TaskInfo) bool
+
+	// PluginState returns the state the plugin of name keeps across the sessions of the scheduler, it is
+	// created by newState at first
+	PluginState(name string, newState func() interface{}) interface{}
+
 	// EventRecorder returns the event recorder
 	EventRecorder() record.EventRecorder
 }
 
+// PluginStateCloner is a state of plugin copied by the sessions which must not change it, e.g. dry runs
+type PluginStateCloner interface {
+	// CloneState returns a deep copy of the state
+	CloneState() interface{}
+}
+
 // VolumeBinder interface for allocate and bind volumes
 type VolumeBinder interface {
 	GetPodVolumes(task *api.TaskInfo, node *v1.Node) (*volumebinding.PodVolumes, error)
@@ -97,6 +130,17 @@ type VolumeBinder interface {
 	BindVolumes(task *api.TaskInfo, podVolumes *volumebinding.PodVolumes) error
 }
 
+// PreBinder prepares the resources of a task before it is bound to the host, e.g. binding its volumes,
+// writing its device annotations or creating its network attachments
+type PreBinder interface {
+	// Name returns the name of the pre-binder, registering a pre-binder of the same name replaces it
+	Name() string
+	// PreBind prepares the resources of the task, the task is not bound if it returns an error
+	PreBind(task *api.TaskInfo) error
+	// PreBindRollBack reverts PreBind when a later pre-binder or the binding of the task fails
+	PreBindRollBack(task *api.TaskInfo)
+}
+
 // Binder interface for binding task and hostname
 type Binder interface {
 	Bind(kubeClient kubernetes.Interface, tasks []*api.TaskInfo) ([]*api.TaskInfo, error)
@@ -104,7 +148,7 @@ type Binder interface {
 
 // Evictor interface for evict pods
 type Evictor interface {
-	Evict(pod *v1.Pod, reason string) error
+	Evict(pod *v1.Pod, reason string, opts api.EvictOptions) error
 }
 
 // StatusUpdater updates pod with given PodCondition
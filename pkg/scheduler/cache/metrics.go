@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics/source"
+)
+
+const (
+	// defaultMetricsInterval is the default interval the usages of nodes are refreshed at
+	defaultMetricsInterval = 5 * time.Second
+	// defaultMetricsWorkers is the default number of nodes whose usages are queried concurrently
+	defaultMetricsWorkers = 16
+	// defaultMetricsTTLIntervals is the default staleness TTL of usages in intervals
+	defaultMetricsTTLIntervals = 3
+	// metricsQueryTimeout is the timeout of a refresh of the usages of all nodes
+	metricsQueryTimeout = 60 * time.Second
+)
+
+// metricsSettings returns the refresh interval, the staleness TTL and the number of workers of metrics
+// configuration: `interval`, 5s by default, `ttl`, 3 intervals by default, and `workers`, 16 by default.
+func metricsSettings(metricsConf map[string]string) (time.Duration, time.Duration, int) {
+	interval, err := time.ParseDuration(metricsConf["interval"])
+	if err != nil || interval <= 0 {
+		interval = defaultMetricsInterval
+	}
+	ttl, err := time.ParseDuration(metricsConf["ttl"])
+	if err != nil || ttl <= 0 {
+		ttl = defaultMetricsTTLIntervals * interval
+	}
+	workers, err := strconv.Atoi(metricsConf["workers"])
+	if err != nil || workers <= 0 {
+		workers = defaultMetricsWorkers
+	}
+	return interval, ttl, workers
+}
+
+// metricsStale returns whether the usage was collected longer than ttl ago and is not marked stale yet,
+// usages never collected are not stale.
+func metricsStale(usage *schedulingapi.NodeUsage, ttl time.Duration, now time.Time) bool {
+	return usage != nil && !usage.Stale && !usage.SampleTime.IsZero() && now.Sub(usage.SampleTime) > ttl
+}
+
+// runMetricsRefresh refreshes the usages of nodes in background until stopCh is closed, so that sessions only
+// read the latest usages in the snapshot. The metrics configuration is read in every round, so that the
+// reloaded configuration, including the interval, takes effect without restarting the scheduler.
+func (sc *SchedulerCache) runMetricsRefresh(stopCh <-chan struct{}) {
+	for {
+		sc.Mutex.Lock()
+		metricsConf := sc.metricsConf
+		sc.Mutex.Unlock()
+
+		if source.Enabled(metricsConf) {
+			sc.GetMetricsData()
+		}
+
+		interval, _, _ := metricsSettings(metricsConf)
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// GetMetricsData refreshes the usages of all nodes from the metrics source, the nodes are queried by a pool of
+// workers, or at once if the source supports it. The usage of a node failing to be queried is kept until it is
+// stale. The usages of pods are queried once if the source supports them.
+func (sc *SchedulerCache) GetMetricsData() {
+	sc.Mutex.Lock()
+	metricsConf := sc.metricsConf
+	nodes := make([]string, 0, len(sc.Nodes))
+	capacities := make(map[string]*schedulingapi.Resource, len(sc.Nodes))
+	for name, nodeInfo := range sc.Nodes {
+		nodes = append(nodes, name)
+		if nodeInfo.Capacity != nil {
+			capacities[name] = nodeInfo.Capacity.Clone()
+		}
+	}
+	sc.Mutex.Unlock()
+
+	provider, err := source.NewProvider(metricsConf)
+	if err != nil {
+		klog.Errorf("Error creating metrics provider: %v\n", err)
+		return
+	}
+	if kubeProvider, ok := provider.(source.KubeProvider); ok && sc.kubeClient != nil {
+		kubeProvider.SetKubeClient(sc.kubeClient)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), metricsQueryTimeout)
+	defer cancel()
+
+	_, _, workers := metricsSettings(metricsConf)
+	periods := metricsPeriods(metricsConf)
+	usages := make([]*schedulingapi.NodeUsage, len(nodes))
+	if usagesProvider, ok := provider.(source.NodeUsagesProvider); ok {
+		nodeUsages, err := usagesProvider.QueryNodeUsages(ctx, metricsConf, capacities, periods)
+		if err != nil {
+			klog.Errorf("Error getting node metrics from %s: %v\n", provider.Name(), err)
+		}
+		now := time.Now()
+		for i, node := range nodes {
+			if usage := nodeUsages[node]; usage != nil && err == nil && usage.SampleTime.IsZero() {
+				usage.SampleTime = now
+			}
+			usages[i] = nodeUsages[node]
+		}
+	} else {
+		workqueue.ParallelizeUntil(ctx, workers, len(nodes), func(i int) {
+			usage, err := provider.QueryNodeUsage(ctx, metricsConf, nodes[i], periods)
+			if err != nil {
+				klog.Errorf("Error getting node metrics of %s from %s: %v\n", nodes[i], provider.Name(), err)
+			} else if usage != nil && usage.SampleTime.IsZero() {
+				// The usage of a provider not setting SampleTime is sampled now.
+				usage.SampleTime = time.Now()
+			}
+			usages[i] = usage
+		})
+	}
+
+	var podUsages map[string]*schedulingapi.PodUsage
+	if podProvider, ok := provider.(source.PodUsageProvider); ok {
+		if podUsages, err = podProvider.QueryPodUsages(ctx, metricsConf); err != nil {
+			klog.Errorf("Error getting pod metrics from %s: %v\n", provider.Name(), err)
+		}
+	}
+
+	nodeUsageMap := make(map[string]*schedulingapi.NodeUsage, len(nodes))
+	for i, node := range nodes {
+		if usages[i] != nil {
+			nodeUsageMap[node] = usages[i]
+		}
+	}
+	sc.setMetricsData(nodeUsageMap, podUsages)
+}
+
+// metricsPeriods returns the periods of the usages collected from the metrics source by the comma separated
+// `periods` of metrics configuration, e.g. `5m,1h`, 5m by default.
+func metricsPeriods(metricsConf map[string]string) []string {
+	var periods []string
+	for _, period := range strings.Split(metricsConf["periods"], ",") {
+		if period = strings.TrimSpace(period); period != "" {
+			periods = append(periods, period)
+		}
+	}
+	if len(periods) == 0 {
+		return []string{"5m"}
+	}
+	return periods
+}
+
+// setMetricsData sets the usages of nodes collected with the usages of the pods on them, the usages of the other
+// nodes are cleared and marked stale once they are stale, or at once if they were never collected.
+func (sc *SchedulerCache) setMetricsData(usageInfo map[string]*schedulingapi.NodeUsage, podUsages map[string]*schedulingapi.PodUsage) {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	_, ttl, _ := metricsSettings(sc.metricsConf)
+	now := time.Now()
+	for name, nodeInfo := range sc.Nodes {
+		usage, found := usageInfo[name]
+		if !found || usage.SampleTime.IsZero() {
+			if nodeInfo.ResourceUsage == nil || (nodeInfo.ResourceUsage.SampleTime.IsZero() && !nodeInfo.ResourceUsage.Stale) {
+				klog.V(3).Infof("node: %s, ResourceUsage was never collected", name)
+				nodeInfo.ResourceUsage = schedulingapi.StaleNodeUsage(time.Time{})
+				nodeInfo.Touch()
+			} else if metricsStale(nodeInfo.ResourceUsage, ttl, now) {
+				klog.V(3).Infof("node: %s, ResourceUsage collected at %v is stale", name, nodeInfo.ResourceUsage.SampleTime)
+				nodeInfo.ResourceUsage = schedulingapi.StaleNodeUsage(nodeInfo.ResourceUsage.SampleTime)
+				nodeInfo.Touch()
+			}
+			continue
+		}
+		if len(podUsages) > 0 && usage.PodUsages == nil {
+			usage.PodUsages = map[string]*schedulingapi.PodUsage{}
+			for _, task := range nodeInfo.Tasks {
+				key := task.Namespace + "/" + task.Name
+				if podUsage, found := podUsages[key]; found {
+					usage.PodUsages[key] = podUsage
+				}
+			}
+		}
+		klog.V(3).Infof("node: %s, ResourceUsage: %+v => %+v", name, *nodeInfo.ResourceUsage, *usage)
+		nodeInfo.ResourceUsage = usage
+		nodeInfo.Touch()
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics/source"
+)
+
+// fakeProvider reports the cpu usage of nodes, or fails for the nodes without usage, and the usages of pods.
+type fakeProvider struct {
+	sync.Mutex
+	usages    map[string]float64
+	podUsages map[string]*api.PodUsage
+	queried   int
+}
+
+func (fp *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (fp *fakeProvider) Validate(metricsConf map[string]string) error {
+	return nil
+}
+
+func (fp *fakeProvider) QueryNodeUsage(ctx context.Context, metricsConf map[string]string, nodeName string, periods []string) (*api.NodeUsage, error) {
+	fp.Lock()
+	defer fp.Unlock()
+	fp.queried++
+	usage, found := fp.usages[nodeName]
+	if !found {
+		return &api.NodeUsage{}, fmt.Errorf("no usage of node %s", nodeName)
+	}
+	return &api.NodeUsage{CPUUsage: usage}, nil
+}
+
+func (fp *fakeProvider) QueryPodUsages(ctx context.Context, metricsConf map[string]string) (map[string]*api.PodUsage, error) {
+	return fp.podUsages, nil
+}
+
+func TestMetricsSettings(t *testing.T) {
+	tests := []struct {
+		conf     map[string]string
+		interval time.Duration
+		ttl      time.Duration
+		workers  int
+	}{
+		{conf: nil, interval: 5 * time.Second, ttl: 15 * time.Second, workers: 16},
+		{conf: map[string]string{"interval": "10s"}, interval: 10 * time.Second, ttl: 30 * time.Second, workers: 16},
+		{conf: map[string]string{"interval": "10s", "ttl": "1m", "workers": "4"}, interval: 10 * time.Second, ttl: time.Minute, workers: 4},
+		{conf: map[string]string{"interval": "-1s", "ttl": "never", "workers": "0"}, interval: 5 * time.Second, ttl: 15 * time.Second, workers: 16},
+	}
+	for _, test := range tests {
+		interval, ttl, workers := metricsSettings(test.conf)
+		if interval != test.interval || ttl != test.ttl || workers != test.workers {
+			t.Errorf("settings of %v: expected %v, %v and %d, got %v, %v and %d",
+				test.conf, test.interval, test.ttl, test.workers, interval, ttl, workers)
+		}
+	}
+}
+
+func TestGetMetricsData(t *testing.T) {
+	provider := &fakeProvider{usages: map[string]float64{}}
+	source.RegisterProvider(provider)
+
+	cache := &SchedulerCache{
+		Nodes:       map[string]*api.NodeInfo{},
+		metricsConf: map[string]string{"type": "fake", "workers": "3", "ttl": "1m"},
+	}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("n%d", i)
+		cache.Nodes[name] = api.NewNodeInfo(buildNode(name, buildResourceList("4", "4G")))
+		provider.usages[name] = float64(i)
+	}
+
+	cache.GetMetricsData()
+	if provider.queried != 10 {
+		t.Errorf("expected 10 nodes queried, got %d", provider.queried)
+	}
+	for name, node := range cache.Nodes {
+		if node.ResourceUsage.CPUUsage != provider.usages[name] || node.ResourceUsage.SampleTime.IsZero() {
+			t.Errorf("expected cpu usage of %s %v sampled, got %+v", name, provider.usages[name], node.ResourceUsage)
+		}
+	}
+
+	// The usages of failed nodes are kept until they are stale.
+	delete(provider.usages, "n1")
+	delete(provider.usages, "n2")
+	cache.Nodes["n2"].ResourceUsage.SampleTime = time.Now().Add(-2 * time.Minute)
+	cache.GetMetricsData()
+	if usage := cache.Nodes["n1"].ResourceUsage; usage.CPUUsage != 1 {
+		t.Errorf("expected usage of n1 kept, got %+v", usage)
+	}
+	if usage := cache.Nodes["n2"].ResourceUsage; usage.CPUUsage != 0 || !usage.Stale || usage.SampleTime.IsZero() {
+		t.Errorf("expected stale usage of n2 cleared and marked stale, got %+v", usage)
+	}
+
+	// The nodes never collected are stale at once.
+	cache.Nodes["n3"].ResourceUsage = &api.NodeUsage{}
+	delete(provider.usages, "n3")
+	cache.GetMetricsData()
+	if usage := cache.Nodes["n3"].ResourceUsage; !usage.Stale {
+		t.Errorf("expected usage of n3 never collected marked stale, got %+v", usage)
+	}
+
+	// The usages of pods are set to the usages of the nodes they run on.
+	pod := buildPod("ns", "p1", "n0", v1.PodRunning, buildResourceList("1", "1G"), nil, nil)
+	if err := cache.Nodes["n0"].AddTask(api.NewTaskInfo(pod)); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	provider.podUsages = map[string]*api.PodUsage{"ns/p1": {MilliCPU: 500, Memory: 1e8}, "ns/p2": {MilliCPU: 100}}
+	cache.GetMetricsData()
+	if podUsages := cache.Nodes["n0"].ResourceUsage.PodUsages; len(podUsages) != 1 || podUsages["ns/p1"].MilliCPU != 500 {
+		t.Errorf("expected usage of pod ns/p1 on n0, got %v", podUsages)
+	}
+	if podUsages := cache.Nodes["n4"].ResourceUsage.PodUsages; len(podUsages) != 0 {
+		t.Errorf("expected no pod usages on n4, got %v", podUsages)
+	}
+}
+
+func TestSnapshotStaleMetrics(t *testing.T) {
+	cache := &SchedulerCache{
+		Nodes:               map[string]*api.NodeInfo{},
+		Jobs:                map[api.JobID]*api.JobInfo{},
+		Queues:              map[api.QueueID]*api.QueueInfo{},
+		NamespaceCollection: map[string]*api.NamespaceCollection{},
+		metricsConf:         map[string]string{"ttl": "1m"},
+	}
+	fresh := api.NewNodeInfo(buildNode("fresh", buildResourceList("4", "4G")))
+	fresh.ResourceUsage = &api.NodeUsage{CPUUsage: 10, SampleTime: time.Now()}
+	stale := api.NewNodeInfo(buildNode("stale", buildResourceList("4", "4G")))
+	stale.ResourceUsage = &api.NodeUsage{CPUUsage: 20, SampleTime: time.Now().Add(-time.Hour)}
+	cache.Nodes["fresh"], cache.Nodes["stale"] = fresh, stale
+
+	snapshot := cache.Snapshot()
+	if usage := snapshot.Nodes["fresh"].ResourceUsage; usage.CPUUsage != 10 {
+		t.Errorf("expected usage of fresh node in snapshot, got %+v", usage)
+	}
+	if usage := snapshot.Nodes["stale"].ResourceUsage; usage.CPUUsage != 0 || !usage.Stale {
+		t.Errorf("expected stale usage cleared and marked stale in snapshot, got %+v", usage)
+	}
+	if cache.Nodes["stale"].ResourceUsage.CPUUsage != 20 {
+		t.Errorf("expected usage in cache not changed by snapshot")
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+const (
+	// bindFailureThreshold is the number of consecutive bind failures quarantining a node
+	bindFailureThreshold = 3
+	// minBindQuarantine is the duration of the first quarantine of a node, doubled by each
+	// following quarantine until a task is bound to the node
+	minBindQuarantine = 30 * time.Second
+	// maxBindQuarantine is the longest quarantine of a node
+	maxBindQuarantine = 10 * time.Minute
+)
+
+// BindErrors are the errors of the tasks failed to bind keyed by their UID. A Binder returns them as its error
+// so that only the failures attributable to the nodes count to their quarantine.
+type BindErrors map[schedulingapi.TaskID]error
+
+func (be BindErrors) Error() string {
+	return "failed to bind pods"
+}
+
+// nodeAttributable returns whether the bind failure of a task is attributable to its node, e.g. rejected by
+// admission. The ones of the pod, e.g. deleted or bound already, and of the API server, e.g. throttled, timed out
+// or unreachable, are not, nor are the failures of binders not returning BindErrors.
+func nodeAttributable(err error) bool {
+	var status apierrors.APIStatus
+	if err == nil || !errors.As(err, &status) {
+		return false
+	}
+	switch {
+	case apierrors.IsNotFound(err), apierrors.IsConflict(err), apierrors.IsAlreadyExists(err), apierrors.IsGone(err),
+		apierrors.IsTooManyRequests(err), apierrors.IsServerTimeout(err), apierrors.IsTimeout(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsUnauthorized(err):
+		return false
+	}
+	return true
+}
+
+// bindFailures is the bind failure history of a node since the last task bound to it
+type bindFailures struct {
+	// failures is the number of consecutive bind failures
+	failures int
+	// quarantines is the number of quarantines, i.e. the exponent of the backoff
+	quarantines int
+	// until is the end of the current quarantine
+	until time.Time
+	// active is whether the quarantine is not released yet
+	active bool
+}
+
+// nodeQuarantine keeps nodes with repeated bind failures, e.g. rejected by admission or kubelet, out
+// of the snapshot for an exponential backoff, instead of placing tasks on them every session.
+type nodeQuarantine struct {
+	sync.Mutex
+	nodes map[string]*bindFailures
+}
+
+func newNodeQuarantine() *nodeQuarantine {
+	return &nodeQuarantine{nodes: make(map[string]*bindFailures)}
+}
+
+// bindFailed records a bind failure on the node, and returns the quarantine duration if the node
+// is quarantined by it. A node released from quarantine is quarantined again by its next failure.
+func (nq *nodeQuarantine) bindFailed(nodeName string, now time.Time) (time.Duration, bool) {
+	if nq == nil {
+		return 0, false
+	}
+	nq.Lock()
+	defer nq.Unlock()
+
+	bf, found := nq.nodes[nodeName]
+	if !found {
+		bf = &bindFailures{}
+		nq.nodes[nodeName] = bf
+	}
+	bf.failures++
+	if bf.failures < bindFailureThreshold || now.Before(bf.until) {
+		return 0, false
+	}
+
+	duration := maxBindQuarantine
+	if bf.quarantines < 16 {
+		if d := minBindQuarantine << bf.quarantines; d < maxBindQuarantine {
+			duration = d
+		}
+	}
+	bf.quarantines++
+	bf.until = now.Add(duration)
+	bf.active = true
+	metrics.RegisterNodeBindQuarantine(nodeName)
+	return duration, true
+}
+
+// bindSucceeded forgets the bind failures of the node.
+func (nq *nodeQuarantine) bindSucceeded(nodeName string) {
+	if nq == nil {
+		return
+	}
+	nq.Lock()
+	defer nq.Unlock()
+
+	if bf, found := nq.nodes[nodeName]; found {
+		if bf.active {
+			metrics.ReleaseNodeBindQuarantine(nodeName)
+		}
+		delete(nq.nodes, nodeName)
+	}
+}
+
+// quarantined returns whether the node is quarantined at now, releasing its expired quarantine.
+func (nq *nodeQuarantine) quarantined(nodeName string, now time.Time) bool {
+	if nq == nil {
+		return false
+	}
+	nq.Lock()
+	defer nq.Unlock()
+
+	bf, found := nq.nodes[nodeName]
+	if !found || !bf.active {
+		return false
+	}
+	if now.Before(bf.until) {
+		return true
+	}
+
+	klog.V(3).Infof("Node <%s> is released from bind failure quarantine", nodeName)
+	bf.active = false
+	metrics.ReleaseNodeBindQuarantine(nodeName)
+	return false
+}
+
+// forget drops the history of a deleted node.
+func (nq *nodeQuarantine) forget(nodeName string) {
+	if nq == nil {
+		return
+	}
+	nq.Lock()
+	defer nq.Unlock()
+
+	delete(nq.nodes, nodeName)
+	metrics.DeleteNodeMetrics(nodeName)
+}
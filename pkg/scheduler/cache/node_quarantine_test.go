@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// failingBinder fails to bind every task with err.
+type failingBinder struct {
+	err error
+}
+
+func (fb *failingBinder) Bind(kubeClient kubernetes.Interface, tasks []*api.TaskInfo) ([]*api.TaskInfo, error) {
+	bindErrors := BindErrors{}
+	for _, task := range tasks {
+		bindErrors[task.UID] = fb.err
+	}
+	return tasks, bindErrors
+}
+
+func TestNodeQuarantine(t *testing.T) {
+	nq := newNodeQuarantine()
+	now := time.Now()
+
+	for i := 1; i < bindFailureThreshold; i++ {
+		if _, quarantined := nq.bindFailed("n1", now); quarantined {
+			t.Fatalf("node quarantined after %d failures", i)
+		}
+	}
+	duration, quarantined := nq.bindFailed("n1", now)
+	if !quarantined || duration != minBindQuarantine {
+		t.Fatalf("expected quarantine of %v, got %v, %v", minBindQuarantine, duration, quarantined)
+	}
+	if !nq.quarantined("n1", now.Add(minBindQuarantine/2)) {
+		t.Errorf("expected node quarantined within its quarantine")
+	}
+	if _, quarantined := nq.bindFailed("n1", now.Add(minBindQuarantine/2)); quarantined {
+		t.Errorf("expected failures within the quarantine not to extend it")
+	}
+	if nq.quarantined("n2", now) {
+		t.Errorf("expected node without failures not quarantined")
+	}
+
+	now = now.Add(minBindQuarantine)
+	if nq.quarantined("n1", now) {
+		t.Errorf("expected node released after its quarantine")
+	}
+	duration, quarantined = nq.bindFailed("n1", now)
+	if !quarantined || duration != 2*minBindQuarantine {
+		t.Errorf("expected released node quarantined for %v by its next failure, got %v, %v",
+			2*minBindQuarantine, duration, quarantined)
+	}
+
+	for i := 0; i < 10; i++ {
+		now = now.Add(maxBindQuarantine)
+		duration, _ = nq.bindFailed("n1", now)
+	}
+	if duration != maxBindQuarantine {
+		t.Errorf("expected quarantine capped at %v, got %v", maxBindQuarantine, duration)
+	}
+
+	nq.bindSucceeded("n1")
+	if nq.quarantined("n1", now) {
+		t.Errorf("expected node released by a successful bind")
+	}
+	if _, quarantined := nq.bindFailed("n1", now); quarantined {
+		t.Errorf("expected failures forgotten by a successful bind")
+	}
+}
+
+func TestBindFailuresAttributableToNodes(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name        string
+		err         error
+		quarantined bool
+	}{
+		{name: "rejected by admission", err: apierrors.NewForbidden(pods, "p1", fmt.Errorf("denied")), quarantined: true},
+		{name: "pod deleted", err: apierrors.NewNotFound(pods, "p1")},
+		{name: "pod bound already", err: apierrors.NewConflict(pods, "p1", fmt.Errorf("bound"))},
+		{name: "API server throttled", err: apierrors.NewTooManyRequests("throttled", 1)},
+		{name: "API server timed out", err: apierrors.NewTimeoutError("timeout", 1)},
+		{name: "API server unreachable", err: fmt.Errorf("connection refused")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc := &SchedulerCache{
+				Binder:         &failingBinder{err: test.err},
+				VolumeBinder:   &util.FakeVolumeBinder{},
+				Recorder:       record.NewFakeRecorder(100),
+				errTasks:       workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+				bindQuarantine: newNodeQuarantine(),
+			}
+			defer sc.errTasks.ShutDown()
+			for i := 0; i < bindFailureThreshold; i++ {
+				task := api.NewTaskInfo(buildPod("ns", fmt.Sprintf("p%d", i), "", v1.PodPending, buildResourceList("1", "1G"), nil, nil))
+				task.NodeName = "n1"
+				sc.Bind([]*api.TaskInfo{task})
+			}
+			if quarantined := sc.bindQuarantine.quarantined("n1", time.Now()); quarantined != test.quarantined {
+				t.Errorf("expected node quarantined %v, got %v", test.quarantined, quarantined)
+			}
+		})
+	}
+}
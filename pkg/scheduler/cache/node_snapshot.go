@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// nodeSnapshot is the clone of a node kept across sessions by the incremental snapshot.
+type nodeSnapshot struct {
+	info *schedulingapi.NodeInfo
+	// source is the generation of the node in cache the clone is taken at, lent is the generation of the clone
+	// when it is put into the latest snapshot, the clone is changed by the session if they differ.
+	source uint64
+	lent   uint64
+	// stale is whether the usage of the clone is marked stale by the snapshot
+	stale bool
+}
+
+// snapshotNode returns the node of the snapshot, with the usage cleared and marked stale if it is collected
+// earlier than ttl. With incremental snapshot, it is the clone of the previous snapshot if neither the node in
+// cache nor the clone is changed since, otherwise a new clone of the node, and whether the clone is reused.
+// Assumes that lock is already acquired.
+func (sc *SchedulerCache) snapshotNode(node *schedulingapi.NodeInfo, incremental bool, ttl time.Duration, now time.Time) (*schedulingapi.NodeInfo, bool) {
+	if !incremental {
+		info := node.Clone()
+		if metricsStale(node.ResourceUsage, ttl, now) {
+			info.ResourceUsage = schedulingapi.StaleNodeUsage(node.ResourceUsage.SampleTime)
+		}
+		return info, false
+	}
+
+	if sc.nodeSnapshots == nil {
+		sc.nodeSnapshots = map[string]*nodeSnapshot{}
+	}
+	// The usage turns stale by time without any change of the node, and fresh again if the ttl is extended.
+	stale := metricsStale(node.ResourceUsage, ttl, now)
+	ns, reused := sc.nodeSnapshots[node.Name]
+	if !reused || ns.source != node.Generation() || ns.lent != ns.info.Generation() || (ns.stale && !stale) {
+		ns = &nodeSnapshot{info: node.Clone(), source: node.Generation()}
+		sc.nodeSnapshots[node.Name] = ns
+		reused = false
+	}
+	if stale && !ns.stale {
+		ns.info.ResourceUsage = schedulingapi.StaleNodeUsage(node.ResourceUsage.SampleTime)
+		ns.stale = true
+	}
+	ns.lent = ns.info.Generation()
+	return ns.info, reused
+}
+
+// pruneNodeSnapshots forgets the clones of the nodes removed from cache.
+// Assumes that lock is already acquired.
+func (sc *SchedulerCache) pruneNodeSnapshots() {
+	for name := range sc.nodeSnapshots {
+		if _, found := sc.Nodes[name]; !found {
+			delete(sc.nodeSnapshots, name)
+		}
+	}
+}
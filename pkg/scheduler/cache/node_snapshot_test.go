@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestIncrementalSnapshot(t *testing.T) {
+	cache := &SchedulerCache{
+		Nodes:               map[string]*api.NodeInfo{},
+		Jobs:                map[api.JobID]*api.JobInfo{},
+		Queues:              map[api.QueueID]*api.QueueInfo{},
+		NamespaceCollection: map[string]*api.NamespaceCollection{},
+		metricsConf:         map[string]string{"ttl": "1m"},
+		incrementalSnapshot: true,
+	}
+	cache.Nodes["n1"] = api.NewNodeInfo(buildNode("n1", buildResourceList("4", "4G")))
+	cache.Nodes["n2"] = api.NewNodeInfo(buildNode("n2", buildResourceList("4", "4G")))
+	cache.Nodes["n2"].ResourceUsage = &api.NodeUsage{CPUUsage: 10, SampleTime: time.Now().Add(-30 * time.Second)}
+
+	first := cache.Snapshot()
+	second := cache.Snapshot()
+	if second.Nodes["n1"] != first.Nodes["n1"] || second.Nodes["n2"] != first.Nodes["n2"] {
+		t.Fatalf("expected nodes not changed reused by the next snapshot")
+	}
+
+	// The session places a task on n1 of its snapshot.
+	task := api.NewTaskInfo(buildPod("ns", "p1", "", v1.PodPending, buildResourceList("1", "1G"), nil, nil))
+	if err := second.Nodes["n1"].AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	third := cache.Snapshot()
+	if third.Nodes["n1"] == second.Nodes["n1"] || len(third.Nodes["n1"].Tasks) != 0 {
+		t.Errorf("expected node changed by the session cloned again from cache, got %d tasks", len(third.Nodes["n1"].Tasks))
+	}
+	if third.Nodes["n2"] != second.Nodes["n2"] {
+		t.Errorf("expected node not changed reused by the next snapshot")
+	}
+
+	// The task is bound to n2 in cache.
+	bound := api.NewTaskInfo(buildPod("ns", "p2", "n2", v1.PodRunning, buildResourceList("1", "1G"), nil, nil))
+	if err := cache.Nodes["n2"].AddTask(bound); err != nil {
+		t.Fatal(err)
+	}
+	fourth := cache.Snapshot()
+	if fourth.Nodes["n2"] == third.Nodes["n2"] || len(fourth.Nodes["n2"].Tasks) != 1 {
+		t.Errorf("expected node changed in cache cloned again, got %d tasks", len(fourth.Nodes["n2"].Tasks))
+	}
+
+	// The usage of n2 turns stale by a shorter ttl, and fresh again by the longer one.
+	cache.metricsConf["ttl"] = "10s"
+	fifth := cache.Snapshot()
+	if usage := fifth.Nodes["n2"].ResourceUsage; fifth.Nodes["n2"] != fourth.Nodes["n2"] || !usage.Stale {
+		t.Errorf("expected usage of reused node marked stale, got %+v", usage)
+	}
+	cache.metricsConf["ttl"] = "1m"
+	if usage := cache.Snapshot().Nodes["n2"].ResourceUsage; usage.Stale || usage.CPUUsage != 10 {
+		t.Errorf("expected fresh usage of node cloned again, got %+v", usage)
+	}
+
+	delete(cache.Nodes, "n1")
+	if snapshot := cache.Snapshot(); snapshot.Nodes["n1"] != nil || cache.nodeSnapshots["n1"] != nil {
+		t.Errorf("expected node removed from cache forgotten by snapshot")
+	}
+	if full := cache.snapshot(false); full.Nodes["n2"] == cache.nodeSnapshots["n2"].info {
+		t.Errorf("expected nodes of full snapshot cloned")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	vcv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclient "volcano.sh/apis/pkg/client/clientset/versioned"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// podGroupStatusFieldManager is the field manager of the status of podgroups applied by scheduler.
+	podGroupStatusFieldManager = "volcano-scheduler"
+	// podGroupStatusWorkers is the number of goroutines writing the status of podgroups.
+	podGroupStatusWorkers = 16
+)
+
+// podGroupStatusApply is the server-side apply configuration of the status of a podgroup. The counts are not omitted
+// when they are zero, so that they are reset instead of left to the value applied before.
+type podGroupStatusApply struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Metadata   podGroupApplyMeta    `json:"metadata"`
+	Status     podGroupStatusFields `json:"status"`
+}
+
+type podGroupApplyMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// schedulerAnnotations returns the annotations written by the scheduler along with the status of a podgroup, e.g.
+// the evictions counted by the cdp plugin, nil if none.
+func schedulerAnnotations(annotations map[string]string) map[string]string {
+	var owned map[string]string
+	for _, key := range []string{schedulingapi.PodGroupEvictionsAnnotation} {
+		if value, found := annotations[key]; found {
+			if owned == nil {
+				owned = map[string]string{}
+			}
+			owned[key] = value
+		}
+	}
+	return owned
+}
+
+type podGroupStatusFields struct {
+	Phase      vcv1beta1.PodGroupPhase       `json:"phase"`
+	Conditions []vcv1beta1.PodGroupCondition `json:"conditions"`
+	Running    int32                         `json:"running"`
+	Succeeded  int32                         `json:"succeeded"`
+	Failed     int32                         `json:"failed"`
+}
+
+// podGroupStatusWriter writes the status of podgroups in the background by server-side apply patches, so that the
+// phase transitions of many podgroups at once, e.g. 50k podgroups turning Inqueue, neither block the session nor
+// saturate the apiserver: the writes of a podgroup are coalesced until it is written, so only its latest status is
+// written, and the writes of all podgroups are limited by a token bucket. A failed write is retried with backoff,
+// unless a newer status of the podgroup is waiting.
+type podGroupStatusWriter struct {
+	vcClient vcclient.Interface
+	limiter  flowcontrol.RateLimiter
+	queue    workqueue.RateLimitingInterface
+
+	mutex   sync.Mutex
+	pending map[string]*vcv1beta1.PodGroup
+}
+
+func newPodGroupStatusWriter(vcClient vcclient.Interface, qps float32, burst int) *podGroupStatusWriter {
+	return &podGroupStatusWriter{
+		vcClient: vcClient,
+		limiter:  flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(100*time.Millisecond, 30*time.Second), "podgroup-status"),
+		pending: map[string]*vcv1beta1.PodGroup{},
+	}
+}
+
+// enqueue writes the status of podgroup later, replacing the status of podgroup waiting to be written if any.
+func (pw *podGroupStatusWriter) enqueue(podGroup *vcv1beta1.PodGroup) {
+	key := podGroup.Namespace + "/" + podGroup.Name
+	pw.mutex.Lock()
+	pw.pending[key] = podGroup
+	pw.mutex.Unlock()
+	pw.queue.Add(key)
+}
+
+// pendingStatus returns the status and the scheduler annotations of podgroup waiting to be written, if any.
+func (pw *podGroupStatusWriter) pendingStatus(namespace, name string) (vcv1beta1.PodGroupStatus, map[string]string, bool) {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	podGroup, found := pw.pending[namespace+"/"+name]
+	if !found {
+		return vcv1beta1.PodGroupStatus{}, nil, false
+	}
+	return *podGroup.Status.DeepCopy(), schedulerAnnotations(podGroup.Annotations), true
+}
+
+// run writes the status of podgroups until stopCh is closed.
+func (pw *podGroupStatusWriter) run(stopCh <-chan struct{}) {
+	defer pw.queue.ShutDown()
+	for i := 0; i < podGroupStatusWorkers; i++ {
+		go wait.Until(func() {
+			for pw.processNext() {
+			}
+		}, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+// processNext writes the status of the next podgroup, it returns false if the queue is shut down.
+func (pw *podGroupStatusWriter) processNext() bool {
+	item, shutdown := pw.queue.Get()
+	if shutdown {
+		return false
+	}
+	key := item.(string)
+	defer pw.queue.Done(key)
+
+	// The podgroup stays pending until it is written, so that the cache keeps its status over the one of
+	// the events received in the meantime.
+	pw.mutex.Lock()
+	podGroup, found := pw.pending[key]
+	pw.mutex.Unlock()
+	if !found {
+		return true
+	}
+
+	pw.limiter.Accept()
+	err := pw.write(podGroup)
+	if err == nil || apierrors.IsNotFound(err) {
+		pw.mutex.Lock()
+		if pw.pending[key] == podGroup {
+			delete(pw.pending, key)
+		}
+		pw.mutex.Unlock()
+		pw.queue.Forget(key)
+		return true
+	}
+
+	klog.Errorf("Failed to apply status of PodGroup <%s>, retry it later: %v", key, err)
+	pw.queue.AddRateLimited(key)
+	return true
+}
+
+// write applies the status of podgroup.
+func (pw *podGroupStatusWriter) write(podGroup *vcv1beta1.PodGroup) error {
+	data, err := json.Marshal(&podGroupStatusApply{
+		APIVersion: vcv1beta1.SchemeGroupVersion.String(),
+		Kind:       "PodGroup",
+		Metadata: podGroupApplyMeta{
+			Name:        podGroup.Name,
+			Namespace:   podGroup.Namespace,
+			Annotations: schedulerAnnotations(podGroup.Annotations),
+		},
+		Status: podGroupStatusFields{
+			Phase:      podGroup.Status.Phase,
+			Conditions: podGroup.Status.Conditions,
+			Running:    podGroup.Status.Running,
+			Succeeded:  podGroup.Status.Succeeded,
+			Failed:     podGroup.Status.Failed,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	force := true
+	_, err = pw.vcClient.SchedulingV1beta1().PodGroups(podGroup.Namespace).Patch(context.TODO(), podGroup.Name,
+		types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: podGroupStatusFieldManager, Force: &force})
+	return err
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+
+	vcv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+)
+
+func TestPodGroupStatusWriter(t *testing.T) {
+	client := volcanoclient.NewSimpleClientset()
+	var applied []podGroupStatusApply
+	patches := map[string][]byte{}
+	failures := 1
+	client.PrependReactor("patch", "podgroups", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			t.Errorf("expected apply patch, got %s", patch.GetPatchType())
+		}
+		if failures > 0 {
+			failures--
+			return true, nil, fmt.Errorf("too many requests")
+		}
+		var apply podGroupStatusApply
+		if err := json.Unmarshal(patch.GetPatch(), &apply); err != nil {
+			t.Errorf("failed to decode patch %s: %v", patch.GetPatch(), err)
+		}
+		applied = append(applied, apply)
+		patches[patch.GetName()] = patch.GetPatch()
+		return true, &vcv1beta1.PodGroup{}, nil
+	})
+
+	writer := newPodGroupStatusWriter(client, 1000, 1000)
+	podGroup := func(name string, phase vcv1beta1.PodGroupPhase, running int32) *vcv1beta1.PodGroup {
+		return &vcv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "c1", Name: name},
+			Status:     vcv1beta1.PodGroupStatus{Phase: phase, Running: running},
+		}
+	}
+
+	// The writes of pg1 are coalesced into the latest one.
+	writer.enqueue(podGroup("pg1", vcv1beta1.PodGroupInqueue, 0))
+	writer.enqueue(podGroup("pg1", vcv1beta1.PodGroupRunning, 2))
+	writer.enqueue(podGroup("pg2", vcv1beta1.PodGroupInqueue, 0))
+	if length := writer.queue.Len(); length != 2 {
+		t.Fatalf("expected 2 podgroups to write, got %d", length)
+	}
+	// The status waiting to be written is kept until written.
+	if status, _, found := writer.pendingStatus("c1", "pg1"); !found || status.Phase != vcv1beta1.PodGroupRunning {
+		t.Errorf("expected pending status of pg1 running, got %+v, %v", status, found)
+	}
+	// The first write fails and is retried.
+	for i := 0; i < 3; i++ {
+		writer.processNext()
+	}
+	if len(applied) != 2 {
+		t.Fatalf("expected status of 2 podgroups applied, got %v", applied)
+	}
+	status := map[string]podGroupStatusFields{}
+	for _, apply := range applied {
+		if apply.Kind != "PodGroup" || apply.APIVersion != vcv1beta1.SchemeGroupVersion.String() || apply.Metadata.Namespace != "c1" {
+			t.Errorf("unexpected apply configuration %+v", apply)
+		}
+		status[apply.Metadata.Name] = apply.Status
+	}
+	if status["pg1"].Phase != vcv1beta1.PodGroupRunning || status["pg1"].Running != 2 {
+		t.Errorf("expected latest status of pg1 applied, got %+v", status["pg1"])
+	}
+	if status["pg2"].Phase != vcv1beta1.PodGroupInqueue {
+		t.Errorf("expected status of pg2 applied, got %+v", status["pg2"])
+	}
+	if len(writer.pending) != 0 {
+		t.Errorf("expected no pending status, got %v", writer.pending)
+	}
+	if !strings.Contains(string(patches["pg2"]), `"running":0`) {
+		t.Errorf("expected zero running count applied to reset it, got %s", patches["pg2"])
+	}
+}
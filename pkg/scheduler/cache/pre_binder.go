@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// volumePreBinder binds the volumes allocated to the task.
+type volumePreBinder struct {
+	volumeBinder VolumeBinder
+}
+
+func (vpb *volumePreBinder) Name() string {
+	return "volume"
+}
+
+func (vpb *volumePreBinder) PreBind(task *schedulingapi.TaskInfo) error {
+	return vpb.volumeBinder.BindVolumes(task, task.PodVolumes)
+}
+
+func (vpb *volumePreBinder) PreBindRollBack(task *schedulingapi.TaskInfo) {
+	vpb.volumeBinder.RevertVolumes(task, task.PodVolumes)
+}
+
+// devicePreBinder writes the annotations of the devices allocated to the task to its pod.
+type devicePreBinder struct {
+	sc *SchedulerCache
+}
+
+func (dpb *devicePreBinder) Name() string {
+	return "device"
+}
+
+func (dpb *devicePreBinder) PreBind(task *schedulingapi.TaskInfo) error {
+	if len(task.DeviceAnnotations) == 0 {
+		return nil
+	}
+
+	for _, devices := range dpb.sc.nodeDevices(task.NodeName) {
+		if !devices.HasDeviceRequest(task.Pod) {
+			continue
+		}
+		if err := devices.LockNode(dpb.sc.Client(), task.Pod); err != nil {
+			return err
+		}
+	}
+
+	annotations := make(map[string]interface{}, len(task.DeviceAnnotations))
+	for k, v := range task.DeviceAnnotations {
+		annotations[k] = v
+	}
+	return patchPodAnnotations(dpb.sc.Client(), task, annotations)
+}
+
+func (dpb *devicePreBinder) PreBindRollBack(task *schedulingapi.TaskInfo) {
+	if len(task.DeviceAnnotations) == 0 {
+		return
+	}
+
+	// A null value removes the annotation in a merge patch
+	annotations := make(map[string]interface{}, len(task.DeviceAnnotations))
+	for k := range task.DeviceAnnotations {
+		annotations[k] = nil
+	}
+	if err := patchPodAnnotations(dpb.sc.Client(), task, annotations); err != nil {
+		klog.Errorf("Failed to remove device annotations of task %s/%s: %v", task.Namespace, task.Name, err)
+	}
+}
+
+func patchPodAnnotations(kubeClient kubernetes.Interface, task *schedulingapi.TaskInfo, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kubeClient.CoreV1().Pods(task.Namespace).Patch(context.TODO(), task.Name,
+		types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("patch pod %s/%s failed: %v", task.Namespace, task.Name, err)
+	}
+	return nil
+}
+
+// nodeDevices returns the shared devices of the node.
+func (sc *SchedulerCache) nodeDevices(nodeName string) []schedulingapi.Devices {
+	sc.Mutex.Lock()
+	defer sc.Mutex.Unlock()
+
+	node, found := sc.Nodes[nodeName]
+	if !found {
+		return nil
+	}
+	var devices []schedulingapi.Devices
+	for _, val := range schedulingapi.RegisteredDevices {
+		if d, ok := node.Others[val].(schedulingapi.Devices); ok {
+			devices = append(devices, d)
+		}
+	}
+	return devices
+}
+
+// RegisterPreBinder adds the pre-binder to the hooks called before tasks are bound, after the volumes and the
+// devices of the tasks are prepared.
+func (sc *SchedulerCache) RegisterPreBinder(preBinder PreBinder) {
+	sc.preBinderMutex.Lock()
+	defer sc.preBinderMutex.Unlock()
+
+	for i, pb := range sc.preBinders {
+		if pb.Name() == preBinder.Name() {
+			sc.preBinders[i] = preBinder
+			return
+		}
+	}
+	sc.preBinders = append(sc.preBinders, preBinder)
+}
+
+// preBinderChain returns the pre-binders in the order they are called.
+func (sc *SchedulerCache) preBinderChain() []PreBinder {
+	sc.preBinderMutex.Lock()
+	defer sc.preBinderMutex.Unlock()
+
+	chain := []PreBinder{&volumePreBinder{volumeBinder: sc.VolumeBinder}, &devicePreBinder{sc: sc}}
+	return append(chain, sc.preBinders...)
+}
+
+// preBind calls the pre-binders of the chain on the task. If one fails, the ones called before it are
+// rolled back in reverse order.
+func (sc *SchedulerCache) preBind(chain []PreBinder, task *schedulingapi.TaskInfo) error {
+	for i, pb := range chain {
+		if err := pb.PreBind(task); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				chain[j].PreBindRollBack(task)
+			}
+			return fmt.Errorf("pre-binder %s failed: %v", pb.Name(), err)
+		}
+	}
+	return nil
+}
+
+// preBindRollBack rolls back the pre-binders of the chain on the task in reverse order.
+func (sc *SchedulerCache) preBindRollBack(chain []PreBinder, task *schedulingapi.TaskInfo) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		chain[i].PreBindRollBack(task)
+	}
+}
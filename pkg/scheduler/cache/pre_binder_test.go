@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type fakePreBinder struct {
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (fpb *fakePreBinder) Name() string {
+	return fpb.name
+}
+
+func (fpb *fakePreBinder) PreBind(task *api.TaskInfo) error {
+	*fpb.calls = append(*fpb.calls, "prebind "+fpb.name)
+	return fpb.err
+}
+
+func (fpb *fakePreBinder) PreBindRollBack(task *api.TaskInfo) {
+	*fpb.calls = append(*fpb.calls, "rollback "+fpb.name)
+}
+
+func TestPreBind(t *testing.T) {
+	task := api.NewTaskInfo(buildPod("c1", "p1", "n1", v1.PodPending, buildResourceList("1", "1G"), nil, nil))
+
+	tests := []struct {
+		name      string
+		failAt    string
+		wantErr   bool
+		wantCalls []string
+	}{
+		{
+			name:      "all pre-binders succeed",
+			wantCalls: []string{"prebind volume", "prebind device", "prebind network"},
+		},
+		{
+			name:      "failed pre-binder rolls back the previous ones in reverse order",
+			failAt:    "network",
+			wantErr:   true,
+			wantCalls: []string{"prebind volume", "prebind device", "prebind network", "rollback device", "rollback volume"},
+		},
+		{
+			name:      "first pre-binder fails",
+			failAt:    "volume",
+			wantErr:   true,
+			wantCalls: []string{"prebind volume"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []string
+			var chain []PreBinder
+			for _, name := range []string{"volume", "device", "network"} {
+				pb := &fakePreBinder{name: name, calls: &calls}
+				if name == test.failAt {
+					pb.err = fmt.Errorf("%s failed", name)
+				}
+				chain = append(chain, pb)
+			}
+
+			sc := &SchedulerCache{}
+			err := sc.preBind(chain, task)
+			if (err != nil) != test.wantErr {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+			if !reflect.DeepEqual(calls, test.wantCalls) {
+				t.Errorf("expected calls %v, got %v", test.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRegisterPreBinder(t *testing.T) {
+	var calls []string
+	sc := &SchedulerCache{}
+	sc.RegisterPreBinder(&fakePreBinder{name: "network", calls: &calls})
+	sc.RegisterPreBinder(&fakePreBinder{name: "license", calls: &calls})
+	replaced := &fakePreBinder{name: "network", calls: &calls}
+	sc.RegisterPreBinder(replaced)
+
+	var names []string
+	for _, pb := range sc.preBinderChain() {
+		names = append(names, pb.Name())
+	}
+	if want := []string{"volume", "device", "network", "license"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected pre-binders %v, got %v", want, names)
+	}
+	if sc.preBinders[0] != replaced {
+		t.Errorf("expected pre-binder network to be replaced")
+	}
+}
+
+func TestPatchPodAnnotations(t *testing.T) {
+	pod := buildPod("c1", "p1", "n1", v1.PodPending, buildResourceList("1", "1G"), nil, nil)
+	pod.Annotations = map[string]string{"keep": "true"}
+	task := api.NewTaskInfo(pod)
+	kubeClient := fake.NewSimpleClientset(pod)
+
+	if err := patchPodAnnotations(kubeClient, task, map[string]interface{}{"volcano.sh/gpu-index": "0"}); err != nil {
+		t.Fatalf("failed to write annotations: %v", err)
+	}
+	got, _ := kubeClient.CoreV1().Pods("c1").Get(context.TODO(), "p1", metav1.GetOptions{})
+	if want := map[string]string{"keep": "true", "volcano.sh/gpu-index": "0"}; !reflect.DeepEqual(got.Annotations, want) {
+		t.Errorf("expected annotations %v, got %v", want, got.Annotations)
+	}
+
+	if err := patchPodAnnotations(kubeClient, task, map[string]interface{}{"volcano.sh/gpu-index": nil}); err != nil {
+		t.Fatalf("failed to remove annotations: %v", err)
+	}
+	got, _ = kubeClient.CoreV1().Pods("c1").Get(context.TODO(), "p1", metav1.GetOptions{})
+	if want := map[string]string{"keep": "true"}; !reflect.DeepEqual(got.Annotations, want) {
+		t.Errorf("expected annotations %v, got %v", want, got.Annotations)
+	}
+}
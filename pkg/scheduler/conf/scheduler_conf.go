@@ -28,6 +28,67 @@ type SchedulerConfiguration struct {
 	// Configurations is configuration for actions
 	Configurations       []Configuration   `yaml:"configurations"`
 	MetricsConfiguration map[string]string `yaml:"metrics"`
+	// Eviction defines how the victims of preempt and reclaim are evicted
+	Eviction EvictionConfiguration `yaml:"eviction"`
+	// Profiles defines named scheduling profiles, each of them schedules the podgroups selecting it
+	// by scheduling.volcano.sh/scheduling-profile annotation in its own session
+	Profiles []Profile `yaml:"profiles"`
+	// MaintenanceWindows defines the windows in which actions are paused or queues are frozen
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenanceWindows"`
+	// Parallelism is the number of goroutines predicating and scoring nodes in parallel, 16 if not set
+	Parallelism int `yaml:"parallelism"`
+}
+
+// MaintenanceWindow defines a recurring window in which the scheduling of actions or queues is paused
+type MaintenanceWindow struct {
+	// Name is name of window
+	Name string `yaml:"name"`
+	// Schedule is the daily window in UTC in format of HH:MM-HH:MM, e.g. 09:00-18:00, the window is all day if empty
+	Schedule string `yaml:"schedule"`
+	// Days is the days of week the window starts on in cron format, e.g. 1-5 or sat,sun, every day if empty
+	Days string `yaml:"days"`
+	// Actions are the actions skipped in the window, e.g. preempt and reclaim
+	Actions []string `yaml:"actions"`
+	// Queues are the queues whose jobs are not scheduled in the window
+	Queues []string `yaml:"queues"`
+}
+
+// Profile defines a named scheduling profile with its own actions and plugins
+type Profile struct {
+	// Name is name of profile
+	Name string `yaml:"name"`
+	// Actions defines the actions list of profile in order
+	Actions string `yaml:"actions"`
+	// Tiers defines plugins in different tiers of profile
+	Tiers []Tier `yaml:"tiers"`
+	// Configurations is configuration for actions of profile
+	Configurations []Configuration `yaml:"configurations"`
+}
+
+const (
+	// EvictionModeDelete evicts victims by deleting them directly, it is the default mode
+	EvictionModeDelete = "delete"
+	// EvictionModeEviction evicts victims by the Eviction API, which respects PodDisruptionBudgets
+	EvictionModeEviction = "eviction"
+)
+
+// EvictionConfiguration defines how the victims of preempt and reclaim are evicted
+type EvictionConfiguration struct {
+	// Mode is delete or eviction, see EvictionModeDelete and EvictionModeEviction
+	Mode string `yaml:"mode"`
+	// GracePeriodSeconds overrides the termination grace period of victims, the one of pod is used if not set.
+	// It is overridden by the scheduling.volcano.sh/victim-grace-period-seconds annotation of the queue of victim
+	GracePeriodSeconds *int64 `yaml:"gracePeriodSeconds"`
+	// NoEvictLabel is the key of label which makes pods invisible to preempt and reclaim, e.g. volcano.sh/no-evict,
+	// pods with the label set to true are never selected as victims
+	NoEvictLabel string `yaml:"noEvictLabel"`
+	// DomainLabel is the key of node label dividing nodes into preemption domains, e.g. topology.kubernetes.io/zone,
+	// the victims evicted for a job are in the domain of the nodes its tasks are on
+	DomainLabel string `yaml:"domainLabel"`
+	// PipelineTimeout is how long victims may keep terminating after their grace period, e.g. 5m, before their
+	// resources are no longer regarded as releasing, so the tasks pipelined onto their nodes are placed again.
+	// The resources of victims are releasing until they exit if it is empty
+	PipelineTimeout string `yaml:"pipelineTimeout"`
 }
 
 // Tier defines plugin tier
@@ -63,10 +124,12 @@ type PluginOption struct {
 	EnabledReclaimable *bool `yaml:"enableReclaimable"`
 	// EnabledQueueOrder defines whether queueOrderFn is enabled
 	EnabledQueueOrder *bool `yaml:"enableQueueOrder"`
-	// EnabledPredicate defines whether predicateFn is enabled
+	// EnabledClusterOrder defines whether clusterOrderFn is enabled
 	EnabledClusterOrder *bool `yaml:"EnabledClusterOrder"`
-	// EnableClusterOrder defines whether clusterOrderFn is enabled
+	// EnabledPredicate defines whether predicateFn is enabled
 	EnabledPredicate *bool `yaml:"enablePredicate"`
+	// EnabledPrePredicate defines whether prePredicateFn is enabled, it follows EnabledPredicate if not set
+	EnabledPrePredicate *bool `yaml:"enablePrePredicate"`
 	// EnabledBestNode defines whether bestNodeFn is enabled
 	EnabledBestNode *bool `yaml:"enableBestNode"`
 	// EnabledNodeOrder defines whether NodeOrderFn is enabled
@@ -77,14 +140,31 @@ type PluginOption struct {
 	EnabledReservedNodes *bool `yaml:"enableReservedNodes"`
 	// EnabledJobEnqueued defines whether jobEnqueuedFn is enabled
 	EnabledJobEnqueued *bool `yaml:"enableJobEnqueued"`
+	// EnabledJobEnqueueable defines whether jobEnqueueableFn is enabled, it follows EnabledJobEnqueued if not set
+	EnabledJobEnqueueable *bool `yaml:"enableJobEnqueueable"`
+	// EnabledJobValid defines whether jobValidFn is enabled
+	EnabledJobValid *bool `yaml:"enableJobValid"`
 	// EnabledVictim defines whether victimsFn is enabled
 	EnabledVictim *bool `yaml:"enabledVictim"`
+	// EnabledVictimVeto defines whether victimVetoFn is enabled
+	EnabledVictimVeto *bool `yaml:"enableVictimVeto"`
 	// EnabledJobStarving defines whether jobStarvingFn is enabled
 	EnabledJobStarving *bool `yaml:"enableJobStarving"`
 	// EnabledOverused defines whether overusedFn is enabled
 	EnabledOverused *bool `yaml:"enabledOverused"`
 	// EnabledAllocatable defines whether allocatable is enabled
 	EnabledAllocatable *bool `yaml:"enabledAllocatable"`
+	// EnabledParallel defines whether predicateFn and nodeOrderFn of the plugin are called for nodes in parallel,
+	// it is true if not set. The functions of the plugins not safe to be called concurrently are called one at a time
+	EnabledParallel *bool `yaml:"enableParallel"`
+	// ScoreNormalization defines how node scores of the plugin are normalized across candidate nodes
+	// before they are summed up with scores of other plugins, valid values are "none", "minMax" and "zScore".
+	// Empty value means "none", scores of the plugin are summed up as is. It is not normalized by default
+	// since the weights of existing configurations are tuned to the raw scores of the plugins.
+	ScoreNormalization string `yaml:"scoreNormalization"`
+	// APIVersion is the version of the schema of Arguments, v1 if not set. Arguments of an older version
+	// are converted into the latest version of the plugin when the configuration is loaded.
+	APIVersion string `yaml:"apiVersion"`
 	// Arguments defines the different arguments that can be given to different plugins
 	Arguments map[string]interface{} `yaml:"arguments"`
 }
@@ -0,0 +1,241 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	schedcache "volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/capabilities/volumebinding"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+const (
+	// bindDecision is the node a task is bound to
+	bindDecision = "bind"
+	// evictDecision is the reason a task is evicted for
+	evictDecision = "evict"
+	// podGroupDecision is the phase the podgroup of a job is updated to
+	podGroupDecision = "podgroup"
+
+	// maxLoggedDecisionDiffs is the number of differing decisions logged for one dry run
+	maxLoggedDecisionDiffs = 100
+)
+
+// dryRunCache runs sessions on the snapshots of the cache without changing the cluster: the decisions of
+// the session, i.e. bindings, evictions and podgroup phases, are recorded instead of being applied.
+type dryRunCache struct {
+	schedcache.Cache
+
+	mutex     sync.Mutex
+	decisions map[string]map[string]string
+	// states are the copies of the states of plugins changed by the dry run
+	states map[string]interface{}
+}
+
+func newDryRunCache(cache schedcache.Cache) *dryRunCache {
+	return &dryRunCache{
+		Cache: cache,
+		decisions: map[string]map[string]string{
+			bindDecision:     {},
+			evictDecision:    {},
+			podGroupDecision: {},
+		},
+	}
+}
+
+func (dc *dryRunCache) record(decision, key, value string) {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	dc.decisions[decision][key] = value
+}
+
+// AddBindTask records the node the task is bound to.
+func (dc *dryRunCache) AddBindTask(task *api.TaskInfo) error {
+	dc.record(bindDecision, fmt.Sprintf("%s/%s", task.Namespace, task.Name), task.NodeName)
+	return nil
+}
+
+// MarkHungVictim does not mark the victim in dry run, so it is still reported by the sessions scheduling the cluster.
+func (dc *dryRunCache) MarkHungVictim(task *api.TaskInfo) bool {
+	return false
+}
+
+// PluginState returns a copy of the state of the plugin, so that the dry run neither changes the state nor sees it
+// changed by other dry runs. The states which are not cache.PluginStateCloner are created anew.
+func (dc *dryRunCache) PluginState(name string, newState func() interface{}) interface{} {
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	if state, found := dc.states[name]; found {
+		return state
+	}
+	if dc.states == nil {
+		dc.states = map[string]interface{}{}
+	}
+	state := newState()
+	if cloner, ok := dc.Cache.PluginState(name, newState).(schedcache.PluginStateCloner); ok {
+		state = cloner.CloneState()
+	}
+	dc.states[name] = state
+	return state
+}
+
+// Evict records the reason the task is evicted for.
+func (dc *dryRunCache) Evict(task *api.TaskInfo, reason string) error {
+	dc.record(evictDecision, fmt.Sprintf("%s/%s", task.Namespace, task.Name), reason)
+	return nil
+}
+
+// UpdateJobStatus records the phase of the podgroup of the job.
+func (dc *dryRunCache) UpdateJobStatus(job *api.JobInfo, updatePG bool) (*api.JobInfo, error) {
+	if job.PodGroup != nil {
+		dc.record(podGroupDecision, fmt.Sprintf("%s/%s", job.Namespace, job.Name), string(job.PodGroup.Status.Phase))
+	}
+	return job, nil
+}
+
+// BindPodGroup does nothing in dry run.
+func (dc *dryRunCache) BindPodGroup(job *api.JobInfo, cluster string) error {
+	return nil
+}
+
+// RecordJobStatusEvent does nothing in dry run.
+func (dc *dryRunCache) RecordJobStatusEvent(job *api.JobInfo) {}
+
+// UpdateQueueStatus does nothing in dry run.
+func (dc *dryRunCache) UpdateQueueStatus(queue *api.QueueInfo) error {
+	return nil
+}
+
+// AllocateVolumes does nothing in dry run, so that the volumes assumed by the cache are kept.
+func (dc *dryRunCache) AllocateVolumes(task *api.TaskInfo, hostname string, podVolumes *volumebinding.PodVolumes) error {
+	return nil
+}
+
+// BindVolumes does nothing in dry run.
+func (dc *dryRunCache) BindVolumes(task *api.TaskInfo, volumes *volumebinding.PodVolumes) error {
+	return nil
+}
+
+// RevertVolumes does nothing in dry run.
+func (dc *dryRunCache) RevertVolumes(task *api.TaskInfo, podVolumes *volumebinding.PodVolumes) {}
+
+// RegisterPreBinder does nothing in dry run, so that the pre-binders of the active configuration are kept.
+func (dc *dryRunCache) RegisterPreBinder(preBinder schedcache.PreBinder) {}
+
+// UpdateSchedulerNumaInfo does nothing in dry run.
+func (dc *dryRunCache) UpdateSchedulerNumaInfo(sets map[string]api.ResNumaSets) error {
+	return nil
+}
+
+// EventRecorder returns a recorder dropping the events of dry run.
+func (dc *dryRunCache) EventRecorder() record.EventRecorder {
+	return &record.FakeRecorder{}
+}
+
+// decisionDiff is a decision differing between the active and the new configuration,
+// a value is empty if the configuration made no such decision.
+type decisionDiff struct {
+	decision string
+	key      string
+	active   string
+	reloaded string
+}
+
+// diffDecisions returns the decisions of the two dry runs differing, sorted by decision and key.
+func diffDecisions(active, reloaded map[string]map[string]string) []decisionDiff {
+	var diffs []decisionDiff
+	for decision, activeValues := range active {
+		reloadedValues := reloaded[decision]
+		for key, value := range activeValues {
+			if reloadedValues[key] != value {
+				diffs = append(diffs, decisionDiff{decision: decision, key: key, active: value, reloaded: reloadedValues[key]})
+			}
+		}
+		for key, value := range reloadedValues {
+			if _, found := activeValues[key]; !found {
+				diffs = append(diffs, decisionDiff{decision: decision, key: key, reloaded: value})
+			}
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].decision != diffs[j].decision {
+			return diffs[i].decision < diffs[j].decision
+		}
+		return diffs[i].key < diffs[j].key
+	})
+	return diffs
+}
+
+// dryRunConf runs the sessions of the active and of the reloaded configuration on dry-run caches, and logs and
+// exports the decisions differing between them. The comparison runs in background, so that the reload does not wait
+// for it, and the dry runs take turns with the sessions scheduling the cluster instead of holding them up for both.
+func (pc *Scheduler) dryRunConf(actions []framework.Action, plugins []conf.Tier, configurations []conf.Configuration,
+	profiles []*schedulerProfile, maintenanceWindows []*maintenanceWindow) {
+	pc.mutex.Lock()
+	activeActions := pc.actions
+	activePlugins := pc.plugins
+	activeConfigurations := pc.configurations
+	activeProfiles := pc.profiles
+	activeMaintenanceWindows := pc.maintenanceWindows
+	pc.mutex.Unlock()
+
+	go func() {
+		activeCache := pc.dryRunSessions(activeActions, activePlugins, activeConfigurations, activeProfiles, activeMaintenanceWindows)
+		reloadedCache := pc.dryRunSessions(actions, plugins, configurations, profiles, maintenanceWindows)
+		logDecisionDiffs(diffDecisions(activeCache.decisions, reloadedCache.decisions))
+	}()
+}
+
+// dryRunSessions runs the sessions of the configuration on a dry-run cache and returns it with the decisions recorded.
+func (pc *Scheduler) dryRunSessions(actions []framework.Action, plugins []conf.Tier, configurations []conf.Configuration,
+	profiles []*schedulerProfile, maintenanceWindows []*maintenanceWindow) *dryRunCache {
+	pc.sessionMutex.Lock()
+	defer pc.sessionMutex.Unlock()
+
+	// The dry-run sessions are not reported as the scheduling of the cluster, and the actions and the plugins keep
+	// state across sessions, e.g. when nodes were rebalanced, which the dry runs must neither change nor see changed
+	// by each other.
+	defer metrics.SuppressSessionMetrics()()
+	defer framework.SnapshotState()()
+	cache := newDryRunCache(pc.cache)
+	runSessions(cache, actions, plugins, configurations, profiles, maintenanceWindows, nil, nil)
+	return cache
+}
+
+// logDecisionDiffs logs the decisions differing between the dry runs and exports the number of them.
+func logDecisionDiffs(diffs []decisionDiff) {
+	counts := map[string]int{bindDecision: 0, evictDecision: 0, podGroupDecision: 0}
+	for i, diff := range diffs {
+		counts[diff.decision]++
+		if i < maxLoggedDecisionDiffs {
+			klog.Infof("Dry run of reloaded scheduler conf: %s of %s is <%s>, it is <%s> with active conf",
+				diff.decision, diff.key, diff.reloaded, diff.active)
+		}
+	}
+	klog.Infof("Dry run of reloaded scheduler conf: %d bind, %d evict and %d podgroup decisions differ from active conf",
+		counts[bindDecision], counts[evictDecision], counts[podGroupDecision])
+	metrics.UpdateConfigDryRunDiffs(counts)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
+	"volcano.sh/volcano/pkg/scheduler/plugins/proportion"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestDryRunConf(t *testing.T) {
+	framework.RegisterPluginBuilder("drf", drf.New)
+	framework.RegisterPluginBuilder("proportion", proportion.New)
+	defer framework.CleanupPluginBuilders()
+	options.ServerOpts = &options.ServerOption{
+		MinNodesToFind:             100,
+		MinPercentageOfNodesToFind: 5,
+		PercentageOfNodesToFind:    100,
+	}
+
+	binder := &util.FakeBinder{
+		Binds:   map[string]string{},
+		Channel: make(chan string, 10),
+	}
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Binder:        binder,
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+		Recorder:      record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("2", "4Gi"), make(map[string]string)))
+	schedulerCache.AddPod(util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1",
+		make(map[string]string), make(map[string]string)))
+	schedulerCache.AddPodGroupV1beta1(&schedulingv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "c1"},
+		Spec:       schedulingv1.PodGroupSpec{Queue: "c1", MinMember: 1},
+		Status:     schedulingv1.PodGroupStatus{Phase: schedulingv1.PodGroupInqueue},
+	})
+	schedulerCache.AddQueueV1beta1(&schedulingv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+		Spec:       schedulingv1.QueueSpec{Weight: 1},
+	})
+
+	trueValue := true
+	tiers := []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{Name: "drf", EnabledPreemptable: &trueValue, EnabledJobOrder: &trueValue},
+				{Name: "proportion", EnabledQueueOrder: &trueValue, EnabledReclaimable: &trueValue},
+			},
+		},
+	}
+
+	scheduler := &Scheduler{cache: schedulerCache}
+	latencies := actionLatencyCount(t)
+	active := scheduler.dryRunSessions([]framework.Action{allocate.New()}, tiers, nil, nil, nil)
+	reloaded := scheduler.dryRunSessions(nil, tiers, nil, nil, nil)
+
+	if len(binder.Binds) != 0 {
+		t.Errorf("expected no task bound in dry run, got %v", binder.Binds)
+	}
+	if count := actionLatencyCount(t); count != latencies {
+		t.Errorf("expected no action latency observed in dry run, got %d observations", count-latencies)
+	}
+	if metrics.SessionMetricsSuppressed() {
+		t.Errorf("expected the metrics of sessions resumed after dry run")
+	}
+	expected := []decisionDiff{
+		{decision: bindDecision, key: "c1/p1", active: "n1"},
+		{decision: podGroupDecision, key: "c1/pg1", active: string(schedulingv1.PodGroupRunning),
+			reloaded: string(schedulingv1.PodGroupInqueue)},
+	}
+	if diffs := diffDecisions(active.decisions, reloaded.decisions); !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("expected decision diffs %v, got %v", expected, diffs)
+	}
+}
+
+// actionLatencyCount returns the number of the action latencies observed.
+func actionLatencyCount(t *testing.T) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	var count uint64
+	for _, family := range families {
+		if family.GetName() == "volcano_action_scheduling_latency_microseconds" {
+			for _, metric := range family.GetMetric() {
+				count += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return count
+}
+
+// countingState is a state of plugin counting the sessions.
+type countingState struct {
+	sessions int
+}
+
+func (cs *countingState) CloneState() interface{} {
+	clone := *cs
+	return &clone
+}
+
+func TestDryRunPluginState(t *testing.T) {
+	schedulerCache := &cache.SchedulerCache{}
+	newState := func() interface{} { return &countingState{} }
+	schedulerCache.PluginState("counting", newState).(*countingState).sessions = 1
+
+	dryRun := newDryRunCache(schedulerCache)
+	state := dryRun.PluginState("counting", newState).(*countingState)
+	state.sessions++
+	if sessions := dryRun.PluginState("counting", newState).(*countingState).sessions; sessions != 2 {
+		t.Errorf("expected the copy of the state shared by the sessions of the dry run, got %d sessions", sessions)
+	}
+	if sessions := schedulerCache.PluginState("counting", newState).(*countingState).sessions; sessions != 1 {
+		t.Errorf("expected the state of the scheduler not changed by the dry run, got %d sessions", sessions)
+	}
+}
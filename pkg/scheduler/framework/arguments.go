@@ -17,6 +17,8 @@ limitations under the License.
 package framework
 
 import (
+	"fmt"
+
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/pkg/scheduler/conf"
@@ -89,6 +91,26 @@ func (a Arguments) GetBool(ptr *bool, key string) {
 	*ptr = value
 }
 
+// GetString get the string value from string
+func (a Arguments) GetString(ptr *string, key string) {
+	if ptr == nil {
+		return
+	}
+
+	argv, ok := a[key]
+	if !ok {
+		return
+	}
+
+	value, ok := argv.(string)
+	if !ok {
+		klog.Warningf("Could not parse argument: %v for key %s to string", argv, key)
+		return
+	}
+
+	*ptr = value
+}
+
 // GetArgOfActionFromConf return argument of action reading from configuration of schedule
 func GetArgOfActionFromConf(configurations []conf.Configuration, actionName string) Arguments {
 	for _, c := range configurations {
@@ -99,3 +121,76 @@ func GetArgOfActionFromConf(configurations []conf.Configuration, actionName stri
 
 	return nil
 }
+
+// DefaultArgumentsVersion is the apiVersion of the arguments of plugins without apiVersion, i.e. the flat
+// arguments every plugin starts with.
+const DefaultArgumentsVersion = "v1"
+
+// ArgumentsConversion converts the arguments of a plugin from the previous apiVersion, and returns the
+// deprecation warnings of the legacy arguments converted.
+type ArgumentsConversion func(args Arguments) (Arguments, []string, error)
+
+// ArgumentsVersion is an apiVersion of the arguments of a plugin.
+type ArgumentsVersion struct {
+	// Version is the apiVersion, e.g. v2
+	Version string
+	// Convert converts the arguments of the previous apiVersion into Version
+	Convert ArgumentsConversion
+}
+
+// argumentsVersions are the apiVersions of the arguments of plugins after DefaultArgumentsVersion, in order
+var argumentsVersions = map[string][]ArgumentsVersion{}
+
+// RegisterArgumentsVersions registers the apiVersions of the arguments of the plugin after
+// DefaultArgumentsVersion, from the oldest to the latest.
+func RegisterArgumentsVersions(name string, versions ...ArgumentsVersion) {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	argumentsVersions[name] = versions
+}
+
+// ConvertArguments converts the arguments of the plugin from apiVersion, DefaultArgumentsVersion if it is empty,
+// into the latest apiVersion of the plugin. It returns the apiVersion converted into, apiVersion itself if it is
+// the latest one, the converted arguments and the deprecation warnings of the conversions.
+func ConvertArguments(name, apiVersion string, args Arguments) (string, Arguments, []string, error) {
+	pluginMutex.RLock()
+	versions := argumentsVersions[name]
+	pluginMutex.RUnlock()
+
+	next := -1
+	if apiVersion == "" || apiVersion == DefaultArgumentsVersion {
+		next = 0
+	}
+	for i, version := range versions {
+		if version.Version == apiVersion {
+			next = i + 1
+		}
+	}
+	if next < 0 {
+		known := []string{DefaultArgumentsVersion}
+		for _, version := range versions {
+			known = append(known, version.Version)
+		}
+		return "", nil, nil, fmt.Errorf("unknown apiVersion %s of arguments of plugin %s, expected one of %v", apiVersion, name, known)
+	}
+
+	var warnings []string
+	previous := apiVersion
+	if previous == "" {
+		previous = DefaultArgumentsVersion
+	}
+	for _, version := range versions[next:] {
+		converted := Arguments{}
+		for key, value := range args {
+			converted[key] = value
+		}
+		converted, versionWarnings, err := version.Convert(converted)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to convert arguments of plugin %s from %s to %s: %v", name, previous, version.Version, err)
+		}
+		args, apiVersion, previous = converted, version.Version, version.Version
+		warnings = append(warnings, versionWarnings...)
+	}
+	return apiVersion, args, warnings, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package framework
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -186,3 +187,158 @@ func TestGetArgOfActionFromConf(t *testing.T) {
 		}
 	}
 }
+
+func TestArgumentsGetString(t *testing.T) {
+	key1 := "stringkey"
+
+	cases := []struct {
+		name        string
+		arg         Arguments
+		key         string
+		baseValue   string
+		expectValue string
+	}{
+		{
+			name: "key not exist",
+			arg: Arguments{
+				"anotherKey": "value",
+			},
+			key:         key1,
+			baseValue:   "base",
+			expectValue: "base",
+		},
+		{
+			name: "key exist",
+			arg: Arguments{
+				key1: "value",
+			},
+			key:         key1,
+			baseValue:   "base",
+			expectValue: "value",
+		},
+		{
+			name: "value of key invalid",
+			arg: Arguments{
+				key1: 15,
+			},
+			key:         key1,
+			baseValue:   "base",
+			expectValue: "base",
+		},
+	}
+
+	for index, c := range cases {
+		baseValue := c.baseValue
+		c.arg.GetString(nil, c.key)
+		c.arg.GetString(&baseValue, c.key)
+		if baseValue != c.expectValue {
+			t.Errorf("index %d, case %s, value should be %v, but not %v", index, c.name, c.expectValue, baseValue)
+		}
+	}
+}
+
+func TestConvertArguments(t *testing.T) {
+	RegisterArgumentsVersions("versioned",
+		ArgumentsVersion{Version: "v2", Convert: func(args Arguments) (Arguments, []string, error) {
+			if value, found := args["weight"]; found {
+				delete(args, "weight")
+				args["versioned.weight"] = value
+				return args, []string{"weight is deprecated, use versioned.weight instead"}, nil
+			}
+			return args, nil, nil
+		}},
+		ArgumentsVersion{Version: "v3", Convert: func(args Arguments) (Arguments, []string, error) {
+			if _, found := args["versioned.weight"].(string); found {
+				return nil, nil, fmt.Errorf("versioned.weight must be a number")
+			}
+			return args, nil, nil
+		}},
+	)
+	defer RegisterArgumentsVersions("versioned")
+
+	cases := []struct {
+		name           string
+		plugin         string
+		apiVersion     string
+		args           Arguments
+		expectVersion  string
+		expectArgs     Arguments
+		expectWarnings int
+		expectErr      bool
+	}{
+		{
+			name:           "legacy arguments without apiVersion",
+			plugin:         "versioned",
+			args:           Arguments{"weight": 2},
+			expectVersion:  "v3",
+			expectArgs:     Arguments{"versioned.weight": 2},
+			expectWarnings: 1,
+		},
+		{
+			name:          "arguments of the previous apiVersion",
+			plugin:        "versioned",
+			apiVersion:    "v2",
+			args:          Arguments{"weight": 2},
+			expectVersion: "v3",
+			expectArgs:    Arguments{"weight": 2},
+		},
+		{
+			name:          "arguments of the latest apiVersion",
+			plugin:        "versioned",
+			apiVersion:    "v3",
+			args:          Arguments{"versioned.weight": 2},
+			expectVersion: "v3",
+			expectArgs:    Arguments{"versioned.weight": 2},
+		},
+		{
+			name:       "unknown apiVersion",
+			plugin:     "versioned",
+			apiVersion: "v4",
+			args:       Arguments{},
+			expectErr:  true,
+		},
+		{
+			name:       "failed conversion",
+			plugin:     "versioned",
+			apiVersion: "v1",
+			args:       Arguments{"weight": "2"},
+			expectErr:  true,
+		},
+		{
+			name:          "plugin without versions",
+			plugin:        "unversioned",
+			args:          Arguments{"weight": 2},
+			expectVersion: "",
+			expectArgs:    Arguments{"weight": 2},
+		},
+		{
+			name:       "unknown apiVersion of plugin without versions",
+			plugin:     "unversioned",
+			apiVersion: "v2",
+			args:       Arguments{},
+			expectErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		original := Arguments{}
+		for key, value := range c.args {
+			original[key] = value
+		}
+		version, args, warnings, err := ConvertArguments(c.plugin, c.apiVersion, c.args)
+		if (err != nil) != c.expectErr {
+			t.Errorf("case %s: expected error %v, got %v", c.name, c.expectErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.args, original) {
+			t.Errorf("case %s: expected arguments not changed, got %v", c.name, c.args)
+		}
+		if c.expectErr {
+			continue
+		}
+		if version != c.expectVersion || !reflect.DeepEqual(args, c.expectArgs) || len(warnings) != c.expectWarnings {
+			t.Errorf("case %s: expected %s %v with %d warnings, got %s %v with %v",
+				c.name, c.expectVersion, c.expectArgs, c.expectWarnings, version, args, warnings)
+		}
+	}
+}
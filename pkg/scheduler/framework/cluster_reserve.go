@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+const (
+	// ClusterReservePrefix is the prefix of argument keys of the framework configuration which
+	// define the resource kept free in the cluster, e.g. `clusterReserve.cpu: 10%` or
+	// `clusterReserve.memory: 16Gi`. The value is either a quantity or a percentage of the
+	// total allocatable resource of the cluster.
+	ClusterReservePrefix = "clusterReserve."
+	// ClusterReserveExemptPriorityKey is the argument key of the framework configuration of the
+	// priority from which tasks are allowed to use the cluster reserve.
+	ClusterReserveExemptPriorityKey = "clusterReserveExemptPriority"
+)
+
+// clusterReserve is the resource kept free in the cluster for emergency or online workloads.
+type clusterReserve struct {
+	reserve        *api.Resource
+	exemptPriority *int32
+
+	// idle is the total idle resource of the cluster summed from nodeIdle, counted at the first
+	// check in the session and kept by the events of tasks on the nodes.
+	idle     *api.Resource
+	nodeIdle map[string]*api.Resource
+	// pipelined is the total resource pipelined beyond the releasing one summed from nodePipelined,
+	// which is taken from the idle resource of nodes instead, kept as idle.
+	pipelined     *api.Resource
+	nodePipelined map[string]*api.Resource
+}
+
+// pipelinedBeyondReleasing returns the resource pipelined on node beyond its releasing resource.
+func pipelinedBeyondReleasing(node *api.NodeInfo) *api.Resource {
+	if node.Pipelined == nil {
+		return api.EmptyResource()
+	}
+	releasing := node.Releasing
+	if releasing == nil {
+		releasing = api.EmptyResource()
+	}
+	beyond, _ := node.Pipelined.Diff(releasing, api.Zero)
+	return beyond
+}
+
+// newClusterReserve reads the cluster reserve from the framework configuration, it returns
+// nil if no reserve is configured.
+func newClusterReserve(configurations []conf.Configuration, total *api.Resource) *clusterReserve {
+	arguments := GetArgOfActionFromConf(configurations, FrameworkConfigurationName)
+
+	reserve := api.EmptyResource()
+	for key, value := range arguments {
+		if !strings.HasPrefix(key, ClusterReservePrefix) {
+			continue
+		}
+		rn := v1.ResourceName(strings.TrimPrefix(key, ClusterReservePrefix))
+		quantity, err := parseReserve(rn, fmt.Sprint(value), total)
+		if err != nil {
+			klog.Warningf("Invalid %s <%v>, ignore it: %v", key, value, err)
+			continue
+		}
+		switch rn {
+		case v1.ResourceCPU:
+			reserve.MilliCPU = quantity
+		case v1.ResourceMemory:
+			reserve.Memory = quantity
+		default:
+			reserve.SetScalar(rn, quantity)
+		}
+	}
+	if reserve.IsEmpty() {
+		return nil
+	}
+
+	cr := &clusterReserve{reserve: reserve}
+	if _, found := arguments[ClusterReserveExemptPriorityKey]; found {
+		priority := 0
+		arguments.GetInt(&priority, ClusterReserveExemptPriorityKey)
+		exemptPriority := int32(priority)
+		cr.exemptPriority = &exemptPriority
+	}
+	klog.V(4).Infof("Cluster reserve <%v>, exempt priority <%v>", cr.reserve, cr.exemptPriority)
+
+	return cr
+}
+
+// parseReserve parses the reserve of resource rn in the unit of api.Resource.
+func parseReserve(rn v1.ResourceName, value string, total *api.Resource) (float64, error) {
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return 0, err
+		}
+		if percentage < 0 || percentage > 100 {
+			return 0, fmt.Errorf("percentage should be in [0, 100]")
+		}
+		return total.Get(rn) * percentage / 100, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+	if quantity.Sign() < 0 {
+		return 0, fmt.Errorf("quantity should not be negative")
+	}
+	return api.NewResource(v1.ResourceList{rn: quantity}).Get(rn), nil
+}
+
+// AllowedByClusterReserve checks whether allocating task keeps the cluster reserve free. Only the
+// resource requested by task are checked, and tasks of exempt priority are always allowed.
+func (ssn *Session) AllowedByClusterReserve(task *api.TaskInfo) bool {
+	cr := ssn.clusterReserve
+	if cr == nil {
+		return true
+	}
+	if cr.exemptPriority != nil && task.Priority >= *cr.exemptPriority {
+		return true
+	}
+
+	if cr.idle == nil {
+		cr.idle = api.EmptyResource()
+		cr.nodeIdle = make(map[string]*api.Resource, len(ssn.Nodes))
+		cr.pipelined = api.EmptyResource()
+		cr.nodePipelined = make(map[string]*api.Resource, len(ssn.Nodes))
+		for name, node := range ssn.Nodes {
+			cr.nodeIdle[name] = node.Idle.Clone()
+			cr.idle.Add(node.Idle)
+			cr.nodePipelined[name] = pipelinedBeyondReleasing(node)
+			cr.pipelined.Add(cr.nodePipelined[name])
+		}
+	}
+	for _, rn := range cr.reserve.ResourceNames() {
+		request := task.InitResreq.Get(rn)
+		if request <= 0 {
+			continue
+		}
+		// The resource pipelined beyond the releasing one is not idle any more once released.
+		idle := cr.idle.Get(rn) - cr.pipelined.Get(rn)
+		if idle-request < cr.reserve.Get(rn) {
+			klog.V(4).Infof("Task <%s/%s> requests %s <%v> from idle <%v> of cluster, which breaks the reserve <%v>",
+				task.Namespace, task.Name, rn, request, idle, cr.reserve.Get(rn))
+			return false
+		}
+	}
+	return true
+}
+
+// updateClusterIdle replaces the idle and pipelined resource of the node in the totals of the
+// cluster kept for the cluster reserve, after the tasks on the node are changed.
+func (ssn *Session) updateClusterIdle(nodeName string) {
+	cr := ssn.clusterReserve
+	if cr == nil || cr.idle == nil {
+		return
+	}
+	node, found := ssn.Nodes[nodeName]
+	if !found {
+		return
+	}
+	if previous, found := cr.nodeIdle[nodeName]; found {
+		cr.idle.Sub(previous)
+	}
+	cr.nodeIdle[nodeName] = node.Idle.Clone()
+	cr.idle.Add(node.Idle)
+	if previous, found := cr.nodePipelined[nodeName]; found {
+		cr.pipelined.Sub(previous)
+	}
+	cr.nodePipelined[nodeName] = pipelinedBeyondReleasing(node)
+	cr.pipelined.Add(cr.nodePipelined[nodeName])
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+func TestNewClusterReserve(t *testing.T) {
+	total := &api.Resource{MilliCPU: 10000, Memory: 100, ScalarResources: map[v1.ResourceName]float64{"nvidia.com/gpu": 8000}}
+	priority := int32(1000)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		expected  *clusterReserve
+	}{
+		{
+			name: "no reserve",
+			arguments: map[string]interface{}{
+				JobReadyPolicyKey: string(AnyPass),
+			},
+		},
+		{
+			name: "percentage and quantity",
+			arguments: map[string]interface{}{
+				ClusterReservePrefix + "cpu":            "10%",
+				ClusterReservePrefix + "memory":         "20",
+				ClusterReservePrefix + "nvidia.com/gpu": 2,
+			},
+			expected: &clusterReserve{
+				reserve: &api.Resource{MilliCPU: 1000, Memory: 20, ScalarResources: map[v1.ResourceName]float64{"nvidia.com/gpu": 2000}},
+			},
+		},
+		{
+			name: "invalid values are ignored",
+			arguments: map[string]interface{}{
+				ClusterReservePrefix + "cpu":            "120%",
+				ClusterReservePrefix + "memory":         "-1",
+				ClusterReservePrefix + "nvidia.com/gpu": "1",
+				ClusterReserveExemptPriorityKey:         1000,
+			},
+			expected: &clusterReserve{
+				reserve:        &api.Resource{ScalarResources: map[v1.ResourceName]float64{"nvidia.com/gpu": 1000}},
+				exemptPriority: &priority,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configurations := []conf.Configuration{{Name: FrameworkConfigurationName, Arguments: test.arguments}}
+			cr := newClusterReserve(configurations, total)
+			if test.expected == nil {
+				if cr != nil {
+					t.Fatalf("expected no reserve, got <%v>", cr.reserve)
+				}
+				return
+			}
+			if cr == nil {
+				t.Fatalf("expected reserve <%v>, got nil", test.expected.reserve)
+			}
+			if !cr.reserve.Equal(test.expected.reserve, api.Zero) {
+				t.Errorf("expected reserve <%v>, got <%v>", test.expected.reserve, cr.reserve)
+			}
+			if (cr.exemptPriority == nil) != (test.expected.exemptPriority == nil) ||
+				(cr.exemptPriority != nil && *cr.exemptPriority != *test.expected.exemptPriority) {
+				t.Errorf("expected exempt priority <%v>, got <%v>", test.expected.exemptPriority, cr.exemptPriority)
+			}
+		})
+	}
+}
+
+func TestAllowedByClusterReserve(t *testing.T) {
+	priority := int32(1000)
+	ssn := &Session{
+		Nodes: map[string]*api.NodeInfo{
+			"n1": {Name: "n1", Idle: &api.Resource{MilliCPU: 2000, Memory: 100}},
+			"n2": {Name: "n2", Idle: &api.Resource{MilliCPU: 2000, Memory: 100}},
+		},
+	}
+	newTask := func(cpu, memory float64, priority int32) *api.TaskInfo {
+		return &api.TaskInfo{
+			Namespace:  "ns",
+			Name:       "task",
+			Priority:   priority,
+			InitResreq: &api.Resource{MilliCPU: cpu, Memory: memory},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		reserve  *clusterReserve
+		task     *api.TaskInfo
+		expected bool
+	}{
+		{
+			name:     "no reserve",
+			task:     newTask(4000, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "keeps reserve",
+			reserve:  &clusterReserve{reserve: &api.Resource{MilliCPU: 1000}},
+			task:     newTask(3000, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "breaks reserve",
+			reserve:  &clusterReserve{reserve: &api.Resource{MilliCPU: 1000}},
+			task:     newTask(3500, 0, 0),
+			expected: false,
+		},
+		{
+			name:     "reserve of resource not requested",
+			reserve:  &clusterReserve{reserve: &api.Resource{MilliCPU: 1000, Memory: 1000}},
+			task:     newTask(1000, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "exempt priority",
+			reserve:  &clusterReserve{reserve: &api.Resource{MilliCPU: 1000}, exemptPriority: &priority},
+			task:     newTask(3500, 0, 1000),
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ssn.clusterReserve = test.reserve
+			if allowed := ssn.AllowedByClusterReserve(test.task); allowed != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, allowed)
+			}
+		})
+	}
+}
+
+func TestClusterIdleFollowsTasks(t *testing.T) {
+	ssn := &Session{
+		Nodes: map[string]*api.NodeInfo{
+			"n1": {Name: "n1", Idle: &api.Resource{MilliCPU: 2000}},
+			"n2": {Name: "n2", Idle: &api.Resource{MilliCPU: 2000}},
+		},
+		clusterReserve: &clusterReserve{reserve: &api.Resource{MilliCPU: 1000}},
+	}
+	task := &api.TaskInfo{Namespace: "ns", Name: "task", InitResreq: &api.Resource{MilliCPU: 2000}}
+	if !ssn.AllowedByClusterReserve(task) {
+		t.Fatalf("expected task allowed by idle <%v>", ssn.clusterReserve.idle)
+	}
+
+	allocated := &api.TaskInfo{Namespace: "ns", Name: "allocated", TransactionContext: api.TransactionContext{NodeName: "n1"}}
+	ssn.Nodes["n1"].Idle = &api.Resource{MilliCPU: 500}
+	if err := ssn.fireAllocateEvent(allocated, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ssn.AllowedByClusterReserve(task) {
+		t.Errorf("expected task kept by idle <%v> after allocation", ssn.clusterReserve.idle)
+	}
+
+	ssn.Nodes["n1"].Idle = &api.Resource{MilliCPU: 2000}
+	ssn.fireDeallocateEvent(allocated)
+	if !ssn.AllowedByClusterReserve(task) {
+		t.Errorf("expected task allowed by idle <%v> after deallocation", ssn.clusterReserve.idle)
+	}
+}
+
+func TestClusterReserveCountsPipelined(t *testing.T) {
+	ssn := &Session{
+		Nodes: map[string]*api.NodeInfo{
+			"n1": {Name: "n1", Idle: &api.Resource{MilliCPU: 2000}, Releasing: &api.Resource{MilliCPU: 1000}, Pipelined: api.EmptyResource()},
+			"n2": {Name: "n2", Idle: &api.Resource{MilliCPU: 2000}, Releasing: api.EmptyResource(), Pipelined: api.EmptyResource()},
+		},
+		clusterReserve: &clusterReserve{reserve: &api.Resource{MilliCPU: 1000}},
+	}
+	task := &api.TaskInfo{Namespace: "ns", Name: "task", InitResreq: &api.Resource{MilliCPU: 2500}}
+	if !ssn.AllowedByClusterReserve(task) {
+		t.Fatalf("expected task allowed by idle <%v>", ssn.clusterReserve.idle)
+	}
+
+	// 1000 of the 2000 pipelined on n1 are taken from its idle resource once the releasing one is released.
+	pipelined := &api.TaskInfo{Namespace: "ns", Name: "pipelined", TransactionContext: api.TransactionContext{NodeName: "n1"}}
+	ssn.Nodes["n1"].Pipelined = &api.Resource{MilliCPU: 2000}
+	if err := ssn.fireAllocateEvent(pipelined, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ssn.AllowedByClusterReserve(task) {
+		t.Errorf("expected task kept by idle <%v> and pipelined <%v>", ssn.clusterReserve.idle, ssn.clusterReserve.pipelined)
+	}
+	if smaller := (&api.TaskInfo{Namespace: "ns", Name: "smaller", InitResreq: &api.Resource{MilliCPU: 2000}}); !ssn.AllowedByClusterReserve(smaller) {
+		t.Errorf("expected task allowed by idle <%v> and pipelined <%v>", ssn.clusterReserve.idle, ssn.clusterReserve.pipelined)
+	}
+
+	ssn.Nodes["n1"].Pipelined = api.EmptyResource()
+	ssn.fireDeallocateEvent(pipelined)
+	if !ssn.AllowedByClusterReserve(task) {
+		t.Errorf("expected task allowed after the pipelined task is discarded, pipelined <%v>", ssn.clusterReserve.pipelined)
+	}
+}
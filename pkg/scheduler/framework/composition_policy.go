@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+// CompositionPolicy defines how the results of the same kind of functions
+// registered by different plugins are combined.
+type CompositionPolicy string
+
+const (
+	// AllMustPass means every plugin must pass, plugins in all tiers are considered.
+	AllMustPass CompositionPolicy = "allMustPass"
+	// AnyPass means it passes once one plugin passes, plugins in all tiers are considered.
+	AnyPass CompositionPolicy = "anyPass"
+	// Tiered means the first tier which has plugins to make decision decides the result,
+	// every plugin in that tier must pass.
+	Tiered CompositionPolicy = "tiered"
+)
+
+const (
+	// FrameworkConfigurationName is the name of configuration for framework itself
+	// in the configurations of scheduler.
+	FrameworkConfigurationName = "framework"
+
+	// JobReadyPolicyKey is the argument key of composition policy of jobReadyFns.
+	JobReadyPolicyKey = "jobReadyPolicy"
+	// JobPipelinedPolicyKey is the argument key of composition policy of jobPipelinedFns.
+	JobPipelinedPolicyKey = "jobPipelinedPolicy"
+
+	// JobReadyVetoedReason is the reason of event recorded when a plugin vetoes the readiness of job.
+	JobReadyVetoedReason = "JobReadyVetoed"
+)
+
+const (
+	defaultJobReadyPolicy     = AllMustPass
+	defaultJobPipelinedPolicy = Tiered
+)
+
+func validCompositionPolicy(policy CompositionPolicy) bool {
+	switch policy {
+	case AllMustPass, AnyPass, Tiered:
+		return true
+	default:
+		return false
+	}
+}
+
+// getCompositionPolicy reads the composition policy of key from the framework configuration,
+// the default policy is returned if it's not configured or invalid.
+func getCompositionPolicy(configurations []conf.Configuration, key string, defaultPolicy CompositionPolicy) CompositionPolicy {
+	arguments := GetArgOfActionFromConf(configurations, FrameworkConfigurationName)
+	policy := string(defaultPolicy)
+	arguments.GetString(&policy, key)
+
+	if !validCompositionPolicy(CompositionPolicy(policy)) {
+		klog.Warningf("Invalid %s <%s>, use default policy <%s>", key, policy, defaultPolicy)
+		return defaultPolicy
+	}
+	return CompositionPolicy(policy)
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+func newCompositionTestSession(policy CompositionPolicy, readyVotes [][]bool, pipelinedVotes [][]int) *Session {
+	trueValue := true
+	ssn := &Session{
+		jobReadyFns:        map[string]api.ValidateFn{},
+		jobPipelinedFns:    map[string]api.VoteFn{},
+		jobReadyVetoes:     map[api.JobID]string{},
+		jobReadyPolicy:     policy,
+		jobPipelinedPolicy: policy,
+	}
+
+	for i := 0; i < len(readyVotes) || i < len(pipelinedVotes); i++ {
+		tier := conf.Tier{}
+		count := 0
+		if i < len(readyVotes) {
+			count = len(readyVotes[i])
+		}
+		if i < len(pipelinedVotes) && len(pipelinedVotes[i]) > count {
+			count = len(pipelinedVotes[i])
+		}
+		for j := 0; j < count; j++ {
+			name := string(rune('a'+i)) + string(rune('0'+j))
+			tier.Plugins = append(tier.Plugins, conf.PluginOption{
+				Name:                name,
+				EnabledJobReady:     &trueValue,
+				EnabledJobPipelined: &trueValue,
+			})
+			if i < len(readyVotes) && j < len(readyVotes[i]) {
+				vote := readyVotes[i][j]
+				ssn.jobReadyFns[name] = func(interface{}) bool { return vote }
+			}
+			if i < len(pipelinedVotes) && j < len(pipelinedVotes[i]) {
+				vote := pipelinedVotes[i][j]
+				ssn.jobPipelinedFns[name] = func(interface{}) int { return vote }
+			}
+		}
+		ssn.Tiers = append(ssn.Tiers, tier)
+	}
+
+	return ssn
+}
+
+func TestJobReadyCompositionPolicy(t *testing.T) {
+	cases := []struct {
+		name           string
+		policy         CompositionPolicy
+		votes          [][]bool
+		expected       bool
+		expectedVetoer string
+	}{
+		{
+			name:     "allMustPass with all passed",
+			policy:   AllMustPass,
+			votes:    [][]bool{{true}, {true, true}},
+			expected: true,
+		},
+		{
+			name:           "allMustPass with plugin in second tier vetoed",
+			policy:         AllMustPass,
+			votes:          [][]bool{{true}, {true, false}},
+			expected:       false,
+			expectedVetoer: "b1",
+		},
+		{
+			name:     "anyPass with one passed",
+			policy:   AnyPass,
+			votes:    [][]bool{{false}, {false, true}},
+			expected: true,
+		},
+		{
+			name:           "anyPass with none passed",
+			policy:         AnyPass,
+			votes:          [][]bool{{false}, {false}},
+			expected:       false,
+			expectedVetoer: "a0",
+		},
+		{
+			name:     "tiered with first tier passed",
+			policy:   Tiered,
+			votes:    [][]bool{{true, true}, {false}},
+			expected: true,
+		},
+		{
+			name:           "tiered with first tier vetoed",
+			policy:         Tiered,
+			votes:          [][]bool{{true, false}, {true}},
+			expected:       false,
+			expectedVetoer: "a1",
+		},
+		{
+			name:     "no plugin registered",
+			policy:   AnyPass,
+			votes:    nil,
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		ssn := newCompositionTestSession(c.policy, c.votes, nil)
+		job := &api.JobInfo{UID: "job"}
+		if ready := ssn.JobReady(job); ready != c.expected {
+			t.Errorf("case %s: expected ready %v, got %v", c.name, c.expected, ready)
+		}
+		if vetoer, found := ssn.jobReadyVetoes[job.UID]; !found || vetoer != c.expectedVetoer {
+			t.Errorf("case %s: expected vetoer %q, got %q", c.name, c.expectedVetoer, vetoer)
+		}
+	}
+}
+
+func TestRecordJobReadyVetoes(t *testing.T) {
+	job := &api.JobInfo{UID: "job", PodGroup: &api.PodGroup{}}
+	sessionEvents := func(votes [][]bool) int {
+		recorder := record.NewFakeRecorder(10)
+		ssn := newCompositionTestSession(AllMustPass, votes, nil)
+		ssn.recorder = recorder
+		ssn.Jobs = map[api.JobID]*api.JobInfo{job.UID: job}
+		ssn.JobReady(job)
+		recordJobReadyVetoes(ssn)
+		return len(recorder.Events)
+	}
+
+	for i, c := range []struct {
+		votes    [][]bool
+		expected int
+	}{
+		{votes: [][]bool{{true, false}}, expected: 1},
+		{votes: [][]bool{{true, false}}, expected: 0},
+		{votes: [][]bool{{false, true}}, expected: 1},
+		{votes: [][]bool{{true, true}}, expected: 0},
+		{votes: [][]bool{{false, true}}, expected: 1},
+	} {
+		if events := sessionEvents(c.votes); events != c.expected {
+			t.Errorf("session %d: expected %d JobReadyVetoed events, got %d", i, c.expected, events)
+		}
+	}
+}
+
+func TestJobPipelinedCompositionPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   CompositionPolicy
+		votes    [][]int
+		expected bool
+	}{
+		{
+			name:     "tiered permitted by first tier ignores rejection in next tier",
+			policy:   Tiered,
+			votes:    [][]int{{1, 0}, {-1}},
+			expected: true,
+		},
+		{
+			name:     "allMustPass rejected in next tier",
+			policy:   AllMustPass,
+			votes:    [][]int{{1, 0}, {-1}},
+			expected: false,
+		},
+		{
+			name:     "anyPass permitted by one plugin",
+			policy:   AnyPass,
+			votes:    [][]int{{-1, 0}, {1}},
+			expected: true,
+		},
+		{
+			name:     "anyPass all rejected or abstained",
+			policy:   AnyPass,
+			votes:    [][]int{{-1, 0}, {0}},
+			expected: false,
+		},
+		{
+			name:     "all abstained",
+			policy:   AllMustPass,
+			votes:    [][]int{{0}, {0}},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		ssn := newCompositionTestSession(c.policy, nil, c.votes)
+		if pipelined := ssn.JobPipelined(&api.JobInfo{UID: "job"}); pipelined != c.expected {
+			t.Errorf("case %s: expected pipelined %v, got %v", c.name, c.expected, pipelined)
+		}
+	}
+}
+
+func TestGetCompositionPolicy(t *testing.T) {
+	configurations := []conf.Configuration{
+		{
+			Name: FrameworkConfigurationName,
+			Arguments: map[string]interface{}{
+				JobReadyPolicyKey:     "anyPass",
+				JobPipelinedPolicyKey: "unknown",
+			},
+		},
+	}
+
+	if policy := getCompositionPolicy(configurations, JobReadyPolicyKey, defaultJobReadyPolicy); policy != AnyPass {
+		t.Errorf("expected policy %s, got %s", AnyPass, policy)
+	}
+	if policy := getCompositionPolicy(configurations, JobPipelinedPolicyKey, defaultJobPipelinedPolicy); policy != defaultJobPipelinedPolicy {
+		t.Errorf("expected policy %s, got %s", defaultJobPipelinedPolicy, policy)
+	}
+	if policy := getCompositionPolicy(nil, JobReadyPolicyKey, defaultJobReadyPolicy); policy != defaultJobReadyPolicy {
+		t.Errorf("expected policy %s, got %s", defaultJobReadyPolicy, policy)
+	}
+}
@@ -17,16 +17,88 @@ limitations under the License.
 package framework
 
 import (
+	"k8s.io/klog/v2"
+
 	"volcano.sh/volcano/pkg/scheduler/api"
 )
 
 // Event structure
 type Event struct {
 	Task *api.TaskInfo
+	// Err is set by AllocateFunc or PipelineFunc to reject the task, e.g. when the plugin runs out of a
+	// resource it tracks. Releasing the task can not be rejected, the Err set by DeallocateFunc is only logged.
+	Err error
 }
 
-// EventHandler structure
+// EventHandler structure, handlers are called in the order they are added, i.e. the order of plugins in tiers.
 type EventHandler struct {
 	AllocateFunc   func(event *Event)
 	DeallocateFunc func(event *Event)
+	// PipelineFunc is called when the task is pipelined, AllocateFunc is called instead if it is nil.
+	// The pipelined task is released by DeallocateFunc.
+	PipelineFunc func(event *Event)
+}
+
+// fireAllocateEvent calls the handlers for the task allocated, or pipelined if pipeline is true.
+// If a handler rejects the task, the handlers called before are rolled back by DeallocateFunc
+// in reverse order and the error is returned, the caller must revert the task.
+func (ssn *Session) fireAllocateEvent(task *api.TaskInfo, pipeline bool) error {
+	for i, eh := range ssn.eventHandlers {
+		fn := eh.AllocateFunc
+		if pipeline && eh.PipelineFunc != nil {
+			fn = eh.PipelineFunc
+		}
+		if fn == nil {
+			continue
+		}
+
+		event := &Event{Task: task}
+		fn(event)
+		if event.Err == nil {
+			continue
+		}
+
+		for j := i - 1; j >= 0; j-- {
+			if deallocate := ssn.eventHandlers[j].DeallocateFunc; deallocate != nil {
+				notify(deallocate, task)
+			}
+		}
+		return event.Err
+	}
+	ssn.updatePreemptionDomains(task, true)
+	ssn.updateClusterIdle(task.NodeName)
+	return nil
+}
+
+// fireDeallocateEvent calls the handlers for the task released.
+func (ssn *Session) fireDeallocateEvent(task *api.TaskInfo) {
+	for _, eh := range ssn.eventHandlers {
+		if eh.DeallocateFunc != nil {
+			notify(eh.DeallocateFunc, task)
+		}
+	}
+	ssn.updatePreemptionDomains(task, false)
+	ssn.updateClusterIdle(task.NodeName)
+}
+
+// fireRestoreEvent calls AllocateFunc of the handlers for the task which is restored after its
+// release is discarded, the task must not be rejected.
+func (ssn *Session) fireRestoreEvent(task *api.TaskInfo) {
+	for _, eh := range ssn.eventHandlers {
+		if eh.AllocateFunc != nil {
+			notify(eh.AllocateFunc, task)
+		}
+	}
+	ssn.updatePreemptionDomains(task, true)
+	ssn.updateClusterIdle(task.NodeName)
+}
+
+// notify calls fn for the task which can not be rejected, the error of event is only logged.
+func notify(fn func(event *Event), task *api.TaskInfo) {
+	event := &Event{Task: task}
+	fn(event)
+	if event.Err != nil {
+		klog.Errorf("Event handler failed for task <%s/%s>, which can not be rejected: %v",
+			task.Namespace, task.Name, event.Err)
+	}
 }
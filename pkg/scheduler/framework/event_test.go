@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestStatementEventHandlers(t *testing.T) {
+	resources := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")}
+	node := api.NewNodeInfo(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status:     v1.NodeStatus{Allocatable: resources, Capacity: resources},
+	})
+	task := api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p1", UID: "p1"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: resources}}}},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	})
+	task.Job = "ns/j1"
+	job := api.NewJobInfo("ns/j1", task)
+
+	var events []string
+	handlerOf := func(name string, reject bool) *EventHandler {
+		return &EventHandler{
+			AllocateFunc: func(event *Event) {
+				events = append(events, name+"/allocate")
+			},
+			PipelineFunc: func(event *Event) {
+				events = append(events, name+"/pipeline")
+				if reject {
+					event.Err = fmt.Errorf("%s rejects task", name)
+				}
+			},
+			DeallocateFunc: func(event *Event) {
+				events = append(events, name+"/deallocate")
+			},
+		}
+	}
+
+	ssn := &Session{
+		UID:   "ssn",
+		Jobs:  map[api.JobID]*api.JobInfo{job.UID: job},
+		Nodes: map[string]*api.NodeInfo{node.Name: node},
+	}
+	ssn.AddEventHandler(handlerOf("h1", false))
+	ssn.AddEventHandler(&EventHandler{AllocateFunc: func(event *Event) {
+		events = append(events, "h2/allocate")
+	}})
+	ssn.AddEventHandler(handlerOf("h3", true))
+
+	stmt := NewStatement(ssn)
+	if err := stmt.Pipeline(task, node.Name); err == nil {
+		t.Fatalf("expected pipeline rejected by h3")
+	}
+
+	expected := []string{"h1/pipeline", "h2/allocate", "h3/pipeline", "h1/deallocate"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected events %v, got %v", expected, events)
+	}
+	if task.Status != api.Pending || len(task.NodeName) != 0 {
+		t.Errorf("expected task reverted to pending, got status %v on node %q", task.Status, task.NodeName)
+	}
+	if len(node.Tasks) != 0 || !node.Idle.Equal(api.NewResource(resources), api.Zero) {
+		t.Errorf("expected task removed from node, got tasks %v idle %v", node.Tasks, node.Idle)
+	}
+	if len(stmt.operations) != 0 {
+		t.Errorf("expected no operation recorded, got %d", len(stmt.operations))
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/cache"
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
@@ -28,12 +29,36 @@ import (
 
 // OpenSession start the session
 func OpenSession(cache cache.Cache, tiers []conf.Tier, configurations []conf.Configuration) *Session {
+	return OpenProfileSession(cache, tiers, configurations, "", nil)
+}
+
+// OpenProfileSession start the session of the scheduling profile, only the jobs accepted by inProfile
+// are scheduled in the session, and nil inProfile accepts all the jobs. The profile is empty for the default one.
+func OpenProfileSession(cache cache.Cache, tiers []conf.Tier, configurations []conf.Configuration, profile string,
+	inProfile func(*api.JobInfo) bool) *Session {
 	ssn := openSession(cache)
+	ssn.profile = profile
+	if inProfile != nil {
+		for uid, job := range ssn.Jobs {
+			if !inProfile(job) {
+				ssn.otherProfileJobs[uid] = job
+				delete(ssn.Jobs, uid)
+			}
+		}
+	}
 	ssn.Tiers = tiers
+	ssn.serial = newSerialPlugins(tiers)
 	ssn.Configurations = configurations
+	ssn.jobReadyPolicy = getCompositionPolicy(configurations, JobReadyPolicyKey, defaultJobReadyPolicy)
+	ssn.jobPipelinedPolicy = getCompositionPolicy(configurations, JobPipelinedPolicyKey, defaultJobPipelinedPolicy)
+	ssn.clusterReserve = newClusterReserve(configurations, ssn.TotalResource)
 	ssn.NodeMap = GenerateNodeMapAndSlice(ssn.Nodes)
 	ssn.PodLister = NewPodLister(ssn)
 
+	ssn.profiling.setAction(openSessionAction)
+	defer ssn.profiling.clear()
+	openSpan := ssn.startSpan(ssn.span, openSessionAction)
+	defer endSpan(openSpan)
 	for _, tier := range tiers {
 		for _, plugin := range tier.Plugins {
 			if pb, found := GetPluginBuilder(plugin.Name); !found {
@@ -42,7 +67,11 @@ func OpenSession(cache cache.Cache, tiers []conf.Tier, configurations []conf.Con
 				plugin := pb(plugin.Arguments)
 				ssn.plugins[plugin.Name()] = plugin
 				onSessionOpenStart := time.Now()
+				pluginSpan := ssn.startSpan(openSpan, plugin.Name())
+				ssn.profiling.enterPlugin(plugin.Name())
 				plugin.OnSessionOpen(ssn)
+				ssn.profiling.exitPlugin()
+				endSpan(pluginSpan)
 				metrics.UpdatePluginDuration(plugin.Name(), metrics.OnSessionOpen, metrics.Duration(onSessionOpenStart))
 			}
 		}
@@ -52,11 +81,20 @@ func OpenSession(cache cache.Cache, tiers []conf.Tier, configurations []conf.Con
 
 // CloseSession close the session
 func CloseSession(ssn *Session) {
+	ssn.profiling.setAction(closeSessionAction)
+	defer ssn.profiling.clear()
+	defer endSpan(ssn.span)
+	closeSpan := ssn.startSpan(ssn.span, closeSessionAction)
 	for _, plugin := range ssn.plugins {
 		onSessionCloseStart := time.Now()
+		pluginSpan := ssn.startSpan(closeSpan, plugin.Name())
+		ssn.profiling.enterPlugin(plugin.Name())
 		plugin.OnSessionClose(ssn)
+		ssn.profiling.exitPlugin()
+		endSpan(pluginSpan)
 		metrics.UpdatePluginDuration(plugin.Name(), metrics.OnSessionClose, metrics.Duration(onSessionCloseStart))
 	}
 
 	closeSession(ssn)
+	endSpan(closeSpan)
 }
@@ -31,6 +31,14 @@ type Action interface {
 	UnInitialize()
 }
 
+// StatefulAction is the interface of the actions keeping state across sessions.
+type StatefulAction interface {
+	Action
+
+	// SnapshotState saves the state of Action, and returns the function restoring it.
+	SnapshotState() (restore func())
+}
+
 // Plugin is the interface of scheduler plugin
 type Plugin interface {
 	// The unique name of Plugin.
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+const (
+	// NegativeNodeIdle is the invariant that the idle resource of node is never negative
+	NegativeNodeIdle = "negative_node_idle"
+	// QueueAllocatedMismatch is the invariant that the sum of allocated resource of queues equals
+	// the used resource of nodes accounted for the tasks of the jobs
+	QueueAllocatedMismatch = "queue_allocated_mismatch"
+	// TaskDoubleCounted is the invariant that a task is counted once on nodes and in the status index of its job
+	TaskDoubleCounted = "task_double_counted"
+)
+
+// invariantViolation is a violated invariant of scheduling accounting with the offending objects.
+type invariantViolation struct {
+	invariant string
+	message   string
+}
+
+// checkInvariants verifies the accounting of the session, it is called at session close
+// when --invariant-check is enabled, and logs and records metrics of each violation.
+func checkInvariants(ssn *Session) []invariantViolation {
+	var violations []invariantViolation
+	violations = append(violations, checkNodeIdle(ssn)...)
+	violations = append(violations, checkQueueAllocated(ssn)...)
+	violations = append(violations, checkTaskCounted(ssn)...)
+
+	for _, violation := range violations {
+		klog.Errorf("Session %v violates invariant %s: %s", ssn.UID, violation.invariant, violation.message)
+		metrics.RegisterInvariantViolation(violation.invariant)
+	}
+	return violations
+}
+
+// negativeDimensions returns the names of the dimensions of r which are negative.
+func negativeDimensions(r *api.Resource) []string {
+	var names []string
+	if r.MilliCPU < -api.GetMinResource() {
+		names = append(names, string(v1.ResourceCPU))
+	}
+	if r.Memory < -api.GetMinResource() {
+		names = append(names, string(v1.ResourceMemory))
+	}
+	for name, quantity := range r.ScalarResources {
+		if quantity < -api.GetMinResource() {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func checkNodeIdle(ssn *Session) []invariantViolation {
+	var violations []invariantViolation
+	for _, node := range ssn.Nodes {
+		if names := negativeDimensions(node.Idle); len(names) != 0 {
+			violations = append(violations, invariantViolation{
+				invariant: NegativeNodeIdle,
+				message: fmt.Sprintf("idle <%s> of node <%s> is negative in %v, used <%s>, pipelined <%s>",
+					node.Idle, node.Name, names, node.Used, node.Pipelined),
+			})
+		}
+	}
+	return violations
+}
+
+// sessionJobs returns the jobs scheduled in the session and the ones of other profiles.
+func sessionJobs(ssn *Session) map[api.JobID]*api.JobInfo {
+	jobs := make(map[api.JobID]*api.JobInfo, len(ssn.Jobs)+len(ssn.otherProfileJobs))
+	for _, all := range []map[api.JobID]*api.JobInfo{ssn.Jobs, ssn.otherProfileJobs} {
+		for id, job := range all {
+			jobs[id] = job
+		}
+	}
+	return jobs
+}
+
+func checkQueueAllocated(ssn *Session) []invariantViolation {
+	jobs := sessionJobs(ssn)
+
+	// allocated of queues is accounted by jobs, independently of used of nodes accounted by the nodes when the
+	// tasks are added to and removed from them
+	queueAllocated := map[api.QueueID]*api.Resource{}
+	total := api.EmptyResource()
+	for _, job := range jobs {
+		if _, found := queueAllocated[job.Queue]; !found {
+			queueAllocated[job.Queue] = api.EmptyResource()
+		}
+		queueAllocated[job.Queue].Add(job.Allocated)
+		total.Add(job.Allocated)
+	}
+
+	// used of nodes also counts the tasks not allocated to queues, e.g. releasing or of other schedulers, so they
+	// are added to allocated of queues instead of subtracted from used of nodes
+	used := api.EmptyResource()
+	others := api.EmptyResource()
+	for _, node := range ssn.Nodes {
+		if node.Node == nil {
+			continue
+		}
+		used.Add(node.Used)
+		for _, task := range node.Tasks {
+			if task.Status == api.Pipelined {
+				continue
+			}
+			if _, found := jobs[task.Job]; !found || !api.AllocatedStatus(task.Status) {
+				others.Add(task.Resreq)
+			}
+		}
+	}
+	// tasks on the nodes out of the session, e.g. not selected by --node-selector, are not on any node
+	for _, job := range jobs {
+		for _, task := range job.Tasks {
+			if node, found := ssn.Nodes[task.NodeName]; (!found || node.Node == nil) && api.AllocatedStatus(task.Status) {
+				used.Add(task.Resreq)
+			}
+		}
+	}
+
+	accounted := total.Clone().Add(others)
+	if accounted.Equal(used, api.Zero) && used.Equal(accounted, api.Zero) {
+		return nil
+	}
+	return []invariantViolation{{
+		invariant: QueueAllocatedMismatch,
+		message: fmt.Sprintf("sum of allocated of queues <%s> and of other tasks on nodes <%s> does not equal used of nodes <%s>, queues: %v",
+			total, others, used, queueAllocated),
+	}}
+}
+
+func checkTaskCounted(ssn *Session) []invariantViolation {
+	var violations []invariantViolation
+
+	onNodes := map[api.TaskID]string{}
+	for _, node := range ssn.Nodes {
+		for key, task := range node.Tasks {
+			if other, found := onNodes[key]; found {
+				violations = append(violations, invariantViolation{
+					invariant: TaskDoubleCounted,
+					message: fmt.Sprintf("task <%s/%s> is on both node <%s> and node <%s>",
+						task.Namespace, task.Name, other, node.Name),
+				})
+				continue
+			}
+			onNodes[key] = node.Name
+		}
+	}
+
+	for _, job := range sessionJobs(ssn) {
+		indexed := map[api.TaskID]api.TaskStatus{}
+		for status, tasks := range job.TaskStatusIndex {
+			for id, task := range tasks {
+				if other, found := indexed[id]; found {
+					violations = append(violations, invariantViolation{
+						invariant: TaskDoubleCounted,
+						message: fmt.Sprintf("task <%s/%s> of job <%s> is indexed as both %s and %s",
+							task.Namespace, task.Name, job.UID, other, status),
+					})
+					continue
+				}
+				indexed[id] = status
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	resources := v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("2Gi")}
+	request := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")}
+	newSession := func() (*Session, *api.NodeInfo, *api.JobInfo, *api.TaskInfo) {
+		task := api.NewTaskInfo(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p1", UID: "p1"},
+			Spec: v1.PodSpec{
+				NodeName:   "n1",
+				Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: request}}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		})
+		task.Job = "ns/j1"
+		job := api.NewJobInfo("ns/j1", task)
+		job.Queue = "q1"
+		node := api.NewNodeInfo(&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+			Status:     v1.NodeStatus{Allocatable: resources, Capacity: resources},
+		})
+		if err := node.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		return &Session{
+			UID:   "ssn",
+			Jobs:  map[api.JobID]*api.JobInfo{job.UID: job},
+			Nodes: map[string]*api.NodeInfo{node.Name: node},
+		}, node, job, task
+	}
+
+	ssn, _, _, _ := newSession()
+	if violations := checkInvariants(ssn); len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+
+	ssn, node, _, _ := newSession()
+	node.Idle.MilliCPU = -1000
+	if violations := checkInvariants(ssn); len(violations) != 1 || violations[0].invariant != NegativeNodeIdle {
+		t.Errorf("expected negative idle of node, got %v", violations)
+	}
+
+	ssn, _, job, _ := newSession()
+	job.Allocated.MilliCPU += 1000
+	if violations := checkInvariants(ssn); len(violations) != 1 || violations[0].invariant != QueueAllocatedMismatch {
+		t.Errorf("expected mismatched allocated of queues, got %v", violations)
+	}
+
+	// The used resource of nodes is accounted independently of the jobs.
+	ssn, node, _, _ = newSession()
+	node.Used.MilliCPU += 1000
+	if violations := checkInvariants(ssn); len(violations) != 1 || violations[0].invariant != QueueAllocatedMismatch {
+		t.Errorf("expected allocated of queues mismatching used of nodes, got %v", violations)
+	}
+
+	// The tasks of other schedulers are on nodes but not in queues.
+	ssn, node, _, _ = newSession()
+	foreign := api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p2", UID: "p2"},
+		Spec: v1.PodSpec{
+			NodeName:   "n1",
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: request}}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	})
+	if err := node.AddTask(foreign); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	if violations := checkInvariants(ssn); len(violations) != 0 {
+		t.Errorf("expected no violations with tasks of other schedulers, got %v", violations)
+	}
+
+	ssn, _, _, task := newSession()
+	other := api.NewNodeInfo(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n2"},
+		Status:     v1.NodeStatus{Allocatable: resources, Capacity: resources},
+	})
+	clone := task.Clone()
+	clone.Status = api.Pipelined
+	other.Tasks[api.PodKey(task.Pod)] = clone
+	ssn.Nodes[other.Name] = other
+	if violations := checkInvariants(ssn); len(violations) != 1 || violations[0].invariant != TaskDoubleCounted {
+		t.Errorf("expected task double counted on nodes, got %v", violations)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"math"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// NodeCordonedReason is the reason the capacity of a cordoned node is kept from tasks.
+const NodeCordonedReason = metrics.NodeReducedCordoned
+
+// RecordNodeCapacityReduction records the resource of the node kept from tasks in this session for the reason, e.g.
+// the allocatable of a node filtered out for its usage. It is exported in the effective schedulable capacity of the
+// node at session close, so that operators find why the node is treated as full; the reasons other than the
+// metrics.NodeReduced ones are exported together as metrics.NodeReducedOther.
+func (ssn *Session) RecordNodeCapacityReduction(node, reason string, reduced *api.Resource) {
+	if ssn.nodeCapacityReductions[node] == nil {
+		ssn.nodeCapacityReductions[node] = map[string]*api.Resource{}
+	}
+	ssn.nodeCapacityReductions[node][reason] = reduced.Clone()
+}
+
+// nodeCapacityReductionsOf returns the resource of the node kept from tasks by reason, including the ones recorded
+// by plugins, the share of the node of the cluster reserve, and the allocatable of the node if it is cordoned or
+// quarantined. Nodes not ready are left out of the snapshot.
+func (ssn *Session) nodeCapacityReductionsOf(node *api.NodeInfo) map[string]*api.Resource {
+	reductions := map[string]*api.Resource{}
+	if _, found := ssn.quarantinedNodes[node.Name]; found {
+		reductions[metrics.NodeReducedQuarantine] = node.Allocatable
+		return reductions
+	}
+	for reason, reduced := range ssn.nodeCapacityReductions[node.Name] {
+		reductions[reason] = reduced
+	}
+	if share := ssn.clusterReserveShare(node); share != nil {
+		reductions[metrics.NodeReducedClusterReserve] = share
+	}
+	if node.Node.Spec.Unschedulable {
+		reductions[NodeCordonedReason] = node.Allocatable
+	}
+	return reductions
+}
+
+// clusterReserveShare returns the share of the node of the cluster reserve by its allocatable, as the reserve is
+// kept free in the whole cluster instead of on any node. It returns nil if no cluster reserve is configured.
+func (ssn *Session) clusterReserveShare(node *api.NodeInfo) *api.Resource {
+	if ssn.clusterReserve == nil || ssn.TotalResource == nil {
+		return nil
+	}
+	share := api.EmptyResource()
+	if ssn.TotalResource.MilliCPU > 0 {
+		share.MilliCPU = ssn.clusterReserve.reserve.MilliCPU * node.Allocatable.MilliCPU / ssn.TotalResource.MilliCPU
+	}
+	if ssn.TotalResource.Memory > 0 {
+		share.Memory = ssn.clusterReserve.reserve.Memory * node.Allocatable.Memory / ssn.TotalResource.Memory
+	}
+	if share.MilliCPU == 0 && share.Memory == 0 {
+		return nil
+	}
+	return share
+}
+
+// effectiveCapacity returns the allocatable of the node, oversubscription included, without the reductions.
+func effectiveCapacity(node *api.NodeInfo, reductions map[string]*api.Resource) *api.Resource {
+	effective := node.Allocatable.Clone()
+	for _, reduced := range reductions {
+		effective.MilliCPU = math.Max(effective.MilliCPU-reduced.MilliCPU, 0)
+		effective.Memory = math.Max(effective.Memory-reduced.Memory, 0)
+	}
+	return effective
+}
+
+// capacityReducedByLabel sums the reductions by the bounded reasons of metrics.
+func capacityReducedByLabel(reductions map[string]*api.Resource) map[string]*api.Resource {
+	labels := map[string]*api.Resource{}
+	for reason, reduced := range reductions {
+		switch reason {
+		case metrics.NodeReducedCordoned, metrics.NodeReducedUsage, metrics.NodeReducedClusterReserve,
+			metrics.NodeReducedReservation, metrics.NodeReducedQuarantine:
+		default:
+			reason = metrics.NodeReducedOther
+		}
+		if labels[reason] == nil {
+			labels[reason] = api.EmptyResource()
+		}
+		labels[reason].MilliCPU += reduced.MilliCPU
+		labels[reason].Memory += reduced.Memory
+	}
+	return labels
+}
+
+// recordNodeCapacity exports the cpu and memory of each node from capacity to the effective schedulable capacity,
+// and the reductions of the latter by reason, in the session of the profile. The quarantined nodes are exported
+// as the ones kept from tasks as a whole.
+func recordNodeCapacity(ssn *Session) {
+	metrics.ResetNodeCapacityReduced(ssn.profile)
+	for _, nodes := range []map[string]*api.NodeInfo{ssn.Nodes, ssn.quarantinedNodes} {
+		for name, node := range nodes {
+			recordCapacityOf(ssn, name, node)
+		}
+	}
+}
+
+// recordCapacityOf exports the schedulable capacity of the node and the reductions of it.
+func recordCapacityOf(ssn *Session, name string, node *api.NodeInfo) {
+	if node.Node == nil {
+		return
+	}
+	capacity := api.NewResource(node.Node.Status.Capacity)
+	allocatable := api.NewResource(node.Node.Status.Allocatable)
+	reductions := ssn.nodeCapacityReductionsOf(node)
+	effective := effectiveCapacity(node, reductions)
+
+	metrics.UpdateNodeSchedulable(name, ssn.profile, metrics.NodeCapacity, capacity.MilliCPU, capacity.Memory)
+	metrics.UpdateNodeSchedulable(name, ssn.profile, metrics.NodeAllocatable, allocatable.MilliCPU, allocatable.Memory)
+	metrics.UpdateNodeSchedulable(name, ssn.profile, metrics.NodeOversubscribed, node.Allocatable.MilliCPU, node.Allocatable.Memory)
+	metrics.UpdateNodeSchedulable(name, ssn.profile, metrics.NodeEffective, effective.MilliCPU, effective.Memory)
+	for reason, reduced := range capacityReducedByLabel(reductions) {
+		metrics.UpdateNodeCapacityReduced(name, ssn.profile, reason, reduced.MilliCPU, reduced.Memory)
+	}
+	if len(reductions) != 0 {
+		klog.V(4).Infof("Node %s effective schedulable capacity is <%v>, allocatable <%v> is reduced by %v in profile <%s>",
+			name, effective, node.Allocatable, reductions, ssn.profile)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestEffectiveNodeCapacity(t *testing.T) {
+	ready := api.NewNodeInfo(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), map[string]string{}))
+	cordonedNode := util.BuildNode("n2", util.BuildResourceList("4", "8Gi"), map[string]string{})
+	cordonedNode.Spec.Unschedulable = true
+	cordoned := api.NewNodeInfo(cordonedNode)
+	quarantined := api.NewNodeInfo(util.BuildNode("n3", util.BuildResourceList("4", "8Gi"), map[string]string{}))
+
+	ssn := &Session{
+		nodeCapacityReductions: map[string]map[string]*api.Resource{},
+		quarantinedNodes:       map[string]*api.NodeInfo{"n3": quarantined},
+	}
+	ssn.RecordNodeCapacityReduction("n1", "magic", api.NewResource(util.BuildResourceList("1", "1Gi")))
+	reserved := &Session{
+		nodeCapacityReductions: map[string]map[string]*api.Resource{},
+		clusterReserve:         &clusterReserve{reserve: api.NewResource(util.BuildResourceList("2", "2Gi"))},
+		TotalResource:          api.NewResource(util.BuildResourceList("8", "16Gi")),
+	}
+
+	tests := []struct {
+		name              string
+		ssn               *Session
+		node              *api.NodeInfo
+		expectedReasons   []string
+		expectedEffective *api.Resource
+	}{
+		{
+			name:              "reduced by plugin",
+			ssn:               ssn,
+			node:              ready,
+			expectedReasons:   []string{"magic"},
+			expectedEffective: api.NewResource(util.BuildResourceList("3", "7Gi")),
+		},
+		{
+			name:              "cordoned",
+			ssn:               ssn,
+			node:              cordoned,
+			expectedReasons:   []string{NodeCordonedReason},
+			expectedEffective: api.NewResource(v1.ResourceList{}),
+		},
+		{
+			name:              "quarantined",
+			ssn:               ssn,
+			node:              quarantined,
+			expectedReasons:   []string{metrics.NodeReducedQuarantine},
+			expectedEffective: api.NewResource(v1.ResourceList{}),
+		},
+		{
+			name:              "share of cluster reserve by allocatable",
+			ssn:               reserved,
+			node:              ready,
+			expectedReasons:   []string{metrics.NodeReducedClusterReserve},
+			expectedEffective: api.NewResource(util.BuildResourceList("3", "7Gi")),
+		},
+	}
+
+	for _, test := range tests {
+		reductions := test.ssn.nodeCapacityReductionsOf(test.node)
+		var reasons []string
+		for reason := range reductions {
+			reasons = append(reasons, reason)
+		}
+		if !reflect.DeepEqual(reasons, test.expectedReasons) {
+			t.Errorf("%s: expected reductions %v, got %v", test.name, test.expectedReasons, reductions)
+		}
+		effective := effectiveCapacity(test.node, reductions)
+		if effective.MilliCPU != test.expectedEffective.MilliCPU || effective.Memory != test.expectedEffective.Memory {
+			t.Errorf("%s: expected effective capacity %v, got %v", test.name, test.expectedEffective, effective)
+		}
+	}
+
+	// The reductions for the reasons unknown to metrics are exported together.
+	labels := capacityReducedByLabel(map[string]*api.Resource{
+		"magic":                     api.NewResource(util.BuildResourceList("1", "1Gi")),
+		"other-magic":               api.NewResource(util.BuildResourceList("2", "1Gi")),
+		metrics.NodeReducedCordoned: api.NewResource(util.BuildResourceList("4", "8Gi")),
+	})
+	if len(labels) != 2 || labels[metrics.NodeReducedOther].MilliCPU != 3000 || labels[metrics.NodeReducedCordoned].MilliCPU != 4000 {
+		t.Errorf("expected the reductions of magic reasons exported as other, got %v", labels)
+	}
+}
+
+func TestNodeCapacityPerProfile(t *testing.T) {
+	node := api.NewNodeInfo(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), map[string]string{}))
+	record := func(profile string, reduced bool) {
+		ssn := &Session{
+			profile:                profile,
+			Nodes:                  map[string]*api.NodeInfo{"n1": node},
+			nodeCapacityReductions: map[string]map[string]*api.Resource{},
+		}
+		if reduced {
+			ssn.RecordNodeCapacityReduction("n1", metrics.NodeReducedUsage, node.Allocatable)
+		}
+		recordNodeCapacity(ssn)
+	}
+	reducedProfiles := func() map[string]bool {
+		families, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("failed to gather metrics: %v", err)
+		}
+		profiles := map[string]bool{}
+		for _, family := range families {
+			if family.GetName() != "volcano_node_capacity_reduced_milli_cpu" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "profile" {
+						profiles[label.GetValue()] = true
+					}
+				}
+			}
+		}
+		return profiles
+	}
+
+	record("", true)
+	record("batch", false)
+	if profiles := reducedProfiles(); !reflect.DeepEqual(profiles, map[string]bool{"": true}) {
+		t.Errorf("expected the reductions of the default profile kept by the session of profile batch, got %v", profiles)
+	}
+	record("", false)
+	if profiles := reducedProfiles(); len(profiles) != 0 {
+		t.Errorf("expected the reductions of the default profile cleared by its next session, got %v", profiles)
+	}
+}
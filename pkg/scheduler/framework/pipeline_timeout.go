@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// releaseHungVictims regards the resources of the victims terminating longer than timeout after their grace period,
+// e.g. hung on an unmountable volume or a dead kubelet, as used instead of releasing. So the tasks are no longer
+// pipelined onto the nodes of the victims, and are placed again by the actions of the session.
+func (ssn *Session) releaseHungVictims(timeout time.Duration, now time.Time) {
+	for _, node := range ssn.Nodes {
+		var hungTasks []*api.TaskInfo
+		for _, task := range node.Tasks {
+			if task.Status == api.Releasing && task.Pod != nil && task.Pod.DeletionTimestamp != nil &&
+				now.After(task.Pod.DeletionTimestamp.Add(timeout)) {
+				hungTasks = append(hungTasks, task)
+			}
+		}
+
+		for _, task := range hungTasks {
+			hung := task.Clone()
+			hung.Status = api.Unknown
+			if err := node.UpdateTask(hung); err != nil {
+				klog.Errorf("Failed to release hung victim <%s/%s> on node <%s>: %v",
+					task.Namespace, task.Name, node.Name, err)
+				continue
+			}
+			if job, found := ssn.Jobs[task.Job]; found {
+				if jobTask, found := job.Tasks[task.UID]; found {
+					if err := job.UpdateTaskStatus(jobTask, api.Unknown); err != nil {
+						klog.Errorf("Failed to update status of hung victim <%s/%s> in job <%s>: %v",
+							task.Namespace, task.Name, job.UID, err)
+					}
+				}
+			}
+
+			klog.V(3).Infof("Victim <%s/%s> on node <%s> is terminating longer than %v after its grace period, its resources are not releasing",
+				task.Namespace, task.Name, node.Name, timeout)
+			// The victim is released again in every session until it exits, but reported once.
+			if !ssn.cache.MarkHungVictim(task) {
+				continue
+			}
+			ssn.recorder.Eventf(task.Pod, v1.EventTypeWarning, "PipelineTimeout",
+				"Pod is terminating longer than %v after its grace period, the tasks pipelined onto node %s are placed again",
+				timeout, node.Name)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestReleaseHungVictims(t *testing.T) {
+	now := time.Now()
+	node := api.NewNodeInfo(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), map[string]string{}))
+	var tasks []*api.TaskInfo
+	for name, deleted := range map[string]time.Time{"hung": now.Add(-10 * time.Minute), "terminating": now.Add(-time.Minute)} {
+		pod := util.BuildPod("ns", name, "n1", v1.PodRunning, util.BuildResourceList("2", "4Gi"), "pg1", nil, nil)
+		pod.DeletionTimestamp = &metav1.Time{Time: deleted}
+		task := api.NewTaskInfo(pod)
+		if err := node.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+	job := api.NewJobInfo("ns/pg1", tasks...)
+	recorder := record.NewFakeRecorder(10)
+	ssn := &Session{
+		Nodes:    map[string]*api.NodeInfo{"n1": node},
+		Jobs:     map[api.JobID]*api.JobInfo{job.UID: job},
+		recorder: recorder,
+		cache:    &cache.SchedulerCache{},
+	}
+
+	ssn.releaseHungVictims(5*time.Minute, now)
+
+	if futureIdle := node.FutureIdle(); futureIdle.MilliCPU != 2000 {
+		t.Errorf("expected only the resources of terminating victim releasing, got future idle %v", futureIdle)
+	}
+	if hung := job.TaskStatusIndex[api.Unknown]; len(hung) != 1 {
+		t.Errorf("expected hung victim not releasing in job, got %v", hung)
+	}
+	if releasing := job.TaskStatusIndex[api.Releasing]; len(releasing) != 1 {
+		t.Errorf("expected terminating victim releasing in job, got %v", releasing)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "PipelineTimeout") {
+			t.Errorf("unexpected event %s", event)
+		}
+	default:
+		t.Errorf("expected event recorded on hung victim")
+	}
+
+	// The hung victim is released again in the next session, but not reported again.
+	node = api.NewNodeInfo(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), map[string]string{}))
+	pod := util.BuildPod("ns", "hung", "n1", v1.PodRunning, util.BuildResourceList("2", "4Gi"), "pg1", nil, nil)
+	pod.DeletionTimestamp = &metav1.Time{Time: now.Add(-10 * time.Minute)}
+	if err := node.AddTask(api.NewTaskInfo(pod)); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	ssn.Nodes = map[string]*api.NodeInfo{"n1": node}
+	ssn.releaseHungVictims(5*time.Minute, now)
+	if futureIdle := node.FutureIdle(); futureIdle.MilliCPU != 2000 {
+		t.Errorf("expected hung victim not releasing in next session, got future idle %v", futureIdle)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected hung victim reported once, got event %s", <-recorder.Events)
+	}
+}
@@ -98,6 +98,41 @@ func loadPluginBuilder(pluginPath string) (PluginBuilder, error) {
 	return builder, nil
 }
 
+// StateSnapshot saves the state a plugin keeps across sessions, and returns the function restoring it.
+type StateSnapshot = func() (restore func())
+
+var stateSnapshots = map[string]StateSnapshot{}
+
+// RegisterStateSnapshot registers the snapshot of the state the plugin keeps across sessions
+func RegisterStateSnapshot(name string, snapshot StateSnapshot) {
+	pluginMutex.Lock()
+	defer pluginMutex.Unlock()
+
+	stateSnapshots[name] = snapshot
+}
+
+// SnapshotState saves the state the actions and the plugins keep across sessions, and returns the function
+// restoring it, so that sessions which must not change the scheduler, e.g. dry runs, can be run.
+func SnapshotState() (restore func()) {
+	pluginMutex.RLock()
+	defer pluginMutex.RUnlock()
+
+	var restores []func()
+	for _, act := range actionMap {
+		if stateful, ok := act.(StatefulAction); ok {
+			restores = append(restores, stateful.SnapshotState())
+		}
+	}
+	for _, snapshot := range stateSnapshots {
+		restores = append(restores, snapshot())
+	}
+	return func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
 // Action management
 var actionMap = map[string]Action{}
 
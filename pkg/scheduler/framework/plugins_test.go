@@ -18,6 +18,20 @@ package framework
 
 import "testing"
 
+type statefulAction struct {
+	evicted int
+}
+
+func (a *statefulAction) Name() string         { return "stateful" }
+func (a *statefulAction) Initialize()          {}
+func (a *statefulAction) Execute(ssn *Session) { a.evicted++ }
+func (a *statefulAction) UnInitialize()        {}
+
+func (a *statefulAction) SnapshotState() func() {
+	evicted := a.evicted
+	return func() { a.evicted = evicted }
+}
+
 func TestGetPluginName(t *testing.T) {
 	cases := []struct {
 		pluginPath string
@@ -52,3 +66,29 @@ func TestGetPluginName(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotState(t *testing.T) {
+	action := &statefulAction{evicted: 1}
+	RegisterAction(action)
+	defer delete(actionMap, action.Name())
+	sessions := 1
+	RegisterStateSnapshot("stateful", func() func() {
+		saved := sessions
+		return func() { sessions = saved }
+	})
+	defer delete(stateSnapshots, "stateful")
+
+	restore := SnapshotState()
+	// Two dry runs each start from the state saved.
+	for i := 0; i < 2; i++ {
+		action.Execute(nil)
+		sessions++
+		if action.evicted != 2 || sessions != 2 {
+			t.Errorf("expected dry run %d to start from the state saved, got %d evicted and %d sessions", i, action.evicted-1, sessions-1)
+		}
+		restore()
+	}
+	if action.evicted != 1 || sessions != 1 {
+		t.Errorf("expected state restored, got %d evicted and %d sessions", action.evicted, sessions)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// PluginFailure is the last predicate failure of a plugin for the tasks of a job in a session.
+type PluginFailure struct {
+	Plugin string `json:"plugin"`
+	// Nodes is the number of times the plugin failed a task of the job on a node in the session.
+	Nodes  int    `json:"nodes"`
+	Task   string `json:"task"`
+	Node   string `json:"node"`
+	Reason string `json:"reason"`
+}
+
+// predicateFailures records the last predicate failure of each plugin for each job in a session,
+// predicates may be called concurrently. All methods are no-op on nil predicateFailures.
+type predicateFailures struct {
+	mutex    sync.Mutex
+	failures map[api.JobID]map[string]*PluginFailure
+}
+
+func newPredicateFailures() *predicateFailures {
+	return &predicateFailures{failures: map[api.JobID]map[string]*PluginFailure{}}
+}
+
+// record records that the plugin failed the task on the node.
+func (pf *predicateFailures) record(plugin string, task *api.TaskInfo, node *api.NodeInfo,
+	status []*api.Status, err error) {
+	if pf == nil {
+		return
+	}
+	var reasons []string
+	for _, s := range status {
+		if s != nil && s.Code != api.Success && len(s.Reason) != 0 {
+			reasons = append(reasons, s.Reason)
+		}
+	}
+	if len(reasons) == 0 {
+		if err == nil {
+			return
+		}
+		reasons = append(reasons, err.Error())
+	}
+
+	pf.mutex.Lock()
+	defer pf.mutex.Unlock()
+	plugins, found := pf.failures[task.Job]
+	if !found {
+		plugins = map[string]*PluginFailure{}
+		pf.failures[task.Job] = plugins
+	}
+	failure, found := plugins[plugin]
+	if !found {
+		failure = &PluginFailure{Plugin: plugin}
+		plugins[plugin] = failure
+	}
+	failure.Nodes++
+	failure.Task = fmt.Sprintf("%s/%s", task.Namespace, task.Name)
+	failure.Node = node.Name
+	failure.Reason = strings.Join(reasons, "; ")
+}
+
+// PredicateFailures returns the last predicate failure of each plugin for the tasks of the job
+// in the session, sorted by plugin name.
+func (ssn *Session) PredicateFailures(job api.JobID) []PluginFailure {
+	pf := ssn.predicateFailures
+	if pf == nil {
+		return nil
+	}
+	pf.mutex.Lock()
+	defer pf.mutex.Unlock()
+	var failures []PluginFailure
+	for _, failure := range pf.failures[job] {
+		failures = append(failures, *failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Plugin < failures[j].Plugin
+	})
+	return failures
+}
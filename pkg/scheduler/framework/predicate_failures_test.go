@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+func TestPredicateFailures(t *testing.T) {
+	enabled := true
+	ssn := &Session{
+		Tiers: []conf.Tier{{Plugins: []conf.PluginOption{
+			{Name: "gpu", EnabledPredicate: &enabled},
+			{Name: "taint", EnabledPredicate: &enabled},
+		}}},
+		predicateFns: map[string]api.PredicateFn{
+			"gpu": func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+				return []*api.Status{{Code: api.Success}}, nil
+			},
+			"taint": func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+				return []*api.Status{{Code: api.Unschedulable, Reason: "node(s) had untolerated taint"}},
+					fmt.Errorf("plugin taint predicates failed")
+			},
+		},
+		predicateFailures: newPredicateFailures(),
+	}
+
+	task := &api.TaskInfo{Job: "ns/job", Namespace: "ns", Name: "p1"}
+	for _, node := range []string{"n1", "n2"} {
+		if _, err := ssn.PredicateFn(task, &api.NodeInfo{Name: node}); err == nil {
+			t.Errorf("expected task to fail node %s", node)
+		}
+	}
+
+	expected := []PluginFailure{{Plugin: "taint", Nodes: 2, Task: "ns/p1", Node: "n2", Reason: "node(s) had untolerated taint"}}
+	if failures := ssn.PredicateFailures("ns/job"); !reflect.DeepEqual(failures, expected) {
+		t.Errorf("expected failures %v, got %v", expected, failures)
+	}
+	if failures := ssn.PredicateFailures("ns/other"); len(failures) != 0 {
+		t.Errorf("expected no failures of other job, got %v", failures)
+	}
+}
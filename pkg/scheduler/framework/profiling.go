@@ -0,0 +1,320 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// actionLabel is the pprof label of the goroutines running an action.
+	actionLabel = "action"
+	// pluginLabel is the pprof label of the goroutines running a function of a plugin.
+	pluginLabel = "plugin"
+
+	// openSessionAction and closeSessionAction are the action labels of OnSessionOpen and OnSessionClose of plugins.
+	openSessionAction  = "OnSessionOpen"
+	closeSessionAction = "OnSessionClose"
+)
+
+// profilingLabels tags the goroutines running the actions and plugins of session with pprof labels,
+// so that CPU profiles attribute the time to e.g. action allocate and plugin binpack.
+// Goroutines started by an action or plugin, e.g. to predicate nodes in parallel, inherit the labels.
+// All methods are no-op on nil profilingLabels.
+type profilingLabels struct {
+	// action is the actionContext with the labels of current action.
+	action atomic.Value
+	// plugins are the contexts with the labels of current action and the plugins, keyed by action and plugin.
+	plugins sync.Map
+}
+
+// actionContext wraps the context of action, so that contexts of different types are stored in atomic.Value.
+type actionContext struct {
+	ctx context.Context
+}
+
+type pluginLabelsKey struct {
+	action string
+	plugin string
+}
+
+func newProfilingLabels() *profilingLabels {
+	pl := &profilingLabels{}
+	pl.action.Store(actionContext{ctx: context.Background()})
+	return pl
+}
+
+// setAction tags the current goroutine with the label of action.
+func (pl *profilingLabels) setAction(action string) {
+	if pl == nil {
+		return
+	}
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels(actionLabel, action))
+	pl.action.Store(actionContext{ctx: ctx})
+	pprof.SetGoroutineLabels(ctx)
+}
+
+// clear removes the labels from the current goroutine.
+func (pl *profilingLabels) clear() {
+	if pl == nil {
+		return
+	}
+	pl.action.Store(actionContext{ctx: context.Background()})
+	pprof.SetGoroutineLabels(context.Background())
+}
+
+// enterPlugin tags the current goroutine with the labels of current action and plugin.
+func (pl *profilingLabels) enterPlugin(plugin string) {
+	if pl == nil {
+		return
+	}
+	actionCtx := pl.action.Load().(actionContext).ctx
+	action, _ := pprof.Label(actionCtx, actionLabel)
+	key := pluginLabelsKey{action: action, plugin: plugin}
+	ctx, found := pl.plugins.Load(key)
+	if !found {
+		ctx, _ = pl.plugins.LoadOrStore(key, pprof.WithLabels(actionCtx, pprof.Labels(pluginLabel, plugin)))
+	}
+	pprof.SetGoroutineLabels(ctx.(context.Context))
+}
+
+// exitPlugin tags the current goroutine with the label of current action only.
+func (pl *profilingLabels) exitPlugin() {
+	if pl == nil {
+		return
+	}
+	pprof.SetGoroutineLabels(pl.action.Load().(actionContext).ctx)
+}
+
+// ExecuteAction executes action in session, with the goroutine tagged by the label of action if enabled,
+// and traced in a span of action if the session is traced.
+func (ssn *Session) ExecuteAction(action Action) {
+	ssn.profiling.setAction(action.Name())
+	defer ssn.profiling.clear()
+	actionSpan := ssn.startSpan(ssn.span, action.Name())
+	defer endSpan(actionSpan)
+	ssn.latency.setAction(action.Name())
+	action.Execute(ssn)
+	ssn.latency.endAction(ssn, action.Name(), actionSpan)
+}
+
+// The wrapXxx functions tag the goroutines calling fn of plugin with the labels of current action and plugin.
+
+func (pl *profilingLabels) wrapCompareFn(plugin string, fn api.CompareFn) api.CompareFn {
+	if pl == nil {
+		return fn
+	}
+	return func(l, r interface{}) int {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(l, r)
+	}
+}
+
+func (pl *profilingLabels) wrapValidateFn(plugin string, fn api.ValidateFn) api.ValidateFn {
+	if pl == nil {
+		return fn
+	}
+	return func(obj interface{}) bool {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(obj)
+	}
+}
+
+func (pl *profilingLabels) wrapValidateExFn(plugin string, fn api.ValidateExFn) api.ValidateExFn {
+	if pl == nil {
+		return fn
+	}
+	return func(obj interface{}) *api.ValidateResult {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(obj)
+	}
+}
+
+func (pl *profilingLabels) wrapVoteFn(plugin string, fn api.VoteFn) api.VoteFn {
+	if pl == nil {
+		return fn
+	}
+	return func(obj interface{}) int {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(obj)
+	}
+}
+
+func (pl *profilingLabels) wrapJobEnqueuedFn(plugin string, fn api.JobEnqueuedFn) api.JobEnqueuedFn {
+	if pl == nil {
+		return fn
+	}
+	return func(obj interface{}) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		fn(obj)
+	}
+}
+
+func (pl *profilingLabels) wrapPredicateFn(plugin string, fn api.PredicateFn) api.PredicateFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, node)
+	}
+}
+
+func (pl *profilingLabels) wrapPrePredicateFn(plugin string, fn api.PrePredicateFn) api.PrePredicateFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo) error {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task)
+	}
+}
+
+func (pl *profilingLabels) wrapBestNodeFn(plugin string, fn api.BestNodeFn) api.BestNodeFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, scores map[float64][]*api.NodeInfo) *api.NodeInfo {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, scores)
+	}
+}
+
+func (pl *profilingLabels) wrapEvictableFn(plugin string, fn api.EvictableFn) api.EvictableFn {
+	if pl == nil {
+		return fn
+	}
+	return func(evictor *api.TaskInfo, evictees []*api.TaskInfo) ([]*api.TaskInfo, int) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(evictor, evictees)
+	}
+}
+
+func (pl *profilingLabels) wrapNodeOrderFn(plugin string, fn api.NodeOrderFn) api.NodeOrderFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, node)
+	}
+}
+
+func (pl *profilingLabels) wrapBatchNodeOrderFn(plugin string, fn api.BatchNodeOrderFn) api.BatchNodeOrderFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, nodes)
+	}
+}
+
+func (pl *profilingLabels) wrapNodeMapFn(plugin string, fn api.NodeMapFn) api.NodeMapFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, node)
+	}
+}
+
+func (pl *profilingLabels) wrapNodeReduceFn(plugin string, fn api.NodeReduceFn) api.NodeReduceFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, scores k8sframework.NodeScoreList) error {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(task, scores)
+	}
+}
+
+func (pl *profilingLabels) wrapAllocatableFn(plugin string, fn api.AllocatableFn) api.AllocatableFn {
+	if pl == nil {
+		return fn
+	}
+	return func(queue *api.QueueInfo, task *api.TaskInfo) bool {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(queue, task)
+	}
+}
+
+func (pl *profilingLabels) wrapTargetJobFn(plugin string, fn api.TargetJobFn) api.TargetJobFn {
+	if pl == nil {
+		return fn
+	}
+	return func(jobs []*api.JobInfo) *api.JobInfo {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(jobs)
+	}
+}
+
+func (pl *profilingLabels) wrapReservedNodesFn(plugin string, fn api.ReservedNodesFn) api.ReservedNodesFn {
+	if pl == nil {
+		return fn
+	}
+	return func() {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		fn()
+	}
+}
+
+func (pl *profilingLabels) wrapVictimTasksFn(plugin string, fn api.VictimTasksFn) api.VictimTasksFn {
+	if pl == nil {
+		return fn
+	}
+	return func(tasks []*api.TaskInfo) []*api.TaskInfo {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(tasks)
+	}
+}
+
+func (pl *profilingLabels) wrapVictimVetoFn(plugin string, fn api.VictimVetoFn) api.VictimVetoFn {
+	if pl == nil {
+		return fn
+	}
+	return func(evictor *api.TaskInfo, victims []*api.TaskInfo) []*api.TaskInfo {
+		pl.enterPlugin(plugin)
+		defer pl.exitPlugin()
+		return fn(evictor, victims)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestProfilingLabels(t *testing.T) {
+	pl := newProfilingLabels()
+	pl.setAction("allocate")
+	defer pl.clear()
+
+	called := false
+	predicate := pl.wrapPredicateFn("binpack", func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		called = true
+		return nil, nil
+	})
+	if _, err := predicate(nil, nil); err != nil || !called {
+		t.Fatalf("expected wrapped predicate called without error, called %v, err %v", called, err)
+	}
+
+	value, found := pl.plugins.Load(pluginLabelsKey{action: "allocate", plugin: "binpack"})
+	if !found {
+		t.Fatalf("expected labels of action allocate and plugin binpack")
+	}
+	ctx := value.(context.Context)
+	if action, _ := pprof.Label(ctx, actionLabel); action != "allocate" {
+		t.Errorf("expected action label allocate, got %q", action)
+	}
+	if plugin, _ := pprof.Label(ctx, pluginLabel); plugin != "binpack" {
+		t.Errorf("expected plugin label binpack, got %q", plugin)
+	}
+
+	var disabled *profilingLabels
+	disabled.setAction("allocate")
+	disabled.enterPlugin("binpack")
+	disabled.exitPlugin()
+	disabled.clear()
+	if fn := disabled.wrapReservedNodesFn("reservation", nil); fn != nil {
+		t.Errorf("expected fn not wrapped when profiling labels disabled")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"math"
+
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// ScoreNormalizationNone means node scores of plugin are used as is.
+	ScoreNormalizationNone = "none"
+	// ScoreNormalizationMinMax scales node scores of plugin into [0, MaxNodeScore] linearly.
+	ScoreNormalizationMinMax = "minMax"
+	// ScoreNormalizationZScore replaces node scores of plugin with their standard scores.
+	ScoreNormalizationZScore = "zScore"
+)
+
+// scoreNormalizeFn normalizes the node scores of one plugin in place.
+type scoreNormalizeFn func(scores map[string]float64)
+
+// ValidScoreNormalization checks whether the given score normalization policy is supported.
+func ValidScoreNormalization(policy string) bool {
+	switch policy {
+	case "", ScoreNormalizationNone, ScoreNormalizationMinMax, ScoreNormalizationZScore:
+		return true
+	default:
+		return false
+	}
+}
+
+// getScoreNormalizeFn returns the normalize function of policy, nil means no normalization.
+func getScoreNormalizeFn(policy string) scoreNormalizeFn {
+	switch policy {
+	case ScoreNormalizationMinMax:
+		return normalizeMinMax
+	case ScoreNormalizationZScore:
+		return normalizeZScore
+	default:
+		return nil
+	}
+}
+
+// normalizeMinMax scales scores into [0, MaxNodeScore]. If all nodes have the same score,
+// they are all set to 0 since the plugin does not distinguish them.
+func normalizeMinMax(scores map[string]float64) {
+	if len(scores) == 0 {
+		return
+	}
+
+	minScore, maxScore := math.MaxFloat64, -math.MaxFloat64
+	for _, score := range scores {
+		minScore = math.Min(minScore, score)
+		maxScore = math.Max(maxScore, score)
+	}
+
+	scoreRange := maxScore - minScore
+	for name, score := range scores {
+		if scoreRange == 0 {
+			scores[name] = 0
+			continue
+		}
+		scores[name] = (score - minScore) / scoreRange * float64(k8sframework.MaxNodeScore)
+	}
+}
+
+// normalizeZScore replaces scores with (score - mean) / stddev. If all nodes have the same score,
+// they are all set to 0 since the plugin does not distinguish them.
+func normalizeZScore(scores map[string]float64) {
+	if len(scores) == 0 {
+		return
+	}
+
+	var sum float64
+	for _, score := range scores {
+		sum += score
+	}
+	mean := sum / float64(len(scores))
+
+	var variance float64
+	for _, score := range scores {
+		variance += (score - mean) * (score - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(scores)))
+
+	for name, score := range scores {
+		if stddev == 0 {
+			scores[name] = 0
+			continue
+		}
+		scores[name] = (score - mean) / stddev
+	}
+}
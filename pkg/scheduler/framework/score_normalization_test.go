@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScoreNormalization(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   string
+		scores   map[string]float64
+		expected map[string]float64
+	}{
+		{
+			name:     "none keeps scores",
+			policy:   ScoreNormalizationNone,
+			scores:   map[string]float64{"n1": 3, "n2": 1000},
+			expected: map[string]float64{"n1": 3, "n2": 1000},
+		},
+		{
+			name:     "min max scales into node score range",
+			policy:   ScoreNormalizationMinMax,
+			scores:   map[string]float64{"n1": 10, "n2": 20, "n3": 30},
+			expected: map[string]float64{"n1": 0, "n2": 50, "n3": 100},
+		},
+		{
+			name:     "min max with same scores",
+			policy:   ScoreNormalizationMinMax,
+			scores:   map[string]float64{"n1": 7, "n2": 7},
+			expected: map[string]float64{"n1": 0, "n2": 0},
+		},
+		{
+			name:     "z score",
+			policy:   ScoreNormalizationZScore,
+			scores:   map[string]float64{"n1": 2, "n2": 4, "n3": 4, "n4": 4, "n5": 5, "n6": 5, "n7": 7, "n8": 9},
+			expected: map[string]float64{"n1": -1.5, "n2": -0.5, "n3": -0.5, "n4": -0.5, "n5": 0, "n6": 0, "n7": 1, "n8": 2},
+		},
+		{
+			name:     "z score with same scores",
+			policy:   ScoreNormalizationZScore,
+			scores:   map[string]float64{"n1": 7, "n2": 7},
+			expected: map[string]float64{"n1": 0, "n2": 0},
+		},
+	}
+
+	for _, c := range cases {
+		if !ValidScoreNormalization(c.policy) {
+			t.Errorf("case %s: policy %s should be valid", c.name, c.policy)
+		}
+		if fn := getScoreNormalizeFn(c.policy); fn != nil {
+			fn(c.scores)
+		}
+		for name, score := range c.expected {
+			if math.Abs(c.scores[name]-score) > 1e-9 {
+				t.Errorf("case %s: expected score of %s is %v, but got %v", c.name, name, score, c.scores[name])
+			}
+		}
+	}
+
+	if ValidScoreNormalization("max") {
+		t.Errorf("policy max should be invalid")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sync"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+// serialPlugins serializes the calls of the predicate and node order functions of the plugins with enableParallel
+// set to false, as the nodes are predicated and scored in parallel, so that plugins not safe to be called
+// concurrently are called for one node at a time. All methods are no-op on nil serialPlugins.
+type serialPlugins struct {
+	mutexes map[string]*sync.Mutex
+}
+
+// newSerialPlugins returns the serialPlugins of the plugins opting out of parallelism in tiers, nil if none.
+func newSerialPlugins(tiers []conf.Tier) *serialPlugins {
+	mutexes := map[string]*sync.Mutex{}
+	for _, tier := range tiers {
+		for _, plugin := range tier.Plugins {
+			if plugin.EnabledParallel != nil && !*plugin.EnabledParallel {
+				mutexes[plugin.Name] = &sync.Mutex{}
+			}
+		}
+	}
+	if len(mutexes) == 0 {
+		return nil
+	}
+	return &serialPlugins{mutexes: mutexes}
+}
+
+// mutex returns the mutex serializing the calls of plugin, nil if the plugin is called in parallel.
+func (sp *serialPlugins) mutex(plugin string) *sync.Mutex {
+	if sp == nil {
+		return nil
+	}
+	return sp.mutexes[plugin]
+}
+
+func (sp *serialPlugins) wrapPredicateFn(plugin string, fn api.PredicateFn) api.PredicateFn {
+	mutex := sp.mutex(plugin)
+	if mutex == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return fn(task, node)
+	}
+}
+
+func (sp *serialPlugins) wrapNodeOrderFn(plugin string, fn api.NodeOrderFn) api.NodeOrderFn {
+	mutex := sp.mutex(plugin)
+	if mutex == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return fn(task, node)
+	}
+}
+
+func (sp *serialPlugins) wrapNodeMapFn(plugin string, fn api.NodeMapFn) api.NodeMapFn {
+	mutex := sp.mutex(plugin)
+	if mutex == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return fn(task, node)
+	}
+}
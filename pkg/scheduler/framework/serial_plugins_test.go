@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+func TestSerialPlugins(t *testing.T) {
+	falseValue, trueValue := false, true
+	tiers := []conf.Tier{{Plugins: []conf.PluginOption{
+		{Name: "unsafe", EnabledParallel: &falseValue},
+		{Name: "safe", EnabledParallel: &trueValue},
+		{Name: "default"},
+	}}}
+	sp := newSerialPlugins(tiers)
+	if sp.mutex("unsafe") == nil || sp.mutex("safe") != nil || sp.mutex("default") != nil {
+		t.Fatalf("expected only plugin unsafe serialized, got %v", sp.mutexes)
+	}
+
+	var running, overlaps int32
+	predicate := sp.wrapPredicateFn("unsafe", func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		for i := 0; i < 1000; i++ {
+			atomic.LoadInt32(&running)
+		}
+		atomic.AddInt32(&running, -1)
+		return nil, nil
+	})
+	workqueue.ParallelizeUntil(context.TODO(), 16, 1000, func(int) {
+		predicate(nil, nil)
+	})
+	if overlaps != 0 {
+		t.Errorf("expected predicate of plugin unsafe called one at a time, got %d overlapping calls", overlaps)
+	}
+
+	if newSerialPlugins([]conf.Tier{{Plugins: []conf.PluginOption{{Name: "default"}}}}) != nil {
+		t.Errorf("expected no serialPlugins if all plugins are called in parallel")
+	}
+	var none *serialPlugins
+	if fn := none.wrapNodeOrderFn("unsafe", nil); fn != nil {
+		t.Errorf("expected fn not wrapped on nil serialPlugins")
+	}
+}
@@ -19,6 +19,7 @@ package framework
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,6 +63,13 @@ type Session struct {
 	RevocableNodes map[string]*api.NodeInfo
 	Queues         map[api.QueueID]*api.QueueInfo
 	NamespaceInfo  map[api.NamespaceName]*api.NamespaceInfo
+	// otherProfileJobs are the jobs of other scheduling profiles, they are not scheduled in the session
+	// but are still accounted in the status of queues
+	otherProfileJobs map[api.JobID]*api.JobInfo
+	// profile is the name of the scheduling profile of the session, empty for the default profile
+	profile string
+	// quarantinedNodes are the nodes quarantined for bind failures, which are left out of Nodes
+	quarantinedNodes map[string]*api.NodeInfo
 
 	// NodeMap is like Nodes except that it uses k8s NodeInfo api and should only
 	// be used in k8s compatable api scenarios such as in predicates and nodeorder plugins.
@@ -72,6 +80,38 @@ type Session struct {
 	Configurations []conf.Configuration
 	NodeList       []*api.NodeInfo
 
+	// jobReadyPolicy and jobPipelinedPolicy define how the results of
+	// jobReadyFns and jobPipelinedFns from different plugins are combined.
+	jobReadyPolicy     CompositionPolicy
+	jobPipelinedPolicy CompositionPolicy
+	// jobReadyVetoes records the plugin which vetoed the readiness of job at latest check, empty if job is ready.
+	jobReadyVetoes map[api.JobID]string
+	statistics     *sessionStatistics
+	// clusterReserve is the resource kept free in the cluster, nil if not configured.
+	clusterReserve *clusterReserve
+	// noEvictLabel is the key of label which makes pods invisible to preempt and reclaim, empty if not configured.
+	noEvictLabel string
+	// domainLabel is the key of node label dividing nodes into preemption domains, empty if not configured.
+	domainLabel string
+	// jobDomains is the number of the tasks of jobs allocated or pipelined to the nodes of each preemption
+	// domain, counted once for a job in the session and kept by the events of its tasks.
+	jobDomains map[api.JobID]map[string]int
+	// config is the configuration of the session when it is opened.
+	config SessionConfig
+	// profiling tags the goroutines running actions and plugins with pprof labels, nil if not enabled.
+	profiling *profilingLabels
+	// latency measures the latency of the filter and score functions of plugins, nil if not enabled.
+	latency *pluginLatency
+	// serial serializes the calls of the plugins not safe to be called in parallel, nil if there are none.
+	serial *serialPlugins
+	// tracer traces the session, span is the root span of the session, both nil if not traced.
+	tracer Tracer
+	span   Span
+	// nodeCapacityReductions records the resource of nodes kept from tasks by reason in this session.
+	nodeCapacityReductions map[string]map[string]*api.Resource
+	// predicateFailures records the last predicate failures of plugins for the jobs in this session.
+	predicateFailures *predicateFailures
+
 	plugins           map[string]Plugin
 	eventHandlers     []*EventHandler
 	jobOrderFns       map[string]api.CompareFn
@@ -97,6 +137,7 @@ type Session struct {
 	targetJobFns      map[string]api.TargetJobFn
 	reservedNodesFns  map[string]api.ReservedNodesFn
 	victimTasksFns    map[string][]api.VictimTasksFn
+	victimVetoFns     map[string]api.VictimVetoFn
 	jobStarvingFns    map[string]api.ValidateFn
 }
 
@@ -108,6 +149,8 @@ func openSession(cache cache.Cache) *Session {
 		recorder:        cache.EventRecorder(),
 		cache:           cache,
 		informerFactory: cache.SharedInformerFactory(),
+		noEvictLabel:    cache.NoEvictLabel(),
+		domainLabel:     cache.PreemptionDomainLabel(),
 
 		TotalResource:  api.EmptyResource(),
 		podGroupStatus: map[api.JobID]scheduling.PodGroupStatus{},
@@ -117,6 +160,9 @@ func openSession(cache cache.Cache) *Session {
 		CSINodesStatus: map[string]*api.CSINodeStatusInfo{},
 		RevocableNodes: map[string]*api.NodeInfo{},
 		Queues:         map[api.QueueID]*api.QueueInfo{},
+		statistics:     newSessionStatistics(),
+
+		otherProfileJobs: map[api.JobID]*api.JobInfo{},
 
 		plugins:           map[string]Plugin{},
 		jobOrderFns:       map[string]api.CompareFn{},
@@ -142,8 +188,23 @@ func openSession(cache cache.Cache) *Session {
 		targetJobFns:      map[string]api.TargetJobFn{},
 		reservedNodesFns:  map[string]api.ReservedNodesFn{},
 		victimTasksFns:    map[string][]api.VictimTasksFn{},
+		victimVetoFns:     map[string]api.VictimVetoFn{},
 		jobStarvingFns:    map[string]api.ValidateFn{},
+
+		jobReadyPolicy:     defaultJobReadyPolicy,
+		jobPipelinedPolicy: defaultJobPipelinedPolicy,
+		jobReadyVetoes:     map[api.JobID]string{},
+
+		nodeCapacityReductions: map[string]map[string]*api.Resource{},
+		predicateFailures:      newPredicateFailures(),
 	}
+	ssn.config = getSessionConfig()
+	if ssn.config.ProfilingLabels {
+		ssn.profiling = newProfilingLabels()
+	}
+	ssn.tracer = getTracer()
+	ssn.latency = newPluginLatency(ssn.config.PluginLatencyMetrics, ssn.tracer != nil)
+	ssn.span = ssn.startSpan(nil, sessionSpan)
 
 	snapshot := cache.Snapshot()
 
@@ -177,8 +238,12 @@ func openSession(cache cache.Cache) *Session {
 	ssn.Nodes = snapshot.Nodes
 	ssn.CSINodesStatus = snapshot.CSINodesStatus
 	ssn.RevocableNodes = snapshot.RevocableNodes
+	ssn.quarantinedNodes = snapshot.QuarantinedNodes
 	ssn.Queues = snapshot.Queues
 	ssn.NamespaceInfo = snapshot.NamespaceInfo
+	if timeout := cache.PipelineTimeout(); timeout > 0 {
+		ssn.releaseHungVictims(timeout, time.Now())
+	}
 	// calculate all nodes' resource only once in each schedule cycle, other plugins can clone it when need
 	for _, n := range ssn.Nodes {
 		ssn.TotalResource.Add(n.Allocatable)
@@ -197,9 +262,11 @@ func updateQueueStatus(ssn *Session) {
 	for queueID := range ssn.Queues {
 		allocatedResources[queueID] = &api.Resource{}
 	}
-	for _, job := range ssn.Jobs {
-		for _, runningTask := range job.TaskStatusIndex[api.Running] {
-			allocatedResources[job.Queue].Add(runningTask.Resreq)
+	for _, jobs := range []map[api.JobID]*api.JobInfo{ssn.Jobs, ssn.otherProfileJobs} {
+		for _, job := range jobs {
+			for _, runningTask := range job.TaskStatusIndex[api.Running] {
+				allocatedResources[job.Queue].Add(runningTask.Resreq)
+			}
 		}
 	}
 
@@ -221,15 +288,41 @@ func updateQueueStatus(ssn *Session) {
 	}
 }
 
+// recordJobReadyVetoes records events for jobs whose readiness is vetoed by another plugin than at the last check,
+// so a job vetoed by the same plugin in every session is reported once.
+func recordJobReadyVetoes(ssn *Session) {
+	for jobID, plugin := range ssn.jobReadyVetoes {
+		job, found := ssn.Jobs[jobID]
+		if !found || job.ReadyVetoedBy == plugin {
+			continue
+		}
+		job.ReadyVetoedBy = plugin
+		if len(plugin) == 0 {
+			continue
+		}
+		ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeWarning, JobReadyVetoedReason,
+			fmt.Sprintf("Job readiness is vetoed by plugin <%s> under policy <%s>", plugin, ssn.jobReadyPolicy))
+	}
+}
+
 func closeSession(ssn *Session) {
+	if ssn.config.InvariantCheck {
+		checkInvariants(ssn)
+	}
+	recordJobReadyVetoes(ssn)
+	recordSessionSummary(ssn)
+	recordNodeCapacity(ssn)
+
 	ju := newJobUpdater(ssn)
 	ju.UpdateAll()
 
 	updateQueueStatus(ssn)
 
 	ssn.Jobs = nil
+	ssn.otherProfileJobs = nil
 	ssn.Nodes = nil
 	ssn.RevocableNodes = nil
+	ssn.quarantinedNodes = nil
 	ssn.plugins = nil
 	ssn.eventHandlers = nil
 	ssn.jobOrderFns = nil
@@ -237,8 +330,10 @@ func closeSession(ssn *Session) {
 	ssn.clusterOrderFns = nil
 	ssn.NodeList = nil
 	ssn.TotalResource = nil
-
-	klog.V(3).Infof("Close Session %v", ssn.UID)
+	ssn.jobReadyVetoes = nil
+	ssn.statistics = nil
+	ssn.clusterReserve = nil
+	ssn.nodeCapacityReductions = nil
 }
 
 func jobStatus(ssn *Session, jobInfo *api.JobInfo) scheduling.PodGroupStatus {
@@ -260,16 +355,23 @@ func jobStatus(ssn *Session, jobInfo *api.JobInfo) scheduling.PodGroupStatus {
 	} else {
 		allocated := 0
 		for status, tasks := range jobInfo.TaskStatusIndex {
-			if api.AllocatedStatus(status) || status == api.Succeeded {
+			if api.AllocatedStatus(status) {
 				allocated += len(tasks)
 			}
 		}
+		allocated += int(jobInfo.TaskNum(api.Succeeded))
+
+		// The minAvailable of job is lower than its minMember if it falls back after its gang scheduling times out.
+		minMember := jobInfo.PodGroup.Spec.MinMember
+		if jobInfo.MinAvailable < minMember {
+			minMember = jobInfo.MinAvailable
+		}
 
 		// If there're enough allocated resource, it's running
-		if int32(allocated) >= jobInfo.PodGroup.Spec.MinMember {
+		if int32(allocated) >= minMember {
 			status.Phase = scheduling.PodGroupRunning
 			// If all allocated tasks is succeeded, it's completed
-			if len(jobInfo.TaskStatusIndex[api.Succeeded]) == allocated {
+			if int(jobInfo.TaskNum(api.Succeeded)) == allocated {
 				status.Phase = scheduling.PodGroupCompleted
 			}
 		} else if jobInfo.PodGroup.Status.Phase != scheduling.PodGroupInqueue {
@@ -278,12 +380,73 @@ func jobStatus(ssn *Session, jobInfo *api.JobInfo) scheduling.PodGroupStatus {
 	}
 
 	status.Running = int32(len(jobInfo.TaskStatusIndex[api.Running]))
-	status.Failed = int32(len(jobInfo.TaskStatusIndex[api.Failed]))
-	status.Succeeded = int32(len(jobInfo.TaskStatusIndex[api.Succeeded]))
+	status.Failed = jobInfo.TaskNum(api.Failed)
+	status.Succeeded = jobInfo.TaskNum(api.Succeeded)
 
 	return status
 }
 
+// JobPaused returns whether the scheduling of job is paused, either by the job itself or by its queue.
+func (ssn *Session) JobPaused(job *api.JobInfo) bool {
+	if job.Paused {
+		return true
+	}
+	if queue, found := ssn.Queues[job.Queue]; found && queue.Paused {
+		return true
+	}
+	return false
+}
+
+// JobDeferred checks whether enqueue of job is deferred by the not-before time or schedule window
+// of job, which default to the ones of its queue.
+func (ssn *Session) JobDeferred(job *api.JobInfo, now time.Time) bool {
+	notBefore, window := job.NotBefore, job.ScheduleWindow
+	if queue, found := ssn.Queues[job.Queue]; found {
+		if notBefore == nil {
+			notBefore = queue.NotBefore
+		}
+		if window == nil {
+			window = queue.ScheduleWindow
+		}
+	}
+
+	if notBefore != nil && now.Before(*notBefore) {
+		return true
+	}
+	return !window.Contains(now)
+}
+
+// GangSchedulingTimedOut checks whether job has waited for its gang to be fully placed longer than its gang scheduling
+// timeout since it is enqueued, the timeout defaults to the one of its queue. A job running, or with minMember tasks
+// placed, is not waiting for its gang, however long ago it is enqueued.
+func (ssn *Session) GangSchedulingTimedOut(job *api.JobInfo, now time.Time) bool {
+	timeout := job.GangSchedulingTimeout
+	if queue, found := ssn.Queues[job.Queue]; found && timeout == 0 {
+		timeout = queue.GangSchedulingTimeout
+	}
+
+	if timeout == 0 || job.InqueueTime.IsZero() {
+		return false
+	}
+	if job.PodGroup == nil || job.PodGroup.Status.Phase != scheduling.PodGroupInqueue ||
+		job.ReadyTaskNum() >= job.PodGroup.Spec.MinMember {
+		return false
+	}
+	return now.Sub(job.InqueueTime) > timeout
+}
+
+// OtherProfileJobs returns the jobs of other scheduling profiles. They are not scheduled in the session, but
+// their resources are allocated or enqueued in the same queues, so plugins accounting queues count them as well.
+func (ssn *Session) OtherProfileJobs() map[api.JobID]*api.JobInfo {
+	return ssn.otherProfileJobs
+}
+
+// Profile returns the name of the scheduling profile of the session, empty for the default profile. Plugins keeping
+// state across sessions key it by profile, as profiles schedule different jobs in the same queues.
+func (ssn *Session) Profile() string {
+	return ssn.profile
+}
+
 // Statement returns new statement object
 func (ssn *Session) Statement() *Statement {
 	return &Statement{
@@ -323,14 +486,14 @@ func (ssn *Session) Pipeline(task *api.TaskInfo, hostname string) error {
 		return fmt.Errorf("failed to find node %s", hostname)
 	}
 
-	for _, eh := range ssn.eventHandlers {
-		if eh.AllocateFunc != nil {
-			eh.AllocateFunc(&Event{
-				Task: task,
-			})
-		}
+	if err := ssn.fireAllocateEvent(task, true); err != nil {
+		klog.Errorf("Failed to pipeline task <%v/%v> to node <%v> in Session <%v>: %v",
+			task.Namespace, task.Name, hostname, ssn.UID, err)
+		ssn.unassign(task)
+		return err
 	}
 
+	ssn.statistics.tasksPipelined++
 	return nil
 }
 
@@ -385,12 +548,11 @@ func (ssn *Session) Allocate(task *api.TaskInfo, nodeInfo *api.NodeInfo) (err er
 	}
 
 	// Callbacks
-	for _, eh := range ssn.eventHandlers {
-		if eh.AllocateFunc != nil {
-			eh.AllocateFunc(&Event{
-				Task: task,
-			})
-		}
+	if err := ssn.fireAllocateEvent(task, false); err != nil {
+		klog.Errorf("Failed to allocate task <%v/%v> to node <%v> in Session <%v>: %v",
+			task.Namespace, task.Name, hostname, ssn.UID, err)
+		ssn.unassign(task)
+		return err
 	}
 
 	if ssn.JobReady(job) {
@@ -426,7 +588,10 @@ func (ssn *Session) dispatch(task *api.TaskInfo) error {
 		return fmt.Errorf("failed to find job %s", task.Job)
 	}
 
-	metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	ssn.statistics.tasksBound++
+	if !metrics.SessionMetricsSuppressed() {
+		metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	}
 	return nil
 }
 
@@ -459,15 +624,28 @@ func (ssn *Session) Evict(reclaimee *api.TaskInfo, reason string) error {
 		}
 	}
 
-	for _, eh := range ssn.eventHandlers {
-		if eh.DeallocateFunc != nil {
-			eh.DeallocateFunc(&Event{
-				Task: reclaimee,
-			})
+	ssn.fireDeallocateEvent(reclaimee)
+
+	ssn.statistics.tasksEvicted++
+	return nil
+}
+
+// unassign reverts the task to pending after the event handlers rejected it.
+func (ssn *Session) unassign(task *api.TaskInfo) {
+	if job, found := ssn.Jobs[task.Job]; found {
+		if err := job.UpdateTaskStatus(task, api.Pending); err != nil {
+			klog.Errorf("Failed to update task <%v/%v> status to %v when unassigning in Session <%v>: %v",
+				task.Namespace, task.Name, api.Pending, ssn.UID, err)
 		}
 	}
 
-	return nil
+	if node, found := ssn.Nodes[task.NodeName]; found {
+		if err := node.RemoveTask(task); err != nil {
+			klog.Errorf("Failed to remove task <%v/%v> from node <%v> when unassigning in Session <%v>: %v",
+				task.Namespace, task.Name, task.NodeName, ssn.UID, err)
+		}
+	}
+	task.NodeName = ""
 }
 
 // BindPodGroup bind PodGroup to specified cluster
@@ -505,6 +683,18 @@ func (ssn *Session) AddEventHandler(eh *EventHandler) {
 	ssn.eventHandlers = append(ssn.eventHandlers, eh)
 }
 
+// RegisterPreBinder registers the pre-binder called before tasks are bound, e.g. to create the network
+// attachments of the tasks. Pre-binders are kept across sessions, one of the same name is replaced.
+func (ssn *Session) RegisterPreBinder(preBinder cache.PreBinder) {
+	ssn.cache.RegisterPreBinder(preBinder)
+}
+
+// PluginState returns the state the plugin of name keeps across the sessions of the scheduler, it is created by
+// newState at first. Scheduling profiles share the state, dry runs get a copy if it is a cache.PluginStateCloner.
+func (ssn *Session) PluginState(name string, newState func() interface{}) interface{} {
+	return ssn.cache.PluginState(name, newState)
+}
+
 // UpdateSchedulerNumaInfo update SchedulerNumaInfo
 func (ssn *Session) UpdateSchedulerNumaInfo(AllocatedSets map[string]api.ResNumaSets) {
 	ssn.cache.UpdateSchedulerNumaInfo(AllocatedSets)
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import "sync"
+
+// SessionConfig configures the sessions opened after it is set; the scheduler sets it from its flags, so the
+// framework does not depend on the options of the scheduler command.
+type SessionConfig struct {
+	// InvariantCheck verifies the accounting invariants of each session at session close
+	InvariantCheck bool
+	// ProfilingLabels tags the goroutines running actions and plugins with pprof labels
+	ProfilingLabels bool
+	// PluginLatencyMetrics observes the latency of each call of the filter and score functions of plugins
+	PluginLatencyMetrics bool
+}
+
+var (
+	sessionConfigMutex sync.Mutex
+	sessionConfig      SessionConfig
+)
+
+// SetSessionConfig sets the configuration of the sessions opened after it.
+func SetSessionConfig(config SessionConfig) {
+	sessionConfigMutex.Lock()
+	defer sessionConfigMutex.Unlock()
+	sessionConfig = config
+}
+
+func getSessionConfig() SessionConfig {
+	sessionConfigMutex.Lock()
+	defer sessionConfigMutex.Unlock()
+	return sessionConfig
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package framework
 
 import (
+	"k8s.io/klog/v2"
 	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"volcano.sh/apis/pkg/apis/scheduling"
@@ -26,122 +27,134 @@ import (
 
 // AddJobOrderFn add job order function
 func (ssn *Session) AddJobOrderFn(name string, cf api.CompareFn) {
-	ssn.jobOrderFns[name] = cf
+	ssn.jobOrderFns[name] = ssn.profiling.wrapCompareFn(name, cf)
 }
 
 // AddQueueOrderFn add queue order function
 func (ssn *Session) AddQueueOrderFn(name string, qf api.CompareFn) {
-	ssn.queueOrderFns[name] = qf
+	ssn.queueOrderFns[name] = ssn.profiling.wrapCompareFn(name, qf)
 }
 
 // AddClusterOrderFn add queue order function
 func (ssn *Session) AddClusterOrderFn(name string, qf api.CompareFn) {
-	ssn.clusterOrderFns[name] = qf
+	ssn.clusterOrderFns[name] = ssn.profiling.wrapCompareFn(name, qf)
 }
 
 // AddTaskOrderFn add task order function
 func (ssn *Session) AddTaskOrderFn(name string, cf api.CompareFn) {
-	ssn.taskOrderFns[name] = cf
+	ssn.taskOrderFns[name] = ssn.profiling.wrapCompareFn(name, cf)
 }
 
 // AddPreemptableFn add preemptable function
 func (ssn *Session) AddPreemptableFn(name string, cf api.EvictableFn) {
-	ssn.preemptableFns[name] = cf
+	ssn.preemptableFns[name] = ssn.profiling.wrapEvictableFn(name, cf)
 }
 
 // AddReclaimableFn add Reclaimable function
 func (ssn *Session) AddReclaimableFn(name string, rf api.EvictableFn) {
-	ssn.reclaimableFns[name] = rf
+	ssn.reclaimableFns[name] = ssn.profiling.wrapEvictableFn(name, rf)
 }
 
 // AddJobReadyFn add JobReady function
 func (ssn *Session) AddJobReadyFn(name string, vf api.ValidateFn) {
-	ssn.jobReadyFns[name] = vf
+	ssn.jobReadyFns[name] = ssn.profiling.wrapValidateFn(name, vf)
 }
 
 // AddJobPipelinedFn add pipelined function
 func (ssn *Session) AddJobPipelinedFn(name string, vf api.VoteFn) {
-	ssn.jobPipelinedFns[name] = vf
+	ssn.jobPipelinedFns[name] = ssn.profiling.wrapVoteFn(name, vf)
 }
 
 // AddPredicateFn add Predicate function
 func (ssn *Session) AddPredicateFn(name string, pf api.PredicateFn) {
-	ssn.predicateFns[name] = pf
+	ssn.predicateFns[name] = ssn.serial.wrapPredicateFn(name, ssn.profiling.wrapPredicateFn(name, ssn.latency.wrapPredicateFn(name, pf)))
 }
 
 // AddPrePredicateFn add PrePredicate function
 func (ssn *Session) AddPrePredicateFn(name string, pf api.PrePredicateFn) {
-	ssn.prePredicateFns[name] = pf
+	ssn.prePredicateFns[name] = ssn.profiling.wrapPrePredicateFn(name, ssn.latency.wrapPrePredicateFn(name, pf))
 }
 
 // AddBestNodeFn add BestNode function
 func (ssn *Session) AddBestNodeFn(name string, pf api.BestNodeFn) {
-	ssn.bestNodeFns[name] = pf
+	ssn.bestNodeFns[name] = ssn.profiling.wrapBestNodeFn(name, ssn.latency.wrapBestNodeFn(name, pf))
 }
 
 // AddNodeOrderFn add Node order function
 func (ssn *Session) AddNodeOrderFn(name string, pf api.NodeOrderFn) {
-	ssn.nodeOrderFns[name] = pf
+	ssn.nodeOrderFns[name] = ssn.serial.wrapNodeOrderFn(name, ssn.profiling.wrapNodeOrderFn(name, ssn.latency.wrapNodeOrderFn(name, pf)))
 }
 
 // AddBatchNodeOrderFn add Batch Node order function
 func (ssn *Session) AddBatchNodeOrderFn(name string, pf api.BatchNodeOrderFn) {
-	ssn.batchNodeOrderFns[name] = pf
+	ssn.batchNodeOrderFns[name] = ssn.profiling.wrapBatchNodeOrderFn(name, ssn.latency.wrapBatchNodeOrderFn(name, pf))
 }
 
 // AddNodeMapFn add Node map function
 func (ssn *Session) AddNodeMapFn(name string, pf api.NodeMapFn) {
-	ssn.nodeMapFns[name] = pf
+	ssn.nodeMapFns[name] = ssn.serial.wrapNodeMapFn(name, ssn.profiling.wrapNodeMapFn(name, ssn.latency.wrapNodeMapFn(name, pf)))
 }
 
 // AddNodeReduceFn add Node reduce function
 func (ssn *Session) AddNodeReduceFn(name string, pf api.NodeReduceFn) {
-	ssn.nodeReduceFns[name] = pf
+	ssn.nodeReduceFns[name] = ssn.profiling.wrapNodeReduceFn(name, ssn.latency.wrapNodeReduceFn(name, pf))
 }
 
 // AddOverusedFn add overused function
 func (ssn *Session) AddOverusedFn(name string, fn api.ValidateFn) {
-	ssn.overusedFns[name] = fn
+	ssn.overusedFns[name] = ssn.profiling.wrapValidateFn(name, fn)
 }
 
 // AddAllocatableFn add allocatable function
 func (ssn *Session) AddAllocatableFn(name string, fn api.AllocatableFn) {
-	ssn.allocatableFns[name] = fn
+	ssn.allocatableFns[name] = ssn.profiling.wrapAllocatableFn(name, fn)
 }
 
 // AddJobValidFn add jobvalid function
 func (ssn *Session) AddJobValidFn(name string, fn api.ValidateExFn) {
-	ssn.jobValidFns[name] = fn
+	ssn.jobValidFns[name] = ssn.profiling.wrapValidateExFn(name, fn)
 }
 
 // AddJobEnqueueableFn add jobenqueueable function
 func (ssn *Session) AddJobEnqueueableFn(name string, fn api.VoteFn) {
-	ssn.jobEnqueueableFns[name] = fn
+	ssn.jobEnqueueableFns[name] = ssn.profiling.wrapVoteFn(name, fn)
 }
 
 // AddJobEnqueuedFn add jobEnqueued function
 func (ssn *Session) AddJobEnqueuedFn(name string, fn api.JobEnqueuedFn) {
-	ssn.jobEnqueuedFns[name] = fn
+	ssn.jobEnqueuedFns[name] = ssn.profiling.wrapJobEnqueuedFn(name, fn)
 }
 
 // AddTargetJobFn add targetjob function
 func (ssn *Session) AddTargetJobFn(name string, fn api.TargetJobFn) {
-	ssn.targetJobFns[name] = fn
+	ssn.targetJobFns[name] = ssn.profiling.wrapTargetJobFn(name, fn)
 }
 
 // AddReservedNodesFn add reservedNodesFn function
 func (ssn *Session) AddReservedNodesFn(name string, fn api.ReservedNodesFn) {
-	ssn.reservedNodesFns[name] = fn
+	ssn.reservedNodesFns[name] = ssn.profiling.wrapReservedNodesFn(name, fn)
 }
 
 // AddVictimTasksFns add victimTasksFns function
 func (ssn *Session) AddVictimTasksFns(name string, fns []api.VictimTasksFn) {
+	if ssn.profiling != nil {
+		wrapped := make([]api.VictimTasksFn, 0, len(fns))
+		for _, fn := range fns {
+			wrapped = append(wrapped, ssn.profiling.wrapVictimTasksFn(name, fn))
+		}
+		fns = wrapped
+	}
 	ssn.victimTasksFns[name] = fns
 }
 
+// AddVictimVetoFn add victimVetoFn function
+func (ssn *Session) AddVictimVetoFn(name string, fn api.VictimVetoFn) {
+	ssn.victimVetoFns[name] = ssn.profiling.wrapVictimVetoFn(name, fn)
+}
+
 // AddJobStarvingFns add jobStarvingFns function
 func (ssn *Session) AddJobStarvingFns(name string, fn api.ValidateFn) {
-	ssn.jobStarvingFns[name] = fn
+	ssn.jobStarvingFns[name] = ssn.profiling.wrapValidateFn(name, fn)
 }
 
 // Reclaimable invoke reclaimable function of the plugins
@@ -149,6 +162,11 @@ func (ssn *Session) Reclaimable(reclaimer *api.TaskInfo, reclaimees []*api.TaskI
 	var victims []*api.TaskInfo
 	var init bool
 
+	reclaimees = ssn.evictable(reclaimees)
+	if len(reclaimees) == 0 {
+		return nil
+	}
+
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledReclaimable) {
@@ -199,6 +217,11 @@ func (ssn *Session) Preemptable(preemptor *api.TaskInfo, preemptees []*api.TaskI
 	var victims []*api.TaskInfo
 	var init bool
 
+	preemptees = ssn.evictable(preemptees)
+	if len(preemptees) == 0 {
+		return nil
+	}
+
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledPreemptable) {
@@ -246,6 +269,90 @@ func (ssn *Session) Preemptable(preemptor *api.TaskInfo, preemptees []*api.TaskI
 	return victims
 }
 
+// evictable filters out the tasks with the no-evict label, which are never victims.
+func (ssn *Session) evictable(tasks []*api.TaskInfo) []*api.TaskInfo {
+	if len(ssn.noEvictLabel) == 0 {
+		return tasks
+	}
+
+	var evictable []*api.TaskInfo
+	for _, task := range tasks {
+		if api.IsNoEvict(task.Pod, ssn.noEvictLabel) {
+			klog.V(4).Infof("Task <%s/%s> is not evictable, it has no-evict label %s",
+				task.Namespace, task.Name, ssn.noEvictLabel)
+			continue
+		}
+		evictable = append(evictable, task)
+	}
+	return evictable
+}
+
+// Evictable filters out the tasks with the no-evict label, for the actions evicting tasks without evictors.
+func (ssn *Session) Evictable(tasks []*api.TaskInfo) []*api.TaskInfo {
+	return ssn.evictable(tasks)
+}
+
+// InPreemptionDomain returns whether victims on the node may be evicted for the job, i.e. the node is in the
+// preemption domain of a node the tasks of the job are allocated or pipelined to. It is always true if the domain
+// label is not configured, or if no task of the job is on a node yet, so the first node chosen sets the domain.
+func (ssn *Session) InPreemptionDomain(job *api.JobInfo, node *api.NodeInfo) bool {
+	if len(ssn.domainLabel) == 0 || node.Node == nil {
+		return true
+	}
+
+	domains := ssn.preemptionDomains(job)
+	return len(domains) == 0 || domains[node.Node.Labels[ssn.domainLabel]] > 0
+}
+
+// preemptionDomains returns the number of the tasks of job allocated or pipelined to the nodes of each preemption
+// domain, it is counted at the first call for job in the session and kept by updatePreemptionDomains.
+func (ssn *Session) preemptionDomains(job *api.JobInfo) map[string]int {
+	if domains, found := ssn.jobDomains[job.UID]; found {
+		return domains
+	}
+	domains := map[string]int{}
+	for _, task := range job.Tasks {
+		if len(task.NodeName) == 0 || !(api.AllocatedStatus(task.Status) || task.Status == api.Pipelined) {
+			continue
+		}
+		if domain, found := ssn.nodeDomain(task.NodeName); found {
+			domains[domain]++
+		}
+	}
+	if ssn.jobDomains == nil {
+		ssn.jobDomains = map[api.JobID]map[string]int{}
+	}
+	ssn.jobDomains[job.UID] = domains
+	return domains
+}
+
+// updatePreemptionDomains counts the task allocated or pipelined to its node if placed, or released from it
+// otherwise, in the preemption domains of its job if they are counted already.
+func (ssn *Session) updatePreemptionDomains(task *api.TaskInfo, placed bool) {
+	domains, found := ssn.jobDomains[task.Job]
+	if !found {
+		return
+	}
+	domain, found := ssn.nodeDomain(task.NodeName)
+	if !found {
+		return
+	}
+	if placed {
+		domains[domain]++
+	} else if domains[domain]--; domains[domain] <= 0 {
+		delete(domains, domain)
+	}
+}
+
+// nodeDomain returns the preemption domain of the node, false if the node is not in the session.
+func (ssn *Session) nodeDomain(nodeName string) (string, bool) {
+	node, found := ssn.Nodes[nodeName]
+	if !found || node.Node == nil {
+		return "", false
+	}
+	return node.Node.Labels[ssn.domainLabel], true
+}
+
 // Overused invoke overused function of the plugins
 func (ssn *Session) Overused(queue *api.QueueInfo) bool {
 	for _, tier := range ssn.Tiers {
@@ -286,8 +393,33 @@ func (ssn *Session) Allocatable(queue *api.QueueInfo, candidate *api.TaskInfo) b
 	return true
 }
 
-// JobReady invoke jobready function of the plugins
+// JobReady invoke jobready function of the plugins, the results are combined by jobReadyPolicy:
+//   - allMustPass: job is ready only if all plugins pass;
+//   - anyPass: job is ready if any plugin passes;
+//   - tiered: the first tier having jobReadyFns decides, all plugins in it must pass.
+//
+// Job is ready if no plugin registers jobReadyFn. The plugin which vetoes the readiness is recorded
+// and reported by event when session is closed if it differs from the last vetoing plugin of job.
 func (ssn *Session) JobReady(obj interface{}) bool {
+	ready, vetoer := ssn.jobReady(obj)
+
+	if job, ok := obj.(*api.JobInfo); ok && ssn.jobReadyVetoes != nil {
+		if ready {
+			ssn.jobReadyVetoes[job.UID] = ""
+		} else {
+			ssn.jobReadyVetoes[job.UID] = vetoer
+			klog.V(4).Infof("Job <%s/%s> readiness is vetoed by plugin <%s> under policy <%s>",
+				job.Namespace, job.Name, vetoer, ssn.jobReadyPolicy)
+		}
+	}
+
+	return ready
+}
+
+// jobReady returns whether job is ready and the plugin vetoed it if not ready.
+func (ssn *Session) jobReady(obj interface{}) (bool, string) {
+	var vetoer string
+	var hasFound bool
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledJobReady) {
@@ -297,20 +429,44 @@ func (ssn *Session) JobReady(obj interface{}) bool {
 			if !found {
 				continue
 			}
+			hasFound = true
 
-			if !jrf(obj) {
-				return false
+			if jrf(obj) {
+				if ssn.jobReadyPolicy == AnyPass {
+					return true, ""
+				}
+				continue
+			}
+
+			if ssn.jobReadyPolicy != AnyPass {
+				return false, plugin.Name
 			}
+			if len(vetoer) == 0 {
+				vetoer = plugin.Name
+			}
+		}
+		// this tier registered function and all of them passed
+		if hasFound && ssn.jobReadyPolicy == Tiered {
+			return true, ""
 		}
 	}
 
-	return true
+	// under anyPass policy, no registered plugin passed
+	if hasFound && ssn.jobReadyPolicy == AnyPass {
+		return false, vetoer
+	}
+
+	return true, ""
 }
 
 // JobPipelined invoke pipelined function of the plugins
-// Check if job has get enough resource to run
+// Check if job has get enough resource to run, the votes are combined by jobPipelinedPolicy:
+//   - allMustPass: job is pipelined only if no plugin rejects;
+//   - anyPass: job is pipelined if any plugin permits, or all plugins abstain;
+//   - tiered: any plugin rejects, job is not pipelined; the first tier having plugin permitted
+//     decides job is pipelined.
 func (ssn *Session) JobPipelined(obj interface{}) bool {
-	var hasFound bool
+	var hasFound, hasRejected bool
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
 			if !isEnabled(plugin.EnabledJobPipelined) {
@@ -323,20 +479,26 @@ func (ssn *Session) JobPipelined(obj interface{}) bool {
 
 			res := jrf(obj)
 			if res < 0 {
-				return false
+				if ssn.jobPipelinedPolicy != AnyPass {
+					return false
+				}
+				hasRejected = true
 			}
 			if res > 0 {
+				if ssn.jobPipelinedPolicy == AnyPass {
+					return true
+				}
 				hasFound = true
 			}
 		}
 		// if plugin exists that votes permit, meanwhile other plugin votes abstention,
 		// permit job to be pipelined, do not check next tier
-		if hasFound {
+		if hasFound && ssn.jobPipelinedPolicy == Tiered {
 			return true
 		}
 	}
 
-	return true
+	return !hasRejected
 }
 
 // JobStarving invoke jobStarving function of the plugins
@@ -371,6 +533,9 @@ func (ssn *Session) JobStarving(obj interface{}) bool {
 func (ssn *Session) JobValid(obj interface{}) *api.ValidateResult {
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
+			if !isEnabledOr(plugin.EnabledJobValid, true) {
+				continue
+			}
 			jrf, found := ssn.jobValidFns[plugin.Name]
 			if !found {
 				continue
@@ -390,7 +555,7 @@ func (ssn *Session) JobEnqueueable(obj interface{}) bool {
 	var hasFound bool
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
-			if !isEnabled(plugin.EnabledJobEnqueued) {
+			if !isEnabledOr(plugin.EnabledJobEnqueueable, isEnabled(plugin.EnabledJobEnqueued)) {
 				continue
 			}
 			fn, found := ssn.jobEnqueueableFns[plugin.Name]
@@ -464,7 +629,8 @@ func (ssn *Session) VictimTasks(tasks []*api.TaskInfo) map[*api.TaskInfo]bool {
 				continue
 			}
 			for _, fn := range fns {
-				victimTasks := fn(tasks)
+				// plugins may select victims out of tasks, so the no-evict label is checked on the selected ones.
+				victimTasks := ssn.evictable(fn(tasks))
 				for _, victim := range victimTasks {
 					victimSet[victim] = true
 				}
@@ -477,6 +643,88 @@ func (ssn *Session) VictimTasks(tasks []*api.TaskInfo) map[*api.TaskInfo]bool {
 	return victimSet
 }
 
+// VetoVictims invoke victimVeto function of the plugins before victims are evicted,
+// it returns the victims which are not vetoed by any plugin.
+func (ssn *Session) VetoVictims(evictor *api.TaskInfo, victims []*api.TaskInfo) []*api.TaskInfo {
+	if len(victims) == 0 {
+		return victims
+	}
+
+	vetoed := map[api.TaskID]bool{}
+	for _, tier := range ssn.Tiers {
+		for _, plugin := range tier.Plugins {
+			if !isEnabled(plugin.EnabledVictimVeto) {
+				continue
+			}
+			vf, found := ssn.victimVetoFns[plugin.Name]
+			if !found {
+				continue
+			}
+			for _, victim := range vf(evictor, victims) {
+				if evictor != nil {
+					klog.V(3).Infof("Victim <%s/%s> of Task <%s/%s> is vetoed by plugin <%s>.",
+						victim.Namespace, victim.Name, evictor.Namespace, evictor.Name, plugin.Name)
+				} else {
+					klog.V(3).Infof("Victim <%s/%s> is vetoed by plugin <%s>.", victim.Namespace, victim.Name, plugin.Name)
+				}
+				vetoed[victim.UID] = true
+			}
+		}
+	}
+	if len(vetoed) == 0 {
+		return victims
+	}
+
+	var kept []*api.TaskInfo
+	for _, victim := range victims {
+		if !vetoed[victim.UID] {
+			kept = append(kept, victim)
+		}
+	}
+	return kept
+}
+
+// VetoSelectedVictims selects the victims to evict from candidates by selectFn, and vetoes only the selected ones,
+// so that the plugins vetoing victims, e.g. the extenders called over http, are called on the victims to be evicted
+// rather than on all candidates of each node considered. The victims vetoed are dropped from candidates and the
+// victims are selected again until none of them is vetoed; each victim is vetoed at most once.
+func (ssn *Session) VetoSelectedVictims(evictor *api.TaskInfo, candidates []*api.TaskInfo,
+	selectFn func(candidates []*api.TaskInfo) []*api.TaskInfo) []*api.TaskInfo {
+	kept := map[api.TaskID]bool{}
+	for {
+		selected := selectFn(candidates)
+		var unvetoed []*api.TaskInfo
+		for _, victim := range selected {
+			if !kept[victim.UID] {
+				unvetoed = append(unvetoed, victim)
+			}
+		}
+		if len(unvetoed) == 0 {
+			return selected
+		}
+		for _, victim := range ssn.VetoVictims(evictor, unvetoed) {
+			kept[victim.UID] = true
+		}
+
+		vetoed := map[api.TaskID]bool{}
+		for _, victim := range unvetoed {
+			if !kept[victim.UID] {
+				vetoed[victim.UID] = true
+			}
+		}
+		if len(vetoed) == 0 {
+			return selected
+		}
+		var remaining []*api.TaskInfo
+		for _, candidate := range candidates {
+			if !vetoed[candidate.UID] {
+				remaining = append(remaining, candidate)
+			}
+		}
+		candidates = remaining
+	}
+}
+
 // ReservedNodes invoke ReservedNodes function of the plugins
 func (ssn *Session) ReservedNodes() {
 	for _, tier := range ssn.Tiers {
@@ -613,6 +861,7 @@ func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) ([]*api.
 				continue
 			}
 			status, err := pfn(task, node)
+			ssn.predicateFailures.record(plugin.Name, task, node, status, err)
 			predicateStatus = append(predicateStatus, status...)
 			if err != nil {
 				return predicateStatus, err
@@ -626,8 +875,7 @@ func (ssn *Session) PredicateFn(task *api.TaskInfo, node *api.NodeInfo) ([]*api.
 func (ssn *Session) PrePredicateFn(task *api.TaskInfo) error {
 	for _, tier := range ssn.Tiers {
 		for _, plugin := range tier.Plugins {
-			// we use same option as predicates for they are
-			if !isEnabled(plugin.EnabledPredicate) {
+			if !isEnabledOr(plugin.EnabledPrePredicate, isEnabled(plugin.EnabledPredicate)) {
 				continue
 			}
 			pfn, found := ssn.prePredicateFns[plugin.Name]
@@ -686,6 +934,8 @@ func (ssn *Session) NodeOrderFn(task *api.TaskInfo, node *api.NodeInfo) (float64
 }
 
 // BatchNodeOrderFn invoke node order function of the plugins
+// For the plugins with score normalization, their node order scores are also calculated here
+// so that the scores could be normalized across all candidate nodes.
 func (ssn *Session) BatchNodeOrderFn(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
 	priorityScore := make(map[string]float64, len(nodes))
 	for _, tier := range ssn.Tiers {
@@ -693,15 +943,30 @@ func (ssn *Session) BatchNodeOrderFn(task *api.TaskInfo, nodes []*api.NodeInfo)
 			if !isEnabled(plugin.EnabledNodeOrder) {
 				continue
 			}
-			pfn, found := ssn.batchNodeOrderFns[plugin.Name]
-			if !found {
-				continue
+			normalizeFn := getScoreNormalizeFn(plugin.ScoreNormalization)
+			pluginScore := make(map[string]float64, len(nodes))
+			if pfn, found := ssn.batchNodeOrderFns[plugin.Name]; found {
+				score, err := pfn(task, nodes)
+				if err != nil {
+					return nil, err
+				}
+				for nodeName, score := range score {
+					pluginScore[nodeName] += score
+				}
 			}
-			score, err := pfn(task, nodes)
-			if err != nil {
-				return nil, err
+			if normalizeFn != nil {
+				if pfn, found := ssn.nodeOrderFns[plugin.Name]; found {
+					for _, node := range nodes {
+						score, err := pfn(task, node)
+						if err != nil {
+							return nil, err
+						}
+						pluginScore[node.Name] += score
+					}
+				}
+				normalizeFn(pluginScore)
 			}
-			for nodeName, score := range score {
+			for nodeName, score := range pluginScore {
 				priorityScore[nodeName] += score
 			}
 		}
@@ -713,6 +978,14 @@ func isEnabled(enabled *bool) bool {
 	return enabled != nil && *enabled
 }
 
+// isEnabledOr returns whether the function is enabled, following fallback if enabled is not set.
+func isEnabledOr(enabled *bool, fallback bool) bool {
+	if enabled == nil {
+		return fallback
+	}
+	return *enabled
+}
+
 // NodeOrderMapFn invoke node order function of the plugins
 func (ssn *Session) NodeOrderMapFn(task *api.TaskInfo, node *api.NodeInfo) (map[string]float64, float64, error) {
 	nodeScoreMap := map[string]float64{}
@@ -722,7 +995,8 @@ func (ssn *Session) NodeOrderMapFn(task *api.TaskInfo, node *api.NodeInfo) (map[
 			if !isEnabled(plugin.EnabledNodeOrder) {
 				continue
 			}
-			if pfn, found := ssn.nodeOrderFns[plugin.Name]; found {
+			// node order scores of the plugins with score normalization are calculated in BatchNodeOrderFn.
+			if pfn, found := ssn.nodeOrderFns[plugin.Name]; found && getScoreNormalizeFn(plugin.ScoreNormalization) == nil {
 				score, err := pfn(task, node)
 				if err != nil {
 					return nodeScoreMap, priorityScore, err
@@ -0,0 +1,312 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestJobDeferred(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	nightly := &api.ScheduleWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	daytime := &api.ScheduleWindow{Start: 9 * time.Hour, End: 17 * time.Hour}
+
+	ssn := &Session{
+		Queues: map[api.QueueID]*api.QueueInfo{
+			"default": {UID: "default"},
+			"nightly": {UID: "nightly", ScheduleWindow: nightly},
+			"later":   {UID: "later", NotBefore: &later},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		job      *api.JobInfo
+		expected bool
+	}{
+		{
+			name: "no window",
+			job:  &api.JobInfo{Queue: "default"},
+		},
+		{
+			name:     "job not before",
+			job:      &api.JobInfo{Queue: "default", NotBefore: &later},
+			expected: true,
+		},
+		{
+			name:     "queue default window",
+			job:      &api.JobInfo{Queue: "nightly"},
+			expected: true,
+		},
+		{
+			name: "job window overrides queue",
+			job:  &api.JobInfo{Queue: "nightly", ScheduleWindow: daytime},
+		},
+		{
+			name:     "queue default not before",
+			job:      &api.JobInfo{Queue: "later"},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		if deferred := ssn.JobDeferred(test.job, now); deferred != test.expected {
+			t.Errorf("case %s: expected %v, got %v", test.name, test.expected, deferred)
+		}
+	}
+}
+
+func TestGangSchedulingTimedOut(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ssn := &Session{
+		Queues: map[api.QueueID]*api.QueueInfo{
+			"default": {UID: "default"},
+			"batch":   {UID: "batch", GangSchedulingTimeout: time.Hour},
+		},
+	}
+	// newJob returns a job of queue enqueued for inqueue, whose podgroup of minMember 2 is in phase, with running tasks.
+	newJob := func(queue api.QueueID, inqueue, timeout time.Duration, phase scheduling.PodGroupPhase, running int) *api.JobInfo {
+		job := api.NewJobInfo("job")
+		job.Queue = queue
+		job.GangSchedulingTimeout = timeout
+		if inqueue != 0 {
+			job.InqueueTime = now.Add(-inqueue)
+		}
+		job.PodGroup = &api.PodGroup{PodGroup: scheduling.PodGroup{
+			Spec:   scheduling.PodGroupSpec{MinMember: 2},
+			Status: scheduling.PodGroupStatus{Phase: phase},
+		}}
+		for i := 0; i < running; i++ {
+			job.AddTaskInfo(api.NewTaskInfo(util.BuildPod("ns", fmt.Sprintf("p%d", i), "n1", v1.PodRunning,
+				util.BuildResourceList("1", "1Gi"), "pg", nil, nil)))
+		}
+		return job
+	}
+
+	tests := []struct {
+		name     string
+		job      *api.JobInfo
+		expected bool
+	}{
+		{
+			name: "no timeout",
+			job:  newJob("default", 24*time.Hour, 0, scheduling.PodGroupInqueue, 0),
+		},
+		{
+			name: "not enqueued",
+			job:  newJob("batch", 0, 0, scheduling.PodGroupPending, 0),
+		},
+		{
+			name:     "queue default timeout",
+			job:      newJob("batch", 2*time.Hour, 0, scheduling.PodGroupInqueue, 1),
+			expected: true,
+		},
+		{
+			name: "job timeout overrides queue",
+			job:  newJob("batch", 2*time.Hour, 3*time.Hour, scheduling.PodGroupInqueue, 0),
+		},
+		{
+			name: "within timeout",
+			job:  newJob("default", time.Minute, time.Hour, scheduling.PodGroupInqueue, 0),
+		},
+		{
+			name: "already running",
+			job:  newJob("batch", 2*time.Hour, 0, scheduling.PodGroupRunning, 2),
+		},
+		{
+			name: "gang placed before running",
+			job:  newJob("batch", 2*time.Hour, 0, scheduling.PodGroupInqueue, 2),
+		},
+	}
+
+	for _, test := range tests {
+		if timedOut := ssn.GangSchedulingTimedOut(test.job, now); timedOut != test.expected {
+			t.Errorf("case %s: expected %v, got %v", test.name, test.expected, timedOut)
+		}
+	}
+}
+
+func TestVetoVictims(t *testing.T) {
+	trueValue, falseValue := true, false
+	evictor := &api.TaskInfo{UID: "evictor", Namespace: "ns", Name: "evictor"}
+	victims := []*api.TaskInfo{
+		{UID: "v1", Namespace: "ns", Name: "v1"},
+		{UID: "v2", Namespace: "ns", Name: "v2"},
+		{UID: "v3", Namespace: "ns", Name: "v3"},
+	}
+	vetoOf := func(uids ...api.TaskID) api.VictimVetoFn {
+		return func(_ *api.TaskInfo, victims []*api.TaskInfo) []*api.TaskInfo {
+			var vetoed []*api.TaskInfo
+			for _, victim := range victims {
+				for _, uid := range uids {
+					if victim.UID == uid {
+						vetoed = append(vetoed, victim)
+					}
+				}
+			}
+			return vetoed
+		}
+	}
+
+	ssn := &Session{
+		Tiers: []conf.Tier{
+			{Plugins: []conf.PluginOption{{Name: "p1", EnabledVictimVeto: &trueValue}}},
+			{Plugins: []conf.PluginOption{
+				{Name: "p2", EnabledVictimVeto: &trueValue},
+				{Name: "p3", EnabledVictimVeto: &falseValue},
+			}},
+		},
+		victimVetoFns: map[string]api.VictimVetoFn{
+			"p1": vetoOf("v1"),
+			"p2": vetoOf("v3"),
+			"p3": vetoOf("v2"),
+		},
+	}
+
+	kept := ssn.VetoVictims(evictor, victims)
+	if len(kept) != 1 || kept[0].UID != "v2" {
+		t.Errorf("expected only victim v2 kept, got %v", kept)
+	}
+
+	// Only the victims selected are vetoed, each of them once, and the vetoed ones are replaced by other candidates.
+	var calls [][]api.TaskID
+	ssn.victimVetoFns = map[string]api.VictimVetoFn{
+		"p1": func(evictor *api.TaskInfo, victims []*api.TaskInfo) []*api.TaskInfo {
+			var uids []api.TaskID
+			for _, victim := range victims {
+				uids = append(uids, victim.UID)
+			}
+			calls = append(calls, uids)
+			return vetoOf("v1")(evictor, victims)
+		},
+	}
+	firstTwo := func(candidates []*api.TaskInfo) []*api.TaskInfo {
+		if len(candidates) > 2 {
+			return candidates[:2]
+		}
+		return candidates
+	}
+	selected := ssn.VetoSelectedVictims(evictor, victims, firstTwo)
+	if len(selected) != 2 || selected[0].UID != "v2" || selected[1].UID != "v3" {
+		t.Errorf("expected victims v2 and v3 selected, got %v", selected)
+	}
+	expectedCalls := [][]api.TaskID{{"v1", "v2"}, {"v3"}}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("expected veto calls %v, got %v", expectedCalls, calls)
+	}
+}
+
+func TestPreemptableNoEvictLabel(t *testing.T) {
+	trueValue := true
+	preemptor := &api.TaskInfo{UID: "preemptor", Namespace: "ns", Name: "preemptor"}
+	preemptees := []*api.TaskInfo{
+		{UID: "p1", Namespace: "ns", Name: "p1", Pod: &v1.Pod{}},
+		{UID: "p2", Namespace: "ns", Name: "p2", Pod: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"volcano.sh/no-evict": "true"}}}},
+	}
+	var seen []*api.TaskInfo
+	all := func(_ *api.TaskInfo, evictees []*api.TaskInfo) ([]*api.TaskInfo, int) {
+		seen = evictees
+		return evictees, 1
+	}
+
+	ssn := &Session{
+		Tiers: []conf.Tier{
+			{Plugins: []conf.PluginOption{{Name: "p", EnabledPreemptable: &trueValue, EnabledReclaimable: &trueValue}}},
+		},
+		preemptableFns: map[string]api.EvictableFn{"p": all},
+		reclaimableFns: map[string]api.EvictableFn{"p": all},
+		noEvictLabel:   "volcano.sh/no-evict",
+	}
+
+	if victims := ssn.Preemptable(preemptor, preemptees); len(victims) != 1 || victims[0].UID != "p1" {
+		t.Errorf("expected only p1 preemptable, got %v", victims)
+	}
+	if len(seen) != 1 || seen[0].UID != "p1" {
+		t.Errorf("expected plugins only see p1, got %v", seen)
+	}
+	if victims := ssn.Reclaimable(preemptor, preemptees); len(victims) != 1 || victims[0].UID != "p1" {
+		t.Errorf("expected only p1 reclaimable, got %v", victims)
+	}
+	if victims := ssn.Preemptable(preemptor, preemptees[1:]); len(victims) != 0 {
+		t.Errorf("expected no victims, got %v", victims)
+	}
+}
+
+func TestInPreemptionDomain(t *testing.T) {
+	nodeIn := func(name, zone string) *api.NodeInfo {
+		return &api.NodeInfo{Name: name, Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: map[string]string{"topology.kubernetes.io/zone": zone}}}}
+	}
+	ssn := &Session{
+		Nodes: map[string]*api.NodeInfo{
+			"a1": nodeIn("a1", "a"),
+			"a2": nodeIn("a2", "a"),
+			"b1": nodeIn("b1", "b"),
+		},
+		domainLabel: "topology.kubernetes.io/zone",
+	}
+	pending := &api.JobInfo{UID: "j1", Tasks: map[api.TaskID]*api.TaskInfo{
+		"t1": {UID: "t1", Job: "j1", TransactionContext: api.TransactionContext{Status: api.Pending}},
+	}}
+	pipelined := &api.JobInfo{UID: "j2", Tasks: map[api.TaskID]*api.TaskInfo{
+		"t1": {UID: "t1", Job: "j2", TransactionContext: api.TransactionContext{Status: api.Pipelined, NodeName: "a1"}},
+		"t2": {UID: "t2", Job: "j2", TransactionContext: api.TransactionContext{Status: api.Pending}},
+	}}
+
+	if !ssn.InPreemptionDomain(pending, ssn.Nodes["b1"]) {
+		t.Errorf("expected any node in the preemption domain of job with no task on nodes")
+	}
+	if !ssn.InPreemptionDomain(pipelined, ssn.Nodes["a2"]) {
+		t.Errorf("expected a2 in the preemption domain of job pipelined to a1")
+	}
+	if ssn.InPreemptionDomain(pipelined, ssn.Nodes["b1"]) {
+		t.Errorf("expected b1 out of the preemption domain of job pipelined to a1")
+	}
+
+	t2 := pipelined.Tasks["t2"]
+	t2.Status, t2.NodeName = api.Pipelined, "b1"
+	if err := ssn.fireAllocateEvent(t2, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ssn.InPreemptionDomain(pipelined, ssn.Nodes["b1"]) {
+		t.Errorf("expected b1 in the preemption domain of job pipelined to b1")
+	}
+	t1 := pipelined.Tasks["t1"]
+	ssn.fireDeallocateEvent(t1)
+	t1.Status, t1.NodeName = api.Pending, ""
+	if ssn.InPreemptionDomain(pipelined, ssn.Nodes["a2"]) {
+		t.Errorf("expected a2 out of the preemption domain of job unpipelined from a1")
+	}
+	ssn.domainLabel = ""
+	if !ssn.InPreemptionDomain(pipelined, ssn.Nodes["b1"]) {
+		t.Errorf("expected any node in the preemption domain without domain label")
+	}
+}
@@ -79,13 +79,7 @@ func (s *Statement) Evict(reclaimee *api.TaskInfo, reason string) error {
 		}
 	}
 
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.DeallocateFunc != nil {
-			eh.DeallocateFunc(&Event{
-				Task: reclaimee,
-			})
-		}
-	}
+	s.ssn.fireDeallocateEvent(reclaimee)
 
 	s.operations = append(s.operations, operation{
 		name:   Evict,
@@ -104,6 +98,7 @@ func (s *Statement) evict(reclaimee *api.TaskInfo, reason string) error {
 		return err
 	}
 
+	s.ssn.statistics.tasksEvicted++
 	return nil
 }
 
@@ -130,13 +125,7 @@ func (s *Statement) unevict(reclaimee *api.TaskInfo) error {
 		}
 	}
 
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.AllocateFunc != nil {
-			eh.AllocateFunc(&Event{
-				Task: reclaimee,
-			})
-		}
-	}
+	s.ssn.fireRestoreEvent(reclaimee)
 
 	return nil
 }
@@ -168,12 +157,11 @@ func (s *Statement) Pipeline(task *api.TaskInfo, hostname string) error {
 			hostname, s.ssn.UID)
 	}
 
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.AllocateFunc != nil {
-			eh.AllocateFunc(&Event{
-				Task: task,
-			})
-		}
+	if err := s.ssn.fireAllocateEvent(task, true); err != nil {
+		klog.Errorf("Failed to pipeline task <%v/%v> to node <%v> in Session <%v>: %v",
+			task.Namespace, task.Name, hostname, s.ssn.UID, err)
+		s.ssn.unassign(task)
+		return err
 	}
 
 	s.operations = append(s.operations, operation{
@@ -185,6 +173,7 @@ func (s *Statement) Pipeline(task *api.TaskInfo, hostname string) error {
 }
 
 func (s *Statement) pipeline(task *api.TaskInfo) {
+	s.ssn.statistics.tasksPipelined++
 }
 
 func (s *Statement) unpipeline(task *api.TaskInfo) error {
@@ -210,13 +199,7 @@ func (s *Statement) unpipeline(task *api.TaskInfo) error {
 			task.NodeName, s.ssn.UID)
 	}
 
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.DeallocateFunc != nil {
-			eh.DeallocateFunc(&Event{
-				Task: task,
-			})
-		}
-	}
+	s.ssn.fireDeallocateEvent(task)
 	task.NodeName = ""
 
 	return nil
@@ -272,12 +255,11 @@ func (s *Statement) Allocate(task *api.TaskInfo, nodeInfo *api.NodeInfo) (err er
 	}
 
 	// Callbacks
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.AllocateFunc != nil {
-			eh.AllocateFunc(&Event{
-				Task: task,
-			})
-		}
+	if err := s.ssn.fireAllocateEvent(task, false); err != nil {
+		klog.Errorf("Failed to allocate task <%v/%v> to node <%v> in Session <%v>: %v",
+			task.Namespace, task.Name, hostname, s.ssn.UID, err)
+		s.ssn.unassign(task)
+		return err
 	}
 
 	// Update status in session
@@ -307,7 +289,10 @@ func (s *Statement) allocate(task *api.TaskInfo) error {
 		return fmt.Errorf("failed to find job %s", task.Job)
 	}
 
-	metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	s.ssn.statistics.tasksBound++
+	if !metrics.SessionMetricsSuppressed() {
+		metrics.UpdateTaskScheduleDuration(metrics.Duration(task.Pod.CreationTimestamp.Time))
+	}
 	return nil
 }
 
@@ -335,13 +320,7 @@ func (s *Statement) unallocate(task *api.TaskInfo) error {
 		}
 	}
 
-	for _, eh := range s.ssn.eventHandlers {
-		if eh.DeallocateFunc != nil {
-			eh.DeallocateFunc(&Event{
-				Task: task,
-			})
-		}
-	}
+	s.ssn.fireDeallocateEvent(task)
 	task.NodeName = ""
 
 	return nil
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// sessionStatistics collects what happened in a scheduling session, it is
+// summarized as a single log line and metrics at session close.
+type sessionStatistics struct {
+	tasksBound     int
+	tasksPipelined int
+	tasksEvicted   int
+
+	actionDurations map[string]time.Duration
+}
+
+func newSessionStatistics() *sessionStatistics {
+	return &sessionStatistics{
+		actionDurations: map[string]time.Duration{},
+	}
+}
+
+// RecordActionDuration records how long the action took in this session.
+func (ssn *Session) RecordActionDuration(action string, duration time.Duration) {
+	ssn.statistics.actionDurations[action] += duration
+}
+
+// nodesFilteredByReason returns the number of nodes filtered out for each
+// reason, accumulated over the tasks of all jobs in the session.
+func (ssn *Session) nodesFilteredByReason() map[string]int {
+	reasons := map[string]int{}
+	for _, job := range ssn.Jobs {
+		for _, fitErrors := range job.NodesFitErrors {
+			for reason, count := range fitErrors.Reasons() {
+				reasons[reason] += count
+			}
+		}
+	}
+	return reasons
+}
+
+// nodeFilteredLabels maps the substrings of fit errors to the bounded reasons of metrics, the first one contained
+// in a fit error is taken.
+var nodeFilteredLabels = []struct {
+	substring string
+	reason    string
+}{
+	{"usage", metrics.NodeFilteredUsage},
+	{"volume", metrics.NodeFilteredVolumes},
+	{"pvc", metrics.NodeFilteredVolumes},
+	{"nsufficient", metrics.NodeFilteredResources},
+	{"resource fit", metrics.NodeFilteredResources},
+	{"pod number", metrics.NodeFilteredResources},
+	{"free storage", metrics.NodeFilteredResources},
+	{"taint", metrics.NodeFilteredTaints},
+	{"affinity", metrics.NodeFilteredAffinity},
+	{"selector", metrics.NodeFilteredAffinity},
+	{"didn't match", metrics.NodeFilteredAffinity},
+	{"port", metrics.NodeFilteredPorts},
+	{"unschedulable", metrics.NodeFilteredUnschedulable},
+	{"not ready", metrics.NodeFilteredUnschedulable},
+	{"reserv", metrics.NodeFilteredReserved},
+	{"exclusive", metrics.NodeFilteredReserved},
+	{"excluded", metrics.NodeFilteredReserved},
+}
+
+// nodesFilteredByLabel sums the nodes filtered out by the bounded reasons of metrics, as the fit errors may name
+// resources, taints or nodes.
+func nodesFilteredByLabel(nodesFiltered map[string]int) map[string]int {
+	labels := map[string]int{}
+	for reason, count := range nodesFiltered {
+		label := metrics.NodeFilteredOther
+		for _, filtered := range nodeFilteredLabels {
+			if strings.Contains(reason, filtered.substring) {
+				label = filtered.reason
+				break
+			}
+		}
+		labels[label] += count
+	}
+	return labels
+}
+
+// recordSessionSummary emits the summary of the session as a log line and metrics.
+func recordSessionSummary(ssn *Session) {
+	stat := ssn.statistics
+	nodesFiltered := ssn.nodesFilteredByReason()
+
+	metrics.UpdateSessionSummary(len(ssn.Jobs), map[string]int{
+		metrics.TaskBound:     stat.tasksBound,
+		metrics.TaskPipelined: stat.tasksPipelined,
+		metrics.TaskEvicted:   stat.tasksEvicted,
+	}, nodesFilteredByLabel(nodesFiltered))
+
+	klog.V(3).InfoS("Session summary", "session", ssn.UID,
+		"jobs", len(ssn.Jobs), "nodes", len(ssn.Nodes),
+		"tasksBound", stat.tasksBound, "tasksPipelined", stat.tasksPipelined, "tasksEvicted", stat.tasksEvicted,
+		"nodesFiltered", nodesFiltered, "actionDurations", stat.actionDurations)
+}
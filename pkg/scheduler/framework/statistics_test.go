@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+func TestSessionStatistics(t *testing.T) {
+	ssn := &Session{
+		Jobs:       map[api.JobID]*api.JobInfo{},
+		statistics: newSessionStatistics(),
+	}
+
+	ssn.RecordActionDuration("allocate", time.Second)
+	ssn.RecordActionDuration("backfill", time.Millisecond)
+	ssn.RecordActionDuration("allocate", time.Second)
+	expectedDurations := map[string]time.Duration{
+		"allocate": 2 * time.Second,
+		"backfill": time.Millisecond,
+	}
+	if !reflect.DeepEqual(ssn.statistics.actionDurations, expectedDurations) {
+		t.Errorf("expected action durations %v, got %v", expectedDurations, ssn.statistics.actionDurations)
+	}
+
+	for i, nodes := range [][]string{{"n1", "n2"}, {"n1"}} {
+		job := &api.JobInfo{
+			UID:            api.JobID(rune('a' + i)),
+			NodesFitErrors: map[api.TaskID]*api.FitErrors{},
+		}
+		fitErrors := api.NewFitErrors()
+		for _, node := range nodes {
+			fitErrors.SetNodeError(node, errors.New("node(s) had untolerated taint"))
+		}
+		job.NodesFitErrors["task"] = fitErrors
+		ssn.Jobs[job.UID] = job
+	}
+	expectedReasons := map[string]int{"node(s) had untolerated taint": 3}
+	if reasons := ssn.nodesFilteredByReason(); !reflect.DeepEqual(reasons, expectedReasons) {
+		t.Errorf("expected nodes filtered %v, got %v", expectedReasons, reasons)
+	}
+
+	// The reasons of metrics are bounded, whatever the fit errors name.
+	labels := nodesFilteredByLabel(map[string]int{
+		"node(s) had untolerated taint {gpu: true}": 3,
+		"Insufficient cpu":                          1,
+		"Insufficient nvidia.com/gpu":               2,
+		"node(s) resource fit failed":               1,
+		"node(s) had volume node affinity conflict": 1,
+		"node n1 is magic":                          4,
+	})
+	expectedLabels := map[string]int{
+		metrics.NodeFilteredTaints:    3,
+		metrics.NodeFilteredResources: 4,
+		metrics.NodeFilteredVolumes:   1,
+		metrics.NodeFilteredOther:     4,
+	}
+	if !reflect.DeepEqual(labels, expectedLabels) {
+		t.Errorf("expected nodes filtered by label %v, got %v", expectedLabels, labels)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// The extension points of plugins measured by pluginLatency.
+const (
+	extensionPointPrePredicate   = "prePredicate"
+	extensionPointPredicate      = "predicate"
+	extensionPointNodeOrder      = "nodeOrder"
+	extensionPointBatchNodeOrder = "batchNodeOrder"
+	extensionPointNodeMap        = "nodeMap"
+	extensionPointNodeReduce     = "nodeReduce"
+	extensionPointBestNode       = "bestNode"
+
+	// sessionSpan is the name of the root span of a session.
+	sessionSpan = "session"
+)
+
+// Tracer traces the scheduling sessions, e.g. by exporting them as OpenTelemetry spans. The root span of a session
+// has a child span for OnSessionOpen, one for each action and one for OnSessionClose. The spans of OnSessionOpen and
+// OnSessionClose have a child span for each plugin, and the span of an action has a child span for each filter and
+// score function of plugins called in the action, e.g. `predicates.predicate`, with the number of calls and their
+// total duration as attributes, instead of one span for each call.
+type Tracer interface {
+	// StartSpan starts a span of name as a child of parent, or the root span of a session if parent is nil.
+	StartSpan(parent Span, name string) Span
+}
+
+// Span is a span started by Tracer.
+type Span interface {
+	// SetAttribute sets the attribute of span.
+	SetAttribute(key string, value interface{})
+	// End ends the span.
+	End()
+}
+
+var (
+	tracerMutex sync.Mutex
+	tracer      Tracer
+)
+
+// RegisterTracer registers the tracer of the sessions opened after it, nil disables tracing.
+func RegisterTracer(t Tracer) {
+	tracerMutex.Lock()
+	defer tracerMutex.Unlock()
+	tracer = t
+}
+
+func getTracer() Tracer {
+	tracerMutex.Lock()
+	defer tracerMutex.Unlock()
+	return tracer
+}
+
+// startSpan starts a child span of parent if the session is traced, nil otherwise.
+func (ssn *Session) startSpan(parent Span, name string) Span {
+	if ssn.tracer == nil {
+		return nil
+	}
+	return ssn.tracer.StartSpan(parent, name)
+}
+
+// endSpan ends the span if it is started.
+func endSpan(span Span) {
+	if span != nil {
+		span.End()
+	}
+}
+
+type latencyKey struct {
+	action         string
+	plugin         string
+	extensionPoint string
+}
+
+// latencyStats is the number of calls and their total duration in nanoseconds, updated atomically.
+type latencyStats struct {
+	calls       int64
+	nanoseconds int64
+}
+
+// pluginLatency measures the latency of the filter and score functions of plugins, observed by the
+// plugin_extension_point_latency_microseconds histogram if enabled, and summed up by action for the spans of the
+// session if it is traced. All methods are no-op on nil pluginLatency.
+type pluginLatency struct {
+	histograms bool
+	traced     bool
+	// action is the name of current action.
+	action atomic.Value
+	// stats are the latencyStats of the functions called in current action keyed by latencyKey.
+	stats sync.Map
+}
+
+// newPluginLatency returns the pluginLatency of the session, nil if neither histograms nor tracing are enabled.
+func newPluginLatency(histograms, traced bool) *pluginLatency {
+	if !histograms && !traced {
+		return nil
+	}
+	pl := &pluginLatency{histograms: histograms, traced: traced}
+	pl.action.Store("")
+	return pl
+}
+
+// setAction sets the action the functions are called in.
+func (pl *pluginLatency) setAction(action string) {
+	if pl == nil {
+		return
+	}
+	pl.action.Store(action)
+}
+
+// observe records one call of the function of plugin at the extension point started at start.
+func (pl *pluginLatency) observe(plugin, extensionPoint string, start time.Time) {
+	duration := time.Since(start)
+	if pl.histograms {
+		metrics.UpdatePluginExtensionPointDuration(plugin, extensionPoint, duration)
+	}
+	if !pl.traced {
+		return
+	}
+	key := latencyKey{action: pl.action.Load().(string), plugin: plugin, extensionPoint: extensionPoint}
+	value, found := pl.stats.Load(key)
+	if !found {
+		value, _ = pl.stats.LoadOrStore(key, &latencyStats{})
+	}
+	stats := value.(*latencyStats)
+	atomic.AddInt64(&stats.calls, 1)
+	atomic.AddInt64(&stats.nanoseconds, int64(duration))
+}
+
+// endAction adds the spans of the functions called in the action as children of the span of action, and clears
+// their stats.
+func (pl *pluginLatency) endAction(ssn *Session, action string, actionSpan Span) {
+	if pl == nil || !pl.traced {
+		return
+	}
+	var keys []latencyKey
+	pl.stats.Range(func(k, _ interface{}) bool {
+		if key := k.(latencyKey); key.action == action {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].plugin != keys[j].plugin {
+			return keys[i].plugin < keys[j].plugin
+		}
+		return keys[i].extensionPoint < keys[j].extensionPoint
+	})
+	for _, key := range keys {
+		value, _ := pl.stats.LoadAndDelete(key)
+		stats := value.(*latencyStats)
+		span := ssn.startSpan(actionSpan, key.plugin+"."+key.extensionPoint)
+		if span == nil {
+			continue
+		}
+		span.SetAttribute("calls", atomic.LoadInt64(&stats.calls))
+		span.SetAttribute("duration", time.Duration(atomic.LoadInt64(&stats.nanoseconds)).String())
+		span.End()
+	}
+}
+
+// The wrapXxx functions measure the latency of the filter and score functions of plugin.
+
+func (pl *pluginLatency) wrapPrePredicateFn(plugin string, fn api.PrePredicateFn) api.PrePredicateFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo) error {
+		defer pl.observe(plugin, extensionPointPrePredicate, time.Now())
+		return fn(task)
+	}
+}
+
+func (pl *pluginLatency) wrapPredicateFn(plugin string, fn api.PredicateFn) api.PredicateFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		defer pl.observe(plugin, extensionPointPredicate, time.Now())
+		return fn(task, node)
+	}
+}
+
+func (pl *pluginLatency) wrapNodeOrderFn(plugin string, fn api.NodeOrderFn) api.NodeOrderFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		defer pl.observe(plugin, extensionPointNodeOrder, time.Now())
+		return fn(task, node)
+	}
+}
+
+func (pl *pluginLatency) wrapBatchNodeOrderFn(plugin string, fn api.BatchNodeOrderFn) api.BatchNodeOrderFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
+		defer pl.observe(plugin, extensionPointBatchNodeOrder, time.Now())
+		return fn(task, nodes)
+	}
+}
+
+func (pl *pluginLatency) wrapNodeMapFn(plugin string, fn api.NodeMapFn) api.NodeMapFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		defer pl.observe(plugin, extensionPointNodeMap, time.Now())
+		return fn(task, node)
+	}
+}
+
+func (pl *pluginLatency) wrapNodeReduceFn(plugin string, fn api.NodeReduceFn) api.NodeReduceFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, scores k8sframework.NodeScoreList) error {
+		defer pl.observe(plugin, extensionPointNodeReduce, time.Now())
+		return fn(task, scores)
+	}
+}
+
+func (pl *pluginLatency) wrapBestNodeFn(plugin string, fn api.BestNodeFn) api.BestNodeFn {
+	if pl == nil {
+		return fn
+	}
+	return func(task *api.TaskInfo, scores map[float64][]*api.NodeInfo) *api.NodeInfo {
+		defer pl.observe(plugin, extensionPointBestNode, time.Now())
+		return fn(task, scores)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the sessions.
+const tracerName = "volcano.sh/volcano/pkg/scheduler/framework"
+
+// otelTracer is the Tracer exporting the spans of sessions to OpenTelemetry.
+type otelTracer struct {
+	tracer oteltrace.Tracer
+}
+
+// otelSpan is the Span of otelTracer, ctx carries the span to its children.
+type otelSpan struct {
+	ctx  context.Context
+	span oteltrace.Span
+}
+
+// NewOpenTelemetryTracer returns the Tracer starting the spans of sessions by the tracers of provider.
+func NewOpenTelemetryTracer(provider oteltrace.TracerProvider) Tracer {
+	return &otelTracer{tracer: provider.Tracer(tracerName)}
+}
+
+func (ot *otelTracer) StartSpan(parent Span, name string) Span {
+	ctx := context.Background()
+	if parent, ok := parent.(*otelSpan); ok {
+		ctx = parent.ctx
+	}
+	ctx, span := ot.tracer.Start(ctx, name)
+	return &otelSpan{ctx: ctx, span: span}
+}
+
+func (s *otelSpan) SetAttribute(key string, value interface{}) {
+	s.span.SetAttributes(attribute.Any(key, value))
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// recordingTracer records the spans ended by their path from the root span.
+type recordingTracer struct {
+	sync.Mutex
+	ended []string
+	calls map[string]int64
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	path   string
+}
+
+func (rt *recordingTracer) StartSpan(parent Span, name string) Span {
+	path := name
+	if parent != nil {
+		path = parent.(*recordingSpan).path + "/" + name
+	}
+	return &recordingSpan{tracer: rt, path: path}
+}
+
+func (rs *recordingSpan) SetAttribute(key string, value interface{}) {
+	if key == "calls" {
+		rs.tracer.Lock()
+		defer rs.tracer.Unlock()
+		rs.tracer.calls[rs.path] = value.(int64)
+	}
+}
+
+func (rs *recordingSpan) End() {
+	rs.tracer.Lock()
+	defer rs.tracer.Unlock()
+	rs.tracer.ended = append(rs.tracer.ended, rs.path)
+}
+
+// fakeAction calls the fn in the action.
+type fakeAction struct {
+	name string
+	fn   func()
+}
+
+func (fa *fakeAction) Name() string { return fa.name }
+
+func (fa *fakeAction) Initialize() {}
+
+func (fa *fakeAction) Execute(ssn *Session) { fa.fn() }
+
+func (fa *fakeAction) UnInitialize() {}
+
+func TestTraceAction(t *testing.T) {
+	tracer := &recordingTracer{calls: map[string]int64{}}
+	ssn := &Session{tracer: tracer, latency: newPluginLatency(false, true)}
+	ssn.span = ssn.startSpan(nil, sessionSpan)
+
+	predicate := ssn.latency.wrapPredicateFn("usage", func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		return nil, nil
+	})
+	nodeOrder := ssn.latency.wrapNodeOrderFn("binpack", func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		return 0, nil
+	})
+	ssn.ExecuteAction(&fakeAction{name: "allocate", fn: func() {
+		for i := 0; i < 3; i++ {
+			predicate(nil, nil)
+		}
+		nodeOrder(nil, nil)
+	}})
+	ssn.ExecuteAction(&fakeAction{name: "backfill", fn: func() {}})
+	endSpan(ssn.span)
+
+	expected := []string{
+		"session/allocate/binpack.nodeOrder",
+		"session/allocate/usage.predicate",
+		"session/allocate",
+		"session/backfill",
+		"session",
+	}
+	if !reflect.DeepEqual(tracer.ended, expected) {
+		t.Errorf("expected spans %v, got %v", expected, tracer.ended)
+	}
+	expectedCalls := map[string]int64{"session/allocate/binpack.nodeOrder": 1, "session/allocate/usage.predicate": 3}
+	if !reflect.DeepEqual(tracer.calls, expectedCalls) {
+		t.Errorf("expected calls %v, got %v", expectedCalls, tracer.calls)
+	}
+}
+
+func TestPluginLatencyDisabled(t *testing.T) {
+	if pl := newPluginLatency(false, false); pl != nil {
+		t.Fatalf("expected no plugin latency when neither histograms nor tracing are enabled")
+	}
+	var pl *pluginLatency
+	if fn := pl.wrapPredicateFn("usage", nil); fn != nil {
+		t.Errorf("expected fn not wrapped when plugin latency disabled")
+	}
+	ssn := &Session{}
+	if span := ssn.startSpan(nil, sessionSpan); span != nil {
+		t.Errorf("expected no span when session is not traced")
+	}
+}
+
+func TestOpenTelemetryTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := NewOpenTelemetryTracer(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	root := tracer.StartSpan(nil, sessionSpan)
+	action := tracer.StartSpan(root, "allocate")
+	action.SetAttribute("calls", int64(3))
+	action.End()
+	root.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans exported, got %d", len(spans))
+	}
+	actionSpan, rootSpan := spans[0], spans[1]
+	if rootSpan.Name != sessionSpan || rootSpan.Parent.IsValid() {
+		t.Errorf("expected root span %s without parent, got %s with parent %v", sessionSpan, rootSpan.Name, rootSpan.Parent)
+	}
+	if actionSpan.Name != "allocate" || actionSpan.Parent.SpanID() != rootSpan.SpanContext.SpanID() {
+		t.Errorf("expected span allocate child of the root span, got %s with parent %v", actionSpan.Name, actionSpan.Parent)
+	}
+	if len(actionSpan.Attributes) != 1 || actionSpan.Attributes[0].Key != "calls" || actionSpan.Attributes[0].Value.AsInt64() != 3 {
+		t.Errorf("expected attribute calls=3, got %v", actionSpan.Attributes)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// maintenanceWindow pauses actions and freezes queues in a recurring window.
+type maintenanceWindow struct {
+	name string
+	// window is the daily window, nil if the window is all day.
+	window *api.ScheduleWindow
+	// days are the days of week the window starts on.
+	days    [7]bool
+	actions []string
+	queues  []string
+}
+
+// maintenance is the actions paused and queues frozen by the maintenance windows active in a cycle.
+type maintenance struct {
+	actions map[string]struct{}
+	queues  map[string]struct{}
+}
+
+// unmarshalMaintenanceWindows parses the maintenance windows of scheduler configuration.
+func unmarshalMaintenanceWindows(confStr string) ([]*maintenanceWindow, error) {
+	schedulerConf := &conf.SchedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
+		return nil, err
+	}
+
+	var windows []*maintenanceWindow
+	names := map[string]struct{}{}
+	for _, mw := range schedulerConf.MaintenanceWindows {
+		if len(mw.Name) == 0 {
+			return nil, fmt.Errorf("name of maintenance window is empty")
+		}
+		if _, found := names[mw.Name]; found {
+			return nil, fmt.Errorf("duplicated maintenance window %s", mw.Name)
+		}
+		names[mw.Name] = struct{}{}
+
+		window, err := parseMaintenanceWindow(mw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %s: %v", mw.Name, err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseMaintenanceWindow(mw conf.MaintenanceWindow) (*maintenanceWindow, error) {
+	if len(mw.Actions) == 0 && len(mw.Queues) == 0 {
+		return nil, fmt.Errorf("neither actions nor queues are given")
+	}
+	for _, action := range mw.Actions {
+		if _, found := framework.GetAction(strings.TrimSpace(action)); !found {
+			return nil, fmt.Errorf("unknown action %s", action)
+		}
+	}
+
+	window := &maintenanceWindow{name: mw.Name}
+	if len(mw.Schedule) != 0 {
+		sw, err := api.ParseScheduleWindow(mw.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		window.window = sw
+	}
+	days, err := parseWeekdays(mw.Days)
+	if err != nil {
+		return nil, err
+	}
+	window.days = days
+	for _, action := range mw.Actions {
+		window.actions = append(window.actions, strings.TrimSpace(action))
+	}
+	window.queues = mw.Queues
+	return window, nil
+}
+
+// parseWeekdays parses the day of week field of cron, e.g. `*`, `1-5` or `sat,sun`, 0 and 7 are both Sunday.
+func parseWeekdays(value string) ([7]bool, error) {
+	var days [7]bool
+	value = strings.TrimSpace(value)
+	if len(value) == 0 || value == "*" {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+
+	for _, field := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(field), "-", 2)
+		first, err := parseWeekday(bounds[0])
+		if err != nil {
+			return days, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseWeekday(bounds[1]); err != nil {
+				return days, err
+			}
+		}
+		if last < first {
+			return days, fmt.Errorf("invalid range %q of days", field)
+		}
+		for day := first; day <= last; day++ {
+			days[day%7] = true
+		}
+	}
+	return days, nil
+}
+
+func parseWeekday(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if day, found := weekdayNames[value]; found {
+		return int(day), nil
+	}
+	day, err := strconv.Atoi(value)
+	if err != nil || day < 0 || day > 7 {
+		return 0, fmt.Errorf("invalid day of week %q", value)
+	}
+	return day, nil
+}
+
+// active checks whether t is inside the window, a window wrapping around midnight belongs to the day it starts on.
+func (mw *maintenanceWindow) active(t time.Time) bool {
+	if !mw.window.Contains(t) {
+		return false
+	}
+
+	t = t.UTC()
+	day := t.Weekday()
+	if mw.window != nil && mw.window.Start > mw.window.End {
+		offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+			time.Duration(t.Second())*time.Second
+		if offset < mw.window.End {
+			day = (day + 6) % 7
+		}
+	}
+	return mw.days[day]
+}
+
+// activeMaintenance returns the actions paused and queues frozen by the windows active at t.
+func activeMaintenance(windows []*maintenanceWindow, t time.Time) *maintenance {
+	m := &maintenance{actions: map[string]struct{}{}, queues: map[string]struct{}{}}
+	for _, mw := range windows {
+		if !mw.active(t) {
+			continue
+		}
+		for _, action := range mw.actions {
+			m.actions[action] = struct{}{}
+		}
+		for _, queue := range mw.queues {
+			m.queues[queue] = struct{}{}
+		}
+	}
+	return m
+}
+
+// actionPaused checks whether the action is paused by a maintenance window.
+func (m *maintenance) actionPaused(action string) bool {
+	if m == nil {
+		return false
+	}
+	_, found := m.actions[action]
+	return found
+}
+
+// freezeQueues pauses the scheduling of the queues frozen by a maintenance window in session.
+func (m *maintenance) freezeQueues(ssn *framework.Session) {
+	if m == nil {
+		return
+	}
+	for name := range m.queues {
+		if queue, found := ssn.Queues[api.QueueID(name)]; found {
+			queue.Paused = true
+		}
+	}
+}
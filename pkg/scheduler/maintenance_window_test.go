@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestUnmarshalMaintenanceWindows(t *testing.T) {
+	windows, err := unmarshalMaintenanceWindows(`
+actions: "allocate, preempt, reclaim"
+maintenanceWindows:
+- name: business-hours
+  schedule: "09:00-18:00"
+  days: "1-5"
+  actions: ["preempt", "reclaim"]
+- name: upgrade
+  schedule: "22:00-02:00"
+  days: "sat"
+  queues: ["research"]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 maintenance windows, got %d", len(windows))
+	}
+
+	// 2023-06-05 is Monday.
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		time    time.Time
+		actions map[string]struct{}
+		queues  map[string]struct{}
+	}{
+		{
+			name:    "business hours",
+			time:    monday.Add(10 * time.Hour),
+			actions: map[string]struct{}{"preempt": {}, "reclaim": {}},
+			queues:  map[string]struct{}{},
+		},
+		{
+			name:    "after business hours",
+			time:    monday.Add(18 * time.Hour),
+			actions: map[string]struct{}{},
+			queues:  map[string]struct{}{},
+		},
+		{
+			name:    "weekend",
+			time:    monday.Add(5*24*time.Hour + 10*time.Hour),
+			actions: map[string]struct{}{},
+			queues:  map[string]struct{}{},
+		},
+		{
+			name:    "upgrade window on saturday night",
+			time:    monday.Add(5*24*time.Hour + 23*time.Hour),
+			actions: map[string]struct{}{},
+			queues:  map[string]struct{}{"research": {}},
+		},
+		{
+			name:    "upgrade window after midnight belongs to saturday",
+			time:    monday.Add(6*24*time.Hour + time.Hour),
+			actions: map[string]struct{}{},
+			queues:  map[string]struct{}{"research": {}},
+		},
+		{
+			name:    "no upgrade window after friday midnight",
+			time:    monday.Add(5*24*time.Hour + time.Hour),
+			actions: map[string]struct{}{},
+			queues:  map[string]struct{}{},
+		},
+	}
+	for _, test := range tests {
+		m := activeMaintenance(windows, test.time)
+		if !reflect.DeepEqual(m.actions, test.actions) || !reflect.DeepEqual(m.queues, test.queues) {
+			t.Errorf("%s: expected actions %v and queues %v paused, got %v and %v",
+				test.name, test.actions, test.queues, m.actions, m.queues)
+		}
+	}
+
+	for _, invalid := range []string{`
+maintenanceWindows:
+- schedule: "09:00-18:00"
+  actions: ["preempt"]
+`, `
+maintenanceWindows:
+- name: w1
+  actions: ["preempt"]
+- name: w1
+  queues: ["q1"]
+`, `
+maintenanceWindows:
+- name: w1
+  schedule: "09:00-18:00"
+`, `
+maintenanceWindows:
+- name: w1
+  actions: ["drain"]
+`, `
+maintenanceWindows:
+- name: w1
+  schedule: "9am-6pm"
+  actions: ["preempt"]
+`, `
+maintenanceWindows:
+- name: w1
+  days: "5-1"
+  actions: ["preempt"]
+`, `
+maintenanceWindows:
+- name: w1
+  days: "8"
+  actions: ["preempt"]
+`} {
+		if _, err := unmarshalMaintenanceWindows(invalid); err == nil {
+			t.Errorf("expected error for invalid maintenance windows %s", invalid)
+		}
+	}
+}
+
+func TestRunSessionsInMaintenanceWindow(t *testing.T) {
+	framework.RegisterPluginBuilder("drf", drf.New)
+	defer framework.CleanupPluginBuilders()
+	options.ServerOpts = &options.ServerOption{
+		MinNodesToFind:             100,
+		MinPercentageOfNodesToFind: 5,
+		PercentageOfNodesToFind:    100,
+	}
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Binder:        &util.FakeBinder{Binds: map[string]string{}, Channel: make(chan string, 10)},
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+		Recorder:      record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), make(map[string]string)))
+	for _, queue := range []string{"q1", "q2"} {
+		schedulerCache.AddPod(util.BuildPod("ns", "p-"+queue, "", v1.PodPending, util.BuildResourceList("1", "1G"),
+			"pg-"+queue, make(map[string]string), make(map[string]string)))
+		schedulerCache.AddPodGroupV1beta1(&schedulingv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg-" + queue, Namespace: "ns"},
+			Spec:       schedulingv1.PodGroupSpec{Queue: queue, MinMember: 1},
+			Status:     schedulingv1.PodGroupStatus{Phase: schedulingv1.PodGroupInqueue},
+		})
+		schedulerCache.AddQueueV1beta1(&schedulingv1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: queue},
+			Spec:       schedulingv1.QueueSpec{Weight: 1},
+		})
+	}
+	tiers := []conf.Tier{{Plugins: []conf.PluginOption{{Name: "drf"}}}}
+
+	tests := []struct {
+		name     string
+		window   *maintenanceWindow
+		expected map[string]string
+	}{
+		{
+			name:     "no maintenance window",
+			expected: map[string]string{"ns/p-q1": "n1", "ns/p-q2": "n1"},
+		},
+		{
+			name:     "queue frozen",
+			window:   &maintenanceWindow{name: "w1", days: [7]bool{true, true, true, true, true, true, true}, queues: []string{"q1"}},
+			expected: map[string]string{"ns/p-q2": "n1"},
+		},
+		{
+			name:     "action paused",
+			window:   &maintenanceWindow{name: "w1", days: [7]bool{true, true, true, true, true, true, true}, actions: []string{"allocate"}},
+			expected: map[string]string{},
+		},
+		{
+			name:     "window not active",
+			window:   &maintenanceWindow{name: "w1", queues: []string{"q1"}},
+			expected: map[string]string{"ns/p-q1": "n1", "ns/p-q2": "n1"},
+		},
+	}
+	for _, test := range tests {
+		var windows []*maintenanceWindow
+		if test.window != nil {
+			windows = append(windows, test.window)
+		}
+		dryRunCache := newDryRunCache(schedulerCache)
+		runSessions(dryRunCache, []framework.Action{allocate.New()}, tiers, nil, nil, windows, nil, nil)
+		if binds := dryRunCache.decisions[bindDecision]; !reflect.DeepEqual(binds, test.expected) {
+			t.Errorf("%s: expected binds %v, got %v", test.name, test.expected, binds)
+		}
+	}
+}
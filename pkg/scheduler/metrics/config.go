@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+)
+
+var (
+	configDryRuns = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "config_dry_runs_total",
+			Help:      "Number of dry runs of reloaded scheduler configurations",
+		},
+	)
+
+	configDryRunDiffs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "config_dry_run_decision_diffs",
+			Help:      "Number of decisions of the last dry run of the reloaded scheduler configuration differing from the active one",
+		}, []string{"decision"},
+	)
+
+	deprecatedPluginArguments = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "deprecated_plugin_arguments_total",
+			Help:      "Number of deprecated arguments of plugins converted into their latest apiVersion when loading scheduler configurations",
+		}, []string{"plugin"},
+	)
+)
+
+// sessionMetricsSuppressed counts the dry-run sessions in progress, the metrics of sessions are not updated in them.
+var sessionMetricsSuppressed int32
+
+// SuppressSessionMetrics stops updating the metrics of sessions, e.g. in the dry runs of scheduler configuration whose
+// decisions are not applied, and returns the function resuming them. The sessions are serialized, so no metric of the
+// sessions scheduling the cluster is suppressed.
+func SuppressSessionMetrics() (resume func()) {
+	atomic.AddInt32(&sessionMetricsSuppressed, 1)
+	return func() {
+		atomic.AddInt32(&sessionMetricsSuppressed, -1)
+	}
+}
+
+// SessionMetricsSuppressed returns whether the metrics of sessions are suppressed.
+func SessionMetricsSuppressed() bool {
+	return atomic.LoadInt32(&sessionMetricsSuppressed) > 0
+}
+
+// RegisterDeprecatedPluginArguments records the deprecated arguments of plugin converted when loading a scheduler configuration
+func RegisterDeprecatedPluginArguments(plugin string, count int) {
+	deprecatedPluginArguments.WithLabelValues(plugin).Add(float64(count))
+}
+
+// DeprecatedPluginArguments returns the counter of the deprecated arguments of plugin
+func DeprecatedPluginArguments(plugin string) prometheus.Counter {
+	return deprecatedPluginArguments.WithLabelValues(plugin)
+}
+
+// UpdateConfigDryRunDiffs records the number of differing decisions of each kind in a dry run of scheduler configuration
+func UpdateConfigDryRunDiffs(diffs map[string]int) {
+	configDryRuns.Inc()
+	for decision, count := range diffs {
+		configDryRunDiffs.WithLabelValues(decision).Set(float64(count))
+	}
+}
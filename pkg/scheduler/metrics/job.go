@@ -41,11 +41,17 @@ var (
 
 // UpdateJobShare records share for one job
 func UpdateJobShare(jobNs, jobID string, share float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	jobShare.WithLabelValues(jobNs, jobID).Set(share)
 }
 
 // RegisterJobRetries total number of job retries.
 func RegisterJobRetries(jobID string) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	jobRetryCount.WithLabelValues(jobID).Inc()
 }
 
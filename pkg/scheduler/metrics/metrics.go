@@ -89,6 +89,15 @@ var (
 		}, []string{"plugin", "OnSession"},
 	)
 
+	pluginExtensionPointLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "plugin_extension_point_latency_microseconds",
+			Help:      "Latency of one call of the filter or score function of plugin in microseconds",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		}, []string{"plugin", "extension_point"},
+	)
+
 	actionSchedulingLatency = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Subsystem: VolcanoNamespace,
@@ -150,11 +159,25 @@ var (
 
 // UpdatePluginDuration updates latency for every plugin
 func UpdatePluginDuration(pluginName, onSessionStatus string, duration time.Duration) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	pluginSchedulingLatency.WithLabelValues(pluginName, onSessionStatus).Observe(DurationInMicroseconds(duration))
 }
 
+// UpdatePluginExtensionPointDuration updates latency of one call of the function of plugin at the extension point
+func UpdatePluginExtensionPointDuration(pluginName, extensionPoint string, duration time.Duration) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	pluginExtensionPointLatency.WithLabelValues(pluginName, extensionPoint).Observe(DurationInMicroseconds(duration))
+}
+
 // UpdateActionDuration updates latency for every action
 func UpdateActionDuration(actionName string, duration time.Duration) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	actionSchedulingLatency.WithLabelValues(actionName).Observe(DurationInMicroseconds(duration))
 }
 
@@ -165,6 +188,9 @@ func UpdateE2eDuration(duration time.Duration) {
 
 // UpdateE2eSchedulingDurationByJob updates entire end to end scheduling duration
 func UpdateE2eSchedulingDurationByJob(jobName string, queue string, namespace string, duration time.Duration) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	e2eJobSchedulingDuration.WithLabelValues(jobName, queue, namespace).Set(DurationInMilliseconds(duration))
 	e2eJobSchedulingLatency.Observe(DurationInMilliseconds(duration))
 }
@@ -176,6 +202,9 @@ func UpdateE2eSchedulingStartTimeByJob(jobName string, queue string, namespace s
 
 // UpdateE2eSchedulingLastTimeByJob updates the last time of scheduling
 func UpdateE2eSchedulingLastTimeByJob(jobName string, queue string, namespace string, t time.Time) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	e2eJobSchedulingLastTime.WithLabelValues(jobName, queue, namespace).Set(ConvertToUnix(t))
 }
 
@@ -186,26 +215,41 @@ func UpdateTaskScheduleDuration(duration time.Duration) {
 
 // UpdatePodScheduleStatus update pod schedule decision, could be Success, Failure, Error
 func UpdatePodScheduleStatus(label string, count int) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	scheduleAttempts.WithLabelValues(label).Add(float64(count))
 }
 
 // UpdatePreemptionVictimsCount updates count of preemption victims
 func UpdatePreemptionVictimsCount(victimsCount int) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	preemptionVictims.Set(float64(victimsCount))
 }
 
 // RegisterPreemptionAttempts records number of attempts for preemtion
 func RegisterPreemptionAttempts() {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	preemptionAttempts.Inc()
 }
 
 // UpdateUnscheduleTaskCount records total number of unscheduleable tasks
 func UpdateUnscheduleTaskCount(jobID string, taskCount int) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	unscheduleTaskCount.WithLabelValues(jobID).Set(float64(taskCount))
 }
 
 // UpdateUnscheduleJobCount records total number of unscheduleable jobs
 func UpdateUnscheduleJobCount(jobCount int) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	unscheduleJobCount.Set(float64(jobCount))
 }
 
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+)
+
+const (
+	// NodeCapacity is the capacity of node
+	NodeCapacity = "capacity"
+	// NodeAllocatable is the allocatable of node, i.e. the capacity without the resource reserved for system
+	NodeAllocatable = "allocatable"
+	// NodeOversubscribed is the allocatable of node with the oversubscription resource
+	NodeOversubscribed = "oversubscribed"
+	// NodeEffective is the oversubscribed of node without the resource kept from tasks by the scheduler
+	NodeEffective = "effective"
+)
+
+// The reasons of node_capacity_reduced_*, the reductions recorded for other reasons are exported as NodeReducedOther
+// so that the label is bounded.
+const (
+	// NodeReducedCordoned is the allocatable of a cordoned node
+	NodeReducedCordoned = "cordoned"
+	// NodeReducedUsage is the allocatable of a node filtered out for its usage
+	NodeReducedUsage = "usage"
+	// NodeReducedClusterReserve is the share of a node of the resource kept free in the cluster by clusterReserve.*
+	NodeReducedClusterReserve = "cluster_reserve"
+	// NodeReducedReservation is the share of a node of the resource kept by advance reservations for their queues
+	NodeReducedReservation = "reservation"
+	// NodeReducedQuarantine is the allocatable of a node quarantined for repeated bind failures
+	NodeReducedQuarantine = "quarantine"
+	// NodeReducedOther is the resource kept from tasks for the other reasons
+	NodeReducedOther = "other"
+)
+
+var (
+	nodeBindQuarantines = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_bind_quarantines_total",
+			Help:      "Number of times one node is quarantined for repeated bind failures",
+		}, []string{"node_name"},
+	)
+
+	nodeBindQuarantined = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_bind_quarantined",
+			Help:      "Whether one node is quarantined for repeated bind failures",
+		}, []string{"node_name"},
+	)
+
+	nodeSchedulableMilliCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_schedulable_milli_cpu",
+			Help:      "CPU of one node at each stage from capacity to the effective schedulable capacity by scheduling profile",
+		}, []string{"node_name", "profile", "stage"},
+	)
+
+	nodeSchedulableMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_schedulable_memory_bytes",
+			Help:      "Memory of one node at each stage from capacity to the effective schedulable capacity by scheduling profile",
+		}, []string{"node_name", "profile", "stage"},
+	)
+
+	nodeCapacityReducedMilliCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_capacity_reduced_milli_cpu",
+			Help:      "CPU of one node kept from tasks by reason in the latest scheduling session of the profile",
+		}, []string{"node_name", "profile", "reason"},
+	)
+
+	nodeCapacityReducedMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_capacity_reduced_memory_bytes",
+			Help:      "Memory of one node kept from tasks by reason in the latest scheduling session of the profile",
+		}, []string{"node_name", "profile", "reason"},
+	)
+)
+
+// nodeCapacityLabels are the labels of the schedulable capacity of nodes exported, by which the reductions of the
+// previous session of a profile and the metrics of a node deleted are cleared.
+var nodeCapacityLabels = struct {
+	sync.Mutex
+	// profiles are the profiles exported
+	profiles map[string]struct{}
+	// reasons are the reasons of reductions exported by profile and node
+	reasons map[string]map[string][]string
+}{profiles: map[string]struct{}{}, reasons: map[string]map[string][]string{}}
+
+// UpdateNodeSchedulable records the cpu and memory of one node at the stage in the session of the profile
+func UpdateNodeSchedulable(nodeName, profile, stage string, milliCPU, memory float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	nodeCapacityLabels.Lock()
+	nodeCapacityLabels.profiles[profile] = struct{}{}
+	nodeCapacityLabels.Unlock()
+	nodeSchedulableMilliCPU.WithLabelValues(nodeName, profile, stage).Set(milliCPU)
+	nodeSchedulableMemory.WithLabelValues(nodeName, profile, stage).Set(memory)
+}
+
+// UpdateNodeCapacityReduced records the cpu and memory of one node kept from tasks for the reason in the session
+// of the profile
+func UpdateNodeCapacityReduced(nodeName, profile, reason string, milliCPU, memory float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	nodeCapacityLabels.Lock()
+	if nodeCapacityLabels.reasons[profile] == nil {
+		nodeCapacityLabels.reasons[profile] = map[string][]string{}
+	}
+	nodeCapacityLabels.reasons[profile][nodeName] = append(nodeCapacityLabels.reasons[profile][nodeName], reason)
+	nodeCapacityLabels.Unlock()
+	nodeCapacityReducedMilliCPU.WithLabelValues(nodeName, profile, reason).Set(milliCPU)
+	nodeCapacityReducedMemory.WithLabelValues(nodeName, profile, reason).Set(memory)
+}
+
+// ResetNodeCapacityReduced clears the capacity of nodes kept from tasks recorded by the previous session of the
+// profile, the ones of the other profiles are kept
+func ResetNodeCapacityReduced(profile string) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	nodeCapacityLabels.Lock()
+	defer nodeCapacityLabels.Unlock()
+	for nodeName, reasons := range nodeCapacityLabels.reasons[profile] {
+		for _, reason := range reasons {
+			nodeCapacityReducedMilliCPU.DeleteLabelValues(nodeName, profile, reason)
+			nodeCapacityReducedMemory.DeleteLabelValues(nodeName, profile, reason)
+		}
+	}
+	delete(nodeCapacityLabels.reasons, profile)
+}
+
+// RegisterNodeBindQuarantine records one node is quarantined for repeated bind failures
+func RegisterNodeBindQuarantine(nodeName string) {
+	nodeBindQuarantines.WithLabelValues(nodeName).Inc()
+	nodeBindQuarantined.WithLabelValues(nodeName).Set(1)
+}
+
+// ReleaseNodeBindQuarantine records one node is released from quarantine
+func ReleaseNodeBindQuarantine(nodeName string) {
+	nodeBindQuarantined.WithLabelValues(nodeName).Set(0)
+}
+
+// DeleteNodeMetrics delete all metrics related to the node
+func DeleteNodeMetrics(nodeName string) {
+	nodeBindQuarantines.DeleteLabelValues(nodeName)
+	nodeBindQuarantined.DeleteLabelValues(nodeName)
+	nodeCapacityLabels.Lock()
+	defer nodeCapacityLabels.Unlock()
+	for profile := range nodeCapacityLabels.profiles {
+		for _, stage := range []string{NodeCapacity, NodeAllocatable, NodeOversubscribed, NodeEffective} {
+			nodeSchedulableMilliCPU.DeleteLabelValues(nodeName, profile, stage)
+			nodeSchedulableMemory.DeleteLabelValues(nodeName, profile, stage)
+		}
+		for _, reason := range nodeCapacityLabels.reasons[profile][nodeName] {
+			nodeCapacityReducedMilliCPU.DeleteLabelValues(nodeName, profile, reason)
+			nodeCapacityReducedMemory.DeleteLabelValues(nodeName, profile, reason)
+		}
+		delete(nodeCapacityLabels.reasons[profile], nodeName)
+	}
+}
@@ -125,38 +125,64 @@ var (
 			Help:      "The number of Unknown PodGroup in this queue",
 		}, []string{"queue_name"},
 	)
+
+	queueBudgetSpent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "queue_budget_spent",
+			Help:      "The cost of tasks in this queue in the current billing window",
+		}, []string{"queue_name"},
+	)
 )
 
 // UpdateQueueAllocated records allocated resources for one queue
 func UpdateQueueAllocated(queueName string, milliCPU, memory float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queueAllocatedMilliCPU.WithLabelValues(queueName).Set(milliCPU)
 	queueAllocatedMemory.WithLabelValues(queueName).Set(memory)
 }
 
 // UpdateQueueRequest records request resources for one queue
 func UpdateQueueRequest(queueName string, milliCPU, memory float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queueRequestMilliCPU.WithLabelValues(queueName).Set(milliCPU)
 	queueRequestMemory.WithLabelValues(queueName).Set(memory)
 }
 
 // UpdateQueueDeserved records deserved resources for one queue
 func UpdateQueueDeserved(queueName string, milliCPU, memory float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queueDeservedMilliCPU.WithLabelValues(queueName).Set(milliCPU)
 	queueDeservedMemory.WithLabelValues(queueName).Set(memory)
 }
 
 // UpdateQueueShare records share for one queue
 func UpdateQueueShare(queueName string, share float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queueShare.WithLabelValues(queueName).Set(share)
 }
 
 // UpdateQueueWeight records weight for one queue
 func UpdateQueueWeight(queueName string, weight int32) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queueWeight.WithLabelValues(queueName).Set(float64(weight))
 }
 
 // UpdateQueueOverused records if one queue is overused
 func UpdateQueueOverused(queueName string, overused bool) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	var value float64
 	if overused {
 		value = 1
@@ -168,24 +194,44 @@ func UpdateQueueOverused(queueName string, overused bool) {
 
 // UpdateQueuePodGroupInqueueCount records the number of Inqueue PodGroup in this queue
 func UpdateQueuePodGroupInqueueCount(queueName string, count int32) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queuePodGroupInqueue.WithLabelValues(queueName).Set(float64(count))
 }
 
 // UpdateQueuePodGroupPendingCount records the number of Pending PodGroup in this queue
 func UpdateQueuePodGroupPendingCount(queueName string, count int32) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queuePodGroupPending.WithLabelValues(queueName).Set(float64(count))
 }
 
 // UpdateQueuePodGroupRunningCount records the number of Running PodGroup in this queue
 func UpdateQueuePodGroupRunningCount(queueName string, count int32) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queuePodGroupRunning.WithLabelValues(queueName).Set(float64(count))
 }
 
 // UpdateQueuePodGroupUnknownCount records the number of Unknown PodGroup in this queue
 func UpdateQueuePodGroupUnknownCount(queueName string, count int32) {
+	if SessionMetricsSuppressed() {
+		return
+	}
 	queuePodGroupUnknown.WithLabelValues(queueName).Set(float64(count))
 }
 
+// UpdateQueueBudgetSpent records the cost of tasks in this queue in the current billing window
+func UpdateQueueBudgetSpent(queueName string, spent float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	queueBudgetSpent.WithLabelValues(queueName).Set(spent)
+}
+
 // DeleteQueueMetrics delete all metrics related to the queue
 func DeleteQueueMetrics(queueName string) {
 	queueAllocatedMilliCPU.DeleteLabelValues(queueName)
@@ -198,6 +244,7 @@ func DeleteQueueMetrics(queueName string) {
 	queueWeight.DeleteLabelValues(queueName)
 	queueOverused.DeleteLabelValues(queueName)
 	queuePodGroupInqueue.DeleteLabelValues(queueName)
+	queueBudgetSpent.DeleteLabelValues(queueName)
 	queuePodGroupPending.DeleteLabelValues(queueName)
 	queuePodGroupRunning.DeleteLabelValues(queueName)
 	queuePodGroupUnknown.DeleteLabelValues(queueName)
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+)
+
+const (
+	// TaskBound label
+	TaskBound = "bound"
+	// TaskPipelined label
+	TaskPipelined = "pipelined"
+	// TaskEvicted label
+	TaskEvicted = "evicted"
+)
+
+// The reasons of session_nodes_filtered, the fit errors of nodes are mapped to them so that the label is bounded.
+const (
+	// NodeFilteredUsage label
+	NodeFilteredUsage = "usage"
+	// NodeFilteredResources label
+	NodeFilteredResources = "resources"
+	// NodeFilteredVolumes label
+	NodeFilteredVolumes = "volumes"
+	// NodeFilteredTaints label
+	NodeFilteredTaints = "taints"
+	// NodeFilteredAffinity label
+	NodeFilteredAffinity = "affinity"
+	// NodeFilteredPorts label
+	NodeFilteredPorts = "ports"
+	// NodeFilteredUnschedulable label
+	NodeFilteredUnschedulable = "unschedulable"
+	// NodeFilteredReserved label
+	NodeFilteredReserved = "reserved"
+	// NodeFilteredOther label
+	NodeFilteredOther = "other"
+)
+
+var (
+	sessionJobsConsidered = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "session_jobs_considered",
+			Help:      "Number of jobs considered in the latest scheduling session",
+		},
+	)
+
+	sessionTasks = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "session_tasks",
+			Help:      "Number of tasks bound, pipelined or evicted in the latest scheduling session",
+		}, []string{"operation"},
+	)
+
+	invariantViolations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "invariant_violations_total",
+			Help:      "Number of violations of scheduling invariants found at session close",
+		}, []string{"invariant"},
+	)
+
+	sessionNodesFiltered = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "session_nodes_filtered",
+			Help:      "Number of nodes filtered out by reason in the latest scheduling session",
+		}, []string{"reason"},
+	)
+)
+
+// UpdateSessionSummary records the summary of the latest scheduling session, nodesFiltered is keyed by the
+// NodeFiltered reasons
+func UpdateSessionSummary(jobs int, tasks map[string]int, nodesFiltered map[string]int) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	sessionJobsConsidered.Set(float64(jobs))
+	for operation, count := range tasks {
+		sessionTasks.WithLabelValues(operation).Set(float64(count))
+	}
+	sessionNodesFiltered.Reset()
+	for reason, count := range nodesFiltered {
+		sessionNodesFiltered.WithLabelValues(reason).Set(float64(count))
+	}
+}
+
+// RegisterInvariantViolation records a violation of scheduling invariant
+func RegisterInvariantViolation(invariant string) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	invariantViolations.WithLabelValues(invariant).Inc()
+}
@@ -24,19 +24,29 @@ import (
 type NodeMetrics struct {
 	CPU    float64
 	Memory float64
+	// GPU and GPUMemory are the GPU utilization and GPU memory usage, set if GPUReported, i.e. the node has GPUs
+	// and the metrics source reports them.
+	GPU         float64
+	GPUMemory   float64
+	GPUReported bool
 }
 
 type MetricsClient interface {
 	NodeMetricsAvg(ctx context.Context, nodeName string, period string) (*NodeMetrics, error)
+	// NodeMetricsMax returns the max usages of the node over the period.
+	NodeMetricsMax(ctx context.Context, nodeName string, period string) (*NodeMetrics, error)
+	// NodeMetricsCommon returns the latest usages of the node.
+	NodeMetricsCommon(ctx context.Context, nodeName string) (*NodeMetrics, error)
 }
 
+// NewMetricsClient returns the built-in metrics client selected by the `type` of metrics configuration.
 func NewMetricsClient(metricsConf map[string]string) (MetricsClient, error) {
 	address := metricsConf["address"]
 	if len(address) == 0 {
 		return nil, errors.New("metrics address is empty")
 	}
 	metricsType := metricsConf["type"]
-	if metricsType == "elasticsearch" {
+	if metricsType == ElasticsearchProvider {
 		return NewElasticsearchMetricsClient(address, metricsConf)
 	}
 	return NewPrometheusMetricsClient(address, metricsConf)
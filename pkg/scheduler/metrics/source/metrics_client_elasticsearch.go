@@ -19,9 +19,11 @@ package source
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"text/template"
 
 	"github.com/elastic/go-elasticsearch/v7"
 )
@@ -33,40 +35,74 @@ const (
 	esCPUUsageField = "host.cpu.usage"
 	// esMemUsageField is the field name of mem usage in the document
 	esMemUsageField = "system.memory.actual.used.pct"
+	// esTimestampField is the field name of the time of the document
+	esTimestampField = "@timestamp"
+	// esCommonPeriod is the period the latest document is searched in for the latest usages
+	esCommonPeriod = "5m"
+	// esUsageScale converts the usages in the documents, which are ratios, to percentages
+	esUsageScale = 100
+	// esQueryTemplate is the query of the documents of the node in the period
+	esQueryTemplate = `{"bool": {"must": [` +
+		`{"range": {"{{.TimestampField}}": {"gte": "now-{{.Period}}", "lt": "now"}}},` +
+		`{"term": {"{{.HostnameField}}": "{{.Node}}"}}]}}`
 )
 
 type ElasticsearchMetricsClient struct {
-	address           string
-	indexName         string
-	es                *elasticsearch.Client
-	hostnameFieldName string
+	address            string
+	indexName          string
+	es                 *elasticsearch.Client
+	transport          http.RoundTripper
+	hostnameFieldName  string
+	cpuUsageFieldName  string
+	memUsageFieldName  string
+	timestampFieldName string
+	usageScale         float64
+	queryTemplate      *template.Template
+}
+
+// esQueryArgs are the arguments the query template is executed with.
+type esQueryArgs struct {
+	Node           string
+	Period         string
+	HostnameField  string
+	TimestampField string
 }
 
 func NewElasticsearchMetricsClient(address string, conf map[string]string) (*ElasticsearchMetricsClient, error) {
-	e := &ElasticsearchMetricsClient{address: address}
-	indexName := conf["elasticsearch.index"]
-	if len(indexName) == 0 {
-		e.indexName = "metricbeat-*"
-	} else {
-		e.indexName = indexName
-	}
-	hostNameFieldName := conf["elasticsearch.hostnameFieldName"]
-	if len(hostNameFieldName) == 0 {
-		e.hostnameFieldName = esHostNameField
-	} else {
-		e.hostnameFieldName = hostNameFieldName
-	}
-	var err error
-	insecureSkipVerify := conf["tls.insecureSkipVerify"] == "true"
+	e := &ElasticsearchMetricsClient{
+		address:            address,
+		indexName:          confOrDefault(conf, "elasticsearch.index", "metricbeat-*"),
+		hostnameFieldName:  confOrDefault(conf, "elasticsearch.hostnameFieldName", esHostNameField),
+		cpuUsageFieldName:  confOrDefault(conf, "elasticsearch.cpuUsageFieldName", esCPUUsageField),
+		memUsageFieldName:  confOrDefault(conf, "elasticsearch.memUsageFieldName", esMemUsageField),
+		timestampFieldName: confOrDefault(conf, "elasticsearch.timestampFieldName", esTimestampField),
+		usageScale:         esUsageScale,
+	}
+	if usageScale := conf["elasticsearch.usageScale"]; len(usageScale) != 0 {
+		scale, err := strconv.ParseFloat(usageScale, 64)
+		if err != nil || scale <= 0 {
+			return nil, fmt.Errorf("invalid elasticsearch.usageScale %q, expected a positive number", usageScale)
+		}
+		e.usageScale = scale
+	}
+	queryTemplate, err := template.New("query").Parse(confOrDefault(conf, "elasticsearch.queryTemplate", esQueryTemplate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid elasticsearch.queryTemplate: %v", err)
+	}
+	e.queryTemplate = queryTemplate
+	if _, err := e.nodeQuery("node", esCommonPeriod); err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(conf)
+	if err != nil {
+		return nil, err
+	}
+	e.transport = transport
 	e.es, err = elasticsearch.NewClient(elasticsearch.Config{
 		Addresses: []string{address},
-		Username:  conf["elasticsearch.username"],
-		Password:  conf["elasticsearch.password"],
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: insecureSkipVerify,
-			},
-		},
+		// The credentials are read from their files by the transport.
+		Transport: transport,
 	})
 	if err != nil {
 		return nil, err
@@ -74,55 +110,81 @@ func NewElasticsearchMetricsClient(address string, conf map[string]string) (*Ela
 	return e, nil
 }
 
+// confOrDefault returns the value of the key in the metrics configuration, or the default value if it is empty.
+func confOrDefault(conf map[string]string, key, defaultValue string) string {
+	if value := conf[key]; len(value) != 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// CloseIdleConnections closes the idle connections of the client once it is replaced.
+func (e *ElasticsearchMetricsClient) CloseIdleConnections() {
+	closeIdleConnections(e.transport)
+}
+
 func (e *ElasticsearchMetricsClient) NodeMetricsAvg(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
-	nodeMetrics := &NodeMetrics{}
-	var buf bytes.Buffer
-	query := map[string]interface{}{
-		"size": 0,
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": []map[string]interface{}{
-					{
-						"range": map[string]interface{}{
-							"@timestamp": map[string]interface{}{
-								"gte": "now-" + period,
-								"lt":  "now",
-							},
-						},
-					},
-					{
-						"term": map[string]interface{}{
-							e.hostnameFieldName: nodeName,
-						},
-					},
-				},
-			},
-		},
-		"aggs": map[string]interface{}{
-			"cpu": map[string]interface{}{
-				"avg": map[string]interface{}{
-					"field": esCPUUsageField,
-				},
-			},
-			"mem": map[string]interface{}{
-				"avg": map[string]interface{}{
-					"field": esMemUsageField,
-				},
-			},
-		},
+	return e.aggregate(ctx, nodeName, period, "avg")
+}
+
+// NodeMetricsMax returns the max usages of the node in the documents of the period.
+func (e *ElasticsearchMetricsClient) NodeMetricsMax(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
+	return e.aggregate(ctx, nodeName, period, "max")
+}
+
+// NodeMetricsCommon returns the usages of the node in its latest document.
+func (e *ElasticsearchMetricsClient) NodeMetricsCommon(ctx context.Context, nodeName string) (*NodeMetrics, error) {
+	query, err := e.nodeQuery(nodeName, esCommonPeriod)
+	if err != nil {
+		return nil, err
 	}
-	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+	query["size"] = 1
+	query["sort"] = []map[string]interface{}{{e.timestampFieldName: map[string]interface{}{"order": "desc"}}}
+	query["docvalue_fields"] = []string{e.cpuUsageFieldName, e.memUsageFieldName}
+	var r struct {
+		Hits struct {
+			Hits []struct {
+				Fields map[string][]float64 `json:"fields"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := e.search(ctx, query, &r); err != nil {
 		return nil, err
 	}
-	res, err := e.es.Search(
-		e.es.Search.WithContext(ctx),
-		e.es.Search.WithIndex(e.indexName),
-		e.es.Search.WithBody(&buf),
-	)
+	nodeMetrics := &NodeMetrics{}
+	if len(r.Hits.Hits) == 0 {
+		return nodeMetrics, nil
+	}
+	fields := r.Hits.Hits[0].Fields
+	// The data obtained from Elasticsearch is in decimals by default and needs to be multiplied by 100.
+	if values := fields[e.cpuUsageFieldName]; len(values) > 0 {
+		nodeMetrics.CPU = values[0] * e.usageScale
+	}
+	if values := fields[e.memUsageFieldName]; len(values) > 0 {
+		nodeMetrics.Memory = values[0] * e.usageScale
+	}
+	return nodeMetrics, nil
+}
+
+// aggregate returns the usages of the node aggregated by agg, e.g. avg or max, in the documents of the period.
+func (e *ElasticsearchMetricsClient) aggregate(ctx context.Context, nodeName, period, agg string) (*NodeMetrics, error) {
+	query, err := e.nodeQuery(nodeName, period)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	query["size"] = 0
+	query["aggs"] = map[string]interface{}{
+		"cpu": map[string]interface{}{
+			agg: map[string]interface{}{
+				"field": e.cpuUsageFieldName,
+			},
+		},
+		"mem": map[string]interface{}{
+			agg: map[string]interface{}{
+				"field": e.memUsageFieldName,
+			},
+		},
+	}
 	var r struct {
 		Aggregations struct {
 			CPU struct {
@@ -133,11 +195,52 @@ func (e *ElasticsearchMetricsClient) NodeMetricsAvg(ctx context.Context, nodeNam
 			}
 		} `json:"aggregations"`
 	}
-	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+	if err := e.search(ctx, query, &r); err != nil {
 		return nil, err
 	}
-	// The data obtained from Elasticsearch is in decimals and needs to be multiplied by 100.
-	nodeMetrics.CPU = r.Aggregations.CPU.Value * 100
-	nodeMetrics.Memory = r.Aggregations.Mem.Value * 100
+	nodeMetrics := &NodeMetrics{}
+	// The data obtained from Elasticsearch is in decimals by default and needs to be multiplied by 100.
+	nodeMetrics.CPU = r.Aggregations.CPU.Value * e.usageScale
+	nodeMetrics.Memory = r.Aggregations.Mem.Value * e.usageScale
 	return nodeMetrics, nil
 }
+
+// nodeQuery returns the query of the documents of the node in the period, rendered from the query template.
+func (e *ElasticsearchMetricsClient) nodeQuery(nodeName, period string) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	err := e.queryTemplate.Execute(&buf, &esQueryArgs{
+		Node:           nodeName,
+		Period:         period,
+		HostnameField:  e.hostnameFieldName,
+		TimestampField: e.timestampFieldName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute elasticsearch.queryTemplate: %v", err)
+	}
+	var query interface{}
+	if err := json.Unmarshal(buf.Bytes(), &query); err != nil {
+		return nil, fmt.Errorf("elasticsearch.queryTemplate is not a JSON query: %v", err)
+	}
+	return map[string]interface{}{"query": query}, nil
+}
+
+// search sends the query to the index and decodes the response to result.
+func (e *ElasticsearchMetricsClient) search(ctx context.Context, query map[string]interface{}, result interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return err
+	}
+	res, err := e.es.Search(
+		e.es.Search.WithContext(ctx),
+		e.es.Search.WithIndex(e.indexName),
+		e.es.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("failed to search index %s: %s", e.indexName, res.String())
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
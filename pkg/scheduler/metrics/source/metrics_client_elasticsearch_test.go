@@ -16,7 +16,17 @@
 
 package source
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestElasticsearchMetricsClientDefaultIndexName(t *testing.T) {
 	client, err := NewElasticsearchMetricsClient("http://localhost:9200", map[string]string{})
@@ -37,3 +47,120 @@ func TestElasticsearchMetricsClientCustomIndexName(t *testing.T) {
 		t.Errorf("Custom index name should be custom-index")
 	}
 }
+
+// fakeElasticsearch serves the search API of Elasticsearch, it records the path and the query of the last search.
+func fakeElasticsearch(t *testing.T, status int, response string) (*httptest.Server, *string, *map[string]interface{}, *http.Header) {
+	var path string
+	var query map[string]interface{}
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		// The client checks the product by the info API if the first search fails.
+		if r.URL.Path == "/" {
+			io.WriteString(w, `{"version": {"number": "7.17.7"}, "tagline": "You Know, for Search"}`)
+			return
+		}
+		path, header = r.URL.Path, r.Header
+		body, _ := io.ReadAll(r.Body)
+		query = nil
+		if err := json.Unmarshal(body, &query); err != nil {
+			t.Errorf("Failed to decode query %s: %v", body, err)
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, &path, &query, &header
+}
+
+func TestElasticsearchMetricsClientQueryTemplate(t *testing.T) {
+	server, path, query, header := fakeElasticsearch(t, http.StatusOK,
+		`{"aggregations": {"cpu": {"value": 42}, "mem": {"value": 7}}}`)
+	apiKeyFile := filepath.Join(t.TempDir(), "api-key")
+	if err := os.WriteFile(apiKeyFile, []byte("a2V5\n"), 0600); err != nil {
+		t.Fatalf("Failed to write api key: %v", err)
+	}
+	client, err := NewElasticsearchMetricsClient(server.URL, map[string]string{
+		"elasticsearch.index":             "node-exporter-*",
+		"elasticsearch.apiKeyFile":        apiKeyFile,
+		"elasticsearch.cpuUsageFieldName": "node.cpu.pct",
+		"elasticsearch.memUsageFieldName": "node.mem.pct",
+		"elasticsearch.usageScale":        "1",
+		"elasticsearch.queryTemplate":     `{"bool": {"filter": [{"term": {"labels.instance": "{{.Node}}:9100"}}, {"range": {"{{.TimestampField}}": {"gte": "now-{{.Period}}"}}}]}}`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	metrics, err := client.NodeMetricsAvg(context.TODO(), "n1", "1h")
+	if err != nil {
+		t.Fatalf("Failed to query metrics: %v", err)
+	}
+	if metrics.CPU != 42 || metrics.Memory != 7 {
+		t.Errorf("Expected cpu 42 and memory 7 in percent, got %v", metrics)
+	}
+	if *path != "/node-exporter-*/_search" {
+		t.Errorf("Expected search of index node-exporter-*, got %s", *path)
+	}
+	if auth := header.Get("Authorization"); auth != "APIKey a2V5" {
+		t.Errorf("Expected API key authorization, got %q", auth)
+	}
+	encoded, _ := json.Marshal(*query)
+	for _, expected := range []string{`"labels.instance":"n1:9100"`, `"gte":"now-1h"`, `"@timestamp"`, `"field":"node.cpu.pct"`, `{"avg":{"field":"node.mem.pct"}}`} {
+		if !strings.Contains(string(encoded), expected) {
+			t.Errorf("Expected %s in query %s", expected, encoded)
+		}
+	}
+}
+
+func TestElasticsearchMetricsClientCommon(t *testing.T) {
+	server, _, query, _ := fakeElasticsearch(t, http.StatusOK,
+		`{"hits": {"hits": [{"fields": {"host.cpu.usage": [0.5], "system.memory.actual.used.pct": [0.25]}}]}}`)
+	client, err := NewElasticsearchMetricsClient(server.URL, map[string]string{})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	metrics, err := client.NodeMetricsCommon(context.TODO(), "n1")
+	if err != nil {
+		t.Fatalf("Failed to query metrics: %v", err)
+	}
+	if metrics.CPU != 50 || metrics.Memory != 25 {
+		t.Errorf("Expected cpu 50 and memory 25 in percent, got %v", metrics)
+	}
+	encoded, _ := json.Marshal(*query)
+	for _, expected := range []string{`"host.hostname":"n1"`, `"gte":"now-5m"`, `"size":1`} {
+		if !strings.Contains(string(encoded), expected) {
+			t.Errorf("Expected %s in query %s", expected, encoded)
+		}
+	}
+}
+
+func TestElasticsearchMetricsClientSearchError(t *testing.T) {
+	server, _, _, _ := fakeElasticsearch(t, http.StatusNotFound,
+		`{"error": {"type": "index_not_found_exception"}, "status": 404}`)
+	client, err := NewElasticsearchMetricsClient(server.URL, map[string]string{})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.NodeMetricsMax(context.TODO(), "n1", "5m"); err == nil || !strings.Contains(err.Error(), "index_not_found_exception") {
+		t.Errorf("Expected error of missing index, got %v", err)
+	}
+}
+
+func TestElasticsearchMetricsClientInvalidConf(t *testing.T) {
+	for _, conf := range []map[string]string{
+		{"elasticsearch.usageScale": "-1"},
+		{"elasticsearch.usageScale": "percent"},
+		{"elasticsearch.queryTemplate": `{"term": {"host.name": "{{.Node}"}}`},
+		{"elasticsearch.queryTemplate": `{"term": {"host.name": {{.Node}}}}`},
+		{"elasticsearch.queryTemplate": `{"term": {"host.name": "{{.Host}}"}}`},
+		{"elasticsearch.username": "elastic", "elasticsearch.password": "secret"},
+	} {
+		if _, err := NewElasticsearchMetricsClient("http://localhost:9200", conf); err == nil {
+			t.Errorf("Expected error of invalid configuration %v", conf)
+		}
+	}
+}
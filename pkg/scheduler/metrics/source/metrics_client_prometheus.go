@@ -18,7 +18,6 @@ package source
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -29,6 +28,8 @@ import (
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	pmodel "github.com/prometheus/common/model"
 	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 const (
@@ -36,66 +37,185 @@ const (
 	promCPUUsageAvg = "cpu_usage_avg"
 	// promMemUsageAvg record name of mem average usage defined in prometheus rules
 	promMemUsageAvg = "mem_usage_avg"
+	// promGPUUsageAvg record name of gpu average utilization defined in prometheus rules
+	promGPUUsageAvg = "gpu_usage_avg"
+	// promGPUMemUsageAvg record name of gpu memory average usage defined in prometheus rules
+	promGPUMemUsageAvg = "gpu_mem_usage_avg"
+	// promCPUUsageActive record name of cpu instant usage defined in prometheus rules
+	promCPUUsageActive = "cpu_usage_active"
+	// promMemUsageActive record name of mem instant usage defined in prometheus rules
+	promMemUsageActive = "mem_usage_active"
+	// promGPUUsageActive record name of gpu instant utilization defined in prometheus rules
+	promGPUUsageActive = "gpu_usage_active"
+	// promGPUMemUsageActive record name of gpu memory instant usage defined in prometheus rules
+	promGPUMemUsageActive = "gpu_mem_usage_active"
+
+	// promPodCPUQuery and promPodMemoryQuery are the keys of metrics configuration with the queries of the cpu
+	// usages in cores and the memory usages in bytes of pods, by the labels namespace and pod.
+	promPodCPUQuery    = "prometheus.podCPUQuery"
+	promPodMemoryQuery = "prometheus.podMemoryQuery"
+	// defaultPromPodCPUQuery and defaultPromPodMemoryQuery query the container metrics of cAdvisor.
+	defaultPromPodCPUQuery    = `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!=""}[5m]))`
+	defaultPromPodMemoryQuery = `sum by (namespace, pod) (container_memory_working_set_bytes{container!=""})`
 )
 
 type PrometheusMetricsClient struct {
-	address string
-	conf    map[string]string
+	address   string
+	conf      map[string]string
+	transport http.RoundTripper
 }
 
+var _ PodMetricsClient = &PrometheusMetricsClient{}
+
 func NewPrometheusMetricsClient(address string, conf map[string]string) (*PrometheusMetricsClient, error) {
-	return &PrometheusMetricsClient{address: address, conf: conf}, nil
+	transport, err := newTransport(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusMetricsClient{address: address, conf: conf, transport: transport}, nil
+}
+
+// CloseIdleConnections closes the idle connections of the client once it is replaced.
+func (p *PrometheusMetricsClient) CloseIdleConnections() {
+	closeIdleConnections(p.transport)
 }
 
 func (p *PrometheusMetricsClient) NodeMetricsAvg(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
-	klog.V(4).Infof("Get node metrics from Prometheus: %s", p.address)
-	var client api.Client
-	var err error
-	insecureSkipVerify := p.conf["tls.insecureSkipVerify"] == "true"
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: insecureSkipVerify,
-		},
-	}
-	client, err = api.NewClient(api.Config{
+	query := func(record string) string {
+		return fmt.Sprintf("%s_%s{instance=\"%s\"}", record, period, nodeName)
+	}
+	return p.nodeMetrics(ctx, query(promCPUUsageAvg), query(promMemUsageAvg), query(promGPUUsageAvg), query(promGPUMemUsageAvg))
+}
+
+// NodeMetricsMax returns the max of the instant usages of the node over the period.
+func (p *PrometheusMetricsClient) NodeMetricsMax(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
+	query := func(record string) string {
+		return fmt.Sprintf("max_over_time(%s{instance=\"%s\"}[%s])", record, nodeName, period)
+	}
+	return p.nodeMetrics(ctx, query(promCPUUsageActive), query(promMemUsageActive), query(promGPUUsageActive), query(promGPUMemUsageActive))
+}
+
+// NodeMetricsCommon returns the instant usages of the node.
+func (p *PrometheusMetricsClient) NodeMetricsCommon(ctx context.Context, nodeName string) (*NodeMetrics, error) {
+	query := func(record string) string {
+		return fmt.Sprintf("%s{instance=\"%s\"}", record, nodeName)
+	}
+	return p.nodeMetrics(ctx, query(promCPUUsageActive), query(promMemUsageActive), query(promGPUUsageActive), query(promGPUMemUsageActive))
+}
+
+// PodMetrics returns the usages of pods by the queries of metrics configuration, the pods without cpu usage
+// are skipped.
+func (p *PrometheusMetricsClient) PodMetrics(ctx context.Context) (map[string]*schedulingapi.PodUsage, error) {
+	client, err := api.NewClient(api.Config{
 		Address:      p.address,
-		RoundTripper: tr,
+		RoundTripper: p.transport,
 	})
 	if err != nil {
 		return nil, err
 	}
 	v1api := prometheusv1.NewAPI(client)
-	nodeMetrics := &NodeMetrics{}
-	for _, metric := range []string{promCPUUsageAvg, promMemUsageAvg} {
-		queryStr := fmt.Sprintf("%s_%s{instance=\"%s\"}", metric, period, nodeName)
-		klog.V(4).Infof("Query prometheus by %s", queryStr)
-		res, warnings, err := v1api.Query(ctx, queryStr, time.Now())
-		if err != nil {
-			klog.Errorf("Error querying Prometheus: %v", err)
-		}
-		if len(warnings) > 0 {
-			klog.V(3).Infof("Warning querying Prometheus: %v", warnings)
-		}
-		if res == nil || res.String() == "" {
-			klog.Warningf("Warning querying Prometheus: no data found for %s", queryStr)
-			continue
-		}
-		// plugin.usage only need type pmodel.ValVector in Prometheus.rulues
-		if res.Type() != pmodel.ValVector {
+
+	cpuQuery, memQuery := p.conf[promPodCPUQuery], p.conf[promPodMemoryQuery]
+	if len(cpuQuery) == 0 {
+		cpuQuery = defaultPromPodCPUQuery
+	}
+	if len(memQuery) == 0 {
+		memQuery = defaultPromPodMemoryQuery
+	}
+	cpuUsages, err := queryPods(ctx, v1api, cpuQuery)
+	if err != nil {
+		return nil, err
+	}
+	memUsages, err := queryPods(ctx, v1api, memQuery)
+	if err != nil {
+		return nil, err
+	}
+	usages := make(map[string]*schedulingapi.PodUsage, len(cpuUsages))
+	for pod, cpu := range cpuUsages {
+		usages[pod] = &schedulingapi.PodUsage{MilliCPU: cpu * 1000, Memory: memUsages[pod]}
+	}
+	return usages, nil
+}
+
+// queryPods returns the values of the vector queried keyed by the labels namespace/pod.
+func queryPods(ctx context.Context, v1api prometheusv1.API, queryStr string) (map[string]float64, error) {
+	klog.V(4).Infof("Query prometheus by %s", queryStr)
+	res, warnings, err := v1api.Query(ctx, queryStr, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error querying Prometheus by %s: %v", queryStr, err)
+	}
+	if len(warnings) > 0 {
+		klog.V(3).Infof("Warning querying Prometheus: %v", warnings)
+	}
+	vector, ok := res.(pmodel.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %v of %s, expected vector", res.Type(), queryStr)
+	}
+	values := make(map[string]float64, len(vector))
+	for _, sample := range vector {
+		namespace, pod := sample.Metric["namespace"], sample.Metric["pod"]
+		if len(namespace) == 0 || len(pod) == 0 {
 			continue
 		}
-		// only method res.String() can get data, dataType []pmodel.ValVector, eg: "{k1:v1, ...} => #[value] @#[timespace]\n {k2:v2, ...} => ..."
-		firstRowValVector := strings.Split(res.String(), "\n")[0]
-		rowValues := strings.Split(strings.TrimSpace(firstRowValVector), "=>")
-		value := strings.Split(strings.TrimSpace(rowValues[1]), " ")
-		switch metric {
-		case promCPUUsageAvg:
-			cpuUsage, _ := strconv.ParseFloat(value[0], 64)
-			nodeMetrics.CPU = cpuUsage
-		case promMemUsageAvg:
-			memUsage, _ := strconv.ParseFloat(value[0], 64)
-			nodeMetrics.Memory = memUsage
-		}
+		values[string(namespace)+"/"+string(pod)] = float64(sample.Value)
+	}
+	return values, nil
+}
+
+// nodeMetrics returns the cpu, memory, gpu and gpu memory usages of the node by their queries. The gpu usages
+// are not reported if there is no data of gpuQuery, e.g. the node has no GPUs.
+func (p *PrometheusMetricsClient) nodeMetrics(ctx context.Context, cpuQuery, memQuery, gpuQuery, gpuMemQuery string) (*NodeMetrics, error) {
+	klog.V(4).Infof("Get node metrics from Prometheus: %s", p.address)
+	client, err := api.NewClient(api.Config{
+		Address:      p.address,
+		RoundTripper: p.transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	v1api := prometheusv1.NewAPI(client)
+	nodeMetrics := &NodeMetrics{}
+	if value, found := query(ctx, v1api, cpuQuery); found {
+		nodeMetrics.CPU = value
+	} else {
+		klog.Warningf("Warning querying Prometheus: no data found for %s", cpuQuery)
+	}
+	if value, found := query(ctx, v1api, memQuery); found {
+		nodeMetrics.Memory = value
+	} else {
+		klog.Warningf("Warning querying Prometheus: no data found for %s", memQuery)
+	}
+	if value, found := query(ctx, v1api, gpuQuery); found {
+		nodeMetrics.GPU = value
+		nodeMetrics.GPUReported = true
+		nodeMetrics.GPUMemory, _ = query(ctx, v1api, gpuMemQuery)
+	} else {
+		klog.V(4).Infof("No data found for %s, gpu usages are not reported", gpuQuery)
 	}
 	return nodeMetrics, nil
 }
+
+// query returns the value of the first row of the vector queried, and whether it is found.
+func query(ctx context.Context, v1api prometheusv1.API, queryStr string) (float64, bool) {
+	klog.V(4).Infof("Query prometheus by %s", queryStr)
+	res, warnings, err := v1api.Query(ctx, queryStr, time.Now())
+	if err != nil {
+		klog.Errorf("Error querying Prometheus: %v", err)
+	}
+	if len(warnings) > 0 {
+		klog.V(3).Infof("Warning querying Prometheus: %v", warnings)
+	}
+	if res == nil || res.String() == "" {
+		return 0, false
+	}
+	// plugin.usage only need type pmodel.ValVector in Prometheus.rulues
+	if res.Type() != pmodel.ValVector {
+		return 0, false
+	}
+	// only method res.String() can get data, dataType []pmodel.ValVector, eg: "{k1:v1, ...} => #[value] @#[timespace]\n {k2:v2, ...} => ..."
+	firstRowValVector := strings.Split(res.String(), "\n")[0]
+	rowValues := strings.Split(strings.TrimSpace(firstRowValVector), "=>")
+	value := strings.Split(strings.TrimSpace(rowValues[1]), " ")
+	usage, _ := strconv.ParseFloat(value[0], 64)
+	return usage, true
+}
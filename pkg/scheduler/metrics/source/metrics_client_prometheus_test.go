@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetricsClientQueries(t *testing.T) {
+	values := map[string]string{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse query: %v", err)
+		}
+		query := r.Form.Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(query, "gpu") && strings.Contains(query, "n2") {
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+			return
+		}
+		value := "50"
+		switch {
+		case strings.Contains(query, "gpu_mem"):
+			value = "80"
+		case strings.Contains(query, "gpu"):
+			value = "90"
+		case strings.Contains(query, "mem"):
+			value = "70"
+		}
+		values[query] = value
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"instance":"n1"},"value":[1684000000,"%s"]}]}}`, value)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewPrometheusMetricsClient(server.URL, map[string]string{})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		metrics func() (*NodeMetrics, error)
+		queries []string
+	}{
+		{
+			name:    "average",
+			metrics: func() (*NodeMetrics, error) { return client.NodeMetricsAvg(context.TODO(), "n1", "5m") },
+			queries: []string{`cpu_usage_avg_5m{instance="n1"}`, `mem_usage_avg_5m{instance="n1"}`,
+				`gpu_usage_avg_5m{instance="n1"}`, `gpu_mem_usage_avg_5m{instance="n1"}`},
+		},
+		{
+			name:    "max",
+			metrics: func() (*NodeMetrics, error) { return client.NodeMetricsMax(context.TODO(), "n1", "5m") },
+			queries: []string{`max_over_time(cpu_usage_active{instance="n1"}[5m])`, `max_over_time(mem_usage_active{instance="n1"}[5m])`,
+				`max_over_time(gpu_usage_active{instance="n1"}[5m])`, `max_over_time(gpu_mem_usage_active{instance="n1"}[5m])`},
+		},
+		{
+			name:    "common",
+			metrics: func() (*NodeMetrics, error) { return client.NodeMetricsCommon(context.TODO(), "n1") },
+			queries: []string{`cpu_usage_active{instance="n1"}`, `mem_usage_active{instance="n1"}`,
+				`gpu_usage_active{instance="n1"}`, `gpu_mem_usage_active{instance="n1"}`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		values = map[string]string{}
+		nodeMetrics, err := testCase.metrics()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.name, err)
+			continue
+		}
+		for _, query := range testCase.queries {
+			if _, found := values[query]; !found {
+				t.Errorf("%s: expected query %s, got %v", testCase.name, query, values)
+			}
+		}
+		if nodeMetrics.CPU != 50 || nodeMetrics.Memory != 70 {
+			t.Errorf("%s: expected cpu 50 and memory 70, got %v", testCase.name, *nodeMetrics)
+		}
+		if !nodeMetrics.GPUReported || nodeMetrics.GPU != 90 || nodeMetrics.GPUMemory != 80 {
+			t.Errorf("%s: expected gpu 90 and gpu memory 80, got %v", testCase.name, *nodeMetrics)
+		}
+	}
+
+	nodeMetrics, err := client.NodeMetricsAvg(context.TODO(), "n2", "5m")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if nodeMetrics.GPUReported || nodeMetrics.CPU != 50 {
+		t.Errorf("expected cpu usage but no gpu usages of node without gpu data, got %v", *nodeMetrics)
+	}
+}
+
+func TestPrometheusMetricsClientPodMetrics(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse query: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.Form.Get("query"), "memory") {
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"namespace":"ns","pod":"p1"},"value":[1684000000,"1000000"]}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"namespace":"ns","pod":"p1"},"value":[1684000000,"0.5"]},{"metric":{"namespace":"ns","pod":"p2"},"value":[1684000000,"2"]},{"metric":{},"value":[1684000000,"1"]}]}}`)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewPrometheusMetricsClient(server.URL, map[string]string{})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	usages, err := client.PodMetrics(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(usages) != 2 || usages["ns/p1"].MilliCPU != 500 || usages["ns/p1"].Memory != 1000000 ||
+		usages["ns/p2"].MilliCPU != 2000 || usages["ns/p2"].Memory != 0 {
+		t.Errorf("unexpected pod usages %v", usages)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// MetricsServerProvider is the name of the provider querying metrics-server by the metrics.k8s.io API.
+	MetricsServerProvider = "metrics_server"
+
+	// metricsServerNodesPath is the path of the node metrics of metrics.k8s.io API
+	metricsServerNodesPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+	// metricsServerPodsPath is the path of the pod metrics of all namespaces of metrics.k8s.io API
+	metricsServerPodsPath = "/apis/metrics.k8s.io/v1beta1/pods"
+)
+
+// KubeProvider is a provider querying the Kubernetes API server, the client of the scheduler is set before
+// the usages are queried.
+type KubeProvider interface {
+	Provider
+	SetKubeClient(kubeClient kubernetes.Interface)
+}
+
+// metricsServerNodeMetrics is the node metrics of metrics.k8s.io API, only the fields used are decoded.
+type metricsServerNodeMetrics struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Window   metav1.Duration   `json:"window"`
+	Usage    v1.ResourceList   `json:"usage"`
+}
+
+// metricsServerNodeMetricsList is the list of node metrics of metrics.k8s.io API.
+type metricsServerNodeMetricsList struct {
+	Items []metricsServerNodeMetrics `json:"items"`
+}
+
+// metricsServerPodMetricsList is the list of pod metrics of metrics.k8s.io API, only the fields used are decoded.
+type metricsServerPodMetricsList struct {
+	Items []struct {
+		Metadata   metav1.ObjectMeta `json:"metadata"`
+		Containers []struct {
+			Usage v1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// metricsServerProvider populates the usages of nodes by metrics-server, which only reports the usages over
+// a short window, e.g. 30s. The usages of the window are used as the averages and maxes of all the periods.
+type metricsServerProvider struct {
+	mutex      sync.RWMutex
+	kubeClient kubernetes.Interface
+}
+
+func (mp *metricsServerProvider) Name() string {
+	return MetricsServerProvider
+}
+
+// Validate accepts any configuration, metrics-server is reached through the API server.
+func (mp *metricsServerProvider) Validate(metricsConf map[string]string) error {
+	return nil
+}
+
+func (mp *metricsServerProvider) SetKubeClient(kubeClient kubernetes.Interface) {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+	mp.kubeClient = kubeClient
+}
+
+func (mp *metricsServerProvider) client() kubernetes.Interface {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+	return mp.kubeClient
+}
+
+// QueryNodeUsage returns the usages of a node by its metrics and the capacity of the node got from the API server.
+// The scheduler cache collects the usages of all nodes by QueryNodeUsages instead.
+func (mp *metricsServerProvider) QueryNodeUsage(ctx context.Context, metricsConf map[string]string, nodeName string, periods []string) (*schedulingapi.NodeUsage, error) {
+	kubeClient := mp.client()
+	if kubeClient == nil {
+		return nil, errors.New("no kubernetes client to query metrics-server")
+	}
+
+	raw, err := kubeClient.CoreV1().RESTClient().Get().AbsPath(metricsServerNodesPath, nodeName).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics of node %s from metrics-server: %v", nodeName, err)
+	}
+	var nodeMetrics metricsServerNodeMetrics
+	if err := json.Unmarshal(raw, &nodeMetrics); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics of node %s: %v", nodeName, err)
+	}
+	node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %v", nodeName, err)
+	}
+	return metricsServerNodeUsage(nodeName, &nodeMetrics, schedulingapi.NewResource(node.Status.Capacity), periods), nil
+}
+
+// QueryNodeUsages lists the metrics of all nodes once, the usages are percentages of the capacities of the nodes
+// in the scheduler cache, so that neither the metrics nor the node is got per node.
+func (mp *metricsServerProvider) QueryNodeUsages(ctx context.Context, metricsConf map[string]string, capacities map[string]*schedulingapi.Resource, periods []string) (map[string]*schedulingapi.NodeUsage, error) {
+	kubeClient := mp.client()
+	if kubeClient == nil {
+		return nil, errors.New("no kubernetes client to query metrics-server")
+	}
+
+	raw, err := kubeClient.CoreV1().RESTClient().Get().AbsPath(metricsServerNodesPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metrics of nodes from metrics-server: %v", err)
+	}
+	var nodeMetrics metricsServerNodeMetricsList
+	if err := json.Unmarshal(raw, &nodeMetrics); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics of nodes: %v", err)
+	}
+	usages := make(map[string]*schedulingapi.NodeUsage, len(nodeMetrics.Items))
+	for i := range nodeMetrics.Items {
+		name := nodeMetrics.Items[i].Metadata.Name
+		if capacity, found := capacities[name]; found && capacity != nil {
+			usages[name] = metricsServerNodeUsage(name, &nodeMetrics.Items[i], capacity, periods)
+		}
+	}
+	return usages, nil
+}
+
+// metricsServerNodeUsage returns the usages of the node metrics in percentages of the capacity of node, like the
+// usages of node exporters, they are used as the averages and maxes of all the periods.
+func metricsServerNodeUsage(nodeName string, nodeMetrics *metricsServerNodeMetrics, capacity *schedulingapi.Resource, periods []string) *schedulingapi.NodeUsage {
+	cpu := percentage(float64(nodeMetrics.Usage.Cpu().MilliValue()), capacity.MilliCPU)
+	memory := percentage(float64(nodeMetrics.Usage.Memory().Value()), capacity.Memory)
+	klog.V(4).Infof("node: %v, CpuUsage: %v, MemUsage: %v, window: %v", nodeName, cpu, memory, nodeMetrics.Window.Duration)
+
+	usage := &schedulingapi.NodeUsage{
+		CPUUsageAvg: make(map[string]float64),
+		MEMUsageAvg: make(map[string]float64),
+		CPUUsageMax: make(map[string]float64),
+		MEMUsageMax: make(map[string]float64),
+		CPUUsage:    cpu,
+		MEMUsage:    memory,
+		SampleTime:  time.Now(),
+	}
+	for _, period := range periods {
+		usage.CPUUsageAvg[period] = cpu
+		usage.MEMUsageAvg[period] = memory
+		usage.CPUUsageMax[period] = cpu
+		usage.MEMUsageMax[period] = memory
+	}
+	return usage
+}
+
+// QueryPodUsages returns the usages of the containers of pods summed up.
+func (mp *metricsServerProvider) QueryPodUsages(ctx context.Context, metricsConf map[string]string) (map[string]*schedulingapi.PodUsage, error) {
+	kubeClient := mp.client()
+	if kubeClient == nil {
+		return nil, errors.New("no kubernetes client to query metrics-server")
+	}
+
+	raw, err := kubeClient.CoreV1().RESTClient().Get().AbsPath(metricsServerPodsPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics of pods from metrics-server: %v", err)
+	}
+	var podMetrics metricsServerPodMetricsList
+	if err := json.Unmarshal(raw, &podMetrics); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics of pods: %v", err)
+	}
+	usages := make(map[string]*schedulingapi.PodUsage, len(podMetrics.Items))
+	for _, item := range podMetrics.Items {
+		usage := &schedulingapi.PodUsage{}
+		for _, container := range item.Containers {
+			usage.MilliCPU += float64(container.Usage.Cpu().MilliValue())
+			usage.Memory += float64(container.Usage.Memory().Value())
+		}
+		usages[item.Metadata.Namespace+"/"+item.Metadata.Name] = usage
+	}
+	return usages, nil
+}
+
+func percentage(used, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return used / total * 100
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestMetricsServerProvider(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/apis/metrics.k8s.io/v1beta1/nodes/n1":
+			fmt.Fprint(w, `{"kind":"NodeMetrics","apiVersion":"metrics.k8s.io/v1beta1","metadata":{"name":"n1"},`+
+				`"timestamp":"2023-05-01T00:00:00Z","window":"20s","usage":{"cpu":"1","memory":"2Gi"}}`)
+		case "/api/v1/nodes/n1":
+			fmt.Fprint(w, `{"kind":"Node","apiVersion":"v1","metadata":{"name":"n1"},`+
+				`"status":{"capacity":{"cpu":"4","memory":"8Gi"},"allocatable":{"cpu":"3","memory":"6Gi"}}}`)
+		case "/apis/metrics.k8s.io/v1beta1/nodes":
+			fmt.Fprint(w, `{"kind":"NodeMetricsList","apiVersion":"metrics.k8s.io/v1beta1","items":[`+
+				`{"metadata":{"name":"n1"},"window":"20s","usage":{"cpu":"1","memory":"2Gi"}},`+
+				`{"metadata":{"name":"n3"},"window":"20s","usage":{"cpu":"1","memory":"2Gi"}}]}`)
+		case "/apis/metrics.k8s.io/v1beta1/pods":
+			fmt.Fprint(w, `{"kind":"PodMetricsList","apiVersion":"metrics.k8s.io/v1beta1","items":[{"metadata":{"namespace":"ns","name":"p1"},`+
+				`"containers":[{"name":"c1","usage":{"cpu":"250m","memory":"1Gi"}},{"name":"c2","usage":{"cpu":"250m","memory":"1Gi"}}]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	provider, err := NewProvider(map[string]string{"type": MetricsServerProvider})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	kubeProvider, ok := provider.(KubeProvider)
+	if !ok {
+		t.Fatalf("expected provider %s querying the API server", provider.Name())
+	}
+	if _, err := provider.QueryNodeUsage(context.TODO(), nil, "n1", []string{"5m"}); err == nil {
+		t.Errorf("expected error without kubernetes client")
+	}
+
+	kubeProvider.SetKubeClient(kubernetes.NewForConfigOrDie(&rest.Config{Host: server.URL}))
+	defer kubeProvider.SetKubeClient(nil)
+	usage, err := provider.QueryNodeUsage(context.TODO(), nil, "n1", []string{"5m", "1h"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if usage.CPUUsage != 25 || usage.MEMUsage != 25 {
+		t.Errorf("expected cpu and memory usage 25, got %v", *usage)
+	}
+	for _, period := range []string{"5m", "1h"} {
+		if usage.CPUUsageAvg[period] != 25 || usage.MEMUsageAvg[period] != 25 || usage.CPUUsageMax[period] != 25 {
+			t.Errorf("expected usages of period %s 25, got %v", period, *usage)
+		}
+	}
+	if usage.GPUReported {
+		t.Errorf("expected no gpu usages reported by metrics-server")
+	}
+
+	if _, err := provider.QueryNodeUsage(context.TODO(), nil, "n2", []string{"5m"}); err == nil {
+		t.Errorf("expected error of node without metrics")
+	}
+
+	// The usages of all nodes are listed once, and are percentages of the capacities in the scheduler cache.
+	usages, err := provider.(NodeUsagesProvider).QueryNodeUsages(context.TODO(), nil, map[string]*schedulingapi.Resource{
+		"n1": {MilliCPU: 2000, Memory: 4 * 1024 * 1024 * 1024},
+		"n2": {MilliCPU: 2000, Memory: 4 * 1024 * 1024 * 1024},
+	}, []string{"5m"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(usages) != 1 || usages["n1"] == nil || usages["n1"].CPUUsage != 50 || usages["n1"].MEMUsageMax["5m"] != 50 {
+		t.Errorf("expected usages 50 of node n1 only, got %v", usages)
+	}
+
+	podUsages, err := provider.(PodUsageProvider).QueryPodUsages(context.TODO(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(podUsages) != 1 || podUsages["ns/p1"].MilliCPU != 500 || podUsages["ns/p1"].Memory != 2*1024*1024*1024 {
+		t.Errorf("expected usages of containers of pod ns/p1 summed up, got %v", podUsages)
+	}
+}
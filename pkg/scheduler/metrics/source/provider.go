@@ -0,0 +1,271 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// PrometheusProvider is the name of the provider querying Prometheus, the default.
+	PrometheusProvider = "prometheus"
+	// ElasticsearchProvider is the name of the provider querying Elasticsearch.
+	ElasticsearchProvider = "elasticsearch"
+)
+
+// Provider is a metrics backend the scheduler collects node usages from, it is selected by the `type` of
+// metrics configuration. Downstream schedulers compile in their own backend by registering a provider in init.
+type Provider interface {
+	// Name returns the name of the provider, i.e. the `type` of metrics configuration selecting it.
+	Name() string
+	// Validate checks the metrics configuration, no usage is collected if it fails.
+	Validate(metricsConf map[string]string) error
+	// QueryNodeUsage returns the usages of the node, the averages and maxes are over the periods. The usages
+	// collected are returned along with the error if some of them fail.
+	QueryNodeUsage(ctx context.Context, metricsConf map[string]string, nodeName string, periods []string) (*schedulingapi.NodeUsage, error)
+}
+
+// PodUsageProvider is a provider collecting the usages of pods as well, by which the usage plugin estimates
+// the usages of tasks placed on nodes.
+type PodUsageProvider interface {
+	Provider
+	// QueryPodUsages returns the latest usages of the pods reported by the metrics source, keyed by namespace/name.
+	QueryPodUsages(ctx context.Context, metricsConf map[string]string) (map[string]*schedulingapi.PodUsage, error)
+}
+
+// NodeUsagesProvider is a provider collecting the usages of all nodes at once, e.g. by a single list of the metrics
+// source, instead of querying the nodes one by one.
+type NodeUsagesProvider interface {
+	Provider
+	// QueryNodeUsages returns the usages of the nodes of capacities, which are the capacities of the nodes in the
+	// scheduler cache the usages are percentages of. Nodes not reported by the metrics source are left out.
+	QueryNodeUsages(ctx context.Context, metricsConf map[string]string, capacities map[string]*schedulingapi.Resource, periods []string) (map[string]*schedulingapi.NodeUsage, error)
+}
+
+// PodMetricsClient is a metrics client collecting the usages of pods.
+type PodMetricsClient interface {
+	// PodMetrics returns the latest usages of the pods, keyed by namespace/name.
+	PodMetrics(ctx context.Context) (map[string]*schedulingapi.PodUsage, error)
+}
+
+var providerMutex sync.RWMutex
+
+var providers = map[string]Provider{}
+
+func init() {
+	RegisterProvider(&clientProvider{name: PrometheusProvider, newClient: func(address string, conf map[string]string) (MetricsClient, error) {
+		return NewPrometheusMetricsClient(address, conf)
+	}})
+	RegisterProvider(&clientProvider{name: ElasticsearchProvider, newClient: func(address string, conf map[string]string) (MetricsClient, error) {
+		return NewElasticsearchMetricsClient(address, conf)
+	}})
+	RegisterProvider(&metricsServerProvider{})
+}
+
+// RegisterProvider registers the provider, registering a provider of the same name replaces it.
+func RegisterProvider(provider Provider) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	providers[provider.Name()] = provider
+}
+
+// GetProvider returns the provider of the name.
+func GetProvider(name string) (Provider, bool) {
+	providerMutex.RLock()
+	defer providerMutex.RUnlock()
+
+	provider, found := providers[name]
+	return provider, found
+}
+
+// ProviderNames returns the sorted names of the registered providers.
+func ProviderNames() []string {
+	providerMutex.RLock()
+	defer providerMutex.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled checks whether usages of nodes are collected by the metrics configuration, i.e. it has the address of
+// the metrics source, or the type of a provider without address, e.g. metrics_server.
+func Enabled(metricsConf map[string]string) bool {
+	return len(metricsConf["address"]) != 0 || len(metricsConf["type"]) != 0
+}
+
+// NewProvider returns the provider selected by the `type` of metrics configuration, Prometheus by default,
+// after validating the configuration.
+func NewProvider(metricsConf map[string]string) (Provider, error) {
+	name := metricsConf["type"]
+	if len(name) == 0 {
+		name = PrometheusProvider
+	}
+	provider, found := GetProvider(name)
+	if !found {
+		return nil, fmt.Errorf("unknown metrics type %q, registered types: %s", name, strings.Join(ProviderNames(), ", "))
+	}
+	if err := provider.Validate(metricsConf); err != nil {
+		return nil, fmt.Errorf("invalid metrics configuration of %s: %v", name, err)
+	}
+	return provider, nil
+}
+
+// clientProvider is the provider of a built-in metrics client.
+type clientProvider struct {
+	name      string
+	newClient func(address string, conf map[string]string) (MetricsClient, error)
+
+	mutex sync.Mutex
+	// cached is the client built by conf, it is shared by the queries of all nodes and only rebuilt when the metrics
+	// configuration changes, so that the transport and its connections are reused.
+	conf   map[string]string
+	cached MetricsClient
+}
+
+// idleConnectionsCloser is a metrics client closing the idle connections of its transport once it is replaced.
+type idleConnectionsCloser interface {
+	CloseIdleConnections()
+}
+
+func (cp *clientProvider) Name() string {
+	return cp.name
+}
+
+func (cp *clientProvider) Validate(metricsConf map[string]string) error {
+	_, err := cp.client(metricsConf)
+	return err
+}
+
+func (cp *clientProvider) client(metricsConf map[string]string) (MetricsClient, error) {
+	address := metricsConf["address"]
+	if len(address) == 0 {
+		return nil, errors.New("metrics address is empty")
+	}
+
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if cp.cached != nil && reflect.DeepEqual(cp.conf, metricsConf) {
+		return cp.cached, nil
+	}
+	client, err := cp.newClient(address, metricsConf)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := cp.cached.(idleConnectionsCloser); ok {
+		closer.CloseIdleConnections()
+	}
+	cp.conf = make(map[string]string, len(metricsConf))
+	for key, value := range metricsConf {
+		cp.conf[key] = value
+	}
+	cp.cached = client
+	return client, nil
+}
+
+func (cp *clientProvider) QueryNodeUsage(ctx context.Context, metricsConf map[string]string, nodeName string, periods []string) (*schedulingapi.NodeUsage, error) {
+	client, err := cp.client(metricsConf)
+	if err != nil {
+		return nil, err
+	}
+	return QueryNodeUsage(ctx, client, nodeName, periods)
+}
+
+// QueryPodUsages returns the usages of pods if the metrics client collects them, or nil otherwise.
+func (cp *clientProvider) QueryPodUsages(ctx context.Context, metricsConf map[string]string) (map[string]*schedulingapi.PodUsage, error) {
+	client, err := cp.client(metricsConf)
+	if err != nil {
+		return nil, err
+	}
+	podClient, ok := client.(PodMetricsClient)
+	if !ok {
+		return nil, nil
+	}
+	return podClient.PodMetrics(ctx)
+}
+
+// QueryNodeUsage returns the usages of the node queried by the metrics client, failed averages and maxes
+// of periods are skipped, the error of the latest usages is returned along with the usages collected.
+func QueryNodeUsage(ctx context.Context, client MetricsClient, nodeName string, periods []string) (*schedulingapi.NodeUsage, error) {
+	usage := &schedulingapi.NodeUsage{
+		CPUUsageAvg: make(map[string]float64),
+		MEMUsageAvg: make(map[string]float64),
+		CPUUsageMax: make(map[string]float64),
+		MEMUsageMax: make(map[string]float64),
+
+		GPUUsageAvg:    make(map[string]float64),
+		GPUMEMUsageAvg: make(map[string]float64),
+		GPUUsageMax:    make(map[string]float64),
+		GPUMEMUsageMax: make(map[string]float64),
+	}
+
+	for _, period := range periods {
+		nodeMetrics, err := client.NodeMetricsAvg(ctx, nodeName, period)
+		if err != nil {
+			klog.Errorf("Error getting node metrics: %v\n", err)
+			continue
+		}
+		klog.V(4).Infof("node: %v, CpuUsageAvg: %v, MemUsageAvg: %v, period:%v", nodeName, nodeMetrics.CPU, nodeMetrics.Memory, period)
+		usage.CPUUsageAvg[period] = nodeMetrics.CPU
+		usage.MEMUsageAvg[period] = nodeMetrics.Memory
+		if nodeMetrics.GPUReported {
+			usage.GPUUsageAvg[period] = nodeMetrics.GPU
+			usage.GPUMEMUsageAvg[period] = nodeMetrics.GPUMemory
+		}
+		usage.SampleTime = time.Now()
+
+		nodeMetrics, err = client.NodeMetricsMax(ctx, nodeName, period)
+		if err != nil {
+			klog.Errorf("Error getting node max metrics: %v\n", err)
+			continue
+		}
+		klog.V(4).Infof("node: %v, CpuUsageMax: %v, MemUsageMax: %v, period:%v", nodeName, nodeMetrics.CPU, nodeMetrics.Memory, period)
+		usage.CPUUsageMax[period] = nodeMetrics.CPU
+		usage.MEMUsageMax[period] = nodeMetrics.Memory
+		if nodeMetrics.GPUReported {
+			usage.GPUUsageMax[period] = nodeMetrics.GPU
+			usage.GPUMEMUsageMax[period] = nodeMetrics.GPUMemory
+		}
+	}
+
+	nodeMetrics, err := client.NodeMetricsCommon(ctx, nodeName)
+	if err != nil {
+		return usage, fmt.Errorf("error getting node common metrics: %v", err)
+	}
+	klog.V(4).Infof("node: %v, CpuUsage: %v, MemUsage: %v", nodeName, nodeMetrics.CPU, nodeMetrics.Memory)
+	usage.CPUUsage = nodeMetrics.CPU
+	usage.MEMUsage = nodeMetrics.Memory
+	usage.GPUUsage = nodeMetrics.GPU
+	usage.GPUMEMUsage = nodeMetrics.GPUMemory
+	usage.GPUReported = nodeMetrics.GPUReported
+	return usage, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type fakeProvider struct{}
+
+func (fp *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (fp *fakeProvider) Validate(metricsConf map[string]string) error {
+	if len(metricsConf["fake.database"]) == 0 {
+		return errors.New("fake.database is empty")
+	}
+	return nil
+}
+
+func (fp *fakeProvider) QueryNodeUsage(ctx context.Context, metricsConf map[string]string, nodeName string, periods []string) (*schedulingapi.NodeUsage, error) {
+	return &schedulingapi.NodeUsage{CPUUsage: 10}, nil
+}
+
+type fakeMetricsClient struct {
+	failedPeriod string
+}
+
+func (fc *fakeMetricsClient) NodeMetricsAvg(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
+	if period == fc.failedPeriod {
+		return nil, errors.New("no data")
+	}
+	return &NodeMetrics{CPU: 20, Memory: 30}, nil
+}
+
+func (fc *fakeMetricsClient) NodeMetricsMax(ctx context.Context, nodeName string, period string) (*NodeMetrics, error) {
+	return &NodeMetrics{CPU: 40, Memory: 50, GPU: 60, GPUMemory: 70, GPUReported: true}, nil
+}
+
+func (fc *fakeMetricsClient) NodeMetricsCommon(ctx context.Context, nodeName string) (*NodeMetrics, error) {
+	return nil, errors.New("no data")
+}
+
+func TestNewProvider(t *testing.T) {
+	RegisterProvider(&fakeProvider{})
+	defer func() {
+		providerMutex.Lock()
+		delete(providers, "fake")
+		providerMutex.Unlock()
+	}()
+
+	testCases := []struct {
+		name     string
+		conf     map[string]string
+		expected string
+		hasErr   bool
+	}{
+		{
+			name:     "prometheus by default",
+			conf:     map[string]string{"address": "http://localhost:9090"},
+			expected: PrometheusProvider,
+		},
+		{
+			name:     "elasticsearch",
+			conf:     map[string]string{"type": "elasticsearch", "address": "http://localhost:9200"},
+			expected: ElasticsearchProvider,
+		},
+		{
+			name:   "built-in provider without address",
+			conf:   map[string]string{"type": "prometheus"},
+			hasErr: true,
+		},
+		{
+			name:     "registered provider",
+			conf:     map[string]string{"type": "fake", "fake.database": "usage"},
+			expected: "fake",
+		},
+		{
+			name:   "registered provider with invalid configuration",
+			conf:   map[string]string{"type": "fake"},
+			hasErr: true,
+		},
+		{
+			name:   "unknown provider",
+			conf:   map[string]string{"type": "influxdb", "address": "http://localhost:8086"},
+			hasErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := NewProvider(testCase.conf)
+		if testCase.hasErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got provider %s", testCase.name, provider.Name())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.name, err)
+			continue
+		}
+		if provider.Name() != testCase.expected {
+			t.Errorf("%s: expected provider %s, got %s", testCase.name, testCase.expected, provider.Name())
+		}
+	}
+}
+
+func TestQueryNodeUsage(t *testing.T) {
+	usage, err := QueryNodeUsage(context.TODO(), &fakeMetricsClient{failedPeriod: "1h"}, "n1", []string{"5m", "1h"})
+	if err == nil {
+		t.Errorf("expected error of the latest usages")
+	}
+	if usage.CPUUsageAvg["5m"] != 20 || usage.MEMUsageAvg["5m"] != 30 || usage.CPUUsageMax["5m"] != 40 {
+		t.Errorf("expected usages of 5m collected, got %v", *usage)
+	}
+	if usage.GPUUsageMax["5m"] != 60 || usage.GPUMEMUsageMax["5m"] != 70 {
+		t.Errorf("expected max gpu usages of 5m collected, got %v", *usage)
+	}
+	if _, found := usage.CPUUsageAvg["1h"]; found {
+		t.Errorf("expected usages of failed period 1h skipped, got %v", *usage)
+	}
+	if usage.SampleTime.IsZero() {
+		t.Errorf("expected sample time set")
+	}
+}
+
+func TestClientProviderCachesClient(t *testing.T) {
+	var built int
+	cp := &clientProvider{name: "fake", newClient: func(address string, conf map[string]string) (MetricsClient, error) {
+		built++
+		return &fakeMetricsClient{}, nil
+	}}
+
+	conf := map[string]string{"address": "http://metrics:9090"}
+	for i := 0; i < 3; i++ {
+		cp.QueryNodeUsage(context.TODO(), conf, "n1", []string{"5m"})
+	}
+	if err := cp.Validate(conf); err != nil || built != 1 {
+		t.Errorf("expected the client built once for the same configuration, got %d builds and error %v", built, err)
+	}
+
+	changed := map[string]string{"address": "http://metrics:9091"}
+	cp.QueryNodeUsage(context.TODO(), changed, "n1", []string{"5m"})
+	if built != 2 {
+		t.Errorf("expected the client rebuilt for the changed configuration, got %d builds", built)
+	}
+}
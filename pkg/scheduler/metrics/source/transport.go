@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// tlsInsecureSkipVerifyKey is the key of metrics configuration which skips the verification of server certificates
+	tlsInsecureSkipVerifyKey = "tls.insecureSkipVerify"
+	// tlsCAFileKey is the key of metrics configuration with the CA bundle verifying server certificates
+	tlsCAFileKey = "tls.caFile"
+	// tlsCertFileKey and tlsKeyFileKey are the keys of metrics configuration with the client certificate and key of mTLS
+	tlsCertFileKey = "tls.certFile"
+	tlsKeyFileKey  = "tls.keyFile"
+	// tlsServerNameKey is the key of metrics configuration with the server name verified, the host of address by default
+	tlsServerNameKey = "tls.serverName"
+	// bearerTokenFileKey is the key of metrics configuration with the file of bearer token sent in the Authorization
+	// header, e.g. a service account token
+	bearerTokenFileKey = "auth.bearerTokenFile"
+	// esUsernameKey and esPasswordFileKey are the keys of metrics configuration with the user of elasticsearch and the
+	// file of its password, e.g. mounted from a Secret
+	esUsernameKey     = "elasticsearch.username"
+	esPasswordFileKey = "elasticsearch.passwordFile"
+	// esAPIKeyFileKey is the key of metrics configuration with the file of the base64 encoded API key of elasticsearch
+	esAPIKeyFileKey = "elasticsearch.apiKeyFile"
+	// headerKeyPrefix is the prefix of the keys of metrics configuration with custom HTTP headers,
+	// e.g. `headers.X-Scope-OrgID: tenant`
+	headerKeyPrefix = "headers."
+)
+
+// inlineCredentialKeys are the keys of the credentials in the metrics configuration itself, which are rejected, as
+// the scheduler configuration is kept in a ConfigMap; the credentials are read from the files of the keys they map to.
+var inlineCredentialKeys = map[string]string{
+	"auth.bearerToken":       bearerTokenFileKey,
+	"elasticsearch.password": esPasswordFileKey,
+	"elasticsearch.apiKey":   esAPIKeyFileKey,
+}
+
+// newTransport returns the transport of the HTTP requests to the metrics source by the TLS, authentication
+// and header configuration.
+func newTransport(conf map[string]string) (http.RoundTripper, error) {
+	tlsConfig, err := newTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+
+	headers := newHeaders(conf)
+	credential, err := newCredential(conf)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) != 0 || credential != nil {
+		transport = &headerRoundTripper{headers: headers, credential: credential, next: transport}
+	}
+	return transport, nil
+}
+
+func newTLSConfig(conf map[string]string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: conf[tlsInsecureSkipVerifyKey] == "true",
+		ServerName:         conf[tlsServerNameKey],
+	}
+
+	if caFile := conf[tlsCAFileKey]; len(caFile) != 0 {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", tlsCAFileKey, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in %s %s", tlsCAFileKey, caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certFile, keyFile := conf[tlsCertFileKey], conf[tlsKeyFileKey]
+	if len(certFile) != 0 || len(keyFile) != 0 {
+		if len(certFile) == 0 || len(keyFile) == 0 {
+			return nil, fmt.Errorf("both %s and %s are required for client certificate", tlsCertFileKey, tlsKeyFileKey)
+		}
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		// The client certificate is loaded at each handshake, so that the rotated one is sent.
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %v", err)
+			}
+			return &cert, nil
+		}
+	}
+	return tlsConfig, nil
+}
+
+// newHeaders returns the custom headers.
+func newHeaders(conf map[string]string) http.Header {
+	headers := http.Header{}
+	for key, value := range conf {
+		if name := strings.TrimPrefix(key, headerKeyPrefix); name != key && len(name) != 0 {
+			headers.Set(name, value)
+		}
+	}
+	return headers
+}
+
+// credential is the Authorization header of the requests, whose secret is read from file at each request, so that
+// the rotated credentials, e.g. of a projected service account token or a mounted Secret, are sent.
+type credential struct {
+	scheme string
+	file   string
+	// username is the user of the basic authentication, whose password is in file
+	username string
+}
+
+// newCredential returns the credential of the metrics configuration, nil if none. Only one of the bearer token,
+// the API key of elasticsearch and the password of elasticsearch is allowed.
+func newCredential(conf map[string]string) (*credential, error) {
+	for key, fileKey := range inlineCredentialKeys {
+		if len(conf[key]) != 0 {
+			return nil, fmt.Errorf("%s is not allowed in the scheduler configuration, set %s to the file of the "+
+				"credential instead, e.g. mounted from a Secret", key, fileKey)
+		}
+	}
+
+	var credentials []*credential
+	if file := conf[bearerTokenFileKey]; len(file) != 0 {
+		credentials = append(credentials, &credential{scheme: "Bearer", file: file})
+	}
+	if file := conf[esAPIKeyFileKey]; len(file) != 0 {
+		credentials = append(credentials, &credential{scheme: "APIKey", file: file})
+	}
+	if file := conf[esPasswordFileKey]; len(file) != 0 {
+		if len(conf[esUsernameKey]) == 0 {
+			return nil, fmt.Errorf("%s is required with %s", esUsernameKey, esPasswordFileKey)
+		}
+		credentials = append(credentials, &credential{scheme: "Basic", file: file, username: conf[esUsernameKey]})
+	}
+	if len(credentials) == 0 {
+		return nil, nil
+	}
+	if len(credentials) > 1 {
+		return nil, fmt.Errorf("only one of %s, %s and %s is allowed", bearerTokenFileKey, esAPIKeyFileKey, esPasswordFileKey)
+	}
+	// The credential file is checked once the configuration is loaded.
+	if _, err := credentials[0].header(); err != nil {
+		return nil, err
+	}
+	return credentials[0], nil
+}
+
+// header returns the Authorization header by the secret in the file of credential.
+func (c *credential) header() (string, error) {
+	content, err := os.ReadFile(c.file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential file %s: %v", c.file, err)
+	}
+	secret := strings.TrimSpace(string(content))
+	if len(secret) == 0 {
+		return "", fmt.Errorf("credential file %s is empty", c.file)
+	}
+	if c.scheme == "Basic" {
+		secret = base64.StdEncoding.EncodeToString([]byte(c.username + ":" + secret))
+	}
+	return c.scheme + " " + secret, nil
+}
+
+// headerRoundTripper sets the headers of the requests.
+type headerRoundTripper struct {
+	headers    http.Header
+	credential *credential
+	next       http.RoundTripper
+}
+
+func (hrt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range hrt.headers {
+		req.Header[name] = values
+	}
+	if hrt.credential != nil {
+		authorization, err := hrt.credential.header()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", authorization)
+	}
+	return hrt.next.RoundTrip(req)
+}
+
+// closeIdleConnections closes the idle connections of the transport returned by newTransport.
+func closeIdleConnections(transport http.RoundTripper) {
+	if hrt, ok := transport.(*headerRoundTripper); ok {
+		transport = hrt.next
+	}
+	if t, ok := transport.(*http.Transport); ok {
+		t.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrometheusMetricsClientAuth(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Scope-OrgID") != "tenant" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"instance":"n1"},"value":[1684000000,"50"]}]}}`)
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatalf("failed to write ca: %v", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatalf("failed to write token: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		conf     map[string]string
+		expected float64
+	}{
+		{
+			name:     "ca bundle and bearer token file",
+			conf:     map[string]string{"tls.caFile": caFile, "auth.bearerTokenFile": tokenFile, "headers.X-Scope-OrgID": "tenant"},
+			expected: 50,
+		},
+		{
+			name:     "insecure skip verify and bearer token file",
+			conf:     map[string]string{"tls.insecureSkipVerify": "true", "auth.bearerTokenFile": tokenFile, "headers.X-Scope-OrgID": "tenant"},
+			expected: 50,
+		},
+		{
+			name: "unknown certificate authority",
+			conf: map[string]string{"auth.bearerTokenFile": tokenFile, "headers.X-Scope-OrgID": "tenant"},
+		},
+		{
+			name: "no header",
+			conf: map[string]string{"tls.caFile": caFile, "auth.bearerTokenFile": tokenFile},
+		},
+	}
+
+	for _, testCase := range testCases {
+		client, err := NewPrometheusMetricsClient(server.URL, testCase.conf)
+		if err != nil {
+			t.Errorf("%s: failed to create client: %v", testCase.name, err)
+			continue
+		}
+		nodeMetrics, err := client.NodeMetricsCommon(context.TODO(), "n1")
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.name, err)
+			continue
+		}
+		if nodeMetrics.CPU != testCase.expected {
+			t.Errorf("%s: expected cpu %v, got %v", testCase.name, testCase.expected, nodeMetrics.CPU)
+		}
+	}
+}
+
+func TestCredentialRotation(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("old"), 0600); err != nil {
+		t.Fatalf("failed to write password: %v", err)
+	}
+	transport, err := newTransport(map[string]string{"elasticsearch.username": "volcano", "elasticsearch.passwordFile": passwordFile})
+	if err != nil {
+		t.Fatalf("failed to create transport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for _, password := range []string{"old", "new"} {
+		if err := os.WriteFile(passwordFile, []byte(password), 0600); err != nil {
+			t.Fatalf("failed to write password: %v", err)
+		}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		resp.Body.Close()
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.SetBasicAuth("volcano", password)
+		if expected := req.Header.Get("Authorization"); authorization != expected {
+			t.Errorf("expected authorization %q, got %q", expected, authorization)
+		}
+	}
+}
+
+func TestNewTransportInvalid(t *testing.T) {
+	dir := t.TempDir()
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		conf map[string]string
+	}{
+		{name: "missing ca file", conf: map[string]string{"tls.caFile": filepath.Join(dir, "missing")}},
+		{name: "no certificate in ca file", conf: map[string]string{"tls.caFile": emptyFile}},
+		{name: "client certificate without key", conf: map[string]string{"tls.certFile": emptyFile}},
+		{name: "invalid client certificate", conf: map[string]string{"tls.certFile": emptyFile, "tls.keyFile": emptyFile}},
+		{name: "inline bearer token", conf: map[string]string{"auth.bearerToken": "secret"}},
+		{name: "inline elasticsearch password", conf: map[string]string{"elasticsearch.username": "volcano", "elasticsearch.password": "secret"}},
+		{name: "inline elasticsearch api key", conf: map[string]string{"elasticsearch.apiKey": "a2V5"}},
+		{name: "bearer token and api key files", conf: map[string]string{"auth.bearerTokenFile": tokenFile, "elasticsearch.apiKeyFile": tokenFile}},
+		{name: "password file without username", conf: map[string]string{"elasticsearch.passwordFile": tokenFile}},
+		{name: "empty bearer token file", conf: map[string]string{"auth.bearerTokenFile": emptyFile}},
+		{name: "missing bearer token file", conf: map[string]string{"auth.bearerTokenFile": filepath.Join(dir, "missing")}},
+	}
+
+	for _, testCase := range testCases {
+		if _, err := newTransport(testCase.conf); err == nil {
+			t.Errorf("%s: expected error", testCase.name)
+		}
+	}
+}
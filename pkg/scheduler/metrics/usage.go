@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto" // auto-registry collectors in default registry
+)
+
+var (
+	nodeUsage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_usage_percentage",
+			Help:      "Usage of one node by resource and period seen by the usage plugin in the latest scheduling session",
+		}, []string{"node_name", "resource", "period"},
+	)
+
+	nodeUsageOverThreshold = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_usage_over_threshold",
+			Help:      "Whether the usage of one node by resource and period is over the threshold of the usage plugin",
+		}, []string{"node_name", "resource", "period"},
+	)
+
+	usagePredicateRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "usage_predicate_rejections_total",
+			Help:      "Number of times one node is filtered out by the usage threshold of resource and period",
+		}, []string{"node_name", "resource", "period"},
+	)
+)
+
+// UpdateNodeUsage records the usage of one node by resource and period in percentage
+func UpdateNodeUsage(nodeName, resource, period string, usage float64) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	nodeUsage.WithLabelValues(nodeName, resource, period).Set(usage)
+}
+
+// UpdateNodeUsageOverThreshold records whether the usage of one node by resource and period is over the threshold
+func UpdateNodeUsageOverThreshold(nodeName, resource, period string, over bool) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	value := 0.0
+	if over {
+		value = 1
+	}
+	nodeUsageOverThreshold.WithLabelValues(nodeName, resource, period).Set(value)
+}
+
+// ResetNodeUsage clears the usages of nodes recorded by the previous session
+func ResetNodeUsage() {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	nodeUsage.Reset()
+	nodeUsageOverThreshold.Reset()
+}
+
+// RegisterUsagePredicateRejection records one node is filtered out by the usage threshold of resource and period
+func RegisterUsagePredicateRejection(nodeName, resource, period string) {
+	if SessionMetricsSuppressed() {
+		return
+	}
+	usagePredicateRejections.WithLabelValues(nodeName, resource, period).Inc()
+}
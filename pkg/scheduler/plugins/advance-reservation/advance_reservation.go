@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package advancereservation
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// PluginName indicates name of volcano scheduler plugin.
+const PluginName = "advance-reservation"
+
+/*
+   actions: "enqueue, allocate, backfill"
+   tiers:
+   - plugins:
+     - name: advance-reservation
+*/
+
+// fence keeps the capacity of the nodes of an active advance reservation from the jobs of other queues.
+type fence struct {
+	reservation *AdvanceReservation
+	nodes       map[string]bool
+	reserved    *api.Resource
+	// idle is the idle resource of the nodes, used is the resource allocated to the jobs of the owner queue on them
+	idle map[v1.ResourceName]float64
+	used map[v1.ResourceName]float64
+}
+
+// remaining returns the reserved resource not used by the jobs of the owner queue yet.
+func (f *fence) remaining(rn v1.ResourceName) float64 {
+	if remaining := f.reserved.Get(rn) - f.used[rn]; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// admits checks whether the nodes keep the remaining reserved resource idle after the request of other queue
+// is allocated on them, the resources not requested are not checked.
+func (f *fence) admits(request *api.Resource) bool {
+	for _, rn := range f.reserved.ResourceNames() {
+		if value := request.Get(rn); value > 0 && f.idle[rn]-value < f.remaining(rn) {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds the request allocated on the nodes, sign is -1 if it is released.
+func (f *fence) add(request *api.Resource, owned bool, sign float64) {
+	for _, rn := range f.reserved.ResourceNames() {
+		f.idle[rn] -= sign * request.Get(rn)
+		if owned {
+			f.used[rn] += sign * request.Get(rn)
+		}
+	}
+}
+
+type advanceReservationPlugin struct {
+	jobs   map[api.JobID]*api.JobInfo
+	fences []*fence
+}
+
+// New return advance reservation plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &advanceReservationPlugin{}
+}
+
+func (ap *advanceReservationPlugin) Name() string {
+	return PluginName
+}
+
+// queueOf returns the queue of the job of task, it is empty for the pods out of the jobs of the session.
+func (ap *advanceReservationPlugin) queueOf(task *api.TaskInfo) string {
+	if job, found := ap.jobs[task.Job]; found {
+		return string(job.Queue)
+	}
+	return ""
+}
+
+// buildFences builds the fences of the reservations active at now on the nodes of the session.
+func (ap *advanceReservationPlugin) buildFences(reservations []*AdvanceReservation, nodes map[string]*api.NodeInfo,
+	now time.Time) {
+	ap.fences = nil
+	for _, ar := range reservations {
+		if !ar.Active(now) {
+			continue
+		}
+		f := &fence{
+			reservation: ar,
+			nodes:       map[string]bool{},
+			reserved:    api.NewResource(ar.Spec.Resources),
+			idle:        map[v1.ResourceName]float64{},
+			used:        map[v1.ResourceName]float64{},
+		}
+		selector := labels.SelectorFromSet(ar.Spec.NodeSelector)
+		for name, node := range nodes {
+			if node.Node == nil || !selector.Matches(labels.Set(node.Node.Labels)) {
+				continue
+			}
+			f.nodes[name] = true
+			for _, rn := range f.reserved.ResourceNames() {
+				f.idle[rn] += node.Idle.Get(rn)
+			}
+			for _, task := range node.Tasks {
+				if api.AllocatedStatus(task.Status) && ap.queueOf(task) == ar.Spec.Queue {
+					for _, rn := range f.reserved.ResourceNames() {
+						f.used[rn] += task.Resreq.Get(rn)
+					}
+				}
+			}
+		}
+		klog.V(4).Infof("Advance reservation %s fences %v on %d nodes for queue %s, used %v, idle %v",
+			ar.Name, f.reserved, len(f.nodes), ar.Spec.Queue, f.used, f.idle)
+		ap.fences = append(ap.fences, f)
+	}
+}
+
+// violatedFence returns the fence the task of other queue violates if it is allocated on node, or nil.
+func (ap *advanceReservationPlugin) violatedFence(task *api.TaskInfo, node string) *fence {
+	queue := ap.queueOf(task)
+	for _, f := range ap.fences {
+		if f.nodes[node] && f.reservation.Spec.Queue != queue && !f.admits(task.Resreq) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (ap *advanceReservationPlugin) OnSessionOpen(ssn *framework.Session) {
+	ap.jobs = ssn.Jobs
+	ap.buildFences(watcher.get(ssn.ClientConfig()), ssn.Nodes, time.Now())
+	if len(ap.fences) == 0 {
+		return
+	}
+
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if f := ap.violatedFence(task, node.Name); f != nil {
+			return []*api.Status{{Code: api.Unschedulable, Reason: api.NodeReservedByAdvanceReservation}},
+				fmt.Errorf("plugin %s predicates failed, capacity of node %s is reserved by %s for queue %s",
+					ap.Name(), node.Name, f.reservation.Name, f.reservation.Spec.Queue)
+		}
+		return nil, nil
+	}
+	ssn.AddPredicateFn(ap.Name(), predicateFn)
+
+	// The predicate results may be reused by the siblings of a task while the idle resource of the other fenced
+	// nodes changes, so the allocation is checked again. A task restored after its eviction is discarded, which
+	// is running again, can not be rejected.
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			task := event.Task
+			if f := ap.violatedFence(task, task.NodeName); f != nil && task.Status != api.Running {
+				event.Err = fmt.Errorf("capacity of node %s is reserved by %s for queue %s",
+					task.NodeName, f.reservation.Name, f.reservation.Spec.Queue)
+				return
+			}
+			for _, f := range ap.fences {
+				if f.nodes[task.NodeName] {
+					f.add(task.Resreq, ap.queueOf(task) == f.reservation.Spec.Queue, 1)
+				}
+			}
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			task := event.Task
+			for _, f := range ap.fences {
+				if f.nodes[task.NodeName] {
+					f.add(task.Resreq, ap.queueOf(task) == f.reservation.Spec.Queue, -1)
+				}
+			}
+		},
+	})
+}
+
+// recordCapacityReductions records the remaining reserved cpu and memory of the fences as the capacity of the nodes
+// kept from tasks, each node keeps the share of the fences on it by its allocatable.
+func (ap *advanceReservationPlugin) recordCapacityReductions(ssn *framework.Session) {
+	reductions := map[string]*api.Resource{}
+	for _, f := range ap.fences {
+		total := api.EmptyResource()
+		for name := range f.nodes {
+			total.MilliCPU += ssn.Nodes[name].Allocatable.MilliCPU
+			total.Memory += ssn.Nodes[name].Allocatable.Memory
+		}
+		for name := range f.nodes {
+			allocatable := ssn.Nodes[name].Allocatable
+			if reductions[name] == nil {
+				reductions[name] = api.EmptyResource()
+			}
+			if total.MilliCPU > 0 {
+				reductions[name].MilliCPU += f.remaining(v1.ResourceCPU) * allocatable.MilliCPU / total.MilliCPU
+			}
+			if total.Memory > 0 {
+				reductions[name].Memory += f.remaining(v1.ResourceMemory) * allocatable.Memory / total.Memory
+			}
+		}
+	}
+	for name, reduced := range reductions {
+		if reduced.MilliCPU > 0 || reduced.Memory > 0 {
+			ssn.RecordNodeCapacityReduction(name, metrics.NodeReducedReservation, reduced)
+		}
+	}
+}
+
+func (ap *advanceReservationPlugin) OnSessionClose(ssn *framework.Session) {
+	ap.recordCapacityReductions(ssn)
+	ap.jobs = nil
+	ap.fences = nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package advancereservation
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func buildReservation(name, queue, start, end string) *unstructured.Unstructured {
+	reservation := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "scheduling.volcano.sh/v1beta1",
+		"kind":       "AdvanceReservation",
+		"spec": map[string]interface{}{
+			"queue":        queue,
+			"nodeSelector": map[string]interface{}{"pool": "gpu"},
+			"resources":    map[string]interface{}{"cpu": "64", "nvidia.com/gpu": "8"},
+			"startTime":    start,
+			"endTime":      end,
+		},
+	}}
+	reservation.SetName(name)
+	return reservation
+}
+
+// watchReservations watches the reservations by rw until stopCh is closed.
+func watchReservations(t *testing.T, rw *reservationWatcher, stopCh <-chan struct{}, reservations ...runtime.Object) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{advanceReservationResource: "AdvanceReservationList"}, reservations...)
+	rw.watch(client, stopCh)
+	if !cache.WaitForCacheSync(stopCh, rw.informer.HasSynced) {
+		t.Fatalf("failed to sync advance reservations")
+	}
+}
+
+func TestReservationWatcher(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	rw := &reservationWatcher{started: true}
+	if reservations := rw.get(nil); reservations != nil {
+		t.Errorf("expected no reservations before they are watched, got %v", reservations)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	watchReservations(t, rw, stopCh,
+		buildReservation("experiment", "research", "2023-01-01T08:00:00Z", "2023-01-01T20:00:00Z"),
+		buildReservation("no-queue", "", "2023-01-01T08:00:00Z", "2023-01-01T20:00:00Z"),
+		buildReservation("reversed", "research", "2023-01-01T20:00:00Z", "2023-01-01T08:00:00Z"))
+
+	reservations := rw.get(nil)
+	if len(reservations) != 1 || reservations[0].Name != "experiment" {
+		t.Fatalf("expected only valid reservation experiment, got %v", reservations)
+	}
+	ar := reservations[0]
+	if gpu := ar.Spec.Resources["nvidia.com/gpu"]; gpu.Value() != 8 || ar.Spec.NodeSelector["pool"] != "gpu" {
+		t.Errorf("expected 8 gpus reserved on pool gpu, got %v on %v", ar.Spec.Resources, ar.Spec.NodeSelector)
+	}
+	if ar.Active(now) || !ar.Active(now.Add(8*time.Hour)) || ar.Active(now.Add(20*time.Hour)) {
+		t.Errorf("expected reservation active from 08:00 to 20:00, got window %v - %v", ar.Spec.StartTime, ar.Spec.EndTime)
+	}
+}
+
+func TestAdvanceReservation(t *testing.T) {
+	now := time.Now()
+	reservation := func(start, end time.Time) *AdvanceReservation {
+		return &AdvanceReservation{
+			ObjectMeta: metav1.ObjectMeta{Name: "experiment"},
+			Spec: AdvanceReservationSpec{
+				Queue:        "owner",
+				NodeSelector: map[string]string{"pool": "gpu"},
+				Resources:    util.BuildResourceList("2", "0"),
+				StartTime:    metav1.NewTime(start),
+				EndTime:      metav1.NewTime(end),
+			},
+		}
+	}
+	defer func() {
+		watcher = &reservationWatcher{}
+	}()
+
+	tests := []struct {
+		name        string
+		queue       string
+		reservation *AdvanceReservation
+		expected    int
+	}{
+		{
+			name:        "other queue keeps reserved capacity idle",
+			queue:       "other",
+			reservation: reservation(now.Add(-time.Hour), now.Add(time.Hour)),
+			expected:    1,
+		},
+		{
+			name:        "owner queue uses reserved capacity",
+			queue:       "owner",
+			reservation: reservation(now.Add(-time.Hour), now.Add(time.Hour)),
+			expected:    2,
+		},
+		{
+			name:        "reservation out of window",
+			queue:       "other",
+			reservation: reservation(now.Add(time.Hour), now.Add(2*time.Hour)),
+			expected:    2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.reservation)
+			if err != nil {
+				t.Fatalf("failed to convert reservation: %v", err)
+			}
+			reservation := &unstructured.Unstructured{Object: object}
+			reservation.SetAPIVersion(advanceReservationResource.GroupVersion().String())
+			reservation.SetKind("AdvanceReservation")
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			watcher = &reservationWatcher{started: true}
+			watchReservations(t, watcher, stopCh, reservation)
+
+			c := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				PodGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", test.queue, 1, schedulingv1.PodGroupInqueue)},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("2", "1G"), "pg1", nil, nil),
+					util.BuildPod("ns", "p2", "", v1.PodPending, util.BuildResourceList("2", "1G"), "pg1", nil, nil),
+				},
+				Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("4", "8G"), map[string]string{"pool": "gpu"})},
+				Queues: []*schedulingv1.Queue{util.BuildQueue("owner", 1, nil), util.BuildQueue("other", 1, nil)},
+			}
+			defer c.Close()
+
+			c.Run(allocate.New())
+			if binds := c.Cache().Binds(); len(binds) != test.expected {
+				t.Errorf("expected %d tasks bound, got %v", test.expected, binds)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package advancereservation
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// refreshPeriod is the period of checking whether the CRD of advance reservations is installed until it is
+	refreshPeriod = 30 * time.Second
+	// discoveryTimeout bounds the requests checking whether the CRD of advance reservations is installed
+	discoveryTimeout = 10 * time.Second
+)
+
+var advanceReservationResource = schema.GroupVersionResource{
+	Group:    "scheduling.volcano.sh",
+	Version:  "v1beta1",
+	Resource: "advancereservations",
+}
+
+// AdvanceReservation reserves the capacity of a group of nodes for the jobs of a queue in a time window.
+//
+// The type belongs to volcano.sh/apis along with the other types of scheduling.volcano.sh, whose generated clients
+// and informers are shared by the scheduler cache. Until it is added there, it is defined here and watched by a
+// dynamic informer.
+type AdvanceReservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AdvanceReservationSpec `json:"spec"`
+}
+
+// AdvanceReservationSpec is the specification of an advance reservation.
+type AdvanceReservationSpec struct {
+	// Queue is the owner queue of the reservation, only its jobs may use the reserved capacity.
+	Queue string `json:"queue"`
+	// NodeSelector selects the nodes the capacity is reserved on, all nodes if it is empty.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Resources is the capacity reserved on the selected nodes in total.
+	Resources v1.ResourceList `json:"resources"`
+	// StartTime and EndTime are the window the capacity is reserved in.
+	StartTime metav1.Time `json:"startTime"`
+	EndTime   metav1.Time `json:"endTime"`
+}
+
+// Active checks whether now is inside the window of the reservation.
+func (ar *AdvanceReservation) Active(now time.Time) bool {
+	return !now.Before(ar.Spec.StartTime.Time) && now.Before(ar.Spec.EndTime.Time)
+}
+
+// reservationWatcher watches the advance reservations by an informer, which is started in the background once the
+// CRD is found to be installed, so the sessions never wait for the apiserver.
+type reservationWatcher struct {
+	sync.Mutex
+	started bool
+	// informer watches the advance reservations, nil until the CRD is found to be installed
+	informer cache.SharedIndexInformer
+}
+
+var watcher = &reservationWatcher{}
+
+// get returns the valid advance reservations from the informer cache, it is empty if the CRD is not installed or
+// the reservations are not synced yet. The informer is started in the background by the first call with config.
+func (rw *reservationWatcher) get(config *rest.Config) []*AdvanceReservation {
+	rw.Lock()
+	defer rw.Unlock()
+
+	if !rw.started && config != nil {
+		rw.started = true
+		go rw.discover(config, wait.NeverStop)
+	}
+	if rw.informer == nil || !rw.informer.HasSynced() {
+		return nil
+	}
+
+	var reservations []*AdvanceReservation
+	for _, obj := range rw.informer.GetStore().List() {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		ar := &AdvanceReservation{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, ar); err != nil {
+			klog.Warningf("Invalid advance reservation %s: %v", item.GetName(), err)
+			continue
+		}
+		if len(ar.Spec.Queue) == 0 || !ar.Spec.StartTime.Before(&ar.Spec.EndTime) {
+			klog.Warningf("Invalid advance reservation %s: queue is required and start time must be before end time",
+				ar.Name)
+			continue
+		}
+		reservations = append(reservations, ar)
+	}
+	return reservations
+}
+
+// discover checks whether the CRD of advance reservations is installed every refreshPeriod, each check bounded by
+// discoveryTimeout, and starts the informer of advance reservations once it is.
+func (rw *reservationWatcher) discover(config *rest.Config, stopCh <-chan struct{}) {
+	config = rest.CopyConfig(config)
+	config.Timeout = discoveryTimeout
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		klog.V(4).Infof("Failed to create client of advance reservations: %v", err)
+		return
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		klog.V(4).Infof("Failed to create discovery client of advance reservations: %v", err)
+		return
+	}
+
+	wait.Until(func() {
+		if rw.watching() {
+			return
+		}
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(advanceReservationResource.GroupVersion().String())
+		if err != nil {
+			klog.V(4).Infof("Advance reservations are not available: %v", err)
+			return
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == advanceReservationResource.Resource {
+				rw.watch(client, stopCh)
+				return
+			}
+		}
+	}, refreshPeriod, stopCh)
+}
+
+func (rw *reservationWatcher) watching() bool {
+	rw.Lock()
+	defer rw.Unlock()
+	return rw.informer != nil
+}
+
+// watch starts the informer of advance reservations until stopCh is closed.
+func (rw *reservationWatcher) watch(client dynamic.Interface, stopCh <-chan struct{}) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, advanceReservationResource, metav1.NamespaceAll, 0,
+		cache.Indexers{}, nil).Informer()
+	rw.Lock()
+	rw.informer = informer
+	rw.Unlock()
+	go informer.Run(stopCh)
+}
@@ -45,6 +45,10 @@ const (
 	BinpackResources = "binpack.resources"
 	// BinpackResourcesPrefix is the key prefix for additional resource key name
 	BinpackResourcesPrefix = BinpackResources + "."
+	// BinpackDaemonWeight is the key for the weight in [0, 1] of the requests of DaemonSet and static pods, 1 by
+	// default. The rest of these requests is left out of both the used and the allocatable resources of nodes,
+	// e.g. 0 packs nodes by the requests of workloads only.
+	BinpackDaemonWeight = "binpack.daemonWeight"
 
 	resourceFmt = "%s[%d]"
 )
@@ -54,10 +58,11 @@ type priorityWeight struct {
 	BinPackingCPU       int
 	BinPackingMemory    int
 	BinPackingResources map[v1.ResourceName]int
+	BinPackingDaemon    float64
 }
 
 func (w *priorityWeight) String() string {
-	length := 3
+	length := 4
 	if extendLength := len(w.BinPackingResources); extendLength == 0 {
 		length++
 	} else {
@@ -68,6 +73,7 @@ func (w *priorityWeight) String() string {
 		fmt.Sprintf(resourceFmt, BinpackWeight, w.BinPackingWeight),
 		fmt.Sprintf(resourceFmt, BinpackCPU, w.BinPackingCPU),
 		fmt.Sprintf(resourceFmt, BinpackMemory, w.BinPackingMemory),
+		fmt.Sprintf("%s[%v]", BinpackDaemonWeight, w.BinPackingDaemon),
 	)
 
 	if len(w.BinPackingResources) == 0 {
@@ -107,6 +113,7 @@ func calculateWeight(args framework.Arguments) priorityWeight {
 	         binpack.resources: nvidia.com/gpu, example.com/foo
 	         binpack.resources.nvidia.com/gpu: 2
 	         binpack.resources.example.com/foo: 3
+	         binpack.daemonWeight: 0.5
 	*/
 	// Values are initialized to 1.
 	weight := priorityWeight{
@@ -114,6 +121,7 @@ func calculateWeight(args framework.Arguments) priorityWeight {
 		BinPackingCPU:       1,
 		BinPackingMemory:    1,
 		BinPackingResources: make(map[v1.ResourceName]int),
+		BinPackingDaemon:    1,
 	}
 
 	// Checks whether binpack.weight is provided or not, if given, modifies the value in weight struct.
@@ -151,6 +159,12 @@ func calculateWeight(args framework.Arguments) priorityWeight {
 		weight.BinPackingResources[v1.ResourceName(resource)] = resourceWeight
 	}
 
+	// Checks whether binpack.daemonWeight is provided or not, if given, modifies the value in weight struct.
+	args.GetFloat64(&weight.BinPackingDaemon, BinpackDaemonWeight)
+	if weight.BinPackingDaemon < 0 || weight.BinPackingDaemon > 1 {
+		weight.BinPackingDaemon = 1
+	}
+
 	weight.BinPackingResources[v1.ResourceCPU] = weight.BinPackingCPU
 	weight.BinPackingResources[v1.ResourceMemory] = weight.BinPackingMemory
 
@@ -217,6 +231,12 @@ func BinPackingScore(task *api.TaskInfo, node *api.NodeInfo, weight priorityWeig
 		}
 		allocate := allocatable.Get(resource)
 		nodeUsed := used.Get(resource)
+		// The requests of DaemonSet and static pods left out by their weight are overhead of the node.
+		if node.DaemonUsed != nil && weight.BinPackingDaemon < 1 {
+			daemonUsed := node.DaemonUsed.Get(resource) * (1 - weight.BinPackingDaemon)
+			allocate -= daemonUsed
+			nodeUsed -= daemonUsed
+		}
 
 		resourceWeight, found := weight.BinPackingResources[resource]
 		if !found {
@@ -51,6 +51,7 @@ func TestArguments(t *testing.T) {
 		"binpack.resources":                 "nvidia.com/gpu, example.com/foo",
 		"binpack.resources.nvidia.com/gpu":  7,
 		"binpack.resources.example.com/foo": -3,
+		"binpack.daemonWeight":              0.5,
 	}
 
 	builder, ok := framework.GetPluginBuilder(PluginName)
@@ -74,6 +75,9 @@ func TestArguments(t *testing.T) {
 	if weight.BinPackingMemory != 2 {
 		t.Errorf("memory should be 2, but not %v", weight.BinPackingMemory)
 	}
+	if weight.BinPackingDaemon != 0.5 {
+		t.Errorf("daemon weight should be 0.5, but not %v", weight.BinPackingDaemon)
+	}
 	for name, weight := range weight.BinPackingResources {
 		switch name {
 		case "nvidia.com/gpu":
@@ -98,6 +102,40 @@ func TestArguments(t *testing.T) {
 	}
 }
 
+func TestDaemonWeight(t *testing.T) {
+	controller := true
+	daemon := util.BuildPod("c1", "ds", "n1", v1.PodRunning, util.BuildResourceList("1", "1Gi"), "", nil, nil)
+	daemon.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &controller}}
+	node := api.NewNodeInfo(util.BuildNode("n1", util.BuildResourceList("4", "8Gi"), nil))
+	for _, pod := range []*v1.Pod{daemon, util.BuildPod("c1", "p0", "n1", v1.PodRunning, util.BuildResourceList("1", "1Gi"), "pg1", nil, nil)} {
+		if err := node.AddTask(api.NewTaskInfo(pod)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	task := api.NewTaskInfo(util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1Gi"), "pg1", nil, nil))
+
+	tests := []struct {
+		name     string
+		weight   interface{}
+		expected float64
+	}{
+		// cpu (1+2)/4 and memory (1+2)/8
+		{name: "requests of daemonset pods counted by default", expected: 56.25},
+		// cpu (1+1)/3 and memory (1+1)/7
+		{name: "requests of daemonset pods left out", weight: 0, expected: (2.0/3 + 2.0/7) / 2 * 100},
+		{name: "invalid weight", weight: -1, expected: 56.25},
+	}
+	for _, test := range tests {
+		args := framework.Arguments{}
+		if test.weight != nil {
+			args[BinpackDaemonWeight] = test.weight
+		}
+		if score := BinPackingScore(task, node, calculateWeight(args)); math.Abs(score-test.expected) > eps {
+			t.Errorf("%s: expected score %v, got %v", test.name, test.expected, score)
+		}
+	}
+}
+
 func addResource(resourceList v1.ResourceList, name v1.ResourceName, need string) {
 	resourceList[name] = resource.MustParse(need)
 }
@@ -41,11 +41,14 @@ const (
 )
 
 type CooldownProtectionPlugin struct {
+	starvation starvationConfig
+	// releasing are the tasks releasing when the session is opened
+	releasing map[api.TaskID]bool
 }
 
 // New return CooldownProtectionPlugin
 func New(arguments framework.Arguments) framework.Plugin {
-	return &CooldownProtectionPlugin{}
+	return &CooldownProtectionPlugin{starvation: parseStarvationConfig(arguments)}
 }
 
 // Name implements framework.Plugin
@@ -72,9 +75,13 @@ func (sp *CooldownProtectionPlugin) podCooldownTime(pod *v1.Pod) (value time.Dur
 
 // OnSessionOpen implements framework.Plugin
 func (sp *CooldownProtectionPlugin) OnSessionOpen(ssn *framework.Session) {
+	sp.releasing = releasingTasks(ssn)
+	sp.starvation.boostStarvingJobs(ssn, time.Now())
+
 	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) ([]*api.TaskInfo, int) {
 		var victims []*api.TaskInfo
-		for _, preemptee := range preemptees {
+		now := time.Now()
+		for _, preemptee := range sp.starvation.starvationVictims(ssn.Jobs, minRuntimeVictims(ssn.Jobs, ssn.OtherProfileJobs(), preemptees, now), now) {
 			cooldownTime, enabled := sp.podCooldownTime(preemptee.Pod)
 			if !enabled {
 				victims = append(victims, preemptee)
@@ -104,9 +111,26 @@ func (sp *CooldownProtectionPlugin) OnSessionOpen(ssn *framework.Session) {
 		return victims, util.Permit
 	}
 
+	reclaimableFn := func(reclaimer *api.TaskInfo, reclaimees []*api.TaskInfo) ([]*api.TaskInfo, int) {
+		now := time.Now()
+		victims := sp.starvation.starvationVictims(ssn.Jobs, minRuntimeVictims(ssn.Jobs, ssn.OtherProfileJobs(), reclaimees, now), now)
+		// Abstain if no reclaimee is protected, so the plugins of lower tiers still decide.
+		if len(victims) == len(reclaimees) {
+			return victims, util.Abstain
+		}
+		klog.V(4).Infof("Reclaim victims from cdp plugins are %+v", victims)
+		return victims, util.Permit
+	}
+
 	klog.V(4).Info("plugin cdp session open")
 	ssn.AddPreemptableFn(sp.Name(), preemptableFn)
+	ssn.AddReclaimableFn(sp.Name(), reclaimableFn)
 }
 
 // OnSessionClose implements framework.Plugin
-func (*CooldownProtectionPlugin) OnSessionClose(ssn *framework.Session) {}
+func (sp *CooldownProtectionPlugin) OnSessionClose(ssn *framework.Session) {
+	now := time.Now()
+	updateProtectedCondition(ssn, now)
+	sp.starvation.updateEvictedCondition(ssn, sp.releasing, now)
+	sp.releasing = nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// jobStartTime returns when job started running, i.e. when the running task completing its minAvailable
+// started. It returns false if fewer than minAvailable tasks of job are running.
+func jobStartTime(job *api.JobInfo) (time.Time, bool) {
+	var starts []time.Time
+	for _, task := range job.TaskStatusIndex[api.Running] {
+		if task.Pod != nil && task.Pod.Status.StartTime != nil {
+			starts = append(starts, task.Pod.Status.StartTime.Time)
+		}
+	}
+	minAvailable := int(job.MinAvailable)
+	if minAvailable < 1 {
+		minAvailable = 1
+	}
+	if len(starts) < minAvailable {
+		return time.Time{}, false
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i].Before(starts[j])
+	})
+	return starts[minAvailable-1], true
+}
+
+// protectedUntil returns until when the tasks of job are protected from preemption and reclaim by the
+// scheduling.volcano.sh/min-runtime annotation of its podgroup, it returns false if job is not protected.
+func protectedUntil(job *api.JobInfo) (time.Time, bool) {
+	if job.PodGroup == nil {
+		return time.Time{}, false
+	}
+	minRuntime := api.GetMinRuntime(job.PodGroup.Annotations)
+	if minRuntime <= 0 {
+		return time.Time{}, false
+	}
+	start, started := jobStartTime(job)
+	if !started {
+		return time.Time{}, false
+	}
+	return start.Add(minRuntime), true
+}
+
+// minRuntimeVictims filters out the preemptees whose jobs are within their protected window. The preemptees on
+// the nodes shared by scheduling profiles may belong to the jobs of other profiles, which are protected as well.
+func minRuntimeVictims(jobs, otherProfileJobs map[api.JobID]*api.JobInfo, preemptees []*api.TaskInfo, now time.Time) []*api.TaskInfo {
+	var victims []*api.TaskInfo
+	for _, preemptee := range preemptees {
+		job, found := jobs[preemptee.Job]
+		if !found {
+			job, found = otherProfileJobs[preemptee.Job]
+		}
+		if found {
+			if until, protected := protectedUntil(job); protected && until.After(now) {
+				klog.V(4).Infof("Task <%s/%s> is protected by min runtime of its job until %v",
+					preemptee.Namespace, preemptee.Name, until)
+				continue
+			}
+		}
+		victims = append(victims, preemptee)
+	}
+	return victims
+}
+
+// updateProtectedCondition updates the PreemptionProtected condition of the jobs with min runtime, the message
+// tells until when the job is protected and does not change with the remaining time, so the podgroup is not
+// updated in every session.
+func updateProtectedCondition(ssn *framework.Session, now time.Time) {
+	for _, job := range ssn.Jobs {
+		until, protected := protectedUntil(job)
+		if !protected {
+			continue
+		}
+		jc := &scheduling.PodGroupCondition{
+			Type:               api.PodGroupPreemptionProtectedType,
+			Status:             v1.ConditionTrue,
+			Reason:             api.MinRuntimeReason,
+			Message:            fmt.Sprintf("protected from preemption and reclaim until %s", until.UTC().Format(time.RFC3339)),
+			LastTransitionTime: metav1.Now(),
+			TransitionID:       string(ssn.UID),
+		}
+		if !until.After(now) {
+			jc.Status = v1.ConditionFalse
+			jc.Reason = api.MinRuntimeElapsedReason
+			jc.Message = fmt.Sprintf("min runtime elapsed at %s", until.UTC().Format(time.RFC3339))
+		}
+		// The condition is only written when it changes, so the podgroup is not updated in every session.
+		if current := podGroupCondition(job, api.PodGroupPreemptionProtectedType); current != nil &&
+			current.Status == jc.Status && current.Reason == jc.Reason && current.Message == jc.Message {
+			continue
+		}
+		if err := ssn.UpdatePodGroupCondition(job, jc); err != nil {
+			klog.Errorf("Failed to update job <%s/%s> condition: %v", job.Namespace, job.Name, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestMinRuntimeVictims(t *testing.T) {
+	now := time.Now()
+	newJob := func(name, minRuntime string, minAvailable int32, starts ...time.Duration) *api.JobInfo {
+		var tasks []*api.TaskInfo
+		for i, start := range starts {
+			startTime := metav1.NewTime(now.Add(-start))
+			tasks = append(tasks, api.NewTaskInfo(&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("%s-%d", name, i), UID: types.UID(fmt.Sprintf("%s-%d", name, i))},
+				Status:     v1.PodStatus{Phase: v1.PodRunning, StartTime: &startTime},
+			}))
+		}
+		job := api.NewJobInfo(api.JobID("ns/"+name), tasks...)
+		job.MinAvailable = minAvailable
+		job.PodGroup = &api.PodGroup{PodGroup: scheduling.PodGroup{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{api.MinRuntimeAnnotation: minRuntime},
+		}}}
+		for _, task := range tasks {
+			task.Job = job.UID
+		}
+		return job
+	}
+
+	jobs := map[api.JobID]*api.JobInfo{}
+	var preemptees []*api.TaskInfo
+	for _, job := range []*api.JobInfo{
+		// started 20 minutes ago when the second task started, protected for 10 minutes more
+		newJob("protected", "30m", 2, 40*time.Minute, 20*time.Minute),
+		newJob("elapsed", "30m", 1, 40*time.Minute),
+		// not started as fewer than minAvailable tasks are running
+		newJob("starting", "30m", 2, time.Minute),
+		newJob("unprotected", "", 1, time.Minute),
+	} {
+		jobs[job.UID] = job
+		for _, task := range job.Tasks {
+			preemptees = append(preemptees, task)
+		}
+	}
+	// the job of another scheduling profile running on the same nodes is protected as well
+	otherJob := newJob("other-profile", "30m", 1, 20*time.Minute)
+	otherProfileJobs := map[api.JobID]*api.JobInfo{otherJob.UID: otherJob}
+	for _, task := range otherJob.Tasks {
+		preemptees = append(preemptees, task)
+	}
+
+	victims := minRuntimeVictims(jobs, otherProfileJobs, preemptees, now)
+	if len(victims) != len(preemptees)-3 {
+		t.Fatalf("expected %d victims, got %d", len(preemptees)-3, len(victims))
+	}
+	for _, victim := range victims {
+		if victim.Job == "ns/protected" || victim.Job == "ns/other-profile" {
+			t.Errorf("expected task %s of protected job not a victim", victim.Name)
+		}
+	}
+
+	until, protected := protectedUntil(jobs["ns/protected"])
+	if !protected || !until.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("expected job protected until %v, got %v %v", now.Add(10*time.Minute), until, protected)
+	}
+}
+
+func TestUpdateProtectedCondition(t *testing.T) {
+	now := time.Now()
+	startTime := metav1.NewTime(now.Add(-10 * time.Minute))
+	task := api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p1", UID: "p1"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, StartTime: &startTime},
+	})
+	job := api.NewJobInfo("ns/pg1", task)
+	job.MinAvailable = 1
+	job.PodGroup = &api.PodGroup{PodGroup: scheduling.PodGroup{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{api.MinRuntimeAnnotation: "30m"},
+	}}}
+	ssn := &framework.Session{UID: "s1", Jobs: map[api.JobID]*api.JobInfo{job.UID: job}}
+
+	updateProtectedCondition(ssn, now)
+	condition := podGroupCondition(job, api.PodGroupPreemptionProtectedType)
+	if condition == nil || condition.Status != v1.ConditionTrue || condition.Reason != api.MinRuntimeReason {
+		t.Fatalf("expected job protected by min runtime, got %v", condition)
+	}
+
+	ssn.UID = "s2"
+	updateProtectedCondition(ssn, now.Add(time.Minute))
+	if condition := podGroupCondition(job, api.PodGroupPreemptionProtectedType); condition.TransitionID != "s1" {
+		t.Errorf("expected unchanged condition not written again, got transition %s", condition.TransitionID)
+	}
+
+	updateProtectedCondition(ssn, now.Add(time.Hour))
+	if condition := podGroupCondition(job, api.PodGroupPreemptionProtectedType); condition.TransitionID != "s2" ||
+		condition.Reason != api.MinRuntimeElapsedReason {
+		t.Errorf("expected condition written once min runtime elapsed, got %v", condition)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// StarvationThreshold is the number of times a job is evicted by the scheduler, e.g. preempted or reclaimed,
+	// before it is protected from starvation, 0 disables the protection and the counting.
+	StarvationThreshold = "cdp.starvationThreshold"
+	// StarvationProtection is the duration a job evicted too often is protected from preemption and reclaim
+	// since it is evicted last, 30m by default.
+	StarvationProtection = "cdp.starvationProtection"
+	// StarvationPriorityBoost is added to the priority of a job while it is protected from starvation, 1 by default.
+	StarvationPriorityBoost = "cdp.starvationPriorityBoost"
+
+	defaultStarvationProtection    = 30 * time.Minute
+	defaultStarvationPriorityBoost = 1
+
+	// evictedMessage is the message of Evicted condition while the job is not protected, the evictions are counted
+	// by the evictions annotation of the podgroup.
+	evictedMessage = "evicted %d times since the last starvation protection"
+)
+
+// starvationConfig is the configuration of the protection of jobs evicted too often.
+type starvationConfig struct {
+	threshold     int
+	protection    time.Duration
+	priorityBoost int
+}
+
+func parseStarvationConfig(arguments framework.Arguments) starvationConfig {
+	sc := starvationConfig{
+		protection:    defaultStarvationProtection,
+		priorityBoost: defaultStarvationPriorityBoost,
+	}
+	arguments.GetInt(&sc.threshold, StarvationThreshold)
+	arguments.GetInt(&sc.priorityBoost, StarvationPriorityBoost)
+	if protection, _ := arguments[StarvationProtection].(string); protection != "" {
+		if d, err := time.ParseDuration(protection); err == nil && d > 0 {
+			sc.protection = d
+		} else {
+			klog.Warningf("invalid %s=%s", StarvationProtection, protection)
+		}
+	}
+	return sc
+}
+
+// evictions returns how many times job is evicted by the scheduler since it is last protected from starvation, by
+// the evictions annotation of its podgroup.
+func evictions(job *api.JobInfo) int {
+	if job.PodGroup == nil {
+		return 0
+	}
+	value, found := job.PodGroup.Annotations[api.PodGroupEvictionsAnnotation]
+	if !found {
+		return 0
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		klog.Warningf("Invalid %s=%s of job <%s/%s>", api.PodGroupEvictionsAnnotation, value, job.Namespace, job.Name)
+		return 0
+	}
+	return count
+}
+
+// evictedCondition returns the Evicted condition of the podgroup of job, nil if none.
+func evictedCondition(job *api.JobInfo) *scheduling.PodGroupCondition {
+	return podGroupCondition(job, api.PodGroupEvictedType)
+}
+
+// podGroupCondition returns the condition of conditionType of the podgroup of job, nil if none.
+func podGroupCondition(job *api.JobInfo, conditionType scheduling.PodGroupConditionType) *scheduling.PodGroupCondition {
+	if job.PodGroup == nil {
+		return nil
+	}
+	for i, c := range job.PodGroup.Status.Conditions {
+		if c.Type == conditionType {
+			return &job.PodGroup.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// protectedUntil returns until when job is protected from starvation since the protection is granted, it returns
+// false if job is not granted the protection.
+func (sc starvationConfig) protectedUntil(job *api.JobInfo) (time.Time, bool) {
+	c := evictedCondition(job)
+	if sc.threshold <= 0 || c == nil || c.Reason != api.StarvationProtectedReason {
+		return time.Time{}, false
+	}
+	return c.LastTransitionTime.Add(sc.protection), true
+}
+
+// starving checks whether job is protected from starvation at now.
+func (sc starvationConfig) starving(job *api.JobInfo, now time.Time) bool {
+	until, protected := sc.protectedUntil(job)
+	return protected && until.After(now)
+}
+
+// starvationVictims filters out the preemptees whose jobs are protected from starvation.
+func (sc starvationConfig) starvationVictims(jobs map[api.JobID]*api.JobInfo, preemptees []*api.TaskInfo, now time.Time) []*api.TaskInfo {
+	if sc.threshold <= 0 {
+		return preemptees
+	}
+	var victims []*api.TaskInfo
+	for _, preemptee := range preemptees {
+		if job, found := jobs[preemptee.Job]; found && sc.starving(job, now) {
+			klog.V(4).Infof("Task <%s/%s> is protected from starvation as its job is evicted too often",
+				preemptee.Namespace, preemptee.Name)
+			continue
+		}
+		victims = append(victims, preemptee)
+	}
+	return victims
+}
+
+// boostStarvingJobs raises the priority of the jobs protected from starvation, so they are also ordered before
+// and not preempted by the jobs of the same priority.
+func (sc starvationConfig) boostStarvingJobs(ssn *framework.Session, now time.Time) {
+	if sc.threshold <= 0 || sc.priorityBoost == 0 {
+		return
+	}
+	for _, job := range ssn.Jobs {
+		if sc.starving(job, now) {
+			job.Priority += int32(sc.priorityBoost)
+		}
+	}
+}
+
+// releasingTasks returns the tasks releasing when the session is opened, so the tasks evicted in the session
+// are told apart from them.
+func releasingTasks(ssn *framework.Session) map[api.TaskID]bool {
+	releasing := map[api.TaskID]bool{}
+	for _, job := range ssn.Jobs {
+		for id := range job.TaskStatusIndex[api.Releasing] {
+			releasing[id] = true
+		}
+	}
+	return releasing
+}
+
+// updateEvictedCondition counts the jobs whose tasks are evicted in the session, i.e. releasing but not when the
+// session is opened, once per session, in the evictions annotation of their podgroups. A job evicted as many times
+// as the threshold is granted the protection from starvation by the Evicted condition, and its count is reset; the
+// count starts again from zero when the protection expires, so the evictions during the protection, e.g. by other
+// actions than preempt and reclaim, are not counted.
+func (sc starvationConfig) updateEvictedCondition(ssn *framework.Session, releasing map[api.TaskID]bool, now time.Time) {
+	if sc.threshold <= 0 {
+		return
+	}
+	for _, job := range ssn.Jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+		until, protected := sc.protectedUntil(job)
+		if protected && until.After(now) {
+			continue
+		}
+
+		evicted := false
+		for id := range job.TaskStatusIndex[api.Releasing] {
+			if !releasing[id] {
+				evicted = true
+				break
+			}
+		}
+		count := evictions(job)
+		if protected {
+			// The protection expired.
+			count = 0
+		} else if !evicted {
+			continue
+		}
+		if evicted {
+			count++
+		}
+
+		jc := &scheduling.PodGroupCondition{
+			Type:               api.PodGroupEvictedType,
+			Status:             v1.ConditionTrue,
+			Reason:             api.EvictedReason,
+			Message:            fmt.Sprintf(evictedMessage, count),
+			LastTransitionTime: metav1.NewTime(now),
+			TransitionID:       string(ssn.UID),
+		}
+		if count >= sc.threshold {
+			jc.Reason = api.StarvationProtectedReason
+			jc.Message = fmt.Sprintf("evicted %d times, protected from preemption and reclaim until %s", count,
+				now.Add(sc.protection).UTC().Format(time.RFC3339))
+			count = 0
+		} else if !evicted {
+			// The condition is kept since the job is evicted last.
+			jc.LastTransitionTime = evictedCondition(job).LastTransitionTime
+		}
+
+		if job.PodGroup.Annotations == nil {
+			job.PodGroup.Annotations = map[string]string{}
+		}
+		job.PodGroup.Annotations[api.PodGroupEvictionsAnnotation] = strconv.Itoa(count)
+		if err := ssn.UpdatePodGroupCondition(job, jc); err != nil {
+			klog.Errorf("Failed to update job <%s/%s> condition: %v", job.Namespace, job.Name, err)
+		}
+	}
+}
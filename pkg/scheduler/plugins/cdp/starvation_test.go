@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cdp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestStarvationProtection(t *testing.T) {
+	condition := func(reason string, last time.Time) schedulingv1.PodGroupCondition {
+		message := "evicted 5 times"
+		if reason == api.StarvationProtectedReason {
+			message += ", protected from preemption and reclaim"
+		}
+		return schedulingv1.PodGroupCondition{
+			Type:               schedulingv1.PodGroupConditionType(api.PodGroupEvictedType),
+			Status:             v1.ConditionTrue,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: metav1.NewTime(last),
+		}
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		conditions []schedulingv1.PodGroupCondition
+		evictions  string
+		evict      bool
+		// expected after the session is opened
+		expectPriority  int32
+		expectProtected bool
+		// expected after the session is closed
+		expectCount  int
+		expectReason string
+	}{
+		{
+			name:  "first eviction is counted",
+			evict: true,
+
+			expectCount:  1,
+			expectReason: api.EvictedReason,
+		},
+		{
+			name: "job never evicted has no condition",
+		},
+		{
+			name:       "evictions are counted by the annotation, not the message",
+			conditions: []schedulingv1.PodGroupCondition{condition(api.EvictedReason, now.Add(-time.Hour))},
+			evict:      true,
+
+			expectCount:  1,
+			expectReason: api.EvictedReason,
+		},
+		{
+			name:       "job evicted as often as the threshold is protected and its count is reset",
+			conditions: []schedulingv1.PodGroupCondition{condition(api.EvictedReason, now.Add(-time.Hour))},
+			evictions:  "1",
+			evict:      true,
+
+			expectCount:  0,
+			expectReason: api.StarvationProtectedReason,
+		},
+		{
+			name:       "protected job is boosted and not a victim",
+			conditions: []schedulingv1.PodGroupCondition{condition(api.StarvationProtectedReason, now.Add(-10*time.Minute))},
+			evictions:  "0",
+
+			expectPriority:  1,
+			expectProtected: true,
+			expectCount:     0,
+			expectReason:    api.StarvationProtectedReason,
+		},
+		{
+			name:       "protection elapses",
+			conditions: []schedulingv1.PodGroupCondition{condition(api.StarvationProtectedReason, now.Add(-time.Hour))},
+			evictions:  "0",
+
+			expectCount:  0,
+			expectReason: api.EvictedReason,
+		},
+		{
+			name:       "evictions are counted from zero once the protection elapses",
+			conditions: []schedulingv1.PodGroupCondition{condition(api.StarvationProtectedReason, now.Add(-time.Hour))},
+			evictions:  "1",
+			evict:      true,
+
+			expectCount:  1,
+			expectReason: api.EvictedReason,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pg := util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupRunning)
+			pg.Status.Conditions = test.conditions
+			if len(test.evictions) != 0 {
+				pg.Annotations = map[string]string{api.PodGroupEvictionsAnnotation: test.evictions}
+			}
+			tc := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				Arguments: map[string]framework.Arguments{PluginName: {StarvationThreshold: 2}},
+				PodGroups: []*schedulingv1.PodGroup{pg},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns", "p1", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", nil, nil),
+				},
+				Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("2", "4G"), nil)},
+				Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+			}
+			defer tc.Close()
+
+			ssn := tc.Open()
+			job := ssn.Jobs["ns/pg1"]
+			if job.Priority != test.expectPriority {
+				t.Errorf("expected priority %d, got %d", test.expectPriority, job.Priority)
+			}
+			var tasks []*api.TaskInfo
+			for _, task := range job.Tasks {
+				tasks = append(tasks, task)
+			}
+			victims := ssn.Preemptable(&api.TaskInfo{}, tasks)
+			if protected := len(victims) == 0; protected != test.expectProtected {
+				t.Errorf("expected protected %v, got victims %v", test.expectProtected, victims)
+			}
+			if test.evict {
+				if err := ssn.Evict(tasks[0], "preempt"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tc.Close()
+
+			if count := evictions(job); count != test.expectCount {
+				t.Errorf("expected %d evictions, got %d", test.expectCount, count)
+			}
+			if c := evictedCondition(job); test.evict && c.LastTransitionTime.Before(&metav1.Time{Time: now}) {
+				t.Errorf("expected last eviction updated, got %v", c.LastTransitionTime)
+			}
+			for _, c := range job.PodGroup.Status.Conditions {
+				if c.Type != api.PodGroupEvictedType {
+					continue
+				}
+				if c.Reason != test.expectReason {
+					t.Errorf("expected reason %s, got %s", test.expectReason, c.Reason)
+				}
+				if protected := strings.Contains(c.Message, "protected"); protected != (test.expectReason == api.StarvationProtectedReason) {
+					t.Errorf("unexpected message %q of reason %s", c.Message, c.Reason)
+				}
+			}
+		})
+	}
+}
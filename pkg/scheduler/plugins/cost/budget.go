@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"sync"
+	"time"
+)
+
+// queueSpend is the cost of the tasks of a queue in the current billing window.
+type queueSpend struct {
+	windowStart time.Time
+	lastUpdate  time.Time
+	// rate is the cost per hour of the tasks of the queue seen at lastUpdate
+	rate  float64
+	spent float64
+}
+
+// budgetTracker tracks the spend of queues across the sessions of a scheduler, it is kept in the state of the plugin
+// in the scheduler cache. It is only kept in memory, so the spend of the current billing window is lost when the
+// scheduler restarts.
+type budgetTracker struct {
+	sync.Mutex
+	queues map[string]*queueSpend
+}
+
+func newBudgetTracker() interface{} {
+	return &budgetTracker{queues: map[string]*queueSpend{}}
+}
+
+// CloneState returns a copy of the spend of queues, e.g. for dry runs.
+func (bt *budgetTracker) CloneState() interface{} {
+	bt.Lock()
+	defer bt.Unlock()
+	queues := make(map[string]*queueSpend, len(bt.queues))
+	for queue, qs := range bt.queues {
+		spend := *qs
+		queues[queue] = &spend
+	}
+	return &budgetTracker{queues: queues}
+}
+
+// windowStart returns the start of the billing window of now, windows are aligned to the zero time.
+func windowStart(now time.Time, window time.Duration) time.Time {
+	if window <= 0 {
+		return time.Time{}
+	}
+	return now.Truncate(window)
+}
+
+// update charges the queues for the time since their last update at the rate seen then, and records the
+// current rates of the queues of the session. The spend of a queue is reset when a new billing window starts,
+// queues no longer in the session are then dropped. It returns the spend of the queues in the current
+// billing window.
+func (bt *budgetTracker) update(rates map[string]float64, now time.Time, window time.Duration) map[string]float64 {
+	bt.Lock()
+	defer bt.Unlock()
+
+	start := windowStart(now, window)
+	spent := map[string]float64{}
+	for queue, qs := range bt.queues {
+		from := qs.lastUpdate
+		if qs.windowStart.Before(start) {
+			if _, found := rates[queue]; !found {
+				delete(bt.queues, queue)
+				continue
+			}
+			qs.windowStart = start
+			qs.spent = 0
+			from = start
+		}
+		if now.After(from) {
+			qs.spent += qs.rate * now.Sub(from).Hours()
+		}
+		qs.lastUpdate = now
+		qs.rate = 0
+		spent[queue] = qs.spent
+	}
+	for queue, rate := range rates {
+		qs, found := bt.queues[queue]
+		if !found {
+			qs = &queueSpend{windowStart: start, lastUpdate: now}
+			bt.queues[queue] = qs
+			spent[queue] = 0
+		}
+		qs.rate = rate
+	}
+	return spent
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/scheduler/plugins/util"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "cost"
+
+	// WeightArgument is the weight of cost score
+	WeightArgument = "cost.weight"
+	// PricingConfigMapArgument is the namespace/name of the ConfigMap with the cost per hour of instance types
+	PricingConfigMapArgument = "cost.pricingConfigMap"
+	// CapacityTypeLabelArgument is the label of node with its capacity type, e.g. spot or on-demand
+	CapacityTypeLabelArgument = "cost.capacityTypeLabel"
+	// BillingWindowArgument is the duration of the billing window of queue budgets
+	BillingWindowArgument = "cost.billingWindow"
+
+	// BudgetAnnotation is the key of annotation on queue with its budget in a billing window
+	BudgetAnnotation = "volcano.sh/budget"
+
+	defaultCapacityTypeLabel = "karpenter.sh/capacity-type"
+	defaultBillingWindow     = 30 * 24 * time.Hour
+)
+
+/*
+   actions: "enqueue, allocate, backfill"
+   tiers:
+   - plugins:
+     - name: cost
+       arguments:
+         cost.weight: 1
+         cost.pricingConfigMap: volcano-system/node-pricing
+         cost.capacityTypeLabel: karpenter.sh/capacity-type
+         cost.billingWindow: 720h
+*/
+
+type costPlugin struct {
+	weight            int
+	pricingConfigMap  string
+	capacityTypeLabel string
+	billingWindow     time.Duration
+
+	// prices is the cost per hour of nodes in the session by name
+	prices map[string]float64
+	// spent is the spend of queues in the current billing window
+	spent map[string]float64
+}
+
+// New function returns costPlugin object
+func New(arguments framework.Arguments) framework.Plugin {
+	cp := &costPlugin{
+		weight:            1,
+		capacityTypeLabel: defaultCapacityTypeLabel,
+		billingWindow:     defaultBillingWindow,
+	}
+	arguments.GetInt(&cp.weight, WeightArgument)
+	arguments.GetString(&cp.pricingConfigMap, PricingConfigMapArgument)
+	arguments.GetString(&cp.capacityTypeLabel, CapacityTypeLabelArgument)
+
+	var window string
+	arguments.GetString(&window, BillingWindowArgument)
+	if window != "" {
+		if duration, err := time.ParseDuration(window); err == nil && duration > 0 {
+			cp.billingWindow = duration
+		} else {
+			klog.Warningf("Invalid %s %q of plugin %s, use default %v", BillingWindowArgument, window, PluginName,
+				defaultBillingWindow)
+		}
+	}
+	return cp
+}
+
+func (cp *costPlugin) Name() string {
+	return PluginName
+}
+
+// queueBudget returns the budget of queue in a billing window by its BudgetAnnotation.
+func queueBudget(queue *api.QueueInfo) (float64, bool) {
+	if queue == nil || queue.Queue == nil {
+		return 0, false
+	}
+	value, found := queue.Queue.Annotations[BudgetAnnotation]
+	if !found {
+		return 0, false
+	}
+	budget, err := strconv.ParseFloat(value, 64)
+	if err != nil || budget < 0 {
+		klog.V(4).Infof("Invalid budget %q of queue %s", value, queue.Name)
+		return 0, false
+	}
+	return budget, true
+}
+
+// taskCost returns the cost per hour of task on node, i.e. the price of node in proportion to the share of
+// node taken by task.
+func (cp *costPlugin) taskCost(task *api.TaskInfo, node *api.NodeInfo) (float64, bool) {
+	price, found := cp.prices[node.Name]
+	if !found {
+		return 0, false
+	}
+	return price * taskShare(task, node), true
+}
+
+// scores scores the nodes where task costs less higher, scaled to the max node score between the most and the
+// least expensive nodes. Nodes without price are not scored.
+func (cp *costPlugin) scores(task *api.TaskInfo, nodes []*api.NodeInfo) map[string]float64 {
+	costs := map[string]float64{}
+	minCost, maxCost := 0.0, 0.0
+	for _, node := range nodes {
+		taskCost, found := cp.taskCost(task, node)
+		if !found {
+			continue
+		}
+		if len(costs) == 0 || taskCost < minCost {
+			minCost = taskCost
+		}
+		if len(costs) == 0 || taskCost > maxCost {
+			maxCost = taskCost
+		}
+		costs[node.Name] = taskCost
+	}
+
+	scores := map[string]float64{}
+	if maxCost <= minCost {
+		return scores
+	}
+	for name, taskCost := range costs {
+		scores[name] = (maxCost - taskCost) / (maxCost - minCost) * float64(k8sframework.MaxNodeScore) * float64(cp.weight)
+		klog.V(5).Infof("Cost of task %s/%s on node %s is %f, score %f", task.Namespace, task.Name, name, taskCost, scores[name])
+	}
+	return scores
+}
+
+// queueRates returns the cost per hour of the allocated tasks of every queue in the session, including the tasks of
+// the jobs of other scheduling profiles, so the sessions of every profile charge queues the same rates.
+func (cp *costPlugin) queueRates(ssn *framework.Session) map[string]float64 {
+	rates := map[string]float64{}
+	for _, queue := range ssn.Queues {
+		rates[queue.Name] = 0
+	}
+	for _, jobs := range []map[api.JobID]*api.JobInfo{ssn.Jobs, ssn.OtherProfileJobs()} {
+		for _, job := range jobs {
+			for _, task := range job.Tasks {
+				if !api.AllocatedStatus(task.Status) {
+					continue
+				}
+				node, found := ssn.Nodes[task.NodeName]
+				if !found {
+					continue
+				}
+				if taskCost, found := cp.taskCost(task, node); found {
+					rates[string(job.Queue)] += taskCost
+				}
+			}
+		}
+	}
+	return rates
+}
+
+func (cp *costPlugin) OnSessionOpen(ssn *framework.Session) {
+	prices := pricing.get(ssn.KubeClient(), cp.pricingConfigMap)
+	cp.prices = map[string]float64{}
+	for name, node := range ssn.Nodes {
+		if price, found := nodePrice(node.Node, prices, cp.capacityTypeLabel); found {
+			cp.prices[name] = price
+		}
+	}
+
+	tracker := ssn.PluginState(PluginName, newBudgetTracker).(*budgetTracker)
+	cp.spent = tracker.update(cp.queueRates(ssn), time.Now(), cp.billingWindow)
+	for queue, spent := range cp.spent {
+		metrics.UpdateQueueBudgetSpent(queue, spent)
+	}
+
+	ssn.AddJobEnqueueableFn(cp.Name(), func(obj interface{}) int {
+		job := obj.(*api.JobInfo)
+		budget, found := queueBudget(ssn.Queues[job.Queue])
+		if !found {
+			return util.Abstain
+		}
+		if spent := cp.spent[string(job.Queue)]; spent >= budget {
+			msg := fmt.Sprintf("queue <%s> spent %.2f of its budget %.2f in the billing window", job.Queue, spent, budget)
+			klog.V(3).Infof("Job <%s/%s> is not enqueueable: %s", job.Namespace, job.Name, msg)
+			ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), msg)
+			return util.Reject
+		}
+		return util.Permit
+	})
+
+	batchNodeOrderFn := func(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
+		return cp.scores(task, nodes), nil
+	}
+	ssn.AddBatchNodeOrderFn(cp.Name(), batchNodeOrderFn)
+}
+
+func (cp *costPlugin) OnSessionClose(ssn *framework.Session) {
+	cp.prices = nil
+	cp.spent = nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func buildNode(name string, labels map[string]string, cpu, memory string) *api.NodeInfo {
+	return api.NewNodeInfo(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}},
+	})
+}
+
+func buildTask(name, cpu, memory string) *api.TaskInfo {
+	return api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}}}},
+	})
+}
+
+func TestNodePrice(t *testing.T) {
+	prices := map[string]float64{"m5.large": 0.1, "m5.large_spot": 0.03}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		price  float64
+		found  bool
+	}{
+		{name: "label", labels: map[string]string{HourlyCostLabel: "0.5", instanceTypeLabel: "m5.large"}, price: 0.5, found: true},
+		{name: "spot", labels: map[string]string{instanceTypeLabel: "m5.large", defaultCapacityTypeLabel: "spot"}, price: 0.03, found: true},
+		{name: "on-demand", labels: map[string]string{instanceTypeLabel: "m5.large", defaultCapacityTypeLabel: "on-demand"}, price: 0.1, found: true},
+		{name: "invalid label", labels: map[string]string{HourlyCostLabel: "free", instanceTypeLabel: "m5.large"}, price: 0.1, found: true},
+		{name: "unknown type", labels: map[string]string{instanceTypeLabel: "c5.xlarge"}},
+		{name: "no labels"},
+	}
+	for _, test := range tests {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name, Labels: test.labels}}
+		price, found := nodePrice(node, prices, defaultCapacityTypeLabel)
+		if price != test.price || found != test.found {
+			t.Errorf("%s: expected price %v found %v, got %v %v", test.name, test.price, test.found, price, found)
+		}
+	}
+}
+
+func TestPricingWatcherGet(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "volcano-system", Name: "node-pricing"},
+		Data:       map[string]string{"m5.large": "0.1", "m5.large_spot": " 0.03 ", "bad": "-1"},
+	}, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "node-pricing"},
+		Data:       map[string]string{"m5.large": "0.2"},
+	})
+	watchers := &pricingWatchers{watchers: map[string]*pricingWatcher{}}
+	defer watchers.stop()
+	get := func() map[string]float64 {
+		return watchers.get(client, "volcano-system/node-pricing")
+	}
+	synced := func(configMap string) map[string]float64 {
+		var prices map[string]float64
+		if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+			prices = watchers.get(client, configMap)
+			return prices != nil, nil
+		}); err != nil {
+			t.Fatalf("expected prices of %s from the informer: %v", configMap, err)
+		}
+		return prices
+	}
+	if prices := synced("volcano-system/node-pricing"); len(prices) != 2 || prices["m5.large"] != 0.1 || prices["m5.large_spot"] != 0.03 {
+		t.Errorf("unexpected prices %v", prices)
+	}
+
+	// The profiles pricing nodes by different ConfigMaps keep their own watchers.
+	if prices := synced("team-a/node-pricing"); len(prices) != 1 || prices["m5.large"] != 0.2 {
+		t.Errorf("unexpected prices of team-a %v", prices)
+	}
+	watcher := watchers.watchers["volcano-system/node-pricing"]
+	if prices := get(); prices["m5.large"] != 0.1 || watchers.watchers["volcano-system/node-pricing"] != watcher {
+		t.Errorf("expected the watcher of volcano-system kept with prices, got %v", prices)
+	}
+
+	if err := client.CoreV1().ConfigMaps("volcano-system").Delete(context.TODO(), "node-pricing", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return get() == nil, nil
+	}); err != nil {
+		t.Errorf("expected no prices after the ConfigMap is deleted, got %v", get())
+	}
+
+	// The watchers not read any more are stopped.
+	watchers.watchers["team-a/node-pricing"].used = time.Now().Add(-2 * pricingWatcherIdle)
+	get()
+	if _, found := watchers.watchers["team-a/node-pricing"]; found || len(watchers.watchers) != 1 {
+		t.Errorf("expected the idle watcher of team-a stopped, got %v", watchers.watchers)
+	}
+}
+
+func TestScores(t *testing.T) {
+	cp := &costPlugin{weight: 2, prices: map[string]float64{"spot": 1, "on-demand": 3}}
+	nodes := []*api.NodeInfo{
+		buildNode("spot", nil, "4", "16Gi"),
+		buildNode("on-demand", nil, "4", "16Gi"),
+		buildNode("unpriced", nil, "4", "16Gi"),
+	}
+	scores := cp.scores(buildTask("p1", "2", "4Gi"), nodes)
+	if scores["spot"] != 200 || scores["on-demand"] != 0 || len(scores) != 2 {
+		t.Errorf("unexpected scores %v", scores)
+	}
+
+	cp.prices = map[string]float64{"spot": 1}
+	if scores := cp.scores(buildTask("p1", "2", "4Gi"), nodes); len(scores) != 0 {
+		t.Errorf("expected no scores for a single priced node, got %v", scores)
+	}
+}
+
+func TestTaskShare(t *testing.T) {
+	node := buildNode("n1", nil, "4", "16Gi")
+	if share := taskShare(buildTask("p1", "1", "8Gi"), node); share != 0.5 {
+		t.Errorf("expected memory share 0.5, got %v", share)
+	}
+	if share := taskShare(buildTask("p2", "8", "1Gi"), node); share != 1 {
+		t.Errorf("expected share capped to 1, got %v", share)
+	}
+}
+
+func TestBudgetTrackerUpdate(t *testing.T) {
+	bt := &budgetTracker{queues: map[string]*queueSpend{}}
+	window := 24 * time.Hour
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if spent := bt.update(map[string]float64{"q1": 2, "q2": 1}, start.Add(time.Hour), window); spent["q1"] != 0 {
+		t.Errorf("expected nothing spent at first session, got %v", spent)
+	}
+	spent := bt.update(map[string]float64{"q1": 4, "q2": 0}, start.Add(4*time.Hour), window)
+	if spent["q1"] != 6 || spent["q2"] != 3 {
+		t.Errorf("expected spend charged at previous rates, got %v", spent)
+	}
+	spent = bt.update(map[string]float64{"q1": 4}, start.Add(6*time.Hour), window)
+	if spent["q1"] != 14 || spent["q2"] != 3 {
+		t.Errorf("expected spend kept in window, got %v", spent)
+	}
+	spent = bt.update(map[string]float64{"q1": 4}, start.Add(25*time.Hour), window)
+	if math.Abs(spent["q1"]-4) > 1e-9 {
+		t.Errorf("expected spend reset in new window, got %v", spent)
+	}
+	if _, found := spent["q2"]; found {
+		t.Errorf("expected queue not in session dropped in new window, got %v", spent)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// HourlyCostLabel is the label of node with its cost per hour, it overrides the pricing ConfigMap
+	HourlyCostLabel = "volcano.sh/hourly-cost"
+	// instanceTypeLabel is the well-known label of node with its instance type
+	instanceTypeLabel = "node.kubernetes.io/instance-type"
+)
+
+// pricingWatcherIdle is how long the watcher of a pricing ConfigMap no profile prices nodes by is kept.
+const pricingWatcherIdle = 10 * time.Minute
+
+// pricingWatcher watches a pricing ConfigMap by an informer of that ConfigMap only, so the sessions read the
+// prices from the informer cache instead of the apiserver.
+type pricingWatcher struct {
+	stopCh chan struct{}
+	lister corelisters.ConfigMapLister
+	synced cache.InformerSynced
+	// resourceVersion is the version of the ConfigMap prices are parsed from
+	resourceVersion string
+	// prices is the cost per hour by instance type or by instance type and capacity type
+	prices map[string]float64
+	// used is the last time the prices are read
+	used time.Time
+}
+
+// pricingWatchers keeps a watcher per pricing ConfigMap by namespace/name, so that the scheduling profiles pricing
+// nodes by different ConfigMaps do not restart the informers of each other. The watchers not read for
+// pricingWatcherIdle are stopped.
+type pricingWatchers struct {
+	sync.Mutex
+	watchers map[string]*pricingWatcher
+}
+
+var pricing = &pricingWatchers{watchers: map[string]*pricingWatcher{}}
+
+// parsePrices parses the cost per hour of the keys of ConfigMap data, invalid values are skipped.
+func parsePrices(data map[string]string) map[string]float64 {
+	prices := map[string]float64{}
+	for key, value := range data {
+		price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || price < 0 {
+			klog.V(4).Infof("Skip invalid price %q of %s in pricing ConfigMap", value, key)
+			continue
+		}
+		prices[key] = price
+	}
+	return prices
+}
+
+// get returns the prices of the pricing ConfigMap in namespace/name, it is nil if the ConfigMap is not available
+// or not synced yet. The informer of the ConfigMap is started by the first call with it.
+func (pws *pricingWatchers) get(client kubernetes.Interface, configMap string) map[string]float64 {
+	pws.Lock()
+	defer pws.Unlock()
+
+	now := time.Now()
+	for key, watcher := range pws.watchers {
+		if key != configMap && now.Sub(watcher.used) > pricingWatcherIdle {
+			close(watcher.stopCh)
+			delete(pws.watchers, key)
+		}
+	}
+	if configMap == "" || client == nil {
+		return nil
+	}
+	namespace, name := "", configMap
+	if parts := strings.SplitN(configMap, "/", 2); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	}
+	watcher, found := pws.watchers[configMap]
+	if !found {
+		watcher = newPricingWatcher(client, namespace, name)
+		pws.watchers[configMap] = watcher
+	}
+	watcher.used = now
+	if !watcher.synced() {
+		return nil
+	}
+
+	cm, err := watcher.lister.ConfigMaps(namespace).Get(name)
+	if err != nil {
+		klog.V(4).Infof("Pricing ConfigMap %s is not available: %v", configMap, err)
+		return nil
+	}
+	if cm.ResourceVersion != watcher.resourceVersion || watcher.prices == nil {
+		watcher.resourceVersion = cm.ResourceVersion
+		watcher.prices = parsePrices(cm.Data)
+	}
+	return watcher.prices
+}
+
+// stop stops the informers of all watchers.
+func (pws *pricingWatchers) stop() {
+	pws.Lock()
+	defer pws.Unlock()
+	for key, watcher := range pws.watchers {
+		close(watcher.stopCh)
+		delete(pws.watchers, key)
+	}
+}
+
+// newPricingWatcher starts the informer of the ConfigMap namespace/name.
+func newPricingWatcher(client kubernetes.Interface, namespace, name string) *pricingWatcher {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	configMaps := factory.Core().V1().ConfigMaps()
+	watcher := &pricingWatcher{
+		stopCh: make(chan struct{}),
+		lister: configMaps.Lister(),
+		synced: configMaps.Informer().HasSynced,
+	}
+	factory.Start(watcher.stopCh)
+	return watcher
+}
+
+// nodePrice returns the cost per hour of node, by its HourlyCostLabel, or by the prices of its instance type
+// and capacity type, e.g. `m5.large_spot`, then of its instance type only, e.g. `m5.large`.
+func nodePrice(node *v1.Node, prices map[string]float64, capacityTypeLabel string) (float64, bool) {
+	if node == nil {
+		return 0, false
+	}
+	if value, found := node.Labels[HourlyCostLabel]; found {
+		price, err := strconv.ParseFloat(value, 64)
+		if err == nil && price >= 0 {
+			return price, true
+		}
+		klog.V(4).Infof("Invalid cost %q of node %s", value, node.Name)
+	}
+
+	instanceType := node.Labels[instanceTypeLabel]
+	if instanceType == "" || prices == nil {
+		return 0, false
+	}
+	if capacityType := node.Labels[capacityTypeLabel]; capacityType != "" && capacityTypeLabel != "" {
+		if price, found := prices[instanceType+"_"+capacityType]; found {
+			return price, true
+		}
+	}
+	price, found := prices[instanceType]
+	return price, found
+}
+
+// taskShare returns the share of node taken by the resource request of task, i.e. the max of its shares of
+// the allocatable cpu and memory of node.
+func taskShare(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	if task.Resreq == nil || node.Allocatable == nil {
+		return 0
+	}
+	share := 0.0
+	if node.Allocatable.MilliCPU > 0 {
+		share = task.Resreq.MilliCPU / node.Allocatable.MilliCPU
+	}
+	if node.Allocatable.Memory > 0 {
+		if memory := task.Resreq.Memory / node.Allocatable.Memory; memory > share {
+			share = memory
+		}
+	}
+	if share > 1 {
+		share = 1
+	}
+	return share
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datalocality
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "datalocality"
+
+	// WeightArgument is the weight of data locality score
+	WeightArgument = "datalocality.weight"
+)
+
+/*
+   actions: "enqueue, allocate, backfill"
+   tiers:
+   - plugins:
+     - name: datalocality
+       arguments:
+         datalocality.weight: 2
+*/
+
+type dataLocalityPlugin struct {
+	weight  int
+	sources []LocalitySource
+}
+
+// New function returns dataLocalityPlugin object
+func New(arguments framework.Arguments) framework.Plugin {
+	weight := 1
+	arguments.GetInt(&weight, WeightArgument)
+	return &dataLocalityPlugin{weight: weight}
+}
+
+func (dp *dataLocalityPlugin) Name() string {
+	return PluginName
+}
+
+// buildSources builds the registered sources of data locality in order of their names.
+func buildSources(ssn *framework.Session) []LocalitySource {
+	var names []string
+	for name := range sourceBuilders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sources []LocalitySource
+	for _, name := range names {
+		if source := sourceBuilders[name](ssn); source != nil {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// score returns the average locality of node to the data of task of the sources knowing the data,
+// scaled to the max node score.
+func (dp *dataLocalityPlugin) score(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	total, known := 0.0, 0
+	for _, source := range dp.sources {
+		locality, found := source.Locality(task, node)
+		if !found {
+			continue
+		}
+		klog.V(5).Infof("Locality of node %s to data of task %s/%s by source %s is %f",
+			node.Name, task.Namespace, task.Name, source.Name(), locality)
+		total += locality
+		known++
+	}
+	if known == 0 {
+		return 0
+	}
+	return total / float64(known) * float64(k8sframework.MaxNodeScore) * float64(dp.weight)
+}
+
+func (dp *dataLocalityPlugin) OnSessionOpen(ssn *framework.Session) {
+	dp.sources = buildSources(ssn)
+
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if !requireCacheLocal(task, ssn.Jobs) {
+			return nil, nil
+		}
+		for _, source := range dp.sources {
+			if fluid, ok := source.(*fluidSource); ok && !fluid.cacheLocal(task, node) {
+				msg := fmt.Sprintf("Node %s does not cache datasets of task %s/%s", node.Name, task.Namespace, task.Name)
+				return []*api.Status{{Code: api.UnschedulableAndUnresolvable, Reason: api.NodeDatasetNotCached}},
+					fmt.Errorf("plugin %s predicates failed %s", dp.Name(), msg)
+			}
+		}
+		return nil, nil
+	}
+	ssn.AddPredicateFn(dp.Name(), predicateFn)
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		score := dp.score(task, node)
+		klog.V(4).Infof("Data locality score of node %s for task %s/%s is %f", node.Name, task.Namespace, task.Name, score)
+		return score, nil
+	}
+	ssn.AddNodeOrderFn(dp.Name(), nodeOrderFn)
+}
+
+func (dp *dataLocalityPlugin) OnSessionClose(ssn *framework.Session) {
+	dp.sources = nil
+}
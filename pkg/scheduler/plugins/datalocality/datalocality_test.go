@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datalocality
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func buildNode(name string, labels, annotations map[string]string) *api.NodeInfo {
+	return api.NewNodeInfo(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+	})
+}
+
+func buildTask(name string, annotations map[string]string, volumes ...v1.Volume) *api.TaskInfo {
+	task := api.NewTaskInfo(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID("uid-" + name), Annotations: annotations},
+		Spec:       v1.PodSpec{Volumes: volumes},
+	})
+	task.Job = "ns/j1"
+	return task
+}
+
+func claimVolume(claim string) v1.Volume {
+	return v1.Volume{Name: claim, VolumeSource: v1.VolumeSource{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+	}}
+}
+
+func TestCacheSourceLocality(t *testing.T) {
+	job := &api.JobInfo{UID: "ns/j1", PodGroup: &api.PodGroup{PodGroup: scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{DatasetsAnnotation: "imagenet"}},
+	}}}
+	source := &cacheSource{jobs: map[api.JobID]*api.JobInfo{job.UID: job}}
+	task := buildTask("p1", nil, claimVolume("train"), v1.Volume{Name: "data", VolumeSource: v1.VolumeSource{
+		HostPath: &v1.HostPathVolumeSource{Path: "/mnt/data"},
+	}})
+
+	tests := []struct {
+		name     string
+		node     *api.NodeInfo
+		expected float64
+	}{
+		{
+			name:     "nothing cached",
+			node:     buildNode("n1", nil, nil),
+			expected: 0,
+		},
+		{
+			name:     "hostPath cached",
+			node:     buildNode("n2", nil, map[string]string{CachedDatasetsAnnotation: "/mnt/data"}),
+			expected: 1.0 / 3,
+		},
+		{
+			name:     "all cached",
+			node:     buildNode("n3", nil, map[string]string{CachedDatasetsAnnotation: "ns/train, /mnt/data,imagenet"}),
+			expected: 1,
+		},
+	}
+	for _, test := range tests {
+		locality, known := source.Locality(task, test.node)
+		if !known || locality != test.expected {
+			t.Errorf("case %s: expected locality %f, got %f, known %v", test.name, test.expected, locality, known)
+		}
+	}
+
+	other := buildTask("p2", nil)
+	other.Job = "ns/j2"
+	if _, known := source.Locality(other, buildNode("n1", nil, nil)); known {
+		t.Errorf("expected task without datasets unknown to cache source")
+	}
+}
+
+func TestPVSourceLocality(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	factory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "local"},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv1"},
+	})
+	factory.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(&v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv1"},
+		Spec: v1.PersistentVolumeSpec{NodeAffinity: &v1.VolumeNodeAffinity{Required: &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{{
+				Key: "kubernetes.io/hostname", Operator: v1.NodeSelectorOpIn, Values: []string{"n1"},
+			}}}},
+		}}},
+	})
+	source := &pvSource{
+		pvcLister: factory.Core().V1().PersistentVolumeClaims().Lister(),
+		pvLister:  factory.Core().V1().PersistentVolumes().Lister(),
+	}
+	task := buildTask("p1", nil, claimVolume("local"), claimVolume("unbound"))
+
+	if locality, known := source.Locality(task, buildNode("n1", map[string]string{"kubernetes.io/hostname": "n1"}, nil)); !known || locality != 1 {
+		t.Errorf("expected n1 local to the volume, got %f, known %v", locality, known)
+	}
+	if locality, known := source.Locality(task, buildNode("n2", map[string]string{"kubernetes.io/hostname": "n2"}, nil)); !known || locality != 0 {
+		t.Errorf("expected n2 not local to the volume, got %f, known %v", locality, known)
+	}
+	if _, known := source.Locality(buildTask("p2", nil, claimVolume("unbound")), buildNode("n1", nil, nil)); known {
+		t.Errorf("expected task without bound volumes unknown to pv source")
+	}
+}
+
+type fixedSource struct {
+	locality float64
+	known    bool
+}
+
+func (s *fixedSource) Name() string {
+	return "fixed"
+}
+
+func (s *fixedSource) Locality(*api.TaskInfo, *api.NodeInfo) (float64, bool) {
+	return s.locality, s.known
+}
+
+func TestScore(t *testing.T) {
+	dp := &dataLocalityPlugin{weight: 2, sources: []LocalitySource{
+		&fixedSource{locality: 1, known: true},
+		&fixedSource{locality: 0.5, known: true},
+		&fixedSource{known: false},
+	}}
+	if score := dp.score(buildTask("p1", nil), buildNode("n1", nil, nil)); score != 150 {
+		t.Errorf("expected score 150, got %f", score)
+	}
+
+	dp.sources = []LocalitySource{&fixedSource{known: false}}
+	if score := dp.score(buildTask("p1", nil), buildNode("n1", nil, nil)); score != 0 {
+		t.Errorf("expected score 0 without known data, got %f", score)
+	}
+}
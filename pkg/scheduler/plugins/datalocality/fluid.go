@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datalocality
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// CacheLocalAnnotation is the key of annotation on pod/podgroup which requires the tasks to be placed on
+	// the cache nodes of all their Fluid datasets
+	CacheLocalAnnotation = "volcano.sh/dataset-cache-local"
+
+	fluidSourceName = "fluid"
+	// fluidLabelFormat is the label of node on which Fluid caches the dataset of namespace and name,
+	// the PVC of a Fluid dataset has the same namespace and name as the dataset
+	fluidLabelFormat = "fluid.io/s-%s-%s"
+	// fluidRefreshPeriod is the period of checking whether Fluid is installed until it is
+	fluidRefreshPeriod = 30 * time.Second
+	// fluidDiscoveryTimeout bounds the requests checking whether Fluid is installed
+	fluidDiscoveryTimeout = 10 * time.Second
+)
+
+var datasetResource = schema.GroupVersionResource{Group: "data.fluid.io", Version: "v1alpha1", Resource: "datasets"}
+
+// fluidStatus watches the cached fraction of Fluid datasets by an informer, which is started in the background once
+// Fluid is found to be installed, so the sessions never wait for the apiserver.
+type fluidStatus struct {
+	sync.Mutex
+	started bool
+	// informer watches the datasets, nil until Fluid is found to be installed
+	informer cache.SharedIndexInformer
+}
+
+var status = &fluidStatus{}
+
+// cachedFraction parses the cachedPercentage of dataset status, e.g. 75.0%.
+func cachedFraction(dataset *unstructured.Unstructured) (float64, bool) {
+	value, found, err := unstructured.NestedString(dataset.Object, "status", "cacheStates", "cachedPercentage")
+	if err != nil || !found {
+		return 0, false
+	}
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return percentage / 100, true
+}
+
+// get returns the cached fraction of Fluid datasets from the informer cache, it is nil if Fluid is not available or
+// its datasets are not synced yet. The informer is started in the background by the first call with config.
+func (fs *fluidStatus) get(config *rest.Config) map[string]float64 {
+	fs.Lock()
+	defer fs.Unlock()
+
+	if !fs.started && config != nil {
+		fs.started = true
+		go fs.discover(config, wait.NeverStop)
+	}
+	if fs.informer == nil || !fs.informer.HasSynced() {
+		return nil
+	}
+
+	fractions := map[string]float64{}
+	for _, obj := range fs.informer.GetStore().List() {
+		dataset, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		if fraction, found := cachedFraction(dataset); found {
+			fractions[dataset.GetNamespace()+"/"+dataset.GetName()] = fraction
+		}
+	}
+	return fractions
+}
+
+// discover checks whether Fluid is installed every fluidRefreshPeriod, each check bounded by fluidDiscoveryTimeout,
+// and starts the informer of datasets once it is.
+func (fs *fluidStatus) discover(config *rest.Config, stopCh <-chan struct{}) {
+	config = rest.CopyConfig(config)
+	config.Timeout = fluidDiscoveryTimeout
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		klog.V(4).Infof("Failed to create client of Fluid datasets: %v", err)
+		return
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		klog.V(4).Infof("Failed to create discovery client of Fluid datasets: %v", err)
+		return
+	}
+
+	wait.Until(func() {
+		if fs.watching() {
+			return
+		}
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(datasetResource.GroupVersion().String())
+		if err != nil {
+			klog.V(4).Infof("Cache status of Fluid datasets is not available: %v", err)
+			return
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == datasetResource.Resource {
+				fs.watch(client, stopCh)
+				return
+			}
+		}
+	}, fluidRefreshPeriod, stopCh)
+}
+
+func (fs *fluidStatus) watching() bool {
+	fs.Lock()
+	defer fs.Unlock()
+	return fs.informer != nil
+}
+
+// watch starts the informer of datasets until stopCh is closed.
+func (fs *fluidStatus) watch(client dynamic.Interface, stopCh <-chan struct{}) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, datasetResource, metav1.NamespaceAll, 0,
+		cache.Indexers{}, nil).Informer()
+	fs.Lock()
+	fs.informer = informer
+	fs.Unlock()
+	go informer.Run(stopCh)
+}
+
+// fluidSource prefers the nodes holding cached blocks of the Fluid datasets of the task, i.e. the nodes
+// labeled by Fluid, in proportion to the cached fraction of the datasets in their status.
+type fluidSource struct {
+	// fractions is the cached fraction of datasets, nil if the status of Fluid is not available,
+	// and labeled nodes are then treated as fully cached
+	fractions map[string]float64
+	// labeled tells whether any node in the session is labeled by Fluid for the label
+	labeled map[string]bool
+}
+
+func newFluidSource(ssn *framework.Session) LocalitySource {
+	source := &fluidSource{
+		fractions: status.get(ssn.ClientConfig()),
+		labeled:   map[string]bool{},
+	}
+	for _, node := range ssn.Nodes {
+		if node.Node == nil {
+			continue
+		}
+		for label, value := range node.Node.Labels {
+			if strings.HasPrefix(label, "fluid.io/s-") && value == "true" {
+				source.labeled[label] = true
+			}
+		}
+	}
+	return source
+}
+
+func (s *fluidSource) Name() string {
+	return fluidSourceName
+}
+
+type fluidDataset struct {
+	key   string
+	label string
+}
+
+// datasetsOf returns the Fluid datasets of task, i.e. the PVCs known by the status of Fluid or labeled on nodes.
+func (s *fluidSource) datasetsOf(task *api.TaskInfo) []fluidDataset {
+	var datasets []fluidDataset
+	for _, volume := range task.Pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claim := volume.PersistentVolumeClaim.ClaimName
+		dataset := fluidDataset{
+			key:   task.Namespace + "/" + claim,
+			label: fmt.Sprintf(fluidLabelFormat, task.Namespace, claim),
+		}
+		if _, found := s.fractions[dataset.key]; found || s.labeled[dataset.label] {
+			datasets = append(datasets, dataset)
+		}
+	}
+	return datasets
+}
+
+func (s *fluidSource) Locality(task *api.TaskInfo, node *api.NodeInfo) (float64, bool) {
+	if task.Pod == nil || node.Node == nil {
+		return 0, false
+	}
+	datasets := s.datasetsOf(task)
+	if len(datasets) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, dataset := range datasets {
+		if node.Node.Labels[dataset.label] != "true" {
+			continue
+		}
+		fraction, found := s.fractions[dataset.key]
+		if !found {
+			fraction = 1
+		}
+		total += fraction
+	}
+	return total / float64(len(datasets)), true
+}
+
+// cacheLocal checks whether node is a cache node of all the Fluid datasets of task.
+func (s *fluidSource) cacheLocal(task *api.TaskInfo, node *api.NodeInfo) bool {
+	if task.Pod == nil || node.Node == nil {
+		return true
+	}
+	for _, dataset := range s.datasetsOf(task) {
+		if node.Node.Labels[dataset.label] != "true" {
+			return false
+		}
+	}
+	return true
+}
+
+// requireCacheLocal checks whether the task or its job requires to be placed on the cache nodes of its datasets.
+func requireCacheLocal(task *api.TaskInfo, jobs map[api.JobID]*api.JobInfo) bool {
+	if task.Pod != nil && task.Pod.Annotations[CacheLocalAnnotation] == "true" {
+		return true
+	}
+	if job, found := jobs[task.Job]; found && job.PodGroup != nil {
+		return job.PodGroup.Annotations[CacheLocalAnnotation] == "true"
+	}
+	return false
+}
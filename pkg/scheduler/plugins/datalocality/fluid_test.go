@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datalocality
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func buildDataset(namespace, name, cachedPercentage string) *unstructured.Unstructured {
+	dataset := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "data.fluid.io/v1alpha1",
+		"kind":       "Dataset",
+		"status": map[string]interface{}{
+			"cacheStates": map[string]interface{}{"cachedPercentage": cachedPercentage},
+		},
+	}}
+	dataset.SetNamespace(namespace)
+	dataset.SetName(name)
+	return dataset
+}
+
+func TestFluidStatus(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{datasetResource: "DatasetList"},
+		buildDataset("ns", "train", "75.0%"), buildDataset("ns", "empty", ""))
+	fs := &fluidStatus{started: true}
+
+	if fractions := fs.get(nil); fractions != nil {
+		t.Errorf("expected no cached fraction before datasets are watched, got %v", fractions)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	fs.watch(client, stopCh)
+	if !cache.WaitForCacheSync(stopCh, fs.informer.HasSynced) {
+		t.Fatalf("failed to sync datasets")
+	}
+	fractions := fs.get(nil)
+	if len(fractions) != 1 || fractions["ns/train"] != 0.75 {
+		t.Errorf("expected cached fraction 0.75 of ns/train, got %v", fractions)
+	}
+}
+
+func TestFluidSource(t *testing.T) {
+	source := &fluidSource{
+		fractions: map[string]float64{"ns/train": 0.5},
+		labeled:   map[string]bool{"fluid.io/s-ns-train": true, "fluid.io/s-ns-coco": true},
+	}
+	cacheNode := buildNode("n1", map[string]string{"fluid.io/s-ns-train": "true", "fluid.io/s-ns-coco": "true"}, nil)
+	otherNode := buildNode("n2", map[string]string{"fluid.io/s-ns-coco": "true"}, nil)
+	task := buildTask("p1", nil, claimVolume("train"), claimVolume("coco"), claimVolume("scratch"))
+
+	// coco is labeled without status, its cache nodes are treated as fully cached
+	if locality, known := source.Locality(task, cacheNode); !known || locality != 0.75 {
+		t.Errorf("expected locality 0.75 of cache node, got %f, known %v", locality, known)
+	}
+	if locality, known := source.Locality(task, otherNode); !known || locality != 0.5 {
+		t.Errorf("expected locality 0.5 of other node, got %f, known %v", locality, known)
+	}
+	if _, known := source.Locality(buildTask("p2", nil, claimVolume("scratch")), cacheNode); known {
+		t.Errorf("expected task without Fluid datasets unknown to fluid source")
+	}
+
+	if !source.cacheLocal(task, cacheNode) || source.cacheLocal(task, otherNode) {
+		t.Errorf("expected only n1 cache-local to task")
+	}
+}
+
+func TestRequireCacheLocal(t *testing.T) {
+	job := &api.JobInfo{UID: "ns/j1", PodGroup: &api.PodGroup{PodGroup: scheduling.PodGroup{}}}
+	job.PodGroup.Annotations = map[string]string{CacheLocalAnnotation: "true"}
+	jobs := map[api.JobID]*api.JobInfo{job.UID: job}
+
+	if !requireCacheLocal(buildTask("p1", nil), jobs) {
+		t.Errorf("expected task of cache-local job cache-local")
+	}
+	other := buildTask("p2", map[string]string{CacheLocalAnnotation: "true"})
+	other.Job = "ns/j2"
+	if !requireCacheLocal(other, jobs) {
+		t.Errorf("expected cache-local task cache-local")
+	}
+	other.Pod.Annotations = nil
+	if requireCacheLocal(other, jobs) {
+		t.Errorf("expected task not cache-local")
+	}
+}
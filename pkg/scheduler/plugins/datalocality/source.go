@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datalocality
+
+import (
+	"strings"
+
+	corev1 "k8s.io/client-go/listers/core/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// DatasetsAnnotation is the key of annotation on pod/podgroup which lists the comma separated datasets read by
+	// the task/job besides its volumes, e.g. datasets cached by Alluxio
+	DatasetsAnnotation = "volcano.sh/datasets"
+	// CachedDatasetsAnnotation is the key of annotation on node which lists the comma separated datasets cached on
+	// the node, a dataset is the namespace/name of a PVC, a hostPath, or a dataset in volcano.sh/datasets annotation
+	CachedDatasetsAnnotation = "volcano.sh/cached-datasets"
+)
+
+// LocalitySource tells how local a node is to the input data of a task.
+type LocalitySource interface {
+	// Name returns the name of source
+	Name() string
+	// Locality returns the locality of node to the data of task in [0, 1],
+	// known is false if the task reads no data known by the source.
+	Locality(task *api.TaskInfo, node *api.NodeInfo) (locality float64, known bool)
+}
+
+// LocalitySourceBuilder builds a source of data locality for the session.
+type LocalitySourceBuilder func(ssn *framework.Session) LocalitySource
+
+var sourceBuilders = map[string]LocalitySourceBuilder{}
+
+// RegisterLocalitySource registers the builder of a source of data locality, e.g. for other cache systems.
+func RegisterLocalitySource(name string, builder LocalitySourceBuilder) {
+	sourceBuilders[name] = builder
+}
+
+func init() {
+	RegisterLocalitySource(pvSourceName, newPVSource)
+	RegisterLocalitySource(cacheSourceName, newCacheSource)
+	RegisterLocalitySource(fluidSourceName, newFluidSource)
+}
+
+const pvSourceName = "pv"
+
+// pvSource prefers nodes matching the node affinity of the persistent volumes bound to the task, e.g. local volumes.
+type pvSource struct {
+	pvcLister corev1.PersistentVolumeClaimLister
+	pvLister  corev1.PersistentVolumeLister
+}
+
+func newPVSource(ssn *framework.Session) LocalitySource {
+	factory := ssn.InformerFactory()
+	if factory == nil {
+		return nil
+	}
+	return &pvSource{
+		pvcLister: factory.Core().V1().PersistentVolumeClaims().Lister(),
+		pvLister:  factory.Core().V1().PersistentVolumes().Lister(),
+	}
+}
+
+func (s *pvSource) Name() string {
+	return pvSourceName
+}
+
+func (s *pvSource) Locality(task *api.TaskInfo, node *api.NodeInfo) (float64, bool) {
+	if task.Pod == nil || node.Node == nil {
+		return 0, false
+	}
+
+	total, local := 0, 0
+	for _, volume := range task.Pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := s.pvcLister.PersistentVolumeClaims(task.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+		if err != nil || len(pvc.Spec.VolumeName) == 0 {
+			// unbound claims are placed by volume binding
+			continue
+		}
+		pv, err := s.pvLister.Get(pvc.Spec.VolumeName)
+		if err != nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+
+		total++
+		matched, err := corev1helpers.MatchNodeSelectorTerms(node.Node, pv.Spec.NodeAffinity.Required)
+		if err != nil {
+			klog.V(4).Infof("Failed to match node affinity of persistent volume %s: %v", pv.Name, err)
+			continue
+		}
+		if matched {
+			local++
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(local) / float64(total), true
+}
+
+const cacheSourceName = "cache"
+
+// cacheSource prefers nodes caching the datasets of the task by the volcano.sh/cached-datasets annotation of node.
+type cacheSource struct {
+	jobs map[api.JobID]*api.JobInfo
+}
+
+func newCacheSource(ssn *framework.Session) LocalitySource {
+	return &cacheSource{jobs: ssn.Jobs}
+}
+
+func (s *cacheSource) Name() string {
+	return cacheSourceName
+}
+
+func splitDatasets(value string) []string {
+	var datasets []string
+	for _, dataset := range strings.Split(value, ",") {
+		if dataset = strings.TrimSpace(dataset); len(dataset) != 0 {
+			datasets = append(datasets, dataset)
+		}
+	}
+	return datasets
+}
+
+// datasetsOf returns the datasets read by task, the ones of its job are included.
+func (s *cacheSource) datasetsOf(task *api.TaskInfo) []string {
+	var datasets []string
+	for _, volume := range task.Pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			datasets = append(datasets, task.Namespace+"/"+volume.PersistentVolumeClaim.ClaimName)
+		case volume.HostPath != nil:
+			datasets = append(datasets, volume.HostPath.Path)
+		}
+	}
+
+	annotations := []string{task.Pod.Annotations[DatasetsAnnotation]}
+	if job, found := s.jobs[task.Job]; found && job.PodGroup != nil {
+		annotations = append(annotations, job.PodGroup.Annotations[DatasetsAnnotation])
+	}
+	for _, value := range annotations {
+		datasets = append(datasets, splitDatasets(value)...)
+	}
+	return datasets
+}
+
+func (s *cacheSource) Locality(task *api.TaskInfo, node *api.NodeInfo) (float64, bool) {
+	if task.Pod == nil || node.Node == nil {
+		return 0, false
+	}
+	datasets := s.datasetsOf(task)
+	if len(datasets) == 0 {
+		return 0, false
+	}
+
+	cached := map[string]bool{}
+	for _, key := range splitDatasets(node.Node.Annotations[CachedDatasetsAnnotation]) {
+		cached[key] = true
+	}
+
+	local := 0
+	for _, dataset := range datasets {
+		if cached[dataset] {
+			local++
+		}
+	}
+	return float64(local) / float64(len(datasets)), true
+}
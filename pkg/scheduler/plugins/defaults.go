@@ -34,6 +34,12 @@ func ApplyPluginConfDefaults(option *conf.PluginOption) {
 	if option.EnabledJobEnqueued == nil {
 		option.EnabledJobEnqueued = &t
 	}
+	if option.EnabledJobEnqueueable == nil {
+		option.EnabledJobEnqueueable = option.EnabledJobEnqueued
+	}
+	if option.EnabledJobValid == nil {
+		option.EnabledJobValid = &t
+	}
 	if option.EnabledTaskOrder == nil {
 		option.EnabledTaskOrder = &t
 	}
@@ -46,9 +52,15 @@ func ApplyPluginConfDefaults(option *conf.PluginOption) {
 	if option.EnabledQueueOrder == nil {
 		option.EnabledQueueOrder = &t
 	}
+	if option.EnabledClusterOrder == nil {
+		option.EnabledClusterOrder = &t
+	}
 	if option.EnabledPredicate == nil {
 		option.EnabledPredicate = &t
 	}
+	if option.EnabledPrePredicate == nil {
+		option.EnabledPrePredicate = option.EnabledPredicate
+	}
 	if option.EnabledBestNode == nil {
 		option.EnabledBestNode = &t
 	}
@@ -64,6 +76,9 @@ func ApplyPluginConfDefaults(option *conf.PluginOption) {
 	if option.EnabledVictim == nil {
 		option.EnabledVictim = &t
 	}
+	if option.EnabledVictimVeto == nil {
+		option.EnabledVictimVeto = &t
+	}
 	if option.EnabledJobStarving == nil {
 		option.EnabledJobStarving = &t
 	}
@@ -35,8 +35,22 @@ import (
 // PluginName indicates name of volcano scheduler plugin.
 const PluginName = "drf"
 
+const (
+	// DRFResources is the key of the comma separated resources counted in the dominant share,
+	// e.g. cpu, memory, nvidia.com/gpu; all the resources are counted if it is not set
+	DRFResources = "drf.resources"
+	// DRFResourcesPrefix is the key prefix of the weight of a resource in the dominant share,
+	// e.g. drf.resources.nvidia.com/gpu: 2
+	DRFResourcesPrefix = DRFResources + "."
+)
+
 var shareDelta = 0.000001
 
+// ownJobsHierarchy is the name of the child node holding the jobs of a queue in the hierarchy,
+// so that the jobs of a queue with sub-queues compete with them as a child of weight 1. The jobs
+// are ordered by the job order, so the share of the node is the share of all the jobs.
+const ownJobsHierarchy = "."
+
 // hierarchicalNode represents the node hierarchy
 // and the corresponding weight and drf attribute
 type hierarchicalNode struct {
@@ -74,21 +88,52 @@ func (node *hierarchicalNode) Clone(parent *hierarchicalNode) *hierarchicalNode
 	return newNode
 }
 
-// resourceSaturated returns true if any resource of the job is saturated or the job demands fully allocated resource
+// resourceSaturated returns true if the job requests nothing, any resource of the job is saturated
+// or the job demands fully allocated resource
 func resourceSaturated(allocated *api.Resource,
 	jobRequest *api.Resource, demandingResources map[v1.ResourceName]bool) bool {
-	for _, rn := range allocated.ResourceNames() {
-		if allocated.Get(rn) != 0 && jobRequest.Get(rn) != 0 &&
-			allocated.Get(rn) >= jobRequest.Get(rn) {
+	resourceNames := jobRequest.ResourceNames()
+	if len(resourceNames) == 0 {
+		return true
+	}
+	for _, rn := range resourceNames {
+		if allocated.Get(rn) >= jobRequest.Get(rn) {
 			return true
 		}
-		if !demandingResources[rn] && jobRequest.Get(rn) != 0 {
+		if !demandingResources[rn] {
 			return true
 		}
 	}
 	return false
 }
 
+// weightedShare returns the share of the node divided by its weight, or the share if its weight is zero.
+func (node *hierarchicalNode) weightedShare() float64 {
+	if node.weight == 0 {
+		return node.attr.share
+	}
+	return node.attr.share / node.weight
+}
+
+// compareNodes compares the sibling nodes by their weighted shares. Saturated nodes are ordered last, and
+// nodes of zero weight, which only take the resources left by their demanding siblings, are ordered after
+// the nodes of positive weight.
+func compareNodes(lnode, rnode *hierarchicalNode) float64 {
+	if lnode.saturated != rnode.saturated {
+		if lnode.saturated {
+			return 1
+		}
+		return -1
+	}
+	if (lnode.weight == 0) != (rnode.weight == 0) {
+		if lnode.weight == 0 {
+			return 1
+		}
+		return -1
+	}
+	return lnode.weightedShare() - rnode.weightedShare()
+}
+
 type drfAttr struct {
 	share            float64
 	dominantResource string
@@ -113,6 +158,10 @@ type drfPlugin struct {
 	// hierarchical tree root
 	hierarchicalRoot *hierarchicalNode
 
+	// resourceWeights are the weights of the resources counted in the dominant share,
+	// all the resources are counted with weight 1 if it is nil
+	resourceWeights map[v1.ResourceName]float64
+
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
 }
@@ -131,10 +180,37 @@ func New(arguments framework.Arguments) framework.Plugin {
 			weight:    1,
 			children:  map[string]*hierarchicalNode{},
 		},
+		resourceWeights: parseResourceWeights(arguments),
 		pluginArguments: arguments,
 	}
 }
 
+// parseResourceWeights parses the resources counted in the dominant share and their weights, e.g.
+// `drf.resources: cpu, memory, nvidia.com/gpu` with `drf.resources.nvidia.com/gpu: 2`.
+func parseResourceWeights(args framework.Arguments) map[v1.ResourceName]float64 {
+	resourcesStr, ok := args[DRFResources].(string)
+	if !ok || strings.TrimSpace(resourcesStr) == "" {
+		return nil
+	}
+
+	weights := map[v1.ResourceName]float64{}
+	for _, resource := range strings.Split(resourcesStr, ",") {
+		resource = strings.TrimSpace(resource)
+		if resource == "" {
+			continue
+		}
+
+		weight := 1.0
+		args.GetFloat64(&weight, DRFResourcesPrefix+resource)
+		if weight < 0 {
+			klog.Warningf("invalid weight %v of resource %s in drf, use 1 instead", weight, resource)
+			weight = 1
+		}
+		weights[v1.ResourceName(resource)] = weight
+	}
+	return weights
+}
+
 func (drf *drfPlugin) Name() string {
 	return PluginName
 }
@@ -152,33 +228,31 @@ func (drf *drfPlugin) HierarchyEnabled(ssn *framework.Session) bool {
 	return false
 }
 
+// compareQueues compares the nodes of the queues from the root down to the first level they differ at,
+// the jobs of a queue are compared with its sub-queues by the node holding them.
 func (drf *drfPlugin) compareQueues(root *hierarchicalNode, lqueue *api.QueueInfo, rqueue *api.QueueInfo) float64 {
 	lnode := root
-	lpaths := strings.Split(lqueue.Hierarchy, "/")
+	lpaths := append(strings.Split(lqueue.Hierarchy, "/"), ownJobsHierarchy)
 	rnode := root
-	rpaths := strings.Split(rqueue.Hierarchy, "/")
-	depth := 0
-	if len(lpaths) < len(rpaths) {
-		depth = len(lpaths)
-	} else {
-		depth = len(rpaths)
-	}
-	for i := 0; i < depth; i++ {
-		// Saturated nodes have minumun prioirty,
-		// so that demanding nodes will be poped first.
-		if !lnode.saturated && rnode.saturated {
-			return -1
+	rpaths := append(strings.Split(rqueue.Hierarchy, "/"), ownJobsHierarchy)
+	for i := 1; i < len(lpaths) && i < len(rpaths); i++ {
+		lnode = lnode.children[lpaths[i]]
+		rnode = rnode.children[rpaths[i]]
+		// Queues without jobs are not in the hierarchy, they have nothing to schedule.
+		if lnode == nil || rnode == nil {
+			if lnode != nil {
+				return -1
+			}
+			if rnode != nil {
+				return 1
+			}
+			return 0
 		}
-		if lnode.saturated && !rnode.saturated {
-			return 1
+		if lnode == rnode {
+			continue
 		}
-		if lnode.attr.share/lnode.weight == rnode.attr.share/rnode.weight {
-			if i < depth-1 {
-				lnode = lnode.children[lpaths[i+1]]
-				rnode = rnode.children[rpaths[i+1]]
-			}
-		} else {
-			return lnode.attr.share/lnode.weight - rnode.attr.share/rnode.weight
+		if ret := compareNodes(lnode, rnode); ret != 0 {
+			return ret
 		}
 	}
 	return 0
@@ -192,7 +266,8 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 	hierarchyEnabled := drf.HierarchyEnabled(ssn)
 
-	for _, job := range ssn.Jobs {
+	// The jobs of other profiles are counted in the shares of their queues as well.
+	for _, job := range append(jobList(ssn.Jobs), jobList(ssn.OtherProfileJobs())...) {
 		attr := &drfAttr{
 			allocated: api.EmptyResource(),
 		}
@@ -389,16 +464,19 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 func (drf *drfPlugin) buildHierarchy(root *hierarchicalNode, job *api.JobInfo, attr *drfAttr,
 	hierarchy, hierarchicalWeights string) {
 	inode := root
-	paths := strings.Split(hierarchy, "/")
+	paths := append(strings.Split(hierarchy, "/"), ownJobsHierarchy)
 	weights := strings.Split(hierarchicalWeights, "/")
 
 	for i := 1; i < len(paths); i++ {
 		if child, ok := inode.children[paths[i]]; ok {
 			inode = child
 		} else {
-			fweight, _ := strconv.ParseFloat(weights[i], 64)
-			if fweight < 1 {
-				fweight = 1
+			// The weight is 1 if it is not given or invalid, a zero weight is kept.
+			fweight := float64(1)
+			if i < len(weights) && paths[i] != ownJobsHierarchy {
+				if w, err := strconv.ParseFloat(weights[i], 64); err == nil && w >= 0 {
+					fweight = w
+				}
 			}
 			child = &hierarchicalNode{
 				weight:    fweight,
@@ -439,15 +517,16 @@ func (drf *drfPlugin) updateHierarchicalShare(node *hierarchicalNode,
 		klog.V(4).Infof("Update hierarchical node %s, share %f, dominant %s, resource %v, saturated: %t",
 			node.hierarchy, node.attr.share, node.attr.dominantResource, node.attr.allocated, node.saturated)
 	} else {
-		var mdr float64 = 1
-		// get minimun dominant resource share
+		// get minimum weighted dominant resource share of the demanding children, an empty demanding
+		// child makes it zero, so that the nodes above a starving queue are not ordered by its siblings only.
+		mdr := math.MaxFloat64
+		node.request = api.EmptyResource()
 		for _, child := range node.children {
 			drf.updateHierarchicalShare(child, demandingResources)
-			// skip empty child and saturated child
-			if child.attr.share != 0 && !child.saturated {
-				_, resShare := drf.calculateShare(child.attr.allocated, drf.totalResource)
-				if resShare < mdr {
-					mdr = resShare
+			node.request.Add(child.request)
+			if !child.saturated && child.weight != 0 {
+				if share := child.weightedShare(); share < mdr {
+					mdr = share
 				}
 			}
 		}
@@ -458,16 +537,12 @@ func (drf *drfPlugin) updateHierarchicalShare(node *hierarchicalNode,
 			if !child.saturated {
 				saturated = false
 			}
-			// only consider non-empty children
-			if child.attr.share != 0 {
-				// saturated child is not scaled
-				if child.saturated {
-					t := child.attr.allocated
-					node.attr.allocated.Add(t)
-				} else {
-					t := child.attr.allocated.Clone().Multi(mdr / child.attr.share)
-					node.attr.allocated.Add(t)
-				}
+			// saturated children, children of zero weight, empty children and jobs are not scaled
+			if child.saturated || child.weight == 0 || child.attr.share == 0 || node.hierarchy == ownJobsHierarchy {
+				node.attr.allocated.Add(child.attr.allocated)
+			} else {
+				// scale the demanding child as if the children were allocated in proportion to their weights
+				node.attr.allocated.Add(child.attr.allocated.Clone().Multi(mdr * child.weight / child.attr.share))
 			}
 		}
 		node.attr.dominantResource, node.attr.share = drf.calculateShare(
@@ -504,6 +579,13 @@ func (drf *drfPlugin) calculateShare(allocated, totalResource *api.Resource) (st
 	dominantResource := ""
 	for _, rn := range totalResource.ResourceNames() {
 		share := helpers.Share(allocated.Get(rn), totalResource.Get(rn))
+		if drf.resourceWeights != nil {
+			weight, found := drf.resourceWeights[rn]
+			if !found {
+				continue
+			}
+			share *= weight
+		}
 		if share > res {
 			res = share
 			dominantResource = string(rn)
@@ -519,3 +601,12 @@ func (drf *drfPlugin) OnSessionClose(session *framework.Session) {
 	drf.totalAllocated = api.EmptyResource()
 	drf.jobAttrs = map[api.JobID]*drfAttr{}
 }
+
+// jobList returns the jobs of the map.
+func jobList(jobs map[api.JobID]*api.JobInfo) []*api.JobInfo {
+	list := make([]*api.JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job)
+	}
+	return list
+}
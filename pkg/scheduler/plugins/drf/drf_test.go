@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"math"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestCalculateShareResourceWeights(t *testing.T) {
+	resources := func(cpu, memory, gpu string) *api.Resource {
+		return api.NewResource(v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+			"nvidia.com/gpu":  resource.MustParse(gpu),
+		})
+	}
+	total := resources("100", "100Gi", "8")
+	allocated := resources("40", "10Gi", "2")
+
+	tests := []struct {
+		name             string
+		arguments        framework.Arguments
+		expectedResource string
+		expectedShare    float64
+	}{
+		{
+			name:             "all resources by default",
+			expectedResource: "cpu",
+			expectedShare:    0.4,
+		},
+		{
+			name:             "only listed resources",
+			arguments:        framework.Arguments{DRFResources: "memory, nvidia.com/gpu"},
+			expectedResource: "nvidia.com/gpu",
+			expectedShare:    0.25,
+		},
+		{
+			name: "weighted resource",
+			arguments: framework.Arguments{
+				DRFResources:                          "cpu, memory, nvidia.com/gpu",
+				DRFResourcesPrefix + "nvidia.com/gpu": 2,
+			},
+			expectedResource: "nvidia.com/gpu",
+			expectedShare:    0.5,
+		},
+		{
+			name: "negative weight is reset",
+			arguments: framework.Arguments{
+				DRFResources:               "cpu, nvidia.com/gpu",
+				DRFResourcesPrefix + "cpu": -1.5,
+			},
+			expectedResource: "cpu",
+			expectedShare:    0.4,
+		},
+	}
+
+	for _, test := range tests {
+		drf := New(test.arguments).(*drfPlugin)
+		dominantResource, share := drf.calculateShare(allocated, total)
+		if dominantResource != test.expectedResource || math.Abs(share-test.expectedShare) > shareDelta {
+			t.Errorf("case %s: expected dominant resource %s with share %v, got %s with %v", test.name,
+				test.expectedResource, test.expectedShare, dominantResource, share)
+		}
+	}
+}
@@ -211,12 +211,6 @@ func TestHDRF(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		if test.name == "blocking nodes test" {
-			// TODO(wangyang0616): First make sure that ut can run, and then fix the failed ut later
-			// See issue for details: https://github.com/volcano-sh/volcano/issues/2810
-			t.Skip("Test cases are not as expected, fixed later. see issue: #2810")
-		}
-
 		binder := &util.FakeBinder{
 			Binds:   map[string]string{},
 			Channel: make(chan string),
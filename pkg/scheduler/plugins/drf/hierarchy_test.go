@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+type hierarchyJob struct {
+	hierarchy string
+	weights   string
+	allocated *api.Resource
+	request   *api.Resource
+}
+
+// buildTestHierarchy builds the hierarchy of the jobs like OnSessionOpen.
+func buildTestHierarchy(total *api.Resource, jobs []hierarchyJob) (*drfPlugin, map[string]*api.QueueInfo) {
+	drf := New(nil).(*drfPlugin)
+	drf.totalResource = total
+	queues := map[string]*api.QueueInfo{}
+	for i, j := range jobs {
+		job := &api.JobInfo{UID: api.JobID(fmt.Sprintf("job%d", i)), TotalRequest: j.request}
+		attr := &drfAttr{allocated: j.allocated.Clone()}
+		drf.updateShare(attr)
+		drf.totalAllocated.Add(attr.allocated)
+		drf.UpdateHierarchicalShare(drf.hierarchicalRoot, drf.totalAllocated, job, attr, j.hierarchy, j.weights)
+		queues[j.hierarchy] = &api.QueueInfo{Hierarchy: j.hierarchy, Weights: j.weights}
+	}
+	return drf, queues
+}
+
+func TestCompareQueues(t *testing.T) {
+	cpu := func(value float64) *api.Resource {
+		return &api.Resource{MilliCPU: value * 1000}
+	}
+	drf, queues := buildTestHierarchy(cpu(10), []hierarchyJob{
+		{hierarchy: "root/a/a1", weights: "1/1/1", allocated: cpu(5), request: cpu(10)},
+		{hierarchy: "root/a/a2/x", weights: "1/1/1/1", allocated: cpu(0), request: cpu(4)},
+		{hierarchy: "root/a", weights: "1/1", allocated: cpu(1), request: cpu(2)},
+		{hierarchy: "root/b", weights: "1/1", allocated: cpu(2), request: cpu(10)},
+		{hierarchy: "root/c", weights: "1/4", allocated: cpu(1), request: cpu(10)},
+		{hierarchy: "root/z", weights: "1/0", allocated: cpu(0), request: cpu(10)},
+	})
+
+	tests := []struct {
+		name   string
+		lqueue string
+		rqueue string
+		less   bool
+	}{
+		{
+			name:   "deep empty sub-queue is not starved by its siblings",
+			lqueue: "root/a/a2/x",
+			rqueue: "root/b",
+			less:   true,
+		},
+		{
+			name:   "empty sub-queue goes before its sibling",
+			lqueue: "root/a/a2/x",
+			rqueue: "root/a/a1",
+			less:   true,
+		},
+		{
+			name:   "jobs of a queue compete with its sub-queues",
+			lqueue: "root/a",
+			rqueue: "root/a/a1",
+			less:   true,
+		},
+		{
+			name:   "share is divided by weight",
+			lqueue: "root/c",
+			rqueue: "root/b",
+			less:   true,
+		},
+		{
+			name:   "zero weight queue goes after demanding siblings",
+			lqueue: "root/b",
+			rqueue: "root/z",
+			less:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lqueue, rqueue := queues[test.lqueue], queues[test.rqueue]
+			if less := drf.compareQueues(drf.hierarchicalRoot, lqueue, rqueue) < 0; less != test.less {
+				t.Errorf("expected %s less than %s %t, got %t", test.lqueue, test.rqueue, test.less, less)
+			}
+			if less := drf.compareQueues(drf.hierarchicalRoot, rqueue, lqueue) < 0; less == test.less {
+				t.Errorf("expected %s less than %s %t, got %t", test.rqueue, test.lqueue, !test.less, less)
+			}
+		})
+	}
+}
+
+// randomHierarchyJobs generates jobs in random queues of up to 3 levels, the weights of a path are the same
+// for all the queues under it.
+func randomHierarchyJobs(r *rand.Rand) (*api.Resource, []hierarchyJob) {
+	weightChoices := []string{"0", "0.5", "1", "2", "3"}
+	weights := map[string]string{"root": "1"}
+	total := &api.Resource{}
+	var jobs []hierarchyJob
+	for i := 0; i < 1+r.Intn(8); i++ {
+		paths, pathWeights := []string{"root"}, []string{"1"}
+		for depth := 0; depth < 1+r.Intn(3); depth++ {
+			paths = append(paths, string(rune('a'+r.Intn(3))))
+			path := strings.Join(paths, "/")
+			if _, found := weights[path]; !found {
+				weights[path] = weightChoices[r.Intn(len(weightChoices))]
+			}
+			pathWeights = append(pathWeights, weights[path])
+		}
+		request := &api.Resource{MilliCPU: float64(r.Intn(5) * 1000), Memory: float64(r.Intn(5) * 1000)}
+		allocated := &api.Resource{
+			MilliCPU: float64(r.Intn(int(request.MilliCPU/1000)+1) * 1000),
+			Memory:   float64(r.Intn(int(request.Memory/1000)+1) * 1000),
+		}
+		total.Add(allocated)
+		jobs = append(jobs, hierarchyJob{
+			hierarchy: strings.Join(paths, "/"),
+			weights:   strings.Join(pathWeights, "/"),
+			allocated: allocated,
+			request:   request,
+		})
+	}
+	// Leave some resources free, or none so that the jobs are blocked.
+	total.Add(&api.Resource{MilliCPU: float64(r.Intn(3) * 1000), Memory: float64(r.Intn(3) * 1000)})
+	total.Add(&api.Resource{MilliCPU: 1000, Memory: 1000})
+	return total, jobs
+}
+
+func TestHierarchyProperties(t *testing.T) {
+	property := func(seed int64) bool {
+		total, jobs := randomHierarchyJobs(rand.New(rand.NewSource(seed)))
+		drf, queues := buildTestHierarchy(total, jobs)
+
+		var check func(node *hierarchicalNode) error
+		check = func(node *hierarchicalNode) error {
+			if node.children == nil {
+				return nil
+			}
+			saturated, demandingEmpty := true, false
+			actual, unscaled := api.EmptyResource(), api.EmptyResource()
+			for _, child := range node.children {
+				if err := check(child); err != nil {
+					return err
+				}
+				saturated = saturated && child.saturated
+				demandingEmpty = demandingEmpty || (!child.saturated && child.weight != 0 && child.attr.share == 0)
+				actual.Add(child.attr.allocated)
+				if child.saturated || child.weight == 0 {
+					unscaled.Add(child.attr.allocated)
+				}
+			}
+			if node.saturated != saturated {
+				return fmt.Errorf("node %s saturated %t, but its children saturated %t", node.hierarchy, node.saturated, saturated)
+			}
+			// Children are only scaled down.
+			if _, share := drf.calculateShare(actual, drf.totalResource); node.attr.share > share+shareDelta {
+				return fmt.Errorf("node %s share %f is greater than the share %f of its children", node.hierarchy, node.attr.share, share)
+			}
+			// An empty demanding child keeps the node from the share of its demanding siblings.
+			if _, share := drf.calculateShare(unscaled, drf.totalResource); demandingEmpty &&
+				node.hierarchy != ownJobsHierarchy && math.Abs(node.attr.share-share) > shareDelta {
+				return fmt.Errorf("node %s share %f with empty demanding child, expected %f", node.hierarchy, node.attr.share, share)
+			}
+			for _, lchild := range node.children {
+				for _, rchild := range node.children {
+					if !lchild.saturated && !rchild.saturated && lchild.weight == 0 && rchild.weight != 0 &&
+						compareNodes(lchild, rchild) <= 0 {
+						return fmt.Errorf("node %s of zero weight goes before its sibling %s", lchild.hierarchy, rchild.hierarchy)
+					}
+				}
+			}
+			return nil
+		}
+		if err := check(drf.hierarchicalRoot); err != nil {
+			t.Logf("seed %d: %v", seed, err)
+			return false
+		}
+
+		sign := func(value float64) int {
+			if value < 0 {
+				return -1
+			}
+			if value > 0 {
+				return 1
+			}
+			return 0
+		}
+		for _, lqueue := range queues {
+			if ret := drf.compareQueues(drf.hierarchicalRoot, lqueue, lqueue); ret != 0 {
+				t.Logf("seed %d: queue %s is not equal to itself: %f", seed, lqueue.Hierarchy, ret)
+				return false
+			}
+			for _, rqueue := range queues {
+				lr := sign(drf.compareQueues(drf.hierarchicalRoot, lqueue, rqueue))
+				rl := sign(drf.compareQueues(drf.hierarchicalRoot, rqueue, lqueue))
+				if lr != -rl {
+					t.Logf("seed %d: queues %s and %s are compared %d and %d", seed, lqueue.Hierarchy, rqueue.Hierarchy, lr, rl)
+					return false
+				}
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
@@ -48,6 +48,15 @@ type PreemptableResponse struct {
 type ReclaimableRequest PreemptableRequest
 type ReclaimableResponse PreemptableResponse
 
+type VictimVetoRequest struct {
+	Evictor *api.TaskInfo   `json:"evictor"`
+	Victims []*api.TaskInfo `json:"victims"`
+}
+
+type VictimVetoResponse struct {
+	Vetoed []api.TaskID `json:"vetoed"`
+}
+
 type JobEnqueueableRequest struct {
 	Job *api.JobInfo `json:"job"`
 }
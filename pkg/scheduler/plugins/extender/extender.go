@@ -58,6 +58,13 @@ const (
 	ExtenderJobEnqueueableVerb = "extender.jobEnqueueableVerb"
 	// ExtenderJobReadyVerb is the verb of JobReady method
 	ExtenderJobReadyVerb = "extender.jobReadyVerb"
+	// ExtenderVictimVetoVerb is the verb of VictimVeto method
+	ExtenderVictimVetoVerb = "extender.victimVetoVerb"
+	// ExtenderVictimVetoTimeout is the timeout for VictimVeto calls, it follows extender.httpTimeout if not set
+	ExtenderVictimVetoTimeout = "extender.victimVetoTimeout"
+	// ExtenderVictimVetoFailOpen indicates whether victims are kept when VictimVeto call fails,
+	// it follows extender.ignorable if not set
+	ExtenderVictimVetoFailOpen = "extender.victimVetoFailOpen"
 	// ExtenderIgnorable indicates whether the extender can ignore unexpected errors
 	ExtenderIgnorable = "extender.ignorable"
 )
@@ -74,12 +81,16 @@ type extenderConfig struct {
 	queueOverusedVerb  string
 	jobEnqueueableVerb string
 	jobReadyVerb       string
+	victimVetoVerb     string
+	victimVetoTimeout  time.Duration
+	victimVetoFailOpen bool
 	ignorable          bool
 }
 
 type extenderPlugin struct {
-	client http.Client
-	config *extenderConfig
+	client           http.Client
+	victimVetoClient http.Client
+	config           *extenderConfig
 }
 
 func parseExtenderConfig(arguments framework.Arguments) *extenderConfig {
@@ -105,6 +116,9 @@ func parseExtenderConfig(arguments framework.Arguments) *extenderConfig {
 				   extender.reclaimableVerb: reclaimable
 				   extender.queueOverusedVerb: queueOverused
 				   extender.jobEnqueueableVerb: jobEnqueueable
+				   extender.victimVetoVerb: victimVeto
+				   extender.victimVetoTimeout: 50ms
+				   extender.victimVetoFailOpen: true
 				   extender.ignorable: true
 		     - name: proportion
 		     - name: nodeorder
@@ -120,8 +134,11 @@ func parseExtenderConfig(arguments framework.Arguments) *extenderConfig {
 	ec.queueOverusedVerb, _ = arguments[ExtenderQueueOverusedVerb].(string)
 	ec.jobEnqueueableVerb, _ = arguments[ExtenderJobEnqueueableVerb].(string)
 	ec.jobReadyVerb, _ = arguments[ExtenderJobReadyVerb].(string)
+	ec.victimVetoVerb, _ = arguments[ExtenderVictimVetoVerb].(string)
 
 	arguments.GetBool(&ec.ignorable, ExtenderIgnorable)
+	ec.victimVetoFailOpen = ec.ignorable
+	arguments.GetBool(&ec.victimVetoFailOpen, ExtenderVictimVetoFailOpen)
 
 	ec.httpTimeout = time.Second
 	if httpTimeout, _ := arguments[ExtenderHTTPTimeout].(string); httpTimeout != "" {
@@ -129,6 +146,12 @@ func parseExtenderConfig(arguments framework.Arguments) *extenderConfig {
 			ec.httpTimeout = timeoutDuration
 		}
 	}
+	ec.victimVetoTimeout = ec.httpTimeout
+	if victimVetoTimeout, _ := arguments[ExtenderVictimVetoTimeout].(string); victimVetoTimeout != "" {
+		if timeoutDuration, err := time.ParseDuration(victimVetoTimeout); err == nil {
+			ec.victimVetoTimeout = timeoutDuration
+		}
+	}
 
 	return ec
 }
@@ -136,7 +159,11 @@ func parseExtenderConfig(arguments framework.Arguments) *extenderConfig {
 func New(arguments framework.Arguments) framework.Plugin {
 	cfg := parseExtenderConfig(arguments)
 	klog.V(4).Infof("Initialize extender plugin with endpoint address %s", cfg.urlPrefix)
-	return &extenderPlugin{client: http.Client{Timeout: cfg.httpTimeout}, config: cfg}
+	return &extenderPlugin{
+		client:           http.Client{Timeout: cfg.httpTimeout},
+		victimVetoClient: http.Client{Timeout: cfg.victimVetoTimeout},
+		config:           cfg,
+	}
 }
 
 func (ep *extenderPlugin) Name() string {
@@ -232,6 +259,33 @@ func (ep *extenderPlugin) OnSessionOpen(ssn *framework.Session) {
 		})
 	}
 
+	if ep.config.victimVetoVerb != "" {
+		ssn.AddVictimVetoFn(ep.Name(), func(evictor *api.TaskInfo, victims []*api.TaskInfo) []*api.TaskInfo {
+			resp := &VictimVetoResponse{}
+			err := ep.sendWith(&ep.victimVetoClient, ep.config.victimVetoVerb, &VictimVetoRequest{Evictor: evictor, Victims: victims}, resp)
+			if err != nil {
+				klog.Warningf("VictimVeto failed with error %v", err)
+
+				if ep.config.victimVetoFailOpen {
+					return nil
+				}
+				return victims
+			}
+
+			vetoed := map[api.TaskID]bool{}
+			for _, uid := range resp.Vetoed {
+				vetoed[uid] = true
+			}
+			var vetoedVictims []*api.TaskInfo
+			for _, victim := range victims {
+				if vetoed[victim.UID] {
+					vetoedVictims = append(vetoedVictims, victim)
+				}
+			}
+			return vetoedVictims
+		})
+	}
+
 	if ep.config.jobEnqueueableVerb != "" {
 		ssn.AddJobEnqueueableFn(ep.Name(), func(obj interface{}) int {
 			job := obj.(*api.JobInfo)
@@ -290,6 +344,10 @@ func (ep *extenderPlugin) OnSessionClose(ssn *framework.Session) {
 }
 
 func (ep *extenderPlugin) send(action string, args interface{}, result interface{}) error {
+	return ep.sendWith(&ep.client, action, args, result)
+}
+
+func (ep *extenderPlugin) sendWith(client *http.Client, action string, args interface{}, result interface{}) error {
 	out, err := json.Marshal(args)
 	if err != nil {
 		return err
@@ -304,7 +362,7 @@ func (ep *extenderPlugin) send(action string, args interface{}, result interface
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := ep.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -18,9 +18,12 @@ package plugins
 
 import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
+	advancereservation "volcano.sh/volcano/pkg/scheduler/plugins/advance-reservation"
 	"volcano.sh/volcano/pkg/scheduler/plugins/binpack"
 	"volcano.sh/volcano/pkg/scheduler/plugins/cdp"
 	"volcano.sh/volcano/pkg/scheduler/plugins/conformance"
+	"volcano.sh/volcano/pkg/scheduler/plugins/cost"
+	"volcano.sh/volcano/pkg/scheduler/plugins/datalocality"
 	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
 	"volcano.sh/volcano/pkg/scheduler/plugins/extender"
 	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
@@ -31,6 +34,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/plugins/priority"
 	"volcano.sh/volcano/pkg/scheduler/plugins/proportion"
 	"volcano.sh/volcano/pkg/scheduler/plugins/rescheduling"
+	resourcestrategyfit "volcano.sh/volcano/pkg/scheduler/plugins/resource-strategy-fit"
 	"volcano.sh/volcano/pkg/scheduler/plugins/resourcequota"
 	"volcano.sh/volcano/pkg/scheduler/plugins/sla"
 	tasktopology "volcano.sh/volcano/pkg/scheduler/plugins/task-topology"
@@ -55,6 +59,11 @@ func init() {
 	framework.RegisterPluginBuilder(cdp.PluginName, cdp.New)
 	framework.RegisterPluginBuilder(rescheduling.PluginName, rescheduling.New)
 	framework.RegisterPluginBuilder(usage.PluginName, usage.New)
+	framework.RegisterArgumentsVersions(usage.PluginName, usage.ArgumentsVersions...)
+	framework.RegisterPluginBuilder(datalocality.PluginName, datalocality.New)
+	framework.RegisterPluginBuilder(cost.PluginName, cost.New)
+	framework.RegisterPluginBuilder(resourcestrategyfit.PluginName, resourcestrategyfit.New)
+	framework.RegisterPluginBuilder(advancereservation.PluginName, advancereservation.New)
 
 	// Plugins for Queues
 	framework.RegisterPluginBuilder(proportion.PluginName, proportion.New)
@@ -64,4 +73,10 @@ func init() {
 
 	// Plugins for ResourceQuota
 	framework.RegisterPluginBuilder(resourcequota.PluginName, resourcequota.New)
+
+	// State kept across sessions by plugins
+	framework.RegisterStateSnapshot(tdm.PluginName, tdm.SnapshotState)
+	framework.RegisterStateSnapshot(rescheduling.PluginName, rescheduling.SnapshotState)
+	framework.RegisterStateSnapshot(usage.PluginName, usage.SnapshotState)
+	framework.RegisterStateSnapshot(proportion.PluginName, proportion.SnapshotState)
 }
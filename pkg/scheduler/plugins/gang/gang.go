@@ -18,6 +18,9 @@ package gang
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +37,10 @@ import (
 // PluginName indicates name of volcano scheduler plugin.
 const PluginName = "gang"
 
+// GangSchedulingTimeoutReason is the reason of event when a job is sent back to pending after its gang
+// scheduling times out.
+const GangSchedulingTimeoutReason = "GangSchedulingTimeout"
+
 type gangPlugin struct {
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
@@ -48,7 +55,25 @@ func (gp *gangPlugin) Name() string {
 	return PluginName
 }
 
+// hasFallback checks whether job opts in to start with fewer tasks than its minMember once its gang scheduling
+// times out.
+func hasFallback(job *api.JobInfo) bool {
+	return job.GangFallbackMinMember > 0 && job.GangFallbackMinMember < job.PodGroup.Spec.MinMember
+}
+
 func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
+	// Jobs whose gang scheduling timed out start with their fallback minMember, every check of the gang below
+	// follows the lowered minAvailable of the job in the session.
+	now := time.Now()
+	for _, job := range ssn.Jobs {
+		if job.PodGroup == nil || !hasFallback(job) || !ssn.GangSchedulingTimedOut(job, now) {
+			continue
+		}
+		klog.V(3).Infof("Gang scheduling of job <%s/%s> timed out, fall back to minAvailable %d from %d",
+			job.Namespace, job.Name, job.GangFallbackMinMember, job.MinAvailable)
+		job.MinAvailable = job.GangFallbackMinMember
+	}
+
 	validJobFn := func(obj interface{}) *api.ValidateResult {
 		job, ok := obj.(*api.JobInfo)
 		if !ok {
@@ -83,20 +108,32 @@ func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
 	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) ([]*api.TaskInfo, int) {
 		var victims []*api.TaskInfo
 		jobOccupiedMap := map[api.JobID]int32{}
+		taskOccupiedMap := map[api.JobID]map[api.TaskID]int32{}
 
 		for _, preemptee := range preemptees {
 			job := ssn.Jobs[preemptee.Job]
 			if _, found := jobOccupiedMap[job.UID]; !found {
 				jobOccupiedMap[job.UID] = job.ReadyTaskNum()
+				taskOccupiedMap[job.UID] = job.ReadyTaskNumOfTasks()
 			}
 
-			if jobOccupiedMap[job.UID] > job.MinAvailable {
-				jobOccupiedMap[job.UID]--
-				victims = append(victims, preemptee)
-			} else {
+			if jobOccupiedMap[job.UID] <= job.MinAvailable {
 				klog.V(4).Infof("Can not preempt task <%v/%v> because job %s ready num(%d) <= MinAvailable(%d) for gang-scheduling",
 					preemptee.Namespace, preemptee.Name, job.Name, jobOccupiedMap[job.UID], job.MinAvailable)
+				continue
+			}
+
+			taskID := preemptee.GetTaskSpecKey()
+			if minAvailable, found := job.TaskMinAvailable[taskID]; found && job.CheckTaskMinAvailable() &&
+				taskOccupiedMap[job.UID][taskID] <= minAvailable {
+				klog.V(4).Infof("Can not preempt task <%v/%v> because task %s of job %s ready num(%d) <= MinAvailable(%d) for gang-scheduling",
+					preemptee.Namespace, preemptee.Name, taskID, job.Name, taskOccupiedMap[job.UID][taskID], minAvailable)
+				continue
 			}
+
+			jobOccupiedMap[job.UID]--
+			taskOccupiedMap[job.UID][taskID]--
+			victims = append(victims, preemptee)
 		}
 
 		klog.V(4).Infof("Victims from Gang plugins are %+v", victims)
@@ -155,8 +192,9 @@ func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
 	jobStarvingFn := func(obj interface{}) bool {
 		ji := obj.(*api.JobInfo)
 		occupied := ji.WaitingTaskNum() + ji.ReadyTaskNum()
-		// In the preemption scenario, the taskMinAvailble configuration is not concerned, only the jobMinAvailble is concerned
-		return occupied < ji.MinAvailable
+		// A job is starving if it has less tasks than its minAvailable, or any of its tasks has less than the minAvailable
+		// of the task, e.g. the workers are running but the ps is not.
+		return occupied < ji.MinAvailable || ji.CheckTaskStarving()
 	}
 	ssn.AddJobStarvingFns(gp.Name(), jobStarvingFn)
 }
@@ -164,23 +202,44 @@ func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
 func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 	var unreadyTaskCount int32
 	var unScheduleJobCount int
+	now := time.Now()
 	for _, job := range ssn.Jobs {
-		if !job.Ready() {
-			schedulableTaskNum := func() (num int32) {
-				for _, task := range job.TaskStatusIndex[api.Pending] {
-					ctx := task.GetTransactionContext()
-					if task.LastTransaction != nil {
-						ctx = *task.LastTransaction
-					}
-					if api.AllocatedStatus(ctx.Status) {
-						num++
+		if !job.Ready() || !job.CheckTaskReady() {
+			schedulableTasks := job.ReadyTaskNumOfTasks()
+			schedulableTaskNum := job.ReadyTaskNum()
+			for _, task := range job.TaskStatusIndex[api.Pending] {
+				ctx := task.GetTransactionContext()
+				if task.LastTransaction != nil {
+					ctx = *task.LastTransaction
+				}
+				if api.AllocatedStatus(ctx.Status) {
+					schedulableTasks[task.GetTaskSpecKey()]++
+					schedulableTaskNum++
+				}
+			}
+			unreadyTaskCount = job.MinAvailable - schedulableTaskNum
+
+			// The tasks short of their minAvailable, e.g. "ps 0/1", are reported with the fit error.
+			var unreadyTasks []string
+			if job.CheckTaskMinAvailable() {
+				var taskUnreadyCount int32
+				for taskID, minAvailable := range job.TaskMinAvailable {
+					if schedulableTasks[taskID] < minAvailable {
+						taskUnreadyCount += minAvailable - schedulableTasks[taskID]
+						unreadyTasks = append(unreadyTasks, fmt.Sprintf("%s %d/%d", taskID, schedulableTasks[taskID], minAvailable))
 					}
 				}
-				return num + job.ReadyTaskNum()
+				if taskUnreadyCount > unreadyTaskCount {
+					unreadyTaskCount = taskUnreadyCount
+				}
 			}
-			unreadyTaskCount = job.MinAvailable - schedulableTaskNum()
 			msg := fmt.Sprintf("%v/%v tasks in gang unschedulable: %v",
-				unreadyTaskCount, len(job.Tasks), job.FitError())
+				unreadyTaskCount, int32(len(job.Tasks))+job.CompactedTaskNum(), job.FitError())
+			if len(unreadyTasks) != 0 {
+				sort.Strings(unreadyTasks)
+				msg = fmt.Sprintf("%v/%v tasks in gang unschedulable, schedulable/min of tasks %s: %v",
+					unreadyTaskCount, int32(len(job.Tasks))+job.CompactedTaskNum(), strings.Join(unreadyTasks, ", "), job.FitError())
+			}
 			job.JobFitErrors = msg
 
 			unScheduleJobCount++
@@ -199,6 +258,18 @@ func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 				klog.Errorf("Failed to update job <%s/%s> condition: %v",
 					job.Namespace, job.Name, err)
 			}
+			updateResourcesSufficientCondition(ssn, job, false)
+
+			// A gang not placed in time is sent back to pending, which releases its enqueued resource to other jobs
+			// until it is enqueued again.
+			if job.PodGroup.Status.Phase == scheduling.PodGroupInqueue && !hasFallback(job) &&
+				ssn.GangSchedulingTimedOut(job, now) {
+				klog.V(3).Infof("Gang scheduling of job <%s/%s> timed out, send it back to pending",
+					job.Namespace, job.Name)
+				job.PodGroup.Status.Phase = scheduling.PodGroupPending
+				ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeWarning, GangSchedulingTimeoutReason,
+					fmt.Sprintf("Gang not placed within the gang scheduling timeout: %s", msg))
+			}
 
 			// allocated task should follow the job fit error
 			for _, taskInfo := range job.TaskStatusIndex[api.Allocated] {
@@ -212,6 +283,7 @@ func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 				fitError.SetError(msg)
 			}
 		} else {
+			updateResourcesSufficientCondition(ssn, job, true)
 			jc := &scheduling.PodGroupCondition{
 				Type:               scheduling.PodGroupScheduled,
 				Status:             v1.ConditionTrue,
@@ -232,3 +304,27 @@ func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 
 	metrics.UpdateUnscheduleJobCount(unScheduleJobCount)
 }
+
+// updateResourcesSufficientCondition updates the ResourcesSufficient condition of job, which
+// carries the missing resources of job if they are insufficient.
+func updateResourcesSufficientCondition(ssn *framework.Session, job *api.JobInfo, ready bool) {
+	jc := &scheduling.PodGroupCondition{
+		Type:               api.PodGroupResourcesSufficientType,
+		Status:             v1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		TransitionID:       string(ssn.UID),
+	}
+	if !ready {
+		missing := job.GetMissingResources()
+		if !missing.IsEmpty() {
+			jc.Status = v1.ConditionFalse
+			jc.Reason = api.ScaleNeededReason
+			jc.Message = missing.String()
+		}
+	}
+
+	if err := ssn.UpdatePodGroupCondition(job, jc); err != nil {
+		klog.Errorf("Failed to update job <%s/%s> condition: %v",
+			job.Namespace, job.Name, err)
+	}
+}
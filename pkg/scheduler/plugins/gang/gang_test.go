@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gang
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestGangSchedulingTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		expectBinds  map[string]string
+		bindsNum     int
+		expectPhase  schedulingv1.PodGroupPhase
+		expectEvents []string
+	}{
+		{
+			name:        "no timeout",
+			expectBinds: map[string]string{},
+			expectPhase: schedulingv1.PodGroupInqueue,
+		},
+		{
+			name:         "timed out without fallback",
+			annotations:  map[string]string{api.GangSchedulingTimeoutAnnotation: "10m"},
+			expectBinds:  map[string]string{},
+			expectPhase:  schedulingv1.PodGroupPending,
+			expectEvents: []string{GangSchedulingTimeoutReason},
+		},
+		{
+			name: "timed out with fallback",
+			annotations: map[string]string{
+				api.GangSchedulingTimeoutAnnotation: "10m",
+				api.GangFallbackMinMemberAnnotation: "1",
+			},
+			bindsNum:    1,
+			expectPhase: schedulingv1.PodGroupRunning,
+		},
+		{
+			name: "fallback not lower than min member",
+			annotations: map[string]string{
+				api.GangSchedulingTimeoutAnnotation: "10m",
+				api.GangFallbackMinMemberAnnotation: "2",
+			},
+			expectBinds: map[string]string{},
+			expectPhase: schedulingv1.PodGroupPending,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pg := util.BuildPodGroup("ns", "pg1", "q1", 2, schedulingv1.PodGroupInqueue)
+			pg.Annotations = test.annotations
+			// The podgroup was enqueued an hour ago.
+			pg.Status.Conditions = []schedulingv1.PodGroupCondition{{
+				Type:               schedulingv1.PodGroupConditionType(api.PodGroupInqueueType),
+				Status:             v1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}}
+			c := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				PodGroups: []*schedulingv1.PodGroup{pg},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", nil, nil),
+					util.BuildPod("ns", "p2", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", nil, nil),
+				},
+				Nodes:          []*v1.Node{util.BuildNode("n1", util.BuildResourceList("1", "2G"), nil)},
+				Queues:         []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+				ExpectBindMap:  test.expectBinds,
+				ExpectBindsNum: test.bindsNum,
+				ExpectPhases:   map[string]schedulingv1.PodGroupPhase{"ns/pg1": test.expectPhase},
+				ExpectEvents:   test.expectEvents,
+			}
+			defer c.Close()
+
+			c.Run(allocate.New())
+			if err := c.CheckAll(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package nodeorder
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	utilFeature "k8s.io/apiserver/pkg/util/feature"
@@ -39,6 +41,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/plugins/util/k8s"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
 const (
@@ -63,6 +66,10 @@ const (
 	PodTopologySpreadWeight = "podtopologyspread.weight"
 	// SelectorSpreadWeight is the key for providing Selector Spread Priority Weight in YAML
 	selectorSpreadWeight = "selectorspread.weight"
+	// NodeStabilityWeight is the key for providing Node Stability Priority Weight in YAML
+	NodeStabilityWeight = "nodestability.weight"
+	// PreviousNodeWeight is the key for providing Previous Node Priority Weight in YAML
+	PreviousNodeWeight = "previousnode.weight"
 )
 
 type nodeOrderPlugin struct {
@@ -89,6 +96,8 @@ type priorityWeight struct {
 	imageLocalityWeight     int
 	podTopologySpreadWeight int
 	selectorSpreadWeight    int
+	nodeStabilityWeight     int
+	previousNodeWeight      int
 }
 
 // calculateWeight from the provided arguments.
@@ -96,6 +105,15 @@ type priorityWeight struct {
 // Currently only supported priorities are nodeaffinity, podaffinity, leastrequested,
 // mostrequested, balancedresouce, imagelocality, tainttoleration.
 //
+// The nodestability weight multiplies the score of the Ready flaps and restarts of a node in
+// the last hour, it only applies to the tasks of node stability sensitive jobs and is 0 by default.
+//
+// The previousnode weight multiplies the score of the node a task ran on before it was
+// restarted by the RestartTask policy, see api.PreviousNodeAnnotation.
+//
+// The nodeaffinity weight multiplies the score of the preferred node affinity terms of
+// a task, which is normalized to [0, 100] over all nodes in the batch node order.
+//
 // User should specify priority weights in the config in this format:
 //
 //	actions: "reclaim, allocate, backfill, preempt"
@@ -118,6 +136,8 @@ type priorityWeight struct {
 //	      tainttoleration.weight: 3
 //	      imagelocality.weight: 1
 //	      podtopologyspread.weight: 2
+//	      nodestability.weight: 1
+//	      previousnode.weight: 1
 func calculateWeight(args framework.Arguments) priorityWeight {
 	// Initial values for weights.
 	// By default, for backward compatibility and for reasonable scores,
@@ -132,6 +152,8 @@ func calculateWeight(args framework.Arguments) priorityWeight {
 		imageLocalityWeight:     1,
 		podTopologySpreadWeight: 2, // be consistent with kubernetes default setting.
 		selectorSpreadWeight:    0,
+		nodeStabilityWeight:     0,
+		previousNodeWeight:      1,
 	}
 
 	// Checks whether nodeaffinity.weight is provided or not, if given, modifies the value in weight struct.
@@ -161,12 +183,19 @@ func calculateWeight(args framework.Arguments) priorityWeight {
 	// Checks whether selectorspread.weight is provided or not, if given, modifies the value in weight struct.
 	args.GetInt(&weight.selectorSpreadWeight, selectorSpreadWeight)
 
+	// Checks whether nodestability.weight is provided or not, if given, modifies the value in weight struct.
+	args.GetInt(&weight.nodeStabilityWeight, NodeStabilityWeight)
+
+	// Checks whether previousnode.weight is provided or not, if given, modifies the value in weight struct.
+	args.GetInt(&weight.previousNodeWeight, PreviousNodeWeight)
+
 	return weight
 }
 
 func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 	weight := calculateWeight(pp.pluginArguments)
 	nodeMap := ssn.NodeMap
+	now := time.Now()
 
 	fts := feature.Features{
 		EnableReadWriteOncePod:                       utilFeature.DefaultFeatureGate.Enabled(features.ReadWriteOncePod),
@@ -276,18 +305,22 @@ func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 			klog.V(5).Infof("Node: %s, task<%s/%s> Balanced Request weight %d, score: %f", node.Name, task.Namespace, task.Name, weight.balancedResourceWeight, float64(score)*float64(weight.balancedResourceWeight))
 		}
 
-		// NodeAffinity
-		if weight.nodeAffinityWeight != 0 {
-			score, status := nodeAffinity.Score(context.TODO(), state, task.Pod, node.Name)
-			if !status.IsSuccess() {
-				klog.Warningf("Node: %s, Calculate Node Affinity Priority Failed because of Error: %v", node.Name, status.AsError())
-				return 0, status.AsError()
-			}
+		// NodeStability
+		if job, found := ssn.Jobs[task.Job]; found && job.NodeStabilitySensitive && weight.nodeStabilityWeight != 0 {
+			score := nodeStabilityScore(node, now)
+
+			// If nodeStabilityWeight is provided, score is multiplied with weight, if not, score is added to total score.
+			nodeScore += score * float64(weight.nodeStabilityWeight)
+			klog.V(5).Infof("Node: %s, task<%s/%s> Node Stability weight %d, score: %f", node.Name, task.Namespace, task.Name, weight.nodeStabilityWeight, score*float64(weight.nodeStabilityWeight))
+		}
 
-			// TODO: should we normalize the score
-			// If nodeAffinityWeight is provided, host.Score is multiplied with weight, if not, host.Score is added to total score.
-			nodeScore += float64(score) * float64(weight.nodeAffinityWeight)
-			klog.V(5).Infof("Node: %s, task<%s/%s> Node Affinity weight %d, score: %f", node.Name, task.Namespace, task.Name, weight.nodeAffinityWeight, float64(score)*float64(weight.nodeAffinityWeight))
+		// PreviousNode
+		if weight.previousNodeWeight != 0 {
+			score := previousNodeScore(task, node)
+
+			// If previousNodeWeight is provided, score is multiplied with weight, if not, score is added to total score.
+			nodeScore += score * float64(weight.previousNodeWeight)
+			klog.V(5).Infof("Node: %s, task<%s/%s> Previous Node weight %d, score: %f", node.Name, task.Namespace, task.Name, weight.previousNodeWeight, score*float64(weight.previousNodeWeight))
 		}
 
 		klog.V(4).Infof("Nodeorder Total Score for task<%s/%s> on node %s is: %f", task.Namespace, task.Name, node.Name, nodeScore)
@@ -320,6 +353,11 @@ func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 		}
 		nodeScores := make(map[string]float64, len(nodes))
 
+		nodeAffinityScores, err := nodeAffinityScore(nodeAffinity, state, task.Pod, nodes, weight.nodeAffinityWeight)
+		if err != nil {
+			return nil, err
+		}
+
 		podAffinityScores, podErr := interPodAffinityScore(interPodAffinity, state, task.Pod, nodes, weight.podAffinityWeight)
 		if podErr != nil {
 			return nil, podErr
@@ -341,7 +379,7 @@ func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 		}
 
 		for _, node := range nodes {
-			nodeScores[node.Name] = podAffinityScores[node.Name] + nodeTolerationScores[node.Name] + podTopologySpreadScores[node.Name] + selectorSpreadScores[node.Name]
+			nodeScores[node.Name] = nodeAffinityScores[node.Name] + podAffinityScores[node.Name] + nodeTolerationScores[node.Name] + podTopologySpreadScores[node.Name] + selectorSpreadScores[node.Name]
 		}
 
 		klog.V(4).Infof("Batch Total Score for task %s/%s is: %v", task.Namespace, task.Name, nodeScores)
@@ -350,6 +388,68 @@ func (pp *nodeOrderPlugin) OnSessionOpen(ssn *framework.Session) {
 	ssn.AddBatchNodeOrderFn(pp.Name(), batchNodeOrderFn)
 }
 
+// nodeStabilityScore scores the node by its Ready flaps and restarts in the last stability window,
+// a node without such events gets the max score, and the score halves with each event.
+func nodeStabilityScore(node *api.NodeInfo, now time.Time) float64 {
+	events := node.Stability.Events(now.Add(-api.NodeStabilityWindow))
+	return float64(k8sframework.MaxNodeScore) / math.Pow(2, float64(events))
+}
+
+// previousNodeScore gives the max score to the node the task ran on before its restart, where the local data and
+// images of the task are likely kept, the node only gets it if the task still fits the node.
+func previousNodeScore(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	if task.Pod == nil || task.Pod.Annotations[api.PreviousNodeAnnotation] != node.Name {
+		return 0
+	}
+	return float64(k8sframework.MaxNodeScore)
+}
+
+// nodeAffinityScore scores the nodes by the preferred node affinity terms of pod. The
+// scores are normalized over the nodes before multiplied with nodeAffinityWeight, so
+// the weights of the terms only rank the nodes and do not dwarf the other priorities.
+func nodeAffinityScore(
+	nodeAffinity *nodeaffinity.NodeAffinity,
+	cycleState *k8sframework.CycleState,
+	pod *v1.Pod,
+	nodes []*v1.Node,
+	nodeAffinityWeight int,
+) (map[string]float64, error) {
+	nodeScores := make(map[string]float64, len(nodes))
+	if nodeAffinityWeight == 0 {
+		return nodeScores, nil
+	}
+
+	preScoreStatus := nodeAffinity.PreScore(context.TODO(), cycleState, pod, nodes)
+	if !preScoreStatus.IsSuccess() {
+		return nil, preScoreStatus.AsError()
+	}
+
+	nodeScoreList := make(k8sframework.NodeScoreList, len(nodes))
+	for index, node := range nodes {
+		s, status := nodeAffinity.Score(context.TODO(), cycleState, pod, node.Name)
+		if !status.IsSuccess() {
+			return nil, fmt.Errorf("calculate node affinity priority failed %v", status.Message())
+		}
+		nodeScoreList[index] = k8sframework.NodeScore{
+			Name:  node.Name,
+			Score: s,
+		}
+	}
+
+	nodeAffinity.NormalizeScore(context.TODO(), cycleState, pod, nodeScoreList)
+
+	for _, nodeScore := range nodeScoreList {
+		// return error if score plugin returns invalid score.
+		if nodeScore.Score > k8sframework.MaxNodeScore || nodeScore.Score < k8sframework.MinNodeScore {
+			return nil, fmt.Errorf("node affinity returns an invalid score %v for node %s", nodeScore.Score, nodeScore.Name)
+		}
+		nodeScores[nodeScore.Name] = float64(nodeScore.Score * int64(nodeAffinityWeight))
+	}
+
+	klog.V(4).Infof("node affinity Score for task %s/%s is: %v", pod.Namespace, pod.Name, nodeScores)
+	return nodeScores, nil
+}
+
 func interPodAffinityScore(
 	interPodAffinity *interpodaffinity.InterPodAffinity,
 	state *k8sframework.CycleState,
@@ -363,7 +463,7 @@ func interPodAffinityScore(
 	}
 
 	nodeScoreList := make(k8sframework.NodeScoreList, len(nodes))
-	// the default parallelization worker number is 16.
+	// the parallelization worker number is the parallelism of scheduler, 16 by default.
 	// the whole scoring will fail if one of the processes failed.
 	// so just create a parallelizeContext to control the whole ParallelizeUntil process.
 	// if the parallelizeCancel is invoked, the whole "ParallelizeUntil" goes to the end.
@@ -371,7 +471,7 @@ func interPodAffinityScore(
 	// and the ParallelizeUntil guarantees only "workerNum" goroutines will be working simultaneously.
 	// so it's enough to allocate workerNum size for errCh.
 	// note that, in such case, size of errCh should be no less than parallelization number
-	workerNum := 16
+	workerNum := util.GetParallelism()
 	errCh := make(chan error, workerNum)
 	parallelizeContext, parallelizeCancel := context.WithCancel(context.TODO())
 	defer parallelizeCancel()
@@ -429,7 +529,7 @@ func taintTolerationScore(
 
 	nodeScoreList := make(k8sframework.NodeScoreList, len(nodes))
 	// size of errCh should be no less than parallelization number, see interPodAffinityScore.
-	workerNum := 16
+	workerNum := util.GetParallelism()
 	errCh := make(chan error, workerNum)
 	parallelizeContext, parallelizeCancel := context.WithCancel(context.TODO())
 	defer parallelizeCancel()
@@ -487,7 +587,7 @@ func podTopologySpreadScore(
 
 	nodeScoreList := make(k8sframework.NodeScoreList, len(nodes))
 	// size of errCh should be no less than parallelization number, see interPodAffinityScore.
-	workerNum := 16
+	workerNum := util.GetParallelism()
 	errCh := make(chan error, workerNum)
 	parallelizeContext, parallelizeCancel := context.WithCancel(context.TODO())
 	workqueue.ParallelizeUntil(parallelizeContext, workerNum, len(nodes), func(index int) {
@@ -542,7 +642,7 @@ func selectorSpreadScore(
 	}
 	nodeScoreList := make(k8sframework.NodeScoreList, len(nodes))
 	// size of errCh should be no less than parallelization number, see interPodAffinityScore.
-	workerNum := 16
+	workerNum := util.GetParallelism()
 	errCh := make(chan error, workerNum)
 	parallelizeContext, parallelizeCancel := context.WithCancel(context.TODO())
 	workqueue.ParallelizeUntil(parallelizeContext, workerNum, len(nodes), func(index int) {
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeorder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	k8sframework "k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodeaffinity"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/plugins/util/k8s"
+)
+
+func TestNodeAffinityScore(t *testing.T) {
+	newNode := func(name, zone string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}}}
+	}
+	nodes := []*v1.Node{newNode("n1", "a"), newNode("n2", "b"), newNode("n3", "c")}
+	nodeMap := map[string]*k8sframework.NodeInfo{}
+	for _, node := range nodes {
+		nodeInfo := k8sframework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		nodeMap[node.Name] = nodeInfo
+	}
+
+	preferZone := func(zone string, weight int32) v1.PreferredSchedulingTerm {
+		return v1.PreferredSchedulingTerm{
+			Weight: weight,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{zone}},
+				},
+			},
+		}
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p1", Namespace: "default"},
+		Spec: v1.PodSpec{
+			Affinity: &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{
+						preferZone("a", 80), preferZone("b", 20),
+					},
+				},
+			},
+		},
+	}
+
+	handle := k8s.NewFrameworkHandle(nodeMap, nil, nil)
+	p, _ := nodeaffinity.New(&config.NodeAffinityArgs{AddedAffinity: &v1.NodeAffinity{}}, handle)
+	nodeAffinity := p.(*nodeaffinity.NodeAffinity)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		weight   int
+		expected map[string]float64
+	}{
+		{
+			name:     "preferred terms are normalized and weighted",
+			pod:      pod,
+			weight:   2,
+			expected: map[string]float64{"n1": 200, "n2": 50, "n3": 0},
+		},
+		{
+			name:     "disabled by zero weight",
+			pod:      pod,
+			weight:   0,
+			expected: map[string]float64{},
+		},
+		{
+			name:     "no preferred terms",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p2", Namespace: "default"}},
+			weight:   2,
+			expected: map[string]float64{"n1": 0, "n2": 0, "n3": 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scores, err := nodeAffinityScore(nodeAffinity, k8sframework.NewCycleState(), test.pod, nodes, test.weight)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(scores, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, scores)
+			}
+		})
+	}
+}
+
+func TestNodeStabilityScore(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent, old := now.Add(-time.Minute), now.Add(-2*api.NodeStabilityWindow)
+
+	tests := []struct {
+		name      string
+		stability *api.NodeStability
+		expected  float64
+	}{
+		{
+			name:      "stable node",
+			stability: &api.NodeStability{},
+			expected:  100,
+		},
+		{
+			name:      "events out of window",
+			stability: &api.NodeStability{ReadyFlaps: []time.Time{old}},
+			expected:  100,
+		},
+		{
+			name:      "flapping and restarted node",
+			stability: &api.NodeStability{ReadyFlaps: []time.Time{old, recent}, Restarts: []time.Time{recent}},
+			expected:  25,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if score := nodeStabilityScore(&api.NodeInfo{Stability: test.stability}, now); score != test.expected {
+				t.Errorf("expected score %v, got %v", test.expected, score)
+			}
+		})
+	}
+}
+
+func TestPreviousNodeScore(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{api.PreviousNodeAnnotation: "n1"}}}
+	task := api.NewTaskInfo(pod)
+
+	if score := previousNodeScore(task, &api.NodeInfo{Name: "n1"}); score != 100 {
+		t.Errorf("expected max score on previous node, got %v", score)
+	}
+	if score := previousNodeScore(task, &api.NodeInfo{Name: "n2"}); score != 0 {
+		t.Errorf("expected no score on other node, got %v", score)
+	}
+	if score := previousNodeScore(api.NewTaskInfo(&v1.Pod{}), &api.NodeInfo{Name: "n1"}); score != 0 {
+		t.Errorf("expected no score for task never restarted, got %v", score)
+	}
+}
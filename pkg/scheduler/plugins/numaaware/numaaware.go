@@ -35,6 +35,7 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/plugins/numaaware/policy"
 	"volcano.sh/volcano/pkg/scheduler/plugins/numaaware/provider/cpumanager"
 	"volcano.sh/volcano/pkg/scheduler/plugins/util"
+	schedulerutil "volcano.sh/volcano/pkg/scheduler/util"
 )
 
 const (
@@ -236,7 +237,7 @@ func filterNodeByPolicy(task *api.TaskInfo, node *api.NodeInfo, nodeResSets map[
 
 func getNodeNumaNumForTask(nodeInfo []*api.NodeInfo, resAssignMap map[string]api.ResNumaSets) []api.ScoredNode {
 	nodeNumaCnts := make([]api.ScoredNode, len(nodeInfo))
-	workqueue.ParallelizeUntil(context.TODO(), 16, len(nodeInfo), func(index int) {
+	workqueue.ParallelizeUntil(context.TODO(), schedulerutil.GetParallelism(), len(nodeInfo), func(index int) {
 		node := nodeInfo[index]
 		assignCpus := resAssignMap[node.Name][string(v1.ResourceCPU)]
 		nodeNumaCnts[index] = api.ScoredNode{
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// checkEphemeralStorage checks that the ephemeral storage requested by task, including the size limit
+// of its emptyDir volumes, fits the future idle ephemeral storage of node, so disk-heavy tasks are not
+// packed onto nodes which will hit disk pressure eviction. Nodes not reporting ephemeral storage are skipped.
+func checkEphemeralStorage(task *api.TaskInfo, node *api.NodeInfo) *api.Status {
+	request := task.InitResreq.Get(v1.ResourceEphemeralStorage)
+	if request <= 0 || node.Allocatable == nil || node.Allocatable.Get(v1.ResourceEphemeralStorage) <= 0 {
+		return nil
+	}
+
+	futureIdle := node.Idle.Get(v1.ResourceEphemeralStorage) + node.Releasing.Get(v1.ResourceEphemeralStorage) -
+		node.Pipelined.Get(v1.ResourceEphemeralStorage)
+	if request <= futureIdle {
+		return nil
+	}
+	return &api.Status{
+		Code: api.Unschedulable,
+		Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+			task.Namespace, task.Name, node.Name, api.NodeEphemeralStorageInsufficient),
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckEphemeralStorage(t *testing.T) {
+	newResource := func(storage float64) *api.Resource {
+		return &api.Resource{ScalarResources: map[v1.ResourceName]float64{v1.ResourceEphemeralStorage: storage}}
+	}
+	newNode := func(allocatable, idle, releasing, pipelined float64) *api.NodeInfo {
+		return &api.NodeInfo{
+			Name:        "n1",
+			Allocatable: newResource(allocatable),
+			Idle:        newResource(idle),
+			Releasing:   newResource(releasing),
+			Pipelined:   newResource(pipelined),
+		}
+	}
+	newTask := func(storage float64) *api.TaskInfo {
+		return &api.TaskInfo{Namespace: "ns", Name: "t1", InitResreq: newResource(storage)}
+	}
+
+	tests := []struct {
+		name     string
+		task     *api.TaskInfo
+		node     *api.NodeInfo
+		expected bool
+	}{
+		{
+			name:     "no request",
+			task:     newTask(0),
+			node:     newNode(100, 0, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "node not reporting ephemeral storage",
+			task:     newTask(50),
+			node:     newNode(0, 0, 0, 0),
+			expected: true,
+		},
+		{
+			name:     "fits releasing storage",
+			task:     newTask(50),
+			node:     newNode(100, 20, 40, 0),
+			expected: true,
+		},
+		{
+			name: "pipelined storage is taken",
+			task: newTask(50),
+			node: newNode(100, 20, 40, 20),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := checkEphemeralStorage(test.task, test.node)
+			if passed := status == nil; passed != test.expected {
+				t.Errorf("expected passed %v, got status %v", test.expected, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// isExclusiveTask checks whether task requests whole node by itself or by its job. The tasks on nodes
+// are marked exclusive by the cache along with their jobs, which may not be scheduled in the session.
+func isExclusiveTask(jobs map[api.JobID]*api.JobInfo, task *api.TaskInfo) bool {
+	if task.ExclusiveNode {
+		return true
+	}
+	job, found := jobs[task.Job]
+	return found && job.ExclusiveNode
+}
+
+// isDaemonSetTask checks whether task is a pod of DaemonSet, which runs on every node and
+// does not break the exclusivity of node.
+func isDaemonSetTask(task *api.TaskInfo) bool {
+	if task.Pod == nil {
+		return false
+	}
+	controllerRef := metav1.GetControllerOf(task.Pod)
+	return controllerRef != nil && controllerRef.Kind == "DaemonSet"
+}
+
+// checkExclusiveNode checks that exclusive task is only placed on node without tasks of other jobs,
+// and no task is placed on node held by exclusive task of other job.
+func checkExclusiveNode(jobs map[api.JobID]*api.JobInfo, task *api.TaskInfo, node *api.NodeInfo) *api.Status {
+	exclusive := isExclusiveTask(jobs, task)
+	for _, other := range node.Tasks {
+		if other.Job == task.Job && len(task.Job) != 0 || isDaemonSetTask(other) {
+			continue
+		}
+		if exclusive {
+			return &api.Status{
+				Code: api.Unschedulable,
+				Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+					task.Namespace, task.Name, node.Name, api.NodeNotEmptyForExclusiveTask),
+			}
+		}
+		if other.ExclusiveNode {
+			return &api.Status{
+				Code: api.Unschedulable,
+				Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+					task.Namespace, task.Name, node.Name, api.NodeHeldExclusively),
+			}
+		}
+	}
+	return nil
+}
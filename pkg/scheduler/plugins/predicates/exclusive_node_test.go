@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckExclusiveNode(t *testing.T) {
+	trueValue := true
+	newTask := func(name string, job api.JobID, exclusive bool) *api.TaskInfo {
+		return &api.TaskInfo{
+			Namespace:     "ns",
+			Name:          name,
+			Job:           job,
+			ExclusiveNode: exclusive,
+			Pod:           &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}},
+		}
+	}
+	newNode := func(tasks ...*api.TaskInfo) *api.NodeInfo {
+		node := &api.NodeInfo{Name: "n1", Tasks: map[api.TaskID]*api.TaskInfo{}}
+		for _, task := range tasks {
+			node.Tasks[api.TaskID(task.Name)] = task
+		}
+		return node
+	}
+	daemonSetTask := newTask("ds", "", false)
+	daemonSetTask.Pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &trueValue}}
+
+	jobs := map[api.JobID]*api.JobInfo{
+		"ns/exclusive": {UID: "ns/exclusive", ExclusiveNode: true},
+		"ns/shared":    {UID: "ns/shared"},
+	}
+
+	tests := []struct {
+		name     string
+		task     *api.TaskInfo
+		node     *api.NodeInfo
+		expected string
+	}{
+		{
+			name: "shared task on shared node",
+			task: newTask("t1", "ns/shared", false),
+			node: newNode(newTask("t2", "ns/other", false)),
+		},
+		{
+			name: "exclusive job task on empty node",
+			task: newTask("t1", "ns/exclusive", false),
+			node: newNode(daemonSetTask),
+		},
+		{
+			name: "exclusive job task on node of same job",
+			task: newTask("t1", "ns/exclusive", false),
+			node: newNode(newTask("t2", "ns/exclusive", false)),
+		},
+		{
+			name:     "exclusive task on node of other job",
+			task:     newTask("t1", "ns/shared", true),
+			node:     newNode(newTask("t2", "ns/other", false)),
+			expected: api.NodeNotEmptyForExclusiveTask,
+		},
+		{
+			name:     "shared task on node held by exclusive job not in session",
+			task:     newTask("t1", "ns/shared", false),
+			node:     newNode(newTask("t2", "ns/other-profile", true)),
+			expected: api.NodeHeldExclusively,
+		},
+		{
+			name:     "shared task on node held by exclusive task",
+			task:     newTask("t1", "ns/shared", false),
+			node:     newNode(newTask("t2", "ns/other", true)),
+			expected: api.NodeHeldExclusively,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := checkExclusiveNode(jobs, test.task, test.node)
+			if len(test.expected) == 0 {
+				if status != nil {
+					t.Errorf("expected no status, got %v", status.Reason)
+				}
+				return
+			}
+			if status == nil || status.Code != api.Unschedulable {
+				t.Fatalf("expected unschedulable status %s, got %v", test.expected, status)
+			}
+			if want := "Task <ns/t1> on Node <n1> failed, reason: " + test.expected; status.Reason != want {
+				t.Errorf("expected reason %s, got %s", want, status.Reason)
+			}
+		})
+	}
+}
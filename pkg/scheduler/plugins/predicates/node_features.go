@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// checkNodeFeatures checks that node has the architecture and the node features required by the job of task,
+// the reason tells whether the architecture or the node features are not matched.
+func checkNodeFeatures(task *api.TaskInfo, node *api.NodeInfo) *api.Status {
+	if len(task.NodeFeatures) == 0 || node.Node == nil {
+		return nil
+	}
+	feature, unmatched := api.UnmatchedNodeFeature(node.Node, task.NodeFeatures)
+	if !unmatched {
+		return nil
+	}
+
+	reason := api.NodeFeaturesMismatch
+	if feature.Arch {
+		reason = api.NodeArchMismatch
+	}
+	klog.V(4).Infof("Node <%s> does not match node feature %q of task <%s/%s>",
+		node.Name, feature.Item, task.Namespace, task.Name)
+	return &api.Status{
+		Code: api.UnschedulableAndUnresolvable,
+		Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+			task.Namespace, task.Name, node.Name, reason),
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckNodeFeatures(t *testing.T) {
+	task := api.NewTaskInfo(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "p1", Annotations: map[string]string{
+		api.NodeArchAnnotation:     "arm64",
+		api.NodeFeaturesAnnotation: "cpu-cpuid.AVX512F",
+	}}})
+	newNode := func(labels map[string]string) *api.NodeInfo {
+		return api.NewNodeInfo(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: labels}})
+	}
+
+	tests := []struct {
+		name   string
+		node   *api.NodeInfo
+		reason string
+	}{
+		{
+			name: "matched",
+			node: newNode(map[string]string{v1.LabelArchStable: "arm64", "feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true"}),
+		},
+		{
+			name:   "arch mismatch",
+			node:   newNode(map[string]string{v1.LabelArchStable: "amd64", "feature.node.kubernetes.io/cpu-cpuid.AVX512F": "true"}),
+			reason: api.NodeArchMismatch,
+		},
+		{
+			name:   "feature missing",
+			node:   newNode(map[string]string{v1.LabelArchStable: "arm64"}),
+			reason: api.NodeFeaturesMismatch,
+		},
+	}
+	for _, test := range tests {
+		status := checkNodeFeatures(task, test.node)
+		if (status != nil) != (test.reason != "") || (status != nil && !strings.HasSuffix(status.Reason, test.reason)) {
+			t.Errorf("%s: expected reason %q, got %v", test.name, test.reason, status)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// maxVolcanoPodsOf returns the cap of pods scheduled by volcano on node, and whether node is capped.
+// The volcano.sh/max-volcano-pods annotation of node overrides the plugin arguments,
+// otherwise the lower of maxPods and maxPercentage of the allocatable pods of node is used.
+func maxVolcanoPodsOf(node *api.NodeInfo, maxPods int, maxPercentage float64) (int, bool) {
+	if node.Node != nil {
+		if value, found := node.Node.Annotations[api.MaxVolcanoPodsAnnotation]; found {
+			limit, err := strconv.Atoi(value)
+			if err == nil && limit >= 0 {
+				return limit, true
+			}
+			klog.Warningf("invalid %s=%s of node %s", api.MaxVolcanoPodsAnnotation, value, node.Name)
+		}
+	}
+
+	limit, capped := maxPods, maxPods > 0
+	if maxPercentage > 0 && node.Allocatable != nil {
+		byPercentage := int(float64(node.Allocatable.MaxTaskNum) * maxPercentage / 100)
+		if !capped || byPercentage < limit {
+			limit, capped = byPercentage, true
+		}
+	}
+	return limit, capped
+}
+
+// checkVolcanoPodDensity checks that the number of pods scheduled by volcano on node does not
+// exceed its cap, so very dense tiny batch pods do not overload kubelet.
+func checkVolcanoPodDensity(task *api.TaskInfo, node *api.NodeInfo, maxPods int, maxPercentage float64) *api.Status {
+	limit, capped := maxVolcanoPodsOf(node, maxPods, maxPercentage)
+	if !capped {
+		return nil
+	}
+
+	count := 0
+	for _, other := range node.Tasks {
+		if len(other.Job) != 0 {
+			count++
+		}
+	}
+	if count < limit {
+		return nil
+	}
+
+	return &api.Status{
+		Code: api.Unschedulable,
+		Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+			task.Namespace, task.Name, node.Name, api.NodeVolcanoPodNumberExceeded),
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckVolcanoPodDensity(t *testing.T) {
+	newNode := func(annotations map[string]string, volcanoPods, otherPods int) *api.NodeInfo {
+		node := &api.NodeInfo{
+			Name:        "n1",
+			Node:        &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Annotations: annotations}},
+			Allocatable: &api.Resource{MaxTaskNum: 10},
+			Tasks:       map[api.TaskID]*api.TaskInfo{},
+		}
+		for i := 0; i < volcanoPods; i++ {
+			node.Tasks[api.TaskID(fmt.Sprintf("volcano-%d", i))] = &api.TaskInfo{Job: "ns/job"}
+		}
+		for i := 0; i < otherPods; i++ {
+			node.Tasks[api.TaskID(fmt.Sprintf("other-%d", i))] = &api.TaskInfo{}
+		}
+		return node
+	}
+	task := &api.TaskInfo{Namespace: "ns", Name: "t1", Job: "ns/job"}
+
+	tests := []struct {
+		name          string
+		node          *api.NodeInfo
+		maxPods       int
+		maxPercentage float64
+		expected      bool
+	}{
+		{
+			name:     "no cap",
+			node:     newNode(nil, 9, 0),
+			expected: true,
+		},
+		{
+			name:     "below max pods, other pods not counted",
+			node:     newNode(nil, 2, 5),
+			maxPods:  3,
+			expected: true,
+		},
+		{
+			name:    "max pods reached",
+			node:    newNode(nil, 3, 0),
+			maxPods: 3,
+		},
+		{
+			name:          "percentage lower than max pods",
+			node:          newNode(nil, 5, 0),
+			maxPods:       8,
+			maxPercentage: 50,
+		},
+		{
+			name:     "node annotation overrides arguments",
+			node:     newNode(map[string]string{api.MaxVolcanoPodsAnnotation: "6"}, 5, 0),
+			maxPods:  3,
+			expected: true,
+		},
+		{
+			name: "node annotation caps without arguments",
+			node: newNode(map[string]string{api.MaxVolcanoPodsAnnotation: "0"}, 0, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := checkVolcanoPodDensity(task, test.node, test.maxPods, test.maxPercentage)
+			if passed := status == nil; passed != test.expected {
+				t.Errorf("expected passed %v, got status %v", test.expected, status)
+			}
+		})
+	}
+}
@@ -85,6 +85,11 @@ const (
 	ProportionalResource = "predicate.resources"
 	// ProportionalResourcesPrefix is the key prefix for additional resource key name
 	ProportionalResourcesPrefix = ProportionalResource + "."
+
+	// MaxVolcanoPods is the key for the max number of pods scheduled by volcano per node
+	MaxVolcanoPods = "predicate.MaxVolcanoPods"
+	// MaxVolcanoPodsPercentage is the key for the max percentage of pods scheduled by volcano in the allocatable pods of node
+	MaxVolcanoPodsPercentage = "predicate.MaxVolcanoPodsPercentage"
 )
 
 type predicatesPlugin struct {
@@ -117,6 +122,8 @@ type predicateEnable struct {
 	cacheEnable             bool
 	proportionalEnable      bool
 	proportional            map[v1.ResourceName]baseResource
+	maxVolcanoPods          int
+	maxVolcanoPodsPercent   float64
 }
 
 func enablePredicate(args framework.Arguments) predicateEnable {
@@ -148,6 +155,8 @@ func enablePredicate(args framework.Arguments) predicateEnable {
 	         predicate.resources: nvidia.com/gpu
 	         predicate.resources.nvidia.com/gpu.cpu: 4
 	         predicate.resources.nvidia.com/gpu.memory: 8
+	         predicate.MaxVolcanoPods: 50
+	         predicate.MaxVolcanoPodsPercentage: 80
 	     - name: proportion
 	     - name: nodeorder
 	*/
@@ -221,6 +230,9 @@ func enablePredicate(args framework.Arguments) predicateEnable {
 	}
 	predicate.proportional = resourcesProportional
 
+	args.GetInt(&predicate.maxVolcanoPods, MaxVolcanoPods)
+	args.GetFloat64(&predicate.maxVolcanoPodsPercent, MaxVolcanoPodsPercentage)
+
 	return predicate
 }
 
@@ -247,22 +259,30 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 				klog.Errorf("Failed to get node %s info from cache", nodeName)
 				return
 			}
-			//predicate gpu sharing
+			//predicate gpu sharing, the device annotations are written to the pod when it is bound
+			var deviceAnnotations map[string]string
 			for _, val := range api.RegisteredDevices {
 				if devices, ok := nodeInfo.Others[val].(api.Devices); ok {
 					if !devices.HasDeviceRequest(pod) {
 						continue
 					}
 
-					err := devices.Allocate(ssn.KubeClient(), pod)
+					annotations, err := devices.Allocate(pod)
 					if err != nil {
 						klog.Errorf("AllocateToPod failed %s", err.Error())
 						return
 					}
+					for k, v := range annotations {
+						if deviceAnnotations == nil {
+							deviceAnnotations = make(map[string]string)
+						}
+						deviceAnnotations[k] = v
+					}
 				} else {
 					klog.Warningf("Devices %s assertion conversion failed, skip", val)
 				}
 			}
+			event.Task.DeviceAnnotations = deviceAnnotations
 			node.AddPod(pod)
 			klog.V(4).Infof("predicates, update pod %s/%s allocate to node [%s]", pod.Namespace, pod.Name, nodeName)
 		},
@@ -289,7 +309,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 					}
 
 					// deallocate pod gpu id
-					err := devices.Release(ssn.KubeClient(), pod)
+					err := devices.Release(pod)
 					if err != nil {
 						klog.Errorf(err.Error())
 						return
@@ -298,6 +318,7 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 					klog.Warningf("Devices %s assertion conversion failed, skip", val)
 				}
 			}
+			event.Task.DeviceAnnotations = nil
 
 			err := node.RemovePod(pod)
 			if err != nil {
@@ -411,6 +432,52 @@ func (pp *predicatesPlugin) OnSessionOpen(ssn *framework.Session) {
 			predicateStatus = append(predicateStatus, podsNumStatus)
 		}
 
+		if job, found := ssn.Jobs[task.Job]; found {
+			if _, excluded := job.ExcludedNodes[node.Name]; excluded {
+				klog.V(4).Infof("NodeExcluded predicates Task <%s/%s> on Node <%s> failed",
+					task.Namespace, task.Name, node.Name)
+				nodeExcludedStatus := &api.Status{
+					Code: api.UnschedulableAndUnresolvable,
+					Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+						task.Namespace, task.Name, node.Name, api.NodeExcludedByJob),
+				}
+				predicateStatus = append(predicateStatus, nodeExcludedStatus)
+				return predicateStatus, fmt.Errorf("node %s is excluded by job %s", node.Name, job.Name)
+			}
+		}
+
+		if featureStatus := checkNodeFeatures(task, node); featureStatus != nil {
+			klog.V(4).Infof("NodeFeatures predicates Task <%s/%s> on Node <%s> failed",
+				task.Namespace, task.Name, node.Name)
+			predicateStatus = append(predicateStatus, featureStatus)
+			return predicateStatus, fmt.Errorf("%s", featureStatus.Reason)
+		}
+
+		if densityStatus := checkVolcanoPodDensity(task, node, predicate.maxVolcanoPods, predicate.maxVolcanoPodsPercent); densityStatus != nil {
+			klog.V(4).Infof("VolcanoPodDensity predicates Task <%s/%s> on Node <%s> failed",
+				task.Namespace, task.Name, node.Name)
+			predicateStatus = append(predicateStatus, densityStatus)
+		}
+
+		if spreadStatus := checkMaxPerNode(task, node); spreadStatus != nil {
+			klog.V(4).Infof("MaxPerNode predicates Task <%s/%s> on Node <%s> failed",
+				task.Namespace, task.Name, node.Name)
+			predicateStatus = append(predicateStatus, spreadStatus)
+		}
+
+		if storageStatus := checkEphemeralStorage(task, node); storageStatus != nil {
+			klog.V(4).Infof("EphemeralStorage predicates Task <%s/%s> on Node <%s> failed",
+				task.Namespace, task.Name, node.Name)
+			predicateStatus = append(predicateStatus, storageStatus)
+		}
+
+		if exclusiveStatus := checkExclusiveNode(ssn.Jobs, task, node); exclusiveStatus != nil {
+			klog.V(4).Infof("ExclusiveNode predicates Task <%s/%s> on Node <%s> failed",
+				task.Namespace, task.Name, node.Name)
+			predicateStatus = append(predicateStatus, exclusiveStatus)
+			return predicateStatus, fmt.Errorf("%s", exclusiveStatus.Reason)
+		}
+
 		predicateByStablefilter := func(pod *v1.Pod, nodeInfo *k8sframework.NodeInfo) ([]*api.Status, bool, error) {
 			// CheckNodeUnschedulable
 			predicateStatus := make([]*api.Status, 0)
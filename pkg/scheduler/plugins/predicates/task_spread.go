@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"fmt"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// checkMaxPerNode checks that the number of tasks of the same task role of the job on node
+// does not exceed the max per node of task, so tasks are spread without pod anti-affinity.
+func checkMaxPerNode(task *api.TaskInfo, node *api.NodeInfo) *api.Status {
+	if task.MaxPerNode <= 0 || len(task.Job) == 0 {
+		return nil
+	}
+
+	count := 0
+	role := task.GetTaskSpecKey()
+	for _, other := range node.Tasks {
+		if other.UID != task.UID && other.Job == task.Job && other.GetTaskSpecKey() == role {
+			count++
+		}
+	}
+	if count < task.MaxPerNode {
+		return nil
+	}
+
+	return &api.Status{
+		Code: api.Unschedulable,
+		Reason: fmt.Sprintf("Task <%s/%s> on Node <%s> failed, reason: %s",
+			task.Namespace, task.Name, node.Name, api.NodeTaskRoleNumberExceeded),
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestCheckMaxPerNode(t *testing.T) {
+	newTask := func(name string, job api.JobID, role string, maxPerNode int) *api.TaskInfo {
+		return &api.TaskInfo{
+			UID:        api.TaskID(name),
+			Namespace:  "ns",
+			Name:       name,
+			Job:        job,
+			MaxPerNode: maxPerNode,
+			Pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        name,
+				Annotations: map[string]string{batch.TaskSpecKey: role},
+			}},
+		}
+	}
+	newNode := func(tasks ...*api.TaskInfo) *api.NodeInfo {
+		node := &api.NodeInfo{Name: "n1", Tasks: map[api.TaskID]*api.TaskInfo{}}
+		for _, task := range tasks {
+			node.Tasks[task.UID] = task
+		}
+		return node
+	}
+
+	tests := []struct {
+		name     string
+		task     *api.TaskInfo
+		node     *api.NodeInfo
+		expected bool
+	}{
+		{
+			name:     "no limit",
+			task:     newTask("t1", "ns/j1", "worker", 0),
+			node:     newNode(newTask("t2", "ns/j1", "worker", 0), newTask("t3", "ns/j1", "worker", 0)),
+			expected: true,
+		},
+		{
+			name:     "other role and other job are not counted",
+			task:     newTask("t1", "ns/j1", "worker", 1),
+			node:     newNode(newTask("t2", "ns/j1", "ps", 1), newTask("t3", "ns/j2", "worker", 1)),
+			expected: true,
+		},
+		{
+			name:     "below limit",
+			task:     newTask("t1", "ns/j1", "worker", 2),
+			node:     newNode(newTask("t2", "ns/j1", "worker", 2)),
+			expected: true,
+		},
+		{
+			name: "limit reached",
+			task: newTask("t1", "ns/j1", "worker", 2),
+			node: newNode(newTask("t2", "ns/j1", "worker", 2), newTask("t3", "ns/j1", "worker", 2)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := checkMaxPerNode(test.task, test.node)
+			if passed := status == nil; passed != test.expected {
+				t.Errorf("expected passed %v, got status %v", test.expected, status)
+			}
+		})
+	}
+}
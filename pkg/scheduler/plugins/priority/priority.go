@@ -115,7 +115,7 @@ func (pp *priorityPlugin) OnSessionOpen(ssn *framework.Session) {
 
 	jobStarvingFn := func(obj interface{}) bool {
 		ji := obj.(*api.JobInfo)
-		return ji.ReadyTaskNum()+ji.WaitingTaskNum() < int32(len(ji.Tasks))
+		return ji.ReadyTaskNum()+ji.WaitingTaskNum() < int32(len(ji.Tasks))+ji.CompactedTaskNum()
 	}
 	ssn.AddJobStarvingFns(pp.Name(), jobStarvingFn)
 }
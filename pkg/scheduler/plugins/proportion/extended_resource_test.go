@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+const hugePages2Mi = v1.ResourceName("hugepages-2Mi")
+
+// buildGPUPods builds the pods of 1 cpu, 1G memory and gpus of the podgroup, named by the podgroup and their index.
+func buildGPUPods(pg string, phase v1.PodPhase, node, gpus string, count int) []*v1.Pod {
+	var pods []*v1.Pod
+	for i := 0; i < count; i++ {
+		pods = append(pods, util.BuildPod("ns", fmt.Sprintf("%s-%d", pg, i), node, phase, util.BuildResourceListWithGPU("1", "1G", gpus), pg, nil, nil))
+	}
+	return pods
+}
+
+func TestExtendedResourceCapability(t *testing.T) {
+	guaranteed := util.BuildQueue("guaranteed", 1, nil)
+	guaranteed.Spec.Guarantee.Resource = v1.ResourceList{api.GPUResourceName: resource.MustParse("4")}
+	nodeResource := util.BuildResourceListWithGPU("24", "48G", "8")
+	nodeResource[hugePages2Mi] = resource.MustParse("2Gi")
+
+	var pp *proportionPlugin
+	c := uthelper.TestCommonStruct{
+		Name: "capability and guarantee of extended resources bound the deserved resources",
+		Plugins: map[string]framework.PluginBuilder{PluginName: func(arguments framework.Arguments) framework.Plugin {
+			pp = New(arguments).(*proportionPlugin)
+			return pp
+		}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-guaranteed", "guaranteed", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-cpu", "cpu", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-gpu", "gpu", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: append(append(buildGPUPods("pg-guaranteed", v1.PodPending, "", "1", 8),
+			buildGPUPods("pg-cpu", v1.PodPending, "", "1", 8)...), buildGPUPods("pg-gpu", v1.PodPending, "", "1", 8)...),
+		Nodes: []*v1.Node{util.BuildNode("n1", nodeResource, nil)},
+		Queues: []*schedulingv1.Queue{
+			guaranteed,
+			// GPUs are not limited by the capability, but by the GPUs not guaranteed to other queues.
+			util.BuildQueue("cpu", 1, v1.ResourceList{v1.ResourceCPU: resource.MustParse("16")}),
+			util.BuildQueue("gpu", 1, v1.ResourceList{api.GPUResourceName: resource.MustParse("2"), hugePages2Mi: resource.MustParse("1Gi")}),
+		},
+	}
+	defer c.Close()
+	c.Open()
+
+	for queue, expected := range map[api.QueueID]map[v1.ResourceName]float64{
+		"cpu": {v1.ResourceCPU: 16000, api.GPUResourceName: 4000, hugePages2Mi: 2 * 1024 * 1024 * 1024 * 1000},
+		"gpu": {v1.ResourceCPU: 24000, api.GPUResourceName: 2000, hugePages2Mi: 1024 * 1024 * 1024 * 1000},
+	} {
+		attr := pp.queueOpts[queue]
+		for name, quantity := range expected {
+			if got := attr.realCapability.Get(name); got != quantity {
+				t.Errorf("expected real capability %v of %s of queue %s, got %v", quantity, name, queue, got)
+			}
+		}
+	}
+	if deserved := pp.queueOpts["gpu"].deserved.Get(api.GPUResourceName); deserved != 2000 {
+		t.Errorf("expected deserved gpu 2000 of queue gpu, got %v", deserved)
+	}
+	if deserved := pp.queueOpts["cpu"].deserved.Get(api.GPUResourceName); deserved > 4000 {
+		t.Errorf("expected deserved gpu of queue cpu at most 4000, got %v", deserved)
+	}
+}
+
+func TestExtendedResourceReclaimable(t *testing.T) {
+	pgA := util.BuildPodGroup("ns", "pg-a", "a", 1, schedulingv1.PodGroupRunning)
+	pgB := util.BuildPodGroup("ns", "pg-b", "b", 1, schedulingv1.PodGroupInqueue)
+	c := uthelper.TestCommonStruct{
+		Name:      "tasks are reclaimed from queues over their deserved resources requested by the reclaimer",
+		Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+		PodGroups: []*schedulingv1.PodGroup{pgA, pgB},
+		// Queue a deserves 2 of the 4 gpus but is allocated all of them, it is within its deserved cpu and memory.
+		Pods: append(buildGPUPods("pg-a", v1.PodRunning, "n1", "2", 2),
+			util.BuildPod("ns", "pg-b-gpu", "", v1.PodPending, util.BuildResourceListWithGPU("1", "1G", "2"), "pg-b", nil, nil),
+			util.BuildPod("ns", "pg-b-cpu", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg-b", nil, nil)),
+		Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceListWithGPU("4", "8G", "4"), nil)},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("a", 1, nil), util.BuildQueue("b", 1, nil)},
+	}
+	defer c.Close()
+	ssn := c.Open()
+
+	var reclaimees []*api.TaskInfo
+	for _, task := range ssn.Jobs["ns/pg-a"].Tasks {
+		reclaimees = append(reclaimees, task)
+	}
+	reclaimers := map[string]*api.TaskInfo{}
+	for _, task := range ssn.Jobs["ns/pg-b"].Tasks {
+		reclaimers[task.Name] = task
+	}
+
+	for reclaimer, expected := range map[string]int{
+		// A task of queue a is reclaimed for gpus, until queue a is within its deserved gpus.
+		"pg-b-gpu": 1,
+		// Queue a is within its deserved cpu and memory, its gpus are not reclaimed for a cpu task.
+		"pg-b-cpu": 0,
+	} {
+		if victims := ssn.Reclaimable(reclaimers[reclaimer], reclaimees); len(victims) != expected {
+			t.Errorf("expected %d victims for %s, got %v", expected, reclaimer, victims)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// fillIdle returns the resource which is neither allocated nor deserved by the demand of other queues,
+// only this otherwise-idle resource is available to fill queues.
+func (pp *proportionPlugin) fillIdle() *api.Resource {
+	used := api.EmptyResource()
+	for _, attr := range pp.queueOpts {
+		// The resources of queues with parents are in the ones of the queues at the top of the hierarchy.
+		if attr.parent != nil {
+			continue
+		}
+		used.Add(attr.allocated)
+		if attr.fill {
+			continue
+		}
+		unused, _ := attr.deserved.Diff(attr.allocated, api.Zero)
+		used.Add(unused)
+	}
+	idle, _ := pp.totalResource.Diff(used, api.Zero)
+	return idle
+}
+
+// fillAllocatable checks whether the candidate of fill queue fits in the otherwise-idle resource.
+func (pp *proportionPlugin) fillAllocatable(attr *queueAttr, candidate *api.TaskInfo) bool {
+	idle := pp.fillIdle()
+	allocatable := candidate.Resreq.Compare(idle, zeroDimensions).AllLessEqual()
+	if !allocatable {
+		klog.V(3).Infof("Fill queue <%v>: idle <%v>, allocated <%v>; Candidate <%v>: resource request <%v>",
+			attr.name, idle, attr.allocated, candidate.Name, candidate.Resreq)
+	}
+	return allocatable
+}
+
+// isFillTask checks whether the task belongs to a job of fill queue.
+func (pp *proportionPlugin) isFillTask(jobs map[api.JobID]*api.JobInfo, task *api.TaskInfo) bool {
+	job, found := jobs[task.Job]
+	if !found {
+		return false
+	}
+	attr, found := pp.queueOpts[job.Queue]
+	return found && attr.fill
+}
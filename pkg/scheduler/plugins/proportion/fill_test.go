@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestFillAllocatable(t *testing.T) {
+	pp := &proportionPlugin{
+		totalResource: &api.Resource{MilliCPU: 10000, Memory: 10000},
+		queueOpts: map[api.QueueID]*queueAttr{
+			// q1 still deserves 2000 cpu it has not allocated yet
+			"q1": {
+				deserved:  &api.Resource{MilliCPU: 6000, Memory: 4000},
+				allocated: &api.Resource{MilliCPU: 4000, Memory: 4000},
+			},
+			// fill has no deserved share
+			"fill": {
+				name:      "fill",
+				fill:      true,
+				deserved:  api.EmptyResource(),
+				allocated: &api.Resource{MilliCPU: 1000, Memory: 1000},
+			},
+		},
+	}
+
+	if idle, expected := pp.fillIdle(), (&api.Resource{MilliCPU: 3000, Memory: 5000}); !idle.Equal(expected, api.Zero) {
+		t.Errorf("expected idle %v, got %v", expected, idle)
+	}
+
+	tests := []struct {
+		name     string
+		resreq   *api.Resource
+		expected bool
+	}{
+		{
+			name:     "fits in idle resource",
+			resreq:   &api.Resource{MilliCPU: 3000, Memory: 1000},
+			expected: true,
+		},
+		{
+			name:     "takes resource deserved by other queue",
+			resreq:   &api.Resource{MilliCPU: 4000, Memory: 1000},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidate := &api.TaskInfo{Name: "t1", Resreq: test.resreq}
+			if allocatable := pp.fillAllocatable(pp.queueOpts["fill"], candidate); allocatable != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, allocatable)
+			}
+		})
+	}
+}
+
+func TestSortReclaimeesFillFirst(t *testing.T) {
+	ssn := &framework.Session{
+		Jobs: map[api.JobID]*api.JobInfo{
+			"j1": {UID: "j1", Queue: "q1", Priority: 1},
+			"j2": {UID: "j2", Queue: "fill", Priority: 10},
+		},
+	}
+	pp := &proportionPlugin{
+		reclaimOrder: ReclaimOrderLowestPriority,
+		queueOpts: map[api.QueueID]*queueAttr{
+			"q1":   {},
+			"fill": {fill: true},
+		},
+	}
+
+	reclaimees := []*api.TaskInfo{
+		{Name: "t1", Job: "j1"},
+		{Name: "t2", Job: "j2"},
+		{Name: "t3", Job: "j1"},
+	}
+	var names []string
+	for _, reclaimee := range pp.sortReclaimees(ssn, reclaimees) {
+		names = append(names, reclaimee.Name)
+	}
+	if expected := []string{"t2", "t1", "t3"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// ForecastEnable is the key of argument which enables taking the forecasted
+	// demand of queues into account when calculating their deserved resource.
+	ForecastEnable = "proportion.forecast.enable"
+	// ForecastHalfLifeMinutes is the key of argument which defines how fast the
+	// forecaster forgets the submission history of queues, in minutes.
+	ForecastHalfLifeMinutes = "proportion.forecast.halfLifeMinutes"
+
+	defaultForecastHalfLifeMinutes = 15
+	// forecastHorizon is how far ahead the demand of queues is projected.
+	forecastHorizon = time.Hour
+)
+
+// demandHistory is the smoothed demand of a queue, tracked per resource dimension.
+type demandHistory struct {
+	level    map[v1.ResourceName]float64
+	trend    map[v1.ResourceName]float64
+	lastSeen time.Time
+}
+
+// historyKey is the key of the demand history of a queue in a scheduling profile.
+type historyKey struct {
+	profile string
+	queue   api.QueueID
+}
+
+// demandForecaster projects the demand of queues with double exponential smoothing
+// over the demand observed in each session. Plugin instances are rebuilt per session,
+// so it keeps the history across sessions, per scheduling profile as each profile
+// observes the demand of its own jobs.
+type demandForecaster struct {
+	sync.Mutex
+	queues map[historyKey]*demandHistory
+}
+
+var forecaster = newDemandForecaster()
+
+// SnapshotState saves the demand history of queues, and returns the function restoring it.
+func SnapshotState() func() {
+	forecaster.Lock()
+	queues := copyDemandHistories(forecaster.queues)
+	forecaster.Unlock()
+	return func() {
+		forecaster.Lock()
+		defer forecaster.Unlock()
+		forecaster.queues = copyDemandHistories(queues)
+	}
+}
+
+func copyDemandHistories(queues map[historyKey]*demandHistory) map[historyKey]*demandHistory {
+	copied := make(map[historyKey]*demandHistory, len(queues))
+	for key, history := range queues {
+		h := &demandHistory{
+			level:    make(map[v1.ResourceName]float64, len(history.level)),
+			trend:    make(map[v1.ResourceName]float64, len(history.trend)),
+			lastSeen: history.lastSeen,
+		}
+		for rn, level := range history.level {
+			h.level[rn] = level
+		}
+		for rn, trend := range history.trend {
+			h.trend[rn] = trend
+		}
+		copied[key] = h
+	}
+	return copied
+}
+
+func newDemandForecaster() *demandForecaster {
+	return &demandForecaster{
+		queues: map[historyKey]*demandHistory{},
+	}
+}
+
+// observe records the demand of queue in profile at now, and returns its projected demand for the forecast horizon.
+func (f *demandForecaster) observe(profile string, queue api.QueueID, demand *api.Resource, now time.Time, halfLife time.Duration) *api.Resource {
+	f.Lock()
+	defer f.Unlock()
+
+	key := historyKey{profile: profile, queue: queue}
+	history, found := f.queues[key]
+	if !found {
+		history = &demandHistory{
+			level: map[v1.ResourceName]float64{},
+			trend: map[v1.ResourceName]float64{},
+		}
+		for _, rn := range demand.ResourceNames() {
+			history.level[rn] = demand.Get(rn)
+		}
+		history.lastSeen = now
+		f.queues[key] = history
+		return history.forecast()
+	}
+
+	elapsed := now.Sub(history.lastSeen).Seconds()
+	if elapsed <= 0 {
+		return history.forecast()
+	}
+	alpha := 1 - math.Exp2(-elapsed/halfLife.Seconds())
+
+	dimensions := map[v1.ResourceName]struct{}{}
+	for _, rn := range demand.ResourceNames() {
+		dimensions[rn] = struct{}{}
+	}
+	for rn := range history.level {
+		dimensions[rn] = struct{}{}
+	}
+	for rn := range dimensions {
+		prevLevel := history.level[rn]
+		prevTrend := history.trend[rn]
+		level := alpha*demand.Get(rn) + (1-alpha)*(prevLevel+prevTrend*elapsed)
+		history.level[rn] = level
+		history.trend[rn] = alpha*(level-prevLevel)/elapsed + (1-alpha)*prevTrend
+	}
+	history.lastSeen = now
+
+	return history.forecast()
+}
+
+// prune forgets the history in profile of queues which do not exist anymore.
+func (f *demandForecaster) prune(profile string, queues map[api.QueueID]*api.QueueInfo) {
+	f.Lock()
+	defer f.Unlock()
+
+	for key := range f.queues {
+		if _, found := queues[key.queue]; key.profile == profile && !found {
+			delete(f.queues, key)
+		}
+	}
+}
+
+func (h *demandHistory) forecast() *api.Resource {
+	res := api.EmptyResource()
+	for rn, level := range h.level {
+		value := math.Max(0, level+h.trend[rn]*forecastHorizon.Seconds())
+		switch rn {
+		case v1.ResourceCPU:
+			res.MilliCPU = value
+		case v1.ResourceMemory:
+			res.Memory = value
+		default:
+			res.SetScalar(rn, value)
+		}
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestDemandForecaster(t *testing.T) {
+	halfLife := 15 * time.Minute
+	start := time.Now()
+	burst := &api.Resource{MilliCPU: 8000, Memory: 8000, ScalarResources: map[v1.ResourceName]float64{"nvidia.com/gpu": 4000}}
+
+	f := newDemandForecaster()
+	forecast := f.observe("", "q1", burst, start, halfLife)
+	if !forecast.Equal(burst, api.Zero) {
+		t.Errorf("expected first forecast <%v>, got <%v>", burst, forecast)
+	}
+
+	// Steady demand keeps the forecast at the demand.
+	for i := 1; i <= 10; i++ {
+		forecast = f.observe("", "q1", burst, start.Add(time.Duration(i)*time.Minute), halfLife)
+	}
+	if math.Abs(forecast.MilliCPU-burst.MilliCPU) > 1 {
+		t.Errorf("expected steady forecast <%v>, got <%v>", burst, forecast)
+	}
+
+	// Once the burst ends, the forecast decays instead of dropping to zero.
+	now := start.Add(11 * time.Minute)
+	forecast = f.observe("", "q1", api.EmptyResource(), now, halfLife)
+	if forecast.MilliCPU <= 0 || forecast.MilliCPU >= burst.MilliCPU {
+		t.Errorf("expected forecast between 0 and <%v> right after burst, got <%v>", burst.MilliCPU, forecast.MilliCPU)
+	}
+	if forecast.Get("nvidia.com/gpu") <= 0 {
+		t.Errorf("expected gpu forecast kept right after burst, got <%v>", forecast)
+	}
+
+	// Observing at the same time does not change the forecast.
+	again := f.observe("", "q1", api.EmptyResource(), now, halfLife)
+	if !again.Equal(forecast, api.Zero) {
+		t.Errorf("expected forecast <%v> at same time, got <%v>", forecast, again)
+	}
+
+	// A long idle period forgets the burst.
+	forecast = f.observe("", "q1", api.EmptyResource(), now.Add(24*time.Hour), halfLife)
+	if !forecast.IsEmpty() {
+		t.Errorf("expected empty forecast after long idle period, got <%v>", forecast)
+	}
+
+	f.observe("", "q2", burst, start, halfLife)
+	f.prune("", map[api.QueueID]*api.QueueInfo{"q2": {}})
+	if _, found := f.queues[historyKey{queue: "q1"}]; found {
+		t.Errorf("expected history of q1 pruned")
+	}
+	if _, found := f.queues[historyKey{queue: "q2"}]; !found {
+		t.Errorf("expected history of q2 kept")
+	}
+}
+
+func TestDemandForecasterPerProfile(t *testing.T) {
+	halfLife := 15 * time.Minute
+	start := time.Now()
+	burst := &api.Resource{MilliCPU: 8000, Memory: 8000}
+
+	f := newDemandForecaster()
+	f.observe("", "q1", burst, start, halfLife)
+	// The profile gpu sees no demand of its own jobs in q1, which does not decay the history of the default profile.
+	if forecast := f.observe("gpu", "q1", api.EmptyResource(), start.Add(time.Minute), halfLife); !forecast.IsEmpty() {
+		t.Errorf("expected empty forecast of q1 in profile gpu, got <%v>", forecast)
+	}
+	if forecast := f.observe("", "q1", burst, start.Add(time.Minute), halfLife); math.Abs(forecast.MilliCPU-burst.MilliCPU) > 1 {
+		t.Errorf("expected forecast <%v> of q1 in the default profile, got <%v>", burst, forecast)
+	}
+
+	// Pruning the queues of a profile keeps the histories of the other profiles.
+	f.prune("gpu", map[api.QueueID]*api.QueueInfo{})
+	if _, found := f.queues[historyKey{profile: "gpu", queue: "q1"}]; found {
+		t.Errorf("expected history of q1 in profile gpu pruned")
+	}
+	if _, found := f.queues[historyKey{queue: "q1"}]; !found {
+		t.Errorf("expected history of q1 in the default profile kept")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"sort"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/api/helpers"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// buildHierarchy links the attributes of the queues of jobs to the ones of their ancestors by the
+// scheduling.volcano.sh/parent-queue annotation, adds the resources of the descendants of each queue to it, and
+// returns the attributes of the queues at the top of the hierarchy.
+func (pp *proportionPlugin) buildHierarchy(ssn *framework.Session) []*queueAttr {
+	ids := make([]string, 0, len(pp.queueOpts))
+	for id := range pp.queueOpts {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for attr := pp.queueOpts[api.QueueID(id)]; attr.parent == nil; attr = attr.parent {
+			parentID := api.QueueID(ssn.Queues[attr.queueID].Parent)
+			if parentID == "" {
+				break
+			}
+			parent, found := pp.queueOpts[parentID]
+			if !found {
+				parent = pp.newQueueAttr(ssn.Queues[parentID])
+				parent.demand = parent.request
+				pp.queueOpts[parentID] = parent
+			}
+			attr.parent = parent
+			parent.children = append(parent.children, attr)
+		}
+	}
+
+	attrs := make([]*queueAttr, 0, len(pp.queueOpts))
+	depths := map[*queueAttr]int{}
+	for _, attr := range pp.queueOpts {
+		attrs = append(attrs, attr)
+		for ancestor := attr.parent; ancestor != nil; ancestor = ancestor.parent {
+			depths[attr]++
+		}
+		if len(attr.children) != 0 {
+			attr.demand = attr.demand.Clone()
+		}
+	}
+	// The descendants are added to a queue before it is added to its parent.
+	sort.Slice(attrs, func(i, j int) bool {
+		if depths[attrs[i]] != depths[attrs[j]] {
+			return depths[attrs[i]] > depths[attrs[j]]
+		}
+		return attrs[i].name < attrs[j].name
+	})
+	childGuarantees := map[*queueAttr]*api.Resource{}
+	var top []*queueAttr
+	for _, attr := range attrs {
+		if guarantee, found := childGuarantees[attr]; found {
+			attr.guarantee = helpers.Max(attr.guarantee, guarantee)
+		}
+		parent := attr.parent
+		if parent == nil {
+			top = append(top, attr)
+			continue
+		}
+		parent.allocated.Add(attr.allocated)
+		parent.request.Add(attr.request)
+		parent.demand.Add(attr.demand)
+		parent.inqueue.Add(attr.inqueue)
+		parent.elastic.Add(attr.elastic)
+		if _, found := childGuarantees[parent]; !found {
+			childGuarantees[parent] = api.EmptyResource()
+		}
+		childGuarantees[parent].Add(attr.guarantee)
+	}
+	return top
+}
+
+// path returns the attributes of the queue and its ancestors from the top of the hierarchy.
+func (attr *queueAttr) path() []*queueAttr {
+	var path []*queueAttr
+	for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+		path = append([]*queueAttr{ancestor}, path...)
+	}
+	return path
+}
+
+// branches returns the sibling queues the queues descend from below their closest common ancestor, or the queues
+// themselves if they are the same queue or one is an ancestor of the other.
+func branches(lattr, rattr *queueAttr) (*queueAttr, *queueAttr) {
+	lpath, rpath := lattr.path(), rattr.path()
+	for i := 0; i < len(lpath) && i < len(rpath); i++ {
+		if lpath[i] != rpath[i] {
+			return lpath[i], rpath[i]
+		}
+	}
+	return lattr, rattr
+}
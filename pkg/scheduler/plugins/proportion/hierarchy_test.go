@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/actions/enqueue"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// buildChildQueue builds a queue of the weight with the parent queue.
+func buildChildQueue(name, parent string, weight int32) *schedulingv1.Queue {
+	queue := util.BuildQueue(name, weight, nil)
+	queue.Annotations = map[string]string{api.ParentQueueAnnotation: parent}
+	return queue
+}
+
+// buildPods builds the pods of 1 cpu and 1G memory of the podgroup, named by the podgroup and their index.
+func buildPods(pg string, phase v1.PodPhase, node string, count int) []*v1.Pod {
+	var pods []*v1.Pod
+	for i := 0; i < count; i++ {
+		pods = append(pods, util.BuildPod("ns", fmt.Sprintf("%s-%d", pg, i), node, phase, util.BuildResourceList("1", "1G"), pg, nil, nil))
+	}
+	return pods
+}
+
+func hierarchicalQueues() []*schedulingv1.Queue {
+	return []*schedulingv1.Queue{
+		util.BuildQueue("a", 1, nil),
+		util.BuildQueue("b", 1, nil),
+		buildChildQueue("a1", "a", 3),
+		buildChildQueue("a2", "a", 1),
+	}
+}
+
+func TestHierarchicalDeserved(t *testing.T) {
+	var pp *proportionPlugin
+	c := uthelper.TestCommonStruct{
+		Name: "siblings share the deserved resources of their parent",
+		Plugins: map[string]framework.PluginBuilder{PluginName: func(arguments framework.Arguments) framework.Plugin {
+			pp = New(arguments).(*proportionPlugin)
+			return pp
+		}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-a1", "a1", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-a2", "a2", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-b", "b", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods:   append(append(buildPods("pg-a1", v1.PodPending, "", 8), buildPods("pg-a2", v1.PodPending, "", 8)...), buildPods("pg-b", v1.PodPending, "", 8)...),
+		Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "16G"), nil)},
+		Queues: hierarchicalQueues(),
+	}
+	defer c.Close()
+	c.Open()
+
+	for queue, expected := range map[api.QueueID]float64{"a": 4000, "b": 4000, "a1": 3000, "a2": 1000} {
+		attr, found := pp.queueOpts[queue]
+		if !found {
+			t.Errorf("expected attributes of queue %s", queue)
+			continue
+		}
+		if attr.deserved.MilliCPU != expected {
+			t.Errorf("expected deserved cpu %v of queue %s, got %v", expected, queue, attr.deserved.MilliCPU)
+		}
+	}
+	if a := pp.queueOpts["a"]; a.request.MilliCPU != 16000 || len(a.children) != 2 {
+		t.Errorf("expected parent queue a with the requests of its 2 children, got %v and %d", a.request.MilliCPU, len(a.children))
+	}
+}
+
+func TestHierarchicalAllocate(t *testing.T) {
+	c := uthelper.TestCommonStruct{
+		Name:    "tasks are allocated up to the deserved resources of queues and their ancestors",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-a1", "a1", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-a2", "a2", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-b", "b", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods:   append(append(buildPods("pg-a1", v1.PodPending, "", 8), buildPods("pg-a2", v1.PodPending, "", 8)...), buildPods("pg-b", v1.PodPending, "", 8)...),
+		Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "16G"), nil)},
+		Queues: hierarchicalQueues(),
+	}
+	defer c.Close()
+	c.Run(allocate.New())
+
+	binds := map[string]int{}
+	for key := range c.Cache().Binds() {
+		// The keys are ns/pg-<queue>-<index>.
+		binds[strings.Split(key, "-")[1]]++
+	}
+	for pg, expected := range map[string]int{"a1": 3, "a2": 1, "b": 4} {
+		if binds[pg] != expected {
+			t.Errorf("expected %d tasks of pg-%s bound, got %d", expected, pg, binds[pg])
+		}
+	}
+}
+
+func TestHierarchicalEnqueue(t *testing.T) {
+	pgs := []*schedulingv1.PodGroup{
+		util.BuildPodGroup("ns", "pg-a", "a", 1, schedulingv1.PodGroupPending),
+		util.BuildPodGroup("ns", "pg-a1", "a1", 1, schedulingv1.PodGroupPending),
+	}
+	for _, pg := range pgs {
+		minResources := util.BuildResourceList("1", "1G")
+		pg.Spec.MinResources = &minResources
+	}
+	c := uthelper.TestCommonStruct{
+		Name:         "jobs of queues with child queues are not enqueued",
+		Plugins:      map[string]framework.PluginBuilder{PluginName: New},
+		PodGroups:    pgs,
+		Pods:         append(buildPods("pg-a", v1.PodPending, "", 1), buildPods("pg-a1", v1.PodPending, "", 1)...),
+		Nodes:        []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "16G"), nil)},
+		Queues:       hierarchicalQueues(),
+		ExpectPhases: map[string]schedulingv1.PodGroupPhase{"ns/pg-a1": schedulingv1.PodGroupInqueue},
+		ExpectEvents: []string{"queue a has child queues"},
+	}
+	defer c.Close()
+	c.Run(enqueue.New())
+	if err := c.CheckAll(); err != nil {
+		t.Error(err)
+	}
+	if phase, found := c.Cache().PodGroupPhases()["ns/pg-a"]; found && phase != string(schedulingv1.PodGroupPending) {
+		t.Errorf("expected podgroup of parent queue pending, got %s", phase)
+	}
+}
+
+func TestHierarchicalReclaimable(t *testing.T) {
+	pods := append(append(buildPods("pg-a1", v1.PodRunning, "n1", 4), buildPods("pg-a2", v1.PodPending, "", 4)...),
+		append(buildPods("pg-b", v1.PodRunning, "n1", 2), buildPods("pg-b2", v1.PodPending, "", 4)...)...)
+	c := uthelper.TestCommonStruct{
+		Name:    "tasks are reclaimed from another subtree only if it is over its deserved resources",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-a1", "a1", 1, schedulingv1.PodGroupRunning),
+			util.BuildPodGroup("ns", "pg-a2", "a2", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-b", "b", 1, schedulingv1.PodGroupRunning),
+			util.BuildPodGroup("ns", "pg-b2", "b", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods:   pods,
+		Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "8G"), nil)},
+		Queues: hierarchicalQueues(),
+	}
+	defer c.Close()
+	ssn := c.Open()
+
+	tasks := map[string]*api.TaskInfo{}
+	for _, job := range ssn.Jobs {
+		for _, task := range job.Tasks {
+			tasks[task.Name] = task
+		}
+	}
+	reclaimees := []*api.TaskInfo{tasks["pg-a1-0"], tasks["pg-a1-1"]}
+	// a1 deserves 3 of the 4 cpu of a, while a is not over its deserved resources.
+	if victims := ssn.Reclaimable(tasks["pg-b2-0"], reclaimees); len(victims) != 0 {
+		t.Errorf("expected no victims of queue b in subtree a, got %d", len(victims))
+	}
+	if victims := ssn.Reclaimable(tasks["pg-a2-0"], reclaimees); len(victims) != 1 {
+		t.Errorf("expected 1 victim of sibling queue a2, got %d", len(victims))
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/actions/enqueue"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestOtherProfileJobs(t *testing.T) {
+	pending := util.BuildPodGroup("ns", "pg-mine", "q1", 1, schedulingv1.PodGroupPending)
+	minResources := util.BuildResourceList("1", "1G")
+	pending.Spec.MinResources = &minResources
+
+	var pp *proportionPlugin
+	c := uthelper.TestCommonStruct{
+		Name: "jobs of other profiles take the capability of the queue they share",
+		Plugins: map[string]framework.PluginBuilder{PluginName: func(arguments framework.Arguments) framework.Plugin {
+			pp = New(arguments).(*proportionPlugin)
+			return pp
+		}},
+		InProfile: func(job *api.JobInfo) bool { return job.Name != "pg-other" },
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-other", "q1", 4, schedulingv1.PodGroupRunning),
+			pending,
+		},
+		Pods:         append(buildPods("pg-other", v1.PodRunning, "n1", 4), buildPods("pg-mine", v1.PodPending, "", 1)...),
+		Nodes:        []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "16G"), nil)},
+		Queues:       []*schedulingv1.Queue{util.BuildQueue("q1", 1, util.BuildResourceList("4", "8G"))},
+		ExpectPhases: map[string]schedulingv1.PodGroupPhase{"ns/pg-mine": schedulingv1.PodGroupPending},
+	}
+	defer c.Close()
+	ssn := c.Open()
+	if _, found := ssn.Jobs["ns/pg-other"]; found {
+		t.Fatalf("expected job pg-other left to the other profile")
+	}
+	if allocated := pp.queueOpts["q1"].allocated.MilliCPU; allocated != 4000 {
+		t.Errorf("expected allocated cpu 4000 of queue q1 with the job of the other profile, got %v", allocated)
+	}
+
+	enqueue.New().Execute(ssn)
+	c.Close()
+	if err := c.CheckAll(); err != nil {
+		t.Error(err)
+	}
+}
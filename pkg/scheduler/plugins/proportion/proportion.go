@@ -17,8 +17,10 @@ limitations under the License.
 package proportion
 
 import (
+	"fmt"
 	"math"
 	"reflect"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -34,12 +36,23 @@ import (
 // PluginName indicates name of volcano scheduler plugin.
 const PluginName = "proportion"
 
+// zeroDimensions compares all dimensions, treating the ones not defined as zero.
+var zeroDimensions = api.NewDimensionSet(api.Zero, api.Zero)
+
 type proportionPlugin struct {
 	totalResource  *api.Resource
 	totalGuarantee *api.Resource
 	queueOpts      map[api.QueueID]*queueAttr
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
+
+	// forecastEnabled takes the forecasted demand of queues into account when calculating deserved
+	forecastEnabled  bool
+	forecastHalfLife time.Duration
+	// reclaimOrder defines in which order the tasks of borrowing queues are reclaimed
+	reclaimOrder string
+	// totalResourcePolicy selects the nodes counted into the total resource
+	totalResourcePolicy totalResourcePolicy
 }
 
 type queueAttr struct {
@@ -47,10 +60,14 @@ type queueAttr struct {
 	name    string
 	weight  int32
 	share   float64
+	// fill means the queue is a best-effort fill queue which never accrues deserved share
+	fill bool
 
 	deserved  *api.Resource
 	allocated *api.Resource
 	request   *api.Resource
+	// demand is the request, or the max of request and forecasted demand if forecasting is enabled
+	demand *api.Resource
 	// elastic represents the sum of job's elastic resource, job's elastic = job.allocated - job.minAvailable
 	elastic *api.Resource
 	// inqueue represents the resource request of the inqueue job
@@ -59,16 +76,34 @@ type queueAttr struct {
 	// realCapability represents the resource limit of the queue, LessEqual capability
 	realCapability *api.Resource
 	guarantee      *api.Resource
+
+	// parent and children link the queue in the hierarchy of queues, the resources of a parent queue include the
+	// ones of its descendants
+	parent   *queueAttr
+	children []*queueAttr
 }
 
 // New return proportion action
 func New(arguments framework.Arguments) framework.Plugin {
-	return &proportionPlugin{
-		totalResource:   api.EmptyResource(),
-		totalGuarantee:  api.EmptyResource(),
-		queueOpts:       map[api.QueueID]*queueAttr{},
-		pluginArguments: arguments,
+	halfLifeMinutes := defaultForecastHalfLifeMinutes
+	arguments.GetInt(&halfLifeMinutes, ForecastHalfLifeMinutes)
+	if halfLifeMinutes <= 0 {
+		klog.Warningf("Invalid %s %d, use default %d", ForecastHalfLifeMinutes, halfLifeMinutes, defaultForecastHalfLifeMinutes)
+		halfLifeMinutes = defaultForecastHalfLifeMinutes
+	}
+
+	pp := &proportionPlugin{
+		totalResource:    api.EmptyResource(),
+		totalGuarantee:   api.EmptyResource(),
+		queueOpts:        map[api.QueueID]*queueAttr{},
+		pluginArguments:  arguments,
+		forecastHalfLife: time.Duration(halfLifeMinutes) * time.Minute,
+		reclaimOrder:     getReclaimOrder(arguments),
+
+		totalResourcePolicy: newTotalResourcePolicy(arguments),
 	}
+	arguments.GetBool(&pp.forecastEnabled, ForecastEnable)
+	return pp
 }
 
 func (pp *proportionPlugin) Name() string {
@@ -77,53 +112,22 @@ func (pp *proportionPlugin) Name() string {
 
 func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	// Prepare scheduling data for this session.
-	pp.totalResource.Add(ssn.TotalResource)
+	pp.totalResource.Add(pp.totalResourcePolicy.totalResource(ssn))
 
 	klog.V(4).Infof("The total resource is <%v>", pp.totalResource)
 	for _, queue := range ssn.Queues {
-		if len(queue.Queue.Spec.Guarantee.Resource) == 0 {
+		if len(queue.Queue.Spec.Guarantee.Resource) == 0 || queue.Fill {
 			continue
 		}
 		guarantee := api.NewResource(queue.Queue.Spec.Guarantee.Resource)
 		pp.totalGuarantee.Add(guarantee)
 	}
 	klog.V(4).Infof("The total guarantee resource is <%v>", pp.totalGuarantee)
-	// Build attributes for Queues.
-	for _, job := range ssn.Jobs {
+	// Build attributes for Queues, the jobs of other profiles share the queues with the jobs of the session.
+	for _, job := range allJobs(ssn) {
 		klog.V(4).Infof("Considering Job <%s/%s>.", job.Namespace, job.Name)
 		if _, found := pp.queueOpts[job.Queue]; !found {
-			queue := ssn.Queues[job.Queue]
-			attr := &queueAttr{
-				queueID: queue.UID,
-				name:    queue.Name,
-				weight:  queue.Weight,
-
-				deserved:  api.EmptyResource(),
-				allocated: api.EmptyResource(),
-				request:   api.EmptyResource(),
-				elastic:   api.EmptyResource(),
-				inqueue:   api.EmptyResource(),
-				guarantee: api.EmptyResource(),
-			}
-			if len(queue.Queue.Spec.Capability) != 0 {
-				attr.capability = api.NewResource(queue.Queue.Spec.Capability)
-				if attr.capability.MilliCPU <= 0 {
-					attr.capability.MilliCPU = math.MaxFloat64
-				}
-				if attr.capability.Memory <= 0 {
-					attr.capability.Memory = math.MaxFloat64
-				}
-			}
-			if len(queue.Queue.Spec.Guarantee.Resource) != 0 {
-				attr.guarantee = api.NewResource(queue.Queue.Spec.Guarantee.Resource)
-			}
-			realCapability := pp.totalResource.Clone().Sub(pp.totalGuarantee).Add(attr.guarantee)
-			if attr.capability == nil {
-				attr.realCapability = realCapability
-			} else {
-				attr.realCapability = helpers.Min(realCapability, attr.capability)
-			}
-			pp.queueOpts[job.Queue] = attr
+			pp.queueOpts[job.Queue] = pp.newQueueAttr(ssn.Queues[job.Queue])
 			klog.V(4).Infof("Added Queue <%s> attributes.", job.Queue)
 		}
 
@@ -166,6 +170,20 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		}
 	}
 
+	now := time.Now()
+	for _, attr := range pp.queueOpts {
+		attr.demand = attr.request
+		if pp.forecastEnabled {
+			forecast := forecaster.observe(ssn.Profile(), attr.queueID, attr.request, now, pp.forecastHalfLife)
+			attr.demand = helpers.Max(attr.request, forecast)
+			klog.V(4).Infof("Queue <%s> request <%v>, forecasted demand <%v>", attr.name, attr.request, forecast)
+		}
+	}
+	if pp.forecastEnabled {
+		forecaster.prune(ssn.Profile(), ssn.Queues)
+	}
+	top := pp.buildHierarchy(ssn)
+
 	// Record metrics
 	for _, attr := range pp.queueOpts {
 		metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
@@ -178,84 +196,31 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		metrics.UpdateQueuePodGroupUnknownCount(attr.name, queue.Queue.Status.Unknown)
 	}
 
-	remaining := pp.totalResource.Clone()
-	meet := map[api.QueueID]struct{}{}
-	for {
-		totalWeight := int32(0)
-		for _, attr := range pp.queueOpts {
-			if _, found := meet[attr.queueID]; found {
-				continue
-			}
-			totalWeight += attr.weight
-		}
-
-		// If no queues, break
-		if totalWeight == 0 {
-			klog.V(4).Infof("Exiting when total weight is 0")
-			break
-		}
-
-		oldRemaining := remaining.Clone()
-		// Calculates the deserved of each Queue.
-		// increasedDeserved is the increased value for attr.deserved of processed queues
-		// decreasedDeserved is the decreased value for attr.deserved of processed queues
-		increasedDeserved := api.EmptyResource()
-		decreasedDeserved := api.EmptyResource()
-		for _, attr := range pp.queueOpts {
-			klog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
-				attr.name, attr.weight, totalWeight)
-			if _, found := meet[attr.queueID]; found {
-				continue
-			}
-
-			oldDeserved := attr.deserved.Clone()
-			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
+	pp.divide(pp.totalResource.Clone(), top)
 
-			if attr.realCapability != nil {
-				attr.deserved.MinDimensionResource(attr.realCapability, api.Infinity)
-			}
-			attr.deserved.MinDimensionResource(attr.request, api.Zero)
-
-			attr.deserved = helpers.Max(attr.deserved, attr.guarantee)
-			pp.updateShare(attr)
-			klog.V(4).Infof("Format queue <%s> deserved resource to <%v>", attr.name, attr.deserved)
+	ssn.AddQueueOrderFn(pp.Name(), func(l, r interface{}) int {
+		lv := l.(*api.QueueInfo)
+		rv := r.(*api.QueueInfo)
 
-			if attr.request.LessEqual(attr.deserved, api.Zero) {
-				meet[attr.queueID] = struct{}{}
-				klog.V(4).Infof("queue <%s> is meet", attr.name)
-			} else if reflect.DeepEqual(attr.deserved, oldDeserved) {
-				meet[attr.queueID] = struct{}{}
-				klog.V(4).Infof("queue <%s> is meet cause of the capability", attr.name)
+		// Fill queues are ordered after others, so they only take what others leave.
+		if lv.Fill != rv.Fill {
+			if rv.Fill {
+				return -1
 			}
-
-			klog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, realCapability <%v>, allocate <%v>, request <%v>, elastic <%v>, share <%0.2f>",
-				attr.name, attr.deserved, attr.realCapability, attr.allocated, attr.request, attr.elastic, attr.share)
-
-			increased, decreased := attr.deserved.Diff(oldDeserved, api.Zero)
-			increasedDeserved.Add(increased)
-			decreasedDeserved.Add(decreased)
-
-			// Record metrics
-			metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
+			return 1
 		}
 
-		remaining.Sub(increasedDeserved).Add(decreasedDeserved)
-		klog.V(4).Infof("Remaining resource is  <%s>", remaining)
-		if remaining.IsEmpty() || reflect.DeepEqual(remaining, oldRemaining) {
-			klog.V(4).Infof("Exiting when remaining is empty or no queue has more resource request:  <%v>", remaining)
-			break
+		// Queues in different subtrees are ordered by the shares of the subtrees first.
+		lattr, rattr := pp.queueOpts[lv.UID], pp.queueOpts[rv.UID]
+		if lbranch, rbranch := branches(lattr, rattr); lbranch.share != rbranch.share {
+			lattr, rattr = lbranch, rbranch
 		}
-	}
-
-	ssn.AddQueueOrderFn(pp.Name(), func(l, r interface{}) int {
-		lv := l.(*api.QueueInfo)
-		rv := r.(*api.QueueInfo)
 
-		if pp.queueOpts[lv.UID].share == pp.queueOpts[rv.UID].share {
+		if lattr.share == rattr.share {
 			return 0
 		}
 
-		if pp.queueOpts[lv.UID].share < pp.queueOpts[rv.UID].share {
+		if lattr.share < rattr.share {
 			return -1
 		}
 
@@ -265,25 +230,44 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	ssn.AddReclaimableFn(pp.Name(), func(reclaimer *api.TaskInfo, reclaimees []*api.TaskInfo) ([]*api.TaskInfo, int) {
 		var victims []*api.TaskInfo
 		allocations := map[api.QueueID]*api.Resource{}
+		allocationOf := func(attr *queueAttr) *api.Resource {
+			if _, found := allocations[attr.queueID]; !found {
+				allocations[attr.queueID] = attr.allocated.Clone()
+			}
+			return allocations[attr.queueID]
+		}
+		reclaimerAttr := pp.queueOpts[ssn.Jobs[reclaimer.Job].Queue]
 
-		for _, reclaimee := range reclaimees {
+		for _, reclaimee := range pp.sortReclaimees(ssn, reclaimees) {
 			job := ssn.Jobs[reclaimee.Job]
 			attr := pp.queueOpts[job.Queue]
 
-			if _, found := allocations[job.Queue]; !found {
-				allocations[job.Queue] = attr.allocated.Clone()
-			}
-			allocated := allocations[job.Queue]
-			if allocated.LessPartly(reclaimer.Resreq, api.Zero) {
+			allocated := allocationOf(attr)
+			if allocated.Compare(reclaimer.Resreq, zeroDimensions).AnyLess() {
 				klog.V(3).Infof("Failed to allocate resource for Task <%s/%s> in Queue <%s>, not enough resource.",
 					reclaimee.Namespace, reclaimee.Name, job.Queue)
 				continue
 			}
+			// The queue must be over its deserved resources in a dimension both the reclaimer and the reclaimee
+			// request, e.g. GPU tasks of a queue over its deserved GPUs, but not cpu, are not reclaimed for cpu tasks.
+			dimensions := reclaimDimensions(reclaimer, reclaimee)
+			if allocated.Compare(attr.deserved, dimensions).AllLessEqual() {
+				continue
+			}
 
-			if !allocated.LessEqual(attr.deserved, api.Zero) {
-				allocated.Sub(reclaimee.Resreq)
-				victims = append(victims, reclaimee)
+			// Tasks are only reclaimed from another subtree if the subtree is over its deserved resources.
+			_, branch := branches(reclaimerAttr, attr)
+			if branch != attr {
+				branchAllocated := allocationOf(branch)
+				if branchAllocated.Compare(branch.deserved, dimensions).AllLessEqual() {
+					klog.V(4).Infof("Queue <%s> of Task <%s/%s> is in subtree <%s> not over its deserved resources.",
+						job.Queue, reclaimee.Namespace, reclaimee.Name, branch.name)
+					continue
+				}
+				branchAllocated.Sub(reclaimee.Resreq)
 			}
+			allocated.Sub(reclaimee.Resreq)
+			victims = append(victims, reclaimee)
 		}
 		klog.V(4).Infof("Victims from proportion plugins are %+v", victims)
 		return victims, util.Permit
@@ -292,28 +276,53 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	ssn.AddOverusedFn(pp.Name(), func(obj interface{}) bool {
 		queue := obj.(*api.QueueInfo)
 		attr := pp.queueOpts[queue.UID]
+		// Fill queues have no deserved share, they are guarded by the idle resource in allocatable instead.
+		if attr.fill {
+			return false
+		}
 
-		overused := attr.deserved.LessEqual(attr.allocated, api.Zero)
+		overused := attr.deserved.Compare(attr.allocated, zeroDimensions).AllLessEqual()
 		metrics.UpdateQueueOverused(attr.name, overused)
 		if overused {
 			klog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>, share <%v>",
 				queue.Name, attr.deserved, attr.allocated, attr.share)
+			return true
 		}
 
-		return overused
+		// A queue is overused if any of its ancestors is.
+		for ancestor := attr.parent; ancestor != nil; ancestor = ancestor.parent {
+			if ancestor.deserved.Compare(ancestor.allocated, zeroDimensions).AllLessEqual() {
+				klog.V(3).Infof("Parent queue <%v> of queue <%v>: deserved <%v>, allocated <%v>, share <%v>",
+					ancestor.name, queue.Name, ancestor.deserved, ancestor.allocated, ancestor.share)
+				return true
+			}
+		}
+		return false
 	})
 
 	ssn.AddAllocatableFn(pp.Name(), func(queue *api.QueueInfo, candidate *api.TaskInfo) bool {
 		attr := pp.queueOpts[queue.UID]
+		if attr.fill {
+			return pp.fillAllocatable(attr, candidate)
+		}
 
-		free, _ := attr.deserved.Diff(attr.allocated, api.Zero)
-		allocatable := candidate.Resreq.LessEqual(free, api.Zero)
-		if !allocatable {
-			klog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>; Candidate <%v>: resource request <%v>",
-				queue.Name, attr.deserved, attr.allocated, candidate.Name, candidate.Resreq)
+		// Only the jobs of the queues at the bottom of the hierarchy are allocated.
+		if len(attr.children) != 0 {
+			klog.V(3).Infof("Queue <%v> has child queues; Candidate <%v> is not allocated", queue.Name, candidate.Name)
+			return false
 		}
 
-		return allocatable
+		// The candidate must fit in the deserved resources of the queue and of all its ancestors.
+		for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+			free, _ := ancestor.deserved.Diff(ancestor.allocated, api.Zero)
+			if !candidate.Resreq.Compare(free, zeroDimensions).AllLessEqual() {
+				klog.V(3).Infof("Queue <%v>: deserved <%v>, allocated <%v>; Candidate <%v>: resource request <%v>",
+					ancestor.name, ancestor.deserved, ancestor.allocated, candidate.Name, candidate.Resreq)
+				return false
+			}
+		}
+
+		return true
 	})
 
 	ssn.AddJobEnqueueableFn(pp.Name(), func(obj interface{}) int {
@@ -321,6 +330,11 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		queueID := job.Queue
 		attr := pp.queueOpts[queueID]
 		queue := ssn.Queues[queueID]
+		if len(attr.children) != 0 {
+			ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType),
+				fmt.Sprintf("queue %s has child queues, jobs must be submitted to its descendants", queue.Name))
+			return util.Reject
+		}
 		// If no capability is set, always enqueue the job.
 		if attr.realCapability == nil {
 			klog.V(4).Infof("Capability of queue <%s> was not set, allow job <%s/%s> to Inqueue.",
@@ -332,28 +346,32 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 			klog.V(4).Infof("job %s MinResources is null.", job.Name)
 			return util.Permit
 		}
-		minReq := job.GetMinResources()
-
-		klog.V(5).Infof("job %s min resource <%s>, queue %s capability <%s> allocated <%s> inqueue <%s> elastic <%s>",
-			job.Name, minReq.String(), queue.Name, attr.realCapability.String(), attr.allocated.String(), attr.inqueue.String(), attr.elastic.String())
-		// The queue resource quota limit has not reached
-		r := minReq.Add(attr.allocated).Add(attr.inqueue).Sub(attr.elastic)
-		rr := attr.realCapability.Clone()
-
-		for name := range rr.ScalarResources {
-			if _, ok := r.ScalarResources[name]; !ok {
-				delete(rr.ScalarResources, name)
+		// The quota limits of the queue and of all its ancestors have not reached.
+		for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+			minReq := job.GetMinResources()
+
+			klog.V(5).Infof("job %s min resource <%s>, queue %s capability <%s> allocated <%s> inqueue <%s> elastic <%s>",
+				job.Name, minReq.String(), ancestor.name, ancestor.realCapability.String(), ancestor.allocated.String(), ancestor.inqueue.String(), ancestor.elastic.String())
+			// The queue resource quota limit has not reached
+			// Dimensions not requested are treated as zero and dimensions not limited by capability as infinity.
+			r := minReq.Add(ancestor.allocated).Add(ancestor.inqueue).Sub(ancestor.elastic)
+			result := r.Compare(ancestor.realCapability, api.NewDimensionSet(api.Zero, api.Infinity))
+
+			inqueue := result.AllLessEqual()
+			klog.V(5).Infof("job %s inqueue %v", job.Name, inqueue)
+			if !inqueue {
+				message := fmt.Sprintf("queue resource quota insufficient: %v", result.Greater())
+				if ancestor != attr {
+					message = fmt.Sprintf("parent queue %s resource quota insufficient: %v", ancestor.name, result.Greater())
+				}
+				ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), message)
+				return util.Reject
 			}
 		}
-
-		inqueue := r.LessEqual(rr, api.Infinity)
-		klog.V(5).Infof("job %s inqueue %v", job.Name, inqueue)
-		if inqueue {
-			attr.inqueue.Add(job.GetMinResources())
-			return util.Permit
+		for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+			ancestor.inqueue.Add(job.GetMinResources())
 		}
-		ssn.RecordPodGroupEvent(job.PodGroup, v1.EventTypeNormal, string(scheduling.PodGroupUnschedulableType), "queue resource quota insufficient")
-		return util.Reject
+		return util.Permit
 	})
 
 	// Register event handlers.
@@ -361,10 +379,12 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		AllocateFunc: func(event *framework.Event) {
 			job := ssn.Jobs[event.Task.Job]
 			attr := pp.queueOpts[job.Queue]
-			attr.allocated.Add(event.Task.Resreq)
-			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+				ancestor.allocated.Add(event.Task.Resreq)
+				metrics.UpdateQueueAllocated(ancestor.name, ancestor.allocated.MilliCPU, ancestor.allocated.Memory)
 
-			pp.updateShare(attr)
+				pp.updateShare(ancestor)
+			}
 
 			klog.V(4).Infof("Proportion AllocateFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
@@ -372,10 +392,12 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		DeallocateFunc: func(event *framework.Event) {
 			job := ssn.Jobs[event.Task.Job]
 			attr := pp.queueOpts[job.Queue]
-			attr.allocated.Sub(event.Task.Resreq)
-			metrics.UpdateQueueAllocated(attr.name, attr.allocated.MilliCPU, attr.allocated.Memory)
+			for ancestor := attr; ancestor != nil; ancestor = ancestor.parent {
+				ancestor.allocated.Sub(event.Task.Resreq)
+				metrics.UpdateQueueAllocated(ancestor.name, ancestor.allocated.MilliCPU, ancestor.allocated.Memory)
 
-			pp.updateShare(attr)
+				pp.updateShare(ancestor)
+			}
 
 			klog.V(4).Infof("Proportion EvictFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
@@ -383,17 +405,154 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 	})
 }
 
+// divide divides the remaining resource among the queues by their weights, up to their demand and real capability
+// and at least their guarantee, then divides the deserved resource of each parent queue among its children.
+func (pp *proportionPlugin) divide(remaining *api.Resource, attrs []*queueAttr) {
+	meet := map[api.QueueID]struct{}{}
+	// Fill queues never accrue deserved share.
+	for _, attr := range attrs {
+		if attr.fill {
+			meet[attr.queueID] = struct{}{}
+		}
+	}
+	for {
+		totalWeight := int32(0)
+		for _, attr := range attrs {
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+			totalWeight += attr.weight
+		}
+
+		// If no queues, break
+		if totalWeight == 0 {
+			klog.V(4).Infof("Exiting when total weight is 0")
+			break
+		}
+
+		oldRemaining := remaining.Clone()
+		// Calculates the deserved of each Queue.
+		// increasedDeserved is the increased value for attr.deserved of processed queues
+		// decreasedDeserved is the decreased value for attr.deserved of processed queues
+		increasedDeserved := api.EmptyResource()
+		decreasedDeserved := api.EmptyResource()
+		for _, attr := range attrs {
+			klog.V(4).Infof("Considering Queue <%s>: weight <%d>, total weight <%d>.",
+				attr.name, attr.weight, totalWeight)
+			if _, found := meet[attr.queueID]; found {
+				continue
+			}
+
+			oldDeserved := attr.deserved.Clone()
+			attr.deserved.Add(remaining.Clone().Multi(float64(attr.weight) / float64(totalWeight)))
+
+			if attr.realCapability != nil {
+				attr.deserved.MinDimensionResource(attr.realCapability, api.Infinity)
+			}
+			attr.deserved.MinDimensionResource(attr.demand, api.Zero)
+
+			attr.deserved = helpers.Max(attr.deserved, attr.guarantee)
+			pp.updateShare(attr)
+			klog.V(4).Infof("Format queue <%s> deserved resource to <%v>", attr.name, attr.deserved)
+
+			if attr.demand.Compare(attr.deserved, zeroDimensions).AllLessEqual() {
+				meet[attr.queueID] = struct{}{}
+				klog.V(4).Infof("queue <%s> is meet", attr.name)
+			} else if reflect.DeepEqual(attr.deserved, oldDeserved) {
+				meet[attr.queueID] = struct{}{}
+				klog.V(4).Infof("queue <%s> is meet cause of the capability", attr.name)
+			}
+
+			klog.V(4).Infof("The attributes of queue <%s> in proportion: deserved <%v>, realCapability <%v>, allocate <%v>, request <%v>, elastic <%v>, share <%0.2f>",
+				attr.name, attr.deserved, attr.realCapability, attr.allocated, attr.request, attr.elastic, attr.share)
+
+			increased, decreased := attr.deserved.Diff(oldDeserved, api.Zero)
+			increasedDeserved.Add(increased)
+			decreasedDeserved.Add(decreased)
+
+			// Record metrics
+			metrics.UpdateQueueDeserved(attr.name, attr.deserved.MilliCPU, attr.deserved.Memory)
+		}
+
+		// The guarantee of a queue may raise its deserved resources over its share of the remaining resource, e.g.
+		// GPUs guaranteed to a queue, so the remaining resource is floored at zero in every dimension.
+		remaining, _ = remaining.Add(decreasedDeserved).Diff(increasedDeserved, api.Zero)
+		klog.V(4).Infof("Remaining resource is  <%s>", remaining)
+		if remaining.IsEmpty() || reflect.DeepEqual(remaining, oldRemaining) {
+			klog.V(4).Infof("Exiting when remaining is empty or no queue has more resource request:  <%v>", remaining)
+			break
+		}
+	}
+
+	for _, attr := range attrs {
+		if len(attr.children) != 0 {
+			pp.divide(attr.deserved.Clone(), attr.children)
+		}
+	}
+}
+
 func (pp *proportionPlugin) OnSessionClose(ssn *framework.Session) {
 	pp.totalResource = nil
 	pp.totalGuarantee = nil
 	pp.queueOpts = nil
 }
 
+// newQueueAttr returns the attributes of the queue without any job.
+func (pp *proportionPlugin) newQueueAttr(queue *api.QueueInfo) *queueAttr {
+	attr := &queueAttr{
+		queueID: queue.UID,
+		name:    queue.Name,
+		weight:  queue.Weight,
+		fill:    queue.Fill,
+
+		deserved:  api.EmptyResource(),
+		allocated: api.EmptyResource(),
+		request:   api.EmptyResource(),
+		elastic:   api.EmptyResource(),
+		inqueue:   api.EmptyResource(),
+		guarantee: api.EmptyResource(),
+	}
+	if len(queue.Queue.Spec.Capability) != 0 {
+		attr.capability = api.NewResource(queue.Queue.Spec.Capability)
+		if attr.capability.MilliCPU <= 0 {
+			attr.capability.MilliCPU = math.MaxFloat64
+		}
+		if attr.capability.Memory <= 0 {
+			attr.capability.Memory = math.MaxFloat64
+		}
+	}
+	if len(queue.Queue.Spec.Guarantee.Resource) != 0 && !queue.Fill {
+		attr.guarantee = api.NewResource(queue.Queue.Spec.Guarantee.Resource)
+	}
+	// The guarantee of other queues is left out of every resource of the cluster, including extended resources,
+	// and resources not limited by the capability are only limited by the cluster.
+	realCapability, _ := pp.totalResource.Diff(pp.totalGuarantee, api.Zero)
+	realCapability.Add(attr.guarantee)
+	if attr.capability != nil {
+		realCapability.MinDimensionResource(attr.capability, api.Infinity)
+	}
+	attr.realCapability = realCapability
+	return attr
+}
+
+// reclaimDimensions returns the dimensions requested by both reclaimer and reclaimee, all dimensions are compared
+// if there is none.
+func reclaimDimensions(reclaimer, reclaimee *api.TaskInfo) api.DimensionSet {
+	var names []v1.ResourceName
+	for _, name := range reclaimer.Resreq.ResourceNames() {
+		if reclaimee.Resreq.Get(name) >= api.GetMinResource() {
+			names = append(names, name)
+		}
+	}
+	return api.NewDimensionSet(api.Zero, api.Zero, names...)
+}
+
 func (pp *proportionPlugin) updateShare(attr *queueAttr) {
 	res := float64(0)
 
 	// TODO(k82cn): how to handle fragment issues?
-	for _, rn := range attr.deserved.ResourceNames() {
+	// The resources allocated but not deserved, e.g. GPUs guaranteed to other queues, count as fully shared.
+	for _, rn := range append(attr.deserved.ResourceNames(), attr.allocated.ResourceNames()...) {
 		share := helpers.Share(attr.allocated.Get(rn), attr.deserved.Get(rn))
 		if share > res {
 			res = share
@@ -403,3 +562,14 @@ func (pp *proportionPlugin) updateShare(attr *queueAttr) {
 	attr.share = res
 	metrics.UpdateQueueShare(attr.name, attr.share)
 }
+
+// allJobs returns the jobs of the session and of other profiles.
+func allJobs(ssn *framework.Session) []*api.JobInfo {
+	jobs := make([]*api.JobInfo, 0, len(ssn.Jobs)+len(ssn.OtherProfileJobs()))
+	for _, jobsOfProfiles := range []map[api.JobID]*api.JobInfo{ssn.Jobs, ssn.OtherProfileJobs()} {
+		for _, job := range jobsOfProfiles {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
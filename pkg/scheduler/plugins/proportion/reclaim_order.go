@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/api/helpers"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// ReclaimOrder is the key of argument which defines in which order the
+// tasks of borrowing queues are reclaimed.
+const ReclaimOrder = "proportion.reclaimOrder"
+
+const (
+	// ReclaimOrderNone keeps the order of reclaimees given by the reclaim action.
+	ReclaimOrderNone = "none"
+	// ReclaimOrderMostOverGuarantee reclaims tasks of the queue exceeding its guarantee the most first.
+	ReclaimOrderMostOverGuarantee = "mostOverGuarantee"
+	// ReclaimOrderLowestPriority reclaims tasks of the lowest priority job first.
+	ReclaimOrderLowestPriority = "lowestPriority"
+	// ReclaimOrderLIFO reclaims the latest allocated tasks first.
+	ReclaimOrderLIFO = "lifo"
+)
+
+func getReclaimOrder(arguments framework.Arguments) string {
+	order := ReclaimOrderNone
+	arguments.GetString(&order, ReclaimOrder)
+	switch order {
+	case ReclaimOrderNone, ReclaimOrderMostOverGuarantee, ReclaimOrderLowestPriority, ReclaimOrderLIFO:
+		return order
+	default:
+		klog.Warningf("Invalid %s %s, use default %s", ReclaimOrder, order, ReclaimOrderNone)
+		return ReclaimOrderNone
+	}
+}
+
+// sortReclaimees returns a copy of reclaimees sorted according to the reclaim order of the plugin,
+// the tasks of fill queues are always reclaimed first. The slice of the caller is not reordered.
+func (pp *proportionPlugin) sortReclaimees(ssn *framework.Session, reclaimees []*api.TaskInfo) []*api.TaskInfo {
+	var less func(l, r *api.TaskInfo) bool
+	switch pp.reclaimOrder {
+	case ReclaimOrderMostOverGuarantee:
+		excess := map[api.QueueID]float64{}
+		for _, reclaimee := range reclaimees {
+			queue := ssn.Jobs[reclaimee.Job].Queue
+			if _, found := excess[queue]; !found {
+				excess[queue] = pp.overGuarantee(pp.queueOpts[queue])
+			}
+		}
+		less = func(l, r *api.TaskInfo) bool {
+			return excess[ssn.Jobs[l.Job].Queue] > excess[ssn.Jobs[r.Job].Queue]
+		}
+	case ReclaimOrderLowestPriority:
+		less = func(l, r *api.TaskInfo) bool {
+			lp, rp := ssn.Jobs[l.Job].Priority, ssn.Jobs[r.Job].Priority
+			if lp != rp {
+				return lp < rp
+			}
+			return l.Priority < r.Priority
+		}
+	case ReclaimOrderLIFO:
+		less = func(l, r *api.TaskInfo) bool {
+			return allocationTime(l).After(allocationTime(r))
+		}
+	}
+
+	sorted := make([]*api.TaskInfo, len(reclaimees))
+	copy(sorted, reclaimees)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		lf, rf := pp.isFillTask(ssn.Jobs, sorted[i]), pp.isFillTask(ssn.Jobs, sorted[j])
+		if lf != rf {
+			return lf
+		}
+		return less != nil && less(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// overGuarantee returns the dominant share of resource allocated to the queue beyond its guarantee.
+func (pp *proportionPlugin) overGuarantee(attr *queueAttr) float64 {
+	res := float64(0)
+	for _, rn := range attr.allocated.ResourceNames() {
+		over := attr.allocated.Get(rn) - attr.guarantee.Get(rn)
+		if over <= 0 {
+			continue
+		}
+		if share := helpers.Share(over, pp.totalResource.Get(rn)); share > res {
+			res = share
+		}
+	}
+	return res
+}
+
+// allocationTime returns when the task was allocated, approximated by the start time of its pod.
+func allocationTime(task *api.TaskInfo) time.Time {
+	if task.Pod == nil {
+		return time.Time{}
+	}
+	if task.Pod.Status.StartTime != nil {
+		return task.Pod.Status.StartTime.Time
+	}
+	return task.Pod.CreationTimestamp.Time
+}
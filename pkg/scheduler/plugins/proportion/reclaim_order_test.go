@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestSortReclaimees(t *testing.T) {
+	now := time.Now()
+	newTask := func(name string, job api.JobID, priority int32, started time.Time) *api.TaskInfo {
+		return &api.TaskInfo{
+			Name:     name,
+			Job:      job,
+			Priority: priority,
+			Pod: &v1.Pod{
+				Status: v1.PodStatus{StartTime: &metav1.Time{Time: started}},
+			},
+		}
+	}
+
+	ssn := &framework.Session{
+		Jobs: map[api.JobID]*api.JobInfo{
+			"j1": {UID: "j1", Queue: "q1", Priority: 10},
+			"j2": {UID: "j2", Queue: "q2", Priority: 1},
+			"j3": {UID: "j3", Queue: "q2", Priority: 5},
+		},
+	}
+	pp := &proportionPlugin{
+		totalResource: &api.Resource{MilliCPU: 10000, Memory: 10000},
+		queueOpts: map[api.QueueID]*queueAttr{
+			// q1 exceeds its guarantee by 40% of cluster cpu
+			"q1": {allocated: &api.Resource{MilliCPU: 6000, Memory: 1000}, guarantee: &api.Resource{MilliCPU: 2000}},
+			// q2 exceeds its guarantee by 20% of cluster memory
+			"q2": {allocated: &api.Resource{MilliCPU: 1000, Memory: 3000}, guarantee: &api.Resource{MilliCPU: 2000, Memory: 1000}},
+		},
+	}
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{
+			order:    ReclaimOrderNone,
+			expected: []string{"t1", "t2", "t3", "t4"},
+		},
+		{
+			order:    ReclaimOrderMostOverGuarantee,
+			expected: []string{"t4", "t1", "t2", "t3"},
+		},
+		{
+			order:    ReclaimOrderLowestPriority,
+			expected: []string{"t3", "t2", "t1", "t4"},
+		},
+		{
+			order:    ReclaimOrderLIFO,
+			expected: []string{"t4", "t3", "t2", "t1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.order, func(t *testing.T) {
+			reclaimees := []*api.TaskInfo{
+				newTask("t1", "j2", 2, now.Add(-4*time.Minute)),
+				newTask("t2", "j2", 1, now.Add(-3*time.Minute)),
+				newTask("t3", "j2", 0, now.Add(-2*time.Minute)),
+				newTask("t4", "j1", 0, now.Add(-1*time.Minute)),
+			}
+			pp.reclaimOrder = test.order
+			sorted := pp.sortReclaimees(ssn, reclaimees)
+
+			var names []string
+			for _, reclaimee := range sorted {
+				names = append(names, reclaimee.Name)
+			}
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, names)
+			}
+			if reclaimees[0].Name != "t1" || reclaimees[3].Name != "t4" {
+				t.Errorf("expected reclaimees of caller not reordered, got %v", reclaimees)
+			}
+		})
+	}
+}
+
+func TestGetReclaimOrder(t *testing.T) {
+	tests := []struct {
+		arguments framework.Arguments
+		expected  string
+	}{
+		{
+			arguments: framework.Arguments{},
+			expected:  ReclaimOrderNone,
+		},
+		{
+			arguments: framework.Arguments{ReclaimOrder: ReclaimOrderLIFO},
+			expected:  ReclaimOrderLIFO,
+		},
+		{
+			arguments: framework.Arguments{ReclaimOrder: "random"},
+			expected:  ReclaimOrderNone,
+		},
+	}
+
+	for _, test := range tests {
+		if order := getReclaimOrder(test.arguments); order != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, order)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// ExcludeUnschedulable is the key of argument excluding the unschedulable, i.e. cordoned, nodes from the total
+	// resource queues deserve their shares of, false by default.
+	ExcludeUnschedulable = "proportion.excludeUnschedulable"
+	// ExcludeTaints is the key of argument with the comma separated taints excluding the nodes tainted by them from
+	// the total resource, each one is `key`, `key=value` or either with `:Effect`, e.g. `maintenance:NoSchedule`.
+	ExcludeTaints = "proportion.excludeTaints"
+)
+
+// totalResourcePolicy selects the nodes whose allocatable is counted into the total resource.
+type totalResourcePolicy struct {
+	excludeUnschedulable bool
+	excludeTaints        []v1.Taint
+}
+
+func newTotalResourcePolicy(arguments framework.Arguments) totalResourcePolicy {
+	policy := totalResourcePolicy{}
+	arguments.GetBool(&policy.excludeUnschedulable, ExcludeUnschedulable)
+
+	var taints string
+	arguments.GetString(&taints, ExcludeTaints)
+	for _, taint := range strings.Split(taints, ",") {
+		taint = strings.TrimSpace(taint)
+		if taint == "" {
+			continue
+		}
+		keyValue, effect, _ := strings.Cut(taint, ":")
+		key, value, _ := strings.Cut(keyValue, "=")
+		switch v1.TaintEffect(effect) {
+		case "", v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+		default:
+			klog.Warningf("Invalid effect of taint %q in %s, it is ignored", taint, ExcludeTaints)
+			continue
+		}
+		policy.excludeTaints = append(policy.excludeTaints, v1.Taint{Key: key, Value: value, Effect: v1.TaintEffect(effect)})
+	}
+	return policy
+}
+
+// totalResource returns the total allocatable of the nodes of the session not excluded by the policy.
+func (p totalResourcePolicy) totalResource(ssn *framework.Session) *api.Resource {
+	if !p.excludeUnschedulable && len(p.excludeTaints) == 0 {
+		return ssn.TotalResource.Clone()
+	}
+	total := api.EmptyResource()
+	for _, node := range ssn.Nodes {
+		if p.excluded(node) {
+			klog.V(4).Infof("Node <%s> is excluded from the total resource of %s", node.Name, PluginName)
+			continue
+		}
+		total.Add(node.Allocatable)
+	}
+	return total
+}
+
+// excluded returns whether the node is unschedulable or tainted by a taint of the policy.
+func (p totalResourcePolicy) excluded(node *api.NodeInfo) bool {
+	if node.Node == nil {
+		return false
+	}
+	if p.excludeUnschedulable && node.Node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Node.Spec.Taints {
+		for _, excluded := range p.excludeTaints {
+			if taint.Key == excluded.Key && (excluded.Value == "" || taint.Value == excluded.Value) &&
+				(excluded.Effect == "" || taint.Effect == excluded.Effect) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proportion
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestTotalResource(t *testing.T) {
+	cordoned := util.BuildNode("cordoned", util.BuildResourceList("4", "4G"), nil)
+	cordoned.Spec.Unschedulable = true
+	maintenance := util.BuildNode("maintenance", util.BuildResourceList("2", "2G"), nil)
+	maintenance.Spec.Taints = []v1.Taint{{Key: "maintenance", Value: "true", Effect: v1.TaintEffectNoSchedule}}
+	ssn := &framework.Session{
+		TotalResource: api.EmptyResource(),
+		Nodes:         map[string]*api.NodeInfo{},
+	}
+	for _, node := range []*v1.Node{util.BuildNode("n1", util.BuildResourceList("8", "8G"), nil), cordoned, maintenance} {
+		ssn.Nodes[node.Name] = api.NewNodeInfo(node)
+		ssn.TotalResource.Add(ssn.Nodes[node.Name].Allocatable)
+	}
+
+	tests := []struct {
+		name      string
+		arguments framework.Arguments
+		milliCPU  float64
+	}{
+		{name: "all nodes by default", arguments: framework.Arguments{}, milliCPU: 14000},
+		{name: "unschedulable nodes excluded", arguments: framework.Arguments{ExcludeUnschedulable: true}, milliCPU: 10000},
+		{name: "tainted nodes excluded", arguments: framework.Arguments{ExcludeTaints: "maintenance:NoSchedule"}, milliCPU: 12000},
+		{name: "taints of other values kept", arguments: framework.Arguments{ExcludeTaints: "maintenance=false"}, milliCPU: 14000},
+		{name: "taints of other effects kept", arguments: framework.Arguments{ExcludeTaints: "maintenance:NoExecute, gpu"}, milliCPU: 14000},
+		{name: "both excluded", arguments: framework.Arguments{ExcludeUnschedulable: true, ExcludeTaints: "maintenance=true"}, milliCPU: 8000},
+		{name: "invalid effect ignored", arguments: framework.Arguments{ExcludeTaints: "maintenance:Never"}, milliCPU: 14000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			total := newTotalResourcePolicy(test.arguments).totalResource(ssn)
+			if total.MilliCPU != test.milliCPU {
+				t.Errorf("expected total cpu %v, got %v", test.milliCPU, total.MilliCPU)
+			}
+		})
+	}
+	if ssn.TotalResource.MilliCPU != 14000 {
+		t.Errorf("expected total resource of session unchanged, got %v", ssn.TotalResource)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// serviceName is the gRPC service implemented by remote plugins.
+	serviceName = "volcano.scheduler.RemotePlugin"
+	// codecName is the content subtype of the messages, which are encoded in JSON.
+	codecName = "json"
+)
+
+// OnSessionOpenRequest is sent when a session is opened. It is the delta from the state of session Base, i.e. only
+// the objects changed since Base and the removed ones are sent, or the full state if Base is empty.
+type OnSessionOpenRequest struct {
+	Session       string                                   `json:"session"`
+	Base          string                                   `json:"base,omitempty"`
+	Arguments     map[string]interface{}                   `json:"arguments"`
+	Jobs          map[api.JobID]*api.JobInfo               `json:"jobs"`
+	Nodes         map[string]*api.NodeInfo                 `json:"nodes"`
+	Queues        map[api.QueueID]*api.QueueInfo           `json:"queues"`
+	NamespaceInfo map[api.NamespaceName]*api.NamespaceInfo `json:"namespaceInfo"`
+
+	RemovedJobs       []api.JobID         `json:"removedJobs,omitempty"`
+	RemovedNodes      []string            `json:"removedNodes,omitempty"`
+	RemovedQueues     []api.QueueID       `json:"removedQueues,omitempty"`
+	RemovedNamespaces []api.NamespaceName `json:"removedNamespaces,omitempty"`
+}
+
+// OnSessionOpenResponse is the reply of OnSessionOpen, Predicate and NodeOrder are only called if they are enabled.
+// Resync is replied if the remote plugin does not keep the state of the base session, e.g. it is restarted, so that
+// the full state is sent again.
+type OnSessionOpenResponse struct {
+	Predicate bool `json:"predicate"`
+	NodeOrder bool `json:"nodeOrder"`
+	Resync    bool `json:"resync"`
+}
+
+// OnSessionCloseRequest is sent when a session is closed.
+type OnSessionCloseRequest struct {
+	Session string `json:"session"`
+}
+
+// OnSessionCloseResponse is the reply of OnSessionClose.
+type OnSessionCloseResponse struct{}
+
+// PredicateRequest checks whether the task fits each of the nodes, given by name. Updated are the nodes changed in
+// the session since they are sent last, e.g. by the tasks allocated.
+type PredicateRequest struct {
+	Session string          `json:"session"`
+	Task    *api.TaskInfo   `json:"task"`
+	Nodes   []string        `json:"nodes"`
+	Updated []*api.NodeInfo `json:"updated,omitempty"`
+}
+
+// PredicateResponse is the reply of Predicate, the statuses of each node by name. The task fits a node if no status
+// of the node is unschedulable.
+type PredicateResponse struct {
+	Status map[string][]*api.Status `json:"status"`
+}
+
+// NodeOrderRequest scores the nodes, given by name, for the task. Updated are the nodes changed in the session since
+// they are sent last.
+type NodeOrderRequest struct {
+	Session string          `json:"session"`
+	Task    *api.TaskInfo   `json:"task"`
+	Nodes   []string        `json:"nodes"`
+	Updated []*api.NodeInfo `json:"updated,omitempty"`
+}
+
+// NodeOrderResponse is the reply of NodeOrder, the score of each node by name.
+type NodeOrderResponse struct {
+	NodeScore map[string]float64 `json:"nodeScore"`
+}
+
+// Server is implemented by remote plugins and served by RegisterServer.
+type Server interface {
+	OnSessionOpen(ctx context.Context, req *OnSessionOpenRequest) (*OnSessionOpenResponse, error)
+	OnSessionClose(ctx context.Context, req *OnSessionCloseRequest) (*OnSessionCloseResponse, error)
+	Predicate(ctx context.Context, req *PredicateRequest) (*PredicateResponse, error)
+	NodeOrder(ctx context.Context, req *NodeOrderRequest) (*NodeOrderResponse, error)
+}
+
+// SessionState is the state of the session kept by a remote plugin, built from the deltas sent by the scheduler.
+type SessionState struct {
+	Session       string
+	Jobs          map[api.JobID]*api.JobInfo
+	Nodes         map[string]*api.NodeInfo
+	Queues        map[api.QueueID]*api.QueueInfo
+	NamespaceInfo map[api.NamespaceName]*api.NamespaceInfo
+}
+
+// Open applies the state sent by OnSessionOpen. It returns false if the state is the delta of another session than
+// the one kept, the remote plugin replies Resync then.
+func (s *SessionState) Open(req *OnSessionOpenRequest) bool {
+	if len(req.Base) == 0 {
+		*s = SessionState{
+			Jobs:          map[api.JobID]*api.JobInfo{},
+			Nodes:         map[string]*api.NodeInfo{},
+			Queues:        map[api.QueueID]*api.QueueInfo{},
+			NamespaceInfo: map[api.NamespaceName]*api.NamespaceInfo{},
+		}
+	} else if req.Base != s.Session {
+		return false
+	}
+	s.Session = req.Session
+
+	for id, job := range req.Jobs {
+		s.Jobs[id] = job
+	}
+	for _, id := range req.RemovedJobs {
+		delete(s.Jobs, id)
+	}
+	for name, node := range req.Nodes {
+		s.Nodes[name] = node
+	}
+	for _, name := range req.RemovedNodes {
+		delete(s.Nodes, name)
+	}
+	for id, queue := range req.Queues {
+		s.Queues[id] = queue
+	}
+	for _, id := range req.RemovedQueues {
+		delete(s.Queues, id)
+	}
+	for name, namespace := range req.NamespaceInfo {
+		s.NamespaceInfo[name] = namespace
+	}
+	for _, name := range req.RemovedNamespaces {
+		delete(s.NamespaceInfo, name)
+	}
+	return true
+}
+
+// Update applies the nodes updated in the session, sent by Predicate and NodeOrder.
+func (s *SessionState) Update(nodes []*api.NodeInfo) {
+	if s.Nodes == nil {
+		s.Nodes = map[string]*api.NodeInfo{}
+	}
+	for _, node := range nodes {
+		s.Nodes[node.Name] = node
+	}
+}
+
+// RegisterServer registers the remote plugin to the gRPC server.
+func RegisterServer(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+func methodHandler(method string, newRequest func() interface{},
+	call func(srv Server, ctx context.Context, req interface{}) (interface{}, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newRequest()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(Server), ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(method)}
+			return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(Server), ctx, req)
+			})
+		},
+	}
+}
+
+func fullMethod(method string) string {
+	return "/" + serviceName + "/" + method
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		methodHandler("OnSessionOpen", func() interface{} { return &OnSessionOpenRequest{} },
+			func(srv Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.OnSessionOpen(ctx, req.(*OnSessionOpenRequest))
+			}),
+		methodHandler("OnSessionClose", func() interface{} { return &OnSessionCloseRequest{} },
+			func(srv Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.OnSessionClose(ctx, req.(*OnSessionCloseRequest))
+			}),
+		methodHandler("Predicate", func() interface{} { return &PredicateRequest{} },
+			func(srv Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.Predicate(ctx, req.(*PredicateRequest))
+			}),
+		methodHandler("NodeOrder", func() interface{} { return &NodeOrderRequest{} },
+			func(srv Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.NodeOrder(ctx, req.(*NodeOrderRequest))
+			}),
+	},
+	Streams: []grpc.StreamDesc{},
+}
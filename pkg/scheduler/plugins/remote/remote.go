@@ -0,0 +1,486 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// RemoteTimeout is the timeout of the calls to the remote plugin
+	RemoteTimeout = "remote.timeout"
+	// RemoteIgnorable indicates whether the errors of the remote plugin are ignored
+	RemoteIgnorable = "remote.ignorable"
+	// RemoteTLSCAFile is the CA bundle verifying the certificate of the remote plugin, TLS is enabled if it is set
+	RemoteTLSCAFile = "remote.tlsCAFile"
+	// RemoteTLSCertFile and RemoteTLSKeyFile are the client certificate of the scheduler, if any
+	RemoteTLSCertFile = "remote.tlsCertFile"
+	RemoteTLSKeyFile  = "remote.tlsKeyFile"
+	// RemoteTLSServerName is the name verified in the certificate of the remote plugin
+	RemoteTLSServerName = "remote.tlsServerName"
+)
+
+var (
+	connMutex sync.Mutex
+	// conns caches the connections by socket and TLS configuration, they reconnect when the remote plugin is restarted
+	conns = map[tlsConfig]*grpc.ClientConn{}
+
+	stateMutex sync.Mutex
+	// states are the states sent to the remote plugins by name, the next session only sends the delta from them
+	states = map[string]*sentState{}
+)
+
+// tlsConfig is the socket and the TLS configuration of a remote plugin.
+type tlsConfig struct {
+	socket     string
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+}
+
+// sentState is the state of a session sent to a remote plugin, by the generations of jobs, nodes and queues, which
+// are renewed on each change of them in cache, and by the hashes of the encoded namespaces, which are few and built
+// anew by each snapshot.
+type sentState struct {
+	session    string
+	jobs       map[string]uint64
+	nodes      map[string]uint64
+	queues     map[string]uint64
+	namespaces map[string]uint64
+}
+
+type remotePlugin struct {
+	name      string
+	config    tlsConfig
+	arguments framework.Arguments
+	timeout   time.Duration
+	ignorable bool
+	session   string
+
+	mutex sync.Mutex
+	// generation is increased when a node is changed in the session, so the predicates are called again
+	generation int
+	// updated are the nodes changed in the session since they are sent last
+	updated map[string]bool
+	// predicates caches the statuses of the nodes by task for the generation of the nodes
+	predicates map[api.TaskID]*taskPredicates
+}
+
+// taskPredicates are the statuses of the nodes for a task, by node name.
+type taskPredicates struct {
+	generation int
+	status     map[string][]*api.Status
+	err        error
+}
+
+// RegisterPlugins registers the remote plugins given by `name=socket`, each one is a plugin of the name in the
+// scheduler configuration served by the gRPC server on the unix socket.
+func RegisterPlugins(plugins []string) error {
+	for _, plugin := range plugins {
+		parts := strings.SplitN(plugin, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid remote plugin %q, expected name=socket", plugin)
+		}
+		framework.RegisterPluginBuilder(parts[0], NewBuilder(parts[0], parts[1]))
+		klog.V(4).Infof("Remote plugin %s registered on socket %s", parts[0], parts[1])
+	}
+	return nil
+}
+
+// NewBuilder returns the builder of the remote plugin served on the unix socket.
+func NewBuilder(name, socket string) framework.PluginBuilder {
+	return func(arguments framework.Arguments) framework.Plugin {
+		rp := &remotePlugin{
+			name:       name,
+			config:     tlsConfig{socket: socket},
+			arguments:  arguments,
+			timeout:    time.Second,
+			updated:    map[string]bool{},
+			predicates: map[api.TaskID]*taskPredicates{},
+		}
+		if timeout, _ := arguments[RemoteTimeout].(string); timeout != "" {
+			if timeoutDuration, err := time.ParseDuration(timeout); err == nil {
+				rp.timeout = timeoutDuration
+			}
+		}
+		arguments.GetBool(&rp.ignorable, RemoteIgnorable)
+		rp.config.caFile, _ = arguments[RemoteTLSCAFile].(string)
+		rp.config.certFile, _ = arguments[RemoteTLSCertFile].(string)
+		rp.config.keyFile, _ = arguments[RemoteTLSKeyFile].(string)
+		rp.config.serverName, _ = arguments[RemoteTLSServerName].(string)
+		return rp
+	}
+}
+
+func (rp *remotePlugin) Name() string {
+	return rp.name
+}
+
+func (rp *remotePlugin) OnSessionOpen(ssn *framework.Session) {
+	rp.session = string(ssn.UID)
+	resp, err := rp.open(ssn)
+	if err != nil {
+		klog.Warningf("OnSessionOpen of remote plugin %s failed with error %v", rp.name, err)
+		if rp.ignorable {
+			// Without the remote plugin, none of its functions is added to the session.
+			return
+		}
+		// The predicate of the remote plugin must not be skipped, so no node is allowed for tasks in the session.
+		ssn.AddPredicateFn(rp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+			return nil, fmt.Errorf("remote plugin %s is unavailable: %v", rp.name, err)
+		})
+		return
+	}
+
+	// The nodes changed in the session are sent along with the next call.
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			rp.nodeUpdated(event.Task.NodeName)
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			rp.nodeUpdated(event.Task.NodeName)
+		},
+	})
+
+	if resp.Predicate {
+		ssn.AddPredicateFn(rp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+			status, err := rp.predicate(ssn, task)
+			if err != nil {
+				if rp.ignorable {
+					return nil, nil
+				}
+				return nil, err
+			}
+			return status[node.Name], nil
+		})
+	}
+
+	if resp.NodeOrder {
+		ssn.AddBatchNodeOrderFn(rp.Name(), func(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
+			names := make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				names = append(names, node.Name)
+			}
+			resp := &NodeOrderResponse{}
+			rp.mutex.Lock()
+			err := rp.callUpdated(ssn, "NodeOrder", func(updated []*api.NodeInfo) interface{} {
+				return &NodeOrderRequest{Session: rp.session, Task: task, Nodes: names, Updated: updated}
+			}, resp)
+			rp.mutex.Unlock()
+			if err != nil {
+				klog.Warningf("NodeOrder of remote plugin %s failed with error %v", rp.name, err)
+
+				if rp.ignorable {
+					return nil, nil
+				}
+				return nil, err
+			}
+
+			return resp.NodeScore, nil
+		})
+	}
+}
+
+func (rp *remotePlugin) OnSessionClose(ssn *framework.Session) {
+	if err := rp.call("OnSessionClose", &OnSessionCloseRequest{Session: rp.session}, &OnSessionCloseResponse{}); err != nil {
+		klog.Warningf("OnSessionClose of remote plugin %s failed with error %v", rp.name, err)
+	}
+}
+
+// open sends the delta from the state sent in the previous session, or the full state if the remote plugin does not
+// keep it, e.g. it is restarted.
+func (rp *remotePlugin) open(ssn *framework.Session) (*OnSessionOpenResponse, error) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	previous := states[rp.name]
+	delete(states, rp.name)
+	req, current, err := rp.openRequest(ssn, previous)
+	if err != nil {
+		return nil, err
+	}
+	resp := &OnSessionOpenResponse{}
+	if err := rp.call("OnSessionOpen", req, resp); err != nil {
+		return nil, err
+	}
+	if resp.Resync && previous != nil {
+		klog.V(3).Infof("Remote plugin %s lost session %s, send the full state", rp.name, previous.session)
+		if req, current, err = rp.openRequest(ssn, nil); err != nil {
+			return nil, err
+		}
+		resp = &OnSessionOpenResponse{}
+		if err := rp.call("OnSessionOpen", req, resp); err != nil {
+			return nil, err
+		}
+	}
+	if resp.Resync {
+		return nil, fmt.Errorf("remote plugin %s does not keep the full state of session %s", rp.name, rp.session)
+	}
+	states[rp.name] = current
+	return resp, nil
+}
+
+// openRequest builds the delta of the session from previous, the full state if previous is nil.
+func (rp *remotePlugin) openRequest(ssn *framework.Session, previous *sentState) (*OnSessionOpenRequest, *sentState, error) {
+	if previous == nil {
+		previous = &sentState{}
+	}
+	req := &OnSessionOpenRequest{
+		Session:       rp.session,
+		Base:          previous.session,
+		Arguments:     rp.arguments,
+		Jobs:          map[api.JobID]*api.JobInfo{},
+		Nodes:         map[string]*api.NodeInfo{},
+		Queues:        map[api.QueueID]*api.QueueInfo{},
+		NamespaceInfo: map[api.NamespaceName]*api.NamespaceInfo{},
+	}
+	current := &sentState{session: rp.session}
+
+	jobs := make(map[string]uint64, len(ssn.Jobs))
+	for id, job := range ssn.Jobs {
+		jobs[string(id)] = job.Generation()
+	}
+	current.jobs = delta(previous.jobs, jobs, func(id string) {
+		req.Jobs[api.JobID(id)] = ssn.Jobs[api.JobID(id)]
+	}, func(id string) {
+		req.RemovedJobs = append(req.RemovedJobs, api.JobID(id))
+	})
+
+	nodes := make(map[string]uint64, len(ssn.Nodes))
+	for name, node := range ssn.Nodes {
+		nodes[name] = node.Generation()
+	}
+	current.nodes = delta(previous.nodes, nodes, func(name string) {
+		req.Nodes[name] = ssn.Nodes[name]
+	}, func(name string) {
+		req.RemovedNodes = append(req.RemovedNodes, name)
+	})
+
+	queues := make(map[string]uint64, len(ssn.Queues))
+	for id, queue := range ssn.Queues {
+		queues[string(id)] = queue.Generation()
+	}
+	current.queues = delta(previous.queues, queues, func(id string) {
+		req.Queues[api.QueueID(id)] = ssn.Queues[api.QueueID(id)]
+	}, func(id string) {
+		req.RemovedQueues = append(req.RemovedQueues, api.QueueID(id))
+	})
+
+	namespaces := make(map[string]uint64, len(ssn.NamespaceInfo))
+	for name, namespace := range ssn.NamespaceInfo {
+		data, err := json.Marshal(namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash := fnv.New64a()
+		hash.Write(data)
+		namespaces[string(name)] = hash.Sum64()
+	}
+	current.namespaces = delta(previous.namespaces, namespaces, func(name string) {
+		req.NamespaceInfo[api.NamespaceName(name)] = ssn.NamespaceInfo[api.NamespaceName(name)]
+	}, func(name string) {
+		req.RemovedNamespaces = append(req.RemovedNamespaces, api.NamespaceName(name))
+	})
+	return req, current, nil
+}
+
+// delta calls changed for the objects whose versions differ from the previous ones, and removed for the previous
+// objects not found anymore. It returns the versions of objects.
+func delta(previous, versions map[string]uint64, changed, removed func(key string)) map[string]uint64 {
+	for key, version := range versions {
+		if last, found := previous[key]; !found || last != version {
+			changed(key)
+		}
+	}
+	for key := range previous {
+		if _, found := versions[key]; !found {
+			removed(key)
+		}
+	}
+	return versions
+}
+
+// nodeUpdated marks the node changed in the session, it is sent along with the next call.
+func (rp *remotePlugin) nodeUpdated(name string) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	rp.generation++
+	rp.updated[name] = true
+}
+
+// predicate returns the statuses of all nodes for the task by a single call per task, which is cached until a node
+// is changed in the session.
+func (rp *remotePlugin) predicate(ssn *framework.Session, task *api.TaskInfo) (map[string][]*api.Status, error) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	if cached, found := rp.predicates[task.UID]; found && cached.generation == rp.generation {
+		return cached.status, cached.err
+	}
+
+	names := make([]string, 0, len(ssn.Nodes))
+	for name := range ssn.Nodes {
+		names = append(names, name)
+	}
+	resp := &PredicateResponse{}
+	err := rp.callUpdated(ssn, "Predicate", func(updated []*api.NodeInfo) interface{} {
+		return &PredicateRequest{Session: rp.session, Task: task, Nodes: names, Updated: updated}
+	}, resp)
+	if err != nil {
+		klog.Warningf("Predicate of remote plugin %s failed with error %v", rp.name, err)
+	}
+	rp.predicates[task.UID] = &taskPredicates{generation: rp.generation, status: resp.Status, err: err}
+	return resp.Status, err
+}
+
+// callUpdated calls the method with the request built with the nodes changed in the session since they are sent
+// last, it is called with mutex held.
+func (rp *remotePlugin) callUpdated(ssn *framework.Session, method string, request func(updated []*api.NodeInfo) interface{}, resp interface{}) error {
+	var updated []*api.NodeInfo
+	for name := range rp.updated {
+		if node, found := ssn.Nodes[name]; found {
+			updated = append(updated, node)
+		}
+	}
+	if err := rp.call(method, request(updated), resp); err != nil {
+		return err
+	}
+	if len(updated) != 0 {
+		rp.updated = map[string]bool{}
+		// The remote plugin keeps the nodes changed in the session, the next session sends them again.
+		stateMutex.Lock()
+		if state, found := states[rp.name]; found && state.session == rp.session {
+			for _, node := range updated {
+				delete(state.nodes, node.Name)
+			}
+		}
+		stateMutex.Unlock()
+	}
+	return nil
+}
+
+func (rp *remotePlugin) call(method string, req interface{}, resp interface{}) error {
+	conn, err := dial(rp.config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rp.timeout)
+	defer cancel()
+	return conn.Invoke(ctx, fullMethod(method), req, resp)
+}
+
+func dial(config tlsConfig) (*grpc.ClientConn, error) {
+	connMutex.Lock()
+	defer connMutex.Unlock()
+
+	if conn, found := conns[config]; found {
+		return conn, nil
+	}
+
+	creds, err := transportCredentials(config)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial("passthrough:///"+config.socket,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", addr)
+		}),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conns[config] = conn
+	return conn, nil
+}
+
+// transportCredentials returns the TLS credentials of the remote plugin if a CA bundle is configured, or the
+// credentials of a local unix socket otherwise, whose peers are only the processes sharing its volume.
+func transportCredentials(config tlsConfig) (credentials.TransportCredentials, error) {
+	if len(config.caFile) == 0 {
+		return unixSocketCredentials{}, nil
+	}
+
+	ca, err := os.ReadFile(config.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %s: %v", RemoteTLSCAFile, config.caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificate found in %s %s", RemoteTLSCAFile, config.caFile)
+	}
+	tlsConf := &tls.Config{RootCAs: pool, ServerName: config.serverName, MinVersion: tls.VersionTLS12}
+	if len(config.certFile) != 0 || len(config.keyFile) != 0 {
+		cert, err := tls.LoadX509KeyPair(config.certFile, config.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s and %s: %v", RemoteTLSCertFile, RemoteTLSKeyFile, err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsConf), nil
+}
+
+// unixSocketCredentials are the transport credentials of a local unix socket, which needs no handshake.
+type unixSocketCredentials struct{}
+
+type unixSocketAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (unixSocketAuthInfo) AuthType() string {
+	return "unix"
+}
+
+func (unixSocketCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, unixSocketAuthInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+}
+
+func (unixSocketCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, unixSocketAuthInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+}
+
+func (unixSocketCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "unix"}
+}
+
+func (unixSocketCredentials) Clone() credentials.TransportCredentials {
+	return unixSocketCredentials{}
+}
+
+func (unixSocketCredentials) OverrideServerName(string) error {
+	return nil
+}
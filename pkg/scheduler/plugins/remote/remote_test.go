@@ -0,0 +1,267 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// zoneServer only places tasks labeled with a zone on nodes of the zone, and prefers nodes with less tasks.
+type zoneServer struct {
+	opened    map[string]framework.Arguments
+	requests  []*OnSessionOpenRequest
+	closed    []string
+	nodeOrder bool
+	state     SessionState
+	// predicates counts the calls of Predicate
+	predicates int
+}
+
+func (zs *zoneServer) OnSessionOpen(ctx context.Context, req *OnSessionOpenRequest) (*OnSessionOpenResponse, error) {
+	zs.opened[req.Session] = req.Arguments
+	zs.requests = append(zs.requests, req)
+	if !zs.state.Open(req) {
+		return &OnSessionOpenResponse{Resync: true}, nil
+	}
+	return &OnSessionOpenResponse{Predicate: true, NodeOrder: zs.nodeOrder}, nil
+}
+
+func (zs *zoneServer) OnSessionClose(ctx context.Context, req *OnSessionCloseRequest) (*OnSessionCloseResponse, error) {
+	zs.closed = append(zs.closed, req.Session)
+	return &OnSessionCloseResponse{}, nil
+}
+
+func (zs *zoneServer) Predicate(ctx context.Context, req *PredicateRequest) (*PredicateResponse, error) {
+	zs.predicates++
+	zs.state.Update(req.Updated)
+	status := map[string][]*api.Status{}
+	for _, name := range req.Nodes {
+		node := zs.state.Nodes[name]
+		if zone, found := req.Task.Pod.Labels["zone"]; found && node.Node.Labels["zone"] != zone {
+			status[name] = []*api.Status{{
+				Code:   api.Unschedulable,
+				Reason: fmt.Sprintf("node %s is out of zone %s", name, zone),
+			}}
+		}
+	}
+	return &PredicateResponse{Status: status}, nil
+}
+
+func (zs *zoneServer) NodeOrder(ctx context.Context, req *NodeOrderRequest) (*NodeOrderResponse, error) {
+	zs.state.Update(req.Updated)
+	scores := map[string]float64{}
+	for _, name := range req.Nodes {
+		scores[name] = float64(10 - len(zs.state.Nodes[name].Tasks))
+	}
+	return &NodeOrderResponse{NodeScore: scores}, nil
+}
+
+func serve(t *testing.T, srv Server) string {
+	socket := filepath.Join(t.TempDir(), "zone.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", socket, err)
+	}
+	server := grpc.NewServer()
+	RegisterServer(server, srv)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return socket
+}
+
+func TestRemotePlugin(t *testing.T) {
+	zs := &zoneServer{opened: map[string]framework.Arguments{}, nodeOrder: true}
+	socket := serve(t, zs)
+
+	test := uthelper.TestCommonStruct{
+		Name:      "remote zone",
+		Plugins:   map[string]framework.PluginBuilder{"zone": NewBuilder("zone", socket)},
+		Arguments: map[string]framework.Arguments{"zone": {"zone.default": "a"}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("c1", "pg1", "q1", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{"zone": "a"}, map[string]string{}),
+			util.BuildPod("c1", "p2", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+		},
+		Nodes: []*v1.Node{
+			util.BuildNode("n1", util.BuildResourceList("4", "4G"), map[string]string{"zone": "a"}),
+			util.BuildNode("n2", util.BuildResourceList("4", "4G"), map[string]string{"zone": "b"}),
+		},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+	}
+
+	if err := test.CheckPredicates(map[string]map[string]bool{
+		"c1/p1": {"n1": true, "n2": false},
+		"c1/p2": {"n1": true, "n2": true},
+	}); err != nil {
+		t.Error(err)
+	}
+	// The nodes are checked by a single call per task.
+	if zs.predicates != 2 {
+		t.Errorf("expected 2 calls of Predicate, got %d", zs.predicates)
+	}
+
+	ssn := test.Open()
+	var task *api.TaskInfo
+	for _, job := range ssn.Jobs {
+		for _, jobTask := range job.Tasks {
+			if jobTask.Name == "p1" {
+				task = jobTask
+			}
+		}
+	}
+	scores, err := ssn.BatchNodeOrderFn(task, []*api.NodeInfo{ssn.Nodes["n1"], ssn.Nodes["n2"]})
+	if err != nil {
+		t.Fatalf("failed to score nodes: %v", err)
+	}
+	if scores["n1"] != 9 || scores["n2"] != 10 {
+		t.Errorf("expected scores of n1 9 and n2 10, got %v", scores)
+	}
+
+	session := string(ssn.UID)
+	test.Close()
+	if zs.opened[session]["zone.default"] != "a" {
+		t.Errorf("expected arguments of session %s sent to the remote plugin, got %v", session, zs.opened[session])
+	}
+	if len(zs.closed) != 1 || zs.closed[0] != session {
+		t.Errorf("expected session %s closed, got %v", session, zs.closed)
+	}
+}
+
+func TestRemotePluginDelta(t *testing.T) {
+	zs := &zoneServer{opened: map[string]framework.Arguments{}}
+	socket := serve(t, zs)
+
+	test := uthelper.TestCommonStruct{
+		Name:    "remote zone delta",
+		Plugins: map[string]framework.PluginBuilder{"delta-zone": NewBuilder("delta-zone", socket)},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("c1", "pg1", "q1", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{"zone": "a"}, map[string]string{}),
+		},
+		Nodes: []*v1.Node{
+			util.BuildNode("n1", util.BuildResourceList("4", "4G"), map[string]string{"zone": "a"}),
+			util.BuildNode("n2", util.BuildResourceList("4", "4G"), map[string]string{"zone": "b"}),
+		},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+	}
+	defer test.Close()
+
+	test.Run()
+	first := zs.requests[0].Session
+	if req := zs.requests[0]; len(req.Base) != 0 || len(req.Nodes) != 2 || len(req.Jobs) != 1 || len(req.Queues) != 1 {
+		t.Errorf("expected the full state sent first, got base %q, %d nodes, %d jobs and %d queues",
+			req.Base, len(req.Nodes), len(req.Jobs), len(req.Queues))
+	}
+
+	// The jobs, nodes and queues not changed in cache are not sent again.
+	tiers := []conf.Tier{{Plugins: []conf.PluginOption{{Name: "delta-zone"}}}}
+	test.Cache().DeleteNode(test.Nodes[1])
+	framework.CloseSession(framework.OpenSession(test.Cache(), tiers, nil))
+	second := zs.requests[1].Session
+	if req := zs.requests[1]; req.Base != first || len(req.Nodes) != 0 || len(req.Jobs) != 0 || len(req.Queues) != 0 ||
+		len(req.RemovedNodes) != 1 || req.RemovedNodes[0] != "n2" {
+		t.Errorf("expected the delta from session %s removing n2, got base %q, nodes %v, jobs %v, queues %v and removed nodes %v",
+			first, req.Base, req.Nodes, req.Jobs, req.Queues, req.RemovedNodes)
+	}
+	if len(zs.state.Nodes) != 1 || zs.state.Session != second {
+		t.Errorf("expected the state of session %s with 1 node, got %s with %d nodes", second, zs.state.Session, len(zs.state.Nodes))
+	}
+
+	// The job changed in cache is sent again.
+	test.Cache().AddPod(util.BuildPod("c1", "p2", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{"zone": "a"}, map[string]string{}))
+	framework.CloseSession(framework.OpenSession(test.Cache(), tiers, nil))
+	if req := zs.requests[2]; req.Base != second || len(req.Jobs) != 1 || len(req.Nodes) != 0 {
+		t.Errorf("expected the delta from session %s with the job changed, got base %q, jobs %v and nodes %v",
+			second, req.Base, req.Jobs, req.Nodes)
+	}
+	third := zs.requests[2].Session
+
+	// The full state is sent again once the remote plugin lost it.
+	zs.state = SessionState{}
+	framework.CloseSession(framework.OpenSession(test.Cache(), tiers, nil))
+	if len(zs.requests) != 5 || zs.requests[3].Base != third || len(zs.requests[4].Base) != 0 || len(zs.state.Nodes) != 1 {
+		t.Errorf("expected the full state sent again after the delta, got %d requests and %d nodes", len(zs.requests), len(zs.state.Nodes))
+	}
+}
+
+func TestRemotePluginUnavailable(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "missing.sock")
+
+	test := uthelper.TestCommonStruct{
+		Name:    "remote plugin unavailable",
+		Plugins: map[string]framework.PluginBuilder{"zone": NewBuilder("zone", socket)},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("c1", "pg1", "q1", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{"zone": "a"}, map[string]string{}),
+		},
+		Nodes:  []*v1.Node{util.BuildNode("n2", util.BuildResourceList("4", "4G"), map[string]string{"zone": "b"})},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+	}
+	defer test.Close()
+
+	// No node is allowed if the remote plugin which is not ignorable fails to open the session.
+	if err := test.CheckPredicates(map[string]map[string]bool{
+		"c1/p1": {"n2": false},
+	}); err != nil {
+		t.Error(err)
+	}
+	test.Close()
+
+	// None of the functions of the ignorable remote plugin is added if the session fails to open.
+	test.Arguments = map[string]framework.Arguments{"zone": {RemoteIgnorable: true}}
+	if err := test.CheckPredicates(map[string]map[string]bool{
+		"c1/p1": {"n2": true},
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegisterPlugins(t *testing.T) {
+	for _, plugins := range [][]string{{"zone"}, {"=/tmp/zone.sock"}, {"zone="}} {
+		if err := RegisterPlugins(plugins); err == nil {
+			t.Errorf("expected error of invalid remote plugins %v", plugins)
+		}
+	}
+
+	if err := RegisterPlugins([]string{"remote-zone=/tmp/zone.sock"}); err != nil {
+		t.Fatalf("failed to register remote plugins: %v", err)
+	}
+	defer framework.CleanupPluginBuilders()
+	if _, found := framework.GetPluginBuilder("remote-zone"); !found {
+		t.Errorf("expected remote plugin remote-zone registered")
+	}
+}
@@ -25,6 +25,14 @@ func init() {
 	lastRescheduleTime = time.Now()
 }
 
+// SnapshotState saves the last execution time, and returns the function restoring it.
+func SnapshotState() func() {
+	last := lastRescheduleTime
+	return func() {
+		lastRescheduleTime = last
+	}
+}
+
 // timeToRun checks whether it is time to execute rescheduling
 func timeToRun(interval time.Duration) bool {
 	now := time.Now()
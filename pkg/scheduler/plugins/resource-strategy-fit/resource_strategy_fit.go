@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcestrategyfit
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	k8sFramework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "resource-strategy-fit"
+
+	// Weight is the key of argument with the weight of the score of the plugin, 1 by default.
+	Weight = "resource-strategy-fit.weight"
+	// Resources is the key of argument with the strategies and weights of resources, cpu and memory
+	// LeastAllocated with weight 1 by default.
+	Resources = "resource-strategy-fit.resources"
+
+	// MostAllocated prefers the nodes with the most of the resource allocated, i.e. packs the resource.
+	MostAllocated = "MostAllocated"
+	// LeastAllocated prefers the nodes with the least of the resource allocated, i.e. spreads the resource.
+	LeastAllocated = "LeastAllocated"
+)
+
+/*
+   actions: "enqueue, allocate, backfill"
+   tiers:
+   - plugins:
+     - name: resource-strategy-fit
+       arguments:
+         resource-strategy-fit.weight: 10
+         resource-strategy-fit.resources:
+           nvidia.com/gpu:
+             type: MostAllocated
+             weight: 2
+           cpu:
+             type: LeastAllocated
+             weight: 1
+*/
+
+// resourceStrategy is the strategy and weight a resource is scored by.
+type resourceStrategy struct {
+	strategy string
+	weight   int
+}
+
+type resourceStrategyFitPlugin struct {
+	weight    int
+	resources map[v1.ResourceName]resourceStrategy
+}
+
+// New function returns resourceStrategyFitPlugin object
+func New(arguments framework.Arguments) framework.Plugin {
+	weight := 1
+	arguments.GetInt(&weight, Weight)
+	return &resourceStrategyFitPlugin{
+		weight:    weight,
+		resources: parseResources(arguments),
+	}
+}
+
+// parseResources returns the strategies of resources from Resources, the resources with invalid strategies or
+// weights are ignored.
+func parseResources(arguments framework.Arguments) map[v1.ResourceName]resourceStrategy {
+	argsValue, found := arguments[Resources]
+	if !found {
+		return map[v1.ResourceName]resourceStrategy{
+			v1.ResourceCPU:    {strategy: LeastAllocated, weight: 1},
+			v1.ResourceMemory: {strategy: LeastAllocated, weight: 1},
+		}
+	}
+	resources, ok := argsValue.(map[interface{}]interface{})
+	if !ok {
+		klog.Warningf("Invalid %s %v of plugin %s, no resource is scored", Resources, argsValue, PluginName)
+		return nil
+	}
+	strategies := map[v1.ResourceName]resourceStrategy{}
+	for r, s := range resources {
+		resource, _ := r.(string)
+		config, ok := s.(map[interface{}]interface{})
+		if resource == "" || !ok {
+			klog.Warningf("Invalid strategy %v of resource %v in %s of plugin %s, it is ignored", s, r, Resources, PluginName)
+			continue
+		}
+		strategy, _ := config["type"].(string)
+		if strategy != MostAllocated && strategy != LeastAllocated {
+			klog.Warningf("Unknown type %v of resource %s in %s of plugin %s, it is ignored", config["type"], resource, Resources, PluginName)
+			continue
+		}
+		weight := 1
+		if value, found := config["weight"]; found {
+			if weight, ok = value.(int); !ok || weight < 0 {
+				klog.Warningf("Invalid weight %v of resource %s in %s of plugin %s, it is ignored", value, resource, Resources, PluginName)
+				continue
+			}
+		}
+		strategies[v1.ResourceName(resource)] = resourceStrategy{strategy: strategy, weight: weight}
+	}
+	return strategies
+}
+
+func (rsf *resourceStrategyFitPlugin) String() string {
+	msg := make([]string, 0, len(rsf.resources))
+	for name, rs := range rsf.resources {
+		msg = append(msg, fmt.Sprintf("%s[%s, %d]", name, rs.strategy, rs.weight))
+	}
+	return strings.Join(msg, ", ")
+}
+
+func (rsf *resourceStrategyFitPlugin) Name() string {
+	return PluginName
+}
+
+func (rsf *resourceStrategyFitPlugin) OnSessionOpen(ssn *framework.Session) {
+	klog.V(5).Infof("Enter %s plugin ...", PluginName)
+	defer func() {
+		klog.V(5).Infof("Leaving %s plugin. %s ...", PluginName, rsf.String())
+	}()
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		score := rsf.score(task, node)
+		klog.V(4).Infof("Resource strategy fit score for Task %s/%s on node %s is: %v", task.Namespace, task.Name, node.Name, score)
+		return score, nil
+	}
+	if rsf.weight != 0 && len(rsf.resources) != 0 {
+		ssn.AddNodeOrderFn(rsf.Name(), nodeOrderFn)
+	} else {
+		klog.Infof("%s weight is zero or no resource is scored, skip node order function", PluginName)
+	}
+}
+
+func (rsf *resourceStrategyFitPlugin) OnSessionClose(ssn *framework.Session) {
+}
+
+// score returns the average of the scores of the resources requested by the task weighted by their weights,
+// mapped to [0, MaxNodeScore] and multiplied by the weight of the plugin. The resources not requested by the
+// task are not scored.
+func (rsf *resourceStrategyFitPlugin) score(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	score := 0.0
+	weightSum := 0
+	for _, resource := range task.Resreq.ResourceNames() {
+		request := task.Resreq.Get(resource)
+		rs, found := rsf.resources[resource]
+		if !found || request == 0 {
+			continue
+		}
+		resourceScore := resourceFitScore(rs.strategy, request, node.Used.Get(resource), node.Allocatable.Get(resource))
+		klog.V(5).Infof("task %s/%s on node %s resource %s, need %f, used %f, allocatable %f, %s weight %d, score %f",
+			task.Namespace, task.Name, node.Name, resource, request, node.Used.Get(resource), node.Allocatable.Get(resource),
+			rs.strategy, rs.weight, resourceScore)
+		score += resourceScore * float64(rs.weight)
+		weightSum += rs.weight
+	}
+	if weightSum > 0 {
+		score /= float64(weightSum)
+	}
+	return score * float64(k8sFramework.MaxNodeScore*int64(rsf.weight))
+}
+
+// resourceFitScore returns the score of the resource in [0, 1] by the strategy after placing the request on the
+// node, 0 if the node does not have enough of the resource.
+func resourceFitScore(strategy string, requested, used, allocatable float64) float64 {
+	if allocatable == 0 || requested+used > allocatable {
+		return 0
+	}
+	if strategy == MostAllocated {
+		return (requested + used) / allocatable
+	}
+	return (allocatable - requested - used) / allocatable
+}
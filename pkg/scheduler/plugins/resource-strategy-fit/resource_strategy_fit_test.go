@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcestrategyfit
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestResourceStrategyFit(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments framework.Arguments
+		expected  map[string]float64
+	}{
+		{
+			name:      "cpu and memory are spread by default",
+			arguments: framework.Arguments{},
+			// n1: cpu (8-1-4)/8, memory (8-1-2)/8; n2: cpu (8-1)/8, memory (8-1)/8
+			expected: map[string]float64{"n1": 50, "n2": 87.5},
+		},
+		{
+			name: "gpu is packed and cpu is spread",
+			arguments: framework.Arguments{
+				Weight: 2,
+				Resources: map[interface{}]interface{}{
+					api.GPUResourceName: map[interface{}]interface{}{"type": MostAllocated, "weight": 3},
+					"cpu":               map[interface{}]interface{}{"type": LeastAllocated},
+				},
+			},
+			// n1: gpu (1+2)/4 * 3 + cpu (8-1-4)/8; n2: gpu 1/4 * 3 + cpu (8-1)/8
+			expected: map[string]float64{"n1": 131.25, "n2": 81.25},
+		},
+		{
+			name: "resources not requested are not scored",
+			arguments: framework.Arguments{
+				Resources: map[interface{}]interface{}{"example.com/foo": map[interface{}]interface{}{"type": MostAllocated}},
+			},
+			expected: map[string]float64{"n1": 0, "n2": 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				Arguments: map[string]framework.Arguments{PluginName: test.arguments},
+				PodGroups: []*schedulingv1.PodGroup{
+					util.BuildPodGroup("ns", "pg0", "q1", 1, schedulingv1.PodGroupRunning),
+					util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupInqueue),
+				},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns", "p0", "n1", v1.PodRunning, util.BuildResourceListWithGPU("4", "2Gi", "2"), "pg0", nil, nil),
+					util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceListWithGPU("1", "1Gi", "1"), "pg1", nil, nil),
+				},
+				Nodes: []*v1.Node{
+					util.BuildNode("n1", util.BuildResourceListWithGPU("8", "8Gi", "4"), nil),
+					util.BuildNode("n2", util.BuildResourceListWithGPU("8", "8Gi", "4"), nil),
+				},
+				Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+			}
+			defer c.Close()
+			if err := c.CheckScores(map[string]map[string]float64{"ns/p1": test.expected}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestParseResources(t *testing.T) {
+	resources := parseResources(framework.Arguments{
+		Resources: map[interface{}]interface{}{
+			"cpu":             map[interface{}]interface{}{"type": LeastAllocated, "weight": 2},
+			"memory":          map[interface{}]interface{}{"type": "Balanced"},
+			"nvidia.com/gpu":  map[interface{}]interface{}{"type": MostAllocated, "weight": -1},
+			"example.com/foo": "MostAllocated",
+		},
+	})
+	if len(resources) != 1 || resources[v1.ResourceCPU] != (resourceStrategy{strategy: LeastAllocated, weight: 2}) {
+		t.Errorf("expected only the strategy of cpu parsed, got %v", resources)
+	}
+	if resources := parseResources(framework.Arguments{Resources: "cpu"}); len(resources) != 0 {
+		t.Errorf("expected no strategy of invalid resources, got %v", resources)
+	}
+}
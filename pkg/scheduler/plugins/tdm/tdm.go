@@ -43,6 +43,14 @@ const (
 
 var lastEvictAt time.Time
 
+// SnapshotState saves the last time tasks were evicted, and returns the function restoring it.
+func SnapshotState() func() {
+	last := lastEvictAt
+	return func() {
+		lastEvictAt = last
+	}
+}
+
 /*
    actions: "enqueue, reclaim, allocate, preempt"
    tiers:
@@ -318,10 +326,11 @@ func (tp *tdmPlugin) maxVictims(job *api.JobInfo, victims []*api.TaskInfo) []*ap
 // get max pod evict number from job budget configure
 func (tp *tdmPlugin) getMaxPodEvictNum(job *api.JobInfo) int {
 	jobRunningTaskNum := len(job.TaskStatusIndex[api.Running])
+	jobTaskNum := len(job.Tasks) + int(job.CompactedTaskNum())
 	if job.Budget.MaxUnavilable != "" {
-		maxUnavilable := tp.parseIntStr(job.Budget.MaxUnavilable, len(job.Tasks))
-		finalTaskNum := len(job.TaskStatusIndex[api.Succeeded]) + len(job.TaskStatusIndex[api.Failed])
-		realUnavilable := len(job.Tasks) - finalTaskNum - jobRunningTaskNum
+		maxUnavilable := tp.parseIntStr(job.Budget.MaxUnavilable, jobTaskNum)
+		finalTaskNum := int(job.TaskNum(api.Succeeded) + job.TaskNum(api.Failed))
+		realUnavilable := jobTaskNum - finalTaskNum - jobRunningTaskNum
 		if realUnavilable >= maxUnavilable {
 			return 0
 		}
@@ -329,7 +338,7 @@ func (tp *tdmPlugin) getMaxPodEvictNum(job *api.JobInfo) int {
 	}
 
 	if job.Budget.MinAvailable != "" {
-		minAvailable := tp.parseIntStr(job.Budget.MinAvailable, len(job.Tasks))
+		minAvailable := tp.parseIntStr(job.Budget.MinAvailable, jobTaskNum)
 		if jobRunningTaskNum >= minAvailable {
 			return jobRunningTaskNum - minAvailable
 		}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// Thresholds is the key of argument with the usage thresholds in percentage by resource and period,
+	// e.g. `usage.thresholds: {cpu: {5m: 80}, memory: {5m: 90}}`. The resources are cpuResource,
+	// memResource, gpuResource and gpuMemResource.
+	Thresholds = "usage.thresholds"
+
+	// legacyThresholds is the v1 key of Thresholds, thresholds of v1 are keyed by the prefix of resource
+	// and period, e.g. CPUUsageAvg.5m.
+	legacyThresholds = "thresholds"
+)
+
+// ArgumentsVersions are the apiVersions of the arguments of usage plugin after v1.
+var ArgumentsVersions = []framework.ArgumentsVersion{
+	{Version: "v2", Convert: convertV1ToV2},
+}
+
+// convertV1ToV2 converts the thresholds keyed by prefixes into Thresholds. The other arguments of v1 are
+// kept as they are in v2.
+func convertV1ToV2(args framework.Arguments) (framework.Arguments, []string, error) {
+	var warnings []string
+	value, found := args[legacyThresholds]
+	if !found {
+		return args, warnings, nil
+	}
+	delete(args, legacyThresholds)
+	if _, found := args[Thresholds]; found {
+		return args, append(warnings, fmt.Sprintf("%s is deprecated and ignored as %s is set", legacyThresholds, Thresholds)), nil
+	}
+	legacy, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid %s %v, expected thresholds keyed by resource and period", legacyThresholds, value)
+	}
+
+	prefixes := map[string]string{
+		cpuUsageAvgPrefix:    cpuResource,
+		memUsageAvgPrefix:    memResource,
+		gpuUsageAvgPrefix:    gpuResource,
+		gpuMemUsageAvgPrefix: gpuMemResource,
+	}
+	thresholds := map[interface{}]interface{}{}
+	var keys []string
+	for k, v := range legacy {
+		key, _ := k.(string)
+		keys = append(keys, key)
+		converted := false
+		for prefix, resource := range prefixes {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			periods, _ := thresholds[resource].(map[interface{}]interface{})
+			if periods == nil {
+				periods = map[interface{}]interface{}{}
+				thresholds[resource] = periods
+			}
+			periods[strings.TrimPrefix(key, prefix)] = v
+			converted = true
+		}
+		if !converted {
+			warnings = append(warnings, fmt.Sprintf("unknown threshold %v in %s is ignored", k, legacyThresholds))
+		}
+	}
+	sort.Strings(keys)
+	args[Thresholds] = thresholds
+	return args, append(warnings, fmt.Sprintf("%s %v are deprecated, use %s by resource and period instead",
+		legacyThresholds, keys, Thresholds)), nil
+}
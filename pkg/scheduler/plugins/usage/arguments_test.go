@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestConvertV1ToV2(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           framework.Arguments
+		expected       framework.Arguments
+		expectWarnings int
+		expectErr      bool
+	}{
+		{
+			name: "legacy thresholds",
+			args: framework.Arguments{
+				UsageType:     UsageTypeMax,
+				ThresholdMode: ThresholdModeSoft,
+				"thresholds": map[interface{}]interface{}{
+					"CPUUsageAvg.5m":    80,
+					"CPUUsageAvg.1h":    70,
+					"MEMUsageAvg.5m":    "90",
+					"GPUMEMUsageAvg.5m": 95,
+					"DiskUsageAvg.5m":   50,
+				},
+				ScoreMode: ScoreModeWeighted,
+			},
+			expected: framework.Arguments{
+				UsageType:     UsageTypeMax,
+				ThresholdMode: ThresholdModeSoft,
+				Thresholds: map[interface{}]interface{}{
+					cpuResource:    map[interface{}]interface{}{"5m": 80, "1h": 70},
+					memResource:    map[interface{}]interface{}{"5m": "90"},
+					gpuMemResource: map[interface{}]interface{}{"5m": 95},
+				},
+				ScoreMode: ScoreModeWeighted,
+			},
+			// thresholds and the unknown threshold, type and mode are not deprecated
+			expectWarnings: 2,
+		},
+		{
+			name:     "arguments without legacy keys",
+			args:     framework.Arguments{ScoreMode: ScoreModeCPU},
+			expected: framework.Arguments{ScoreMode: ScoreModeCPU},
+		},
+		{
+			name: "legacy keys ignored with new keys",
+			args: framework.Arguments{
+				"thresholds": map[interface{}]interface{}{"CPUUsageAvg.5m": 80},
+				Thresholds:   map[interface{}]interface{}{cpuResource: map[interface{}]interface{}{"5m": 60}},
+			},
+			expected: framework.Arguments{
+				Thresholds: map[interface{}]interface{}{cpuResource: map[interface{}]interface{}{"5m": 60}},
+			},
+			expectWarnings: 1,
+		},
+		{
+			name:      "invalid legacy thresholds",
+			args:      framework.Arguments{"thresholds": "80"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		args, warnings, err := convertV1ToV2(test.args)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+			continue
+		}
+		if test.expectErr {
+			continue
+		}
+		if !reflect.DeepEqual(args, test.expected) || len(warnings) != test.expectWarnings {
+			t.Errorf("%s: expected %v with %d warnings, got %v with %v", test.name, test.expected, test.expectWarnings, args, warnings)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	up := New(framework.Arguments{
+		Thresholds: map[interface{}]interface{}{
+			cpuResource:    map[interface{}]interface{}{"5m": 80, "1h": "70.5"},
+			gpuResource:    map[interface{}]interface{}{"5m": 90, "1d": "high"},
+			"disk":         map[interface{}]interface{}{"5m": 50},
+			gpuMemResource: 95,
+		},
+	}).(*usagePlugin)
+	up.parseThresholds()
+
+	if !reflect.DeepEqual(up.threshold.cpuUsageAvg, map[string]float64{"5m": 80, "1h": 70.5}) {
+		t.Errorf("expected cpu thresholds of 5m and 1h, got %v", up.threshold.cpuUsageAvg)
+	}
+	if !reflect.DeepEqual(up.threshold.gpuUsageAvg, map[string]float64{"5m": 90}) {
+		t.Errorf("expected gpu threshold of 5m only, got %v", up.threshold.gpuUsageAvg)
+	}
+	if len(up.threshold.memUsageAvg) != 0 || len(up.threshold.gpuMemUsageAvg) != 0 {
+		t.Errorf("expected no memory and gpu memory thresholds, got %v and %v", up.threshold.memUsageAvg, up.threshold.gpuMemUsageAvg)
+	}
+}
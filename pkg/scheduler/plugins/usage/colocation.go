@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	k8sFramework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// ColocationOnlineSelector is the key of argument with the label selector of online-service pods, e.g.
+	// `usage.colocation.onlineSelector: "workload-type=online"`. The offline tasks, i.e. the other tasks of the
+	// session, are guarded from the online pods on nodes if it is set.
+	ColocationOnlineSelector = "usage.colocation.onlineSelector"
+	// ColocationMaxOfflineRatio is the key of argument with the percentage of the allocatable of a node the offline
+	// tasks on it may request when online pods use none of it, 100 by default. It scales down linearly as the
+	// usage of online pods rises.
+	ColocationMaxOfflineRatio = "usage.colocation.maxOfflineRatio"
+	// ColocationMinOfflineRatio is the key of argument with the percentage of the allocatable of a node the offline
+	// tasks on it may always request however busy online pods are, 0 by default.
+	ColocationMinOfflineRatio = "usage.colocation.minOfflineRatio"
+	// ColocationWeight is the key of argument with the weight of the score of nodes by the headroom left for
+	// offline tasks, 1 by default.
+	ColocationWeight = "usage.colocation.weight"
+)
+
+// colocationGuard caps the requests of offline tasks on nodes by the usage of online pods on them.
+type colocationGuard struct {
+	selector labels.Selector
+	maxRatio float64
+	minRatio float64
+	weight   int
+	// jobs and otherJobs are the jobs of the session and of the other scheduling profiles, online and offline hold
+	// the requests of online pods and offline tasks on nodes in this session, tracked by the session events
+	jobs      map[api.JobID]*api.JobInfo
+	otherJobs map[api.JobID]*api.JobInfo
+	online    map[string]*api.Resource
+	offline   map[string]*api.Resource
+}
+
+// newColocationGuard returns the guard set by the arguments, or nil if ColocationOnlineSelector is not set.
+func newColocationGuard(args framework.Arguments) *colocationGuard {
+	var selector string
+	args.GetString(&selector, ColocationOnlineSelector)
+	if selector == "" {
+		return nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		klog.Warningf("Invalid %s %q of usage plugin, colocation is not guarded: %v", ColocationOnlineSelector, selector, err)
+		return nil
+	}
+	maxRatio, minRatio := 100.0, 0.0
+	args.GetFloat64(&maxRatio, ColocationMaxOfflineRatio)
+	args.GetFloat64(&minRatio, ColocationMinOfflineRatio)
+	if maxRatio <= 0 || maxRatio > 100 || minRatio < 0 || minRatio > maxRatio {
+		klog.Warningf("Invalid %s %v and %s %v of usage plugin, 100 and 0 are used",
+			ColocationMaxOfflineRatio, maxRatio, ColocationMinOfflineRatio, minRatio)
+		maxRatio, minRatio = 100, 0
+	}
+	weight := 1
+	args.GetInt(&weight, ColocationWeight)
+	if weight < 0 {
+		klog.Warningf("Invalid %s %d of usage plugin, 1 is used", ColocationWeight, weight)
+		weight = 1
+	}
+	return &colocationGuard{selector: parsed, maxRatio: maxRatio, minRatio: minRatio, weight: weight}
+}
+
+// open sums the requests of online pods and offline tasks on the nodes of the session, and tracks the tasks
+// placed by the session events.
+func (cg *colocationGuard) open(ssn *framework.Session) {
+	if cg == nil {
+		return
+	}
+	cg.jobs, cg.otherJobs = ssn.Jobs, ssn.OtherProfileJobs()
+	cg.online = map[string]*api.Resource{}
+	cg.offline = map[string]*api.Resource{}
+	for name, node := range ssn.Nodes {
+		cg.online[name] = api.EmptyResource()
+		cg.offline[name] = api.EmptyResource()
+		for _, task := range node.Tasks {
+			if requests := cg.requestsOf(task); requests != nil {
+				requests[name].Add(task.Resreq)
+			}
+		}
+	}
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			if requests := cg.requestsOf(event.Task); requests != nil && requests[event.Task.NodeName] != nil {
+				requests[event.Task.NodeName].Add(event.Task.Resreq)
+			}
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			if requests := cg.requestsOf(event.Task); requests != nil && requests[event.Task.NodeName] != nil {
+				requests[event.Task.NodeName].Sub(event.Task.Resreq)
+			}
+		},
+	})
+}
+
+// close forgets the requests of the session.
+func (cg *colocationGuard) close() {
+	if cg == nil {
+		return
+	}
+	cg.jobs, cg.otherJobs, cg.online, cg.offline = nil, nil, nil, nil
+}
+
+// isOnline returns whether the task is of an online-service pod.
+func (cg *colocationGuard) isOnline(task *api.TaskInfo) bool {
+	return task.Pod != nil && cg.selector.Matches(labels.Set(task.Pod.Labels))
+}
+
+// requestsOf returns the requests by node the task is summed in: online for online pods, offline for the other
+// tasks of the jobs of all scheduling profiles, or nil for the other pods, e.g. daemons or pods of other schedulers.
+func (cg *colocationGuard) requestsOf(task *api.TaskInfo) map[string]*api.Resource {
+	if cg.isOnline(task) {
+		return cg.online
+	}
+	if _, found := cg.jobs[task.Job]; found {
+		return cg.offline
+	}
+	if _, found := cg.otherJobs[task.Job]; found {
+		return cg.offline
+	}
+	return nil
+}
+
+// offlineCap returns the percentage of the allocatable of the node the offline tasks may request in cpu and
+// memory, scaled down from maxRatio by the cpu and memory usages of online pods. The usage of online pods is
+// the larger one of their requests and the usage of the node not explained by the requests of offline tasks,
+// the usage of a node reporting none or a stale one is ignored.
+func (up *usagePlugin) offlineCap(node *api.NodeInfo) (float64, float64) {
+	cg := up.colocation
+	online, offline := cg.online[node.Name], cg.offline[node.Name]
+	cpuCap, memCap := cg.maxRatio, cg.maxRatio
+	if online == nil || node.Allocatable == nil {
+		return cpuCap, memCap
+	}
+	scale := func(onlineRequest, offlineRequest, total, usage float64, reported bool) float64 {
+		if total <= 0 {
+			return cg.maxRatio
+		}
+		onlineUsage := onlineRequest / total * 100
+		if reported && usage-offlineRequest/total*100 > onlineUsage {
+			onlineUsage = usage - offlineRequest/total*100
+		}
+		if onlineUsage > 100 {
+			onlineUsage = 100
+		}
+		ratio := cg.maxRatio * (100 - onlineUsage) / 100
+		if ratio < cg.minRatio {
+			ratio = cg.minRatio
+		}
+		return ratio
+	}
+	reported := node.ResourceUsage != nil && !up.stale[node.Name]
+	cpuUsage, cpuFound := up.cpuUsage(node.ResourceUsage, up.defaultPeriod())
+	memUsage, memFound := up.memUsage(node.ResourceUsage, up.defaultPeriod())
+	cpuCap = scale(online.MilliCPU, offline.MilliCPU, node.Allocatable.MilliCPU, cpuUsage, reported && cpuFound)
+	memCap = scale(online.Memory, offline.Memory, node.Allocatable.Memory, memUsage, reported && memFound)
+	return cpuCap, memCap
+}
+
+// exceedsOfflineCap returns why placing the offline task on the node would exceed the cap of the requests of
+// offline tasks on it, or empty if it would not or the task is online.
+func (up *usagePlugin) exceedsOfflineCap(task *api.TaskInfo, node *api.NodeInfo) string {
+	cg := up.colocation
+	if cg == nil || cg.isOnline(task) || node.Allocatable == nil || cg.offline[node.Name] == nil {
+		return ""
+	}
+	offline := cg.offline[node.Name]
+	cpuCap, memCap := up.offlineCap(node)
+	if total := node.Allocatable.MilliCPU; total > 0 && task.Resreq.MilliCPU > 0 {
+		if requested := (offline.MilliCPU + task.Resreq.MilliCPU) / total * 100; requested > cpuCap {
+			return fmt.Sprintf("Node %s offline cpu requests %f%% would exceed the colocation cap %f%%", node.Name, requested, cpuCap)
+		}
+	}
+	if total := node.Allocatable.Memory; total > 0 && task.Resreq.Memory > 0 {
+		if requested := (offline.Memory + task.Resreq.Memory) / total * 100; requested > memCap {
+			return fmt.Sprintf("Node %s offline mem requests %f%% would exceed the colocation cap %f%%", node.Name, requested, memCap)
+		}
+	}
+	return ""
+}
+
+// colocationScore returns the score of the node by the headroom left under the cap of offline tasks after
+// placing the offline task, the smaller one of cpu and memory, or 0 for online tasks.
+func (up *usagePlugin) colocationScore(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	cg := up.colocation
+	if cg == nil || cg.isOnline(task) || node.Allocatable == nil || cg.offline[node.Name] == nil {
+		return 0
+	}
+	offline := cg.offline[node.Name]
+	cpuCap, memCap := up.offlineCap(node)
+	headroom := func(requested, total, limit float64) float64 {
+		if total <= 0 || limit <= 0 {
+			return 0
+		}
+		left := (limit - requested/total*100) / limit
+		if left < 0 {
+			return 0
+		}
+		return left
+	}
+	score := headroom(offline.MilliCPU+task.Resreq.MilliCPU, node.Allocatable.MilliCPU, cpuCap)
+	if mem := headroom(offline.Memory+task.Resreq.Memory, node.Allocatable.Memory, memCap); mem < score {
+		score = mem
+	}
+	return score * float64(k8sFramework.MaxNodeScore*int64(cg.weight))
+}
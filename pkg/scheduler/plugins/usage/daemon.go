@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// DaemonWeight is the key of argument with the weight in [0, 1] of the usages of DaemonSet and static pods in the
+// usages of nodes, 1 by default. The cpu and memory usages nodes are filtered and scored by are lowered by the share
+// of the rest of the usages of these pods in the latest usages of nodes, e.g. 0 leaves them out, so that the usages
+// of nodes are the ones of workloads.
+const DaemonWeight = "usage.daemonWeight"
+
+// daemonUsage is the shares in [0, 1] of the cpu and memory usages of a node of the DaemonSet and static pods on it
+// left out by DaemonWeight. The metrics source only reports the latest usages of pods, so the shares are taken of the
+// latest usages of the node and applied to its usages of every period alike.
+type daemonUsage struct {
+	cpu float64
+	mem float64
+}
+
+// parseDaemonWeight returns DaemonWeight of the arguments, 1 if it is invalid.
+func parseDaemonWeight(args framework.Arguments) float64 {
+	weight := 1.0
+	args.GetFloat64(&weight, DaemonWeight)
+	if weight < 0 || weight > 1 {
+		klog.Warningf("Invalid %s %v of usage plugin, 1 is used", DaemonWeight, weight)
+		weight = 1
+	}
+	return weight
+}
+
+// daemonUsages returns the shares of the usages of the DaemonSet and static pods left out of the usages of nodes in
+// this session, keyed by the usages of the nodes. The usages of pods are in percentage of the capacity of the nodes,
+// or their allocatable if the capacity is unknown, as the latest usages of the nodes are.
+func (up *usagePlugin) daemonUsages(ssn *framework.Session) map[*api.NodeUsage]daemonUsage {
+	if up.daemonWeight == 1 {
+		return nil
+	}
+	usages := map[*api.NodeUsage]daemonUsage{}
+	for _, node := range ssn.Nodes {
+		usage := node.ResourceUsage
+		if usage == nil || usage.Stale || len(usage.PodUsages) == 0 {
+			continue
+		}
+		var daemon api.PodUsage
+		for _, task := range node.Tasks {
+			if task.Class == api.PodClassWorkload {
+				continue
+			}
+			if podUsage, found := usage.PodUsages[task.Namespace+"/"+task.Name]; found {
+				daemon.MilliCPU += podUsage.MilliCPU
+				daemon.Memory += podUsage.Memory
+			}
+		}
+		total := node.Capacity
+		if total == nil || total.MilliCPU == 0 || total.Memory == 0 {
+			total = node.Allocatable
+		}
+		var result daemonUsage
+		if total != nil && total.MilliCPU > 0 {
+			result.cpu = daemonShare(daemon.MilliCPU/total.MilliCPU*100, usage.CPUUsage) * (1 - up.daemonWeight)
+		}
+		if total != nil && total.Memory > 0 {
+			result.mem = daemonShare(daemon.Memory/total.Memory*100, usage.MEMUsage) * (1 - up.daemonWeight)
+		}
+		if result.cpu > 0 || result.mem > 0 {
+			klog.V(4).Infof("Usages of DaemonSet and static pods on node %s left out are cpu %f, mem %f", node.Name, result.cpu, result.mem)
+			usages[usage] = result
+		}
+	}
+	return usages
+}
+
+// daemonShare returns the share of the usage of DaemonSet and static pods in the latest usage of the node, both in
+// percentage, at most 1. It is 0 if the latest usage of the node is not reported.
+func daemonShare(daemon, latest float64) float64 {
+	if latest <= 0 {
+		return 0
+	}
+	if share := daemon / latest; share < 1 {
+		return share
+	}
+	return 1
+}
+
+// withoutDaemon returns the usage lowered by the share of DaemonSet and static pods left out.
+func withoutDaemon(value, share float64) float64 {
+	return value * (1 - share)
+}
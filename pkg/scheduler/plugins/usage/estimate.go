@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// EstimatePlacement is the key of argument enabling the estimation of the usages of nodes after placing tasks,
+// false by default. The nodes are filtered and scored by their usages plus the estimated usages of the task and
+// of the tasks placed on them in the session.
+const EstimatePlacement = "usage.estimatePlacement"
+
+// placementEstimator estimates the real usages of tasks by the usages of the running pods of their jobs.
+type placementEstimator struct {
+	// podUsages are the usages of the pods on all nodes keyed by namespace/name
+	podUsages map[string]*api.PodUsage
+	// placed are the sums of the estimated usages of the tasks placed on nodes in the session, tasks holds the
+	// nodes and estimated usages of these tasks
+	placed map[string]*api.PodUsage
+	tasks  map[api.TaskID]placement
+	jobs   map[api.JobID]*api.JobInfo
+}
+
+type placement struct {
+	node  string
+	usage api.PodUsage
+}
+
+// newPlacementEstimator returns the estimator of the session, tracking the tasks placed by the session events.
+func newPlacementEstimator(ssn *framework.Session) *placementEstimator {
+	pe := &placementEstimator{
+		podUsages: map[string]*api.PodUsage{},
+		placed:    map[string]*api.PodUsage{},
+		tasks:     map[api.TaskID]placement{},
+		jobs:      ssn.Jobs,
+	}
+	for _, node := range ssn.Nodes {
+		if node.ResourceUsage == nil {
+			continue
+		}
+		for key, usage := range node.ResourceUsage.PodUsages {
+			pe.podUsages[key] = usage
+		}
+	}
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc:   func(event *framework.Event) { pe.place(event.Task) },
+		DeallocateFunc: func(event *framework.Event) { pe.unplace(event.Task) },
+	})
+	return pe
+}
+
+// place adds the estimated usage of the task allocated or pipelined to the node. The usages of the running tasks,
+// e.g. whose eviction is discarded, are in the usages of the nodes already.
+func (pe *placementEstimator) place(task *api.TaskInfo) {
+	if (task.Status != api.Allocated && task.Status != api.Pipelined) || len(task.NodeName) == 0 {
+		return
+	}
+	pe.unplace(task)
+	estimated := pe.estimate(task)
+	placed, found := pe.placed[task.NodeName]
+	if !found {
+		placed = &api.PodUsage{}
+		pe.placed[task.NodeName] = placed
+	}
+	placed.MilliCPU += estimated.MilliCPU
+	placed.Memory += estimated.Memory
+	pe.tasks[task.UID] = placement{node: task.NodeName, usage: estimated}
+}
+
+// unplace subtracts the estimated usage of the task placed from its node.
+func (pe *placementEstimator) unplace(task *api.TaskInfo) {
+	p, found := pe.tasks[task.UID]
+	if !found {
+		return
+	}
+	delete(pe.tasks, task.UID)
+	pe.placed[p.node].MilliCPU -= p.usage.MilliCPU
+	pe.placed[p.node].Memory -= p.usage.Memory
+}
+
+// estimate returns the average usage of the pods of the same task spec of the job reporting usages, or of all
+// the pods of the job if none of the spec reports, or the resource request of the task if none of the job reports.
+func (pe *placementEstimator) estimate(task *api.TaskInfo) api.PodUsage {
+	if job, found := pe.jobs[task.Job]; found {
+		var spec, all api.PodUsage
+		var specCount, allCount float64
+		for _, other := range job.Tasks {
+			usage, found := pe.podUsages[other.Namespace+"/"+other.Name]
+			if !found {
+				continue
+			}
+			all.MilliCPU += usage.MilliCPU
+			all.Memory += usage.Memory
+			allCount++
+			if other.GetTaskSpecKey() == task.GetTaskSpecKey() {
+				spec.MilliCPU += usage.MilliCPU
+				spec.Memory += usage.Memory
+				specCount++
+			}
+		}
+		if specCount > 0 {
+			return api.PodUsage{MilliCPU: spec.MilliCPU / specCount, Memory: spec.Memory / specCount}
+		}
+		if allCount > 0 {
+			return api.PodUsage{MilliCPU: all.MilliCPU / allCount, Memory: all.Memory / allCount}
+		}
+	}
+	return api.PodUsage{MilliCPU: task.Resreq.MilliCPU, Memory: task.Resreq.Memory}
+}
+
+// extraUsage returns the cpu and memory usages in percentage of the node added by placing the task on it, on top
+// of the tasks placed in the session, by the capacity of the node, or its allocatable if the capacity is unknown.
+func (pe *placementEstimator) extraUsage(task *api.TaskInfo, node *api.NodeInfo) (float64, float64) {
+	estimated := pe.estimate(task)
+	if placed, found := pe.placed[node.Name]; found {
+		estimated.MilliCPU += placed.MilliCPU
+		estimated.Memory += placed.Memory
+	}
+	total := node.Capacity
+	if total == nil || total.MilliCPU == 0 || total.Memory == 0 {
+		total = node.Allocatable
+	}
+	var cpu, mem float64
+	if total != nil && total.MilliCPU > 0 {
+		cpu = estimated.MilliCPU / total.MilliCPU * 100
+	}
+	if total != nil && total.Memory > 0 {
+		mem = estimated.Memory / total.Memory * 100
+	}
+	return cpu, mem
+}
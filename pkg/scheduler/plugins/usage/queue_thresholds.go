@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+// QueueThresholds is the key of argument with the cpu and memory thresholds of queues by queue name, e.g.
+// `usage.queueThresholds: {batch: {cpu: 95, memory: 95}, online: {cpu: {5m: 60}}}`. A threshold of a resource
+// overrides the thresholds of the plugin of the resource for the tasks of the queue, a threshold without period
+// applies to the periods of the thresholds of the plugin, or to the default period if the plugin has none.
+// The thresholds in node annotations override the ones of queues.
+const QueueThresholds = "usage.queueThresholds"
+
+// allPeriods is the period of a queue threshold set without period.
+const allPeriods = ""
+
+// queueThreshold is the cpu and memory thresholds of a queue by period, nil if the queue has none for the resource.
+type queueThreshold struct {
+	cpuUsageAvg map[string]float64
+	memUsageAvg map[string]float64
+}
+
+// parseQueueThresholds returns the thresholds of queues from QueueThresholds, invalid thresholds are ignored.
+func parseQueueThresholds(args framework.Arguments) map[string]queueThreshold {
+	argsValue, found := args[QueueThresholds]
+	if !found {
+		return nil
+	}
+	queues, ok := argsValue.(map[interface{}]interface{})
+	if !ok {
+		klog.Warningf("Invalid %s %v of usage plugin, no queue threshold is set", QueueThresholds, argsValue)
+		return nil
+	}
+
+	result := map[string]queueThreshold{}
+	for q, r := range queues {
+		queue, _ := q.(string)
+		resources, ok := r.(map[interface{}]interface{})
+		if queue == "" || !ok {
+			klog.Warningf("Invalid thresholds %v of queue %v in %s of usage plugin, they are ignored", r, q, QueueThresholds)
+			continue
+		}
+		threshold := queueThreshold{}
+		for res, value := range resources {
+			resource, _ := res.(string)
+			periods := parseQueuePeriods(queue, resource, value)
+			switch resource {
+			case cpuResource:
+				threshold.cpuUsageAvg = periods
+			case memResource:
+				threshold.memUsageAvg = periods
+			default:
+				klog.Warningf("Invalid resource %v of queue %s in %s of usage plugin, it is ignored", res, queue, QueueThresholds)
+			}
+		}
+		result[queue] = threshold
+	}
+	return result
+}
+
+// parseQueuePeriods returns the threshold of the resource of the queue by period, nil if it is invalid.
+func parseQueuePeriods(queue, resource string, value interface{}) map[string]float64 {
+	if threshold, ok := parseFloat(value); ok {
+		return map[string]float64{allPeriods: threshold}
+	}
+	periods, ok := value.(map[interface{}]interface{})
+	if !ok {
+		klog.Warningf("Invalid threshold %v of %s of queue %s in %s of usage plugin, it is ignored", value, resource, queue, QueueThresholds)
+		return nil
+	}
+	result := map[string]float64{}
+	for k, v := range periods {
+		period, _ := k.(string)
+		threshold, ok := parseFloat(v)
+		if period == "" || !ok {
+			klog.Warningf("Invalid threshold %v of period %v of %s of queue %s in %s of usage plugin, it is ignored",
+				v, k, resource, queue, QueueThresholds)
+			continue
+		}
+		result[period] = threshold
+	}
+	return result
+}
+
+// thresholdOfTask returns the cpu and memory thresholds of the queue of the task on the node, nil for the resources
+// the queue has no threshold of or the node overrides by its annotations.
+func (up *usagePlugin) thresholdOfTask(task *api.TaskInfo, node *api.NodeInfo) queueThreshold {
+	if task == nil || len(up.queueThresholds) == 0 {
+		return queueThreshold{}
+	}
+	threshold, found := up.queueThresholds[up.jobQueues[task.Job]]
+	if !found {
+		return queueThreshold{}
+	}
+
+	resolve := func(thresholds, pluginThresholds map[string]float64, annotation string) map[string]float64 {
+		if thresholds == nil {
+			return nil
+		}
+		if node.Node != nil {
+			if _, annotated := parseThreshold(node, annotation); annotated {
+				return nil
+			}
+		}
+		value, found := thresholds[allPeriods]
+		if !found {
+			return thresholds
+		}
+		result := map[string]float64{}
+		for period := range pluginThresholds {
+			result[period] = value
+		}
+		if len(result) == 0 {
+			result[up.defaultPeriod()] = value
+		}
+		return result
+	}
+	return queueThreshold{
+		cpuUsageAvg: resolve(threshold.cpuUsageAvg, up.threshold.cpuUsageAvg, CPUThresholdAnnotation),
+		memUsageAvg: resolve(threshold.memUsageAvg, up.threshold.memUsageAvg, MEMThresholdAnnotation),
+	}
+}
+
+// exceedsQueueThreshold returns why the cpu or memory usage of the node of any period exceeds the threshold of
+// the queue of the task, or empty if none does. The rejection is counted if count is set.
+func (up *usagePlugin) exceedsQueueThreshold(task *api.TaskInfo, node *api.NodeInfo, threshold queueThreshold, count bool) string {
+	for _, resource := range []string{cpuResource, memResource} {
+		thresholds, getUsage := threshold.cpuUsageAvg, up.cpuUsage
+		if resource == memResource {
+			thresholds, getUsage = threshold.memUsageAvg, up.memUsage
+		}
+		for period, value := range thresholds {
+			if usage, found := getUsage(node.ResourceUsage, period); found && usage > value {
+				if count {
+					metrics.RegisterUsagePredicateRejection(node.Name, resource, period)
+				}
+				return fmt.Sprintf("Node %s %s usage %f exceeds the threshold %f of queue %s",
+					node.Name, resource, usage, value, up.jobQueues[task.Job])
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// CPUConsecutiveSamples is the key of argument with the number of consecutive samples the cpu usage
+	// must be above the threshold before the node is filtered out, and below it before the node is admitted again.
+	CPUConsecutiveSamples = "usage.cpu.consecutiveSamples"
+	// MEMConsecutiveSamples is the same as CPUConsecutiveSamples for memory usage.
+	MEMConsecutiveSamples = "usage.memory.consecutiveSamples"
+	// GPUConsecutiveSamples is the same as CPUConsecutiveSamples for GPU utilization and GPU memory usage.
+	GPUConsecutiveSamples = "usage.gpu.consecutiveSamples"
+
+	cpuResource    = "cpu"
+	memResource    = "memory"
+	gpuResource    = "gpu"
+	gpuMemResource = "gpu-memory"
+)
+
+type breachKey struct {
+	node     string
+	resource string
+	period   string
+}
+
+// filterKey is the key of the breach state of a node usage in a scheduling profile, as profiles may
+// configure different thresholds and samples for the same usage.
+type filterKey struct {
+	profile string
+	breachKey
+}
+
+// breachState counts the consecutive samples of one usage above or below its threshold.
+type breachState struct {
+	sampleTime time.Time
+	above      int
+	below      int
+	breached   bool
+}
+
+// spikeFilter remembers the breach state of node usages across sessions, so a short spike reported by
+// noisy node exporters does not flip the decision of the plugin.
+type spikeFilter struct {
+	sync.Mutex
+	states map[filterKey]*breachState
+}
+
+var filter = &spikeFilter{states: map[filterKey]*breachState{}}
+
+// SnapshotState saves the breach states of node usages, and returns the function restoring it.
+func SnapshotState() func() {
+	filter.Lock()
+	states := copyBreachStates(filter.states)
+	filter.Unlock()
+	return func() {
+		filter.Lock()
+		defer filter.Unlock()
+		filter.states = copyBreachStates(states)
+	}
+}
+
+func copyBreachStates(states map[filterKey]*breachState) map[filterKey]*breachState {
+	copied := make(map[filterKey]*breachState, len(states))
+	for key, state := range states {
+		breach := *state
+		copied[key] = &breach
+	}
+	return copied
+}
+
+// observe records the sample of usage taken at sampleTime in profile, and returns whether the usage is treated
+// as above the threshold. A sample is only counted once however many sessions of profile see it. If samples is
+// not greater than 1, the current usage is used as is.
+func (f *spikeFilter) observe(profile string, key breachKey, sampleTime time.Time, usage, threshold float64, samples int) bool {
+	if samples <= 1 {
+		return usage > threshold
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	fk := filterKey{profile: profile, breachKey: key}
+	state, found := f.states[fk]
+	if !found {
+		state = &breachState{}
+		f.states[fk] = state
+	}
+	if !sampleTime.After(state.sampleTime) {
+		return state.breached
+	}
+
+	state.sampleTime = sampleTime
+	if usage > threshold {
+		state.above++
+		state.below = 0
+	} else {
+		state.below++
+		state.above = 0
+	}
+	if !state.breached && state.above >= samples {
+		state.breached = true
+	} else if state.breached && state.below >= samples {
+		state.breached = false
+	}
+	return state.breached
+}
+
+// prune forgets the states in profile of nodes which no longer exist.
+func (f *spikeFilter) prune(profile string, nodes map[string]*api.NodeInfo) {
+	f.Lock()
+	defer f.Unlock()
+
+	for key := range f.states {
+		if _, found := nodes[key.node]; key.profile == profile && !found {
+			delete(f.states, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestSpikeFilter(t *testing.T) {
+	f := &spikeFilter{states: map[filterKey]*breachState{}}
+	key := breachKey{node: "n1", resource: cpuResource, period: "5m"}
+	start := time.Now()
+
+	steps := []struct {
+		name     string
+		sample   int
+		usage    float64
+		expected bool
+	}{
+		{name: "first sample above", sample: 1, usage: 90, expected: false},
+		{name: "same sample seen again", sample: 1, usage: 90, expected: false},
+		{name: "spike ends", sample: 2, usage: 50, expected: false},
+		{name: "above again", sample: 3, usage: 90, expected: false},
+		{name: "second consecutive sample above", sample: 4, usage: 95, expected: true},
+		{name: "first sample below", sample: 5, usage: 50, expected: true},
+		{name: "second consecutive sample below", sample: 6, usage: 50, expected: false},
+	}
+
+	for _, step := range steps {
+		sampleTime := start.Add(time.Duration(step.sample) * time.Minute)
+		if breached := f.observe("", key, sampleTime, step.usage, 80, 2); breached != step.expected {
+			t.Errorf("%s: expected %v, got %v", step.name, step.expected, breached)
+		}
+	}
+
+	if breached := f.observe("", key, start, 90, 80, 1); !breached {
+		t.Errorf("expected usage above threshold to be breached without filtering")
+	}
+
+	f.prune("", map[string]*api.NodeInfo{"n2": {}})
+	if len(f.states) != 0 {
+		t.Errorf("expected states of removed node to be pruned, got %v", f.states)
+	}
+}
+
+func TestSpikeFilterPerProfile(t *testing.T) {
+	f := &spikeFilter{states: map[filterKey]*breachState{}}
+	key := breachKey{node: "n1", resource: cpuResource, period: "5m"}
+	start := time.Now()
+
+	// The samples are counted in each profile, with the thresholds of the profile.
+	for i := 1; i <= 2; i++ {
+		sampleTime := start.Add(time.Duration(i) * time.Minute)
+		f.observe("", key, sampleTime, 90, 80, 2)
+		f.observe("batch", key, sampleTime, 90, 95, 2)
+	}
+	if breached := f.observe("", key, start.Add(2*time.Minute), 90, 80, 2); !breached {
+		t.Errorf("expected usage breached in the default profile")
+	}
+	if breached := f.observe("batch", key, start.Add(2*time.Minute), 90, 95, 2); breached {
+		t.Errorf("expected usage not breached in profile batch")
+	}
+
+	f.prune("batch", map[string]*api.NodeInfo{})
+	if _, found := f.states[filterKey{breachKey: key}]; !found || len(f.states) != 1 {
+		t.Errorf("expected only the states of profile batch pruned, got %v", f.states)
+	}
+}
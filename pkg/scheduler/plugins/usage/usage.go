@@ -19,13 +19,13 @@ package usage
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"k8s.io/klog/v2"
 	k8sFramework "k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
 )
 
 const (
@@ -33,8 +33,71 @@ const (
 	PluginName        = "usage"
 	cpuUsageAvgPrefix = "CPUUsageAvg."
 	memUsageAvgPrefix = "MEMUsageAvg."
-	thresholdSection  = "thresholds"
 	cpuUsageAvg5m     = "5m"
+
+	// gpuUsageAvgPrefix and gpuMemUsageAvgPrefix are the prefixes of thresholds of GPU utilization and GPU memory usage
+	gpuUsageAvgPrefix    = "GPUUsageAvg."
+	gpuMemUsageAvgPrefix = "GPUMEMUsageAvg."
+
+	// ScoreMode is the key of argument selecting the usages nodes are scored by, one of
+	// ScoreModeCPU, ScoreModeMemory, ScoreModeWeighted and ScoreModeGPU.
+	ScoreMode = "usage.scoreMode"
+	// CPUScoreWeight and MEMScoreWeight are the keys of arguments with the weights of the cpu
+	// and memory usages in ScoreModeWeighted.
+	CPUScoreWeight = "usage.cpu.weight"
+	MEMScoreWeight = "usage.memory.weight"
+
+	// ScoreModeCPU scores nodes by cpu usage only, the default.
+	ScoreModeCPU = "cpu"
+	// ScoreModeMemory scores nodes by memory usage only.
+	ScoreModeMemory = "memory"
+	// ScoreModeWeighted scores nodes by the weighted average of cpu and memory usages, and GPU utilization
+	// of the nodes reporting it if GPUScoreWeight is set.
+	ScoreModeWeighted = "weighted"
+	// ScoreModeGPU scores nodes by GPU utilization only.
+	ScoreModeGPU = "gpu"
+	// GPUScoreWeight is the key of argument with the weight of GPU utilization in ScoreModeWeighted, 0 by default.
+	GPUScoreWeight = "usage.gpu.weight"
+
+	// CPUThresholdAnnotation and MEMThresholdAnnotation are the keys of node annotations with the cpu and
+	// memory usage thresholds in percentage of the node, overriding the thresholds of all periods of the plugin.
+	CPUThresholdAnnotation = "volcano.sh/usage-cpu-threshold"
+	MEMThresholdAnnotation = "volcano.sh/usage-mem-threshold"
+
+	// UsageType is the key of argument selecting the type of usages nodes are filtered and scored by,
+	// one of UsageTypeAverage, UsageTypeMax and UsageTypeCommon.
+	UsageType = "type"
+	// UsageTypeAverage is the average usage over the period, the default.
+	UsageTypeAverage = "average"
+	// UsageTypeMax is the max usage over the period.
+	UsageTypeMax = "max"
+	// UsageTypeCommon is the latest usage, the period is ignored.
+	UsageTypeCommon = "common"
+
+	// ThresholdMode is the key of argument selecting how nodes over the thresholds are treated,
+	// ThresholdModeHard or ThresholdModeSoft.
+	ThresholdMode = "mode"
+	// ThresholdModeHard filters out nodes over the thresholds, the default.
+	ThresholdModeHard = "hard"
+	// ThresholdModeSoft keeps nodes over the thresholds feasible but scores them 0.
+	ThresholdModeSoft = "soft"
+
+	// Periods is the key of argument with the weights of the periods blended into the usage of BlendedPeriod,
+	// e.g. `usage.periods: {5m: 1, 1h: 3}`. The periods must be collected by the `periods` of metrics configuration.
+	Periods = "usage.periods"
+	// BlendedPeriod is the period of the usage blended from Periods, e.g. the key of threshold `CPUUsageAvg.blended`.
+	// Nodes are scored by it instead of the 5m usage when Periods is set.
+	BlendedPeriod = "blended"
+
+	// StaleAction is the key of argument selecting how nodes with stale usages are treated, e.g. when the
+	// metrics source is down, one of StaleActionIgnore, StaleActionFilter and StaleActionFail.
+	StaleAction = "usage.staleAction"
+	// StaleActionIgnore skips the thresholds of nodes with stale usages, the default.
+	StaleActionIgnore = "ignore"
+	// StaleActionFilter treats nodes with stale usages as over the thresholds.
+	StaleActionFilter = "filter"
+	// StaleActionFail fails the predicate of nodes with stale usages with an error in both threshold modes.
+	StaleActionFail = "fail"
 )
 
 /*
@@ -42,36 +105,261 @@ const (
    tiers:
    - plugins:
      - name: usage
+       apiVersion: v2
        arguments:
-          thresholds:
-            CPUUsageAvg.5m: 80
-            MEMUsageAvg.5m: 90
+          type: average
+          mode: hard
+          usage.thresholds:
+            cpu:
+              5m: 80
+            memory:
+              5m: 90
+            gpu:
+              5m: 90
+            gpu-memory:
+              5m: 95
+          usage.cpu.consecutiveSamples: 3
+          usage.memory.consecutiveSamples: 3
+          usage.scoreMode: weighted
+          usage.periods:
+            5m: 1
+            1h: 3
+          usage.cpu.weight: 1
+          usage.memory.weight: 2
+          usage.gpu.weight: 2
+          usage.staleAction: ignore
+          usage.estimatePlacement: true
+          usage.daemonWeight: 0.5
+          usage.queueThresholds:
+            batch:
+              cpu: 95
+              memory: 95
+            online:
+              cpu:
+                5m: 60
+          usage.colocation.onlineSelector: workload-type=online
+          usage.colocation.maxOfflineRatio: 80
+          usage.colocation.minOfflineRatio: 10
+          usage.colocation.weight: 1
 */
 
 type thresholdConfig struct {
-	cpuUsageAvg map[string]float64
-	memUsageAvg map[string]float64
+	cpuUsageAvg    map[string]float64
+	memUsageAvg    map[string]float64
+	gpuUsageAvg    map[string]float64
+	gpuMemUsageAvg map[string]float64
 }
 
 type usagePlugin struct {
 	pluginArguments framework.Arguments
 	weight          int
 	threshold       thresholdConfig
+	// cpuSamples and memSamples are the numbers of consecutive samples to filter out spikes of usage
+	cpuSamples int
+	memSamples int
+	gpuSamples int
+	// exceeded holds the usages treated as above their thresholds in this session
+	exceeded map[breachKey]bool
+	// nodeThresholds holds the thresholds of nodes in this session, overridden by node annotations
+	nodeThresholds map[string]thresholdConfig
+	// scoreMode selects the usages nodes are scored by, cpuWeight and memWeight weigh them in ScoreModeWeighted
+	scoreMode string
+	cpuWeight int
+	memWeight int
+	gpuWeight int
+	// usageType is the type of usages nodes are filtered and scored by
+	usageType string
+	// thresholdMode selects whether nodes over the thresholds are filtered out or scored 0
+	thresholdMode string
+	// periodWeights are the weights of the periods blended into the usage of BlendedPeriod
+	periodWeights map[string]float64
+	// staleAction selects how nodes with stale usages are treated, stale holds these nodes in this session
+	staleAction string
+	stale       map[string]bool
+	// estimatePlacement enables estimating the usages of nodes after placing tasks, estimator estimates them in
+	// this session
+	estimatePlacement bool
+	estimator         *placementEstimator
+	// queueThresholds are the thresholds of queues by queue name, jobQueues holds the queue names of jobs in this
+	// session if they are set
+	queueThresholds map[string]queueThreshold
+	jobQueues       map[api.JobID]string
+	// colocation caps the requests of offline tasks on nodes by the usage of online pods if it is set
+	colocation *colocationGuard
+	// daemonWeight weighs the usages of DaemonSet and static pods in the usages of nodes, daemons holds the usages
+	// of these pods left out in this session by the usages of nodes
+	daemonWeight float64
+	daemons      map[*api.NodeUsage]daemonUsage
 }
 
 // New function returns usagePlugin object
 func New(args framework.Arguments) framework.Plugin {
 	usageWeight := 1
 	args.GetInt(&usageWeight, "usage.weight")
+	cpuSamples, memSamples, gpuSamples := 1, 1, 1
+	args.GetInt(&cpuSamples, CPUConsecutiveSamples)
+	args.GetInt(&memSamples, MEMConsecutiveSamples)
+	args.GetInt(&gpuSamples, GPUConsecutiveSamples)
+	scoreMode := ScoreModeCPU
+	args.GetString(&scoreMode, ScoreMode)
+	switch scoreMode {
+	case ScoreModeCPU, ScoreModeMemory, ScoreModeWeighted, ScoreModeGPU:
+	default:
+		klog.Warningf("Unknown %s %q of usage plugin, %s is used", ScoreMode, scoreMode, ScoreModeCPU)
+		scoreMode = ScoreModeCPU
+	}
+	usageType := UsageTypeAverage
+	args.GetString(&usageType, UsageType)
+	switch usageType {
+	case UsageTypeAverage, UsageTypeMax, UsageTypeCommon:
+	default:
+		klog.Warningf("Unknown %s %q of usage plugin, %s is used", UsageType, usageType, UsageTypeAverage)
+		usageType = UsageTypeAverage
+	}
+	thresholdMode := ThresholdModeHard
+	args.GetString(&thresholdMode, ThresholdMode)
+	switch thresholdMode {
+	case ThresholdModeHard, ThresholdModeSoft:
+	default:
+		klog.Warningf("Unknown %s %q of usage plugin, %s is used", ThresholdMode, thresholdMode, ThresholdModeHard)
+		thresholdMode = ThresholdModeHard
+	}
+	staleAction := StaleActionIgnore
+	args.GetString(&staleAction, StaleAction)
+	switch staleAction {
+	case StaleActionIgnore, StaleActionFilter, StaleActionFail:
+	default:
+		klog.Warningf("Unknown %s %q of usage plugin, %s is used", StaleAction, staleAction, StaleActionIgnore)
+		staleAction = StaleActionIgnore
+	}
+	cpuWeight, memWeight := 1, 1
+	args.GetInt(&cpuWeight, CPUScoreWeight)
+	args.GetInt(&memWeight, MEMScoreWeight)
+	if cpuWeight < 0 || memWeight < 0 || cpuWeight+memWeight == 0 {
+		klog.Warningf("Invalid %s %d and %s %d of usage plugin, 1 is used for both",
+			CPUScoreWeight, cpuWeight, MEMScoreWeight, memWeight)
+		cpuWeight, memWeight = 1, 1
+	}
+	estimatePlacement := false
+	args.GetBool(&estimatePlacement, EstimatePlacement)
+	gpuWeight := 0
+	args.GetInt(&gpuWeight, GPUScoreWeight)
+	if gpuWeight < 0 {
+		klog.Warningf("Invalid %s %d of usage plugin, 0 is used", GPUScoreWeight, gpuWeight)
+		gpuWeight = 0
+	}
 	config := thresholdConfig{
-		cpuUsageAvg: make(map[string]float64),
-		memUsageAvg: make(map[string]float64),
+		cpuUsageAvg:    make(map[string]float64),
+		memUsageAvg:    make(map[string]float64),
+		gpuUsageAvg:    make(map[string]float64),
+		gpuMemUsageAvg: make(map[string]float64),
 	}
 	return &usagePlugin{
-		pluginArguments: args,
-		weight:          usageWeight,
-		threshold:       config,
+		pluginArguments:   args,
+		weight:            usageWeight,
+		threshold:         config,
+		cpuSamples:        cpuSamples,
+		memSamples:        memSamples,
+		gpuSamples:        gpuSamples,
+		scoreMode:         scoreMode,
+		cpuWeight:         cpuWeight,
+		memWeight:         memWeight,
+		gpuWeight:         gpuWeight,
+		usageType:         usageType,
+		thresholdMode:     thresholdMode,
+		periodWeights:     parsePeriodWeights(args),
+		staleAction:       staleAction,
+		estimatePlacement: estimatePlacement,
+		queueThresholds:   parseQueueThresholds(args),
+		colocation:        newColocationGuard(args),
+		daemonWeight:      parseDaemonWeight(args),
+	}
+}
+
+// parsePeriodWeights returns the weights of the periods blended, the periods with invalid weights are ignored.
+func parsePeriodWeights(args framework.Arguments) map[string]float64 {
+	argsValue, found := args[Periods]
+	if !found {
+		return nil
+	}
+	periods, ok := argsValue.(map[interface{}]interface{})
+	if !ok {
+		klog.Warningf("Invalid %s %v of usage plugin, periods are not blended", Periods, argsValue)
+		return nil
+	}
+	weights := map[string]float64{}
+	for k, v := range periods {
+		period, _ := k.(string)
+		weight, ok := parseFloat(v)
+		if period == "" || !ok || weight <= 0 {
+			klog.Warningf("Invalid weight %v of period %v in %s of usage plugin, the period is ignored", v, k, Periods)
+			continue
+		}
+		weights[period] = weight
 	}
+	return weights
+}
+
+// parseFloat returns the value of argument as a number, and whether it is a number.
+func parseFloat(v interface{}) (float64, bool) {
+	switch a := v.(type) {
+	case string:
+		val, err := strconv.ParseFloat(a, 64)
+		return val, err == nil
+	case int:
+		return float64(a), true
+	case float64:
+		return a, true
+	default:
+		return 0, false
+	}
+}
+
+// parseThresholds sets the thresholds of the plugin by resource and period from Thresholds.
+func (up *usagePlugin) parseThresholds() {
+	argsValue, found := up.pluginArguments[Thresholds]
+	if !found {
+		klog.V(4).Infof("No %s of usage plugin", Thresholds)
+		return
+	}
+	resources, ok := argsValue.(map[interface{}]interface{})
+	if !ok {
+		klog.Warningf("Invalid %s %v of usage plugin, no threshold is set", Thresholds, argsValue)
+		return
+	}
+	thresholds := map[string]map[string]float64{
+		cpuResource:    up.threshold.cpuUsageAvg,
+		memResource:    up.threshold.memUsageAvg,
+		gpuResource:    up.threshold.gpuUsageAvg,
+		gpuMemResource: up.threshold.gpuMemUsageAvg,
+	}
+	for r, p := range resources {
+		resource, _ := r.(string)
+		periods, ok := p.(map[interface{}]interface{})
+		if _, found := thresholds[resource]; !found || !ok {
+			klog.Warningf("Invalid threshold %v of resource %v in %s of usage plugin, it is ignored", p, r, Thresholds)
+			continue
+		}
+		for k, v := range periods {
+			period, _ := k.(string)
+			val, ok := parseFloat(v)
+			if period == "" || !ok {
+				klog.Warningf("Invalid threshold %v of period %v of %s in %s of usage plugin, it is ignored", v, k, resource, Thresholds)
+				continue
+			}
+			thresholds[resource][period] = val
+			klog.V(4).Infof("Threshold of %s in %s: %f", resource, period, val)
+		}
+	}
+}
+
+// defaultPeriod returns the period nodes are scored by, and of the thresholds in node annotations if the plugin
+// has none: BlendedPeriod if periods are blended, otherwise 5m.
+func (up *usagePlugin) defaultPeriod() string {
+	if len(up.periodWeights) != 0 {
+		return BlendedPeriod
+	}
+	return cpuUsageAvg5m
 }
 
 func (up *usagePlugin) Name() string {
@@ -84,53 +372,126 @@ func (up *usagePlugin) OnSessionOpen(ssn *framework.Session) {
 		klog.V(5).Infof("Leaving usage plugin ...")
 	}()
 
+	up.daemons = up.daemonUsages(ssn)
 	if klog.V(4).Enabled() {
 		for node := range ssn.Nodes {
-			usage := ssn.Nodes[node].ResourceUsage
-			klog.V(4).Infof("node:%v, cpu usage:%v, mem usage:%v", node, usage.CPUUsageAvg["5m"], usage.MEMUsageAvg["5m"])
-		}
-	}
-
-	argsValue, ok := up.pluginArguments[thresholdSection]
-	if ok {
-		args, ok := argsValue.(map[interface{}]interface{})
-		if !ok {
-			klog.V(4).Infof("pluginArguments[thresholdsSection]:%v", argsValue)
-		}
-		for k, v := range args {
-			key, _ := k.(string)
-			var val float64
-			switch a := v.(type) {
-			case string:
-				val, _ = strconv.ParseFloat(a, 64)
-			case int:
-				val = float64(a)
-			case float64:
-				val = a
-			default:
-				klog.V(4).Infof("The threshold %v is an unknown type", a)
+			cpuUsage, _ := up.cpuUsage(ssn.Nodes[node].ResourceUsage, up.defaultPeriod())
+			memUsage, _ := up.memUsage(ssn.Nodes[node].ResourceUsage, up.defaultPeriod())
+			klog.V(4).Infof("node:%v, %s cpu usage:%v, mem usage:%v", node, up.usageType, cpuUsage, memUsage)
+		}
+	}
+
+	up.parseThresholds()
+
+	up.exceeded = map[breachKey]bool{}
+	up.nodeThresholds = map[string]thresholdConfig{}
+	up.stale = map[string]bool{}
+	metrics.ResetNodeUsage()
+	for name, node := range ssn.Nodes {
+		usage := node.ResourceUsage
+		threshold := up.thresholdOf(node)
+		up.nodeThresholds[name] = threshold
+		// The cleared usages of stale nodes are not observed.
+		if usage != nil && usage.Stale {
+			klog.V(4).Infof("Usage of node %s collected at %v is stale, %s it", name, usage.SampleTime, up.staleAction)
+			up.stale[name] = true
+			if up.staleAction == StaleActionFail || (up.thresholdMode == ThresholdModeHard && up.overThreshold(name, queueThreshold{})) {
+				ssn.RecordNodeCapacityReduction(name, PluginName, node.Allocatable)
 			}
-			if strings.Contains(key, cpuUsageAvgPrefix) {
-				periodKey := strings.Replace(key, cpuUsageAvgPrefix, "", 1)
-				up.threshold.cpuUsageAvg[periodKey] = val
+			continue
+		}
+		for period, value := range threshold.cpuUsageAvg {
+			key := breachKey{node: name, resource: cpuResource, period: period}
+			cpuUsage, _ := up.cpuUsage(usage, period)
+			if filter.observe(ssn.Profile(), key, usage.SampleTime, cpuUsage, value, up.cpuSamples) {
+				up.exceeded[key] = true
 			}
-			if strings.Contains(key, memUsageAvgPrefix) {
-				periodKey := strings.Replace(key, memUsageAvgPrefix, "", 1)
-				up.threshold.memUsageAvg[periodKey] = val
+		}
+		for period, value := range threshold.memUsageAvg {
+			key := breachKey{node: name, resource: memResource, period: period}
+			memUsage, _ := up.memUsage(usage, period)
+			if filter.observe(ssn.Profile(), key, usage.SampleTime, memUsage, value, up.memSamples) {
+				up.exceeded[key] = true
+			}
+		}
+		// Nodes without GPUs do not report GPU usages, they are never over the GPU thresholds.
+		for period, value := range threshold.gpuUsageAvg {
+			key := breachKey{node: name, resource: gpuResource, period: period}
+			if gpuUsage, found := up.gpuUsage(usage, period); found &&
+				filter.observe(ssn.Profile(), key, usage.SampleTime, gpuUsage, value, up.gpuSamples) {
+				up.exceeded[key] = true
+			}
+		}
+		for period, value := range threshold.gpuMemUsageAvg {
+			key := breachKey{node: name, resource: gpuMemResource, period: period}
+			if gpuMemUsage, found := up.gpuMemUsage(usage, period); found &&
+				filter.observe(ssn.Profile(), key, usage.SampleTime, gpuMemUsage, value, up.gpuSamples) {
+				up.exceeded[key] = true
 			}
-			klog.V(4).Infof("Threshold config key: %s, value: %f", key, val)
 		}
-	} else {
-		klog.V(4).Infof("Threshold arguments :%v", argsValue)
+		up.recordUsageMetrics(name, usage, threshold)
+		// The node filtered out for its usage is not schedulable at all, unless queues have their own thresholds.
+		if up.thresholdMode == ThresholdModeHard && len(up.queueThresholds) == 0 && up.overThreshold(name, queueThreshold{}) {
+			ssn.RecordNodeCapacityReduction(name, PluginName, node.Allocatable)
+		}
+	}
+	filter.prune(ssn.Profile(), ssn.Nodes)
+	if len(up.queueThresholds) != 0 {
+		up.jobQueues = map[api.JobID]string{}
+		for _, job := range ssn.Jobs {
+			if queue, found := ssn.Queues[job.Queue]; found {
+				up.jobQueues[job.UID] = queue.Name
+			}
+		}
+	}
+	if up.estimatePlacement {
+		up.estimator = newPlacementEstimator(ssn)
 	}
+	up.colocation.open(ssn)
 
 	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
 		predicateStatus := make([]*api.Status, 0)
 		usageStatus := &api.Status{}
-		for period, value := range up.threshold.cpuUsageAvg {
-			klog.V(4).Infof("predicateFn cpuUsageAvg:%v", up.threshold.cpuUsageAvg)
-			if node.ResourceUsage.CPUUsageAvg[period] > value {
-				msg := fmt.Sprintf("Node %s cpu usage %f exceeds the threshold %f", node.Name, node.ResourceUsage.CPUUsageAvg[period], value)
+		if msg := up.exceedsOfflineCap(task, node); msg != "" {
+			usageStatus.Code = api.Unschedulable
+			usageStatus.Reason = msg
+			predicateStatus = append(predicateStatus, usageStatus)
+			return predicateStatus, fmt.Errorf("plugin %s predicates failed %s", up.Name(), msg)
+		}
+		if up.stale[node.Name] {
+			// Nodes with stale usages are not filtered out in soft mode.
+			switch {
+			case up.staleAction == StaleActionFilter && up.thresholdMode == ThresholdModeHard:
+				msg := fmt.Sprintf("Node %s usage collected at %v is stale", node.Name, node.ResourceUsage.SampleTime)
+				usageStatus.Code = api.Unschedulable
+				usageStatus.Reason = msg
+				predicateStatus = append(predicateStatus, usageStatus)
+				return predicateStatus, fmt.Errorf("plugin %s predicates failed %s", up.Name(), msg)
+			case up.staleAction == StaleActionFail:
+				msg := fmt.Sprintf("Node %s usage collected at %v is stale", node.Name, node.ResourceUsage.SampleTime)
+				usageStatus.Code = api.Error
+				usageStatus.Reason = msg
+				predicateStatus = append(predicateStatus, usageStatus)
+				return predicateStatus, fmt.Errorf("plugin %s failed to check usage: %s", up.Name(), msg)
+			}
+		}
+		if up.stale[node.Name] || up.thresholdMode == ThresholdModeSoft {
+			usageStatus.Code = api.Success
+			predicateStatus = append(predicateStatus, usageStatus)
+			return predicateStatus, nil
+		}
+		threshold, found := up.nodeThresholds[node.Name]
+		if !found {
+			threshold = up.thresholdOf(node)
+		}
+		// The thresholds of the queue of the task replace the cpu and memory thresholds of the plugin.
+		queueThreshold := up.thresholdOfTask(task, node)
+		for period, value := range threshold.cpuUsageAvg {
+			klog.V(4).Infof("predicateFn cpuUsageAvg:%v", threshold.cpuUsageAvg)
+			if queueThreshold.cpuUsageAvg == nil && up.exceeded[breachKey{node: node.Name, resource: cpuResource, period: period}] {
+				metrics.RegisterUsagePredicateRejection(node.Name, cpuResource, period)
+				cpuUsage, _ := up.cpuUsage(node.ResourceUsage, period)
+				msg := fmt.Sprintf("Node %s cpu usage %f exceeds the threshold %f", node.Name, cpuUsage, value)
 				usageStatus.Code = api.Unschedulable
 				usageStatus.Reason = msg
 				predicateStatus = append(predicateStatus, usageStatus)
@@ -138,10 +499,12 @@ func (up *usagePlugin) OnSessionOpen(ssn *framework.Session) {
 			}
 		}
 
-		for period, value := range up.threshold.memUsageAvg {
-			klog.V(4).Infof("predicateFn memUsageAvg:%v", up.threshold.memUsageAvg)
-			if node.ResourceUsage.MEMUsageAvg[period] > value {
-				msg := fmt.Sprintf("Node %s mem usage %f exceeds the threshold %f", node.Name, node.ResourceUsage.MEMUsageAvg[period], value)
+		for period, value := range threshold.memUsageAvg {
+			klog.V(4).Infof("predicateFn memUsageAvg:%v", threshold.memUsageAvg)
+			if queueThreshold.memUsageAvg == nil && up.exceeded[breachKey{node: node.Name, resource: memResource, period: period}] {
+				metrics.RegisterUsagePredicateRejection(node.Name, memResource, period)
+				memUsage, _ := up.memUsage(node.ResourceUsage, period)
+				msg := fmt.Sprintf("Node %s mem usage %f exceeds the threshold %f", node.Name, memUsage, value)
 				usageStatus.Code = api.Unschedulable
 				usageStatus.Reason = msg
 				predicateStatus = append(predicateStatus, usageStatus)
@@ -149,6 +512,50 @@ func (up *usagePlugin) OnSessionOpen(ssn *framework.Session) {
 			}
 		}
 
+		if msg := up.exceedsQueueThreshold(task, node, queueThreshold, true); msg != "" {
+			usageStatus.Code = api.Unschedulable
+			usageStatus.Reason = msg
+			predicateStatus = append(predicateStatus, usageStatus)
+			return predicateStatus, fmt.Errorf("plugin %s predicates failed %s", up.Name(), msg)
+		}
+
+		for period, value := range threshold.gpuUsageAvg {
+			if up.exceeded[breachKey{node: node.Name, resource: gpuResource, period: period}] {
+				metrics.RegisterUsagePredicateRejection(node.Name, gpuResource, period)
+				gpuUsage, _ := up.gpuUsage(node.ResourceUsage, period)
+				msg := fmt.Sprintf("Node %s gpu utilization %f exceeds the threshold %f", node.Name, gpuUsage, value)
+				usageStatus.Code = api.Unschedulable
+				usageStatus.Reason = msg
+				predicateStatus = append(predicateStatus, usageStatus)
+				return predicateStatus, fmt.Errorf("plugin %s gpu usage predicates failed %s", up.Name(), msg)
+			}
+		}
+
+		for period, value := range threshold.gpuMemUsageAvg {
+			if up.exceeded[breachKey{node: node.Name, resource: gpuMemResource, period: period}] {
+				metrics.RegisterUsagePredicateRejection(node.Name, gpuMemResource, period)
+				gpuMemUsage, _ := up.gpuMemUsage(node.ResourceUsage, period)
+				msg := fmt.Sprintf("Node %s gpu mem usage %f exceeds the threshold %f", node.Name, gpuMemUsage, value)
+				usageStatus.Code = api.Unschedulable
+				usageStatus.Reason = msg
+				predicateStatus = append(predicateStatus, usageStatus)
+				return predicateStatus, fmt.Errorf("plugin %s gpu memory usage predicates failed %s", up.Name(), msg)
+			}
+		}
+
+		if queueThreshold.cpuUsageAvg != nil {
+			threshold.cpuUsageAvg = queueThreshold.cpuUsageAvg
+		}
+		if queueThreshold.memUsageAvg != nil {
+			threshold.memUsageAvg = queueThreshold.memUsageAvg
+		}
+		if msg := up.exceedsAfterPlacement(task, node, threshold); msg != "" {
+			usageStatus.Code = api.Unschedulable
+			usageStatus.Reason = msg
+			predicateStatus = append(predicateStatus, usageStatus)
+			return predicateStatus, fmt.Errorf("plugin %s predicates failed %s", up.Name(), msg)
+		}
+
 		usageStatus.Code = api.Success
 		predicateStatus = append(predicateStatus, usageStatus)
 		klog.V(4).Infof("Usage plugin filter for task %s/%s on node %s pass.", task.Namespace, task.Name, node.Name)
@@ -156,20 +563,306 @@ func (up *usagePlugin) OnSessionOpen(ssn *framework.Session) {
 	}
 
 	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
-		score := 0.0
-		cpuUsage, exist := node.ResourceUsage.CPUUsageAvg[cpuUsageAvg5m]
-		klog.V(4).Infof("Node %s cpu usage is %f.", node.Name, cpuUsage)
-		if !exist {
-			return 0, nil
-		}
-		score = (100 - cpuUsage) / 100
-		score *= float64(k8sFramework.MaxNodeScore * int64(up.weight))
+		score := up.score(task, node) + up.colocationScore(task, node)
 		klog.V(4).Infof("Node %s score for task %s is %f.", node.Name, task.Name, score)
 		return score, nil
 	}
 
-	ssn.AddPredicateFn(up.Name(), predicateFn)
+	// In soft mode, nodes over the thresholds are kept feasible, e.g. for gangs on a busy cluster,
+	// and are scored 0 instead, while nodes with stale usages still fail in StaleActionFail. The cap of offline
+	// tasks of colocation is always hard.
+	if up.thresholdMode == ThresholdModeHard || up.staleAction == StaleActionFail || up.colocation != nil {
+		ssn.AddPredicateFn(up.Name(), predicateFn)
+	}
 	ssn.AddNodeOrderFn(up.Name(), nodeOrderFn)
 }
 
-func (up *usagePlugin) OnSessionClose(ssn *framework.Session) {}
+// score returns the score of the node by its usage, after placing the task if the placement is estimated, 0 if
+// it does not report the usage, or if it is over the thresholds of the task in soft mode.
+func (up *usagePlugin) score(task *api.TaskInfo, node *api.NodeInfo) float64 {
+	if up.thresholdMode == ThresholdModeSoft && up.overTaskThreshold(task, node) {
+		klog.V(4).Infof("Node %s is over the usage thresholds, score 0.", node.Name)
+		return 0
+	}
+	var extraCPU, extraMem float64
+	if up.estimator != nil {
+		extraCPU, extraMem = up.estimator.extraUsage(task, node)
+	}
+	usage, exist := up.usage(node, extraCPU, extraMem)
+	if !exist {
+		return 0
+	}
+	if usage > 100 {
+		usage = 100
+	}
+	score := (100 - usage) / 100
+	return score * float64(k8sFramework.MaxNodeScore*int64(up.weight))
+}
+
+// overTaskThreshold returns whether any usage of the node is above the thresholds of the task, i.e. the ones of
+// the node with the cpu and memory thresholds of the queue of the task.
+func (up *usagePlugin) overTaskThreshold(task *api.TaskInfo, node *api.NodeInfo) bool {
+	threshold := up.thresholdOfTask(task, node)
+	if up.overThreshold(node.Name, threshold) {
+		return true
+	}
+	return !up.stale[node.Name] && up.exceedsQueueThreshold(task, node, threshold, false) != ""
+}
+
+// overThreshold returns whether any usage of the node is treated as above its threshold in this session, except
+// the cpu and memory usages replaced by the thresholds of a queue, nodes with stale usages are in StaleActionFilter.
+func (up *usagePlugin) overThreshold(node string, queueThreshold queueThreshold) bool {
+	if up.stale[node] {
+		return up.staleAction == StaleActionFilter
+	}
+	threshold := up.nodeThresholds[node]
+	for period := range threshold.cpuUsageAvg {
+		if queueThreshold.cpuUsageAvg == nil && up.exceeded[breachKey{node: node, resource: cpuResource, period: period}] {
+			return true
+		}
+	}
+	for period := range threshold.memUsageAvg {
+		if queueThreshold.memUsageAvg == nil && up.exceeded[breachKey{node: node, resource: memResource, period: period}] {
+			return true
+		}
+	}
+	for period := range threshold.gpuUsageAvg {
+		if up.exceeded[breachKey{node: node, resource: gpuResource, period: period}] {
+			return true
+		}
+	}
+	for period := range threshold.gpuMemUsageAvg {
+		if up.exceeded[breachKey{node: node, resource: gpuMemResource, period: period}] {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUsageMetrics exports the cpu and memory usages of the node of the default period and of the periods of its
+// thresholds seen in this session, and whether they are over the thresholds.
+func (up *usagePlugin) recordUsageMetrics(name string, usage *api.NodeUsage, threshold thresholdConfig) {
+	if usage == nil {
+		return
+	}
+	for resource, thresholds := range map[string]map[string]float64{
+		cpuResource: threshold.cpuUsageAvg,
+		memResource: threshold.memUsageAvg,
+	} {
+		getUsage := up.cpuUsage
+		if resource == memResource {
+			getUsage = up.memUsage
+		}
+		periods := map[string]struct{}{up.defaultPeriod(): {}}
+		for period := range thresholds {
+			periods[period] = struct{}{}
+		}
+		for period := range periods {
+			if value, found := getUsage(usage, period); found {
+				metrics.UpdateNodeUsage(name, resource, period, value)
+			}
+			if _, found := thresholds[period]; found {
+				metrics.UpdateNodeUsageOverThreshold(name, resource, period,
+					up.exceeded[breachKey{node: name, resource: resource, period: period}])
+			}
+		}
+	}
+}
+
+// usage returns the usage of the default period of the node in percentage the node is scored by, with the extra
+// cpu and memory usages in percentage added, and whether the node reports it. In ScoreModeWeighted, the node must
+// report both cpu and memory usages, and GPU utilization is weighed in if the node reports it.
+func (up *usagePlugin) usage(node *api.NodeInfo, extraCPU, extraMem float64) (float64, bool) {
+	cpuUsage, cpuExist := up.cpuUsage(node.ResourceUsage, up.defaultPeriod())
+	memUsage, memExist := up.memUsage(node.ResourceUsage, up.defaultPeriod())
+	cpuUsage += extraCPU
+	memUsage += extraMem
+	gpuUsage, gpuExist := up.gpuUsage(node.ResourceUsage, up.defaultPeriod())
+	klog.V(4).Infof("Node %s cpu usage is %f, mem usage is %f, gpu usage is %f.", node.Name, cpuUsage, memUsage, gpuUsage)
+	switch up.scoreMode {
+	case ScoreModeMemory:
+		return memUsage, memExist
+	case ScoreModeGPU:
+		return gpuUsage, gpuExist
+	case ScoreModeWeighted:
+		if !cpuExist || !memExist {
+			return 0, false
+		}
+		sum := cpuUsage*float64(up.cpuWeight) + memUsage*float64(up.memWeight)
+		weights := up.cpuWeight + up.memWeight
+		if gpuExist {
+			sum += gpuUsage * float64(up.gpuWeight)
+			weights += up.gpuWeight
+		}
+		return sum / float64(weights), true
+	default:
+		return cpuUsage, cpuExist
+	}
+}
+
+// exceedsAfterPlacement returns why the cpu or memory usage of the node of any period would exceed its threshold
+// after placing the task if the placement is estimated, or empty if none would.
+func (up *usagePlugin) exceedsAfterPlacement(task *api.TaskInfo, node *api.NodeInfo, threshold thresholdConfig) string {
+	if up.estimator == nil {
+		return ""
+	}
+	extraCPU, extraMem := up.estimator.extraUsage(task, node)
+	for period, value := range threshold.cpuUsageAvg {
+		if cpuUsage, found := up.cpuUsage(node.ResourceUsage, period); found && cpuUsage+extraCPU > value {
+			metrics.RegisterUsagePredicateRejection(node.Name, cpuResource, period)
+			return fmt.Sprintf("Node %s cpu usage %f would exceed the threshold %f after placing the task", node.Name, cpuUsage+extraCPU, value)
+		}
+	}
+	for period, value := range threshold.memUsageAvg {
+		if memUsage, found := up.memUsage(node.ResourceUsage, period); found && memUsage+extraMem > value {
+			metrics.RegisterUsagePredicateRejection(node.Name, memResource, period)
+			return fmt.Sprintf("Node %s mem usage %f would exceed the threshold %f after placing the task", node.Name, memUsage+extraMem, value)
+		}
+	}
+	return ""
+}
+
+// thresholdOf returns the thresholds of the node. The threshold in the annotation of the node overrides the ones
+// of all periods of the plugin, or is the threshold of the default period if the plugin has none.
+func (up *usagePlugin) thresholdOf(node *api.NodeInfo) thresholdConfig {
+	if node.Node == nil {
+		return up.threshold
+	}
+	cpuThreshold, cpuFound := parseThreshold(node, CPUThresholdAnnotation)
+	memThreshold, memFound := parseThreshold(node, MEMThresholdAnnotation)
+	if !cpuFound && !memFound {
+		return up.threshold
+	}
+
+	override := func(thresholds map[string]float64, value float64, found bool) map[string]float64 {
+		if !found {
+			return thresholds
+		}
+		result := map[string]float64{}
+		for period := range thresholds {
+			result[period] = value
+		}
+		if len(result) == 0 {
+			result[up.defaultPeriod()] = value
+		}
+		return result
+	}
+	return thresholdConfig{
+		cpuUsageAvg:    override(up.threshold.cpuUsageAvg, cpuThreshold, cpuFound),
+		memUsageAvg:    override(up.threshold.memUsageAvg, memThreshold, memFound),
+		gpuUsageAvg:    up.threshold.gpuUsageAvg,
+		gpuMemUsageAvg: up.threshold.gpuMemUsageAvg,
+	}
+}
+
+// parseThreshold returns the threshold in the annotation of the node, and whether it is valid.
+func parseThreshold(node *api.NodeInfo, annotation string) (float64, bool) {
+	value, found := node.Node.Annotations[annotation]
+	if !found {
+		return 0, false
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		klog.Warningf("Invalid annotation %s %q of node %s, the threshold of usage plugin is used", annotation, value, node.Name)
+		return 0, false
+	}
+	return threshold, true
+}
+
+// cpuUsage returns the cpu usage of the period by the usage type of the plugin, and whether it is reported.
+func (up *usagePlugin) cpuUsage(usage *api.NodeUsage, period string) (float64, bool) {
+	if period == BlendedPeriod {
+		return up.blend(usage, up.cpuUsage)
+	}
+	var value float64
+	var found bool
+	switch up.usageType {
+	case UsageTypeMax:
+		value, found = usage.CPUUsageMax[period]
+	case UsageTypeCommon:
+		value, found = usage.CPUUsage, !usage.SampleTime.IsZero() && !usage.Stale
+	default:
+		value, found = usage.CPUUsageAvg[period]
+	}
+	return withoutDaemon(value, up.daemons[usage].cpu), found
+}
+
+// memUsage is the same as cpuUsage for memory usage.
+func (up *usagePlugin) memUsage(usage *api.NodeUsage, period string) (float64, bool) {
+	if period == BlendedPeriod {
+		return up.blend(usage, up.memUsage)
+	}
+	var value float64
+	var found bool
+	switch up.usageType {
+	case UsageTypeMax:
+		value, found = usage.MEMUsageMax[period]
+	case UsageTypeCommon:
+		value, found = usage.MEMUsage, !usage.SampleTime.IsZero() && !usage.Stale
+	default:
+		value, found = usage.MEMUsageAvg[period]
+	}
+	return withoutDaemon(value, up.daemons[usage].mem), found
+}
+
+// gpuUsage is the same as cpuUsage for GPU utilization, it is not reported by nodes without GPUs.
+func (up *usagePlugin) gpuUsage(usage *api.NodeUsage, period string) (float64, bool) {
+	if period == BlendedPeriod {
+		return up.blend(usage, up.gpuUsage)
+	}
+	switch up.usageType {
+	case UsageTypeMax:
+		value, found := usage.GPUUsageMax[period]
+		return value, found
+	case UsageTypeCommon:
+		return usage.GPUUsage, usage.GPUReported
+	default:
+		value, found := usage.GPUUsageAvg[period]
+		return value, found
+	}
+}
+
+// gpuMemUsage is the same as gpuUsage for GPU memory usage.
+func (up *usagePlugin) gpuMemUsage(usage *api.NodeUsage, period string) (float64, bool) {
+	if period == BlendedPeriod {
+		return up.blend(usage, up.gpuMemUsage)
+	}
+	switch up.usageType {
+	case UsageTypeMax:
+		value, found := usage.GPUMEMUsageMax[period]
+		return value, found
+	case UsageTypeCommon:
+		return usage.GPUMEMUsage, usage.GPUReported
+	default:
+		value, found := usage.GPUMEMUsageAvg[period]
+		return value, found
+	}
+}
+
+// blend returns the average of the usages of the periods blended weighted by their weights, the periods not
+// reported are left out, and whether any of them is reported.
+func (up *usagePlugin) blend(usage *api.NodeUsage, usageOf func(*api.NodeUsage, string) (float64, bool)) (float64, bool) {
+	var sum, weights float64
+	for period, weight := range up.periodWeights {
+		if period == BlendedPeriod {
+			continue
+		}
+		if value, found := usageOf(usage, period); found {
+			sum += value * weight
+			weights += weight
+		}
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return sum / weights, true
+}
+
+func (up *usagePlugin) OnSessionClose(ssn *framework.Session) {
+	up.exceeded = nil
+	up.nodeThresholds = nil
+	up.estimator = nil
+	up.jobQueues = nil
+	up.colocation.close()
+	up.daemons = nil
+}
@@ -0,0 +1,739 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/uthelper"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestUsageScoreMode(t *testing.T) {
+	node := &api.NodeInfo{
+		Name: "n1",
+		ResourceUsage: &api.NodeUsage{
+			CPUUsageAvg: map[string]float64{"5m": 20},
+			MEMUsageAvg: map[string]float64{"5m": 80},
+		},
+	}
+	cpuOnly := &api.NodeInfo{
+		Name: "n2",
+		ResourceUsage: &api.NodeUsage{
+			CPUUsageAvg: map[string]float64{"5m": 20},
+			MEMUsageAvg: map[string]float64{},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      framework.Arguments
+		node      *api.NodeInfo
+		expected  float64
+		expectedF bool
+	}{
+		{name: "cpu by default", args: framework.Arguments{}, node: node, expected: 20, expectedF: true},
+		{name: "unknown mode falls back to cpu", args: framework.Arguments{ScoreMode: "disk"}, node: node, expected: 20, expectedF: true},
+		{name: "memory", args: framework.Arguments{ScoreMode: ScoreModeMemory}, node: node, expected: 80, expectedF: true},
+		{name: "memory not reported", args: framework.Arguments{ScoreMode: ScoreModeMemory}, node: cpuOnly, expectedF: false},
+		{name: "weighted evenly", args: framework.Arguments{ScoreMode: ScoreModeWeighted}, node: node, expected: 50, expectedF: true},
+		{
+			name:      "weighted to memory",
+			args:      framework.Arguments{ScoreMode: ScoreModeWeighted, CPUScoreWeight: 1, MEMScoreWeight: 3},
+			node:      node,
+			expected:  65,
+			expectedF: true,
+		},
+		{
+			name:      "invalid weights are reset",
+			args:      framework.Arguments{ScoreMode: ScoreModeWeighted, CPUScoreWeight: 0, MEMScoreWeight: 0},
+			node:      node,
+			expected:  50,
+			expectedF: true,
+		},
+		{name: "weighted without memory", args: framework.Arguments{ScoreMode: ScoreModeWeighted}, node: cpuOnly, expectedF: false},
+	}
+
+	for _, test := range tests {
+		up := New(test.args).(*usagePlugin)
+		usage, found := up.usage(test.node, 0, 0)
+		if found != test.expectedF || usage != test.expected {
+			t.Errorf("%s: expected usage %v, %v, got %v, %v", test.name, test.expected, test.expectedF, usage, found)
+		}
+	}
+}
+
+func TestUsageType(t *testing.T) {
+	usage := &api.NodeUsage{
+		CPUUsageAvg: map[string]float64{"5m": 20},
+		MEMUsageAvg: map[string]float64{"5m": 30},
+		CPUUsageMax: map[string]float64{"5m": 60},
+		MEMUsageMax: map[string]float64{"5m": 70},
+		CPUUsage:    40,
+		MEMUsage:    50,
+		SampleTime:  time.Now(),
+	}
+
+	tests := []struct {
+		usageType   string
+		expectedCPU float64
+		expectedMEM float64
+	}{
+		{usageType: "", expectedCPU: 20, expectedMEM: 30},
+		{usageType: UsageTypeAverage, expectedCPU: 20, expectedMEM: 30},
+		{usageType: UsageTypeMax, expectedCPU: 60, expectedMEM: 70},
+		{usageType: UsageTypeCommon, expectedCPU: 40, expectedMEM: 50},
+	}
+
+	for _, test := range tests {
+		args := framework.Arguments{}
+		if test.usageType != "" {
+			args[UsageType] = test.usageType
+		}
+		up := New(args).(*usagePlugin)
+		cpuUsage, cpuFound := up.cpuUsage(usage, "5m")
+		memUsage, memFound := up.memUsage(usage, "5m")
+		if !cpuFound || !memFound || cpuUsage != test.expectedCPU || memUsage != test.expectedMEM {
+			t.Errorf("type %q: expected usages %v and %v, got %v, %v and %v, %v", test.usageType,
+				test.expectedCPU, test.expectedMEM, cpuUsage, cpuFound, memUsage, memFound)
+		}
+	}
+
+	up := New(framework.Arguments{UsageType: UsageTypeCommon}).(*usagePlugin)
+	if _, found := up.cpuUsage(&api.NodeUsage{}, "5m"); found {
+		t.Errorf("expected latest usage not reported by node never sampled")
+	}
+}
+
+func TestNodeThreshold(t *testing.T) {
+	up := New(framework.Arguments{}).(*usagePlugin)
+	up.threshold.cpuUsageAvg["5m"] = 80
+	up.threshold.cpuUsageAvg["1h"] = 70
+
+	nodeWith := func(annotations map[string]string) *api.NodeInfo {
+		return &api.NodeInfo{Name: "n1", Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Annotations: annotations}}}
+	}
+
+	tests := []struct {
+		name        string
+		node        *api.NodeInfo
+		expectedCPU map[string]float64
+		expectedMEM map[string]float64
+	}{
+		{
+			name:        "no annotation",
+			node:        nodeWith(nil),
+			expectedCPU: map[string]float64{"5m": 80, "1h": 70},
+			expectedMEM: map[string]float64{},
+		},
+		{
+			name:        "cpu threshold overrides all periods",
+			node:        nodeWith(map[string]string{CPUThresholdAnnotation: "50"}),
+			expectedCPU: map[string]float64{"5m": 50, "1h": 50},
+			expectedMEM: map[string]float64{},
+		},
+		{
+			name:        "mem threshold of node without plugin threshold",
+			node:        nodeWith(map[string]string{MEMThresholdAnnotation: "60"}),
+			expectedCPU: map[string]float64{"5m": 80, "1h": 70},
+			expectedMEM: map[string]float64{"5m": 60},
+		},
+		{
+			name:        "invalid threshold falls back",
+			node:        nodeWith(map[string]string{CPUThresholdAnnotation: "150"}),
+			expectedCPU: map[string]float64{"5m": 80, "1h": 70},
+			expectedMEM: map[string]float64{},
+		},
+	}
+
+	for _, test := range tests {
+		threshold := up.thresholdOf(test.node)
+		if !reflect.DeepEqual(threshold.cpuUsageAvg, test.expectedCPU) || !reflect.DeepEqual(threshold.memUsageAvg, test.expectedMEM) {
+			t.Errorf("%s: expected thresholds %v and %v, got %v and %v", test.name,
+				test.expectedCPU, test.expectedMEM, threshold.cpuUsageAvg, threshold.memUsageAvg)
+		}
+	}
+	if up.threshold.cpuUsageAvg["5m"] != 80 {
+		t.Errorf("expected thresholds of plugin not changed, got %v", up.threshold.cpuUsageAvg)
+	}
+}
+
+func TestSoftThresholdMode(t *testing.T) {
+	node := &api.NodeInfo{
+		Name: "n1",
+		ResourceUsage: &api.NodeUsage{
+			CPUUsageAvg: map[string]float64{"5m": 90},
+			MEMUsageAvg: map[string]float64{"5m": 20},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		exceeded bool
+		expected float64
+	}{
+		{name: "hard mode scores by usage", mode: ThresholdModeHard, exceeded: true, expected: 10},
+		{name: "soft mode under threshold scores by usage", mode: ThresholdModeSoft, expected: 10},
+		{name: "soft mode over threshold scores 0", mode: ThresholdModeSoft, exceeded: true, expected: 0},
+		{name: "unknown mode falls back to hard", mode: "medium", exceeded: true, expected: 10},
+	}
+
+	for _, test := range tests {
+		up := New(framework.Arguments{ThresholdMode: test.mode}).(*usagePlugin)
+		up.nodeThresholds = map[string]thresholdConfig{
+			"n1": {cpuUsageAvg: map[string]float64{"5m": 80}, memUsageAvg: map[string]float64{}},
+		}
+		up.exceeded = map[breachKey]bool{}
+		if test.exceeded {
+			up.exceeded[breachKey{node: "n1", resource: cpuResource, period: "5m"}] = true
+		}
+		if score := up.score(nil, node); score != test.expected {
+			t.Errorf("%s: expected score %v, got %v", test.name, test.expected, score)
+		}
+	}
+}
+
+func TestBlendedPeriods(t *testing.T) {
+	usage := &api.NodeUsage{
+		CPUUsageAvg: map[string]float64{"5m": 90, "1h": 30},
+		MEMUsageAvg: map[string]float64{"5m": 40},
+	}
+	up := New(framework.Arguments{
+		Periods: map[interface{}]interface{}{"5m": 1, "1h": "3", "1d": -1},
+	}).(*usagePlugin)
+
+	if expected := map[string]float64{"5m": 1, "1h": 3}; !reflect.DeepEqual(up.periodWeights, expected) {
+		t.Errorf("expected period weights %v, got %v", expected, up.periodWeights)
+	}
+	if period := up.defaultPeriod(); period != BlendedPeriod {
+		t.Errorf("expected nodes scored by %s usage, got %s", BlendedPeriod, period)
+	}
+	if cpuUsage, found := up.cpuUsage(usage, BlendedPeriod); !found || cpuUsage != 45 {
+		t.Errorf("expected blended cpu usage 45, got %v, %v", cpuUsage, found)
+	}
+	if memUsage, found := up.memUsage(usage, BlendedPeriod); !found || memUsage != 40 {
+		t.Errorf("expected blended mem usage of the reported period 40, got %v, %v", memUsage, found)
+	}
+	if _, found := up.cpuUsage(&api.NodeUsage{}, BlendedPeriod); found {
+		t.Errorf("expected blended usage not reported by node reporting no period")
+	}
+
+	up = New(framework.Arguments{}).(*usagePlugin)
+	if period := up.defaultPeriod(); period != "5m" {
+		t.Errorf("expected nodes scored by 5m usage without blending, got %s", period)
+	}
+}
+
+func TestGPUUsage(t *testing.T) {
+	gpuNode := &api.NodeInfo{
+		Name: "n1",
+		ResourceUsage: &api.NodeUsage{
+			CPUUsageAvg:    map[string]float64{"5m": 20},
+			MEMUsageAvg:    map[string]float64{"5m": 40},
+			GPUUsageAvg:    map[string]float64{"5m": 95},
+			GPUMEMUsageAvg: map[string]float64{"5m": 60},
+		},
+	}
+	cpuNode := &api.NodeInfo{
+		Name: "n2",
+		ResourceUsage: &api.NodeUsage{
+			CPUUsageAvg: map[string]float64{"5m": 20},
+			MEMUsageAvg: map[string]float64{"5m": 40},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      framework.Arguments
+		node      *api.NodeInfo
+		expected  float64
+		expectedF bool
+	}{
+		{name: "gpu", args: framework.Arguments{ScoreMode: ScoreModeGPU}, node: gpuNode, expected: 95, expectedF: true},
+		{name: "gpu not reported", args: framework.Arguments{ScoreMode: ScoreModeGPU}, node: cpuNode, expectedF: false},
+		{name: "weighted without gpu weight", args: framework.Arguments{ScoreMode: ScoreModeWeighted}, node: gpuNode, expected: 30, expectedF: true},
+		{
+			name:      "weighted with gpu",
+			args:      framework.Arguments{ScoreMode: ScoreModeWeighted, GPUScoreWeight: 2},
+			node:      gpuNode,
+			expected:  62.5,
+			expectedF: true,
+		},
+		{
+			name:      "weighted with gpu on node without gpu",
+			args:      framework.Arguments{ScoreMode: ScoreModeWeighted, GPUScoreWeight: 2},
+			node:      cpuNode,
+			expected:  30,
+			expectedF: true,
+		},
+	}
+
+	for _, test := range tests {
+		up := New(test.args).(*usagePlugin)
+		usage, found := up.usage(test.node, 0, 0)
+		if found != test.expectedF || usage != test.expected {
+			t.Errorf("%s: expected usage %v, %v, got %v, %v", test.name, test.expected, test.expectedF, usage, found)
+		}
+	}
+
+	up := New(framework.Arguments{UsageType: UsageTypeCommon}).(*usagePlugin)
+	if _, found := up.gpuUsage(&api.NodeUsage{SampleTime: time.Now()}, "5m"); found {
+		t.Errorf("expected latest gpu usage not reported by node without gpu")
+	}
+	if usage, found := up.gpuMemUsage(&api.NodeUsage{GPUMEMUsage: 50, GPUReported: true}, "5m"); !found || usage != 50 {
+		t.Errorf("expected latest gpu memory usage 50, got %v, %v", usage, found)
+	}
+}
+
+func TestStaleAction(t *testing.T) {
+	usages := map[string]*api.NodeUsage{
+		"fresh": {CPUUsageAvg: map[string]float64{"5m": 10}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: time.Now()},
+		"stale": api.StaleNodeUsage(time.Now().Add(-time.Hour)),
+		"busy":  {CPUUsageAvg: map[string]float64{"5m": 90}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: time.Now()},
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		mode     string
+		expected map[string]bool
+	}{
+		{name: "stale node is ignored by default", mode: ThresholdModeHard,
+			expected: map[string]bool{"fresh": true, "stale": true, "busy": false}},
+		{name: "stale node is filtered", action: StaleActionFilter, mode: ThresholdModeHard,
+			expected: map[string]bool{"fresh": true, "stale": false, "busy": false}},
+		{name: "stale node fails", action: StaleActionFail, mode: ThresholdModeHard,
+			expected: map[string]bool{"fresh": true, "stale": false, "busy": false}},
+		{name: "stale node is kept in soft mode", action: StaleActionFilter, mode: ThresholdModeSoft,
+			expected: map[string]bool{"fresh": true, "stale": true, "busy": true}},
+		{name: "stale node fails in soft mode", action: StaleActionFail, mode: ThresholdModeSoft,
+			expected: map[string]bool{"fresh": true, "stale": false, "busy": true}},
+		{name: "unknown action falls back to ignore", action: "retry", mode: ThresholdModeHard,
+			expected: map[string]bool{"fresh": true, "stale": true, "busy": false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := framework.Arguments{
+				ThresholdMode: test.mode,
+				Thresholds:    map[interface{}]interface{}{"cpu": map[interface{}]interface{}{"5m": 80}},
+			}
+			if test.action != "" {
+				args[StaleAction] = test.action
+			}
+			c := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				Arguments: map[string]framework.Arguments{PluginName: args},
+				PodGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupInqueue)},
+				Pods:      []*v1.Pod{util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", nil, nil)},
+				Nodes: []*v1.Node{
+					util.BuildNode("fresh", util.BuildResourceList("4", "8G"), nil),
+					util.BuildNode("stale", util.BuildResourceList("4", "8G"), nil),
+					util.BuildNode("busy", util.BuildResourceList("4", "8G"), nil),
+				},
+				Queues:     []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+				NodeUsages: usages,
+			}
+			defer c.Close()
+			if err := c.CheckPredicates(map[string]map[string]bool{"ns/p1": test.expected}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestQueueThresholds(t *testing.T) {
+	annotated := util.BuildNode("annotated", util.BuildResourceList("4", "8G"), nil)
+	annotated.Annotations = map[string]string{CPUThresholdAnnotation: "70"}
+	usage := func(cpu float64) *api.NodeUsage {
+		return &api.NodeUsage{CPUUsageAvg: map[string]float64{"5m": cpu}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: time.Now()}
+	}
+
+	c := uthelper.TestCommonStruct{
+		Name:    "queue thresholds",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		Arguments: map[string]framework.Arguments{PluginName: {
+			Thresholds: map[interface{}]interface{}{"cpu": map[interface{}]interface{}{"5m": 80}},
+			QueueThresholds: map[interface{}]interface{}{
+				"batch":   map[interface{}]interface{}{"cpu": 95},
+				"online":  map[interface{}]interface{}{"cpu": map[interface{}]interface{}{"5m": "50"}},
+				"invalid": "cpu",
+			},
+		}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-batch", "batch", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-online", "online", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-default", "default", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("ns", "batch", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg-batch", nil, nil),
+			util.BuildPod("ns", "online", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg-online", nil, nil),
+			util.BuildPod("ns", "default", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg-default", nil, nil),
+		},
+		Nodes: []*v1.Node{
+			util.BuildNode("hot", util.BuildResourceList("4", "8G"), nil),
+			util.BuildNode("warm", util.BuildResourceList("4", "8G"), nil),
+			util.BuildNode("cool", util.BuildResourceList("4", "8G"), nil),
+			annotated,
+		},
+		Queues: []*schedulingv1.Queue{
+			util.BuildQueue("batch", 1, nil),
+			util.BuildQueue("online", 1, nil),
+			util.BuildQueue("default", 1, nil),
+		},
+		NodeUsages: map[string]*api.NodeUsage{
+			"hot": usage(90), "warm": usage(60), "cool": usage(30), "annotated": usage(65),
+		},
+	}
+	defer c.Close()
+
+	expected := map[string]map[string]bool{
+		"ns/batch":   {"hot": true, "warm": true, "cool": true, "annotated": true},
+		"ns/online":  {"hot": false, "warm": false, "cool": true, "annotated": true},
+		"ns/default": {"hot": false, "warm": true, "cool": true, "annotated": true},
+	}
+	if err := c.CheckPredicates(expected); err != nil {
+		t.Error(err)
+	}
+
+	up := New(framework.Arguments{
+		ThresholdMode:   ThresholdModeSoft,
+		QueueThresholds: map[interface{}]interface{}{"online": map[interface{}]interface{}{"cpu": 50}},
+	}).(*usagePlugin)
+	up.nodeThresholds = map[string]thresholdConfig{}
+	up.exceeded = map[breachKey]bool{}
+	up.jobQueues = map[api.JobID]string{"ns/online": "online", "ns/batch": "batch"}
+	node := &api.NodeInfo{Name: "warm", ResourceUsage: usage(60)}
+	if score := up.score(&api.TaskInfo{Job: "ns/online"}, node); score != 0 {
+		t.Errorf("expected node over the threshold of queue scored 0 in soft mode, got %v", score)
+	}
+	if score := up.score(&api.TaskInfo{Job: "ns/batch"}, node); score != 40 {
+		t.Errorf("expected node scored 40 by usage for queue without thresholds, got %v", score)
+	}
+}
+
+func TestUsageMetrics(t *testing.T) {
+	c := uthelper.TestCommonStruct{
+		Name:    "usage metrics",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		Arguments: map[string]framework.Arguments{PluginName: {
+			Thresholds: map[interface{}]interface{}{
+				"cpu":    map[interface{}]interface{}{"5m": 80},
+				"memory": map[interface{}]interface{}{"1h": 60},
+			},
+		}},
+		PodGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupInqueue)},
+		Pods:      []*v1.Pod{util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", nil, nil)},
+		Nodes: []*v1.Node{
+			util.BuildNode("metrics-idle", util.BuildResourceList("4", "8G"), nil),
+			util.BuildNode("metrics-busy", util.BuildResourceList("4", "8G"), nil),
+		},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+		NodeUsages: map[string]*api.NodeUsage{
+			"metrics-idle": {CPUUsageAvg: map[string]float64{"5m": 10}, MEMUsageAvg: map[string]float64{"5m": 20, "1h": 30}, SampleTime: time.Now()},
+			"metrics-busy": {CPUUsageAvg: map[string]float64{"5m": 90}, MEMUsageAvg: map[string]float64{"5m": 20, "1h": 30}, SampleTime: time.Now()},
+		},
+	}
+	defer c.Close()
+	if err := c.CheckPredicates(map[string]map[string]bool{"ns/p1": {"metrics-idle": true, "metrics-busy": false}}); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["node_name"] != "metrics-idle" && labels["node_name"] != "metrics-busy" {
+				continue
+			}
+			key := family.GetName() + "/" + labels["node_name"] + "/" + labels["resource"] + "/" + labels["period"]
+			if metric.GetCounter() != nil {
+				values[key] = metric.GetCounter().GetValue()
+			} else {
+				values[key] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	expected := map[string]float64{
+		"volcano_node_usage_percentage/metrics-idle/cpu/5m":            10,
+		"volcano_node_usage_percentage/metrics-idle/memory/5m":         20,
+		"volcano_node_usage_percentage/metrics-idle/memory/1h":         30,
+		"volcano_node_usage_percentage/metrics-busy/cpu/5m":            90,
+		"volcano_node_usage_percentage/metrics-busy/memory/5m":         20,
+		"volcano_node_usage_percentage/metrics-busy/memory/1h":         30,
+		"volcano_node_usage_over_threshold/metrics-idle/cpu/5m":        0,
+		"volcano_node_usage_over_threshold/metrics-idle/memory/1h":     0,
+		"volcano_node_usage_over_threshold/metrics-busy/cpu/5m":        1,
+		"volcano_node_usage_over_threshold/metrics-busy/memory/1h":     0,
+		"volcano_usage_predicate_rejections_total/metrics-busy/cpu/5m": 1,
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected metrics %v, got %v", expected, values)
+	}
+}
+
+func TestEstimatePlacement(t *testing.T) {
+	now := time.Now()
+	usages := map[string]*api.NodeUsage{
+		"n1": {CPUUsageAvg: map[string]float64{"5m": 40}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: now,
+			PodUsages: map[string]*api.PodUsage{"ns/p0": {MilliCPU: 500, Memory: 1e8}}},
+		"n2": {CPUUsageAvg: map[string]float64{"5m": 70}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: now},
+	}
+
+	tests := []struct {
+		name       string
+		estimate   bool
+		predicates map[string]bool
+		scores     map[string]float64
+	}{
+		{name: "usages of nodes only", predicates: map[string]bool{"n1": true, "n2": true},
+			scores: map[string]float64{"n1": 60, "n2": 30}},
+		// The task is estimated to use 500m cpu as the running pod of its job, 12.5% of the nodes.
+		{name: "usages of nodes after placing the task", estimate: true, predicates: map[string]bool{"n1": true, "n2": false},
+			scores: map[string]float64{"n1": 47.5, "n2": 17.5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := uthelper.TestCommonStruct{
+				Name:    test.name,
+				Plugins: map[string]framework.PluginBuilder{PluginName: New},
+				Arguments: map[string]framework.Arguments{PluginName: {
+					EstimatePlacement: test.estimate,
+					Thresholds:        map[interface{}]interface{}{"cpu": map[interface{}]interface{}{"5m": 80}},
+				}},
+				PodGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupRunning)},
+				Pods: []*v1.Pod{
+					util.BuildPod("ns", "p0", "n1", v1.PodRunning, util.BuildResourceList("2", "1G"), "pg1", nil, nil),
+					util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("2", "1G"), "pg1", nil, nil),
+				},
+				Nodes: []*v1.Node{
+					util.BuildNode("n1", util.BuildResourceList("4", "8G"), nil),
+					util.BuildNode("n2", util.BuildResourceList("4", "8G"), nil),
+				},
+				Queues:     []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+				NodeUsages: usages,
+			}
+			defer c.Close()
+			if err := c.CheckPredicates(map[string]map[string]bool{"ns/p1": test.predicates}); err != nil {
+				t.Error(err)
+			}
+			if err := c.CheckScores(map[string]map[string]float64{"ns/p1": test.scores}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestPlacementEstimator(t *testing.T) {
+	job := api.NewJobInfo("j1")
+	running := &api.TaskInfo{UID: "t0", Job: "j1", Namespace: "ns", Name: "p0", Resreq: api.NewResource(util.BuildResourceList("2", "2G"))}
+	job.AddTaskInfo(running)
+	node := &api.NodeInfo{Name: "n1", Capacity: api.NewResource(util.BuildResourceList("4", "4G"))}
+	pe := &placementEstimator{
+		podUsages: map[string]*api.PodUsage{"ns/p0": {MilliCPU: 1000, Memory: 1e9}},
+		placed:    map[string]*api.PodUsage{},
+		tasks:     map[api.TaskID]placement{},
+		jobs:      map[api.JobID]*api.JobInfo{"j1": job},
+	}
+
+	task := &api.TaskInfo{UID: "t1", Job: "j1", Namespace: "ns", Name: "p1", Resreq: api.NewResource(util.BuildResourceList("2", "2G"))}
+	if cpu, mem := pe.extraUsage(task, node); cpu != 25 || mem != 25 {
+		t.Errorf("expected usages of the running pod of the job estimated, got %v and %v", cpu, mem)
+	}
+	other := &api.TaskInfo{UID: "t2", Job: "j2", Resreq: api.NewResource(util.BuildResourceList("2", "2G"))}
+	if cpu, mem := pe.extraUsage(other, node); cpu != 50 || mem != 50 {
+		t.Errorf("expected requests of the task of job without usages estimated, got %v and %v", cpu, mem)
+	}
+
+	task.Status, task.NodeName = api.Allocated, "n1"
+	pe.place(task)
+	pe.place(task)
+	if cpu, _ := pe.extraUsage(other, node); cpu != 75 {
+		t.Errorf("expected usage of the task placed added once, got %v", cpu)
+	}
+	running.NodeName = "n1"
+	pe.place(running)
+	pe.unplace(running)
+	pe.unplace(task)
+	if cpu, _ := pe.extraUsage(other, node); cpu != 50 {
+		t.Errorf("expected usage of the task unplaced subtracted, got %v", cpu)
+	}
+}
+
+func TestColocationGuard(t *testing.T) {
+	online := map[string]string{"workload-type": "online"}
+	usage := func(cpu float64) *api.NodeUsage {
+		return &api.NodeUsage{CPUUsageAvg: map[string]float64{"5m": cpu}, MEMUsageAvg: map[string]float64{"5m": 0}, SampleTime: time.Now()}
+	}
+
+	c := uthelper.TestCommonStruct{
+		Name:    "colocation guard",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		Arguments: map[string]framework.Arguments{PluginName: {
+			ColocationOnlineSelector:  "workload-type=online",
+			ColocationMaxOfflineRatio: 80,
+			ColocationMinOfflineRatio: 10,
+		}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-offline", "q1", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-online", "q1", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-running", "q1", 1, schedulingv1.PodGroupRunning),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("ns", "offline", "", v1.PodPending, util.BuildResourceList("2", "2G"), "pg-offline", nil, nil),
+			util.BuildPod("ns", "online", "", v1.PodPending, util.BuildResourceList("2", "2G"), "pg-online", online, nil),
+			// busy runs an online pod requesting 25% cpu and an offline pod requesting 25% cpu using 60% cpu in total,
+			// so online pods use 35% cpu and offline tasks may request 80% * 65% = 52% cpu.
+			util.BuildPod("ns", "online-busy", "busy", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg-running", online, nil),
+			util.BuildPod("ns", "offline-busy", "busy", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg-running", nil, nil),
+			// hot is used 95% by online pods, offline tasks may request 10% of it.
+			util.BuildPod("ns", "online-hot", "hot", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg-running", online, nil),
+		},
+		Nodes: []*v1.Node{
+			util.BuildNode("idle", util.BuildResourceList("4", "8G"), nil),
+			util.BuildNode("busy", util.BuildResourceList("4", "8G"), nil),
+			util.BuildNode("hot", util.BuildResourceList("4", "8G"), nil),
+		},
+		Queues:     []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+		NodeUsages: map[string]*api.NodeUsage{"idle": usage(0), "busy": usage(60), "hot": usage(95)},
+	}
+	defer c.Close()
+
+	expected := map[string]map[string]bool{
+		"ns/offline": {"idle": true, "busy": false, "hot": false},
+		"ns/online":  {"idle": true, "busy": true, "hot": true},
+	}
+	if err := c.CheckPredicates(expected); err != nil {
+		t.Error(err)
+	}
+	// The offline task requests 50% cpu and 25% memory of idle under the cap of 80%, the headroom of cpu
+	// 37.5% is added to the score by usage 100.
+	if err := c.CheckScores(map[string]map[string]float64{
+		"ns/offline": {"idle": 137.5},
+		"ns/online":  {"idle": 100},
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestColocationGuardOtherProfiles(t *testing.T) {
+	usage := func(cpu float64) *api.NodeUsage {
+		return &api.NodeUsage{CPUUsageAvg: map[string]float64{"5m": cpu}, MEMUsageAvg: map[string]float64{"5m": 0}, SampleTime: time.Now()}
+	}
+
+	c := uthelper.TestCommonStruct{
+		Name:    "colocation guard with jobs of other profiles",
+		Plugins: map[string]framework.PluginBuilder{PluginName: New},
+		Arguments: map[string]framework.Arguments{PluginName: {
+			ColocationOnlineSelector:  "workload-type=online",
+			ColocationMaxOfflineRatio: 80,
+		}},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("ns", "pg-offline", "q1", 1, schedulingv1.PodGroupInqueue),
+			util.BuildPodGroup("ns", "pg-other", "q1", 1, schedulingv1.PodGroupRunning),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("ns", "offline", "", v1.PodPending, util.BuildResourceList("1500m", "1G"), "pg-offline", nil, nil),
+			// the offline task of another profile requests 50% cpu and explains the usage of the node, so offline
+			// tasks may request 80% cpu in total and the pending one does not fit.
+			util.BuildPod("ns", "offline-other", "shared", v1.PodRunning, util.BuildResourceList("2", "1G"), "pg-other", nil, nil),
+		},
+		Nodes:      []*v1.Node{util.BuildNode("shared", util.BuildResourceList("4", "8G"), nil)},
+		Queues:     []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+		NodeUsages: map[string]*api.NodeUsage{"shared": usage(50)},
+		InProfile:  func(job *api.JobInfo) bool { return job.Name != "pg-other" },
+	}
+	defer c.Close()
+
+	if err := c.CheckPredicates(map[string]map[string]bool{"ns/offline": {"shared": false}}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDaemonWeight(t *testing.T) {
+	now := time.Now()
+	usages := map[string]*api.NodeUsage{
+		"n1": {CPUUsageAvg: map[string]float64{"5m": 40}, MEMUsageAvg: map[string]float64{"5m": 10}, CPUUsage: 50, MEMUsage: 10, SampleTime: now,
+			PodUsages: map[string]*api.PodUsage{"ns/ds": {MilliCPU: 1000, Memory: 1e8}, "ns/p0": {MilliCPU: 500, Memory: 1e8}}},
+		"n2": {CPUUsageAvg: map[string]float64{"5m": 30}, MEMUsageAvg: map[string]float64{"5m": 10}, SampleTime: now},
+	}
+	controller := true
+	daemon := util.BuildPod("ns", "ds", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "", nil, nil)
+	daemon.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &controller}}
+
+	tests := []struct {
+		name   string
+		weight interface{}
+		scores map[string]float64
+	}{
+		{name: "usages of daemonset pods counted by default", scores: map[string]float64{"n1": 60, "n2": 70}},
+		// The daemonset pod uses 1 cpu, 25% of the node and half of its latest usage, so the average is halved.
+		{name: "usages of daemonset pods left out", weight: 0, scores: map[string]float64{"n1": 80, "n2": 70}},
+		{name: "half usages of daemonset pods counted", weight: 0.5, scores: map[string]float64{"n1": 70, "n2": 70}},
+		{name: "invalid weight", weight: 2, scores: map[string]float64{"n1": 60, "n2": 70}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := framework.Arguments{}
+			if test.weight != nil {
+				args[DaemonWeight] = test.weight
+			}
+			c := uthelper.TestCommonStruct{
+				Name:      test.name,
+				Plugins:   map[string]framework.PluginBuilder{PluginName: New},
+				Arguments: map[string]framework.Arguments{PluginName: args},
+				PodGroups: []*schedulingv1.PodGroup{util.BuildPodGroup("ns", "pg1", "q1", 1, schedulingv1.PodGroupRunning)},
+				Pods: []*v1.Pod{
+					daemon,
+					util.BuildPod("ns", "p0", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", nil, nil),
+					util.BuildPod("ns", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", nil, nil),
+				},
+				Nodes: []*v1.Node{
+					util.BuildNode("n1", util.BuildResourceList("4", "8G"), nil),
+					util.BuildNode("n2", util.BuildResourceList("4", "8G"), nil),
+				},
+				Queues:     []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+				NodeUsages: usages,
+			}
+			defer c.Close()
+			if err := c.CheckScores(map[string]map[string]float64{"ns/p1": test.scores}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
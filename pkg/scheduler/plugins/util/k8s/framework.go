@@ -30,6 +30,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework/parallelize"
 
 	scheduling "volcano.sh/volcano/pkg/scheduler/capabilities/volumebinding"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
 // Framework is a K8S framework who mainly provides some methods
@@ -149,7 +150,7 @@ func (f *Framework) Extenders() []framework.Extender {
 }
 
 func (f *Framework) Parallelizer() parallelize.Parallelizer {
-	return parallelize.NewParallelizer(16)
+	return parallelize.NewParallelizer(util.GetParallelism())
 }
 
 // NewFrameworkHandle creates a FrameworkHandle interface, which is used by k8s plugins.
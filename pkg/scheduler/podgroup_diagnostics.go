@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// maxPodGroupDiagnoses is the number of podgroups whose last scheduling failures are kept.
+const maxPodGroupDiagnoses = 1000
+
+// podGroupDiagnosis is the last scheduling failure of a podgroup.
+type podGroupDiagnosis struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Queue     string    `json:"queue"`
+	Time      time.Time `json:"time"`
+	// Message is the fit error of the job in the session, if any.
+	Message string                    `json:"message,omitempty"`
+	Plugins []framework.PluginFailure `json:"plugins"`
+}
+
+// podGroupDiagnostics keeps the last predicate failures of each plugin for the podgroups failing to be
+// scheduled, so they are inspected without raising the log verbosity. All methods are no-op on nil
+// podGroupDiagnostics.
+type podGroupDiagnostics struct {
+	// authorizer scopes the podgroups served to the queues of the user, nil if not enabled.
+	authorizer *queueAuthorizer
+
+	mutex     sync.Mutex
+	diagnoses map[string]*podGroupDiagnosis
+}
+
+func newPodGroupDiagnostics() *podGroupDiagnostics {
+	return &podGroupDiagnostics{diagnoses: map[string]*podGroupDiagnosis{}}
+}
+
+// record keeps the failures of the jobs in the session, and forgets the jobs without pending tasks.
+// The jobs not failing in the session keep their last failures.
+func (pd *podGroupDiagnostics) record(ssn *framework.Session) {
+	if pd == nil {
+		return
+	}
+	now := time.Now()
+	pd.mutex.Lock()
+	defer pd.mutex.Unlock()
+	for _, job := range ssn.Jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+		key := job.Namespace + "/" + job.Name
+		if len(job.TaskStatusIndex[api.Pending]) == 0 {
+			delete(pd.diagnoses, key)
+			continue
+		}
+		failures := ssn.PredicateFailures(job.UID)
+		if len(failures) == 0 && len(job.JobFitErrors) == 0 {
+			continue
+		}
+		pd.diagnoses[key] = &podGroupDiagnosis{
+			Namespace: job.Namespace,
+			Name:      job.Name,
+			Queue:     string(job.Queue),
+			Time:      now,
+			Message:   job.JobFitErrors,
+			Plugins:   failures,
+		}
+	}
+
+	// Forget the podgroups failed the longest time ago, e.g. deleted ones.
+	for len(pd.diagnoses) > maxPodGroupDiagnoses {
+		var oldest string
+		for key, diagnosis := range pd.diagnoses {
+			if len(oldest) == 0 || diagnosis.Time.Before(pd.diagnoses[oldest].Time) {
+				oldest = key
+			}
+		}
+		delete(pd.diagnoses, oldest)
+	}
+}
+
+// ServeHTTP serves the last failures of the podgroups in JSON, filtered by the namespace and name parameters,
+// e.g. `kubectl get --raw '/apis/debug.scheduling.volcano.sh/v1alpha1/podgroupdiagnostics?namespace=default&name=job-1'`.
+// Only the podgroups of the queues the user may get are served if the queues are authorized.
+func (pd *podGroupDiagnostics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.URL.Query().Get("namespace"), r.URL.Query().Get("name")
+	diagnoses := []*podGroupDiagnosis{}
+	if pd != nil {
+		visible, err := pd.authorizer.authorize(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		pd.mutex.Lock()
+		for _, diagnosis := range pd.diagnoses {
+			if (len(namespace) == 0 || diagnosis.Namespace == namespace) && (len(name) == 0 || diagnosis.Name == name) {
+				diagnoses = append(diagnoses, diagnosis)
+			}
+		}
+		pd.mutex.Unlock()
+
+		// The accesses are reviewed out of mutex, as they may call the apiserver.
+		allowed := diagnoses[:0]
+		for _, diagnosis := range diagnoses {
+			if visible(diagnosis.Queue) {
+				allowed = append(allowed, diagnosis)
+			}
+		}
+		diagnoses = allowed
+	}
+	sort.Slice(diagnoses, func(i, j int) bool {
+		if diagnoses[i].Namespace != diagnoses[j].Namespace {
+			return diagnoses[i].Namespace < diagnoses[j].Namespace
+		}
+		return diagnoses[i].Name < diagnoses[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diagnoses); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+type failingPlugin struct {
+	fail *bool
+}
+
+func (fp *failingPlugin) Name() string { return "failing" }
+
+func (fp *failingPlugin) OnSessionOpen(ssn *framework.Session) {
+	ssn.AddPredicateFn(fp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if !*fp.fail {
+			return nil, nil
+		}
+		return []*api.Status{{Code: api.Unschedulable, Reason: "node(s) are failing"}}, fmt.Errorf("failing")
+	})
+}
+
+func (fp *failingPlugin) OnSessionClose(ssn *framework.Session) {}
+
+func TestPodGroupDiagnostics(t *testing.T) {
+	fail := true
+	framework.RegisterPluginBuilder("failing", func(framework.Arguments) framework.Plugin {
+		return &failingPlugin{fail: &fail}
+	})
+	defer framework.CleanupPluginBuilders()
+	options.ServerOpts = &options.ServerOption{
+		MinNodesToFind:             100,
+		MinPercentageOfNodesToFind: 5,
+		PercentageOfNodesToFind:    100,
+	}
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		Binder:        &util.FakeBinder{Binds: map[string]string{}, Channel: make(chan string, 10)},
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+		Recorder:      record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("2", "4Gi"), make(map[string]string)))
+	schedulerCache.AddPod(util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1",
+		make(map[string]string), make(map[string]string)))
+	schedulerCache.AddPodGroupV1beta1(&schedulingv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "c1"},
+		Spec:       schedulingv1.PodGroupSpec{Queue: "c1", MinMember: 1},
+		Status:     schedulingv1.PodGroupStatus{Phase: schedulingv1.PodGroupInqueue},
+	})
+	schedulerCache.AddQueueV1beta1(&schedulingv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+		Spec:       schedulingv1.QueueSpec{Weight: 1},
+	})
+
+	trueValue := true
+	tiers := []conf.Tier{{Plugins: []conf.PluginOption{{Name: "failing", EnabledPredicate: &trueValue}}}}
+	diagnostics := newPodGroupDiagnostics()
+	serve := func(query string) []*podGroupDiagnosis {
+		recorder := httptest.NewRecorder()
+		diagnostics.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/podgroups/diagnostics"+query, nil))
+		var diagnoses []*podGroupDiagnosis
+		if err := json.Unmarshal(recorder.Body.Bytes(), &diagnoses); err != nil {
+			t.Fatalf("failed to decode diagnoses %q: %v", recorder.Body.String(), err)
+		}
+		return diagnoses
+	}
+
+	runSessions(newDryRunCache(schedulerCache), []framework.Action{allocate.New()}, tiers, nil, nil, nil, nil, diagnostics)
+	diagnoses := serve("?namespace=c1&name=pg1")
+	if len(diagnoses) != 1 {
+		t.Fatalf("expected diagnosis of podgroup c1/pg1, got %v", diagnoses)
+	}
+	expected := []framework.PluginFailure{{Plugin: "failing", Nodes: 1, Task: "c1/p1", Node: "n1", Reason: "node(s) are failing"}}
+	if !reflect.DeepEqual(diagnoses[0].Plugins, expected) {
+		t.Errorf("expected plugin failures %v, got %v", expected, diagnoses[0].Plugins)
+	}
+	if diagnoses := serve("?namespace=c2"); len(diagnoses) != 0 {
+		t.Errorf("expected no diagnosis in namespace c2, got %v", diagnoses)
+	}
+
+	fail = false
+	runSessions(newDryRunCache(schedulerCache), []framework.Action{allocate.New()}, tiers, nil, nil, nil, nil, diagnostics)
+	if diagnoses := serve(""); len(diagnoses) != 0 {
+		t.Errorf("expected podgroup scheduled to be forgotten, got %v", diagnoses)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+)
+
+// queueDecisionTTL is the duration the reviews of queue accesses are cached.
+const queueDecisionTTL = 10 * time.Second
+
+var (
+	// The headers the apiserver passes the user it proxies in, the defaults of --requestheader-username-headers,
+	// --requestheader-group-headers and --requestheader-extra-headers-prefix of the apiserver set by kubeadm.
+	requestHeaderUsernames     = []string{"X-Remote-User"}
+	requestHeaderGroups        = []string{"X-Remote-Group"}
+	requestHeaderExtraPrefixes = []string{"X-Remote-Extra-"}
+)
+
+// queueAccess is the cached review of the access of a user to a queue, the empty queue for all queues.
+type queueAccess struct {
+	user  string
+	queue string
+}
+
+// queueDecision is a cached review.
+type queueDecision struct {
+	allowed bool
+	expire  time.Time
+}
+
+// queueAuthorizer scopes the data served by the debug endpoints to the queues of the user: the debug endpoints are
+// served to the apiserver, which authenticates the users and passes them in the headers of the requests it proxies
+// to the scheduler, and the data of a queue is only served to the users allowed to get the queue. The credentials of
+// the users are never received by the scheduler. All methods allow all queues on nil queueAuthorizer.
+type queueAuthorizer struct {
+	// authenticator returns the user of a request, once the client certificate of the apiserver is verified.
+	authenticator authenticator.Request
+	client        kubernetes.Interface
+
+	mutex     sync.Mutex
+	decisions map[queueAccess]queueDecision
+}
+
+// newQueueAuthorizer returns the queueAuthorizer of the requests proxied by the apiserver, whose client certificate
+// is verified by the CA in clientCAFile, and has one of allowedNames as common name if any.
+func newQueueAuthorizer(client kubernetes.Interface, clientCAFile string, allowedNames []string) (*queueAuthorizer, error) {
+	requestHeader, err := headerrequest.NewSecure(clientCAFile, allowedNames,
+		requestHeaderUsernames, requestHeaderGroups, requestHeaderExtraPrefixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the requests proxied by the apiserver: %v", err)
+	}
+	return &queueAuthorizer{
+		authenticator: requestHeader,
+		client:        client,
+		decisions:     map[queueAccess]queueDecision{},
+	}, nil
+}
+
+// authorize authenticates the user of the request, and returns whether the user may see the data of a queue; the
+// empty queue stands for the data of all queues, e.g. the session profiles.
+func (qa *queueAuthorizer) authorize(r *http.Request) (func(queue string) bool, error) {
+	if qa == nil {
+		return func(string) bool { return true }, nil
+	}
+	response, found, err := qa.authenticator.AuthenticateRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate the request proxied by the apiserver: %v", err)
+	}
+	// The requests without the client certificate of the apiserver are not authenticated either.
+	if !found {
+		return nil, fmt.Errorf("the request is not proxied by the apiserver with a user")
+	}
+	// The decisions are cached per user, groups and extra, as they are reviewed for all of them.
+	key := fmt.Sprintf("%s/%v/%v", response.User.GetName(), response.User.GetGroups(), response.User.GetExtra())
+	return func(queue string) bool {
+		return qa.allowed(r.Context(), key, response.User, queue)
+	}, nil
+}
+
+// allowed returns whether the user may get the queue, or list all queues for the empty queue. A failed review
+// denies the access.
+func (qa *queueAuthorizer) allowed(ctx context.Context, key string, user user.Info, queue string) bool {
+	access := queueAccess{user: key, queue: queue}
+	now := time.Now()
+	qa.mutex.Lock()
+	qa.expireLocked(now)
+	decision, found := qa.decisions[access]
+	qa.mutex.Unlock()
+	if found {
+		return decision.allowed
+	}
+
+	attributes := &authorizationv1.ResourceAttributes{
+		Verb:     "get",
+		Group:    scheduling.GroupName,
+		Resource: "queues",
+		Name:     queue,
+	}
+	if len(queue) == 0 {
+		attributes.Verb = "list"
+	}
+	extra := map[string]authorizationv1.ExtraValue{}
+	for name, values := range user.GetExtra() {
+		extra[name] = authorizationv1.ExtraValue(values)
+	}
+	review, err := qa.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+			User:               user.GetName(),
+			UID:                user.GetUID(),
+			Groups:             user.GetGroups(),
+			Extra:              extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to review the access of user %s to queue %q: %v", user.GetName(), queue, err)
+		return false
+	}
+
+	qa.mutex.Lock()
+	qa.decisions[access] = queueDecision{allowed: review.Status.Allowed, expire: now.Add(queueDecisionTTL)}
+	qa.mutex.Unlock()
+	return review.Status.Allowed
+}
+
+// expireLocked forgets the reviews expired, it is called with mutex held.
+func (qa *queueAuthorizer) expireLocked(now time.Time) {
+	for access, decision := range qa.decisions {
+		if !now.Before(decision.expire) {
+			delete(qa.decisions, access)
+		}
+	}
+}
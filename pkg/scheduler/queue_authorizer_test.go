@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestQueueAuthorizer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	reviews := 0
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = len(review.Spec.Groups) == 1 && review.Spec.Groups[0] == "admins" ||
+			review.Spec.User == "alice" && attributes.Verb == "get" && attributes.Name == "q1"
+		return true, review, nil
+	})
+
+	// The client certificate of the apiserver is verified by newQueueAuthorizer, the users are taken from the headers
+	// of the requests here.
+	requestHeader, err := headerrequest.New(requestHeaderUsernames, requestHeaderGroups, requestHeaderExtraPrefixes)
+	if err != nil {
+		t.Fatalf("failed to authenticate request headers: %v", err)
+	}
+	authorizer := &queueAuthorizer{authenticator: requestHeader, client: client, decisions: map[queueAccess]queueDecision{}}
+	scheduler := &Scheduler{diagnostics: newPodGroupDiagnostics(), profiler: newSessionProfiler(time.Second)}
+	scheduler.diagnostics.authorizer = authorizer
+	scheduler.profiler.authorizer = authorizer
+	scheduler.diagnostics.diagnoses["c1/pg1"] = &podGroupDiagnosis{Namespace: "c1", Name: "pg1", Queue: "q1", Time: time.Now()}
+	scheduler.diagnostics.diagnoses["c2/pg2"] = &podGroupDiagnosis{Namespace: "c2", Name: "pg2", Queue: "q2", Time: time.Now()}
+	handler := scheduler.DebugHandler()
+
+	serve := func(resource, user, group string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/apis/"+DebugGroupVersion+resource, nil)
+		if len(user) != 0 {
+			request.Header.Set("X-Remote-User", user)
+		}
+		if len(group) != 0 {
+			request.Header.Set("X-Remote-Group", group)
+		}
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	podGroups := func(recorder *httptest.ResponseRecorder) []string {
+		var diagnoses []*podGroupDiagnosis
+		if err := json.Unmarshal(recorder.Body.Bytes(), &diagnoses); err != nil {
+			t.Fatalf("failed to decode diagnoses %q: %v", recorder.Body.String(), err)
+		}
+		names := []string{}
+		for _, diagnosis := range diagnoses {
+			names = append(names, diagnosis.Name)
+		}
+		return names
+	}
+
+	if recorder := serve("", "", ""); recorder.Code != http.StatusOK {
+		t.Errorf("expected the group version discovered, got code %d", recorder.Code)
+	}
+	tests := []struct {
+		name     string
+		user     string
+		group    string
+		code     int
+		expected []string
+		profiles int
+	}{
+		{name: "no user", code: http.StatusUnauthorized, profiles: http.StatusUnauthorized},
+		{name: "queue user", user: "alice", code: http.StatusOK, expected: []string{"pg1"}, profiles: http.StatusForbidden},
+		{name: "admin", user: "bob", group: "admins", code: http.StatusOK, expected: []string{"pg1", "pg2"}, profiles: http.StatusOK},
+	}
+	for _, test := range tests {
+		recorder := serve("/podgroupdiagnostics", test.user, test.group)
+		if recorder.Code != test.code {
+			t.Errorf("%s: expected code %d of diagnostics, got %d", test.name, test.code, recorder.Code)
+			continue
+		}
+		if test.code == http.StatusOK {
+			if names := podGroups(recorder); len(names) != len(test.expected) || names[0] != test.expected[0] {
+				t.Errorf("%s: expected podgroups %v, got %v", test.name, test.expected, names)
+			}
+		}
+		if recorder := serve("/sessionprofiles", test.user, test.group); recorder.Code != test.profiles {
+			t.Errorf("%s: expected code %d of profiles, got %d", test.name, test.profiles, recorder.Code)
+		}
+	}
+
+	// The reviews are cached.
+	before := reviews
+	serve("/podgroupdiagnostics", "alice", "")
+	if reviews != before {
+		t.Errorf("expected the reviews of alice to be cached, got %d reviews more", reviews-before)
+	}
+}
+
+func TestQueueAuthorizerRequiresApiserver(t *testing.T) {
+	cert, _, err := certutil.GenerateSelfSignedCertKey("apiserver-proxy-ca", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, cert, 0600); err != nil {
+		t.Fatalf("failed to write CA: %v", err)
+	}
+	authorizer, err := newQueueAuthorizer(fake.NewSimpleClientset(), caFile, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The users passed without the client certificate of the apiserver are not trusted.
+	request := httptest.NewRequest("GET", "/apis/"+DebugGroupVersion+"/podgroupdiagnostics", nil)
+	request.Header.Set("X-Remote-User", "admin")
+	if _, err := authorizer.authorize(request); err == nil {
+		t.Errorf("expected the request without client certificate rejected")
+	}
+}
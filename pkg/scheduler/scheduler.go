@@ -17,24 +17,34 @@ limitations under the License.
 package scheduler
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/cmd/scheduler/app/options"
 	"volcano.sh/volcano/pkg/filewatcher"
+	"volcano.sh/volcano/pkg/scheduler/api"
 	schedcache "volcano.sh/volcano/pkg/scheduler/cache"
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/scheduler/metrics/source"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
+// DebugGroupVersion is the API group version the debug endpoints are served as through the apiserver.
+const DebugGroupVersion = "debug.scheduling.volcano.sh/v1alpha1"
+
 // Scheduler watches for new unscheduled pods for volcano. It attempts to find
 // nodes that they fit on and writes bindings back to the api server.
 type Scheduler struct {
@@ -48,8 +58,29 @@ type Scheduler struct {
 	actions        []framework.Action
 	plugins        []conf.Tier
 	configurations []conf.Configuration
+	profiles       []*schedulerProfile
 	metricsConf    map[string]string
 	dumper         schedcache.Dumper
+	// profiler keeps the CPU profiles of slow sessions, nil if not enabled.
+	profiler *sessionProfiler
+	// diagnostics keeps the last scheduling failures of podgroups.
+	diagnostics *podGroupDiagnostics
+	// maintenanceWindows pause actions and freeze queues in recurring windows.
+	maintenanceWindows []*maintenanceWindow
+	// dryRun compares the reloaded configuration with the active one in dry-run sessions.
+	dryRun bool
+	// running is set once the cache is synced and sessions are run.
+	running bool
+	// sessionMutex serializes the sessions, including the dry-run ones, whose metrics are suppressed.
+	sessionMutex sync.Mutex
+}
+
+// schedulerProfile is a named scheduling profile, which schedules the jobs selecting it in its own session.
+type schedulerProfile struct {
+	name           string
+	actions        []framework.Action
+	plugins        []conf.Tier
+	configurations []conf.Configuration
 }
 
 // NewScheduler returns a scheduler
@@ -78,20 +109,66 @@ func NewScheduler(
 		cache:          cache,
 		schedulePeriod: period,
 		dumper:         schedcache.Dumper{Cache: cache},
+		diagnostics:    newPodGroupDiagnostics(),
+	}
+	if options.ServerOpts != nil && options.ServerOpts.SlowSessionProfileThreshold > 0 {
+		scheduler.profiler = newSessionProfiler(options.ServerOpts.SlowSessionProfileThreshold)
+	}
+	if options.ServerOpts != nil {
+		scheduler.dryRun = options.ServerOpts.EnableConfigDryRun
+		framework.SetSessionConfig(framework.SessionConfig{
+			InvariantCheck:       options.ServerOpts.EnableInvariantCheck,
+			ProfilingLabels:      options.ServerOpts.EnableProfilingLabels,
+			PluginLatencyMetrics: options.ServerOpts.EnablePluginLatencyMetrics,
+		})
+	}
+	if options.ServerOpts != nil && options.ServerOpts.EnableDebugQueueAuthorization {
+		authorizer, err := newQueueAuthorizer(kubernetes.NewForConfigOrDie(config),
+			options.ServerOpts.RequestHeaderClientCAFile, options.ServerOpts.RequestHeaderAllowedNames)
+		if err != nil {
+			return nil, err
+		}
+		scheduler.diagnostics.authorizer = authorizer
+		if scheduler.profiler != nil {
+			scheduler.profiler.authorizer = authorizer
+		}
 	}
 
 	return scheduler, nil
 }
 
+// DebugHandler serves the debug endpoints as the API group version DebugGroupVersion, to the apiserver proxying the
+// users to them by an APIService: the CPU profiles of slow sessions at sessionprofiles, and the last scheduling
+// failures of podgroups at podgroupdiagnostics.
+func (pc *Scheduler) DebugHandler() http.Handler {
+	prefix := "/apis/" + DebugGroupVersion
+	mux := http.NewServeMux()
+	// The group version is discovered by the apiserver, without resources, so that the endpoints are not taken for
+	// resources by the clients discovering all resources, e.g. the garbage collector.
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: DebugGroupVersion,
+			APIResources: []metav1.APIResource{},
+		})
+	})
+	mux.Handle(prefix+"/sessionprofiles", pc.profiler)
+	mux.Handle(prefix+"/podgroupdiagnostics", pc.diagnostics)
+	return mux
+}
+
 // Run runs the Scheduler
 func (pc *Scheduler) Run(stopCh <-chan struct{}) {
 	pc.loadSchedulerConf()
 	go pc.watchSchedulerConf(stopCh)
 	// Start cache for policy.
-	pc.cache.SetMetricsConf(pc.metricsConf)
 	pc.cache.Run(stopCh)
 	pc.cache.WaitForCacheSync(stopCh)
 	klog.V(2).Infof("scheduler completes Initialization and start to run")
+	pc.mutex.Lock()
+	pc.running = true
+	pc.mutex.Unlock()
 	go wait.Until(pc.runOnce, pc.schedulePeriod, stopCh)
 	if options.ServerOpts.EnableCacheDumper {
 		pc.dumper.ListenForSignal(stopCh)
@@ -107,25 +184,76 @@ func (pc *Scheduler) runOnce() {
 	actions := pc.actions
 	plugins := pc.plugins
 	configurations := pc.configurations
+	profiles := pc.profiles
+	maintenanceWindows := pc.maintenanceWindows
 	pc.mutex.Unlock()
+	defer func() {
+		metrics.UpdateE2eDuration(metrics.Duration(scheduleStartTime))
+	}()
 
+	pc.sessionMutex.Lock()
+	defer pc.sessionMutex.Unlock()
+	runSessions(pc.cache, actions, plugins, configurations, profiles, maintenanceWindows, pc.profiler, pc.diagnostics)
+}
+
+// runSessions runs the session of the default profile and the sessions of the profiles on the cache,
+// the actions and queues paused by the active maintenance windows are skipped in all the sessions.
+func runSessions(cache schedcache.Cache, actions []framework.Action, plugins []conf.Tier,
+	configurations []conf.Configuration, profiles []*schedulerProfile, maintenanceWindows []*maintenanceWindow,
+	profiler *sessionProfiler, diagnostics *podGroupDiagnostics) {
+	paused := activeMaintenance(maintenanceWindows, time.Now())
+	// The default profile schedules the jobs without a known scheduling profile.
+	var inDefaultProfile func(*api.JobInfo) bool
+	if len(profiles) != 0 {
+		profileNames := map[string]struct{}{}
+		for _, profile := range profiles {
+			profileNames[profile.name] = struct{}{}
+		}
+		inDefaultProfile = func(job *api.JobInfo) bool {
+			_, found := profileNames[job.SchedulingProfile]
+			return !found
+		}
+	}
+	runSession(cache, actions, plugins, configurations, "", inDefaultProfile, paused, profiler, diagnostics)
+
+	for _, profile := range profiles {
+		name := profile.name
+		klog.V(4).Infof("Start scheduling profile %s ...", name)
+		runSession(cache, profile.actions, profile.plugins, profile.configurations, name, func(job *api.JobInfo) bool {
+			return job.SchedulingProfile == name
+		}, paused, profiler, diagnostics)
+	}
+}
+
+// runSession runs the actions in a session of the profile, empty for the default one, of the jobs accepted by inProfile.
+func runSession(cache schedcache.Cache, actions []framework.Action, plugins []conf.Tier,
+	configurations []conf.Configuration, profile string, inProfile func(*api.JobInfo) bool, paused *maintenance,
+	profiler *sessionProfiler, diagnostics *podGroupDiagnostics) {
 	//Load configmap to check which action is enabled.
 	conf.EnabledActionMap = make(map[string]bool)
 	for _, action := range actions {
 		conf.EnabledActionMap[action.Name()] = true
 	}
 
-	ssn := framework.OpenSession(pc.cache, plugins, configurations)
-	defer func() {
-		framework.CloseSession(ssn)
-		metrics.UpdateE2eDuration(metrics.Duration(scheduleStartTime))
-	}()
+	capture := profiler.begin()
+	defer profiler.end(capture)
+
+	ssn := framework.OpenProfileSession(cache, plugins, configurations, profile, inProfile)
+	defer framework.CloseSession(ssn)
+	paused.freezeQueues(ssn)
 
 	for _, action := range actions {
+		if paused.actionPaused(action.Name()) {
+			klog.V(3).Infof("Action %s is paused by maintenance window", action.Name())
+			continue
+		}
 		actionStartTime := time.Now()
-		action.Execute(ssn)
-		metrics.UpdateActionDuration(action.Name(), metrics.Duration(actionStartTime))
+		ssn.ExecuteAction(action)
+		actionDuration := metrics.Duration(actionStartTime)
+		metrics.UpdateActionDuration(action.Name(), actionDuration)
+		ssn.RecordActionDuration(action.Name(), actionDuration)
 	}
+	diagnostics.record(ssn)
 }
 
 func (pc *Scheduler) loadSchedulerConf() {
@@ -155,17 +283,57 @@ func (pc *Scheduler) loadSchedulerConf() {
 
 	actions, plugins, configurations, metricsConf, err := unmarshalSchedulerConf(config)
 	if err != nil {
-		klog.Errorf("scheduler config %s is invalid: %v", config, err)
+		klog.Errorf("scheduler config %s is invalid: %v", pc.schedulerConf, err)
+		return
+	}
+	profiles, err := unmarshalSchedulerProfiles(config)
+	if err != nil {
+		klog.Errorf("scheduler config %s has invalid profiles: %v", pc.schedulerConf, err)
+		return
+	}
+	evictionConf, err := unmarshalEvictionConf(config)
+	if err != nil {
+		klog.Errorf("scheduler config %s has invalid eviction configuration: %v", pc.schedulerConf, err)
 		return
 	}
+	maintenanceWindows, err := unmarshalMaintenanceWindows(config)
+	if err != nil {
+		klog.Errorf("scheduler config %s has invalid maintenance windows: %v", pc.schedulerConf, err)
+		return
+	}
+	parallelism, err := unmarshalParallelism(config)
+	if err != nil {
+		klog.Errorf("scheduler config %s has invalid parallelism: %v", pc.schedulerConf, err)
+		return
+	}
+	if source.Enabled(metricsConf) {
+		if _, err := source.NewProvider(metricsConf); err != nil {
+			klog.Warningf("scheduler config %s has invalid metrics configuration: %v", pc.schedulerConf, err)
+		}
+	}
+
+	pc.mutex.Lock()
+	running := pc.running
+	pc.mutex.Unlock()
+	if pc.dryRun && running {
+		pc.dryRunConf(actions, plugins, configurations, profiles, maintenanceWindows)
+	}
 
 	pc.mutex.Lock()
 	// If it is valid, use the new configuration
 	pc.actions = actions
 	pc.plugins = plugins
 	pc.configurations = configurations
+	pc.profiles = profiles
+	pc.maintenanceWindows = maintenanceWindows
+	for _, profile := range profiles {
+		klog.V(2).Infof("Loaded scheduling profile %s with %d actions", profile.name, len(profile.actions))
+	}
 	pc.metricsConf = metricsConf
 	pc.mutex.Unlock()
+	pc.cache.SetEvictionConf(evictionConf)
+	pc.cache.SetMetricsConf(metricsConf)
+	util.SetParallelism(parallelism)
 }
 
 func (pc *Scheduler) getSchedulerConf() (actions []string, plugins []string) {
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxSlowSessionProfiles is the number of the latest slow session profiles kept.
+	maxSlowSessionProfiles = 5
+	// maxCPUProfileSeconds bounds the duration of the CPU profiles of /debug/pprof/profile, 30 seconds by default
+	// of pprof. The CPU profiler is held for the whole duration, so sessions are not profiled meanwhile.
+	maxCPUProfileSeconds = 30
+)
+
+// sessionProfile is the CPU profile of a slow session.
+type sessionProfile struct {
+	start    time.Time
+	duration time.Duration
+	data     []byte
+}
+
+// sessionProfiler profiles the CPU of each session, and keeps the profiles of the sessions
+// taking longer than threshold. All methods are no-op on nil sessionProfiler.
+type sessionProfiler struct {
+	threshold time.Duration
+	// authorizer serves the profiles only to the users allowed to list all queues, nil if not enabled.
+	authorizer *queueAuthorizer
+
+	mutex    sync.Mutex
+	profiles []*sessionProfile
+}
+
+// sessionCapture is the CPU profile of a session in progress.
+type sessionCapture struct {
+	start time.Time
+	buf   bytes.Buffer
+}
+
+func newSessionProfiler(threshold time.Duration) *sessionProfiler {
+	return &sessionProfiler{threshold: threshold}
+}
+
+// begin starts the CPU profile of a session, it returns nil if the CPU is profiled already,
+// e.g. by /debug/pprof/profile.
+func (sp *sessionProfiler) begin() *sessionCapture {
+	if sp == nil {
+		return nil
+	}
+	capture := &sessionCapture{start: time.Now()}
+	if err := pprof.StartCPUProfile(&capture.buf); err != nil {
+		klog.V(4).Infof("Skip profiling session: %v", err)
+		return nil
+	}
+	return capture
+}
+
+// end stops the CPU profile of the session, and keeps it if the session took longer than threshold.
+func (sp *sessionProfiler) end(capture *sessionCapture) {
+	if sp == nil || capture == nil {
+		return
+	}
+	pprof.StopCPUProfile()
+	duration := time.Since(capture.start)
+	if duration <= sp.threshold {
+		return
+	}
+
+	klog.V(3).Infof("Session started at %v took %v longer than %v, keep its CPU profile",
+		capture.start, duration, sp.threshold)
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+	sp.profiles = append(sp.profiles, &sessionProfile{start: capture.start, duration: duration, data: capture.buf.Bytes()})
+	if len(sp.profiles) > maxSlowSessionProfiles {
+		sp.profiles = sp.profiles[len(sp.profiles)-maxSlowSessionProfiles:]
+	}
+}
+
+// ServeHTTP lists the slow session profiles kept, or serves the one selected by the index parameter,
+// e.g. `kubectl get --raw '/apis/debug.scheduling.volcano.sh/v1alpha1/sessionprofiles?index=0' > session.pprof`.
+func (sp *sessionProfiler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if sp == nil {
+		http.Error(w, "slow session profiling is disabled, set --slow-session-profile-threshold to enable it", http.StatusNotFound)
+		return
+	}
+	visible, err := sp.authorizer.authorize(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if !visible("") {
+		http.Error(w, "the session profiles are only served to the users allowed to list all queues", http.StatusForbidden)
+		return
+	}
+
+	sp.mutex.Lock()
+	profiles := sp.profiles
+	sp.mutex.Unlock()
+
+	value := r.URL.Query().Get("index")
+	if len(value) == 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "index\tstart\tduration\n")
+		for i, profile := range profiles {
+			fmt.Fprintf(w, "%d\t%s\t%v\n", i, profile.start.Format(time.RFC3339), profile.duration)
+		}
+		return
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 || index >= len(profiles) {
+		http.Error(w, fmt.Sprintf("invalid index %q of %d profiles", value, len(profiles)), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"session-%d.pprof\"", index))
+	w.Write(profiles[index].data)
+}
+
+// BoundCPUProfiles serves handler, rejecting the requests of /debug/pprof/profile for longer than
+// maxCPUProfileSeconds.
+func BoundCPUProfiles(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/debug/pprof/profile" {
+			if value := r.URL.Query().Get("seconds"); len(value) != 0 {
+				seconds, err := strconv.Atoi(value)
+				if err != nil || seconds <= 0 || seconds > maxCPUProfileSeconds {
+					http.Error(w, fmt.Sprintf("invalid seconds %q, CPU profiles are at most %d seconds", value, maxCPUProfileSeconds),
+						http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
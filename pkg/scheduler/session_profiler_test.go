@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionProfiler(t *testing.T) {
+	sp := newSessionProfiler(time.Hour)
+	sp.end(sp.begin())
+	if len(sp.profiles) != 0 {
+		t.Fatalf("expected profile of fast session dropped, got %d profiles", len(sp.profiles))
+	}
+
+	sp.threshold = 0
+	for i := 0; i < maxSlowSessionProfiles+2; i++ {
+		capture := sp.begin()
+		if capture == nil {
+			t.Fatalf("expected session profiled")
+		}
+		time.Sleep(time.Millisecond)
+		sp.end(capture)
+	}
+	if len(sp.profiles) != maxSlowSessionProfiles {
+		t.Fatalf("expected %d profiles kept, got %d", maxSlowSessionProfiles, len(sp.profiles))
+	}
+
+	recorder := httptest.NewRecorder()
+	sp.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/sessions/profiles", nil))
+	if lines := strings.Count(recorder.Body.String(), "\n"); lines != maxSlowSessionProfiles+1 {
+		t.Errorf("expected %d lines of profile list, got %q", maxSlowSessionProfiles+1, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	sp.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/sessions/profiles?index=0", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.Len() == 0 {
+		t.Errorf("expected profile served, got code %d with %d bytes", recorder.Code, recorder.Body.Len())
+	}
+
+	recorder = httptest.NewRecorder()
+	sp.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/sessions/profiles?index=9", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request for invalid index, got code %d", recorder.Code)
+	}
+
+	var disabled *sessionProfiler
+	recorder = httptest.NewRecorder()
+	disabled.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/sessions/profiles", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected not found when disabled, got code %d", recorder.Code)
+	}
+}
+
+func TestBoundCPUProfiles(t *testing.T) {
+	served := false
+	handler := BoundCPUProfiles(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	}))
+
+	tests := []struct {
+		url    string
+		served bool
+	}{
+		{url: "/debug/pprof/profile", served: true},
+		{url: "/debug/pprof/profile?seconds=10", served: true},
+		{url: "/debug/pprof/profile?seconds=3600"},
+		{url: "/debug/pprof/profile?seconds=x"},
+		{url: "/debug/pprof/trace?seconds=3600", served: true},
+	}
+	for _, test := range tests {
+		served = false
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if served != test.served {
+			t.Errorf("expected %s served %v, got %v with code %d", test.url, test.served, served, recorder.Code)
+		}
+	}
+}
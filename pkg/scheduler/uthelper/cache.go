@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uthelper
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// FakeCache is the scheduler cache of a test case: it is filled with the objects of the case, and records
+// the decisions of sessions, i.e. bindings, evictions and podgroup phases, instead of calling the API server.
+type FakeCache struct {
+	*cache.SchedulerCache
+
+	mutex    sync.Mutex
+	binds    map[string]string
+	evicts   map[string]string
+	phases   map[string]string
+	recorder *fakeRecorder
+}
+
+// NewFakeCache returns an empty fake cache.
+func NewFakeCache() *FakeCache {
+	recorder := &fakeRecorder{}
+	return &FakeCache{
+		SchedulerCache: &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Binder:        &util.FakeBinder{Binds: map[string]string{}},
+			StatusUpdater: &util.FakeStatusUpdater{},
+			VolumeBinder:  &util.FakeVolumeBinder{},
+
+			Recorder: recorder,
+		},
+		binds:    map[string]string{},
+		evicts:   map[string]string{},
+		phases:   map[string]string{},
+		recorder: recorder,
+	}
+}
+
+// AddBindTask records the node the task is bound to.
+func (fc *FakeCache) AddBindTask(task *api.TaskInfo) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	fc.binds[taskKey(task)] = task.NodeName
+	return nil
+}
+
+// Evict records the reason the task is evicted for.
+func (fc *FakeCache) Evict(task *api.TaskInfo, reason string) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	fc.evicts[taskKey(task)] = reason
+	return nil
+}
+
+// UpdateJobStatus records the phase of the podgroup of the job and its events.
+func (fc *FakeCache) UpdateJobStatus(job *api.JobInfo, updatePG bool) (*api.JobInfo, error) {
+	if job.PodGroup != nil {
+		fc.mutex.Lock()
+		fc.phases[fmt.Sprintf("%s/%s", job.Namespace, job.Name)] = string(job.PodGroup.Status.Phase)
+		fc.mutex.Unlock()
+	}
+	fc.RecordJobStatusEvent(job)
+	return job, nil
+}
+
+// BindPodGroup does nothing in tests.
+func (fc *FakeCache) BindPodGroup(job *api.JobInfo, cluster string) error {
+	return nil
+}
+
+// UpdateQueueStatus does nothing in tests.
+func (fc *FakeCache) UpdateQueueStatus(queue *api.QueueInfo) error {
+	return nil
+}
+
+// Binds returns the nodes tasks are bound to, keyed by namespace/name of tasks.
+func (fc *FakeCache) Binds() map[string]string {
+	return fc.copy(fc.binds)
+}
+
+// Evicts returns the reasons tasks are evicted for, keyed by namespace/name of tasks.
+func (fc *FakeCache) Evicts() map[string]string {
+	return fc.copy(fc.evicts)
+}
+
+// PodGroupPhases returns the phases podgroups are updated to, keyed by namespace/name of podgroups.
+func (fc *FakeCache) PodGroupPhases() map[string]string {
+	return fc.copy(fc.phases)
+}
+
+// Events returns the events recorded, in the format `<type> <reason> <message>` of record.FakeRecorder.
+func (fc *FakeCache) Events() []string {
+	return fc.recorder.list()
+}
+
+func (fc *FakeCache) copy(values map[string]string) map[string]string {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
+
+func taskKey(task *api.TaskInfo) string {
+	return fmt.Sprintf("%s/%s", task.Namespace, task.Name)
+}
+
+// fakeRecorder keeps all the events recorded, unlike record.FakeRecorder it never blocks.
+type fakeRecorder struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+var _ record.EventRecorder = &fakeRecorder{}
+
+func (fr *fakeRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+	fr.events = append(fr.events, fmt.Sprintf("%s %s %s", eventtype, reason, message))
+}
+
+func (fr *fakeRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	fr.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (fr *fakeRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	fr.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (fr *fakeRecorder) list() []string {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+	return append([]string{}, fr.events...)
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uthelper helps in-tree and out-of-tree plugins and actions write table-driven tests: a test case
+// describes the cluster and the plugins, runs sessions on a fake cache and checks the decisions, predicates
+// and scores against the expected ones, without an API server.
+package uthelper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// scoreTolerance is the tolerance of comparing scores
+const scoreTolerance = 1e-6
+
+// TestCommonStruct is a test case of plugins and actions: the cluster, the plugins of the session and the
+// decisions expected. Expected decisions left nil are not checked.
+type TestCommonStruct struct {
+	Name string
+	// Plugins are the builders of the plugins registered for the case, keyed by plugin name.
+	Plugins map[string]framework.PluginBuilder
+	// Arguments are the arguments of Plugins, keyed by plugin name.
+	Arguments map[string]framework.Arguments
+	// Tiers are the plugins of the session, Plugins are enabled in one tier with Arguments if empty.
+	Tiers          []conf.Tier
+	Configurations []conf.Configuration
+	// InProfile accepts the jobs of the scheduling profile of the session, the other jobs are left to other
+	// profiles. All the jobs are accepted if it is nil.
+	InProfile func(*api.JobInfo) bool
+
+	PodGroups []*schedulingv1.PodGroup
+	Pods      []*v1.Pod
+	Nodes     []*v1.Node
+	Queues    []*schedulingv1.Queue
+	// NodeUsages are the usages of Nodes collected from the metrics source, keyed by node names.
+	NodeUsages map[string]*api.NodeUsage
+
+	// ExpectBindMap are the nodes tasks are bound to, keyed by namespace/name of tasks.
+	ExpectBindMap map[string]string
+	// ExpectBindsNum is the number of tasks bound, for cases where which tasks are bound does not matter.
+	ExpectBindsNum int
+	// ExpectEvicted are the namespace/name of tasks evicted.
+	ExpectEvicted []string
+	// ExpectPhases are the phases podgroups are updated to, keyed by namespace/name of podgroups.
+	ExpectPhases map[string]schedulingv1.PodGroupPhase
+	// ExpectEvents are substrings of the events recorded, each one must be found in some event.
+	ExpectEvents []string
+
+	cache *FakeCache
+	ssn   *framework.Session
+}
+
+// Open registers the plugins, fills a fake cache with the objects of the case and opens a session on it.
+func (test *TestCommonStruct) Open() *framework.Session {
+	if test.ssn != nil {
+		return test.ssn
+	}
+	if options.ServerOpts == nil {
+		options.ServerOpts = &options.ServerOption{
+			MinNodesToFind:             100,
+			MinPercentageOfNodesToFind: 5,
+			PercentageOfNodesToFind:    100,
+		}
+	}
+	for name, builder := range test.Plugins {
+		framework.RegisterPluginBuilder(name, builder)
+	}
+
+	test.cache = NewFakeCache()
+	for _, node := range test.Nodes {
+		test.cache.AddNode(node)
+	}
+	for name, usage := range test.NodeUsages {
+		if node, found := test.cache.Nodes[name]; found {
+			node.ResourceUsage = usage
+		}
+	}
+	for _, pod := range test.Pods {
+		test.cache.AddPod(pod)
+	}
+	for _, pg := range test.PodGroups {
+		test.cache.AddPodGroupV1beta1(pg)
+	}
+	for _, queue := range test.Queues {
+		test.cache.AddQueueV1beta1(queue)
+	}
+
+	tiers := test.Tiers
+	if len(tiers) == 0 {
+		tiers = []conf.Tier{{Plugins: test.pluginOptions()}}
+	}
+	test.ssn = framework.OpenProfileSession(test.cache, tiers, test.Configurations, "", test.InProfile)
+	return test.ssn
+}
+
+// pluginOptions enables the plugins of the case sorted by name, with the extension points enabled by default
+// in scheduler configuration, i.e. all of them but hierarchy.
+func (test *TestCommonStruct) pluginOptions() []conf.PluginOption {
+	var names []string
+	for name := range test.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	enabled := true
+	var plugins []conf.PluginOption
+	for _, name := range names {
+		plugin := conf.PluginOption{Name: name, Arguments: test.Arguments[name]}
+		value := reflect.ValueOf(&plugin).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if strings.HasPrefix(field.Name, "Enabled") && field.Name != "EnabledHierarchy" {
+				value.Field(i).Set(reflect.ValueOf(&enabled))
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
+// Run executes the actions in a session and closes it, so that the decisions made at closing, e.g. the phases
+// of podgroups, are recorded.
+func (test *TestCommonStruct) Run(actions ...framework.Action) {
+	ssn := test.Open()
+	for _, action := range actions {
+		action.Execute(ssn)
+	}
+	framework.CloseSession(ssn)
+	test.ssn = nil
+}
+
+// Close closes the session if it is open and cleans up the plugins registered.
+func (test *TestCommonStruct) Close() {
+	if test.ssn != nil {
+		framework.CloseSession(test.ssn)
+		test.ssn = nil
+	}
+	framework.CleanupPluginBuilders()
+}
+
+// Cache returns the fake cache of the case, it is nil until the session is opened.
+func (test *TestCommonStruct) Cache() *FakeCache {
+	return test.cache
+}
+
+// CheckAll checks the bindings, evictions, podgroup phases and events expected by the case.
+func (test *TestCommonStruct) CheckAll() error {
+	if test.cache == nil {
+		return fmt.Errorf("case %s: session is never opened", test.Name)
+	}
+	if test.ExpectBindMap != nil {
+		if binds := test.cache.Binds(); !reflect.DeepEqual(test.ExpectBindMap, binds) {
+			return fmt.Errorf("case %s: expected binds %v, got %v", test.Name, test.ExpectBindMap, binds)
+		}
+	}
+	if test.ExpectBindsNum != 0 {
+		if binds := test.cache.Binds(); len(binds) != test.ExpectBindsNum {
+			return fmt.Errorf("case %s: expected %d binds, got %v", test.Name, test.ExpectBindsNum, binds)
+		}
+	}
+	if test.ExpectEvicted != nil {
+		var evicted []string
+		for key := range test.cache.Evicts() {
+			evicted = append(evicted, key)
+		}
+		expected := append([]string{}, test.ExpectEvicted...)
+		sort.Strings(evicted)
+		sort.Strings(expected)
+		if len(evicted) != len(expected) || (len(expected) != 0 && !reflect.DeepEqual(expected, evicted)) {
+			return fmt.Errorf("case %s: expected evicted %v, got %v", test.Name, expected, evicted)
+		}
+	}
+	if test.ExpectPhases != nil {
+		phases := test.cache.PodGroupPhases()
+		for key, phase := range test.ExpectPhases {
+			if phases[key] != string(phase) {
+				return fmt.Errorf("case %s: expected podgroup %s in phase %s, got %q", test.Name, key, phase, phases[key])
+			}
+		}
+	}
+	events := test.cache.Events()
+	for _, expected := range test.ExpectEvents {
+		if !containsEvent(events, expected) {
+			return fmt.Errorf("case %s: expected event %q, got %v", test.Name, expected, events)
+		}
+	}
+	return nil
+}
+
+func containsEvent(events []string, expected string) bool {
+	for _, event := range events {
+		if strings.Contains(event, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPredicates checks whether the tasks fit the nodes by the predicates of the open session, expected
+// is keyed by namespace/name of tasks and then by node names.
+func (test *TestCommonStruct) CheckPredicates(expected map[string]map[string]bool) error {
+	ssn := test.Open()
+	for key, nodes := range expected {
+		task, err := test.task(key)
+		if err != nil {
+			return err
+		}
+		for nodeName, fit := range nodes {
+			node, found := ssn.Nodes[nodeName]
+			if !found {
+				return fmt.Errorf("case %s: node %s not found", test.Name, nodeName)
+			}
+			statuses, err := ssn.PredicateFn(task, node)
+			statusSets := util.StatusSets(statuses)
+			actual := err == nil && !statusSets.ContainsUnschedulable() && !statusSets.ContainsUnschedulableAndUnresolvable()
+			if actual != fit {
+				return fmt.Errorf("case %s: expected task %s fits node %s %v, got %v: %v", test.Name, key, nodeName, fit, actual, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckScores checks the scores of the tasks on the nodes by the node order functions of the open session,
+// expected is keyed by namespace/name of tasks and then by node names.
+func (test *TestCommonStruct) CheckScores(expected map[string]map[string]float64) error {
+	ssn := test.Open()
+	for key, nodes := range expected {
+		task, err := test.task(key)
+		if err != nil {
+			return err
+		}
+		for nodeName, score := range nodes {
+			node, found := ssn.Nodes[nodeName]
+			if !found {
+				return fmt.Errorf("case %s: node %s not found", test.Name, nodeName)
+			}
+			actual, err := ssn.NodeOrderFn(task, node)
+			if err != nil {
+				return fmt.Errorf("case %s: task %s on node %s has err %v", test.Name, key, nodeName, err)
+			}
+			if math.Abs(actual-score) > scoreTolerance {
+				return fmt.Errorf("case %s: expected score of task %s on node %s %v, got %v", test.Name, key, nodeName, score, actual)
+			}
+		}
+	}
+	return nil
+}
+
+// task returns the task of the open session by namespace/name.
+func (test *TestCommonStruct) task(key string) (*api.TaskInfo, error) {
+	for _, job := range test.ssn.Jobs {
+		for _, task := range job.Tasks {
+			if taskKey(task) == key {
+				return task, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("case %s: task %s not found", test.Name, key)
+}
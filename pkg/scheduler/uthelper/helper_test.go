@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uthelper
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	schedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/scheduler/actions/allocate"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/binpack"
+	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+// zonePlugin only places tasks labeled with a zone on nodes of the zone, like an out-of-tree plugin.
+type zonePlugin struct{}
+
+func (zp *zonePlugin) Name() string {
+	return "zone"
+}
+
+func (zp *zonePlugin) OnSessionOpen(ssn *framework.Session) {
+	ssn.AddPredicateFn(zp.Name(), func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		if zone, found := task.Pod.Labels["zone"]; found && node.Node.Labels["zone"] != zone {
+			return nil, fmt.Errorf("node %s is out of zone %s", node.Name, zone)
+		}
+		return nil, nil
+	})
+}
+
+func (zp *zonePlugin) OnSessionClose(ssn *framework.Session) {}
+
+func TestRunActions(t *testing.T) {
+	tests := []TestCommonStruct{
+		{
+			Name:    "gang job fits",
+			Plugins: map[string]framework.PluginBuilder{gang.PluginName: gang.New},
+			PodGroups: []*schedulingv1.PodGroup{
+				util.BuildPodGroup("c1", "pg1", "q1", 2, schedulingv1.PodGroupInqueue),
+			},
+			Pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+				util.BuildPod("c1", "p2", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+			},
+			Nodes:  []*v1.Node{util.BuildNode("n1", util.BuildResourceList("2", "4Gi"), map[string]string{})},
+			Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+			ExpectBindMap: map[string]string{
+				"c1/p1": "n1",
+				"c1/p2": "n1",
+			},
+			ExpectEvicted: []string{},
+		},
+		{
+			Name:    "gang job does not fit",
+			Plugins: map[string]framework.PluginBuilder{gang.PluginName: gang.New},
+			PodGroups: []*schedulingv1.PodGroup{
+				util.BuildPodGroup("c1", "pg1", "q1", 3, schedulingv1.PodGroupInqueue),
+			},
+			Pods: []*v1.Pod{
+				util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+				util.BuildPod("c1", "p2", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+				util.BuildPod("c1", "p3", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{}, map[string]string{}),
+			},
+			Nodes:         []*v1.Node{util.BuildNode("n1", util.BuildResourceList("2", "4Gi"), map[string]string{})},
+			Queues:        []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+			ExpectBindMap: map[string]string{},
+			ExpectPhases:  map[string]schedulingv1.PodGroupPhase{"c1/pg1": schedulingv1.PodGroupInqueue},
+			ExpectEvents:  []string{"Unschedulable"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			defer test.Close()
+			test.Run(allocate.New())
+			if err := test.CheckAll(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestCheckPredicatesAndScores(t *testing.T) {
+	test := TestCommonStruct{
+		Name: "zone and binpack",
+		Plugins: map[string]framework.PluginBuilder{
+			"zone":             func(framework.Arguments) framework.Plugin { return &zonePlugin{} },
+			binpack.PluginName: binpack.New,
+		},
+		Arguments: map[string]framework.Arguments{
+			binpack.PluginName: {"binpack.weight": 1},
+		},
+		PodGroups: []*schedulingv1.PodGroup{
+			util.BuildPodGroup("c1", "pg1", "q1", 1, schedulingv1.PodGroupInqueue),
+		},
+		Pods: []*v1.Pod{
+			util.BuildPod("c1", "p1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", map[string]string{"zone": "a"}, map[string]string{}),
+			util.BuildPod("c1", "p2", "n2", v1.PodRunning, util.BuildResourceList("2", "2G"), "pg1", map[string]string{}, map[string]string{}),
+		},
+		Nodes: []*v1.Node{
+			util.BuildNode("n1", util.BuildResourceList("4", "4G"), map[string]string{"zone": "a"}),
+			util.BuildNode("n2", util.BuildResourceList("4", "4G"), map[string]string{"zone": "b"}),
+		},
+		Queues: []*schedulingv1.Queue{util.BuildQueue("q1", 1, nil)},
+	}
+	defer test.Close()
+
+	if err := test.CheckPredicates(map[string]map[string]bool{
+		"c1/p1": {"n1": true, "n2": false},
+	}); err != nil {
+		t.Error(err)
+	}
+	// binpack scores the cpu and memory of n1 by 1/4 and of n2 by 3/4 after placing p1, out of 100
+	if err := test.CheckScores(map[string]map[string]float64{
+		"c1/p1": {"n1": 25, "n2": 75},
+	}); err != nil {
+		t.Error(err)
+	}
+	if err := test.CheckScores(map[string]map[string]float64{
+		"c1/p1": {"n1": 50},
+	}); err == nil {
+		t.Errorf("expected error of unexpected score")
+	}
+}
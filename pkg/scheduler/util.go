@@ -19,13 +19,19 @@ package scheduler
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
 	"volcano.sh/volcano/pkg/scheduler/plugins"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
 var defaultSchedulerConf = `
@@ -44,15 +50,32 @@ tiers:
 `
 
 func unmarshalSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, []conf.Configuration, map[string]string, error) {
-	var actions []framework.Action
-
 	schedulerConf := &conf.SchedulerConfiguration{}
 
 	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
 		return nil, nil, nil, nil, err
 	}
+	unknownOptions, err := unknownPluginOptions(confStr)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for _, option := range unknownOptions {
+		klog.Warningf("Unknown option %s is ignored", option)
+	}
+	actions, err := parseActionsAndTiers(schedulerConf.Actions, schedulerConf.Tiers)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return actions, schedulerConf.Tiers, schedulerConf.Configurations, schedulerConf.MetricsConfiguration, nil
+}
+
+// parseActionsAndTiers returns the actions in actionNames, and sets default settings for plugins in tiers.
+func parseActionsAndTiers(actionNames string, tiers []conf.Tier) ([]framework.Action, error) {
+	var actions []framework.Action
+
 	// Set default settings for each plugin if not set
-	for i, tier := range schedulerConf.Tiers {
+	for i, tier := range tiers {
 		// drf with hierarchy enabled
 		hdrf := false
 		// proportion enabled
@@ -66,23 +89,166 @@ func unmarshalSchedulerConf(confStr string) ([]framework.Action, []conf.Tier, []
 			if tier.Plugins[j].Name == "proportion" {
 				proportion = true
 			}
-			plugins.ApplyPluginConfDefaults(&schedulerConf.Tiers[i].Plugins[j])
+			if !framework.ValidScoreNormalization(tier.Plugins[j].ScoreNormalization) {
+				return nil, fmt.Errorf("invalid scoreNormalization %s of plugin %s",
+					tier.Plugins[j].ScoreNormalization, tier.Plugins[j].Name)
+			}
+			plugins.ApplyPluginConfDefaults(&tiers[i].Plugins[j])
+			if err := convertPluginArguments(&tiers[i].Plugins[j]); err != nil {
+				return nil, err
+			}
 		}
 		if hdrf && proportion {
-			return nil, nil, nil, nil, fmt.Errorf("proportion and drf with hierarchy enabled conflicts")
+			return nil, fmt.Errorf("proportion and drf with hierarchy enabled conflicts")
 		}
 	}
 
-	actionNames := strings.Split(schedulerConf.Actions, ",")
-	for _, actionName := range actionNames {
+	for _, actionName := range strings.Split(actionNames, ",") {
 		if action, found := framework.GetAction(strings.TrimSpace(actionName)); found {
 			actions = append(actions, action)
 		} else {
-			return nil, nil, nil, nil, fmt.Errorf("failed to find Action %s, ignore it", actionName)
+			return nil, fmt.Errorf("failed to find Action %s, ignore it", actionName)
 		}
 	}
 
-	return actions, schedulerConf.Tiers, schedulerConf.Configurations, schedulerConf.MetricsConfiguration, nil
+	return actions, nil
+}
+
+// convertPluginArguments converts the arguments of the plugin into its latest apiVersion, and logs and counts
+// the deprecation warnings in metrics, so that legacy arguments keep working while their formats evolve.
+func convertPluginArguments(option *conf.PluginOption) error {
+	apiVersion, arguments, warnings, err := framework.ConvertArguments(option.Name, option.APIVersion, option.Arguments)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		klog.Warningf("Deprecated arguments of plugin %s: %s", option.Name, warning)
+	}
+	if len(warnings) != 0 {
+		metrics.RegisterDeprecatedPluginArguments(option.Name, len(warnings))
+	}
+	option.APIVersion, option.Arguments = apiVersion, arguments
+	return nil
+}
+
+// unmarshalSchedulerProfiles returns the named profiles in the scheduler configuration.
+func unmarshalSchedulerProfiles(confStr string) ([]*schedulerProfile, error) {
+	schedulerConf := &conf.SchedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
+		return nil, err
+	}
+
+	var profiles []*schedulerProfile
+	names := map[string]struct{}{}
+	for _, profile := range schedulerConf.Profiles {
+		if len(profile.Name) == 0 {
+			return nil, fmt.Errorf("name of scheduling profile is empty")
+		}
+		if _, found := names[profile.Name]; found {
+			return nil, fmt.Errorf("duplicated scheduling profile %s", profile.Name)
+		}
+		names[profile.Name] = struct{}{}
+
+		actions, err := parseActionsAndTiers(profile.Actions, profile.Tiers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduling profile %s: %v", profile.Name, err)
+		}
+		profiles = append(profiles, &schedulerProfile{
+			name:           profile.Name,
+			actions:        actions,
+			plugins:        profile.Tiers,
+			configurations: profile.Configurations,
+		})
+	}
+	return profiles, nil
+}
+
+// unmarshalEvictionConf returns the configuration of evicting victims, it defaults to delete victims directly.
+func unmarshalEvictionConf(confStr string) (conf.EvictionConfiguration, error) {
+	schedulerConf := &conf.SchedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
+		return conf.EvictionConfiguration{}, err
+	}
+
+	eviction := schedulerConf.Eviction
+	switch eviction.Mode {
+	case "":
+		eviction.Mode = conf.EvictionModeDelete
+	case conf.EvictionModeDelete, conf.EvictionModeEviction:
+	default:
+		return conf.EvictionConfiguration{}, fmt.Errorf("invalid eviction mode %s, it must be %s or %s",
+			eviction.Mode, conf.EvictionModeDelete, conf.EvictionModeEviction)
+	}
+	if eviction.GracePeriodSeconds != nil && *eviction.GracePeriodSeconds < 0 {
+		return conf.EvictionConfiguration{}, fmt.Errorf("invalid eviction gracePeriodSeconds %d, it must not be negative",
+			*eviction.GracePeriodSeconds)
+	}
+	if len(eviction.PipelineTimeout) != 0 {
+		if timeout, err := time.ParseDuration(eviction.PipelineTimeout); err != nil || timeout < 0 {
+			return conf.EvictionConfiguration{}, fmt.Errorf("invalid eviction pipelineTimeout %s, it must be a non-negative duration",
+				eviction.PipelineTimeout)
+		}
+	}
+	return eviction, nil
+}
+
+// unmarshalParallelism returns the number of goroutines predicating and scoring nodes in parallel,
+// it defaults to util.DefaultParallelism.
+func unmarshalParallelism(confStr string) (int, error) {
+	schedulerConf := &conf.SchedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(confStr), schedulerConf); err != nil {
+		return 0, err
+	}
+	if schedulerConf.Parallelism < 0 {
+		return 0, fmt.Errorf("invalid parallelism %d, it must not be negative", schedulerConf.Parallelism)
+	}
+	if schedulerConf.Parallelism == 0 {
+		return util.DefaultParallelism, nil
+	}
+	return schedulerConf.Parallelism, nil
+}
+
+// unknownPluginOptions returns the options of plugins in tiers that are not known,
+// so that a misspelled toggle of registered functions is not ignored silently.
+func unknownPluginOptions(confStr string) ([]string, error) {
+	type rawTier struct {
+		Plugins []map[string]interface{} `yaml:"plugins"`
+	}
+	rawConf := struct {
+		Tiers    []rawTier `yaml:"tiers"`
+		Profiles []struct {
+			Tiers []rawTier `yaml:"tiers"`
+		} `yaml:"profiles"`
+	}{}
+	if err := yaml.Unmarshal([]byte(confStr), &rawConf); err != nil {
+		return nil, err
+	}
+
+	knownOptions := map[string]struct{}{}
+	optionType := reflect.TypeOf(conf.PluginOption{})
+	for i := 0; i < optionType.NumField(); i++ {
+		name := strings.Split(optionType.Field(i).Tag.Get("yaml"), ",")[0]
+		knownOptions[name] = struct{}{}
+	}
+
+	tiers := rawConf.Tiers
+	for _, profile := range rawConf.Profiles {
+		tiers = append(tiers, profile.Tiers...)
+	}
+	var unknown []string
+	for _, tier := range tiers {
+		for _, plugin := range tier.Plugins {
+			var options []string
+			for option := range plugin {
+				if _, found := knownOptions[option]; !found {
+					options = append(options, fmt.Sprintf("%s of plugin %v", option, plugin["name"]))
+				}
+			}
+			sort.Strings(options)
+			unknown = append(unknown, options...)
+		}
+	}
+	return unknown, nil
 }
 
 func readSchedulerConf(confPath string) (string, error) {
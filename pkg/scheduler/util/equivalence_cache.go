@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// equivalenceKey is a predicate result of the tasks of a template on a node.
+type equivalenceKey struct {
+	hash uint64
+	node string
+}
+
+// equivalenceResult is the predicate result of a node of the generation.
+type equivalenceResult struct {
+	generation uint64
+	err        error
+}
+
+// equivalenceCache keeps the predicate results of the tasks of the same template on nodes, keyed by the hash of
+// template, so that the siblings of a task, e.g. thousands of identical workers, reuse the result on a node instead
+// of running the identical predicates again. A result is reused only if the node is of the same generation, i.e.
+// it is not changed since, e.g. by allocating a task on it.
+type equivalenceCache struct {
+	mutex   sync.RWMutex
+	results map[equivalenceKey]equivalenceResult
+}
+
+func newEquivalenceCache() *equivalenceCache {
+	return &equivalenceCache{results: map[equivalenceKey]equivalenceResult{}}
+}
+
+// get returns the predicate result of the tasks of hash on node, nil if it is not kept or the node is changed since.
+func (ec *equivalenceCache) get(hash uint64, node *api.NodeInfo) *equivalenceResult {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+	result, found := ec.results[equivalenceKey{hash: hash, node: node.Name}]
+	if !found || result.generation != node.Generation() {
+		return nil
+	}
+	return &result
+}
+
+// set keeps the predicate result of the tasks of hash on the node of generation.
+func (ec *equivalenceCache) set(hash uint64, node string, generation uint64, err error) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	ec.results[equivalenceKey{hash: hash, node: node}] = equivalenceResult{generation: generation, err: err}
+}
+
+// taskTemplate is the part of task the predicates depend on, the tasks of the same template fit the same nodes.
+type taskTemplate struct {
+	Namespace         string
+	Labels            map[string]string
+	Annotations       map[string]string
+	NodeSelector      map[string]string
+	NodeAffinity      *v1.NodeAffinity
+	Tolerations       []v1.Toleration
+	Volumes           []v1.Volume
+	Containers        []containerTemplate
+	InitContainers    []containerTemplate
+	Overhead          v1.ResourceList
+	PriorityClassName string
+	RuntimeClassName  *string
+	HostNetwork       bool
+	InitResreq        *api.Resource
+	Preemptable       bool
+	RevocableZone     string
+}
+
+type containerTemplate struct {
+	Resources v1.ResourceRequirements
+	Ports     []v1.ContainerPort
+}
+
+// taskEquivalenceHash returns the hash of the template of task, and false if the predicate results of the task
+// can not be reused by other tasks: the results of pod affinity and topology spread depend on the pods on other
+// nodes, and the numa-aware predicate keeps the numa assignment of each task.
+func taskEquivalenceHash(task *api.TaskInfo) (uint64, bool) {
+	pod := task.Pod
+	if pod == nil || len(pod.Spec.TopologySpreadConstraints) != 0 {
+		return 0, false
+	}
+	affinity := pod.Spec.Affinity
+	if affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil) {
+		return 0, false
+	}
+	if task.NumaInfo != nil && len(task.NumaInfo.Policy) != 0 && task.NumaInfo.Policy != "none" {
+		return 0, false
+	}
+
+	template := taskTemplate{
+		Namespace:         pod.Namespace,
+		Labels:            pod.Labels,
+		Annotations:       pod.Annotations,
+		NodeSelector:      pod.Spec.NodeSelector,
+		Tolerations:       pod.Spec.Tolerations,
+		Volumes:           pod.Spec.Volumes,
+		Overhead:          pod.Spec.Overhead,
+		PriorityClassName: pod.Spec.PriorityClassName,
+		RuntimeClassName:  pod.Spec.RuntimeClassName,
+		HostNetwork:       pod.Spec.HostNetwork,
+		InitResreq:        task.InitResreq,
+		Preemptable:       task.Preemptable,
+		RevocableZone:     task.RevocableZone,
+	}
+	if affinity != nil {
+		template.NodeAffinity = affinity.NodeAffinity
+	}
+	for _, container := range pod.Spec.Containers {
+		template.Containers = append(template.Containers, containerTemplate{Resources: container.Resources, Ports: container.Ports})
+	}
+	for _, container := range pod.Spec.InitContainers {
+		template.InitContainers = append(template.InitContainers, containerTemplate{Resources: container.Resources, Ports: container.Ports})
+	}
+
+	data, err := json.Marshal(&template)
+	if err != nil {
+		klog.V(4).Infof("Failed to hash the template of task <%s/%s>: %v", task.Namespace, task.Name, err)
+		return 0, false
+	}
+	hash := fnv.New64a()
+	hash.Write(data)
+	return hash.Sum64(), true
+}
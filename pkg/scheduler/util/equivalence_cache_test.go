@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/cmd/scheduler/app/options"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestEquivalenceCache(t *testing.T) {
+	options.ServerOpts = &options.ServerOption{
+		MinNodesToFind:             100,
+		MinPercentageOfNodesToFind: 5,
+		PercentageOfNodesToFind:    100,
+	}
+	var nodes []*api.NodeInfo
+	for i := 0; i < 3; i++ {
+		nodes = append(nodes, api.NewNodeInfo(BuildNode(fmt.Sprintf("n%d", i), BuildResourceList("4", "8Gi"), nil)))
+	}
+	var calls int32
+	predicate := func(task *api.TaskInfo, node *api.NodeInfo) ([]*api.Status, error) {
+		atomic.AddInt32(&calls, 1)
+		if node.Name == "n2" {
+			return nil, fmt.Errorf("node n2 is tainted")
+		}
+		return nil, nil
+	}
+	task := func(name string) *api.TaskInfo {
+		return api.NewTaskInfo(BuildPod("c1", name, "", v1.PodPending, BuildResourceList("1", "1Gi"), "pg1", nil, nil))
+	}
+
+	ph := NewPredicateHelper()
+	predicateNodes, fitErrors := ph.PredicateNodes(task("p1"), nodes, predicate, true)
+	if len(predicateNodes) != 2 || calls != 3 {
+		t.Fatalf("expected p1 to fit 2 of 3 nodes with 3 predicates, got %d nodes with %d predicates", len(predicateNodes), calls)
+	}
+	if fitErrors.Error() == "" {
+		t.Errorf("expected fit error of n2")
+	}
+
+	// The sibling of p1 reuses the results.
+	calls = 0
+	if predicateNodes, _ := ph.PredicateNodes(task("p2"), nodes, predicate, true); len(predicateNodes) != 2 || calls != 0 {
+		t.Errorf("expected p2 to fit 2 nodes reusing results of p1, got %d nodes with %d predicates", len(predicateNodes), calls)
+	}
+
+	// The result of a node changed is not reused.
+	if err := nodes[0].AddTask(task("p3")); err != nil {
+		t.Fatalf("failed to add task to node: %v", err)
+	}
+	calls = 0
+	if predicateNodes, _ := ph.PredicateNodes(task("p4"), nodes, predicate, true); len(predicateNodes) != 2 || calls != 1 {
+		t.Errorf("expected p4 to predicate changed node n0 only, got %d nodes with %d predicates", len(predicateNodes), calls)
+	}
+
+	// The tasks with pod anti-affinity depend on the pods on other nodes.
+	antiAffinity := task("p5")
+	antiAffinity.Pod.Spec.Affinity = &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "a"}},
+			TopologyKey:   "kubernetes.io/hostname",
+		}},
+	}}
+	calls = 0
+	ph.PredicateNodes(antiAffinity, nodes, predicate, true)
+	ph.PredicateNodes(antiAffinity, nodes, predicate, true)
+	// n2 failed by the job before is skipped by the error cache.
+	if calls != 4 {
+		t.Errorf("expected predicates of task with pod anti-affinity not reused, got %d predicates", calls)
+	}
+
+	// Tasks of a different template do not share results.
+	other := api.NewTaskInfo(BuildPod("c1", "q1", "", v1.PodPending, BuildResourceList("2", "1Gi"), "pg2", nil, nil))
+	calls = 0
+	ph.PredicateNodes(other, nodes, predicate, true)
+	if calls != 3 {
+		t.Errorf("expected task of another template to run predicates, got %d predicates", calls)
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "sync/atomic"
+
+// DefaultParallelism is the number of goroutines predicating and scoring nodes in parallel by default,
+// the same as the default parallelism of kube-scheduler.
+const DefaultParallelism = 16
+
+var parallelism int32 = DefaultParallelism
+
+// SetParallelism sets the number of goroutines predicating and scoring nodes in parallel, it falls back to
+// DefaultParallelism if parallelism is not positive.
+func SetParallelism(n int) {
+	if n <= 0 {
+		n = DefaultParallelism
+	}
+	atomic.StoreInt32(&parallelism, int32(n))
+}
+
+// GetParallelism returns the number of goroutines predicating and scoring nodes in parallel.
+func GetParallelism() int {
+	return int(atomic.LoadInt32(&parallelism))
+}
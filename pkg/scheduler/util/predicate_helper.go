@@ -18,6 +18,9 @@ type PredicateHelper interface {
 
 type predicateHelper struct {
 	taskPredicateErrorCache map[string]map[string]error
+	// equivalence keeps the predicate results of the tasks of the same template on nodes, so that the
+	// siblings of a task reuse them on the nodes not changed since.
+	equivalence *equivalenceCache
 }
 
 // PredicateNodes returns the specified number of nodes that fit a task
@@ -43,6 +46,9 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 		nodeErrorCache = map[string]error{}
 	}
 
+	equivalenceHash, equivalent := taskEquivalenceHash(task)
+	equivalent = equivalent && enableErrorCache
+
 	//create a context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -69,8 +75,21 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 			}
 		}
 
-		// TODO (k82cn): Enable eCache for performance improvement.
-		if _, err := fn(task, node); err != nil {
+		var err error
+		var cached *equivalenceResult
+		if equivalent {
+			cached = ph.equivalence.get(equivalenceHash, node)
+		}
+		if cached != nil {
+			err = cached.err
+		} else {
+			generation := node.Generation()
+			_, err = fn(task, node)
+			if equivalent {
+				ph.equivalence.set(equivalenceHash, node.Name, generation, err)
+			}
+		}
+		if err != nil {
 			klog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
 				task.Namespace, task.Name, node.Name, err)
 			errorLock.Lock()
@@ -91,8 +110,7 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 		}
 	}
 
-	//workqueue.ParallelizeUntil(context.TODO(), 16, len(nodes), checkNode)
-	workqueue.ParallelizeUntil(ctx, 16, allNodes, checkNode)
+	workqueue.ParallelizeUntil(ctx, GetParallelism(), allNodes, checkNode)
 
 	//processedNodes := int(numFoundNodes) + len(filteredNodesStatuses) + len(failedPredicateMap)
 	lastProcessedNodeIndex = (lastProcessedNodeIndex + int(processedNodes)) % allNodes
@@ -101,11 +119,18 @@ func (ph *predicateHelper) PredicateNodes(task *api.TaskInfo, nodes []*api.NodeI
 }
 
 func taskGroupID(task *api.TaskInfo) string {
+	if len(task.ResourceShapes) != 0 {
+		// Tasks of the same template fit different nodes in different resource shapes
+		return fmt.Sprintf("%s/%s/%d", task.Job, task.GetTaskSpecKey(), task.ResourceShape)
+	}
 	return fmt.Sprintf("%s/%s", task.Job, task.GetTaskSpecKey())
 }
 
 func NewPredicateHelper() PredicateHelper {
-	return &predicateHelper{taskPredicateErrorCache: map[string]map[string]error{}}
+	return &predicateHelper{
+		taskPredicateErrorCache: map[string]map[string]error{},
+		equivalence:             newEquivalenceCache(),
+	}
 }
 
 type StatusSets []*api.Status
@@ -253,6 +253,36 @@ func BuildPodWithPriority(namespace, name, nodeName string, p v1.PodPhase, req v
 	}
 }
 
+// BuildPodGroup builds a podgroup object of the phase in the queue
+func BuildPodGroup(namespace, name, queue string, minMember int32, phase schedulingv2.PodGroupPhase) *schedulingv2.PodGroup {
+	return &schedulingv2.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: schedulingv2.PodGroupSpec{
+			Queue:     queue,
+			MinMember: minMember,
+		},
+		Status: schedulingv2.PodGroupStatus{
+			Phase: phase,
+		},
+	}
+}
+
+// BuildQueue builds a queue object of the weight, capability is not limited if it is nil
+func BuildQueue(name string, weight int32, capability v1.ResourceList) *schedulingv2.Queue {
+	return &schedulingv2.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: schedulingv2.QueueSpec{
+			Weight:     weight,
+			Capability: capability,
+		},
+	}
+}
+
 // FakeBinder is used as fake binder
 type FakeBinder struct {
 	Binds   map[string]string
@@ -284,7 +314,7 @@ func (fe *FakeEvictor) Evicts() []string {
 }
 
 // Evict is used by fake evictor to evict pods
-func (fe *FakeEvictor) Evict(p *v1.Pod, reason string) error {
+func (fe *FakeEvictor) Evict(p *v1.Pod, reason string, opts api.EvictOptions) error {
 	fe.Lock()
 	defer fe.Unlock()
 
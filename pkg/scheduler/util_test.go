@@ -20,8 +20,12 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	_ "volcano.sh/volcano/pkg/scheduler/actions"
 	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
 func TestLoadSchedulerConf(t *testing.T) {
@@ -44,148 +48,183 @@ tiers:
 		{
 			Plugins: []conf.PluginOption{
 				{
-					Name:                 "priority",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "priority",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 				{
-					Name:                 "gang",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "gang",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 				{
-					Name:                 "conformance",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "conformance",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 			},
 		},
 		{
 			Plugins: []conf.PluginOption{
 				{
-					Name:                 "drf",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "drf",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 				{
-					Name:                 "predicates",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "predicates",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 				{
-					Name:                 "proportion",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "proportion",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 				{
-					Name:                 "nodeorder",
-					EnabledJobOrder:      &trueValue,
-					EnabledJobReady:      &trueValue,
-					EnabledJobPipelined:  &trueValue,
-					EnabledTaskOrder:     &trueValue,
-					EnabledPreemptable:   &trueValue,
-					EnabledReclaimable:   &trueValue,
-					EnabledQueueOrder:    &trueValue,
-					EnabledPredicate:     &trueValue,
-					EnabledBestNode:      &trueValue,
-					EnabledNodeOrder:     &trueValue,
-					EnabledTargetJob:     &trueValue,
-					EnabledReservedNodes: &trueValue,
-					EnabledJobEnqueued:   &trueValue,
-					EnabledVictim:        &trueValue,
-					EnabledJobStarving:   &trueValue,
-					EnabledOverused:      &trueValue,
-					EnabledAllocatable:   &trueValue,
+					Name:                  "nodeorder",
+					EnabledJobOrder:       &trueValue,
+					EnabledJobReady:       &trueValue,
+					EnabledJobPipelined:   &trueValue,
+					EnabledTaskOrder:      &trueValue,
+					EnabledPreemptable:    &trueValue,
+					EnabledReclaimable:    &trueValue,
+					EnabledQueueOrder:     &trueValue,
+					EnabledPredicate:      &trueValue,
+					EnabledBestNode:       &trueValue,
+					EnabledNodeOrder:      &trueValue,
+					EnabledTargetJob:      &trueValue,
+					EnabledReservedNodes:  &trueValue,
+					EnabledJobEnqueued:    &trueValue,
+					EnabledJobEnqueueable: &trueValue,
+					EnabledJobValid:       &trueValue,
+					EnabledClusterOrder:   &trueValue,
+					EnabledPrePredicate:   &trueValue,
+					EnabledVictim:         &trueValue,
+					EnabledVictimVeto:     &trueValue,
+					EnabledJobStarving:    &trueValue,
+					EnabledOverused:       &trueValue,
+					EnabledAllocatable:    &trueValue,
 				},
 			},
 		},
@@ -206,3 +245,227 @@ tiers:
 			expectedConfigurations, configurations)
 	}
 }
+
+func TestUnmarshalSchedulerConfWithUnknownOption(t *testing.T) {
+	configuration := `
+actions: "enqueue, allocate, backfill"
+tiers:
+- plugins:
+  - name: gang
+    enableJobValid: false
+    enablePrePredicate: false
+  - name: proportion
+    enableJobEnqueuable: false
+`
+	if _, _, _, _, err := unmarshalSchedulerConf(configuration); err != nil {
+		t.Errorf("expected unknown option enableJobEnqueuable to be ignored, got %v", err)
+	}
+	unknown, err := unknownPluginOptions(configuration)
+	if err != nil || !reflect.DeepEqual(unknown, []string{"enableJobEnqueuable of plugin proportion"}) {
+		t.Errorf("expected unknown option enableJobEnqueuable of proportion, got %v, %v", unknown, err)
+	}
+
+	configuration = `
+actions: "enqueue, allocate, backfill"
+tiers:
+- plugins:
+  - name: gang
+    enableJobValid: false
+    enablePrePredicate: false
+  - name: proportion
+    enableJobEnqueueable: false
+`
+	var tiers []conf.Tier
+	_, tiers, _, _, err = unmarshalSchedulerConf(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gang, proportion := tiers[0].Plugins[0], tiers[0].Plugins[1]
+	if *gang.EnabledJobValid || *gang.EnabledPrePredicate || !*gang.EnabledPredicate {
+		t.Errorf("expected jobValid and prePredicate of gang disabled, got %v, %v", *gang.EnabledJobValid, *gang.EnabledPrePredicate)
+	}
+	if *proportion.EnabledJobEnqueueable || !*proportion.EnabledJobEnqueued {
+		t.Errorf("expected only jobEnqueueable of proportion disabled, got %v, %v", *proportion.EnabledJobEnqueueable, *proportion.EnabledJobEnqueued)
+	}
+}
+
+func TestUnmarshalSchedulerConfWithLegacyArguments(t *testing.T) {
+	configuration := `
+actions: "allocate"
+tiers:
+- plugins:
+  - name: usage
+    arguments:
+      type: max
+      thresholds:
+        CPUUsageAvg.5m: 80
+  - name: gang
+    arguments:
+      gang.weight: 2
+`
+	deprecated := testutil.ToFloat64(metrics.DeprecatedPluginArguments("usage"))
+	_, tiers, _, _, err := unmarshalSchedulerConf(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.DeprecatedPluginArguments("usage")) - deprecated; got != 1 {
+		t.Errorf("expected 1 deprecated argument of usage counted, got %v", got)
+	}
+	usage, gang := tiers[0].Plugins[0], tiers[0].Plugins[1]
+	expected := map[string]interface{}{
+		"type":             "max",
+		"usage.thresholds": map[interface{}]interface{}{"cpu": map[interface{}]interface{}{"5m": 80}},
+	}
+	if usage.APIVersion != "v2" || !reflect.DeepEqual(usage.Arguments, expected) {
+		t.Errorf("expected arguments of usage converted to v2 %v, got %s %v", expected, usage.APIVersion, usage.Arguments)
+	}
+	if gang.APIVersion != "" || !reflect.DeepEqual(gang.Arguments, map[string]interface{}{"gang.weight": 2}) {
+		t.Errorf("expected arguments of gang not converted, got %s %v", gang.APIVersion, gang.Arguments)
+	}
+
+	configuration = `
+actions: "allocate"
+tiers:
+- plugins:
+  - name: usage
+    apiVersion: v3
+`
+	if _, _, _, _, err := unmarshalSchedulerConf(configuration); err == nil {
+		t.Errorf("expected error for unknown apiVersion v3 of usage")
+	}
+}
+
+func TestUnmarshalSchedulerProfiles(t *testing.T) {
+	configuration := `
+actions: "enqueue, allocate, backfill"
+tiers:
+- plugins:
+  - name: gang
+profiles:
+- name: ci
+  actions: "enqueue, allocate"
+  tiers:
+  - plugins:
+    - name: priority
+    - name: binpack
+  configurations:
+  - name: enqueue
+    arguments:
+      overcommit-factor: 1.5
+`
+	if _, _, _, _, err := unmarshalSchedulerConf(configuration); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	profiles, err := unmarshalSchedulerProfiles(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].name != "ci" {
+		t.Fatalf("expected profile ci, got %v", profiles)
+	}
+	ci := profiles[0]
+	if len(ci.actions) != 2 || ci.actions[0].Name() != "enqueue" || ci.actions[1].Name() != "allocate" {
+		t.Errorf("expected actions enqueue and allocate of profile ci, got %v", ci.actions)
+	}
+	if len(ci.plugins) != 1 || len(ci.plugins[0].Plugins) != 2 || !*ci.plugins[0].Plugins[1].EnabledNodeOrder {
+		t.Errorf("expected plugins of profile ci with default settings, got %v", ci.plugins)
+	}
+	if len(ci.configurations) != 1 || ci.configurations[0].Name != "enqueue" {
+		t.Errorf("expected configurations of profile ci, got %v", ci.configurations)
+	}
+
+	for _, invalid := range []string{`
+profiles:
+- name: ci
+  actions: "allocate"
+- name: ci
+  actions: "allocate"
+`, `
+profiles:
+- name: ci
+  actions: "unknown"
+`, `
+profiles:
+- actions: "allocate"
+`} {
+		if _, err := unmarshalSchedulerProfiles(invalid); err == nil {
+			t.Errorf("expected error for invalid profiles %s", invalid)
+		}
+	}
+
+	unknownOption := `
+actions: "allocate"
+profiles:
+- name: ci
+  actions: "allocate"
+  tiers:
+  - plugins:
+    - name: gang
+      enableJobEnqueuable: false
+`
+	if unknown, err := unknownPluginOptions(unknownOption); err != nil || !reflect.DeepEqual(unknown, []string{"enableJobEnqueuable of plugin gang"}) {
+		t.Errorf("expected unknown option of plugin in profile, got %v, %v", unknown, err)
+	}
+}
+
+func TestUnmarshalEvictionConf(t *testing.T) {
+	eviction, err := unmarshalEvictionConf(`
+actions: "allocate, preempt"
+eviction:
+  mode: eviction
+  gracePeriodSeconds: 30
+  noEvictLabel: volcano.sh/no-evict
+  pipelineTimeout: 5m
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if eviction.Mode != conf.EvictionModeEviction || eviction.GracePeriodSeconds == nil ||
+		*eviction.GracePeriodSeconds != 30 || eviction.NoEvictLabel != "volcano.sh/no-evict" || eviction.PipelineTimeout != "5m" {
+		t.Errorf("unexpected eviction configuration %+v", eviction)
+	}
+
+	eviction, err = unmarshalEvictionConf(`actions: "allocate, preempt"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if eviction.Mode != conf.EvictionModeDelete || eviction.GracePeriodSeconds != nil {
+		t.Errorf("expected victims deleted by default, got %+v", eviction)
+	}
+
+	for _, invalid := range []string{`
+eviction:
+  mode: drain
+`, `
+eviction:
+  gracePeriodSeconds: -1
+`, `
+eviction:
+  pipelineTimeout: 5
+`, `
+eviction:
+  pipelineTimeout: -5m
+`} {
+		if _, err := unmarshalEvictionConf(invalid); err == nil {
+			t.Errorf("expected error for invalid eviction configuration %s", invalid)
+		}
+	}
+}
+
+func TestUnmarshalParallelism(t *testing.T) {
+	for confStr, expected := range map[string]int{
+		`actions: "allocate"`: util.DefaultParallelism,
+		`parallelism: 32`:     32,
+	} {
+		parallelism, err := unmarshalParallelism(confStr)
+		if err != nil {
+			t.Fatalf("unexpected error of %s: %v", confStr, err)
+		}
+		if parallelism != expected {
+			t.Errorf("expected parallelism %d of %s, got %d", expected, confStr, parallelism)
+		}
+	}
+	if _, err := unmarshalParallelism(`parallelism: -1`); err == nil {
+		t.Errorf("expected error for negative parallelism")
+	}
+}
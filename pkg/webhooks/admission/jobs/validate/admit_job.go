@@ -40,6 +40,7 @@ import (
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/plugins"
 	controllerMpi "volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -205,6 +206,10 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 		msg += " job 'minAvailable' should not be greater than total replicas in tasks;"
 	}
 
+	if err := validateMinSuccess(job); err != nil {
+		msg += fmt.Sprintf(" %v;", err)
+	}
+
 	if err := validatePolicies(job.Spec.Policies, field.NewPath("spec.policies")); err != nil {
 		msg = msg + err.Error() + fmt.Sprintf(" valid events are %v, valid actions are %v;",
 			getValidEvents(), getValidActions())
@@ -223,6 +228,10 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 		msg += err.Error()
 	}
 
+	if _, err := schedulingapi.ParseNodeFeatures(job.Annotations); err != nil {
+		msg += fmt.Sprintf(" %v;", err)
+	}
+
 	queue, err := config.VolcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), job.Spec.Queue, metav1.GetOptions{})
 	if err != nil {
 		msg += fmt.Sprintf(" unable to find job queue: %v;", err)
@@ -269,6 +278,9 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	if new.Spec.MinAvailable < 0 {
 		return fmt.Errorf("job 'minAvailable' must be >= 0")
 	}
+	if err := validateMinSuccess(new); err != nil {
+		return err
+	}
 
 	if len(old.Spec.Tasks) != len(new.Spec.Tasks) {
 		return fmt.Errorf("job updates may not add or remove tasks")
@@ -300,6 +312,38 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	return nil
 }
 
+// validateMinSuccess checks that `minSuccess` pods of job may succeed, i.e. it is not greater than
+// the replicas of tasks, or their completions if defined, and the policy of other pods is valid.
+func validateMinSuccess(job *v1alpha1.Job) error {
+	if job.Spec.MinSuccess == nil {
+		return nil
+	}
+	if *job.Spec.MinSuccess < 1 {
+		return fmt.Errorf("job 'minSuccess' must be >= 1")
+	}
+
+	var total int32
+	completions := jobhelpers.GetTaskCompletions(job)
+	for _, task := range job.Spec.Tasks {
+		if count, found := completions[task.Name]; found {
+			total += count
+		} else {
+			total += task.Replicas
+		}
+	}
+	if *job.Spec.MinSuccess > total {
+		return fmt.Errorf("job 'minSuccess' must not be greater than total replicas or completions of tasks")
+	}
+
+	switch policy := jobhelpers.GetMinSuccessPolicy(job); policy {
+	case jobhelpers.MinSuccessPolicyKill, jobhelpers.MinSuccessPolicyWait:
+	default:
+		return fmt.Errorf("invalid %s %q, valid policies are %s and %s", jobhelpers.MinSuccessPolicyKey, policy,
+			jobhelpers.MinSuccessPolicyKill, jobhelpers.MinSuccessPolicyWait)
+	}
+	return nil
+}
+
 func validateTaskTemplate(task v1alpha1.TaskSpec, job *v1alpha1.Job, index int) string {
 	var v1PodTemplate v1.PodTemplate
 	v1PodTemplate.Template = *task.Template.DeepCopy()
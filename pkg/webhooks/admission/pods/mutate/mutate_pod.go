@@ -17,14 +17,18 @@ limitations under the License.
 package mutate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
+	vcv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
@@ -101,12 +105,16 @@ func Pods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 
 // createPatch patch pod
 func createPatch(pod *v1.Pod) ([]byte, error) {
+	patch := patchPriorityClass(pod)
+
 	if config.ConfigData == nil {
 		klog.V(5).Infof("admission configuration is empty.")
-		return nil, nil
+		if len(patch) == 0 {
+			return nil, nil
+		}
+		return json.Marshal(patch)
 	}
 
-	var patch []patchOperation
 	config.ConfigData.Lock()
 	defer config.ConfigData.Unlock()
 
@@ -204,3 +212,52 @@ func patchSchedulerName(resGroupConfig wkconfig.ResGroupConfig) *patchOperation
 
 	return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: resGroupConfig.SchedulerName}
 }
+
+// getPodGroup gets the podgroup from the informer cache. As the podgroup is created just before its pods, e.g. by the
+// job controller, it may not be in the cache yet, then it is got from the apiserver.
+func getPodGroup(namespace, name string) (*vcv1beta1.PodGroup, error) {
+	pg, err := config.PodGroupLister.PodGroups(namespace).Get(name)
+	if err == nil || !apierrors.IsNotFound(err) || config.VolcanoClient == nil {
+		return pg, err
+	}
+	return config.VolcanoClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// patchPriorityClass patch priority class of PodGroup to pod without priority class, so kubelet eviction
+// and other schedulers see the same priority as Volcano uses to order the PodGroup.
+func patchPriorityClass(pod *v1.Pod) []patchOperation {
+	if len(pod.Spec.PriorityClassName) != 0 || config.PodGroupLister == nil || config.PriorityClassLister == nil {
+		return nil
+	}
+
+	pgName := pod.Annotations[vcv1beta1.KubeGroupNameAnnotationKey]
+	if len(pgName) == 0 {
+		return nil
+	}
+
+	pg, err := getPodGroup(pod.Namespace, pgName)
+	if err != nil {
+		klog.V(4).Infof("Failed to get PodGroup <%s/%s> of pod %s: %v", pod.Namespace, pgName, pod.Name, err)
+		return nil
+	}
+	if len(pg.Spec.PriorityClassName) == 0 {
+		return nil
+	}
+
+	pc, err := config.PriorityClassLister.Get(pg.Spec.PriorityClassName)
+	if err != nil {
+		klog.Warningf("Failed to get PriorityClass %s of PodGroup <%s/%s>: %v",
+			pg.Spec.PriorityClassName, pod.Namespace, pgName, err)
+		return nil
+	}
+
+	// The priority admission plugin has resolved the priority before webhooks, so resolve it here again.
+	patch := []patchOperation{
+		{Op: "add", Path: "/spec/priorityClassName", Value: pc.Name},
+		{Op: "add", Path: "/spec/priority", Value: pc.Value},
+	}
+	if pc.PreemptionPolicy != nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/preemptionPolicy", Value: *pc.PreemptionPolicy})
+	}
+	return patch
+}
@@ -22,8 +22,14 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schedulinglisters "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/client-go/tools/cache"
 
+	vcv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	vcschedulinglisters "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
 	webconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
 
@@ -216,3 +222,100 @@ func TestMutatePods(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchPriorityClass(t *testing.T) {
+	never := v1.PreemptNever
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&schedulingv1.PriorityClass{
+		ObjectMeta:       metav1.ObjectMeta{Name: "high"},
+		Value:            1000,
+		PreemptionPolicy: &never,
+	})
+	config.PriorityClassLister = schedulinglisters.NewPriorityClassLister(indexer)
+	pgIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pgIndexer.Add(&vcv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pg-high"},
+		Spec:       vcv1beta1.PodGroupSpec{PriorityClassName: "high"},
+	})
+	config.PodGroupLister = vcschedulinglisters.NewPodGroupLister(pgIndexer)
+	// The podgroup created just before its pods is not in the informer cache yet.
+	vcClient := vcclient.NewSimpleClientset(
+		&vcv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pg-created"},
+			Spec:       vcv1beta1.PodGroupSpec{PriorityClassName: "high"},
+		},
+		&vcv1beta1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pg-default"},
+		},
+	)
+	config.VolcanoClient = vcClient
+	defer func() {
+		config.PriorityClassLister = nil
+		config.PodGroupLister = nil
+		config.VolcanoClient = nil
+	}()
+
+	newPod := func(pgName, priorityClassName string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"},
+			Spec:       v1.PodSpec{PriorityClassName: priorityClassName},
+		}
+		if len(pgName) != 0 {
+			pod.Annotations = map[string]string{vcv1beta1.KubeGroupNameAnnotationKey: pgName}
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		Name   string
+		Pod    *v1.Pod
+		expect []patchOperation
+	}{
+		{
+			Name: "inherit priority class of podgroup",
+			Pod:  newPod("pg-high", ""),
+			expect: []patchOperation{
+				{Op: "add", Path: "/spec/priorityClassName", Value: "high"},
+				{Op: "add", Path: "/spec/priority", Value: int32(1000)},
+				{Op: "add", Path: "/spec/preemptionPolicy", Value: never},
+			},
+		},
+		{
+			Name: "inherit priority class of podgroup not in cache",
+			Pod:  newPod("pg-created", ""),
+			expect: []patchOperation{
+				{Op: "add", Path: "/spec/priorityClassName", Value: "high"},
+				{Op: "add", Path: "/spec/priority", Value: int32(1000)},
+				{Op: "add", Path: "/spec/preemptionPolicy", Value: never},
+			},
+		},
+		{
+			Name: "keep priority class of pod",
+			Pod:  newPod("pg-high", "low"),
+		},
+		{
+			Name: "podgroup without priority class",
+			Pod:  newPod("pg-default", ""),
+		},
+		{
+			Name: "podgroup not found",
+			Pod:  newPod("pg-missing", ""),
+		},
+		{
+			Name: "pod without podgroup",
+			Pod:  newPod("", ""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if patch := patchPriorityClass(testCase.Pod); !reflect.DeepEqual(patch, testCase.expect) {
+				t.Errorf("expect %v, got %v", testCase.expect, patch)
+			}
+		})
+	}
+	// Only the podgroups not in the informer cache are got from the apiserver.
+	if actions := vcClient.Actions(); len(actions) != 3 {
+		t.Errorf("expect 3 podgroups got from the apiserver, got %v", actions)
+	}
+}
@@ -63,6 +63,14 @@ var service = &router.AdmissionService{
 
 var config = &router.AdmissionServiceConfig{}
 
+const (
+	// parentQueueAnnotation is the key of annotation on queue with the name of its parent queue, it is the same
+	// as the one read by the scheduler.
+	parentQueueAnnotation = "scheduling.volcano.sh/parent-queue"
+	// maxQueueDepth is the max number of ancestors of a queue.
+	maxQueueDepth = 10
+)
+
 // AdmitQueues is to admit queues and return response.
 func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	klog.V(3).Infof("Admitting %s queue %s.", ar.Request.Operation, ar.Request.Name)
@@ -101,6 +109,7 @@ func validateQueue(queue *schedulingv1beta1.Queue) error {
 	errs = append(errs, validateStateOfQueue(queue.Status.State, resourcePath.Child("spec").Child("state"))...)
 	errs = append(errs, validateWeightOfQueue(queue.Spec.Weight, resourcePath.Child("spec").Child("weight"))...)
 	errs = append(errs, validateHierarchicalAttributes(queue, resourcePath.Child("metadata").Child("annotations"))...)
+	errs = append(errs, validateParentQueue(queue, resourcePath.Child("metadata").Child("annotations"))...)
 
 	if len(errs) > 0 {
 		return errs.ToAggregate()
@@ -164,6 +173,36 @@ func validateHierarchicalAttributes(queue *schedulingv1beta1.Queue, fldPath *fie
 	return errs
 }
 
+// validateParentQueue checks the parent queue exists and is not the queue itself or one of its descendants, and
+// that the queue has at most maxQueueDepth ancestors.
+func validateParentQueue(queue *schedulingv1beta1.Queue, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	parent := queue.Annotations[parentQueueAnnotation]
+	visited := map[string]bool{}
+	for ancestor, depth := parent, 1; ancestor != ""; depth++ {
+		if ancestor == queue.Name {
+			return append(errs, field.Invalid(fldPath, parent,
+				fmt.Sprintf("%s must not be queue %s or its descendant", parentQueueAnnotation, queue.Name)))
+		}
+		if visited[ancestor] {
+			return append(errs, field.Invalid(fldPath, parent,
+				fmt.Sprintf("ancestors of queue %s loop at queue %s", queue.Name, ancestor)))
+		}
+		if depth > maxQueueDepth {
+			return append(errs, field.Invalid(fldPath, parent,
+				fmt.Sprintf("queue %s must have at most %d ancestors", queue.Name, maxQueueDepth)))
+		}
+		visited[ancestor] = true
+		ancestorQueue, err := config.VolcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), ancestor, metav1.GetOptions{})
+		if err != nil {
+			return append(errs, field.Invalid(fldPath, parent,
+				fmt.Sprintf("checking %s, get queue %s failed: %v", parentQueueAnnotation, ancestor, err)))
+		}
+		ancestor = ancestorQueue.Annotations[parentQueueAnnotation]
+	}
+	return errs
+}
+
 func validateStateOfQueue(value schedulingv1beta1.QueueState, fldPath *field.Path) field.ErrorList {
 	errs := field.ErrorList{}
 
@@ -203,5 +242,17 @@ func validateQueueDeleting(queue string) error {
 		return err
 	}
 
+	// The children are found by annotation, so all the queues are listed; resource version 0 serves them from
+	// the cache of the API server instead of etcd.
+	queueList, err := config.VolcanoClient.SchedulingV1beta1().Queues().List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	for _, child := range queueList.Items {
+		if child.Annotations[parentQueueAnnotation] == queue {
+			return fmt.Errorf("queue `%s` can not be deleted, it is the parent of queue `%s`", queue, child.Name)
+		}
+	}
+
 	return nil
 }
@@ -911,3 +911,52 @@ func TestAdmitQueues(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateParentQueue(t *testing.T) {
+	buildQueue := func(name, parent string) *schedulingv1beta1.Queue {
+		queue := &schedulingv1beta1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       schedulingv1beta1.QueueSpec{Weight: 1},
+		}
+		if parent != "" {
+			queue.Annotations = map[string]string{parentQueueAnnotation: parent}
+		}
+		return queue
+	}
+	queues := []runtime.Object{buildQueue("a", ""), buildQueue("a1", "a"), buildQueue("a11", "a1"),
+		// c1 and c2 loop, they are created before the webhook is.
+		buildQueue("c1", "c2"), buildQueue("c2", "c1"), buildQueue("d0", "")}
+	for i := 0; i < maxQueueDepth; i++ {
+		queues = append(queues, buildQueue(fmt.Sprintf("d%d", i+1), fmt.Sprintf("d%d", i)))
+	}
+	config.VolcanoClient = fakeclient.NewSimpleClientset(queues...)
+
+	tests := []struct {
+		name  string
+		queue *schedulingv1beta1.Queue
+		valid bool
+	}{
+		{name: "queue without parent", queue: buildQueue("b", ""), valid: true},
+		{name: "queue with existing parent", queue: buildQueue("a2", "a"), valid: true},
+		{name: "queue with missing parent", queue: buildQueue("b1", "b"), valid: false},
+		{name: "queue with itself as parent", queue: buildQueue("b", "b"), valid: false},
+		{name: "queue with descendant as parent", queue: buildQueue("a", "a11"), valid: false},
+		{name: "queue with looping ancestors", queue: buildQueue("b", "c1"), valid: false},
+		{name: "queue at max depth", queue: buildQueue("e", fmt.Sprintf("d%d", maxQueueDepth-1)), valid: true},
+		{name: "queue too deep", queue: buildQueue("e", fmt.Sprintf("d%d", maxQueueDepth)), valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateQueue(test.queue); (err == nil) != test.valid {
+				t.Errorf("expected valid %v, got error %v", test.valid, err)
+			}
+		})
+	}
+
+	if err := validateQueueDeleting("a1"); err == nil {
+		t.Errorf("expected parent queue a1 not deleted")
+	}
+	if err := validateQueueDeleting("a11"); err != nil {
+		t.Errorf("expected queue a11 without children deleted, got %v", err)
+	}
+}
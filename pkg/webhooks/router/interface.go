@@ -20,9 +20,11 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/client-go/kubernetes"
+	schedulinglisters "k8s.io/client-go/listers/scheduling/v1"
 	"k8s.io/client-go/tools/record"
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
+	vcschedulinglisters "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/webhooks/config"
 )
 
@@ -35,6 +37,10 @@ type AdmissionServiceConfig struct {
 	VolcanoClient  versioned.Interface
 	Recorder       record.EventRecorder
 	ConfigData     *config.AdmissionConfiguration
+	// PriorityClassLister lists the priority classes from the informer cache of the webhook manager.
+	PriorityClassLister schedulinglisters.PriorityClassLister
+	// PodGroupLister lists the podgroups from the informer cache of the webhook manager.
+	PodGroupLister vcschedulinglisters.PodGroupLister
 }
 
 type AdmissionService struct {